@@ -48,6 +48,7 @@ func Serve() {
 	http.HandleFunc("/liveness", livenessHandler)
 	http.HandleFunc("/readiness", readinessHandler)
 	http.HandleFunc("/status", statusHandler)
+	http.HandleFunc("/metrics", metricsHandler)
 	err := server.ListenAndServe()
 	if err != nil {
 		lp.LogApiEvent(fmt.Sprintf("unable to serve api on port %d", port), log.ErrorLevel)
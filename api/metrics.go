@@ -0,0 +1,280 @@
+package api
+
+import (
+	"fmt"
+	"hazeltest/client"
+	"hazeltest/status"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type (
+	metricSample struct {
+		name   string
+		labels []metricLabel
+		value  float64
+	}
+	metricLabel struct {
+		name, value string
+	}
+	metricFamily struct {
+		metricType string
+		samples    []metricSample
+	}
+	metricFamilies map[string]*metricFamily
+)
+
+const (
+	metricNamePrefix              = "hazeltest_"
+	metricTypeGauge               = "gauge"
+	metricTypeSummary             = "summary"
+	metricNameCleanedItems        = metricNamePrefix + "cleaned_items"
+	labelActorGroup               = "actor_group"
+	labelActor                    = "actor"
+	labelClientID                 = "client_id"
+	labelName                     = "name"
+	labelDataStructure            = "data_structure"
+	labelQuantile                 = "quantile"
+	contentTypeTextExposition     = "text/plain; version=0.0.4; charset=utf-8"
+	histogramMetricNameUnitSuffix = "_microseconds"
+)
+
+var (
+	// Only status keys looking like the camel-cased keys the actors use for their counters can be
+	// translated into metric names -- keys of any other form (such as the names of data structures
+	// added by cleaners) are either handled explicitly or skipped.
+	statusKeyPattern  = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+func metricsHandler(w http.ResponseWriter, req *http.Request) {
+
+	switch req.Method {
+	case methodGet:
+		families := assembleMetricFamilies(assembleActorStatus(), client.ID().String())
+		w.Header().Set("Content-Type", contentTypeTextExposition)
+		_ = families.write(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+
+}
+
+func assembleMetricFamilies(actorStatus map[ActorGroup]map[string]any, clientID string) metricFamilies {
+
+	families := make(metricFamilies)
+
+	for actorGroup, actors := range actorStatus {
+		for actor, s := range actors {
+			actorStatusMap, ok := s.(map[string]any)
+			if !ok {
+				continue
+			}
+			baseLabels := []metricLabel{
+				{labelActorGroup, string(actorGroup)},
+				{labelActor, actor},
+				{labelClientID, clientID},
+			}
+			for k, v := range actorStatusMap {
+				if actorGroup == StateCleaners {
+					// State cleaners report the number of cleaned items keyed by the name of the cleaned data structure
+					if n, ok := numericValue(v); ok && !isBool(v) {
+						families.add(metricNameCleanedItems, metricTypeGauge, withLabels(baseLabels, metricLabel{labelDataStructure, k}), n)
+						continue
+					}
+				}
+				if !statusKeyPattern.MatchString(k) {
+					continue
+				}
+				families.addStatusValue(metricNamePrefix+toSnakeCase(k), v, baseLabels)
+			}
+		}
+	}
+
+	return families
+
+}
+
+func (f metricFamilies) addStatusValue(name string, v any, labels []metricLabel) {
+
+	switch value := v.(type) {
+	case status.HistogramSnapshot:
+		f.addHistogramSnapshot(name+histogramMetricNameUnitSuffix, value, labels)
+	case map[string]status.HistogramSnapshot:
+		for k, s := range value {
+			f.addHistogramSnapshot(name+histogramMetricNameUnitSuffix, s, withLabels(labels, metricLabel{labelName, k}))
+		}
+	case map[string]any:
+		for k, nested := range value {
+			if !statusKeyPattern.MatchString(k) {
+				continue
+			}
+			if n, ok := numericValue(nested); ok {
+				f.add(name+"_"+toSnakeCase(k), metricTypeGauge, labels, n)
+			}
+		}
+	default:
+		if n, ok := numericValue(v); ok {
+			f.add(name, metricTypeGauge, labels, n)
+		}
+	}
+
+}
+
+func (f metricFamilies) addHistogramSnapshot(name string, s status.HistogramSnapshot, labels []metricLabel) {
+
+	quantiles := []struct {
+		q string
+		v uint64
+	}{
+		{"0.5", s.P50Micros},
+		{"0.9", s.P90Micros},
+		{"0.99", s.P99Micros},
+		{"0.999", s.P999Micros},
+	}
+
+	family := f.get(name, metricTypeSummary)
+	for _, q := range quantiles {
+		family.samples = append(family.samples, metricSample{name, withLabels(labels, metricLabel{labelQuantile, q.q}), float64(q.v)})
+	}
+	family.samples = append(family.samples,
+		metricSample{name + "_sum", labels, float64(s.SumMicros)},
+		metricSample{name + "_count", labels, float64(s.Count)},
+	)
+
+}
+
+func (f metricFamilies) add(name, metricType string, labels []metricLabel, value float64) {
+
+	family := f.get(name, metricType)
+	family.samples = append(family.samples, metricSample{name, labels, value})
+
+}
+
+func (f metricFamilies) get(name, metricType string) *metricFamily {
+
+	family, ok := f[name]
+	if !ok {
+		family = &metricFamily{metricType: metricType}
+		f[name] = family
+	}
+
+	return family
+
+}
+
+func (f metricFamilies) write(w io.Writer) error {
+
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		family := f[name]
+		if _, err := fmt.Fprintf(w, "# TYPE %s %s\n", name, family.metricType); err != nil {
+			return err
+		}
+		lines := make([]string, 0, len(family.samples))
+		for _, s := range family.samples {
+			lines = append(lines, s.format())
+		}
+		sort.Strings(lines)
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+
+}
+
+func (s metricSample) format() string {
+
+	labels := make([]string, 0, len(s.labels))
+	for _, l := range s.labels {
+		labels = append(labels, fmt.Sprintf(`%s="%s"`, l.name, labelValueEscaper.Replace(l.value)))
+	}
+
+	return fmt.Sprintf("%s{%s} %s", s.name, strings.Join(labels, ","), strconv.FormatFloat(s.value, 'g', -1, 64))
+
+}
+
+func withLabels(labels []metricLabel, additional ...metricLabel) []metricLabel {
+
+	result := make([]metricLabel, 0, len(labels)+len(additional))
+	result = append(result, labels...)
+	return append(result, additional...)
+
+}
+
+func numericValue(v any) (float64, bool) {
+
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case bool:
+		if n {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+
+}
+
+func isBool(v any) bool {
+
+	_, ok := v.(bool)
+	return ok
+
+}
+
+func toSnakeCase(s string) string {
+
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+
+}
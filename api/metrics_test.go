@@ -0,0 +1,370 @@
+package api
+
+import (
+	"fmt"
+	"hazeltest/status"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsHandler(t *testing.T) {
+
+	t.Log("given a metrics handler to serve the application's metrics endpoint")
+	{
+		t.Log("\twhen http method other than get is passed")
+		{
+			recorder := httptest.NewRecorder()
+
+			metricsHandler(recorder, httptest.NewRequest(http.MethodPost, "localhost:8080/metrics", nil))
+			response := recorder.Result()
+			defer func(Body io.ReadCloser) {
+				_ = Body.Close()
+			}(response.Body)
+
+			expectedStatusCode := http.StatusMethodNotAllowed
+			msg := fmt.Sprintf("\t\tmetrics handler must return http status %d", expectedStatusCode)
+			if response.StatusCode == expectedStatusCode {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, response.StatusCode)
+			}
+		}
+
+		t.Log("\twhen map runner and chaos monkey have registered")
+		{
+			tracker = newStatefulActorTracker()
+
+			RegisterStatefulActor(MapRunners, sourceMapPokedexRunner, func() map[string]any {
+				return testStatusMapPokedexTestLoop
+			})
+			RegisterStatefulActor(ChaosMonkeys, sourceChaosMonkeyMemberKiller, func() map[string]any {
+				return testStatusMemberKillerMonkey
+			})
+
+			recorder := httptest.NewRecorder()
+
+			metricsHandler(recorder, httptest.NewRequest(http.MethodGet, "localhost:8080/metrics", nil))
+			response := recorder.Result()
+			defer func(Body io.ReadCloser) {
+				_ = Body.Close()
+			}(response.Body)
+
+			expectedStatusCode := http.StatusOK
+			msg := fmt.Sprintf("\t\tmetrics handler must return http status %d", expectedStatusCode)
+			if response.StatusCode == expectedStatusCode {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, response.StatusCode)
+			}
+
+			msg = "\t\tcontent type must be prometheus text exposition format"
+			if response.Header.Get("Content-Type") == contentTypeTextExposition {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, response.Header.Get("Content-Type"))
+			}
+
+			data, err := tryResponseRead(response.Body)
+			msg = "\t\tresponse must be readable"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tresponse must contain metrics for all numeric status values of all registered actors"
+			body := string(data)
+			for _, v := range []string{
+				fmt.Sprintf(`hazeltest_num_maps{actor_group="mapRunners",actor="%s",`, sourceMapPokedexRunner),
+				fmt.Sprintf(`hazeltest_runner_finished{actor_group="mapRunners",actor="%s",`, sourceMapPokedexRunner),
+				fmt.Sprintf(`hazeltest_num_members_killed{actor_group="chaosMonkeys",actor="%s",`, sourceChaosMonkeyMemberKiller),
+			} {
+				if strings.Contains(body, v) {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+		}
+	}
+
+}
+
+func TestAssembleMetricFamilies(t *testing.T) {
+
+	t.Log("given a function to assemble metric families from the status of all registered actors")
+	{
+		clientID := "awesome-client"
+
+		t.Log("\twhen status contains numeric and boolean values")
+		{
+			actorStatus := map[ActorGroup]map[string]any{
+				QueueRunners: {
+					"tweetRunner": map[string]any{
+						"numFailedPuts": 3,
+						"numNilPolls":   uint64(42),
+						"finished":      true,
+						"currentState":  "testLoopStart",
+					},
+				},
+			}
+
+			families := assembleMetricFamilies(actorStatus, clientID)
+
+			msg := "\t\tnumeric and boolean values must be translated into gauges with snake-case names"
+			expected := map[string]float64{
+				"hazeltest_num_failed_puts": 3,
+				"hazeltest_num_nil_polls":   42,
+				"hazeltest_finished":        1,
+			}
+			for name, value := range expected {
+				f, ok := families[name]
+				if ok && f.metricType == metricTypeGauge && len(f.samples) == 1 && f.samples[0].value == value {
+					t.Log(msg, checkMark, name)
+				} else {
+					t.Fatal(msg, ballotX, name)
+				}
+			}
+
+			msg = "\t\tstring values must be skipped"
+			if len(families) == len(expected) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, len(families))
+			}
+
+			msg = "\t\tsamples must carry labels for actor group, actor, and client id"
+			expectedLabels := []metricLabel{
+				{labelActorGroup, string(QueueRunners)},
+				{labelActor, "tweetRunner"},
+				{labelClientID, clientID},
+			}
+			if labelsEqual(expectedLabels, families["hazeltest_num_failed_puts"].samples[0].labels) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, families["hazeltest_num_failed_puts"].samples[0].labels)
+			}
+		}
+
+		t.Log("\twhen status contains nested map of operation values")
+		{
+			actorStatus := map[ActorGroup]map[string]any{
+				QueueRunners: {
+					"tweetRunner": map[string]any{
+						"put": map[string]any{
+							"enabled":   true,
+							"numRuns":   uint32(500),
+							"batchSize": 10,
+						},
+					},
+				},
+			}
+
+			families := assembleMetricFamilies(actorStatus, clientID)
+
+			msg := "\t\tnested values must be translated into gauges prefixed with name of enclosing key"
+			for _, name := range []string{"hazeltest_put_enabled", "hazeltest_put_num_runs", "hazeltest_put_batch_size"} {
+				if _, ok := families[name]; ok {
+					t.Log(msg, checkMark, name)
+				} else {
+					t.Fatal(msg, ballotX, name)
+				}
+			}
+		}
+
+		t.Log("\twhen status contains histogram snapshots")
+		{
+			actorStatus := map[ActorGroup]map[string]any{
+				MapRunners: {
+					sourceMapLoadRunner: map[string]any{
+						"latencies": map[string]status.HistogramSnapshot{
+							"set": {Count: 10, SumMicros: 1000, P50Micros: 100, P90Micros: 110, P99Micros: 120, P999Micros: 130},
+							"get": {Count: 5, SumMicros: 250, P50Micros: 50, P90Micros: 55, P99Micros: 60, P999Micros: 65},
+						},
+					},
+				},
+			}
+
+			families := assembleMetricFamilies(actorStatus, clientID)
+
+			msg := "\t\tsnapshots must be translated into single summary"
+			f, ok := families["hazeltest_latencies_microseconds"]
+			if ok && len(families) == 1 && f.metricType == metricTypeSummary {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, families)
+			}
+
+			msg = "\t\tsummary must contain four quantiles plus sum and count for each snapshot"
+			if len(f.samples) == 2*6 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, len(f.samples))
+			}
+
+			msg = "\t\tsum and count must carry values of snapshot"
+			found := 0
+			for _, s := range f.samples {
+				if s.labels[len(s.labels)-1] != (metricLabel{labelName, "set"}) {
+					continue
+				}
+				if (s.name == "hazeltest_latencies_microseconds_sum" && s.value == 1000) ||
+					(s.name == "hazeltest_latencies_microseconds_count" && s.value == 10) {
+					found++
+				}
+			}
+			if found == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, found)
+			}
+		}
+
+		t.Log("\twhen state cleaner status contains cleaned data structures")
+		{
+			actorStatus := map[ActorGroup]map[string]any{
+				StateCleaners: {
+					"mapCleaner": map[string]any{
+						"ht_load-0": 150,
+						"ht_load-1": 0,
+						"finished":  false,
+					},
+				},
+			}
+
+			families := assembleMetricFamilies(actorStatus, clientID)
+
+			msg := "\t\tcleaned items must be reported in single gauge labelled with data structure name"
+			f, ok := families[metricNameCleanedItems]
+			if ok && len(f.samples) == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, families)
+			}
+
+			for _, s := range f.samples {
+				l := s.labels[len(s.labels)-1]
+				if l.name == labelDataStructure && (l.value == "ht_load-0" && s.value == 150 || l.value == "ht_load-1" && s.value == 0) {
+					t.Log(msg, checkMark, l.value)
+				} else {
+					t.Fatal(msg, ballotX, s)
+				}
+			}
+
+			msg = "\t\tboolean values must still be translated into regular gauges"
+			if _, ok := families["hazeltest_finished"]; ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen map runner status contains keys not resembling status keys")
+		{
+			actorStatus := map[ActorGroup]map[string]any{
+				MapRunners: {
+					sourceMapLoadRunner: map[string]any{
+						"ht_load-0": 150,
+					},
+				},
+			}
+
+			families := assembleMetricFamilies(actorStatus, clientID)
+
+			msg := "\t\tthose keys must be skipped"
+			if len(families) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, families)
+			}
+		}
+	}
+
+}
+
+func TestMetricFamiliesWrite(t *testing.T) {
+
+	t.Log("given metric families to be written in text exposition format")
+	{
+		t.Log("\twhen families contain gauges and summaries")
+		{
+			families := make(metricFamilies)
+			labels := []metricLabel{{labelActor, "some\"actor"}}
+			families.add("hazeltest_b", metricTypeGauge, labels, 2)
+			families.add("hazeltest_a", metricTypeGauge, labels, 1.5)
+			families.addHistogramSnapshot("hazeltest_c_microseconds", status.HistogramSnapshot{Count: 1, SumMicros: 7, P50Micros: 7, P90Micros: 7, P99Micros: 7, P999Micros: 7}, labels)
+
+			var b strings.Builder
+			err := families.write(&b)
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\toutput must contain families sorted by name, each preceded by type line, with label values escaped"
+			expected := `# TYPE hazeltest_a gauge
+hazeltest_a{actor="some\"actor"} 1.5
+# TYPE hazeltest_b gauge
+hazeltest_b{actor="some\"actor"} 2
+# TYPE hazeltest_c_microseconds summary
+hazeltest_c_microseconds_count{actor="some\"actor"} 1
+hazeltest_c_microseconds_sum{actor="some\"actor"} 7
+hazeltest_c_microseconds{actor="some\"actor",quantile="0.5"} 7
+hazeltest_c_microseconds{actor="some\"actor",quantile="0.9"} 7
+hazeltest_c_microseconds{actor="some\"actor",quantile="0.99"} 7
+hazeltest_c_microseconds{actor="some\"actor",quantile="0.999"} 7
+`
+			if b.String() == expected {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, b.String())
+			}
+		}
+	}
+
+}
+
+func TestToSnakeCase(t *testing.T) {
+
+	t.Log("given a function to convert camel-case status keys into snake case")
+	{
+		t.Log("\twhen camel-case keys are converted")
+		{
+			msg := "\t\tresult must be snake-case equivalent"
+			for k, v := range map[string]string{
+				"finished":           "finished",
+				"numFailedInserts":   "num_failed_inserts",
+				"numQueueFullEvents": "num_queue_full_events",
+			} {
+				if actual := toSnakeCase(k); actual == v {
+					t.Log(msg, checkMark, k)
+				} else {
+					t.Fatal(msg, ballotX, actual)
+				}
+			}
+		}
+	}
+
+}
+
+func labelsEqual(expected, actual []metricLabel) bool {
+
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	for i := range expected {
+		if expected[i] != actual[i] {
+			return false
+		}
+	}
+
+	return true
+
+}
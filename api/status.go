@@ -15,14 +15,17 @@ type (
 )
 
 const (
-	MapRunners    ActorGroup = "mapRunners"
-	QueueRunners  ActorGroup = "queueRunners"
-	ChaosMonkeys  ActorGroup = "chaosMonkeys"
-	StateCleaners ActorGroup = "stateCleaners"
+	MapRunners        ActorGroup = "mapRunners"
+	QueueRunners      ActorGroup = "queueRunners"
+	TopicRunners      ActorGroup = "topicRunners"
+	CollectionRunners ActorGroup = "collectionRunners"
+	CounterRunners    ActorGroup = "counterRunners"
+	ChaosMonkeys      ActorGroup = "chaosMonkeys"
+	StateCleaners     ActorGroup = "stateCleaners"
 )
 
 var (
-	availableActorGroups = []ActorGroup{MapRunners, QueueRunners, ChaosMonkeys, StateCleaners}
+	availableActorGroups = []ActorGroup{MapRunners, QueueRunners, TopicRunners, CollectionRunners, CounterRunners, ChaosMonkeys, StateCleaners}
 	tracker              = newStatefulActorTracker()
 )
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"io/ioutil"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -38,6 +39,19 @@ type (
 	k8sPodDeleter interface {
 		delete(cs *kubernetes.Clientset, ctx context.Context, namespace, name string, deleteOptions metav1.DeleteOptions) error
 	}
+	k8sStatefulSetLister interface {
+		list(cs *kubernetes.Clientset, ctx context.Context, namespace string, listOptions metav1.ListOptions) (*appsv1.StatefulSetList, error)
+	}
+	k8sStatefulSetScaler interface {
+		scale(cs *kubernetes.Clientset, ctx context.Context, namespace, name string, replicas int32) error
+	}
+	hzMemberLister interface {
+		list(ac memberAccessConfig) ([]listedHzMember, error)
+	}
+	hzClusterScaler interface {
+		replicas(ac memberAccessConfig) (int, error)
+		scale(ac memberAccessConfig, replicas int) error
+	}
 	k8sOutOfClusterMemberAccess struct {
 		kubeconfig, namespace, labelSelector string
 	}
@@ -47,8 +61,26 @@ type (
 	memberAccessConfig struct {
 		memberAccessMode string
 		targetOnlyActive bool
+		selection        memberSelectionConfig
 		k8sOutOfCluster  k8sOutOfClusterMemberAccess
 		k8sInCluster     k8sInClusterMemberAccess
+		process          processMemberAccess
+	}
+	// accessModeHzMemberChooser and accessModeHzMemberKiller delegate to the member chooser or member killer,
+	// respectively, for the member access mode given in the config, which is only known once the monkey has populated
+	// its config.
+	accessModeHzMemberChooser struct {
+		choosers map[string]hzMemberChooser
+	}
+	accessModeHzMemberKiller struct {
+		killers map[string]hzMemberKiller
+	}
+	// listedHzMember describes a Hazelcast member as it was found at the time of listing. The uid tells members
+	// apart that share the same identifier, such as a StatefulSet's Pod and its replacement.
+	listedHzMember struct {
+		member hzMember
+		uid    string
+		ready  bool
 	}
 	defaultK8sConfigBuilder        struct{}
 	defaultK8sClientsetInitializer struct{}
@@ -60,18 +92,34 @@ type (
 	defaultK8sNamespaceDiscoverer struct {
 		discoveredNamespace string
 	}
-	defaultK8sPodLister  struct{}
-	defaultK8sPodDeleter struct{}
-	k8sHzMemberChooser   struct {
+	defaultK8sPodLister         struct{}
+	defaultK8sPodDeleter        struct{}
+	defaultK8sStatefulSetLister struct{}
+	defaultK8sStatefulSetScaler struct{}
+	k8sHzMemberChooser          struct {
 		clientsetProvider   k8sClientsetProvider
 		namespaceDiscoverer k8sNamespaceDiscoverer
 		podLister           k8sPodLister
+		podSelector         *k8sPodSelector
 	}
 	k8sHzMemberKiller struct {
 		clientsetProvider   k8sClientsetProvider
 		namespaceDiscoverer k8sNamespaceDiscoverer
 		podDeleter          k8sPodDeleter
 	}
+	k8sHzMemberLister struct {
+		clientsetProvider   k8sClientsetProvider
+		namespaceDiscoverer k8sNamespaceDiscoverer
+		podLister           k8sPodLister
+	}
+	// k8sHzClusterScaler scales the StatefulSet the Hazelcast members belong to, which it finds by means of the
+	// same label selector used to find the members' Pods.
+	k8sHzClusterScaler struct {
+		clientsetProvider   k8sClientsetProvider
+		namespaceDiscoverer k8sNamespaceDiscoverer
+		statefulSetLister   k8sStatefulSetLister
+		statefulSetScaler   k8sStatefulSetScaler
+	}
 )
 
 const (
@@ -79,9 +127,56 @@ const (
 )
 
 var (
-	noMemberFoundError = errors.New("unable to identify hazelcast member to be terminated")
+	noMemberFoundError      = errors.New("unable to identify hazelcast member to be terminated")
+	noStatefulSetFoundError = errors.New("unable to identify hazelcast statefulset to be scaled")
 )
 
+func (c *accessModeHzMemberChooser) choose(ac memberAccessConfig) (hzMember, error) {
+
+	if chooser, ok := c.choosers[ac.memberAccessMode]; ok {
+		return chooser.choose(ac)
+	}
+
+	return hzMember{}, fmt.Errorf("no hazelcast member chooser available for member access mode: %s", ac.memberAccessMode)
+
+}
+
+func (k *accessModeHzMemberKiller) kill(member hzMember, ac memberAccessConfig, memberGrace sleepConfig) error {
+
+	if killer, ok := k.killers[ac.memberAccessMode]; ok {
+		return killer.kill(member, ac, memberGrace)
+	}
+
+	return fmt.Errorf("no hazelcast member killer available for member access mode: %s", ac.memberAccessMode)
+
+}
+
+func (c *accessModeHzMemberChooser) countReady(ac memberAccessConfig) (int, error) {
+
+	if chooser, ok := c.choosers[ac.memberAccessMode]; ok {
+		if counter, ok := chooser.(readyMemberCounter); ok {
+			return counter.countReady(ac)
+		}
+	}
+
+	return 0, fmt.Errorf("no ready hazelcast member counter available for member access mode: %s", ac.memberAccessMode)
+
+}
+
+func evaluateGracePeriodSeconds(memberGrace sleepConfig) int {
+
+	if !memberGrace.enabled {
+		return 0
+	}
+
+	if memberGrace.enableRandomness {
+		return rand.Intn(memberGrace.durationSeconds + 1)
+	}
+
+	return memberGrace.durationSeconds
+
+}
+
 func labelSelectorFromConfig(ac memberAccessConfig) (string, error) {
 
 	switch ac.memberAccessMode {
@@ -176,6 +271,34 @@ func (d *defaultK8sPodDeleter) delete(cs *kubernetes.Clientset, ctx context.Cont
 
 }
 
+func (l *defaultK8sStatefulSetLister) list(cs *kubernetes.Clientset, ctx context.Context, namespace string, listOptions metav1.ListOptions) (*appsv1.StatefulSetList, error) {
+
+	if statefulSetList, err := cs.AppsV1().StatefulSets(namespace).List(ctx, listOptions); err != nil {
+		return nil, err
+	} else {
+		return statefulSetList, nil
+	}
+
+}
+
+// scale goes through the StatefulSet's scale subresource, so the rest of the StatefulSet's spec cannot be
+// overwritten by accident.
+func (s *defaultK8sStatefulSetScaler) scale(cs *kubernetes.Clientset, ctx context.Context, namespace, name string, replicas int32) error {
+
+	sc, err := cs.AppsV1().StatefulSets(namespace).GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	sc.Spec.Replicas = replicas
+	if _, err := cs.AppsV1().StatefulSets(namespace).UpdateScale(ctx, name, sc, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
 func (p *defaultK8sClientsetProvider) getOrInit(ac memberAccessConfig) (*kubernetes.Clientset, error) {
 
 	if p.cs != nil {
@@ -268,20 +391,16 @@ func (chooser *k8sHzMemberChooser) choose(ac memberAccessConfig) (hzMember, erro
 		return hzMember{}, noMemberFoundError
 	}
 
-	var podToKill v1.Pod
-	podFound := false
-
+	candidates := pods
 	if ac.targetOnlyActive {
-		lp.LogChaosMonkeyEvent("targetOnlyActive enabled -- trying to find active (ready) hazelcast member pod", log.TraceLevel)
-		for i := 0; i < len(pods); i++ {
-			candidate := selectRandomPodFromList(pods)
-			if isPodReady(candidate) {
-				podToKill = candidate
-				podFound = true
-				break
+		lp.LogChaosMonkeyEvent("targetOnlyActive enabled -- only considering active (ready) hazelcast member pods", log.TraceLevel)
+		candidates = make([]v1.Pod, 0, len(pods))
+		for _, p := range pods {
+			if isPodReady(p) {
+				candidates = append(candidates, p)
 			}
 		}
-		if !podFound {
+		if len(candidates) == 0 {
 			var msg string
 			if len(pods) == 1 {
 				msg = "the only available pod was not ready (can only target ready pods because targetOnlyActive was enabled)"
@@ -291,9 +410,13 @@ func (chooser *k8sHzMemberChooser) choose(ac memberAccessConfig) (hzMember, erro
 			lp.LogChaosMonkeyEvent(msg, log.WarnLevel)
 			return hzMember{}, noMemberFoundError
 		}
-	} else {
-		lp.LogChaosMonkeyEvent(fmt.Sprintf("targetOnlyActive disabled -- randomly choosing one out of %d pods", len(pods)), log.TraceLevel)
-		podToKill = selectRandomPodFromList(pods)
+	}
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("using selection strategy '%s' to choose one out of %d pod/-s", ac.selection.strategy, len(candidates)), log.TraceLevel)
+	podToKill, err := chooser.podSelector.selectPod(candidates, ac.selection)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to choose hazelcast member: selection strategy '%s' did not yield pod: %s", ac.selection.strategy, err.Error()), log.WarnLevel)
+		return hzMember{}, err
 	}
 
 	lp.LogChaosMonkeyEvent(fmt.Sprintf("successfully chose hazelcast member: %s", podToKill.Name), log.InfoLevel)
@@ -301,6 +424,44 @@ func (chooser *k8sHzMemberChooser) choose(ac memberAccessConfig) (hzMember, erro
 
 }
 
+func (chooser *k8sHzMemberChooser) countReady(ac memberAccessConfig) (int, error) {
+
+	clientset, err := chooser.clientsetProvider.getOrInit(ac)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to count ready hazelcast members: clientset initialization failed: %s", err.Error()), log.ErrorLevel)
+		return 0, err
+	}
+
+	namespace, err := chooser.namespaceDiscoverer.getOrDiscover(ac)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to count ready hazelcast members: namespace to operate in could not be determined: %s", err.Error()), log.ErrorLevel)
+		return 0, err
+	}
+
+	labelSelector, err := labelSelectorFromConfig(ac)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to count ready hazelcast members: could not determine label selector: %s", err.Error()), log.ErrorLevel)
+		return 0, err
+	}
+
+	podList, err := chooser.podLister.list(clientset, context.TODO(), namespace, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to count ready hazelcast members: could not list pods: %s", err.Error()), log.ErrorLevel)
+		return 0, err
+	}
+
+	numReady := 0
+	for _, p := range podList.Items {
+		if isPodReady(p) {
+			numReady++
+		}
+	}
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("found %d ready out of %d hazelcast member pod/-s", numReady, len(podList.Items)), log.TraceLevel)
+	return numReady, nil
+
+}
+
 func selectRandomPodFromList(pods []v1.Pod) v1.Pod {
 
 	randomIndex := rand.Intn(len(pods))
@@ -342,16 +503,7 @@ func (killer *k8sHzMemberKiller) kill(m hzMember, ac memberAccessConfig, memberG
 
 	ctx := context.TODO()
 
-	var gracePeriod int
-	if memberGrace.enabled {
-		if memberGrace.enableRandomness {
-			gracePeriod = rand.Intn(memberGrace.durationSeconds + 1)
-		} else {
-			gracePeriod = memberGrace.durationSeconds
-		}
-	} else {
-		gracePeriod = 0
-	}
+	gracePeriod := evaluateGracePeriodSeconds(memberGrace)
 
 	lp.LogChaosMonkeyEvent(fmt.Sprintf("using grace period seconds '%d' to kill hazelcast member '%s'", gracePeriod, m.identifier), log.TraceLevel)
 
@@ -367,3 +519,118 @@ func (killer *k8sHzMemberKiller) kill(m hzMember, ac memberAccessConfig, memberG
 	return nil
 
 }
+
+func (lister *k8sHzMemberLister) list(ac memberAccessConfig) ([]listedHzMember, error) {
+
+	clientset, err := lister.clientsetProvider.getOrInit(ac)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to list hazelcast members: clientset initialization failed: %s", err.Error()), log.ErrorLevel)
+		return nil, err
+	}
+
+	namespace, err := lister.namespaceDiscoverer.getOrDiscover(ac)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to list hazelcast members: namespace to operate in could not be determined: %s", err.Error()), log.ErrorLevel)
+		return nil, err
+	}
+
+	labelSelector, err := labelSelectorFromConfig(ac)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to list hazelcast members: could not determine label selector: %s", err.Error()), log.ErrorLevel)
+		return nil, err
+	}
+
+	podList, err := lister.podLister.list(clientset, context.TODO(), namespace, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to list hazelcast members: could not list pods: %s", err.Error()), log.ErrorLevel)
+		return nil, err
+	}
+
+	members := make([]listedHzMember, 0, len(podList.Items))
+	for _, p := range podList.Items {
+		// Pods already being deleted are on their way out, so they don't count as members anymore
+		if p.DeletionTimestamp != nil {
+			continue
+		}
+		members = append(members, listedHzMember{
+			member: hzMember{p.Name},
+			uid:    string(p.UID),
+			ready:  isPodReady(p),
+		})
+	}
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("listed %d hazelcast member pod/-s using label selector '%s' in namespace '%s'", len(members), labelSelector, namespace), log.TraceLevel)
+	return members, nil
+
+}
+
+func (scaler *k8sHzClusterScaler) replicas(ac memberAccessConfig) (int, error) {
+
+	_, _, sts, err := scaler.findStatefulSet(ac)
+	if err != nil {
+		return 0, err
+	}
+
+	// Kubernetes defaults the number of replicas to 1 if none have been specified
+	if sts.Spec.Replicas == nil {
+		return 1, nil
+	}
+
+	return int(*sts.Spec.Replicas), nil
+
+}
+
+func (scaler *k8sHzClusterScaler) scale(ac memberAccessConfig, replicas int) error {
+
+	clientset, namespace, sts, err := scaler.findStatefulSet(ac)
+	if err != nil {
+		return err
+	}
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("scaling hazelcast statefulset '%s' in namespace '%s' to %d replica/-s", sts.Name, namespace, replicas), log.InfoLevel)
+
+	if err := scaler.statefulSetScaler.scale(clientset, context.TODO(), namespace, sts.Name, int32(replicas)); err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("scaling hazelcast statefulset '%s' unsuccessful: %s", sts.Name, err.Error()), log.ErrorLevel)
+		return err
+	}
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("successfully scaled hazelcast statefulset '%s' to %d replica/-s", sts.Name, replicas), log.InfoLevel)
+	return nil
+
+}
+
+func (scaler *k8sHzClusterScaler) findStatefulSet(ac memberAccessConfig) (*kubernetes.Clientset, string, appsv1.StatefulSet, error) {
+
+	clientset, err := scaler.clientsetProvider.getOrInit(ac)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to find hazelcast statefulset: clientset initialization failed: %s", err.Error()), log.ErrorLevel)
+		return nil, "", appsv1.StatefulSet{}, err
+	}
+
+	namespace, err := scaler.namespaceDiscoverer.getOrDiscover(ac)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to find hazelcast statefulset: namespace to operate in could not be determined: %s", err.Error()), log.ErrorLevel)
+		return nil, "", appsv1.StatefulSet{}, err
+	}
+
+	labelSelector, err := labelSelectorFromConfig(ac)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to find hazelcast statefulset: could not determine label selector: %s", err.Error()), log.ErrorLevel)
+		return nil, "", appsv1.StatefulSet{}, err
+	}
+
+	statefulSetList, err := scaler.statefulSetLister.list(clientset, context.TODO(), namespace, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to find hazelcast statefulset: could not list statefulsets: %s", err.Error()), log.ErrorLevel)
+		return nil, "", appsv1.StatefulSet{}, err
+	}
+
+	// Scaling one out of several StatefulSets would not scale "the" cluster, so refuse to guess
+	if len(statefulSetList.Items) != 1 {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("expected exactly one statefulset for label selector '%s' in namespace '%s', found %d", labelSelector, namespace, len(statefulSetList.Items)), log.ErrorLevel)
+		return nil, "", appsv1.StatefulSet{}, noStatefulSetFoundError
+	}
+
+	return clientset, namespace, statefulSetList.Items[0], nil
+
+}
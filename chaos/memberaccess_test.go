@@ -3,6 +3,7 @@ package chaos
 import (
 	"context"
 	"errors"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -10,6 +11,7 @@ import (
 	"math"
 	"strings"
 	"testing"
+	"time"
 )
 
 type (
@@ -42,6 +44,16 @@ type (
 		numInvocations     int
 		gracePeriodSeconds int64
 	}
+	testK8sStatefulSetLister struct {
+		statefulSetsToReturn []appsv1.StatefulSet
+		returnError          bool
+	}
+	testK8sStatefulSetScaler struct {
+		returnError    bool
+		numInvocations int
+		scaledName     string
+		scaledReplicas int32
+	}
 )
 
 var (
@@ -49,6 +61,8 @@ var (
 	configBuildError              = errors.New("another impossible error")
 	podListError                  = errors.New("another one")
 	podDeleteError                = errors.New("and yet another one")
+	statefulSetListError          = errors.New("this one, too")
+	statefulSetScaleError         = errors.New("you guessed it")
 	namespaceNotDiscoverableError = errors.New("and here goes your sanity")
 )
 
@@ -155,6 +169,31 @@ func (d *testK8sPodDeleter) delete(_ *kubernetes.Clientset, _ context.Context, _
 
 }
 
+func (l *testK8sStatefulSetLister) list(_ *kubernetes.Clientset, _ context.Context, _ string, _ metav1.ListOptions) (*appsv1.StatefulSetList, error) {
+
+	if l.returnError {
+		return nil, statefulSetListError
+	}
+
+	return &appsv1.StatefulSetList{Items: l.statefulSetsToReturn}, nil
+
+}
+
+func (s *testK8sStatefulSetScaler) scale(_ *kubernetes.Clientset, _ context.Context, _, name string, replicas int32) error {
+
+	s.numInvocations++
+
+	if s.returnError {
+		return statefulSetScaleError
+	}
+
+	s.scaledName = name
+	s.scaledReplicas = replicas
+
+	return nil
+
+}
+
 func TestSelectRandomPodFromList(t *testing.T) {
 
 	t.Log("given random pod selection")
@@ -567,7 +606,7 @@ func TestChooseMemberOnK8s(t *testing.T) {
 		t.Log("\twhen clientset cannot be initialized")
 		{
 			podLister := &testK8sPodLister{[]v1.Pod{}, false, 0}
-			memberChooser := k8sHzMemberChooser{errCsProvider, testNamespaceDiscoverer, podLister}
+			memberChooser := k8sHzMemberChooser{errCsProvider, testNamespaceDiscoverer, podLister, &k8sPodSelector{}}
 			member, err := memberChooser.choose(assembleTestAccessConfig(k8sOutOfClusterAccessMode, defaultKubeconfig, true))
 
 			msg := "\t\terror must be returned"
@@ -594,7 +633,7 @@ func TestChooseMemberOnK8s(t *testing.T) {
 		t.Log("\twhen namespace discovery is not successful")
 		{
 			podLister := &testK8sPodLister{[]v1.Pod{}, false, 0}
-			memberChooser := k8sHzMemberChooser{csProvider, errTestNamespaceDiscoverer, nil}
+			memberChooser := k8sHzMemberChooser{csProvider, errTestNamespaceDiscoverer, nil, &k8sPodSelector{}}
 			member, err := memberChooser.choose(testAccessConfig)
 
 			msg := "\t\terror must be returned"
@@ -623,7 +662,7 @@ func TestChooseMemberOnK8s(t *testing.T) {
 			ac := testAccessConfig
 			ac.memberAccessMode = "awesomeUnknownMemberAccessMode"
 			podLister := &testK8sPodLister{[]v1.Pod{}, false, 0}
-			memberChooser := k8sHzMemberChooser{csProvider, testNamespaceDiscoverer, podLister}
+			memberChooser := k8sHzMemberChooser{csProvider, testNamespaceDiscoverer, podLister, &k8sPodSelector{}}
 			member, err := memberChooser.choose(ac)
 
 			msg := "\t\terror must be returned"
@@ -650,7 +689,7 @@ func TestChooseMemberOnK8s(t *testing.T) {
 		t.Log("\twhen pod lister returns error")
 		{
 			podLister := &testK8sPodLister{[]v1.Pod{}, true, 0}
-			memberChooser := k8sHzMemberChooser{csProvider, testNamespaceDiscoverer, podLister}
+			memberChooser := k8sHzMemberChooser{csProvider, testNamespaceDiscoverer, podLister, &k8sPodSelector{}}
 			member, err := memberChooser.choose(testAccessConfig)
 
 			msg := "\t\terror must be returned"
@@ -677,7 +716,7 @@ func TestChooseMemberOnK8s(t *testing.T) {
 		t.Log("\twhen no pods are present")
 		{
 			memberChooser := k8sHzMemberChooser{csProvider, testNamespaceDiscoverer,
-				&testK8sPodLister{[]v1.Pod{}, false, 0}}
+				&testK8sPodLister{[]v1.Pod{}, false, 0}, &k8sPodSelector{}}
 			member, err := memberChooser.choose(assembleTestAccessConfig(k8sOutOfClusterAccessMode, defaultKubeconfig, true))
 
 			msg := "\t\terror must be returned"
@@ -699,7 +738,7 @@ func TestChooseMemberOnK8s(t *testing.T) {
 			pod := assemblePod("hazelcastplatform-0", true)
 			pods := []v1.Pod{pod}
 			memberChooser := k8sHzMemberChooser{csProvider, testNamespaceDiscoverer,
-				&testK8sPodLister{pods, false, 0}}
+				&testK8sPodLister{pods, false, 0}, &k8sPodSelector{}}
 			member, err := memberChooser.choose(testAccessConfig)
 
 			msg := "\t\tno error must be returned"
@@ -721,7 +760,7 @@ func TestChooseMemberOnK8s(t *testing.T) {
 			pod := assemblePod("hazelcastplatform-0", false)
 			pods := []v1.Pod{pod}
 			memberChooser := k8sHzMemberChooser{csProvider, testNamespaceDiscoverer,
-				&testK8sPodLister{pods, false, 0}}
+				&testK8sPodLister{pods, false, 0}, &k8sPodSelector{}}
 			member, err := memberChooser.choose(testAccessConfig)
 
 			msg := "\t\terror must be returned"
@@ -743,7 +782,7 @@ func TestChooseMemberOnK8s(t *testing.T) {
 			pod := assemblePod("hazelcastplatform-0", false)
 			pods := []v1.Pod{pod}
 			memberChooser := k8sHzMemberChooser{csProvider, testNamespaceDiscoverer,
-				&testK8sPodLister{pods, false, 0}}
+				&testK8sPodLister{pods, false, 0}, &k8sPodSelector{}}
 			member, err := memberChooser.choose(assembleTestAccessConfig(k8sInClusterAccessMode, defaultKubeconfig, false))
 
 			msg := "\t\tno error must be returned"
@@ -764,6 +803,149 @@ func TestChooseMemberOnK8s(t *testing.T) {
 
 }
 
+func TestCountReadyMembersOnK8s(t *testing.T) {
+
+	t.Log("given the member chooser's method to count ready hazelcast members")
+	{
+		t.Log("\twhen clientset cannot be initialized")
+		{
+			podLister := &testK8sPodLister{[]v1.Pod{}, false, 0}
+			memberChooser := k8sHzMemberChooser{errCsProvider, testNamespaceDiscoverer, podLister, &k8sPodSelector{}}
+			_, err := memberChooser.countReady(testAccessConfig)
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, clientsetInitError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tpod lister must have no invocations"
+			if podLister.numInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen pod lister returns error")
+		{
+			podLister := &testK8sPodLister{[]v1.Pod{}, true, 0}
+			memberChooser := k8sHzMemberChooser{csProvider, testNamespaceDiscoverer, podLister, &k8sPodSelector{}}
+			_, err := memberChooser.countReady(testAccessConfig)
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, podListError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+		t.Log("\twhen both ready and non-ready pods are present")
+		{
+			pods := []v1.Pod{assemblePod("hazelcastimdg-0", true), assemblePod("hazelcastimdg-1", false), assemblePod("hazelcastimdg-2", true)}
+			podLister := &testK8sPodLister{pods, false, 0}
+			memberChooser := k8sHzMemberChooser{csProvider, testNamespaceDiscoverer, podLister, &k8sPodSelector{}}
+			numReady, err := memberChooser.countReady(testAccessConfig)
+
+			msg := "\t\tonly ready pods must be counted"
+			if err == nil && numReady == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, numReady, err)
+			}
+		}
+	}
+
+}
+
+func TestListMembersOnK8s(t *testing.T) {
+
+	t.Log("given the member lister's method to list hazelcast members")
+	{
+		t.Log("\twhen clientset cannot be initialized")
+		{
+			podLister := &testK8sPodLister{[]v1.Pod{}, false, 0}
+			memberLister := k8sHzMemberLister{errCsProvider, testNamespaceDiscoverer, podLister}
+			_, err := memberLister.list(testAccessConfig)
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, clientsetInitError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tpod lister must have no invocations"
+			if podLister.numInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen namespace cannot be discovered")
+		{
+			podLister := &testK8sPodLister{[]v1.Pod{}, false, 0}
+			memberLister := k8sHzMemberLister{csProvider, errTestNamespaceDiscoverer, podLister}
+			_, err := memberLister.list(testAccessConfig)
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, namespaceNotDiscoverableError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+		t.Log("\twhen pod lister returns error")
+		{
+			podLister := &testK8sPodLister{[]v1.Pod{}, true, 0}
+			memberLister := k8sHzMemberLister{csProvider, testNamespaceDiscoverer, podLister}
+			_, err := memberLister.list(testAccessConfig)
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, podListError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+		t.Log("\twhen pods in different states are present")
+		{
+			terminating := assemblePod("hazelcastimdg-2", true)
+			terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			pods := []v1.Pod{assemblePod("hazelcastimdg-0", true), assemblePod("hazelcastimdg-1", false), terminating}
+			pods[0].UID = "a1b2"
+			pods[1].UID = "c3d4"
+
+			podLister := &testK8sPodLister{pods, false, 0}
+			memberLister := k8sHzMemberLister{csProvider, testNamespaceDiscoverer, podLister}
+			members, err := memberLister.list(testAccessConfig)
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tpods being deleted must be omitted"
+			if len(members) == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, len(members))
+			}
+
+			msg = "\t\tremaining pods must be listed with their names, uids, and readiness"
+			expected := []listedHzMember{{hzMember{"hazelcastimdg-0"}, "a1b2", true}, {hzMember{"hazelcastimdg-1"}, "c3d4", false}}
+			if members[0] == expected[0] && members[1] == expected[1] {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, members)
+			}
+		}
+	}
+
+}
+
 func TestKillMemberOnK8s(t *testing.T) {
 
 	t.Log("given the member killer monkey's method to kill a hazelcast member on kubernetes")
@@ -940,6 +1122,130 @@ func TestKillMemberOnK8s(t *testing.T) {
 
 }
 
+func TestScaleClusterOnK8s(t *testing.T) {
+
+	t.Log("given the cluster scaler's methods to determine and change the number of replicas of the hazelcast statefulset")
+	{
+		t.Log("\twhen clientset cannot be initialized")
+		{
+			stsScaler := &testK8sStatefulSetScaler{}
+			scaler := k8sHzClusterScaler{errCsProvider, testNamespaceDiscoverer, &testK8sStatefulSetLister{}, stsScaler}
+			err := scaler.scale(testAccessConfig, 4)
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, clientsetInitError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tstatefulset scaler must have no invocations"
+			if stsScaler.numInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen statefulset lister returns error")
+		{
+			scaler := k8sHzClusterScaler{csProvider, testNamespaceDiscoverer, &testK8sStatefulSetLister{returnError: true}, &testK8sStatefulSetScaler{}}
+			_, err := scaler.replicas(testAccessConfig)
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, statefulSetListError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+		t.Log("\twhen no statefulset matches label selector")
+		{
+			scaler := k8sHzClusterScaler{csProvider, testNamespaceDiscoverer, &testK8sStatefulSetLister{}, &testK8sStatefulSetScaler{}}
+			_, err := scaler.replicas(testAccessConfig)
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, noStatefulSetFoundError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+		t.Log("\twhen multiple statefulsets match label selector")
+		{
+			stsScaler := &testK8sStatefulSetScaler{}
+			statefulSets := []appsv1.StatefulSet{assembleStatefulSet("hazelcastplatform", 3), assembleStatefulSet("hazelcastplatform-wan", 3)}
+			scaler := k8sHzClusterScaler{csProvider, testNamespaceDiscoverer, &testK8sStatefulSetLister{statefulSetsToReturn: statefulSets}, stsScaler}
+			err := scaler.scale(testAccessConfig, 4)
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, noStatefulSetFoundError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tno statefulset must have been scaled"
+			if stsScaler.numInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen exactly one statefulset matches label selector")
+		{
+			stsScaler := &testK8sStatefulSetScaler{}
+			statefulSets := []appsv1.StatefulSet{assembleStatefulSet("hazelcastplatform", 3)}
+			scaler := k8sHzClusterScaler{csProvider, testNamespaceDiscoverer, &testK8sStatefulSetLister{statefulSetsToReturn: statefulSets}, stsScaler}
+
+			replicas, err := scaler.replicas(testAccessConfig)
+
+			msg := "\t\tnumber of replicas must be returned"
+			if err == nil && replicas == 3 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, replicas, err)
+			}
+
+			err = scaler.scale(testAccessConfig, 4)
+
+			msg = "\t\tstatefulset must have been scaled to given number of replicas"
+			if err == nil && stsScaler.scaledName == "hazelcastplatform" && stsScaler.scaledReplicas == 4 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, stsScaler.scaledName, stsScaler.scaledReplicas, err)
+			}
+		}
+		t.Log("\twhen statefulset does not specify number of replicas")
+		{
+			sts := assembleStatefulSet("hazelcastplatform", 0)
+			sts.Spec.Replicas = nil
+			scaler := k8sHzClusterScaler{csProvider, testNamespaceDiscoverer, &testK8sStatefulSetLister{statefulSetsToReturn: []appsv1.StatefulSet{sts}}, &testK8sStatefulSetScaler{}}
+			replicas, err := scaler.replicas(testAccessConfig)
+
+			msg := "\t\tkubernetes default of one replica must be returned"
+			if err == nil && replicas == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, replicas, err)
+			}
+		}
+		t.Log("\twhen statefulset scaler returns error")
+		{
+			statefulSets := []appsv1.StatefulSet{assembleStatefulSet("hazelcastplatform", 3)}
+			scaler := k8sHzClusterScaler{csProvider, testNamespaceDiscoverer, &testK8sStatefulSetLister{statefulSetsToReturn: statefulSets}, &testK8sStatefulSetScaler{returnError: true}}
+			err := scaler.scale(testAccessConfig, 4)
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, statefulSetScaleError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+	}
+
+}
+
 func assemblePod(name string, ready bool) v1.Pod {
 
 	var readyCondition v1.ConditionStatus
@@ -986,6 +1292,16 @@ func assembleTestAccessConfig(memberAccessMode, kubeconfig string, targetOnlyAct
 			labelSelector: "app.kubernetes.io/name=hazelcastplatform",
 		},
 		k8sInCluster: k8sInClusterMemberAccess{},
+		selection:    memberSelectionConfig{strategy: randomSelection},
+	}
+
+}
+
+func assembleStatefulSet(name string, replicas int32) appsv1.StatefulSet {
+
+	return appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
 	}
 
 }
@@ -1,14 +1,19 @@
 package chaos
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/hazelcast/hazelcast-go-client/cluster"
 	log "github.com/sirupsen/logrus"
 	"hazeltest/api"
 	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
 	"hazeltest/logging"
 	"hazeltest/status"
 	"math/rand"
+	"net/http"
+	"regexp"
 	"sync"
 	"time"
 )
@@ -25,23 +30,38 @@ type (
 		sleep(sc *sleepConfig, sf evaluateTimeToSleep)
 	}
 	monkey interface {
-		init(a client.ConfigPropertyAssigner, s sleeper, c hzMemberChooser, k hzMemberKiller, g *status.Gatherer,
-			readyFunc raiseReady, notReadyFunc raiseNotReady)
+		init(a client.ConfigPropertyAssigner, d monkeyDependencies, g *status.Gatherer, readyFunc raiseReady,
+			notReadyFunc raiseNotReady)
 		causeChaos()
 	}
+	// monkeyDependencies bundles everything monkeys need to access Hazelcast members -- each monkey picks the
+	// dependencies it requires.
+	monkeyDependencies struct {
+		s           sleeper
+		chooser     hzMemberChooser
+		killer      hzMemberKiller
+		guard       safetyGuard
+		lister      hzMemberLister
+		prober      clusterSafetyProber
+		scaler      hzClusterScaler
+		disruptions *disruptionTracker
+	}
 	hzMember struct {
 		identifier string
 	}
 	memberKillerMonkey struct {
-		a                client.ConfigPropertyAssigner
-		stateList        []state
-		s                sleeper
-		chooser          hzMemberChooser
-		killer           hzMemberKiller
-		g                *status.Gatherer
-		readyFunc        raiseReady
-		notReadyFunc     raiseNotReady
-		numMembersKilled uint32
+		a                     client.ConfigPropertyAssigner
+		stateList             []state
+		s                     sleeper
+		chooser               hzMemberChooser
+		killer                hzMemberKiller
+		guard                 safetyGuard
+		disruptions           *disruptionTracker
+		g                     *status.Gatherer
+		readyFunc             raiseReady
+		notReadyFunc          raiseNotReady
+		numMembersKilled      uint32
+		numKillsSkippedUnsafe uint32
 	}
 	monkeyConfigBuilder struct {
 		monkeyKeyPath string
@@ -58,6 +78,7 @@ type (
 		accessConfig     *memberAccessConfig
 		sleep            *sleepConfig
 		memberGrace      *sleepConfig
+		safetyGuard      *safetyGuardConfig
 	}
 	defaultSleeper struct{}
 	state          string
@@ -68,6 +89,7 @@ type (
 const (
 	k8sOutOfClusterAccessMode = "k8sOutOfCluster"
 	k8sInClusterAccessMode    = "k8sInCluster"
+	processAccessMode         = "process"
 )
 
 const (
@@ -126,15 +148,18 @@ func (s *defaultSleeper) sleep(sc *sleepConfig, sf evaluateTimeToSleep) {
 
 }
 
-func (m *memberKillerMonkey) init(a client.ConfigPropertyAssigner, s sleeper, c hzMemberChooser, k hzMemberKiller,
-	g *status.Gatherer, readyFunc raiseReady, notReadyFunc raiseNotReady) {
+func (m *memberKillerMonkey) init(a client.ConfigPropertyAssigner, d monkeyDependencies, g *status.Gatherer,
+	readyFunc raiseReady, notReadyFunc raiseNotReady) {
 
 	m.a = a
-	m.s = s
-	m.chooser = c
-	m.killer = k
+	m.s = d.s
+	m.chooser = d.chooser
+	m.killer = d.killer
+	m.guard = d.guard
+	m.disruptions = d.disruptions
 	m.g = g
 	m.numMembersKilled = 0
+	m.numKillsSkippedUnsafe = 0
 	m.readyFunc = readyFunc
 	m.notReadyFunc = notReadyFunc
 
@@ -180,24 +205,12 @@ func (m *memberKillerMonkey) causeChaos() {
 		f := rand.Float64()
 		if f <= mc.chaosProbability {
 			lp.LogChaosMonkeyEvent(fmt.Sprintf("member killer monkey active in run %d", i), log.TraceLevel)
-			member, err := m.chooser.choose(*mc.accessConfig)
-			if err != nil {
-				var msg string
-				if errors.Is(err, noMemberFoundError) {
-					msg = "no hazelcast member available to be killed -- will try again in next iteration"
-				} else {
-					msg = "unable to choose hazelcast member to kill -- will try again in next iteration"
-				}
-				lp.LogChaosMonkeyEvent(msg, log.WarnLevel)
+			if ok, other := m.disruptions.begin("memberKiller"); !ok {
+				lp.LogChaosMonkeyEvent(fmt.Sprintf("not safe to kill hazelcast member in run %d -- skipping: disruption by monkey '%s' still in progress", i, other), log.WarnLevel)
+				m.updateNumKillsSkippedUnsafe()
 				continue
 			}
-
-			err = m.killer.kill(member, *mc.accessConfig, *mc.memberGrace)
-			if err != nil {
-				lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to kill chosen hazelcast member '%s' -- will try again in next iteration", member.identifier), log.WarnLevel)
-			} else {
-				m.updateNumMembersKilled()
-			}
+			m.disruptions.end(m.killMember(i, mc))
 		} else {
 			lp.LogChaosMonkeyEvent(fmt.Sprintf("member killer monkey inactive in run %d", i), log.InfoLevel)
 		}
@@ -208,6 +221,39 @@ func (m *memberKillerMonkey) causeChaos() {
 
 }
 
+// killMember chooses and kills a Hazelcast member, provided the safety guard deems it safe to do so, and reports
+// whether a member has been killed.
+func (m *memberKillerMonkey) killMember(i uint32, mc *monkeyConfig) bool {
+
+	if safe, reason := m.guard.isSafeToKill(mc.safetyGuard, *mc.accessConfig, m.disruptions.last()); !safe {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("not safe to kill hazelcast member in run %d -- skipping: %s", i, reason), log.WarnLevel)
+		m.updateNumKillsSkippedUnsafe()
+		return false
+	}
+
+	member, err := m.chooser.choose(*mc.accessConfig)
+	if err != nil {
+		var msg string
+		if errors.Is(err, noMemberFoundError) {
+			msg = "no hazelcast member available to be killed -- will try again in next iteration"
+		} else {
+			msg = "unable to choose hazelcast member to kill -- will try again in next iteration"
+		}
+		lp.LogChaosMonkeyEvent(msg, log.WarnLevel)
+		return false
+	}
+
+	err = m.killer.kill(member, *mc.accessConfig, *mc.memberGrace)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to kill chosen hazelcast member '%s' -- will try again in next iteration", member.identifier), log.WarnLevel)
+		return false
+	}
+
+	m.updateNumMembersKilled()
+	return true
+
+}
+
 func (m *memberKillerMonkey) updateNumMembersKilled() {
 
 	m.numMembersKilled++
@@ -215,10 +261,18 @@ func (m *memberKillerMonkey) updateNumMembersKilled() {
 
 }
 
+func (m *memberKillerMonkey) updateNumKillsSkippedUnsafe() {
+
+	m.numKillsSkippedUnsafe++
+	m.g.Updates <- status.Update{Key: statusKeyNumKillsSkippedUnsafe, Value: m.numKillsSkippedUnsafe}
+
+}
+
 func (m *memberKillerMonkey) insertInitialStatus() {
 
 	m.g.Updates <- status.Update{Key: statusKeyNumRuns, Value: uint32(0)}
 	m.g.Updates <- status.Update{Key: statusKeyNumMembersKilled, Value: uint32(0)}
+	m.g.Updates <- status.Update{Key: statusKeyNumKillsSkippedUnsafe, Value: uint32(0)}
 
 }
 
@@ -332,6 +386,11 @@ func (b monkeyConfigBuilder) populateConfig(a client.ConfigPropertyAssigner) (*m
 		return nil, err
 	}
 
+	gc, err := b.populateSafetyGuardConfig(a)
+	if err != nil {
+		return nil, err
+	}
+
 	return &monkeyConfig{
 		enabled:          enabled,
 		numRuns:          numRuns,
@@ -347,14 +406,13 @@ func (b monkeyConfigBuilder) populateConfig(a client.ConfigPropertyAssigner) (*m
 			durationSeconds:  memberGraceDurationSeconds,
 			enableRandomness: memberGraceEnableRandomness,
 		},
+		safetyGuard: gc,
 	}, nil
 
 }
 
 func (b monkeyConfigBuilder) populateMemberAccessConfig(a client.ConfigPropertyAssigner, accessMode string) (*memberAccessConfig, error) {
 
-	var assignmentOps []func() error
-
 	ac := &memberAccessConfig{
 		memberAccessMode: accessMode,
 	}
@@ -364,6 +422,43 @@ func (b monkeyConfigBuilder) populateMemberAccessConfig(a client.ConfigPropertyA
 		return nil, err
 	}
 
+	if err := a.Assign(b.monkeyKeyPath+".memberAccess.selection.strategy", validateSelectionStrategy, func(a any) {
+		ac.selection.strategy = a.(string)
+	}); err != nil {
+		return nil, err
+	}
+
+	if ac.selection.strategy == fixedListSelection {
+		if err := a.Assign(b.monkeyKeyPath+".memberAccess.selection.fixedList.podNames", validatePodNames, func(a any) {
+			for _, n := range a.([]any) {
+				ac.selection.podNames = append(ac.selection.podNames, n.(string))
+			}
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := b.populateMemberAccessModeConfig(a, ac); err != nil {
+		return nil, err
+	}
+
+	// Other strategies rely on Pod properties, which processes don't have
+	if accessMode == processAccessMode && ac.selection.strategy != randomSelection && ac.selection.strategy != roundRobinSelection {
+		return nil, fmt.Errorf("member selection strategy not supported for member access mode '%s': %s", accessMode, ac.selection.strategy)
+	}
+
+	return ac, nil
+
+}
+
+// populateMemberAccessModeConfig populates the part of the member access config specific to the given config's
+// member access mode.
+func (b monkeyConfigBuilder) populateMemberAccessModeConfig(a client.ConfigPropertyAssigner, ac *memberAccessConfig) error {
+
+	var assignmentOps []func() error
+
+	accessMode := ac.memberAccessMode
+
 	switch accessMode {
 	case k8sOutOfClusterAccessMode:
 		var kubeconfig string
@@ -386,7 +481,7 @@ func (b monkeyConfigBuilder) populateMemberAccessConfig(a client.ConfigPropertyA
 		})
 		for _, f := range assignmentOps {
 			if err := f(); err != nil {
-				return nil, err
+				return err
 			}
 		}
 		ac.k8sOutOfCluster = k8sOutOfClusterMemberAccess{
@@ -399,55 +494,217 @@ func (b monkeyConfigBuilder) populateMemberAccessConfig(a client.ConfigPropertyA
 		if err := a.Assign(b.monkeyKeyPath+".memberAccess."+accessMode+".labelSelector", client.ValidateString, func(a any) {
 			labelSelector = a.(string)
 		}); err != nil {
-			return nil, err
+			return err
 		}
 		for _, f := range assignmentOps {
 			if err := f(); err != nil {
-				return nil, err
+				return err
 			}
 		}
 		ac.k8sInCluster = k8sInClusterMemberAccess{
 			labelSelector: labelSelector,
 		}
+	case processAccessMode:
+		var discovery string
+		if err := a.Assign(b.monkeyKeyPath+".memberAccess."+accessMode+".discovery", client.ValidateString, func(a any) {
+			discovery = a.(string)
+		}); err != nil {
+			return err
+		}
+		ac.process = processMemberAccess{discovery: discovery}
+		switch discovery {
+		case pidFileDiscovery:
+			if err := a.Assign(b.monkeyKeyPath+".memberAccess."+accessMode+".pidFile.pattern", client.ValidateString, func(a any) {
+				ac.process.pidFilePattern = a.(string)
+			}); err != nil {
+				return err
+			}
+		case processNameDiscovery:
+			if err := a.Assign(b.monkeyKeyPath+".memberAccess."+accessMode+".processName.pattern", client.ValidateString, func(a any) {
+				ac.process.processNamePattern = a.(string)
+			}); err != nil {
+				return err
+			}
+			if _, err := regexp.Compile(ac.process.processNamePattern); err != nil {
+				return fmt.Errorf("invalid process name pattern for member access mode '%s': %w", accessMode, err)
+			}
+		default:
+			return fmt.Errorf("unknown process discovery mode for member access mode '%s': %s", accessMode, discovery)
+		}
 	default:
-		return nil, fmt.Errorf("unknown hazelcast member access mode: %s", accessMode)
+		return fmt.Errorf("unknown hazelcast member access mode: %s", accessMode)
 	}
 
-	return ac, nil
+	return nil
+
+}
+
+func (b monkeyConfigBuilder) populateSafetyGuardConfig(a client.ConfigPropertyAssigner) (*safetyGuardConfig, error) {
+
+	var assignmentOps []func() error
+
+	gc := &safetyGuardConfig{}
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".safetyGuard.enabled", client.ValidateBool, func(a any) {
+			gc.enabled = a.(bool)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".safetyGuard.minReadyMembers.enabled", client.ValidateBool, func(a any) {
+			gc.minReadyMembers.enabled = a.(bool)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".safetyGuard.minReadyMembers.count", client.ValidateInt, func(a any) {
+			gc.minReadyMembers.count = a.(int)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".safetyGuard.minTimeSinceLastKill.enabled", client.ValidateBool, func(a any) {
+			gc.minTimeSinceLastKill.enabled = a.(bool)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".safetyGuard.minTimeSinceLastKill.durationSeconds", client.ValidateInt, func(a any) {
+			gc.minTimeSinceLastKill.durationSeconds = a.(int)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".safetyGuard.clusterSafe.enabled", client.ValidateBool, func(a any) {
+			gc.clusterSafe.enabled = a.(bool)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".safetyGuard.clusterSafe.healthCheckUrl", client.ValidateString, func(a any) {
+			gc.clusterSafe.healthCheckUrl = a.(string)
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	return gc, nil
+
+}
+
+func validateSelectionStrategy(keyPath string, a any) error {
+
+	if err := client.ValidateString(keyPath, a); err != nil {
+		return err
+	}
+
+	switch a.(string) {
+	case randomSelection, oldestPodSelection, youngestPodSelection, roundRobinSelection, masterSelection, fixedListSelection:
+		return nil
+	default:
+		return fmt.Errorf("selection strategy expected to be one of '%s', '%s', '%s', '%s', '%s', or '%s', got %v",
+			randomSelection, oldestPodSelection, youngestPodSelection, roundRobinSelection, masterSelection, fixedListSelection, a)
+	}
 
 }
 
-func RunMonkeys() {
+func validatePodNames(keyPath string, a any) error {
+
+	names, ok := a.([]any)
+	if !ok {
+		return fmt.Errorf("%s: expected list of pod names, got %v", keyPath, a)
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("%s: expected at least one pod name", keyPath)
+	}
+
+	for _, n := range names {
+		if err := client.ValidateString(keyPath, n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+
+}
+
+func RunMonkeys(hzCluster string, hzMembers []string) {
 
 	clientID := client.ID()
 	lp.LogChaosMonkeyEvent(fmt.Sprintf("%s: starting %d chaos monkey/-s", clientID, len(monkeys)), log.InfoLevel)
 
+	disruptions := &disruptionTracker{}
+
 	var wg sync.WaitGroup
 	for i := 0; i < len(monkeys); i++ {
 		wg.Add(1)
 		go func(i int) {
 			defer wg.Done()
 			m := monkeys[i]
-			// The only mode for accessing hazelcastwrapper members is currently through kubernetes, and as long as that's the
-			// case, we can safely hard-code the member chooser and member killer
-			// Member chooser and member killer share the same Kubernetes clientset
+			// Kubernetes member chooser, member killer, member lister, and cluster scaler share the same Kubernetes clientset
 			clientsetProvider := &defaultK8sClientsetProvider{
 				configBuilder:        &defaultK8sConfigBuilder{},
 				clientsetInitializer: &defaultK8sClientsetInitializer{},
 			}
 			namespaceDiscoverer := &defaultK8sNamespaceDiscoverer{}
+			k8sChooser := &k8sHzMemberChooser{
+				clientsetProvider:   clientsetProvider,
+				namespaceDiscoverer: namespaceDiscoverer,
+				podLister:           &defaultK8sPodLister{},
+				podSelector: &k8sPodSelector{clusterView: &defaultHzClusterView{
+					startClient: func(handler cluster.MembershipStateChangeHandler) {
+						hazelcastwrapper.NewHzClientHelper().AssembleWithMembershipListener(context.TODO(), "chaosMonkeyClusterView", hzCluster, hzMembers, handler)
+					},
+				}},
+			}
+			k8sKiller := &k8sHzMemberKiller{
+				clientsetProvider:   clientsetProvider,
+				namespaceDiscoverer: namespaceDiscoverer,
+				podDeleter:          &defaultK8sPodDeleter{},
+			}
+			// Which member access mode to use is only known once the monkey has populated its config, so all
+			// member choosers and member killers are provided, and the monkey's config determines which one is used
+			chooser := &accessModeHzMemberChooser{choosers: map[string]hzMemberChooser{
+				k8sOutOfClusterAccessMode: k8sChooser,
+				k8sInClusterAccessMode:    k8sChooser,
+				processAccessMode:         &processHzMemberChooser{processLister: &defaultProcessLister{procDir: "/proc"}},
+			}}
+			prober := &defaultClusterSafetyProber{httpClient: &http.Client{Timeout: 10 * time.Second}}
 			m.init(
 				&client.DefaultConfigPropertyAssigner{},
-				&defaultSleeper{},
-				&k8sHzMemberChooser{
-					clientsetProvider:   clientsetProvider,
-					namespaceDiscoverer: namespaceDiscoverer,
-					podLister:           &defaultK8sPodLister{},
-				},
-				&k8sHzMemberKiller{
-					clientsetProvider:   clientsetProvider,
-					namespaceDiscoverer: namespaceDiscoverer,
-					podDeleter:          &defaultK8sPodDeleter{},
+				monkeyDependencies{
+					s:       &defaultSleeper{},
+					chooser: chooser,
+					killer: &accessModeHzMemberKiller{killers: map[string]hzMemberKiller{
+						k8sOutOfClusterAccessMode: k8sKiller,
+						k8sInClusterAccessMode:    k8sKiller,
+						processAccessMode:         &processHzMemberKiller{processSignaler: &defaultProcessSignaler{}},
+					}},
+					// The member chooser knows how to list members, so it also counts the ready ones for the safety guard
+					guard: &preconditionSafetyGuard{
+						counter: chooser,
+						prober:  prober,
+					},
+					lister: &k8sHzMemberLister{
+						clientsetProvider:   clientsetProvider,
+						namespaceDiscoverer: namespaceDiscoverer,
+						podLister:           &defaultK8sPodLister{},
+					},
+					prober: prober,
+					scaler: &k8sHzClusterScaler{
+						clientsetProvider:   clientsetProvider,
+						namespaceDiscoverer: namespaceDiscoverer,
+						statefulSetLister:   &defaultK8sStatefulSetLister{},
+						statefulSetScaler:   &defaultK8sStatefulSetScaler{},
+					},
+					// Shared by all monkeys so the ones disrupting the cluster take turns
+					disruptions: disruptions,
 				},
 				status.NewGatherer(),
 				readyFunc,
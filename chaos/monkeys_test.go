@@ -6,6 +6,7 @@ import (
 	"hazeltest/status"
 	"strings"
 	"testing"
+	"time"
 )
 
 type (
@@ -25,6 +26,10 @@ type (
 	testSleeper struct {
 		secondsSlept int
 	}
+	testSafetyGuard struct {
+		unsafe         bool
+		numInvocations int
+	}
 )
 
 const (
@@ -82,6 +87,18 @@ func (c *testHzMemberChooser) choose(_ memberAccessConfig) (hzMember, error) {
 
 }
 
+func (g *testSafetyGuard) isSafeToKill(_ *safetyGuardConfig, _ memberAccessConfig, _ time.Time) (bool, string) {
+
+	g.numInvocations++
+
+	if g.unsafe {
+		return false, "the cluster is on fire"
+	}
+
+	return true, ""
+
+}
+
 func (a testConfigPropertyAssigner) Assign(keyPath string, eval func(string, any) error, assign func(any)) error {
 
 	if value, ok := a.testConfig[keyPath]; ok {
@@ -161,7 +178,7 @@ func TestMemberKillerMonkeyCauseChaos(t *testing.T) {
 			testNotReadyFunc := func() {
 				raiseNotReadyInvoked = true
 			}
-			m.init(assigner, &testSleeper{}, &testHzMemberChooser{}, &testHzMemberKiller{}, status.NewGatherer(), testReadyFunc, testNotReadyFunc)
+			m.init(assigner, monkeyDependencies{s: &testSleeper{}, chooser: &testHzMemberChooser{}, killer: &testHzMemberKiller{}, guard: &testSafetyGuard{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), testReadyFunc, testNotReadyFunc)
 
 			m.causeChaos()
 			waitForStatusGatheringDone(m.g)
@@ -207,7 +224,7 @@ func TestMemberKillerMonkeyCauseChaos(t *testing.T) {
 			testNotReadyFunc := func() {
 				raiseNotReadyInvoked = true
 			}
-			m.init(assigner, &testSleeper{}, &testHzMemberChooser{}, &testHzMemberKiller{}, status.NewGatherer(), testReadyFunc, testNotReadyFunc)
+			m.init(assigner, monkeyDependencies{s: &testSleeper{}, chooser: &testHzMemberChooser{}, killer: &testHzMemberKiller{}, guard: &testSafetyGuard{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), testReadyFunc, testNotReadyFunc)
 
 			m.causeChaos()
 			waitForStatusGatheringDone(m.g)
@@ -263,7 +280,7 @@ func TestMemberKillerMonkeyCauseChaos(t *testing.T) {
 			testNotReadyFunc := func() {
 				raiseNotReadyInvoked = true
 			}
-			m.init(assigner, &testSleeper{}, chooser, killer, status.NewGatherer(), testReadyFunc, testNotReadyFunc)
+			m.init(assigner, monkeyDependencies{s: &testSleeper{}, chooser: chooser, killer: killer, guard: &testSafetyGuard{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), testReadyFunc, testNotReadyFunc)
 
 			m.causeChaos()
 			waitForStatusGatheringDone(m.g)
@@ -331,7 +348,7 @@ func TestMemberKillerMonkeyCauseChaos(t *testing.T) {
 			killer := &testHzMemberKiller{}
 			m := memberKillerMonkey{}
 
-			m.init(assigner, &testSleeper{}, chooser, killer, status.NewGatherer(), noOpFunc, notReadyFunc)
+			m.init(assigner, monkeyDependencies{s: &testSleeper{}, chooser: chooser, killer: killer, guard: &testSafetyGuard{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, notReadyFunc)
 
 			m.causeChaos()
 			waitForStatusGatheringDone(m.g)
@@ -372,7 +389,7 @@ func TestMemberKillerMonkeyCauseChaos(t *testing.T) {
 			chooser := &testHzMemberChooser{returnError: true}
 			killer := &testHzMemberKiller{}
 			m := memberKillerMonkey{}
-			m.init(assigner, &testSleeper{}, chooser, killer, status.NewGatherer(), noOpFunc, noOpFunc)
+			m.init(assigner, monkeyDependencies{s: &testSleeper{}, chooser: chooser, killer: killer, guard: &testSafetyGuard{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
 
 			m.causeChaos()
 			waitForStatusGatheringDone(m.g)
@@ -414,7 +431,7 @@ func TestMemberKillerMonkeyCauseChaos(t *testing.T) {
 			chooser := &testHzMemberChooser{}
 			killer := &testHzMemberKiller{returnError: true}
 			m := memberKillerMonkey{}
-			m.init(assigner, &testSleeper{}, chooser, killer, status.NewGatherer(), noOpFunc, noOpFunc)
+			m.init(assigner, monkeyDependencies{s: &testSleeper{}, chooser: chooser, killer: killer, guard: &testSafetyGuard{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
 
 			m.causeChaos()
 			waitForStatusGatheringDone(m.g)
@@ -433,6 +450,147 @@ func TestMemberKillerMonkeyCauseChaos(t *testing.T) {
 				t.Fatal(msg, ballotX, key, detail)
 			}
 		}
+		t.Log("\twhen safety guard deems it unsafe to kill")
+		{
+			numRuns := 5
+			assigner := &testConfigPropertyAssigner{
+				assembleTestConfig(
+					memberKillerKeyPath,
+					true,
+					1.0,
+					numRuns,
+					k8sInClusterAccessMode,
+					validLabelSelector,
+					sleepDisabled,
+				)}
+			chooser := &testHzMemberChooser{}
+			killer := &testHzMemberKiller{}
+			guard := &testSafetyGuard{unsafe: true}
+			m := memberKillerMonkey{}
+			m.init(assigner, monkeyDependencies{s: &testSleeper{}, chooser: chooser, killer: killer, guard: guard, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			if detail, ok := checkMonkeyStateTransitions(completeRunStateList, m.stateList); ok {
+				t.Log(genericMsg, checkMark)
+			} else {
+				t.Fatal(genericMsg, ballotX, detail)
+			}
+
+			msg := "\t\tsafety guard must have been consulted in each run"
+			if guard.numInvocations == numRuns {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, fmt.Sprintf("%d != %d", guard.numInvocations, numRuns))
+			}
+
+			msg = "\t\tneither chooser nor killer must have been invoked"
+			if chooser.numInvocations == 0 && killer.numInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tmonkey status must contain expected values"
+			if ok, key, detail := statusContainsExpectedValues(m.g.AssembleStatusCopy(), numRuns, 0, true); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+
+			msg = "\t\tskipped kills must have been counted in status"
+			if v := m.g.AssembleStatusCopy()[statusKeyNumKillsSkippedUnsafe]; v == uint32(numRuns) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, v)
+			}
+		}
+		t.Log("\twhen another monkey's disruption is in progress")
+		{
+			numRuns := 3
+			assigner := &testConfigPropertyAssigner{
+				assembleTestConfig(
+					memberKillerKeyPath,
+					true,
+					1.0,
+					numRuns,
+					k8sInClusterAccessMode,
+					validLabelSelector,
+					sleepDisabled,
+				)}
+			killer := &testHzMemberKiller{}
+			guard := &testSafetyGuard{}
+			disruptions := &disruptionTracker{}
+			disruptions.begin("rollingRestart")
+			m := memberKillerMonkey{}
+			m.init(assigner, monkeyDependencies{s: &testSleeper{}, chooser: &testHzMemberChooser{}, killer: killer, guard: guard, disruptions: disruptions}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tneither safety guard nor killer must have been invoked"
+			if guard.numInvocations == 0 && killer.numInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tskipped kills must have been counted in status"
+			if v := m.g.AssembleStatusCopy()[statusKeyNumKillsSkippedUnsafe]; v == uint32(numRuns) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, v)
+			}
+
+			msg = "\t\tother monkey's disruption must still be in progress"
+			if ok, other := disruptions.begin("memberKiller"); !ok && other == "rollingRestart" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, other)
+			}
+		}
+		t.Log("\twhen safety guard deems it safe to kill")
+		{
+			numRuns := 3
+			assigner := &testConfigPropertyAssigner{
+				assembleTestConfig(
+					memberKillerKeyPath,
+					true,
+					1.0,
+					numRuns,
+					k8sInClusterAccessMode,
+					validLabelSelector,
+					sleepDisabled,
+				)}
+			killer := &testHzMemberKiller{}
+			m := memberKillerMonkey{}
+			m.init(assigner, monkeyDependencies{s: &testSleeper{}, chooser: &testHzMemberChooser{}, killer: killer, guard: &testSafetyGuard{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tkiller must have been invoked in each run"
+			if killer.numInvocations == numRuns {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\ttime of last kill must have been recorded"
+			if !m.disruptions.last().IsZero() {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tno skipped kills must have been counted in status"
+			if v := m.g.AssembleStatusCopy()[statusKeyNumKillsSkippedUnsafe]; v == uint32(0) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, v)
+			}
+		}
 		t.Log("\twhen sleep has been disabled")
 		{
 			assigner := &testConfigPropertyAssigner{
@@ -449,7 +607,7 @@ func TestMemberKillerMonkeyCauseChaos(t *testing.T) {
 			chooser := &testHzMemberChooser{}
 			killer := &testHzMemberKiller{returnError: true}
 			m := memberKillerMonkey{}
-			m.init(assigner, s, chooser, killer, status.NewGatherer(), noOpFunc, noOpFunc)
+			m.init(assigner, monkeyDependencies{s: s, chooser: chooser, killer: killer, guard: &testSafetyGuard{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
 
 			m.causeChaos()
 			waitForStatusGatheringDone(m.g)
@@ -483,7 +641,7 @@ func TestMemberKillerMonkeyCauseChaos(t *testing.T) {
 			chooser := &testHzMemberChooser{}
 			killer := &testHzMemberKiller{returnError: true}
 			m := memberKillerMonkey{}
-			m.init(assigner, s, chooser, killer, status.NewGatherer(), noOpFunc, noOpFunc)
+			m.init(assigner, monkeyDependencies{s: s, chooser: chooser, killer: killer, guard: &testSafetyGuard{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
 
 			m.causeChaos()
 
@@ -567,6 +725,66 @@ func TestPopulateConfig(t *testing.T) {
 			}
 		}
 
+		t.Log("\twhen process access mode is given and no property assignment yields an error")
+		{
+			for _, discovery := range []string{pidFileDiscovery, processNameDiscovery} {
+				t.Logf("\t\t%s", discovery)
+				{
+					testConfig := assembleTestConfig(testMonkeyKeyPath, true, validChaosProbability, 10, processAccessMode, validLabelSelector, sleepDisabled)
+					testConfig[testMonkeyKeyPath+".memberAccess.process.discovery"] = discovery
+					assigner := testConfigPropertyAssigner{testConfig}
+					mc, err := b.populateConfig(assigner)
+
+					msg := "\t\t\tno errors should be returned"
+					if err == nil {
+						t.Log(msg, checkMark)
+					} else {
+						t.Fatal(msg, ballotX, err)
+					}
+
+					msg = "\t\t\tconfig should contain correct values"
+					if mc != nil && configValuesAsExpected(mc, testConfig) {
+						t.Log(msg, checkMark)
+					} else {
+						t.Fatal(msg, ballotX)
+					}
+				}
+			}
+		}
+
+		t.Log("\twhen process access mode is given with invalid discovery configuration")
+		{
+			for description, override := range map[string]map[string]any{
+				"unknown discovery mode":      {testMonkeyKeyPath + ".memberAccess.process.discovery": "crystalBall"},
+				"invalid process name regex":  {testMonkeyKeyPath + ".memberAccess.process.processName.pattern": "Hazelcast(Member"},
+				"empty pid file glob pattern": {testMonkeyKeyPath + ".memberAccess.process.discovery": pidFileDiscovery, testMonkeyKeyPath + ".memberAccess.process.pidFile.pattern": ""},
+			} {
+				t.Logf("\t\t%s", description)
+				{
+					testConfig := assembleTestConfig(testMonkeyKeyPath, true, validChaosProbability, 10, processAccessMode, validLabelSelector, sleepDisabled)
+					for k, v := range override {
+						testConfig[k] = v
+					}
+					assigner := testConfigPropertyAssigner{testConfig}
+					mc, err := b.populateConfig(assigner)
+
+					msg := "\t\t\terror should be returned"
+					if err != nil {
+						t.Log(msg, checkMark)
+					} else {
+						t.Fatal(msg, ballotX)
+					}
+
+					msg = "\t\t\tconfig should be nil"
+					if mc == nil {
+						t.Log(msg, checkMark)
+					} else {
+						t.Fatal(msg, ballotX)
+					}
+				}
+			}
+		}
+
 		t.Log("\twhen top-level property assignment yields an error")
 		{
 			testConfig := assembleTestConfig(testMonkeyKeyPath, true, invalidChaosProbability, 10, k8sInClusterAccessMode, validLabelSelector, sleepDisabled)
@@ -614,6 +832,109 @@ func TestPopulateConfig(t *testing.T) {
 			}
 		}
 
+		t.Log("\twhen fixed list selection strategy is given")
+		{
+			testConfig := assembleTestConfig(testMonkeyKeyPath, true, validChaosProbability, 10, k8sInClusterAccessMode, validLabelSelector, sleepDisabled)
+			testConfig[testMonkeyKeyPath+".memberAccess.selection.strategy"] = fixedListSelection
+			assigner := testConfigPropertyAssigner{testConfig}
+			mc, err := b.populateConfig(assigner)
+
+			msg := "\t\tno errors should be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tconfig should contain correct values"
+			if configValuesAsExpected(mc, testConfig) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tconfig should contain pod names of fixed list"
+			podNames := mc.accessConfig.selection.podNames
+			if len(podNames) == 2 && podNames[0] == "hazelcastplatform-0" && podNames[1] == "hazelcastplatform-2" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, podNames)
+			}
+		}
+
+		t.Log("\twhen fixed list selection strategy is given without pod names")
+		{
+			testConfig := assembleTestConfig(testMonkeyKeyPath, true, validChaosProbability, 10, k8sInClusterAccessMode, validLabelSelector, sleepDisabled)
+			testConfig[testMonkeyKeyPath+".memberAccess.selection.strategy"] = fixedListSelection
+			testConfig[testMonkeyKeyPath+".memberAccess.selection.fixedList.podNames"] = []any{}
+			assigner := testConfigPropertyAssigner{testConfig}
+			mc, err := b.populateConfig(assigner)
+
+			msg := "\t\terror should be returned, and config should be nil"
+			if err != nil && mc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen unknown selection strategy is given")
+		{
+			testConfig := assembleTestConfig(testMonkeyKeyPath, true, validChaosProbability, 10, k8sInClusterAccessMode, validLabelSelector, sleepDisabled)
+			testConfig[testMonkeyKeyPath+".memberAccess.selection.strategy"] = "mostHated"
+			assigner := testConfigPropertyAssigner{testConfig}
+			mc, err := b.populateConfig(assigner)
+
+			msg := "\t\terror should be returned, and config should be nil"
+			if err != nil && mc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen process access mode is given with selection strategy relying on pods")
+		{
+			for _, strategy := range []string{oldestPodSelection, youngestPodSelection, masterSelection, fixedListSelection} {
+				t.Logf("\t\t%s", strategy)
+				{
+					testConfig := assembleTestConfig(testMonkeyKeyPath, true, validChaosProbability, 10, processAccessMode, validLabelSelector, sleepDisabled)
+					testConfig[testMonkeyKeyPath+".memberAccess.selection.strategy"] = strategy
+					assigner := testConfigPropertyAssigner{testConfig}
+					mc, err := b.populateConfig(assigner)
+
+					msg := "\t\t\terror should be returned, and config should be nil"
+					if err != nil && mc == nil {
+						t.Log(msg, checkMark)
+					} else {
+						t.Fatal(msg, ballotX)
+					}
+				}
+			}
+		}
+
+		t.Log("\twhen safety guard property assignment yields an error")
+		{
+			testConfig := assembleTestConfig(testMonkeyKeyPath, true, validChaosProbability, 10, k8sInClusterAccessMode, validLabelSelector, sleepDisabled)
+			testConfig[testMonkeyKeyPath+".safetyGuard.minReadyMembers.count"] = 0
+			assigner := testConfigPropertyAssigner{testConfig}
+			mc, err := b.populateConfig(assigner)
+
+			msg := "\t\terror should be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tconfig should be nil"
+			if mc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
 		t.Log("\twhen unknown k8s hazelcast member access mode is given")
 		{
 			unknownAccessMode := "someUnknownAccessMode"
@@ -694,21 +1015,33 @@ func statusContainsExpectedValues(status map[string]any, expectedNumRuns, expect
 func assembleTestConfig(keyPath string, enabled bool, chaosProbability float64, numRuns int, memberAccessMode, labelSelector string, sleep *sleepConfig) map[string]any {
 
 	return map[string]any{
-		keyPath + ".enabled":                                    enabled,
-		keyPath + ".numRuns":                                    numRuns,
-		keyPath + ".chaosProbability":                           chaosProbability,
-		keyPath + ".memberAccess.mode":                          memberAccessMode,
-		keyPath + ".memberAccess.targetOnlyActive":              true,
-		keyPath + ".memberAccess.k8sOutOfCluster.kubeconfig":    "default",
-		keyPath + ".memberAccess.k8sOutOfCluster.namespace":     "hazelcastplatform",
-		keyPath + ".memberAccess.k8sOutOfCluster.labelSelector": labelSelector,
-		keyPath + ".memberAccess.k8sInCluster.labelSelector":    labelSelector,
-		keyPath + ".sleep.enabled":                              sleep.enabled,
-		keyPath + ".sleep.durationSeconds":                      sleep.durationSeconds,
-		keyPath + ".sleep.enableRandomness":                     sleep.enableRandomness,
-		keyPath + ".memberGrace.enabled":                        true,
-		keyPath + ".memberGrace.durationSeconds":                30,
-		keyPath + ".memberGrace.enableRandomness":               true,
+		keyPath + ".enabled":                                          enabled,
+		keyPath + ".numRuns":                                          numRuns,
+		keyPath + ".chaosProbability":                                 chaosProbability,
+		keyPath + ".memberAccess.mode":                                memberAccessMode,
+		keyPath + ".memberAccess.targetOnlyActive":                    true,
+		keyPath + ".memberAccess.selection.strategy":                  randomSelection,
+		keyPath + ".memberAccess.selection.fixedList.podNames":        []any{"hazelcastplatform-0", "hazelcastplatform-2"},
+		keyPath + ".memberAccess.k8sOutOfCluster.kubeconfig":          "default",
+		keyPath + ".memberAccess.k8sOutOfCluster.namespace":           "hazelcastplatform",
+		keyPath + ".memberAccess.k8sOutOfCluster.labelSelector":       labelSelector,
+		keyPath + ".memberAccess.k8sInCluster.labelSelector":          labelSelector,
+		keyPath + ".memberAccess.process.discovery":                   processNameDiscovery,
+		keyPath + ".memberAccess.process.pidFile.pattern":             "/var/run/hazelcast/*.pid",
+		keyPath + ".memberAccess.process.processName.pattern":         "HazelcastMemberStarter",
+		keyPath + ".sleep.enabled":                                    sleep.enabled,
+		keyPath + ".sleep.durationSeconds":                            sleep.durationSeconds,
+		keyPath + ".sleep.enableRandomness":                           sleep.enableRandomness,
+		keyPath + ".memberGrace.enabled":                              true,
+		keyPath + ".memberGrace.durationSeconds":                      30,
+		keyPath + ".memberGrace.enableRandomness":                     true,
+		keyPath + ".safetyGuard.enabled":                              true,
+		keyPath + ".safetyGuard.minReadyMembers.enabled":              true,
+		keyPath + ".safetyGuard.minReadyMembers.count":                3,
+		keyPath + ".safetyGuard.minTimeSinceLastKill.enabled":         true,
+		keyPath + ".safetyGuard.minTimeSinceLastKill.durationSeconds": 120,
+		keyPath + ".safetyGuard.clusterSafe.enabled":                  false,
+		keyPath + ".safetyGuard.clusterSafe.healthCheckUrl":           "http://hazelcastplatform:5701/hazelcast/health",
 	}
 
 }
@@ -720,12 +1053,20 @@ func configValuesAsExpected(mc *monkeyConfig, expected map[string]any) bool {
 		mc.chaosProbability == expected[testMonkeyKeyPath+".chaosProbability"] &&
 		mc.accessConfig.memberAccessMode == expected[testMonkeyKeyPath+".memberAccess.mode"] &&
 		mc.accessConfig.targetOnlyActive == expected[testMonkeyKeyPath+".memberAccess.targetOnlyActive"] &&
+		mc.accessConfig.selection.strategy == expected[testMonkeyKeyPath+".memberAccess.selection.strategy"] &&
 		mc.sleep.enabled == expected[testMonkeyKeyPath+".sleep.enabled"] &&
 		mc.sleep.durationSeconds == expected[testMonkeyKeyPath+".sleep.durationSeconds"] &&
 		mc.sleep.enableRandomness == expected[testMonkeyKeyPath+".sleep.enableRandomness"] &&
 		mc.memberGrace.enabled == expected[testMonkeyKeyPath+".memberGrace.enabled"] &&
 		mc.memberGrace.durationSeconds == expected[testMonkeyKeyPath+".memberGrace.durationSeconds"] &&
-		mc.memberGrace.enableRandomness == expected[testMonkeyKeyPath+".memberGrace.enableRandomness"]
+		mc.memberGrace.enableRandomness == expected[testMonkeyKeyPath+".memberGrace.enableRandomness"] &&
+		mc.safetyGuard.enabled == expected[testMonkeyKeyPath+".safetyGuard.enabled"] &&
+		mc.safetyGuard.minReadyMembers.enabled == expected[testMonkeyKeyPath+".safetyGuard.minReadyMembers.enabled"] &&
+		mc.safetyGuard.minReadyMembers.count == expected[testMonkeyKeyPath+".safetyGuard.minReadyMembers.count"] &&
+		mc.safetyGuard.minTimeSinceLastKill.enabled == expected[testMonkeyKeyPath+".safetyGuard.minTimeSinceLastKill.enabled"] &&
+		mc.safetyGuard.minTimeSinceLastKill.durationSeconds == expected[testMonkeyKeyPath+".safetyGuard.minTimeSinceLastKill.durationSeconds"] &&
+		mc.safetyGuard.clusterSafe.enabled == expected[testMonkeyKeyPath+".safetyGuard.clusterSafe.enabled"] &&
+		mc.safetyGuard.clusterSafe.healthCheckUrl == expected[testMonkeyKeyPath+".safetyGuard.clusterSafe.healthCheckUrl"]
 
 	var accessModeAsExpected bool
 	if allButAccessModeAsExpected && mc.accessConfig.memberAccessMode == k8sOutOfClusterAccessMode {
@@ -734,6 +1075,13 @@ func configValuesAsExpected(mc *monkeyConfig, expected map[string]any) bool {
 			mc.accessConfig.k8sOutOfCluster.labelSelector == expected[testMonkeyKeyPath+".memberAccess.k8sOutOfCluster.labelSelector"]
 	} else if allButAccessModeAsExpected && mc.accessConfig.memberAccessMode == k8sInClusterAccessMode {
 		accessModeAsExpected = mc.accessConfig.k8sInCluster.labelSelector == expected[testMonkeyKeyPath+".memberAccess.k8sInCluster.labelSelector"]
+	} else if allButAccessModeAsExpected && mc.accessConfig.memberAccessMode == processAccessMode {
+		accessModeAsExpected = mc.accessConfig.process.discovery == expected[testMonkeyKeyPath+".memberAccess.process.discovery"]
+		if mc.accessConfig.process.discovery == pidFileDiscovery {
+			accessModeAsExpected = accessModeAsExpected && mc.accessConfig.process.pidFilePattern == expected[testMonkeyKeyPath+".memberAccess.process.pidFile.pattern"]
+		} else {
+			accessModeAsExpected = accessModeAsExpected && mc.accessConfig.process.processNamePattern == expected[testMonkeyKeyPath+".memberAccess.process.processName.pattern"]
+		}
 	} else {
 		return false
 	}
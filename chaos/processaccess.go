@@ -0,0 +1,409 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type (
+	processLister interface {
+		list(ac memberAccessConfig) ([]hzProcess, error)
+	}
+	processSignaler interface {
+		signal(pid int, sig syscall.Signal) error
+	}
+	processMemberAccess struct {
+		discovery          string
+		pidFilePattern     string
+		processNamePattern string
+	}
+	hzProcess struct {
+		pid    int
+		active bool
+	}
+	// defaultProcessLister discovers Hazelcast member processes either by reading the PIDs from the PID files matching
+	// the configured glob pattern, or by matching the command lines of all running processes against the configured
+	// regular expression. Processes are looked up in procDir, or, on systems without a proc file system (such as
+	// macOS), by means of ps.
+	defaultProcessLister struct {
+		procDir string
+	}
+	defaultProcessSignaler struct{}
+	processHzMemberChooser struct {
+		processLister processLister
+		lastChosenPid int
+	}
+	processHzMemberKiller struct {
+		processSignaler processSignaler
+	}
+)
+
+const (
+	pidFileDiscovery     = "pidFile"
+	processNameDiscovery = "processName"
+)
+
+var (
+	// Interval in which the member killer checks whether a member process has terminated after having been asked to
+	// do so -- variable rather than constant so tests don't have to wait for it
+	processTerminationPollInterval = 500 * time.Millisecond
+	// Runs ps with the given arguments on systems without a proc file system -- variable so tests can provide the
+	// output of ps without having to run it
+	runPs = func(args ...string) ([]byte, error) {
+		return exec.Command("ps", args...).Output()
+	}
+	psLinePattern = regexp.MustCompile(`^\s*(\d+)\s+(.*)$`)
+)
+
+func (l *defaultProcessLister) list(ac memberAccessConfig) ([]hzProcess, error) {
+
+	var pids []int
+	var err error
+
+	switch ac.process.discovery {
+	case pidFileDiscovery:
+		pids, err = l.readPidFiles(ac.process.pidFilePattern)
+	case processNameDiscovery:
+		pids, err = l.matchProcessNames(ac.process.processNamePattern)
+	default:
+		err = fmt.Errorf("encountered unknown process discovery mode: %s", ac.process.discovery)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	processes := make([]hzProcess, 0, len(pids))
+	for _, pid := range pids {
+		if pid == os.Getpid() {
+			continue
+		}
+		processes = append(processes, hzProcess{pid: pid, active: l.isActive(pid)})
+	}
+
+	return processes, nil
+
+}
+
+func (l *defaultProcessLister) readPidFiles(pattern string) ([]int, error) {
+
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to read pid file '%s' -- skipping: %s", f, err.Error()), log.WarnLevel)
+			continue
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil || pid <= 0 {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("pid file '%s' does not contain valid pid -- skipping", f), log.WarnLevel)
+			continue
+		}
+		// Pid files of members having terminated in the meantime might still be around
+		if !l.exists(pid) {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("process with pid %d given in pid file '%s' does not exist -- skipping", pid, f), log.TraceLevel)
+			continue
+		}
+		pids = append(pids, pid)
+	}
+
+	return pids, nil
+
+}
+
+func (l *defaultProcessLister) matchProcessNames(pattern string) ([]int, error) {
+
+	r, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if !l.hasProcDir() {
+		return matchProcessNamesWithPs(r, pattern)
+	}
+
+	entries, err := os.ReadDir(l.procDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		// Process might have terminated since directory was listed, so errors are expected here
+		data, err := os.ReadFile(filepath.Join(l.procDir, e.Name(), "cmdline"))
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		cmdline := strings.TrimSpace(strings.ReplaceAll(string(data), "\x00", " "))
+		if r.MatchString(cmdline) {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("command line of process with pid %d matches pattern '%s'", pid, pattern), log.TraceLevel)
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids, nil
+
+}
+
+func matchProcessNamesWithPs(r *regexp.Regexp, pattern string) ([]int, error) {
+
+	out, err := runPs("-axo", "pid=,command=")
+	if err != nil {
+		return nil, fmt.Errorf("no proc file system available, and unable to list processes using ps: %w", err)
+	}
+
+	var pids []int
+	for _, line := range strings.Split(string(out), "\n") {
+		m := psLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		pid, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		if r.MatchString(strings.TrimSpace(m[2])) {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("command line of process with pid %d matches pattern '%s'", pid, pattern), log.TraceLevel)
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids, nil
+
+}
+
+func (l *defaultProcessLister) hasProcDir() bool {
+
+	_, err := os.Stat(l.procDir)
+	return err == nil
+
+}
+
+func (l *defaultProcessLister) exists(pid int) bool {
+
+	if l.hasProcDir() {
+		_, err := os.Stat(filepath.Join(l.procDir, strconv.Itoa(pid)))
+		return err == nil
+	}
+
+	// No proc file system to ask, so ask the process itself
+	err := (&defaultProcessSignaler{}).signal(pid, syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+
+}
+
+// isActive considers a process active unless the proc file system -- or ps, in its absence -- reports it as stopped
+// or as a zombie, which is the closest a process gets to the readiness of a Kubernetes Pod.
+func (l *defaultProcessLister) isActive(pid int) bool {
+
+	if !l.hasProcDir() {
+		out, err := runPs("-o", "stat=", "-p", strconv.Itoa(pid))
+		state := strings.TrimSpace(string(out))
+		if err != nil || state == "" {
+			return true
+		}
+		return isActiveState(state[0])
+	}
+
+	data, err := os.ReadFile(filepath.Join(l.procDir, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return true
+	}
+
+	// Format is '<pid> (<comm>) <state> ...', and comm may contain spaces and parentheses itself
+	s := string(data)
+	i := strings.LastIndex(s, ")")
+	if i < 0 || i+2 >= len(s) {
+		return true
+	}
+
+	return isActiveState(s[i+2])
+
+}
+
+// isActiveState evaluates the first letter of a process state, which the proc file system and ps share for stopped
+// and zombie processes.
+func isActiveState(state byte) bool {
+
+	switch state {
+	case 'Z', 'T', 't', 'X':
+		return false
+	default:
+		return true
+	}
+
+}
+
+func (s *defaultProcessSignaler) signal(pid int, sig syscall.Signal) error {
+
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	return p.Signal(sig)
+
+}
+
+func (chooser *processHzMemberChooser) choose(ac memberAccessConfig) (hzMember, error) {
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("choosing hazelcast member process using discovery mode '%s'", ac.process.discovery), log.InfoLevel)
+
+	processes, err := chooser.processLister.list(ac)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to choose hazelcast member: could not list processes: %s", err.Error()), log.ErrorLevel)
+		return hzMember{}, err
+	}
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("found %d candidate process/-es", len(processes)), log.TraceLevel)
+
+	if len(processes) == 0 {
+		lp.LogChaosMonkeyEvent("no hazelcast member processes found", log.WarnLevel)
+		return hzMember{}, noMemberFoundError
+	}
+
+	candidates := processes
+	if ac.targetOnlyActive {
+		lp.LogChaosMonkeyEvent("targetOnlyActive enabled -- only considering active hazelcast member processes", log.TraceLevel)
+		candidates = make([]hzProcess, 0, len(processes))
+		for _, p := range processes {
+			if p.active {
+				candidates = append(candidates, p)
+			}
+		}
+		if len(candidates) == 0 {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("out of %d candidate process/-es, none was active (can only target active processes because targetOnlyActive was enabled)", len(processes)), log.WarnLevel)
+			return hzMember{}, noMemberFoundError
+		}
+	}
+
+	var processToKill hzProcess
+	switch ac.selection.strategy {
+	case "", randomSelection:
+		processToKill = candidates[rand.Intn(len(candidates))]
+	case roundRobinSelection:
+		processToKill = chooser.selectNextProcessInRound(candidates)
+		chooser.lastChosenPid = processToKill.pid
+	default:
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to choose hazelcast member: selection strategy '%s' not supported for hazelcast member processes", ac.selection.strategy), log.ErrorLevel)
+		return hzMember{}, fmt.Errorf("member selection strategy not supported for member access mode '%s': %s", processAccessMode, ac.selection.strategy)
+	}
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("successfully chose hazelcast member process with pid %d", processToKill.pid), log.InfoLevel)
+	return hzMember{strconv.Itoa(processToKill.pid)}, nil
+
+}
+
+func (chooser *processHzMemberChooser) countReady(ac memberAccessConfig) (int, error) {
+
+	processes, err := chooser.processLister.list(ac)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to count ready hazelcast members: could not list processes: %s", err.Error()), log.ErrorLevel)
+		return 0, err
+	}
+
+	numReady := 0
+	for _, p := range processes {
+		if p.active {
+			numReady++
+		}
+	}
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("found %d active out of %d hazelcast member process/-es", numReady, len(processes)), log.TraceLevel)
+	return numReady, nil
+
+}
+
+func (chooser *processHzMemberChooser) selectNextProcessInRound(candidates []hzProcess) hzProcess {
+
+	sorted := make([]hzProcess, len(candidates))
+	copy(sorted, candidates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].pid < sorted[j].pid
+	})
+
+	for _, p := range sorted {
+		if p.pid > chooser.lastChosenPid {
+			return p
+		}
+	}
+
+	lp.LogChaosMonkeyEvent("round of processes complete -- starting over", log.TraceLevel)
+	return sorted[0]
+
+}
+
+// kill asks the member process to shut down by sending SIGTERM, and resorts to SIGKILL if the process is still
+// around once the grace period has elapsed. Without a grace period, the process is killed right away.
+func (killer *processHzMemberKiller) kill(m hzMember, _ memberAccessConfig, memberGrace sleepConfig) error {
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("killing hazelcast member process '%s'", m.identifier), log.InfoLevel)
+
+	pid, err := strconv.Atoi(m.identifier)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to kill hazelcast member: identifier '%s' is not a pid", m.identifier), log.ErrorLevel)
+		return err
+	}
+
+	gracePeriod := evaluateGracePeriodSeconds(memberGrace)
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("using grace period seconds '%d' to kill hazelcast member process '%s'", gracePeriod, m.identifier), log.TraceLevel)
+
+	if gracePeriod > 0 {
+		if err := killer.processSignaler.signal(pid, syscall.SIGTERM); err != nil {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("sending SIGTERM to hazelcast member process '%s' unsuccessful: %s", m.identifier, err.Error()), log.ErrorLevel)
+			return err
+		}
+		deadline := time.Now().Add(time.Duration(gracePeriod) * time.Second)
+		for time.Now().Before(deadline) {
+			if !killer.isAlive(pid) {
+				lp.LogChaosMonkeyEvent(fmt.Sprintf("successfully killed hazelcast member process '%s' within %d seconds of grace period", m.identifier, gracePeriod), log.InfoLevel)
+				return nil
+			}
+			time.Sleep(processTerminationPollInterval)
+		}
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("hazelcast member process '%s' still alive after %d seconds of grace period -- sending SIGKILL", m.identifier, gracePeriod), log.WarnLevel)
+	}
+
+	if err := killer.processSignaler.signal(pid, syscall.SIGKILL); err != nil {
+		// Process might have terminated between the last check and the signal
+		if !killer.isAlive(pid) {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("hazelcast member process '%s' terminated before SIGKILL could be sent", m.identifier), log.InfoLevel)
+			return nil
+		}
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("killing hazelcast member process '%s' unsuccessful: %s", m.identifier, err.Error()), log.ErrorLevel)
+		return err
+	}
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("successfully killed hazelcast member process '%s'", m.identifier), log.InfoLevel)
+	return nil
+
+}
+
+func (killer *processHzMemberKiller) isAlive(pid int) bool {
+
+	err := killer.processSignaler.signal(pid, syscall.Signal(0))
+
+	// Permission errors mean the process exists, but belongs to someone else
+	return err == nil || errors.Is(err, syscall.EPERM)
+
+}
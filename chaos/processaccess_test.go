@@ -0,0 +1,440 @@
+package chaos
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type (
+	testProcessLister struct {
+		processesToReturn []hzProcess
+		returnError       bool
+		numInvocations    int
+	}
+	testProcessSignaler struct {
+		// Whether the process terminates upon receiving SIGTERM -- it always does upon receiving SIGKILL
+		terminateUponSigterm bool
+		returnError          bool
+		terminated           bool
+		signalsSent          []syscall.Signal
+	}
+)
+
+var (
+	processListError   = errors.New("the process list has gone the way of the dodo")
+	processSignalError = errors.New("your signal has been lost in the void")
+)
+
+func (l *testProcessLister) list(_ memberAccessConfig) ([]hzProcess, error) {
+
+	l.numInvocations++
+
+	if l.returnError {
+		return nil, processListError
+	}
+
+	return l.processesToReturn, nil
+
+}
+
+func (s *testProcessSignaler) signal(_ int, sig syscall.Signal) error {
+
+	if sig == syscall.Signal(0) {
+		if s.terminated {
+			return syscall.ESRCH
+		}
+		return nil
+	}
+
+	s.signalsSent = append(s.signalsSent, sig)
+
+	if s.returnError {
+		return processSignalError
+	}
+
+	if sig == syscall.SIGKILL || (sig == syscall.SIGTERM && s.terminateUponSigterm) {
+		s.terminated = true
+	}
+
+	return nil
+
+}
+
+func TestDefaultProcessListerList(t *testing.T) {
+
+	t.Log("given a process lister discovering hazelcast member processes")
+	{
+		procDir := t.TempDir()
+		writeFakeProcess(t, procDir, 4711, "java -cp hazelcast.jar com.hazelcast.core.server.HazelcastMemberStarter", "S")
+		writeFakeProcess(t, procDir, 4712, "java -cp hazelcast.jar com.hazelcast.core.server.HazelcastMemberStarter", "T")
+		writeFakeProcess(t, procDir, 4713, "/usr/bin/some-other-process --verbose", "S")
+		l := &defaultProcessLister{procDir: procDir}
+
+		t.Log("\twhen process name discovery is used")
+		{
+			ac := memberAccessConfig{process: processMemberAccess{discovery: processNameDiscovery, processNamePattern: `HazelcastMemberStarter`}}
+			processes, err := l.list(ac)
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tonly processes matching pattern must be returned, with stopped processes reported as inactive"
+			if processesAsExpected(processes, map[int]bool{4711: true, 4712: false}) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, processes)
+			}
+		}
+
+		t.Log("\twhen pid file discovery is used")
+		{
+			pidDir := t.TempDir()
+			writeFile(t, filepath.Join(pidDir, "member-1.pid"), "4711\n")
+			writeFile(t, filepath.Join(pidDir, "member-2.pid"), "4713")
+			writeFile(t, filepath.Join(pidDir, "member-3.pid"), "no pid in here")
+			// Pid file of member having terminated in the meantime
+			writeFile(t, filepath.Join(pidDir, "member-4.pid"), "4714")
+			writeFile(t, filepath.Join(pidDir, "unrelated.txt"), "4712")
+
+			ac := memberAccessConfig{process: processMemberAccess{discovery: pidFileDiscovery, pidFilePattern: filepath.Join(pidDir, "*.pid")}}
+			processes, err := l.list(ac)
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tonly existing processes given in pid files matching pattern must be returned"
+			if processesAsExpected(processes, map[int]bool{4711: true, 4713: true}) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, processes)
+			}
+		}
+
+		t.Log("\twhen unknown discovery mode is given")
+		{
+			processes, err := l.list(memberAccessConfig{process: processMemberAccess{discovery: "crystalBall"}})
+
+			msg := "\t\terror must be returned"
+			if err != nil && processes == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, processes)
+			}
+		}
+	}
+
+}
+
+func TestChooseMemberProcess(t *testing.T) {
+
+	t.Log("given a member chooser for hazelcast members running as processes")
+	{
+		t.Log("\twhen process lister returns error")
+		{
+			chooser := &processHzMemberChooser{processLister: &testProcessLister{returnError: true}}
+			_, err := chooser.choose(memberAccessConfig{})
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, processListError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen no processes have been found")
+		{
+			chooser := &processHzMemberChooser{processLister: &testProcessLister{}}
+			_, err := chooser.choose(memberAccessConfig{})
+
+			msg := "\t\tno member found error must be returned"
+			if errors.Is(err, noMemberFoundError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen only inactive processes have been found and only active ones may be targeted")
+		{
+			chooser := &processHzMemberChooser{processLister: &testProcessLister{processesToReturn: []hzProcess{{4711, false}, {4712, false}}}}
+			_, err := chooser.choose(memberAccessConfig{targetOnlyActive: true})
+
+			msg := "\t\tno member found error must be returned"
+			if errors.Is(err, noMemberFoundError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen both active and inactive processes have been found and only active ones may be targeted")
+		{
+			chooser := &processHzMemberChooser{processLister: &testProcessLister{processesToReturn: []hzProcess{{4711, false}, {4712, true}}}}
+
+			for i := 0; i < 10; i++ {
+				member, err := chooser.choose(memberAccessConfig{targetOnlyActive: true})
+				if err != nil || member.identifier != "4712" {
+					t.Fatal("\t\tactive process must be chosen", ballotX, member, err)
+				}
+			}
+			t.Log("\t\tactive process must be chosen", checkMark)
+		}
+
+		t.Log("\twhen inactive processes may be targeted, too")
+		{
+			chooser := &processHzMemberChooser{processLister: &testProcessLister{processesToReturn: []hzProcess{{4711, false}}}}
+			member, err := chooser.choose(memberAccessConfig{targetOnlyActive: false})
+
+			msg := "\t\tinactive process must be chosen, with pid as identifier"
+			if err == nil && member.identifier == "4711" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, member, err)
+			}
+		}
+	}
+
+}
+
+func TestKillMemberProcess(t *testing.T) {
+
+	defer func(interval time.Duration) {
+		processTerminationPollInterval = interval
+	}(processTerminationPollInterval)
+	processTerminationPollInterval = 10 * time.Millisecond
+
+	t.Log("given a member killer for hazelcast members running as processes")
+	{
+		member := hzMember{"4711"}
+
+		t.Log("\twhen member grace is disabled")
+		{
+			s := &testProcessSignaler{}
+			killer := &processHzMemberKiller{processSignaler: s}
+			err := killer.kill(member, memberAccessConfig{}, sleepConfig{enabled: false})
+
+			msg := "\t\tprocess must have been killed right away"
+			if err == nil && len(s.signalsSent) == 1 && s.signalsSent[0] == syscall.SIGKILL {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.signalsSent, err)
+			}
+		}
+
+		t.Log("\twhen member grace is enabled and process terminates upon SIGTERM")
+		{
+			s := &testProcessSignaler{terminateUponSigterm: true}
+			killer := &processHzMemberKiller{processSignaler: s}
+			err := killer.kill(member, memberAccessConfig{}, sleepConfig{enabled: true, durationSeconds: 1})
+
+			msg := "\t\tonly SIGTERM must have been sent"
+			if err == nil && len(s.signalsSent) == 1 && s.signalsSent[0] == syscall.SIGTERM {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.signalsSent, err)
+			}
+		}
+
+		t.Log("\twhen member grace is enabled and process ignores SIGTERM")
+		{
+			s := &testProcessSignaler{terminateUponSigterm: false}
+			killer := &processHzMemberKiller{processSignaler: s}
+			start := time.Now()
+			err := killer.kill(member, memberAccessConfig{}, sleepConfig{enabled: true, durationSeconds: 1})
+
+			msg := "\t\tSIGKILL must have been sent after grace period has elapsed"
+			if err == nil && len(s.signalsSent) == 2 && s.signalsSent[0] == syscall.SIGTERM && s.signalsSent[1] == syscall.SIGKILL && time.Since(start) >= time.Second {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.signalsSent, err)
+			}
+		}
+
+		t.Log("\twhen signal cannot be sent")
+		{
+			s := &testProcessSignaler{returnError: true}
+			killer := &processHzMemberKiller{processSignaler: s}
+			err := killer.kill(member, memberAccessConfig{}, sleepConfig{enabled: false})
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, processSignalError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen member identifier is not a pid")
+		{
+			s := &testProcessSignaler{}
+			killer := &processHzMemberKiller{processSignaler: s}
+			err := killer.kill(hzMember{"hazelcastplatform-0"}, memberAccessConfig{}, sleepConfig{enabled: false})
+
+			msg := "\t\terror must be returned, and no signal must have been sent"
+			if err != nil && len(s.signalsSent) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.signalsSent, err)
+			}
+		}
+	}
+
+}
+
+func TestAccessModeHzMemberChooserAndKiller(t *testing.T) {
+
+	t.Log("given a member chooser and a member killer delegating according to the member access mode")
+	{
+		processChooser := &testHzMemberChooser{memberID: "4711"}
+		processKiller := &testHzMemberKiller{}
+		chooser := &accessModeHzMemberChooser{choosers: map[string]hzMemberChooser{processAccessMode: processChooser}}
+		killer := &accessModeHzMemberKiller{killers: map[string]hzMemberKiller{processAccessMode: processKiller}}
+
+		t.Log("\twhen chooser and killer are available for access mode")
+		{
+			ac := memberAccessConfig{memberAccessMode: processAccessMode}
+			member, chooseErr := chooser.choose(ac)
+			killErr := killer.kill(member, ac, sleepConfig{})
+
+			msg := "\t\tchooser and killer for access mode must have been invoked"
+			if chooseErr == nil && killErr == nil && processChooser.numInvocations == 1 && processKiller.numInvocations == 1 && processKiller.givenHzMember.identifier == "4711" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, chooseErr, killErr)
+			}
+		}
+
+		t.Log("\twhen no chooser and killer are available for access mode")
+		{
+			ac := memberAccessConfig{memberAccessMode: k8sInClusterAccessMode}
+			_, chooseErr := chooser.choose(ac)
+			killErr := killer.kill(hzMember{"4711"}, ac, sleepConfig{})
+
+			msg := "\t\terrors must be returned"
+			if chooseErr != nil && killErr != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestDefaultProcessListerListWithoutProcFileSystem(t *testing.T) {
+
+	t.Log("given a process lister discovering hazelcast member processes on a system without proc file system")
+	{
+		defer func(f func(args ...string) ([]byte, error)) { runPs = f }(runPs)
+
+		var psInvocations [][]string
+		runPs = func(args ...string) ([]byte, error) {
+			psInvocations = append(psInvocations, args)
+			if args[0] == "-axo" {
+				return []byte("    1 /sbin/launchd\n" +
+					" 4711 java -cp hazelcast.jar com.hazelcast.core.server.HazelcastMemberStarter\n" +
+					" 4712 java -cp hazelcast.jar com.hazelcast.core.server.HazelcastMemberStarter\n" +
+					" 4713 /usr/bin/some-other-process --verbose\n"), nil
+			}
+			if args[len(args)-1] == "4712" {
+				return []byte("T\n"), nil
+			}
+			return []byte("Ss\n"), nil
+		}
+		l := &defaultProcessLister{procDir: filepath.Join(t.TempDir(), "no-proc-here")}
+
+		t.Log("\twhen process name discovery is used")
+		{
+			ac := memberAccessConfig{process: processMemberAccess{discovery: processNameDiscovery, processNamePattern: `HazelcastMemberStarter`}}
+			processes, err := l.list(ac)
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tprocesses listed by ps matching pattern must be returned, with stopped processes reported as inactive"
+			if processesAsExpected(processes, map[int]bool{4711: true, 4712: false}) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, processes)
+			}
+
+			msg = "\t\tps must have been asked for process list and state of each matching process"
+			if len(psInvocations) == 3 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, psInvocations)
+			}
+		}
+
+		t.Log("\twhen ps cannot be run")
+		{
+			runPs = func(_ ...string) ([]byte, error) {
+				return nil, errors.New("ps has left the building")
+			}
+			ac := memberAccessConfig{process: processMemberAccess{discovery: processNameDiscovery, processNamePattern: `HazelcastMemberStarter`}}
+			processes, err := l.list(ac)
+
+			msg := "\t\terror must be returned"
+			if err != nil && processes == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, processes)
+			}
+		}
+	}
+
+}
+
+func processesAsExpected(processes []hzProcess, expected map[int]bool) bool {
+
+	if len(processes) != len(expected) {
+		return false
+	}
+
+	for _, p := range processes {
+		if active, ok := expected[p.pid]; !ok || active != p.active {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+func writeFakeProcess(t *testing.T, procDir string, pid int, cmdline, state string) {
+
+	dir := filepath.Join(procDir, strconv.Itoa(pid))
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(dir, "cmdline"), cmdline+"\x00")
+	writeFile(t, filepath.Join(dir, "stat"), strconv.Itoa(pid)+" (java) "+state+" 1 1 1")
+
+}
+
+func writeFile(t *testing.T, path, content string) {
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+}
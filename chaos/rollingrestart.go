@@ -0,0 +1,452 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/status"
+	"sort"
+)
+
+type (
+	// rollingRestartMonkey restarts all Hazelcast members one after another, waiting for each member's replacement
+	// to become ready before moving on to the next member, which is what an upgrade of the Hazelcast cluster looks
+	// like. Since the replacements are created by Kubernetes, this monkey only supports the Kubernetes member
+	// access modes.
+	rollingRestartMonkey struct {
+		a                           client.ConfigPropertyAssigner
+		stateList                   []state
+		s                           sleeper
+		lister                      hzMemberLister
+		killer                      hzMemberKiller
+		prober                      clusterSafetyProber
+		disruptions                 *disruptionTracker
+		g                           *status.Gatherer
+		readyFunc                   raiseReady
+		notReadyFunc                raiseNotReady
+		numMembersRestarted         uint32
+		numRollingRestartsCompleted uint32
+		numRollingRestartsAborted   uint32
+	}
+	rollingRestartMonkeyConfig struct {
+		enabled             bool
+		numRuns             uint32
+		accessConfig        *memberAccessConfig
+		sleep               *sleepConfig
+		sleepBetweenMembers *sleepConfig
+		memberGrace         *sleepConfig
+		readiness           *readinessConfig
+		waitForClusterSafe  *clusterSafeConfig
+	}
+	readinessConfig struct {
+		pollIntervalSeconds int
+		timeoutSeconds      int
+	}
+)
+
+const (
+	statusKeyNumMembersRestarted         = "numMembersRestarted"
+	statusKeyNumRollingRestartsCompleted = "numRollingRestartsCompleted"
+	statusKeyNumRollingRestartsAborted   = "numRollingRestartsAborted"
+)
+
+var (
+	replacementNotReadyError  = errors.New("replacement of restarted hazelcast member did not become ready in time")
+	clusterNotSafeError       = errors.New("hazelcast cluster did not become safe in time")
+	disruptionInProgressError = errors.New("another monkey's disruption of hazelcast cluster still in progress")
+)
+
+func init() {
+	register(&rollingRestartMonkey{})
+}
+
+func (m *rollingRestartMonkey) init(a client.ConfigPropertyAssigner, d monkeyDependencies, g *status.Gatherer,
+	readyFunc raiseReady, notReadyFunc raiseNotReady) {
+
+	m.a = a
+	m.s = d.s
+	m.lister = d.lister
+	m.killer = d.killer
+	m.prober = d.prober
+	m.disruptions = d.disruptions
+	m.g = g
+	m.numMembersRestarted = 0
+	m.numRollingRestartsCompleted = 0
+	m.numRollingRestartsAborted = 0
+	m.readyFunc = readyFunc
+	m.notReadyFunc = notReadyFunc
+
+	api.RegisterStatefulActor(api.ChaosMonkeys, "rollingRestart", m.g.AssembleStatusCopy)
+
+}
+
+func (m *rollingRestartMonkey) causeChaos() {
+
+	defer m.g.StopListen()
+	go m.g.Listen()
+	m.insertInitialStatus()
+
+	m.appendState(start)
+
+	mc, err := populateRollingRestartMonkeyConfig(m.a)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("aborting rolling restart monkey launch: unable to populate config due to error: %s", err.Error()), log.ErrorLevel)
+		return
+	}
+	m.appendState(populateConfigComplete)
+	m.g.Updates <- status.Update{Key: statusKeyNumRuns, Value: mc.numRuns}
+
+	if !mc.enabled {
+		lp.LogChaosMonkeyEvent("rolling restart monkey not enabled -- won't run", log.InfoLevel)
+		return
+	}
+	m.notReadyFunc()
+	m.appendState(checkEnabledComplete)
+
+	m.appendState(raiseReadyComplete)
+	m.appendState(chaosStart)
+
+	m.readyFunc()
+
+	for i := uint32(0); i < mc.numRuns; i++ {
+		m.s.sleep(mc.sleep, sleepTimeFunc)
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("rolling restart monkey starting rolling restart in run %d", i), log.InfoLevel)
+		if err := m.restartAllMembers(mc); err != nil {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("aborted rolling restart in run %d -- will try again in next run: %s", i, err.Error()), log.WarnLevel)
+			m.updateNumRollingRestartsAborted()
+		} else {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("completed rolling restart in run %d", i), log.InfoLevel)
+			m.updateNumRollingRestartsCompleted()
+		}
+	}
+
+	m.appendState(chaosComplete)
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("rolling restart monkey done after %d loop/-s", mc.numRuns), log.InfoLevel)
+
+}
+
+// restartAllMembers aborts as soon as one member cannot be restarted, or its replacement does not become ready in
+// time, because carrying on would take down yet another member while the cluster is still missing one. For the same
+// reason, it won't begin while another monkey is disrupting the cluster, and keeps the others from doing so until
+// the rolling restart is over.
+func (m *rollingRestartMonkey) restartAllMembers(mc *rollingRestartMonkeyConfig) error {
+
+	if ok, other := m.disruptions.begin("rollingRestart"); !ok {
+		return fmt.Errorf("%w: monkey '%s'", disruptionInProgressError, other)
+	}
+	restarted := false
+	defer func() {
+		m.disruptions.end(restarted)
+	}()
+
+	ac := *mc.accessConfig
+
+	members, err := m.lister.list(ac)
+	if err != nil {
+		return err
+	}
+	if len(members) == 0 {
+		return noMemberFoundError
+	}
+
+	sortForRollingRestart(members)
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("restarting %d hazelcast member/-s one after another", len(members)), log.TraceLevel)
+
+	for i, target := range members {
+		current, err := m.lister.list(ac)
+		if err != nil {
+			return err
+		}
+		if !containsMember(current, target.uid) {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("hazelcast member '%s' is gone already -- skipping", target.member.identifier), log.InfoLevel)
+			continue
+		}
+		numReadyBefore := countReadyMembers(current)
+
+		if err := m.killer.kill(target.member, ac, *mc.memberGrace); err != nil {
+			return err
+		}
+		restarted = true
+
+		if err := m.awaitReplacement(ac, target, numReadyBefore, mc.readiness); err != nil {
+			return err
+		}
+
+		if mc.waitForClusterSafe.enabled {
+			if err := awaitClusterSafe(m.s, m.prober, mc.waitForClusterSafe.healthCheckUrl, mc.readiness); err != nil {
+				return err
+			}
+		}
+
+		m.updateNumMembersRestarted()
+
+		if i < len(members)-1 {
+			m.s.sleep(mc.sleepBetweenMembers, sleepTimeFunc)
+		}
+	}
+
+	return nil
+
+}
+
+// awaitReplacement considers the given member replaced once it is gone and as many members are ready as were ready
+// before it was restarted. This way, it does not matter whether the replacement gets the same name, as a
+// StatefulSet's Pod does, or a new one.
+func (m *rollingRestartMonkey) awaitReplacement(ac memberAccessConfig, restarted listedHzMember, numReadyBefore int, rc *readinessConfig) error {
+
+	return pollUntil(m.s, rc, func() bool {
+		current, err := m.lister.list(ac)
+		if err != nil {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to list hazelcast members while waiting for replacement of '%s': %s", restarted.member.identifier, err.Error()), log.WarnLevel)
+			return false
+		}
+		if containsMember(current, restarted.uid) {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("hazelcast member '%s' still terminating", restarted.member.identifier), log.TraceLevel)
+			return false
+		}
+		numReady := countReadyMembers(current)
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("%d of %d hazelcast member/-s ready after restart of '%s'", numReady, numReadyBefore, restarted.member.identifier), log.TraceLevel)
+		return numReady >= numReadyBefore
+	}, replacementNotReadyError)
+
+}
+
+func awaitClusterSafe(s sleeper, p clusterSafetyProber, healthCheckUrl string, rc *readinessConfig) error {
+
+	return pollUntil(s, rc, func() bool {
+		safe, err := p.probe(healthCheckUrl)
+		if err != nil {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to determine whether hazelcast cluster is safe: %s", err.Error()), log.WarnLevel)
+			return false
+		}
+		return safe
+	}, clusterNotSafeError)
+
+}
+
+// pollUntil sleeps by means of the given sleeper rather than checking the wall clock, so the timeout translates to
+// a fixed number of polls.
+func pollUntil(s sleeper, rc *readinessConfig, condition func() bool, timeoutError error) error {
+
+	pollSleep := &sleepConfig{enabled: true, durationSeconds: rc.pollIntervalSeconds}
+	maxPolls := (rc.timeoutSeconds + rc.pollIntervalSeconds - 1) / rc.pollIntervalSeconds
+
+	for i := 0; i < maxPolls; i++ {
+		s.sleep(pollSleep, sleepTimeFunc)
+		if condition() {
+			return nil
+		}
+	}
+
+	return timeoutError
+
+}
+
+// sortForRollingRestart brings the members into the order in which Kubernetes updates the Pods of a StatefulSet,
+// i.e. from the highest ordinal to the lowest. Comparing lengths first sorts 'member-10' after 'member-9'.
+func sortForRollingRestart(members []listedHzMember) {
+
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i].member.identifier, members[j].member.identifier
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a > b
+	})
+
+}
+
+func containsMember(members []listedHzMember, uid string) bool {
+
+	for _, m := range members {
+		if m.uid == uid {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+func countReadyMembers(members []listedHzMember) int {
+
+	numReady := 0
+	for _, m := range members {
+		if m.ready {
+			numReady++
+		}
+	}
+
+	return numReady
+
+}
+
+func (m *rollingRestartMonkey) updateNumMembersRestarted() {
+
+	m.numMembersRestarted++
+	m.g.Updates <- status.Update{Key: statusKeyNumMembersRestarted, Value: m.numMembersRestarted}
+
+}
+
+func (m *rollingRestartMonkey) updateNumRollingRestartsCompleted() {
+
+	m.numRollingRestartsCompleted++
+	m.g.Updates <- status.Update{Key: statusKeyNumRollingRestartsCompleted, Value: m.numRollingRestartsCompleted}
+
+}
+
+func (m *rollingRestartMonkey) updateNumRollingRestartsAborted() {
+
+	m.numRollingRestartsAborted++
+	m.g.Updates <- status.Update{Key: statusKeyNumRollingRestartsAborted, Value: m.numRollingRestartsAborted}
+
+}
+
+func (m *rollingRestartMonkey) insertInitialStatus() {
+
+	m.g.Updates <- status.Update{Key: statusKeyNumRuns, Value: uint32(0)}
+	m.g.Updates <- status.Update{Key: statusKeyNumMembersRestarted, Value: uint32(0)}
+	m.g.Updates <- status.Update{Key: statusKeyNumRollingRestartsCompleted, Value: uint32(0)}
+	m.g.Updates <- status.Update{Key: statusKeyNumRollingRestartsAborted, Value: uint32(0)}
+
+}
+
+func (m *rollingRestartMonkey) appendState(s state) {
+
+	m.stateList = append(m.stateList, s)
+
+}
+
+func populateRollingRestartMonkeyConfig(a client.ConfigPropertyAssigner) (*rollingRestartMonkeyConfig, error) {
+
+	monkeyKeyPath := "chaosMonkeys.rollingRestart"
+
+	configBuilder := monkeyConfigBuilder{monkeyKeyPath: monkeyKeyPath}
+
+	return configBuilder.populateRollingRestartConfig(a)
+
+}
+
+func (b monkeyConfigBuilder) populateRollingRestartConfig(a client.ConfigPropertyAssigner) (*rollingRestartMonkeyConfig, error) {
+
+	var assignmentOps []func() error
+
+	var enabled bool
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".enabled", client.ValidateBool, func(a any) {
+			enabled = a.(bool)
+		})
+	})
+
+	var numRuns uint32
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".numRuns", client.ValidateInt, func(a any) {
+			numRuns = uint32(a.(int))
+		})
+	})
+
+	var hzMemberAccessMode string
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".memberAccess.mode", client.ValidateString, func(a any) {
+			hzMemberAccessMode = a.(string)
+		})
+	})
+
+	rc := &readinessConfig{}
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".readiness.pollIntervalSeconds", client.ValidateInt, func(a any) {
+			rc.pollIntervalSeconds = a.(int)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".readiness.timeoutSeconds", client.ValidateInt, func(a any) {
+			rc.timeoutSeconds = a.(int)
+		})
+	})
+
+	cc := &clusterSafeConfig{}
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".waitForClusterSafe.enabled", client.ValidateBool, func(a any) {
+			cc.enabled = a.(bool)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".waitForClusterSafe.healthCheckUrl", client.ValidateString, func(a any) {
+			cc.healthCheckUrl = a.(string)
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	if hzMemberAccessMode != k8sOutOfClusterAccessMode && hzMemberAccessMode != k8sInClusterAccessMode {
+		return nil, fmt.Errorf("rolling restart requires kubernetes member access mode, got: %s", hzMemberAccessMode)
+	}
+
+	ac := &memberAccessConfig{memberAccessMode: hzMemberAccessMode}
+	if err := b.populateMemberAccessModeConfig(a, ac); err != nil {
+		return nil, err
+	}
+
+	sleeps := make(map[string]*sleepConfig)
+	for _, sleepKey := range []string{"sleep", "sleepBetweenMembers", "memberGrace"} {
+		sc, err := b.populateSleepConfig(a, sleepKey)
+		if err != nil {
+			return nil, err
+		}
+		sleeps[sleepKey] = sc
+	}
+
+	return &rollingRestartMonkeyConfig{
+		enabled:             enabled,
+		numRuns:             numRuns,
+		accessConfig:        ac,
+		sleep:               sleeps["sleep"],
+		sleepBetweenMembers: sleeps["sleepBetweenMembers"],
+		memberGrace:         sleeps["memberGrace"],
+		readiness:           rc,
+		waitForClusterSafe:  cc,
+	}, nil
+
+}
+
+func (b monkeyConfigBuilder) populateSleepConfig(a client.ConfigPropertyAssigner, sleepKey string) (*sleepConfig, error) {
+
+	var assignmentOps []func() error
+
+	sc := &sleepConfig{}
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+"."+sleepKey+".enabled", client.ValidateBool, func(a any) {
+			sc.enabled = a.(bool)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+"."+sleepKey+".durationSeconds", client.ValidateInt, func(a any) {
+			sc.durationSeconds = a.(int)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+"."+sleepKey+".enableRandomness", client.ValidateBool, func(a any) {
+			sc.enableRandomness = a.(bool)
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	return sc, nil
+
+}
@@ -0,0 +1,534 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"hazeltest/status"
+	"testing"
+)
+
+type (
+	// testRestartableHzCluster acts as both lister and killer, so killed members can be replaced by the time the
+	// members are listed again. A killed member remains listed for the given number of listings, after which its
+	// replacement -- carrying the same name, but a new uid -- shows up.
+	testRestartableHzCluster struct {
+		members               []listedHzMember
+		terminating           map[string]int
+		numListingsUntilGone  int
+		replacementNeverReady bool
+		returnListError       bool
+		returnKillError       bool
+		numListInvocations    int
+		killed                []string
+	}
+)
+
+const (
+	rollingRestartKeyPath = "chaosMonkeys.rollingRestart"
+)
+
+var (
+	clusterListError = errors.New("this cluster is camera-shy")
+	clusterKillError = errors.New("this cluster refuses to die")
+)
+
+func (c *testRestartableHzCluster) list(_ memberAccessConfig) ([]listedHzMember, error) {
+
+	c.numListInvocations++
+
+	if c.returnListError {
+		return nil, clusterListError
+	}
+
+	for i, m := range c.members {
+		remaining, ok := c.terminating[m.member.identifier]
+		if !ok {
+			continue
+		}
+		if remaining > 0 {
+			c.terminating[m.member.identifier] = remaining - 1
+			continue
+		}
+		c.members[i] = listedHzMember{m.member, m.uid + "-replaced", !c.replacementNeverReady}
+		delete(c.terminating, m.member.identifier)
+	}
+
+	members := make([]listedHzMember, len(c.members))
+	copy(members, c.members)
+
+	return members, nil
+
+}
+
+func (c *testRestartableHzCluster) kill(member hzMember, _ memberAccessConfig, _ sleepConfig) error {
+
+	if c.returnKillError {
+		return clusterKillError
+	}
+
+	c.killed = append(c.killed, member.identifier)
+	c.terminating[member.identifier] = c.numListingsUntilGone
+
+	return nil
+
+}
+
+func TestRollingRestartMonkeyCauseChaos(t *testing.T) {
+
+	t.Log("given a rolling restart monkey with the ability to restart hazelcast members")
+	{
+		t.Log("\twhen populating the rolling restart config returns an error")
+		{
+			testConfig := assembleRollingRestartTestConfig(true, 3, k8sInClusterAccessMode)
+			delete(testConfig, rollingRestartKeyPath+".readiness.timeoutSeconds")
+			m := rollingRestartMonkey{}
+
+			raiseReadyInvoked := false
+			testReadyFunc := func() {
+				raiseReadyInvoked = true
+			}
+			cluster := assembleRestartableHzCluster(3, 1)
+			m.init(&testConfigPropertyAssigner{testConfig}, monkeyDependencies{s: &testSleeper{}, lister: cluster, killer: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), testReadyFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tstate transitions must contain only start state"
+			if detail, ok := checkMonkeyStateTransitions([]state{start}, m.stateList); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+
+			msg = "\t\tno member must have been restarted"
+			if len(cluster.killed) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cluster.killed)
+			}
+
+			msg = "\t\traise ready function must not have been invoked"
+			if !raiseReadyInvoked {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen monkey is disabled")
+		{
+			m := rollingRestartMonkey{}
+			cluster := assembleRestartableHzCluster(3, 1)
+			m.init(&testConfigPropertyAssigner{assembleRollingRestartTestConfig(false, 3, k8sInClusterAccessMode)}, monkeyDependencies{s: &testSleeper{}, lister: cluster, killer: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tstate transitions must be correct"
+			if detail, ok := checkMonkeyStateTransitions([]state{start, populateConfigComplete}, m.stateList); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+
+			msg = "\t\tmonkey status must contain expected values"
+			if ok, key, detail := rollingRestartStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 3, 0, 0, 0); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+		t.Log("\twhen replacements of all restarted members become ready")
+		{
+			numRuns := 2
+			m := rollingRestartMonkey{}
+			cluster := assembleRestartableHzCluster(3, 2)
+
+			raiseReadyInvoked := false
+			testReadyFunc := func() {
+				raiseReadyInvoked = true
+			}
+			raiseNotReadyInvoked := false
+			testNotReadyFunc := func() {
+				raiseNotReadyInvoked = true
+			}
+			m.init(&testConfigPropertyAssigner{assembleRollingRestartTestConfig(true, numRuns, k8sInClusterAccessMode)}, monkeyDependencies{s: &testSleeper{}, lister: cluster, killer: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), testReadyFunc, testNotReadyFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tstate transitions must be correct"
+			if detail, ok := checkMonkeyStateTransitions(completeRunStateList, m.stateList); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+
+			msg = "\t\tall members must have been restarted in each run, in reverse order of their ordinals"
+			expected := []string{"hazelcastplatform-2", "hazelcastplatform-1", "hazelcastplatform-0", "hazelcastplatform-2", "hazelcastplatform-1", "hazelcastplatform-0"}
+			if fmt.Sprint(cluster.killed) == fmt.Sprint(expected) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cluster.killed)
+			}
+
+			msg = "\t\tmonkey status must contain expected values"
+			if ok, key, detail := rollingRestartStatusContainsExpectedValues(m.g.AssembleStatusCopy(), numRuns, 6, 2, 0); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+
+			msg = "\t\tboth api status functions must have been invoked"
+			if raiseReadyInvoked && raiseNotReadyInvoked {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen replacement of restarted member never becomes ready")
+		{
+			numRuns := 1
+			m := rollingRestartMonkey{}
+			cluster := assembleRestartableHzCluster(3, 1)
+			cluster.replacementNeverReady = true
+			s := &testSleeper{}
+			m.init(&testConfigPropertyAssigner{assembleRollingRestartTestConfig(true, numRuns, k8sInClusterAccessMode)}, monkeyDependencies{s: s, lister: cluster, killer: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tno further member must have been restarted"
+			if len(cluster.killed) == numRuns {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cluster.killed)
+			}
+
+			msg = "\t\tmonkey must have waited until timeout"
+			if s.secondsSlept == numRuns*60 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.secondsSlept)
+			}
+
+			msg = "\t\tmonkey status must report rolling restart as aborted"
+			if ok, key, detail := rollingRestartStatusContainsExpectedValues(m.g.AssembleStatusCopy(), numRuns, 0, 0, numRuns); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+		t.Log("\twhen another monkey's disruption is in progress")
+		{
+			m := rollingRestartMonkey{}
+			cluster := assembleRestartableHzCluster(3, 1)
+			disruptions := &disruptionTracker{}
+			disruptions.begin("statefulSetScaler")
+			m.init(&testConfigPropertyAssigner{assembleRollingRestartTestConfig(true, 1, k8sInClusterAccessMode)}, monkeyDependencies{s: &testSleeper{}, lister: cluster, killer: cluster, prober: &testClusterSafetyProber{}, disruptions: disruptions}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tno member must have been restarted"
+			if len(cluster.killed) == 0 && cluster.numListInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cluster.killed)
+			}
+
+			msg = "\t\tmonkey status must report rolling restart as aborted"
+			if ok, key, detail := rollingRestartStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 1, 0, 0, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+		t.Log("\twhen rolling restart completes")
+		{
+			m := rollingRestartMonkey{}
+			cluster := assembleRestartableHzCluster(3, 1)
+			disruptions := &disruptionTracker{}
+			m.init(&testConfigPropertyAssigner{assembleRollingRestartTestConfig(true, 1, k8sInClusterAccessMode)}, monkeyDependencies{s: &testSleeper{}, lister: cluster, killer: cluster, prober: &testClusterSafetyProber{}, disruptions: disruptions}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\trolling restart must have been recorded as last disruption"
+			if !disruptions.last().IsZero() {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tother monkeys must be free to disrupt cluster again"
+			if ok, other := disruptions.begin("memberKiller"); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, other)
+			}
+		}
+		t.Log("\twhen members cannot be listed")
+		{
+			m := rollingRestartMonkey{}
+			cluster := assembleRestartableHzCluster(3, 1)
+			cluster.returnListError = true
+			m.init(&testConfigPropertyAssigner{assembleRollingRestartTestConfig(true, 1, k8sInClusterAccessMode)}, monkeyDependencies{s: &testSleeper{}, lister: cluster, killer: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tno member must have been restarted"
+			if len(cluster.killed) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cluster.killed)
+			}
+
+			msg = "\t\tmonkey status must report rolling restart as aborted"
+			if ok, key, detail := rollingRestartStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 1, 0, 0, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+		t.Log("\twhen member cannot be killed")
+		{
+			m := rollingRestartMonkey{}
+			cluster := assembleRestartableHzCluster(3, 1)
+			cluster.returnKillError = true
+			m.init(&testConfigPropertyAssigner{assembleRollingRestartTestConfig(true, 1, k8sInClusterAccessMode)}, monkeyDependencies{s: &testSleeper{}, lister: cluster, killer: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tmonkey must not have waited for replacement"
+			if cluster.numListInvocations == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cluster.numListInvocations)
+			}
+
+			msg = "\t\tmonkey status must report rolling restart as aborted"
+			if ok, key, detail := rollingRestartStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 1, 0, 0, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+		t.Log("\twhen waiting for cluster safety has been enabled and cluster becomes safe")
+		{
+			m := rollingRestartMonkey{}
+			cluster := assembleRestartableHzCluster(3, 1)
+			prober := &testClusterSafetyProber{safe: true}
+			testConfig := assembleRollingRestartTestConfig(true, 1, k8sInClusterAccessMode)
+			testConfig[rollingRestartKeyPath+".waitForClusterSafe.enabled"] = true
+			m.init(&testConfigPropertyAssigner{testConfig}, monkeyDependencies{s: &testSleeper{}, lister: cluster, killer: cluster, prober: prober, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tcluster safety must have been probed once per restarted member"
+			if prober.numInvocations == 3 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, prober.numInvocations)
+			}
+
+			msg = "\t\tmonkey status must report rolling restart as completed"
+			if ok, key, detail := rollingRestartStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 1, 3, 1, 0); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+		t.Log("\twhen waiting for cluster safety has been enabled and cluster does not become safe")
+		{
+			m := rollingRestartMonkey{}
+			cluster := assembleRestartableHzCluster(3, 1)
+			testConfig := assembleRollingRestartTestConfig(true, 1, k8sInClusterAccessMode)
+			testConfig[rollingRestartKeyPath+".waitForClusterSafe.enabled"] = true
+			m.init(&testConfigPropertyAssigner{testConfig}, monkeyDependencies{s: &testSleeper{}, lister: cluster, killer: cluster, prober: &testClusterSafetyProber{returnError: true}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tno further member must have been restarted"
+			if len(cluster.killed) == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cluster.killed)
+			}
+
+			msg = "\t\tmonkey status must report rolling restart as aborted"
+			if ok, key, detail := rollingRestartStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 1, 0, 0, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+	}
+
+}
+
+func TestSortForRollingRestart(t *testing.T) {
+
+	t.Log("given a list of hazelcast members to be restarted")
+	{
+		t.Log("\twhen member names carry ordinals of different lengths")
+		{
+			members := []listedHzMember{
+				{member: hzMember{"hazelcastplatform-9"}},
+				{member: hzMember{"hazelcastplatform-0"}},
+				{member: hzMember{"hazelcastplatform-10"}},
+				{member: hzMember{"hazelcastplatform-2"}},
+			}
+			sortForRollingRestart(members)
+
+			msg := "\t\tmembers must be sorted in descending order of their ordinals"
+			var names []string
+			for _, m := range members {
+				names = append(names, m.member.identifier)
+			}
+			if fmt.Sprint(names) == fmt.Sprint([]string{"hazelcastplatform-10", "hazelcastplatform-9", "hazelcastplatform-2", "hazelcastplatform-0"}) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, names)
+			}
+		}
+	}
+
+}
+
+func TestPopulateRollingRestartConfig(t *testing.T) {
+
+	t.Log("given a property assigner containing the rolling restart monkey's config")
+	{
+		b := monkeyConfigBuilder{monkeyKeyPath: rollingRestartKeyPath}
+
+		t.Log("\twhen config is complete and valid")
+		{
+			mc, err := b.populateRollingRestartConfig(&testConfigPropertyAssigner{assembleRollingRestartTestConfig(true, 3, k8sOutOfClusterAccessMode)})
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tconfig must contain expected values"
+			if mc.enabled && mc.numRuns == 3 &&
+				mc.accessConfig.memberAccessMode == k8sOutOfClusterAccessMode &&
+				mc.accessConfig.k8sOutOfCluster.namespace == "hazelcastplatform" &&
+				mc.sleepBetweenMembers.durationSeconds == 30 &&
+				mc.memberGrace.durationSeconds == 10 &&
+				mc.readiness.pollIntervalSeconds == 5 && mc.readiness.timeoutSeconds == 60 &&
+				!mc.waitForClusterSafe.enabled {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen process member access mode has been configured")
+		{
+			_, err := b.populateRollingRestartConfig(&testConfigPropertyAssigner{assembleRollingRestartTestConfig(true, 3, processAccessMode)})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen readiness poll interval is invalid")
+		{
+			testConfig := assembleRollingRestartTestConfig(true, 3, k8sInClusterAccessMode)
+			testConfig[rollingRestartKeyPath+".readiness.pollIntervalSeconds"] = 0
+			_, err := b.populateRollingRestartConfig(&testConfigPropertyAssigner{testConfig})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen sleep config is incomplete")
+		{
+			testConfig := assembleRollingRestartTestConfig(true, 3, k8sInClusterAccessMode)
+			delete(testConfig, rollingRestartKeyPath+".sleepBetweenMembers.durationSeconds")
+			_, err := b.populateRollingRestartConfig(&testConfigPropertyAssigner{testConfig})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func rollingRestartStatusContainsExpectedValues(status map[string]any, expectedNumRuns, expectedNumMembersRestarted,
+	expectedNumCompleted, expectedNumAborted int) (bool, string, string) {
+
+	expected := map[string]int{
+		statusKeyNumRuns:                     expectedNumRuns,
+		statusKeyNumMembersRestarted:         expectedNumMembersRestarted,
+		statusKeyNumRollingRestartsCompleted: expectedNumCompleted,
+		statusKeyNumRollingRestartsAborted:   expectedNumAborted,
+	}
+
+	for key, value := range expected {
+		if fromStatus, ok := status[key]; !ok || fromStatus != uint32(value) {
+			return false, key, fmt.Sprintf("expected: %d, got: %d", value, fromStatus)
+		}
+	}
+
+	return true, "", ""
+
+}
+
+func assembleRestartableHzCluster(numMembers, numListingsUntilGone int) *testRestartableHzCluster {
+
+	members := make([]listedHzMember, numMembers)
+	for i := 0; i < numMembers; i++ {
+		name := fmt.Sprintf("hazelcastplatform-%d", i)
+		members[i] = listedHzMember{hzMember{name}, name + "-uid", true}
+	}
+
+	return &testRestartableHzCluster{
+		members:              members,
+		terminating:          make(map[string]int),
+		numListingsUntilGone: numListingsUntilGone,
+	}
+
+}
+
+func assembleRollingRestartTestConfig(enabled bool, numRuns int, memberAccessMode string) map[string]any {
+
+	return map[string]any{
+		rollingRestartKeyPath + ".enabled":                                    enabled,
+		rollingRestartKeyPath + ".numRuns":                                    numRuns,
+		rollingRestartKeyPath + ".memberAccess.mode":                          memberAccessMode,
+		rollingRestartKeyPath + ".memberAccess.k8sOutOfCluster.kubeconfig":    "default",
+		rollingRestartKeyPath + ".memberAccess.k8sOutOfCluster.namespace":     "hazelcastplatform",
+		rollingRestartKeyPath + ".memberAccess.k8sOutOfCluster.labelSelector": validLabelSelector,
+		rollingRestartKeyPath + ".memberAccess.k8sInCluster.labelSelector":    validLabelSelector,
+		rollingRestartKeyPath + ".sleep.enabled":                              false,
+		rollingRestartKeyPath + ".sleep.durationSeconds":                      600,
+		rollingRestartKeyPath + ".sleep.enableRandomness":                     false,
+		rollingRestartKeyPath + ".sleepBetweenMembers.enabled":                false,
+		rollingRestartKeyPath + ".sleepBetweenMembers.durationSeconds":        30,
+		rollingRestartKeyPath + ".sleepBetweenMembers.enableRandomness":       false,
+		rollingRestartKeyPath + ".memberGrace.enabled":                        true,
+		rollingRestartKeyPath + ".memberGrace.durationSeconds":                10,
+		rollingRestartKeyPath + ".memberGrace.enableRandomness":               false,
+		rollingRestartKeyPath + ".readiness.pollIntervalSeconds":              5,
+		rollingRestartKeyPath + ".readiness.timeoutSeconds":                   60,
+		rollingRestartKeyPath + ".waitForClusterSafe.enabled":                 false,
+		rollingRestartKeyPath + ".waitForClusterSafe.healthCheckUrl":          "http://hazelcastplatform:5701/hazelcast/health",
+	}
+
+}
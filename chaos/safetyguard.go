@@ -0,0 +1,167 @@
+package chaos
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	readyMemberCounter interface {
+		countReady(ac memberAccessConfig) (int, error)
+	}
+	clusterSafetyProber interface {
+		probe(healthCheckUrl string) (bool, error)
+	}
+	safetyGuard interface {
+		isSafeToKill(gc *safetyGuardConfig, ac memberAccessConfig, lastKill time.Time) (bool, string)
+	}
+	safetyGuardConfig struct {
+		enabled              bool
+		minReadyMembers      minReadyMembersConfig
+		minTimeSinceLastKill minTimeSinceLastKillConfig
+		clusterSafe          clusterSafeConfig
+	}
+	minReadyMembersConfig struct {
+		enabled bool
+		count   int
+	}
+	minTimeSinceLastKillConfig struct {
+		enabled         bool
+		durationSeconds int
+	}
+	clusterSafeConfig struct {
+		enabled        bool
+		healthCheckUrl string
+	}
+	// preconditionSafetyGuard evaluates the preconditions configured for a kill one after another and considers it
+	// unsafe to kill as soon as one of them is not met. A precondition that cannot be evaluated is considered not met,
+	// since the point of the guard is to err on the side of caution.
+	preconditionSafetyGuard struct {
+		counter readyMemberCounter
+		prober  clusterSafetyProber
+	}
+	// defaultClusterSafetyProber asks a Hazelcast member for the cluster's safety state by means of the member's
+	// health check endpoint, which is part of the member's REST API. The Hazelcast Go client does not expose the
+	// partition service, so this is the only way to find out whether the cluster is safe.
+	defaultClusterSafetyProber struct {
+		httpClient *http.Client
+	}
+	hzHealthCheckResponse struct {
+		ClusterSafe bool `json:"clusterSafe"`
+	}
+	// disruptionTracker is shared by the monkeys disrupting the Hazelcast cluster -- member killer, rolling restart,
+	// and statefulset scaler --, which run concurrently. Only one of them may disrupt the cluster at a time, so none
+	// of them acts while the cluster is still recovering from another one's disruption, and the member killer's
+	// safety guard measures the time since the last kill from the last disruption, whichever monkey caused it.
+	disruptionTracker struct {
+		mu             sync.Mutex
+		inProgress     string
+		lastDisruption time.Time
+	}
+)
+
+const (
+	statusKeyNumKillsSkippedUnsafe = "numKillsSkippedUnsafe"
+)
+
+func (g *preconditionSafetyGuard) isSafeToKill(gc *safetyGuardConfig, ac memberAccessConfig, lastKill time.Time) (bool, string) {
+
+	if !gc.enabled {
+		return true, ""
+	}
+
+	if gc.minTimeSinceLastKill.enabled && !lastKill.IsZero() {
+		minTimeSinceLastKill := time.Duration(gc.minTimeSinceLastKill.durationSeconds) * time.Second
+		if elapsed := time.Since(lastKill); elapsed < minTimeSinceLastKill {
+			return false, fmt.Sprintf("only %s elapsed since last kill, but at least %s required", elapsed.Round(time.Second), minTimeSinceLastKill)
+		}
+	}
+
+	if gc.minReadyMembers.enabled {
+		numReady, err := g.counter.countReady(ac)
+		if err != nil {
+			return false, fmt.Sprintf("unable to count ready hazelcast members: %s", err.Error())
+		}
+		if numReady < gc.minReadyMembers.count {
+			return false, fmt.Sprintf("only %d ready hazelcast member/-s, but at least %d required", numReady, gc.minReadyMembers.count)
+		}
+	}
+
+	if gc.clusterSafe.enabled {
+		safe, err := g.prober.probe(gc.clusterSafe.healthCheckUrl)
+		if err != nil {
+			return false, fmt.Sprintf("unable to determine whether hazelcast cluster is safe: %s", err.Error())
+		}
+		if !safe {
+			return false, "hazelcast cluster not safe"
+		}
+	}
+
+	return true, ""
+
+}
+
+// begin reserves the cluster for a disruption by the given monkey, unless another monkey's disruption is still in
+// progress, in which case the name of that monkey is returned.
+func (t *disruptionTracker) begin(monkeyName string) (bool, string) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.inProgress != "" {
+		return false, t.inProgress
+	}
+
+	t.inProgress = monkeyName
+	return true, ""
+
+}
+
+func (t *disruptionTracker) end(disrupted bool) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.inProgress = ""
+	if disrupted {
+		t.lastDisruption = time.Now()
+	}
+
+}
+
+func (t *disruptionTracker) last() time.Time {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.lastDisruption
+
+}
+
+func (p *defaultClusterSafetyProber) probe(healthCheckUrl string) (bool, error) {
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("probing hazelcast cluster safety using health check url '%s'", healthCheckUrl), log.TraceLevel)
+
+	resp, err := p.httpClient.Get(healthCheckUrl)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("health check url '%s' returned status code %d", healthCheckUrl, resp.StatusCode)
+	}
+
+	var health hzHealthCheckResponse
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return false, fmt.Errorf("unable to decode response of health check url '%s': %w", healthCheckUrl, err)
+	}
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("health check url '%s' reported cluster safe: %t", healthCheckUrl, health.ClusterSafe), log.TraceLevel)
+	return health.ClusterSafe, nil
+
+}
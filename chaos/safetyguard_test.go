@@ -0,0 +1,423 @@
+package chaos
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type (
+	testReadyMemberCounter struct {
+		numReady       int
+		returnError    bool
+		numInvocations int
+	}
+	testClusterSafetyProber struct {
+		safe           bool
+		returnError    bool
+		numInvocations int
+	}
+)
+
+var (
+	readyMemberCountError = errors.New("counting is hard")
+	clusterProbeError     = errors.New("the cluster refuses to talk about its feelings")
+)
+
+func (c *testReadyMemberCounter) countReady(_ memberAccessConfig) (int, error) {
+
+	c.numInvocations++
+
+	if c.returnError {
+		return 0, readyMemberCountError
+	}
+
+	return c.numReady, nil
+
+}
+
+func (p *testClusterSafetyProber) probe(_ string) (bool, error) {
+
+	p.numInvocations++
+
+	if p.returnError {
+		return false, clusterProbeError
+	}
+
+	return p.safe, nil
+
+}
+
+func TestPreconditionSafetyGuardIsSafeToKill(t *testing.T) {
+
+	t.Log("given a safety guard evaluating preconditions for killing a hazelcast member")
+	{
+		t.Log("\twhen safety guard has been disabled")
+		{
+			counter := &testReadyMemberCounter{}
+			prober := &testClusterSafetyProber{}
+			g := &preconditionSafetyGuard{counter, prober}
+
+			gc := assembleSafetyGuardConfig(3, 120, true)
+			gc.enabled = false
+			safe, _ := g.isSafeToKill(gc, memberAccessConfig{}, time.Now())
+
+			msg := "\t\tkill must be deemed safe"
+			if safe {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tneither counter nor prober must have been invoked"
+			if counter.numInvocations == 0 && prober.numInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen all preconditions are met")
+		{
+			counter := &testReadyMemberCounter{numReady: 3}
+			prober := &testClusterSafetyProber{safe: true}
+			g := &preconditionSafetyGuard{counter, prober}
+
+			safe, reason := g.isSafeToKill(assembleSafetyGuardConfig(3, 120, true), memberAccessConfig{}, time.Now().Add(-121*time.Second))
+
+			msg := "\t\tkill must be deemed safe"
+			if safe && reason == "" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, reason)
+			}
+
+			msg = "\t\tcounter and prober must have been invoked once each"
+			if counter.numInvocations == 1 && prober.numInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen no member has been killed yet")
+		{
+			g := &preconditionSafetyGuard{&testReadyMemberCounter{numReady: 3}, &testClusterSafetyProber{safe: true}}
+			safe, reason := g.isSafeToKill(assembleSafetyGuardConfig(3, 120, true), memberAccessConfig{}, time.Time{})
+
+			msg := "\t\tminimum time since last kill must not prevent kill"
+			if safe {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, reason)
+			}
+		}
+
+		t.Log("\twhen minimum time since last kill has not yet elapsed")
+		{
+			counter := &testReadyMemberCounter{numReady: 3}
+			g := &preconditionSafetyGuard{counter, &testClusterSafetyProber{safe: true}}
+			safe, reason := g.isSafeToKill(assembleSafetyGuardConfig(3, 120, true), memberAccessConfig{}, time.Now().Add(-30*time.Second))
+
+			msg := "\t\tkill must be deemed unsafe, and reason must be given"
+			if !safe && reason != "" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tremaining preconditions must not have been evaluated"
+			if counter.numInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen fewer members than required are ready")
+		{
+			prober := &testClusterSafetyProber{safe: true}
+			g := &preconditionSafetyGuard{&testReadyMemberCounter{numReady: 2}, prober}
+			safe, reason := g.isSafeToKill(assembleSafetyGuardConfig(3, 120, true), memberAccessConfig{}, time.Time{})
+
+			msg := "\t\tkill must be deemed unsafe, and reason must be given"
+			if !safe && reason != "" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tcluster safety must not have been probed"
+			if prober.numInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen ready members cannot be counted")
+		{
+			g := &preconditionSafetyGuard{&testReadyMemberCounter{returnError: true}, &testClusterSafetyProber{safe: true}}
+			safe, reason := g.isSafeToKill(assembleSafetyGuardConfig(3, 120, true), memberAccessConfig{}, time.Time{})
+
+			msg := "\t\tkill must be deemed unsafe, and reason must be given"
+			if !safe && reason != "" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen cluster is not safe")
+		{
+			g := &preconditionSafetyGuard{&testReadyMemberCounter{numReady: 3}, &testClusterSafetyProber{safe: false}}
+			safe, reason := g.isSafeToKill(assembleSafetyGuardConfig(3, 120, true), memberAccessConfig{}, time.Time{})
+
+			msg := "\t\tkill must be deemed unsafe, and reason must be given"
+			if !safe && reason != "" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen cluster safety cannot be probed")
+		{
+			g := &preconditionSafetyGuard{&testReadyMemberCounter{numReady: 3}, &testClusterSafetyProber{returnError: true}}
+			safe, reason := g.isSafeToKill(assembleSafetyGuardConfig(3, 120, true), memberAccessConfig{}, time.Time{})
+
+			msg := "\t\tkill must be deemed unsafe, and reason must be given"
+			if !safe && reason != "" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen cluster safety check has been disabled")
+		{
+			prober := &testClusterSafetyProber{safe: false}
+			g := &preconditionSafetyGuard{&testReadyMemberCounter{numReady: 3}, prober}
+			safe, reason := g.isSafeToKill(assembleSafetyGuardConfig(3, 120, false), memberAccessConfig{}, time.Time{})
+
+			msg := "\t\tkill must be deemed safe"
+			if safe {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, reason)
+			}
+
+			msg = "\t\tcluster safety must not have been probed"
+			if prober.numInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestDisruptionTrackerBeginAndEnd(t *testing.T) {
+
+	t.Log("given a disruption tracker shared by monkeys disrupting the hazelcast cluster")
+	{
+		t.Log("\twhen one monkey begins a disruption while another one's is in progress")
+		{
+			d := &disruptionTracker{}
+			d.begin("rollingRestart")
+			ok, other := d.begin("memberKiller")
+
+			msg := "\t\tdisruption must be refused, naming monkey whose disruption is in progress"
+			if !ok && other == "rollingRestart" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, other)
+			}
+		}
+
+		t.Log("\twhen disruption ends without having disrupted cluster")
+		{
+			d := &disruptionTracker{}
+			d.begin("memberKiller")
+			d.end(false)
+
+			msg := "\t\tno last disruption must have been recorded"
+			if d.last().IsZero() {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, d.last())
+			}
+
+			msg = "\t\tnext disruption must be allowed to begin"
+			if ok, _ := d.begin("statefulSetScaler"); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen disruption ends having disrupted cluster")
+		{
+			d := &disruptionTracker{}
+			d.begin("statefulSetScaler")
+			d.end(true)
+
+			msg := "\t\ttime of last disruption must have been recorded"
+			if !d.last().IsZero() {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestDefaultClusterSafetyProberProbe(t *testing.T) {
+
+	t.Log("given a prober asking a hazelcast member's health check endpoint whether the cluster is safe")
+	{
+		p := &defaultClusterSafetyProber{httpClient: &http.Client{Timeout: 5 * time.Second}}
+
+		t.Log("\twhen health check endpoint reports cluster safe")
+		{
+			server := assembleHealthCheckServer(http.StatusOK, `{"nodeState":"ACTIVE","clusterState":"ACTIVE","clusterSafe":true,"migrationQueueSize":0,"clusterSize":3}`)
+			defer server.Close()
+
+			safe, err := p.probe(server.URL)
+
+			msg := "\t\tcluster must be reported safe"
+			if err == nil && safe {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen health check endpoint reports cluster not safe")
+		{
+			server := assembleHealthCheckServer(http.StatusOK, `{"nodeState":"ACTIVE","clusterState":"ACTIVE","clusterSafe":false,"migrationQueueSize":271,"clusterSize":2}`)
+			defer server.Close()
+
+			safe, err := p.probe(server.URL)
+
+			msg := "\t\tcluster must be reported not safe"
+			if err == nil && !safe {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen health check endpoint returns non-ok status code")
+		{
+			server := assembleHealthCheckServer(http.StatusServiceUnavailable, "")
+			defer server.Close()
+
+			_, err := p.probe(server.URL)
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen health check endpoint returns response that cannot be decoded")
+		{
+			server := assembleHealthCheckServer(http.StatusOK, "Hazelcast::NodeState=ACTIVE")
+			defer server.Close()
+
+			_, err := p.probe(server.URL)
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestAccessModeHzMemberChooserCountReady(t *testing.T) {
+
+	t.Log("given a member chooser delegating the counting of ready members according to the member access mode")
+	{
+		chooser := &accessModeHzMemberChooser{choosers: map[string]hzMemberChooser{
+			processAccessMode:      &processHzMemberChooser{processLister: &testProcessLister{processesToReturn: []hzProcess{{4711, true}, {4712, false}, {4713, true}}}},
+			k8sInClusterAccessMode: &testHzMemberChooser{},
+		}}
+
+		t.Log("\twhen chooser for access mode is able to count ready members")
+		{
+			numReady, err := chooser.countReady(memberAccessConfig{memberAccessMode: processAccessMode})
+
+			msg := "\t\tnumber of ready members must be returned"
+			if err == nil && numReady == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, numReady, err)
+			}
+		}
+
+		t.Log("\twhen chooser for access mode is not able to count ready members")
+		{
+			_, err := chooser.countReady(memberAccessConfig{memberAccessMode: k8sInClusterAccessMode})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen no chooser is available for access mode")
+		{
+			_, err := chooser.countReady(memberAccessConfig{memberAccessMode: k8sOutOfClusterAccessMode})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func assembleSafetyGuardConfig(minReadyMembers, minSecondsSinceLastKill int, clusterSafeEnabled bool) *safetyGuardConfig {
+
+	return &safetyGuardConfig{
+		enabled: true,
+		minReadyMembers: minReadyMembersConfig{
+			enabled: true,
+			count:   minReadyMembers,
+		},
+		minTimeSinceLastKill: minTimeSinceLastKillConfig{
+			enabled:         true,
+			durationSeconds: minSecondsSinceLastKill,
+		},
+		clusterSafe: clusterSafeConfig{
+			enabled:        clusterSafeEnabled,
+			healthCheckUrl: "http://hazelcastplatform:5701/hazelcast/health",
+		},
+	}
+
+}
+
+func assembleHealthCheckServer(statusCode int, body string) *httptest.Server {
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+
+}
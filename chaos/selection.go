@@ -0,0 +1,215 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hazelcast/hazelcast-go-client/cluster"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"net"
+	"sort"
+	"sync"
+)
+
+type (
+	// hzClusterView provides the hosts of the Hazelcast members as seen by a Hazelcast client, in the order of the
+	// cluster's member list. Hazelcast appends joining members to the end of the member list, so the first member is
+	// the oldest one, which is also the master member.
+	hzClusterView interface {
+		orderedMemberHosts() ([]string, error)
+	}
+	memberSelectionConfig struct {
+		strategy string
+		podNames []string
+	}
+	// k8sPodSelector applies the configured selection strategy to the candidate Pods, and remembers the Pod it has
+	// selected last for the round-robin strategy.
+	k8sPodSelector struct {
+		clusterView hzClusterView
+		lastChosen  string
+	}
+	// defaultHzClusterView keeps track of the cluster's members by means of a membership listener. The listener is
+	// registered with the client's config so it gets informed about the initial member list in the list's order,
+	// which a listener registered after the client has started would not be. The client is only started once the
+	// cluster view is first asked for its members.
+	defaultHzClusterView struct {
+		startClient func(handler cluster.MembershipStateChangeHandler)
+		mu          sync.Mutex
+		started     bool
+		members     []cluster.MemberInfo
+	}
+)
+
+const (
+	randomSelection      = "random"
+	oldestPodSelection   = "oldestPod"
+	youngestPodSelection = "youngestPod"
+	roundRobinSelection  = "roundRobin"
+	masterSelection      = "master"
+	fixedListSelection   = "fixedList"
+)
+
+var (
+	clusterViewNotReadyError = errors.New("hazelcast cluster view not yet available")
+)
+
+func (s *k8sPodSelector) selectPod(candidates []v1.Pod, sc memberSelectionConfig) (v1.Pod, error) {
+
+	switch sc.strategy {
+	case "", randomSelection:
+		return selectRandomPodFromList(candidates), nil
+	case oldestPodSelection:
+		return selectPodByAge(candidates, true), nil
+	case youngestPodSelection:
+		return selectPodByAge(candidates, false), nil
+	case roundRobinSelection:
+		p := s.selectNextPodInRound(candidates)
+		s.lastChosen = p.Name
+		return p, nil
+	case masterSelection:
+		return s.selectMasterPod(candidates)
+	case fixedListSelection:
+		return selectPodFromFixedList(candidates, sc.podNames)
+	default:
+		return v1.Pod{}, fmt.Errorf("unknown member selection strategy: %s", sc.strategy)
+	}
+
+}
+
+func selectPodByAge(candidates []v1.Pod, oldest bool) v1.Pod {
+
+	selected := candidates[0]
+	for _, p := range candidates[1:] {
+		if p.CreationTimestamp.Equal(&selected.CreationTimestamp) {
+			// Same age -- make choice deterministic
+			if p.Name < selected.Name {
+				selected = p
+			}
+			continue
+		}
+		if oldest == p.CreationTimestamp.Before(&selected.CreationTimestamp) {
+			selected = p
+		}
+	}
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("selected pod '%s' created at %s", selected.Name, selected.CreationTimestamp), log.TraceLevel)
+	return selected
+
+}
+
+// selectNextPodInRound selects the Pod whose name follows the name of the Pod selected last in alphabetical order,
+// which, in contrast to tracking Pods by index, works even if Pods come and go in between.
+func (s *k8sPodSelector) selectNextPodInRound(candidates []v1.Pod) v1.Pod {
+
+	sorted := make([]v1.Pod, len(candidates))
+	copy(sorted, candidates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for _, p := range sorted {
+		if p.Name > s.lastChosen {
+			return p
+		}
+	}
+
+	lp.LogChaosMonkeyEvent("round of pods complete -- starting over", log.TraceLevel)
+	return sorted[0]
+
+}
+
+func (s *k8sPodSelector) selectMasterPod(candidates []v1.Pod) (v1.Pod, error) {
+
+	hosts, err := s.clusterView.orderedMemberHosts()
+	if err != nil {
+		return v1.Pod{}, err
+	}
+
+	master := hosts[0]
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("hazelcast master member runs on host '%s'", master), log.TraceLevel)
+
+	for _, p := range candidates {
+		if p.Status.PodIP == master {
+			return p, nil
+		}
+	}
+
+	// Killing another member instead would defeat the purpose of targeting the master
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("no candidate pod runs hazelcast master member on host '%s'", master), log.WarnLevel)
+	return v1.Pod{}, noMemberFoundError
+
+}
+
+func selectPodFromFixedList(candidates []v1.Pod, podNames []string) (v1.Pod, error) {
+
+	names := make(map[string]struct{}, len(podNames))
+	for _, n := range podNames {
+		names[n] = struct{}{}
+	}
+
+	var listed []v1.Pod
+	for _, p := range candidates {
+		if _, ok := names[p.Name]; ok {
+			listed = append(listed, p)
+		}
+	}
+
+	if len(listed) == 0 {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("none of the candidate pods is contained in fixed list of pod names %v", podNames), log.WarnLevel)
+		return v1.Pod{}, noMemberFoundError
+	}
+
+	return selectRandomPodFromList(listed), nil
+
+}
+
+func (v *defaultHzClusterView) orderedMemberHosts() ([]string, error) {
+
+	v.mu.Lock()
+	if !v.started {
+		v.started = true
+		v.mu.Unlock()
+		lp.LogChaosMonkeyEvent("starting hazelcast client to obtain cluster view", log.InfoLevel)
+		v.startClient(v.onMembershipStateChanged)
+		v.mu.Lock()
+	}
+	defer v.mu.Unlock()
+
+	if len(v.members) == 0 {
+		return nil, clusterViewNotReadyError
+	}
+
+	hosts := make([]string, 0, len(v.members))
+	for _, m := range v.members {
+		host, _, err := net.SplitHostPort(m.Address.String())
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine host of hazelcast member '%s': %w", m.UUID, err)
+		}
+		hosts = append(hosts, host)
+	}
+
+	return hosts, nil
+
+}
+
+func (v *defaultHzClusterView) onMembershipStateChanged(e cluster.MembershipStateChanged) {
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("hazelcast member '%s' %s", e.Member, e.State), log.TraceLevel)
+
+	for i, m := range v.members {
+		if m.UUID == e.Member.UUID {
+			if e.State == cluster.MembershipStateRemoved {
+				v.members = append(v.members[:i], v.members[i+1:]...)
+			}
+			return
+		}
+	}
+
+	if e.State == cluster.MembershipStateAdded {
+		v.members = append(v.members, e.Member)
+	}
+
+}
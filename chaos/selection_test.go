@@ -0,0 +1,388 @@
+package chaos
+
+import (
+	"errors"
+	"github.com/hazelcast/hazelcast-go-client/cluster"
+	"github.com/hazelcast/hazelcast-go-client/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+type (
+	testHzClusterView struct {
+		hostsToReturn  []string
+		returnError    bool
+		numInvocations int
+	}
+)
+
+var (
+	clusterViewError = errors.New("the cluster is hiding")
+)
+
+func (v *testHzClusterView) orderedMemberHosts() ([]string, error) {
+
+	v.numInvocations++
+
+	if v.returnError {
+		return nil, clusterViewError
+	}
+
+	return v.hostsToReturn, nil
+
+}
+
+func TestK8sPodSelectorSelectPod(t *testing.T) {
+
+	t.Log("given a selector applying a selection strategy to candidate hazelcast member pods")
+	{
+		now := time.Now()
+		candidates := []v1.Pod{
+			assemblePodWithAgeAndIP("hazelcastplatform-1", now.Add(-2*time.Hour), "10.0.0.11"),
+			assemblePodWithAgeAndIP("hazelcastplatform-0", now.Add(-3*time.Hour), "10.0.0.10"),
+			assemblePodWithAgeAndIP("hazelcastplatform-2", now.Add(-1*time.Hour), "10.0.0.12"),
+		}
+
+		t.Log("\twhen random strategy is given")
+		{
+			s := &k8sPodSelector{}
+			p, err := s.selectPod(candidates, memberSelectionConfig{strategy: randomSelection})
+
+			msg := "\t\tone of the candidates must be selected"
+			if err == nil && (p.Name == "hazelcastplatform-0" || p.Name == "hazelcastplatform-1" || p.Name == "hazelcastplatform-2") {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, p.Name, err)
+			}
+		}
+
+		t.Log("\twhen oldest pod strategy is given")
+		{
+			s := &k8sPodSelector{}
+			p, err := s.selectPod(candidates, memberSelectionConfig{strategy: oldestPodSelection})
+
+			msg := "\t\toldest pod must be selected"
+			if err == nil && p.Name == "hazelcastplatform-0" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, p.Name, err)
+			}
+		}
+
+		t.Log("\twhen youngest pod strategy is given")
+		{
+			s := &k8sPodSelector{}
+			p, err := s.selectPod(candidates, memberSelectionConfig{strategy: youngestPodSelection})
+
+			msg := "\t\tyoungest pod must be selected"
+			if err == nil && p.Name == "hazelcastplatform-2" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, p.Name, err)
+			}
+		}
+
+		t.Log("\twhen round-robin strategy is given")
+		{
+			s := &k8sPodSelector{}
+
+			var selected []string
+			for i := 0; i < 4; i++ {
+				p, err := s.selectPod(candidates, memberSelectionConfig{strategy: roundRobinSelection})
+				if err != nil {
+					t.Fatal("\t\tno error must be returned", ballotX, err)
+				}
+				selected = append(selected, p.Name)
+			}
+
+			msg := "\t\tpods must be selected in order of their names, starting over once round is complete"
+			if selected[0] == "hazelcastplatform-0" && selected[1] == "hazelcastplatform-1" && selected[2] == "hazelcastplatform-2" && selected[3] == "hazelcastplatform-0" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, selected)
+			}
+		}
+
+		t.Log("\twhen round-robin strategy is given and pod selected last is gone")
+		{
+			s := &k8sPodSelector{lastChosen: "hazelcastplatform-1"}
+			p, err := s.selectPod([]v1.Pod{candidates[1], candidates[2]}, memberSelectionConfig{strategy: roundRobinSelection})
+
+			msg := "\t\tpod following pod selected last must be selected"
+			if err == nil && p.Name == "hazelcastplatform-2" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, p.Name, err)
+			}
+		}
+
+		t.Log("\twhen master strategy is given and master runs in candidate pod")
+		{
+			s := &k8sPodSelector{clusterView: &testHzClusterView{hostsToReturn: []string{"10.0.0.11", "10.0.0.10", "10.0.0.12"}}}
+			p, err := s.selectPod(candidates, memberSelectionConfig{strategy: masterSelection})
+
+			msg := "\t\tpod running master member must be selected"
+			if err == nil && p.Name == "hazelcastplatform-1" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, p.Name, err)
+			}
+		}
+
+		t.Log("\twhen master strategy is given and master does not run in any candidate pod")
+		{
+			s := &k8sPodSelector{clusterView: &testHzClusterView{hostsToReturn: []string{"10.0.0.42", "10.0.0.10"}}}
+			_, err := s.selectPod(candidates, memberSelectionConfig{strategy: masterSelection})
+
+			msg := "\t\tno member found error must be returned"
+			if errors.Is(err, noMemberFoundError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen master strategy is given and cluster view yields error")
+		{
+			s := &k8sPodSelector{clusterView: &testHzClusterView{returnError: true}}
+			_, err := s.selectPod(candidates, memberSelectionConfig{strategy: masterSelection})
+
+			msg := "\t\terror must be returned"
+			if errors.Is(err, clusterViewError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen fixed list strategy is given")
+		{
+			s := &k8sPodSelector{}
+
+			for i := 0; i < 10; i++ {
+				p, err := s.selectPod(candidates, memberSelectionConfig{strategy: fixedListSelection, podNames: []string{"hazelcastplatform-2", "hazelcastplatform-7"}})
+				if err != nil || p.Name != "hazelcastplatform-2" {
+					t.Fatal("\t\tonly listed pod must be selected", ballotX, p.Name, err)
+				}
+			}
+			t.Log("\t\tonly listed pod must be selected", checkMark)
+		}
+
+		t.Log("\twhen fixed list strategy is given and no listed pod is among candidates")
+		{
+			s := &k8sPodSelector{}
+			_, err := s.selectPod(candidates, memberSelectionConfig{strategy: fixedListSelection, podNames: []string{"hazelcastplatform-7"}})
+
+			msg := "\t\tno member found error must be returned"
+			if errors.Is(err, noMemberFoundError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen unknown strategy is given")
+		{
+			s := &k8sPodSelector{}
+			_, err := s.selectPod(candidates, memberSelectionConfig{strategy: "mostHated"})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestChooseMemberOnK8sUsingSelectionStrategy(t *testing.T) {
+
+	t.Log("given the member chooser's method to choose a target hazelcast member")
+	{
+		t.Log("\twhen selection strategy is given and target only active is activated")
+		{
+			now := time.Now()
+			oldestButNotReady := assemblePodWithAgeAndIP("hazelcastplatform-0", now.Add(-3*time.Hour), "10.0.0.10")
+			oldestButNotReady.Status.Conditions[0].Status = v1.ConditionFalse
+			pods := []v1.Pod{
+				oldestButNotReady,
+				assemblePodWithAgeAndIP("hazelcastplatform-1", now.Add(-2*time.Hour), "10.0.0.11"),
+				assemblePodWithAgeAndIP("hazelcastplatform-2", now.Add(-1*time.Hour), "10.0.0.12"),
+			}
+			memberChooser := k8sHzMemberChooser{csProvider, testNamespaceDiscoverer, &testK8sPodLister{pods, false, 0}, &k8sPodSelector{}}
+
+			ac := testAccessConfig
+			ac.selection = memberSelectionConfig{strategy: oldestPodSelection}
+			member, err := memberChooser.choose(ac)
+
+			msg := "\t\tstrategy must be applied to ready pods only"
+			if err == nil && member.identifier == "hazelcastplatform-1" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, member, err)
+			}
+		}
+
+		t.Log("\twhen selection strategy does not yield pod")
+		{
+			pods := []v1.Pod{assemblePod("hazelcastplatform-0", true)}
+			memberChooser := k8sHzMemberChooser{csProvider, testNamespaceDiscoverer, &testK8sPodLister{pods, false, 0}, &k8sPodSelector{}}
+
+			ac := testAccessConfig
+			ac.selection = memberSelectionConfig{strategy: fixedListSelection, podNames: []string{"hazelcastplatform-7"}}
+			member, err := memberChooser.choose(ac)
+
+			msg := "\t\tno member found error must be returned along with empty member"
+			if errors.Is(err, noMemberFoundError) && member == emptyMember {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, member, err)
+			}
+		}
+	}
+
+}
+
+func TestChooseMemberProcessUsingSelectionStrategy(t *testing.T) {
+
+	t.Log("given a member chooser for hazelcast members running as processes")
+	{
+		processes := []hzProcess{{4713, true}, {4711, true}, {4712, true}}
+
+		t.Log("\twhen round-robin strategy is given")
+		{
+			chooser := &processHzMemberChooser{processLister: &testProcessLister{processesToReturn: processes}}
+
+			var chosen []string
+			for i := 0; i < 4; i++ {
+				member, err := chooser.choose(memberAccessConfig{selection: memberSelectionConfig{strategy: roundRobinSelection}})
+				if err != nil {
+					t.Fatal("\t\tno error must be returned", ballotX, err)
+				}
+				chosen = append(chosen, member.identifier)
+			}
+
+			msg := "\t\tprocesses must be chosen in order of their pids, starting over once round is complete"
+			if chosen[0] == "4711" && chosen[1] == "4712" && chosen[2] == "4713" && chosen[3] == "4711" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, chosen)
+			}
+		}
+
+		t.Log("\twhen strategy relying on pods is given")
+		{
+			chooser := &processHzMemberChooser{processLister: &testProcessLister{processesToReturn: processes}}
+			_, err := chooser.choose(memberAccessConfig{selection: memberSelectionConfig{strategy: masterSelection}})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestDefaultHzClusterViewOrderedMemberHosts(t *testing.T) {
+
+	t.Log("given a cluster view keeping track of hazelcast members by means of a membership listener")
+	{
+		t.Log("\twhen cluster view is asked for members for the first time")
+		{
+			numClientStarts := 0
+			v := &defaultHzClusterView{}
+			v.startClient = func(handler cluster.MembershipStateChangeHandler) {
+				numClientStarts++
+				handler(assembleMembershipEvent("10.0.0.10:5701", cluster.MembershipStateAdded))
+				handler(assembleMembershipEvent("10.0.0.11:5701", cluster.MembershipStateAdded))
+			}
+
+			hosts, err := v.orderedMemberHosts()
+
+			msg := "\t\thosts of initial members must be returned in order"
+			if err == nil && len(hosts) == 2 && hosts[0] == "10.0.0.10" && hosts[1] == "10.0.0.11" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, hosts, err)
+			}
+
+			_, _ = v.orderedMemberHosts()
+
+			msg = "\t\tclient must have been started only once"
+			if numClientStarts == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, numClientStarts)
+			}
+		}
+
+		t.Log("\twhen master leaves the cluster and new member joins")
+		{
+			var listener cluster.MembershipStateChangeHandler
+			master := assembleMembershipEvent("10.0.0.10:5701", cluster.MembershipStateAdded)
+			v := &defaultHzClusterView{}
+			v.startClient = func(handler cluster.MembershipStateChangeHandler) {
+				listener = handler
+				handler(master)
+				handler(assembleMembershipEvent("10.0.0.11:5701", cluster.MembershipStateAdded))
+			}
+			_, _ = v.orderedMemberHosts()
+
+			master.State = cluster.MembershipStateRemoved
+			listener(master)
+			listener(assembleMembershipEvent("10.0.0.12:5701", cluster.MembershipStateAdded))
+
+			hosts, err := v.orderedMemberHosts()
+
+			msg := "\t\toldest remaining member must be first, and new member must be last"
+			if err == nil && len(hosts) == 2 && hosts[0] == "10.0.0.11" && hosts[1] == "10.0.0.12" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, hosts, err)
+			}
+		}
+
+		t.Log("\twhen no member has been reported yet")
+		{
+			v := &defaultHzClusterView{startClient: func(_ cluster.MembershipStateChangeHandler) {}}
+			_, err := v.orderedMemberHosts()
+
+			msg := "\t\tcluster view not ready error must be returned"
+			if errors.Is(err, clusterViewNotReadyError) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+	}
+
+}
+
+func assemblePodWithAgeAndIP(name string, created time.Time, ip string) v1.Pod {
+
+	p := assemblePod(name, true)
+	p.CreationTimestamp = metav1.NewTime(created)
+	p.Status.PodIP = ip
+
+	return p
+
+}
+
+func assembleMembershipEvent(address string, state cluster.MembershipState) cluster.MembershipStateChanged {
+
+	return cluster.MembershipStateChanged{
+		Member: cluster.MemberInfo{
+			Address: cluster.Address(address),
+			UUID:    types.NewUUID(),
+		},
+		State: state,
+	}
+
+}
@@ -0,0 +1,366 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/status"
+	"math/rand"
+)
+
+type (
+	// statefulSetScalerMonkey grows and shrinks the Hazelcast cluster by changing the number of replicas of the
+	// members' StatefulSet, thus causing partition migrations, which killing members alone does not (at least not
+	// permanently, since Kubernetes replaces killed members right away). After each change, the monkey waits for the
+	// cluster to reach the new size before it makes the next change.
+	statefulSetScalerMonkey struct {
+		a                  client.ConfigPropertyAssigner
+		stateList          []state
+		s                  sleeper
+		lister             hzMemberLister
+		scaler             hzClusterScaler
+		prober             clusterSafetyProber
+		disruptions        *disruptionTracker
+		g                  *status.Gatherer
+		readyFunc          raiseReady
+		notReadyFunc       raiseNotReady
+		numScaleOuts       uint32
+		numScaleIns        uint32
+		numScalingsAborted uint32
+	}
+	statefulSetScalerMonkeyConfig struct {
+		enabled            bool
+		numRuns            uint32
+		accessConfig       *memberAccessConfig
+		replicas           *replicasConfig
+		sleep              *sleepConfig
+		readiness          *readinessConfig
+		waitForClusterSafe *clusterSafeConfig
+	}
+	replicasConfig struct {
+		min       int
+		max       int
+		maxChange int
+	}
+)
+
+const (
+	statusKeyNumScaleOuts       = "numScaleOuts"
+	statusKeyNumScaleIns        = "numScaleIns"
+	statusKeyNumScalingsAborted = "numScalingsAborted"
+)
+
+var (
+	clusterSizeNotReachedError = errors.New("hazelcast cluster did not reach desired size in time")
+)
+
+func init() {
+	register(&statefulSetScalerMonkey{})
+}
+
+func (m *statefulSetScalerMonkey) init(a client.ConfigPropertyAssigner, d monkeyDependencies, g *status.Gatherer,
+	readyFunc raiseReady, notReadyFunc raiseNotReady) {
+
+	m.a = a
+	m.s = d.s
+	m.lister = d.lister
+	m.scaler = d.scaler
+	m.prober = d.prober
+	m.disruptions = d.disruptions
+	m.g = g
+	m.numScaleOuts = 0
+	m.numScaleIns = 0
+	m.numScalingsAborted = 0
+	m.readyFunc = readyFunc
+	m.notReadyFunc = notReadyFunc
+
+	api.RegisterStatefulActor(api.ChaosMonkeys, "statefulSetScaler", m.g.AssembleStatusCopy)
+
+}
+
+func (m *statefulSetScalerMonkey) causeChaos() {
+
+	defer m.g.StopListen()
+	go m.g.Listen()
+	m.insertInitialStatus()
+
+	m.appendState(start)
+
+	mc, err := populateStatefulSetScalerMonkeyConfig(m.a)
+	if err != nil {
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("aborting statefulset scaler monkey launch: unable to populate config due to error: %s", err.Error()), log.ErrorLevel)
+		return
+	}
+	m.appendState(populateConfigComplete)
+	m.g.Updates <- status.Update{Key: statusKeyNumRuns, Value: mc.numRuns}
+
+	if !mc.enabled {
+		lp.LogChaosMonkeyEvent("statefulset scaler monkey not enabled -- won't run", log.InfoLevel)
+		return
+	}
+	m.notReadyFunc()
+	m.appendState(checkEnabledComplete)
+
+	m.appendState(raiseReadyComplete)
+	m.appendState(chaosStart)
+
+	m.readyFunc()
+
+	for i := uint32(0); i < mc.numRuns; i++ {
+		m.s.sleep(mc.sleep, sleepTimeFunc)
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("statefulset scaler monkey acting in run %d", i), log.InfoLevel)
+		current, target, err := m.scaleCluster(mc)
+		if err != nil {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("aborted scaling of hazelcast cluster in run %d -- will try again in next run: %s", i, err.Error()), log.WarnLevel)
+			m.updateNumScalingsAborted()
+			continue
+		}
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("scaled hazelcast cluster from %d to %d member/-s in run %d", current, target, i), log.InfoLevel)
+		if target > current {
+			m.updateNumScaleOuts()
+		} else {
+			m.updateNumScaleIns()
+		}
+	}
+
+	m.appendState(chaosComplete)
+	lp.LogChaosMonkeyEvent(fmt.Sprintf("statefulset scaler monkey done after %d loop/-s", mc.numRuns), log.InfoLevel)
+
+}
+
+// scaleCluster won't act while another monkey is disrupting the cluster, and keeps the others from doing so until
+// the cluster has reached its new size.
+func (m *statefulSetScalerMonkey) scaleCluster(mc *statefulSetScalerMonkeyConfig) (int, int, error) {
+
+	if ok, other := m.disruptions.begin("statefulSetScaler"); !ok {
+		return 0, 0, fmt.Errorf("%w: monkey '%s'", disruptionInProgressError, other)
+	}
+	scaled := false
+	defer func() {
+		m.disruptions.end(scaled)
+	}()
+
+	ac := *mc.accessConfig
+
+	current, err := m.scaler.replicas(ac)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	target, err := chooseTargetReplicas(current, mc.replicas)
+	if err != nil {
+		return current, 0, err
+	}
+
+	if err := m.scaler.scale(ac, target); err != nil {
+		return current, target, err
+	}
+	scaled = true
+
+	if err := m.awaitClusterSize(ac, target, mc.readiness); err != nil {
+		return current, target, err
+	}
+
+	if mc.waitForClusterSafe.enabled {
+		if err := awaitClusterSafe(m.s, m.prober, mc.waitForClusterSafe.healthCheckUrl, mc.readiness); err != nil {
+			return current, target, err
+		}
+	}
+
+	return current, target, nil
+
+}
+
+// awaitClusterSize considers the cluster to have reached the given size once it consists of exactly that many
+// members, all of which are ready. Members being removed as part of a scale-in are not listed anymore as soon as
+// their deletion has begun, so the cluster's size already counts as reached while they are still shutting down.
+func (m *statefulSetScalerMonkey) awaitClusterSize(ac memberAccessConfig, size int, rc *readinessConfig) error {
+
+	return pollUntil(m.s, rc, func() bool {
+		members, err := m.lister.list(ac)
+		if err != nil {
+			lp.LogChaosMonkeyEvent(fmt.Sprintf("unable to list hazelcast members while waiting for cluster to reach size %d: %s", size, err.Error()), log.WarnLevel)
+			return false
+		}
+		numReady := countReadyMembers(members)
+		lp.LogChaosMonkeyEvent(fmt.Sprintf("hazelcast cluster currently has %d member/-s, %d of which ready, waiting for %d", len(members), numReady, size), log.TraceLevel)
+		return len(members) == size && numReady == size
+	}, clusterSizeNotReachedError)
+
+}
+
+// chooseTargetReplicas randomly chooses a number of replicas different from the current one within the configured
+// bounds, changing the number by no more than the configured maximum. The monkey won't act on a cluster whose size
+// is outside the bounds, as someone else -- human or machine -- is probably in charge of it.
+func chooseTargetReplicas(current int, rc *replicasConfig) (int, error) {
+
+	if current < rc.min || current > rc.max {
+		return 0, fmt.Errorf("current number of replicas %d outside of configured bounds [%d, %d]", current, rc.min, rc.max)
+	}
+
+	lowest := max(rc.min, current-rc.maxChange)
+	highest := min(rc.max, current+rc.maxChange)
+
+	// Skip the current number of replicas
+	target := lowest + rand.Intn(highest-lowest)
+	if target >= current {
+		target++
+	}
+
+	return target, nil
+
+}
+
+func (m *statefulSetScalerMonkey) updateNumScaleOuts() {
+
+	m.numScaleOuts++
+	m.g.Updates <- status.Update{Key: statusKeyNumScaleOuts, Value: m.numScaleOuts}
+
+}
+
+func (m *statefulSetScalerMonkey) updateNumScaleIns() {
+
+	m.numScaleIns++
+	m.g.Updates <- status.Update{Key: statusKeyNumScaleIns, Value: m.numScaleIns}
+
+}
+
+func (m *statefulSetScalerMonkey) updateNumScalingsAborted() {
+
+	m.numScalingsAborted++
+	m.g.Updates <- status.Update{Key: statusKeyNumScalingsAborted, Value: m.numScalingsAborted}
+
+}
+
+func (m *statefulSetScalerMonkey) insertInitialStatus() {
+
+	m.g.Updates <- status.Update{Key: statusKeyNumRuns, Value: uint32(0)}
+	m.g.Updates <- status.Update{Key: statusKeyNumScaleOuts, Value: uint32(0)}
+	m.g.Updates <- status.Update{Key: statusKeyNumScaleIns, Value: uint32(0)}
+	m.g.Updates <- status.Update{Key: statusKeyNumScalingsAborted, Value: uint32(0)}
+
+}
+
+func (m *statefulSetScalerMonkey) appendState(s state) {
+
+	m.stateList = append(m.stateList, s)
+
+}
+
+func populateStatefulSetScalerMonkeyConfig(a client.ConfigPropertyAssigner) (*statefulSetScalerMonkeyConfig, error) {
+
+	monkeyKeyPath := "chaosMonkeys.statefulSetScaler"
+
+	configBuilder := monkeyConfigBuilder{monkeyKeyPath: monkeyKeyPath}
+
+	return configBuilder.populateStatefulSetScalerConfig(a)
+
+}
+
+func (b monkeyConfigBuilder) populateStatefulSetScalerConfig(a client.ConfigPropertyAssigner) (*statefulSetScalerMonkeyConfig, error) {
+
+	var assignmentOps []func() error
+
+	var enabled bool
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".enabled", client.ValidateBool, func(a any) {
+			enabled = a.(bool)
+		})
+	})
+
+	var numRuns uint32
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".numRuns", client.ValidateInt, func(a any) {
+			numRuns = uint32(a.(int))
+		})
+	})
+
+	var hzMemberAccessMode string
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".memberAccess.mode", client.ValidateString, func(a any) {
+			hzMemberAccessMode = a.(string)
+		})
+	})
+
+	rpc := &replicasConfig{}
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".replicas.min", client.ValidateInt, func(a any) {
+			rpc.min = a.(int)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".replicas.max", client.ValidateInt, func(a any) {
+			rpc.max = a.(int)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".replicas.maxChange", client.ValidateInt, func(a any) {
+			rpc.maxChange = a.(int)
+		})
+	})
+
+	rc := &readinessConfig{}
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".readiness.pollIntervalSeconds", client.ValidateInt, func(a any) {
+			rc.pollIntervalSeconds = a.(int)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".readiness.timeoutSeconds", client.ValidateInt, func(a any) {
+			rc.timeoutSeconds = a.(int)
+		})
+	})
+
+	cc := &clusterSafeConfig{}
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".waitForClusterSafe.enabled", client.ValidateBool, func(a any) {
+			cc.enabled = a.(bool)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(b.monkeyKeyPath+".waitForClusterSafe.healthCheckUrl", client.ValidateString, func(a any) {
+			cc.healthCheckUrl = a.(string)
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	if hzMemberAccessMode != k8sOutOfClusterAccessMode && hzMemberAccessMode != k8sInClusterAccessMode {
+		return nil, fmt.Errorf("statefulset scaling requires kubernetes member access mode, got: %s", hzMemberAccessMode)
+	}
+
+	if rpc.min >= rpc.max {
+		return nil, fmt.Errorf("minimum number of replicas (%d) must be less than maximum number of replicas (%d)", rpc.min, rpc.max)
+	}
+
+	ac := &memberAccessConfig{memberAccessMode: hzMemberAccessMode}
+	if err := b.populateMemberAccessModeConfig(a, ac); err != nil {
+		return nil, err
+	}
+
+	sc, err := b.populateSleepConfig(a, "sleep")
+	if err != nil {
+		return nil, err
+	}
+
+	return &statefulSetScalerMonkeyConfig{
+		enabled:            enabled,
+		numRuns:            numRuns,
+		accessConfig:       ac,
+		replicas:           rpc,
+		sleep:              sc,
+		readiness:          rc,
+		waitForClusterSafe: cc,
+	}, nil
+
+}
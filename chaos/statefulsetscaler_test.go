@@ -0,0 +1,496 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"hazeltest/status"
+	"testing"
+)
+
+type (
+	// testScalableHzCluster acts as both cluster scaler and member lister. Once scaled, the cluster reaches its
+	// new size after the given number of listings.
+	testScalableHzCluster struct {
+		numReplicas          int
+		numMembers           int
+		numListingsUntilDone int
+		neverDone            bool
+		returnReplicasError  bool
+		returnScaleError     bool
+		scaledTo             []int
+	}
+)
+
+const (
+	statefulSetScalerKeyPath = "chaosMonkeys.statefulSetScaler"
+)
+
+var (
+	replicasError = errors.New("the statefulset has forgotten how many replicas it has")
+	scaleError    = errors.New("the statefulset likes its current size")
+)
+
+func (c *testScalableHzCluster) replicas(_ memberAccessConfig) (int, error) {
+
+	if c.returnReplicasError {
+		return 0, replicasError
+	}
+
+	return c.numReplicas, nil
+
+}
+
+func (c *testScalableHzCluster) scale(_ memberAccessConfig, replicas int) error {
+
+	if c.returnScaleError {
+		return scaleError
+	}
+
+	c.numReplicas = replicas
+	c.scaledTo = append(c.scaledTo, replicas)
+
+	return nil
+
+}
+
+func (c *testScalableHzCluster) list(_ memberAccessConfig) ([]listedHzMember, error) {
+
+	if c.numMembers != c.numReplicas && !c.neverDone {
+		if c.numListingsUntilDone > 0 {
+			c.numListingsUntilDone--
+		} else {
+			c.numMembers = c.numReplicas
+		}
+	}
+
+	members := make([]listedHzMember, c.numMembers)
+	for i := 0; i < c.numMembers; i++ {
+		name := fmt.Sprintf("hazelcastplatform-%d", i)
+		members[i] = listedHzMember{hzMember{name}, name + "-uid", true}
+	}
+
+	return members, nil
+
+}
+
+func TestStatefulSetScalerMonkeyCauseChaos(t *testing.T) {
+
+	t.Log("given a statefulset scaler monkey with the ability to scale the hazelcast cluster")
+	{
+		t.Log("\twhen populating the statefulset scaler config returns an error")
+		{
+			testConfig := assembleStatefulSetScalerTestConfig(true, 3, k8sInClusterAccessMode, 3, 5)
+			delete(testConfig, statefulSetScalerKeyPath+".replicas.maxChange")
+			m := statefulSetScalerMonkey{}
+			cluster := &testScalableHzCluster{numReplicas: 3, numMembers: 3}
+			m.init(&testConfigPropertyAssigner{testConfig}, monkeyDependencies{s: &testSleeper{}, lister: cluster, scaler: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tstate transitions must contain only start state"
+			if detail, ok := checkMonkeyStateTransitions([]state{start}, m.stateList); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+
+			msg = "\t\tcluster must not have been scaled"
+			if len(cluster.scaledTo) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cluster.scaledTo)
+			}
+		}
+		t.Log("\twhen monkey is disabled")
+		{
+			m := statefulSetScalerMonkey{}
+			cluster := &testScalableHzCluster{numReplicas: 3, numMembers: 3}
+			m.init(&testConfigPropertyAssigner{assembleStatefulSetScalerTestConfig(false, 3, k8sInClusterAccessMode, 3, 5)}, monkeyDependencies{s: &testSleeper{}, lister: cluster, scaler: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tstate transitions must be correct"
+			if detail, ok := checkMonkeyStateTransitions([]state{start, populateConfigComplete}, m.stateList); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+
+			msg = "\t\tmonkey status must contain expected values"
+			if ok, key, detail := statefulSetScalerStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 3, 0, 0, 0); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+		t.Log("\twhen cluster reaches new size after each change")
+		{
+			numRuns := 4
+			m := statefulSetScalerMonkey{}
+			cluster := &testScalableHzCluster{numReplicas: 3, numMembers: 3, numListingsUntilDone: 2}
+
+			raiseReadyInvoked := false
+			testReadyFunc := func() {
+				raiseReadyInvoked = true
+			}
+			raiseNotReadyInvoked := false
+			testNotReadyFunc := func() {
+				raiseNotReadyInvoked = true
+			}
+			m.init(&testConfigPropertyAssigner{assembleStatefulSetScalerTestConfig(true, numRuns, k8sInClusterAccessMode, 3, 5)}, monkeyDependencies{s: &testSleeper{}, lister: cluster, scaler: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), testReadyFunc, testNotReadyFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tstate transitions must be correct"
+			if detail, ok := checkMonkeyStateTransitions(completeRunStateList, m.stateList); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+
+			msg = "\t\tcluster must have been scaled once per run, within bounds, by one replica at a time"
+			previous := 3
+			for _, replicas := range cluster.scaledTo {
+				if replicas < 3 || replicas > 5 || (replicas != previous-1 && replicas != previous+1) {
+					t.Fatal(msg, ballotX, cluster.scaledTo)
+				}
+				previous = replicas
+			}
+			if len(cluster.scaledTo) == numRuns {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cluster.scaledTo)
+			}
+
+			msg = "\t\tmonkey status must contain expected values"
+			numScaleOuts, numScaleIns := 0, 0
+			previous = 3
+			for _, replicas := range cluster.scaledTo {
+				if replicas > previous {
+					numScaleOuts++
+				} else {
+					numScaleIns++
+				}
+				previous = replicas
+			}
+			if ok, key, detail := statefulSetScalerStatusContainsExpectedValues(m.g.AssembleStatusCopy(), numRuns, numScaleOuts, numScaleIns, 0); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+
+			msg = "\t\tboth api status functions must have been invoked"
+			if raiseReadyInvoked && raiseNotReadyInvoked {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen cluster never reaches new size")
+		{
+			m := statefulSetScalerMonkey{}
+			cluster := &testScalableHzCluster{numReplicas: 3, numMembers: 3, neverDone: true}
+			s := &testSleeper{}
+			m.init(&testConfigPropertyAssigner{assembleStatefulSetScalerTestConfig(true, 1, k8sInClusterAccessMode, 3, 5)}, monkeyDependencies{s: s, lister: cluster, scaler: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tmonkey must have waited until timeout"
+			if s.secondsSlept == 60 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.secondsSlept)
+			}
+
+			msg = "\t\tmonkey status must report scaling as aborted"
+			if ok, key, detail := statefulSetScalerStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 1, 0, 0, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+		t.Log("\twhen current number of replicas is outside of configured bounds")
+		{
+			m := statefulSetScalerMonkey{}
+			cluster := &testScalableHzCluster{numReplicas: 7, numMembers: 7}
+			m.init(&testConfigPropertyAssigner{assembleStatefulSetScalerTestConfig(true, 2, k8sInClusterAccessMode, 3, 5)}, monkeyDependencies{s: &testSleeper{}, lister: cluster, scaler: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tcluster must not have been scaled"
+			if len(cluster.scaledTo) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cluster.scaledTo)
+			}
+
+			msg = "\t\tmonkey status must report all scalings as aborted"
+			if ok, key, detail := statefulSetScalerStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 2, 0, 0, 2); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+		t.Log("\twhen another monkey's disruption is in progress")
+		{
+			m := statefulSetScalerMonkey{}
+			cluster := &testScalableHzCluster{numReplicas: 3, numMembers: 3}
+			disruptions := &disruptionTracker{}
+			disruptions.begin("memberKiller")
+			m.init(&testConfigPropertyAssigner{assembleStatefulSetScalerTestConfig(true, 1, k8sInClusterAccessMode, 3, 5)}, monkeyDependencies{s: &testSleeper{}, lister: cluster, scaler: cluster, prober: &testClusterSafetyProber{}, disruptions: disruptions}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tstatefulset must not have been scaled"
+			if cluster.numReplicas == 3 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cluster.numReplicas)
+			}
+
+			msg = "\t\tmonkey status must report scaling as aborted"
+			if ok, key, detail := statefulSetScalerStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 1, 0, 0, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+		t.Log("\twhen number of replicas cannot be determined")
+		{
+			m := statefulSetScalerMonkey{}
+			cluster := &testScalableHzCluster{returnReplicasError: true}
+			m.init(&testConfigPropertyAssigner{assembleStatefulSetScalerTestConfig(true, 1, k8sInClusterAccessMode, 3, 5)}, monkeyDependencies{s: &testSleeper{}, lister: cluster, scaler: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tmonkey status must report scaling as aborted"
+			if ok, key, detail := statefulSetScalerStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 1, 0, 0, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+		t.Log("\twhen statefulset cannot be scaled")
+		{
+			m := statefulSetScalerMonkey{}
+			cluster := &testScalableHzCluster{numReplicas: 3, numMembers: 3, returnScaleError: true}
+			s := &testSleeper{}
+			m.init(&testConfigPropertyAssigner{assembleStatefulSetScalerTestConfig(true, 1, k8sInClusterAccessMode, 3, 5)}, monkeyDependencies{s: s, lister: cluster, scaler: cluster, prober: &testClusterSafetyProber{}, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tmonkey must not have waited for cluster to reach new size"
+			if s.secondsSlept == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.secondsSlept)
+			}
+
+			msg = "\t\tmonkey status must report scaling as aborted"
+			if ok, key, detail := statefulSetScalerStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 1, 0, 0, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+		t.Log("\twhen waiting for cluster safety has been enabled and cluster does not become safe")
+		{
+			m := statefulSetScalerMonkey{}
+			cluster := &testScalableHzCluster{numReplicas: 3, numMembers: 3}
+			prober := &testClusterSafetyProber{safe: false}
+			testConfig := assembleStatefulSetScalerTestConfig(true, 1, k8sInClusterAccessMode, 3, 5)
+			testConfig[statefulSetScalerKeyPath+".waitForClusterSafe.enabled"] = true
+			m.init(&testConfigPropertyAssigner{testConfig}, monkeyDependencies{s: &testSleeper{}, lister: cluster, scaler: cluster, prober: prober, disruptions: &disruptionTracker{}}, status.NewGatherer(), noOpFunc, noOpFunc)
+
+			m.causeChaos()
+			waitForStatusGatheringDone(m.g)
+
+			msg := "\t\tcluster safety must have been probed until timeout"
+			if prober.numInvocations == 12 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, prober.numInvocations)
+			}
+
+			msg = "\t\tmonkey status must report scaling as aborted"
+			if ok, key, detail := statefulSetScalerStatusContainsExpectedValues(m.g.AssembleStatusCopy(), 1, 0, 0, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+		}
+	}
+
+}
+
+func TestChooseTargetReplicas(t *testing.T) {
+
+	t.Log("given the bounds within which to choose the target number of replicas")
+	{
+		t.Log("\twhen current number of replicas is at lower bound")
+		{
+			target, err := chooseTargetReplicas(3, &replicasConfig{min: 3, max: 5, maxChange: 1})
+
+			msg := "\t\tcluster must be scaled out"
+			if err == nil && target == 4 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, target, err)
+			}
+		}
+		t.Log("\twhen current number of replicas is at upper bound")
+		{
+			target, err := chooseTargetReplicas(5, &replicasConfig{min: 3, max: 5, maxChange: 1})
+
+			msg := "\t\tcluster must be scaled in"
+			if err == nil && target == 4 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, target, err)
+			}
+		}
+		t.Log("\twhen maximum change permits several target numbers of replicas")
+		{
+			msg := "\t\ttarget must always be within bounds, within maximum change, and different from current number of replicas"
+			for i := 0; i < 100; i++ {
+				target, err := chooseTargetReplicas(5, &replicasConfig{min: 2, max: 9, maxChange: 2})
+				if err != nil || target < 3 || target > 7 || target == 5 {
+					t.Fatal(msg, ballotX, target, err)
+				}
+			}
+			t.Log(msg, checkMark)
+		}
+		t.Log("\twhen current number of replicas is outside of bounds")
+		{
+			_, err := chooseTargetReplicas(2, &replicasConfig{min: 3, max: 5, maxChange: 1})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestPopulateStatefulSetScalerConfig(t *testing.T) {
+
+	t.Log("given a property assigner containing the statefulset scaler monkey's config")
+	{
+		b := monkeyConfigBuilder{monkeyKeyPath: statefulSetScalerKeyPath}
+
+		t.Log("\twhen config is complete and valid")
+		{
+			mc, err := b.populateStatefulSetScalerConfig(&testConfigPropertyAssigner{assembleStatefulSetScalerTestConfig(true, 3, k8sInClusterAccessMode, 3, 5)})
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tconfig must contain expected values"
+			if mc.enabled && mc.numRuns == 3 &&
+				mc.accessConfig.memberAccessMode == k8sInClusterAccessMode &&
+				mc.accessConfig.k8sInCluster.labelSelector == validLabelSelector &&
+				*mc.replicas == (replicasConfig{min: 3, max: 5, maxChange: 1}) &&
+				mc.readiness.timeoutSeconds == 60 &&
+				!mc.waitForClusterSafe.enabled {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen process member access mode has been configured")
+		{
+			_, err := b.populateStatefulSetScalerConfig(&testConfigPropertyAssigner{assembleStatefulSetScalerTestConfig(true, 3, processAccessMode, 3, 5)})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen minimum number of replicas is not less than maximum number of replicas")
+		{
+			_, err := b.populateStatefulSetScalerConfig(&testConfigPropertyAssigner{assembleStatefulSetScalerTestConfig(true, 3, k8sInClusterAccessMode, 5, 5)})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen maximum change is invalid")
+		{
+			testConfig := assembleStatefulSetScalerTestConfig(true, 3, k8sInClusterAccessMode, 3, 5)
+			testConfig[statefulSetScalerKeyPath+".replicas.maxChange"] = 0
+			_, err := b.populateStatefulSetScalerConfig(&testConfigPropertyAssigner{testConfig})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func statefulSetScalerStatusContainsExpectedValues(status map[string]any, expectedNumRuns, expectedNumScaleOuts,
+	expectedNumScaleIns, expectedNumAborted int) (bool, string, string) {
+
+	expected := map[string]int{
+		statusKeyNumRuns:            expectedNumRuns,
+		statusKeyNumScaleOuts:       expectedNumScaleOuts,
+		statusKeyNumScaleIns:        expectedNumScaleIns,
+		statusKeyNumScalingsAborted: expectedNumAborted,
+	}
+
+	for key, value := range expected {
+		if fromStatus, ok := status[key]; !ok || fromStatus != uint32(value) {
+			return false, key, fmt.Sprintf("expected: %d, got: %d", value, fromStatus)
+		}
+	}
+
+	return true, "", ""
+
+}
+
+func assembleStatefulSetScalerTestConfig(enabled bool, numRuns int, memberAccessMode string, minReplicas, maxReplicas int) map[string]any {
+
+	return map[string]any{
+		statefulSetScalerKeyPath + ".enabled":                                    enabled,
+		statefulSetScalerKeyPath + ".numRuns":                                    numRuns,
+		statefulSetScalerKeyPath + ".memberAccess.mode":                          memberAccessMode,
+		statefulSetScalerKeyPath + ".memberAccess.k8sOutOfCluster.kubeconfig":    "default",
+		statefulSetScalerKeyPath + ".memberAccess.k8sOutOfCluster.namespace":     "hazelcastplatform",
+		statefulSetScalerKeyPath + ".memberAccess.k8sOutOfCluster.labelSelector": validLabelSelector,
+		statefulSetScalerKeyPath + ".memberAccess.k8sInCluster.labelSelector":    validLabelSelector,
+		statefulSetScalerKeyPath + ".replicas.min":                               minReplicas,
+		statefulSetScalerKeyPath + ".replicas.max":                               maxReplicas,
+		statefulSetScalerKeyPath + ".replicas.maxChange":                         1,
+		statefulSetScalerKeyPath + ".sleep.enabled":                              false,
+		statefulSetScalerKeyPath + ".sleep.durationSeconds":                      600,
+		statefulSetScalerKeyPath + ".sleep.enableRandomness":                     false,
+		statefulSetScalerKeyPath + ".readiness.pollIntervalSeconds":              5,
+		statefulSetScalerKeyPath + ".readiness.timeoutSeconds":                   60,
+		statefulSetScalerKeyPath + ".waitForClusterSafe.enabled":                 false,
+		statefulSetScalerKeyPath + ".waitForClusterSafe.healthCheckUrl":          "http://hazelcastplatform:5701/hazelcast/health",
+	}
+
+}
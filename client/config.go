@@ -11,6 +11,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 )
 
 const (
@@ -165,6 +166,24 @@ func ValidatePercentage(path string, a any) error {
 
 }
 
+func ValidateDuration(path string, a any) error {
+
+	s, ok := a.(string)
+	if !ok {
+		return FailedParse{"duration string", path}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return FailedParse{"duration string", path}
+	} else if d <= 0 {
+		return FailedValueCheck{"expected this duration to be positive", path}
+	}
+
+	return nil
+
+}
+
 func ParseConfigs() error {
 
 	if args, err := parseCommandLineArgs(); err != nil {
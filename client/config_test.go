@@ -214,6 +214,56 @@ func TestValidateString(t *testing.T) {
 
 }
 
+func TestValidateDuration(t *testing.T) {
+
+	t.Log("given a duration validation function")
+	{
+		path := "mapTests.pokedex.runDuration.duration"
+
+		t.Log("\twhen providing a string that can be parsed into a positive duration")
+		{
+			for _, v := range []string{"6h", "90m", "1h30m15s"} {
+				err := ValidateDuration(path, v)
+
+				msg := "\t\tno error should occur"
+				if err == nil {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+		}
+
+		correctTypeOfErrorMsg := "\t\terror of correct type should be returned"
+		t.Log("\twhen providing a string that can be parsed into a duration, but duration is not positive")
+		{
+			for _, v := range []string{"0s", "-5m"} {
+				err := ValidateDuration(path, v)
+
+				if err != nil && errors.As(err, &FailedValueCheck{}) {
+					t.Log(correctTypeOfErrorMsg, checkMark, v)
+				} else {
+					t.Fatal(correctTypeOfErrorMsg, ballotX, v)
+				}
+			}
+		}
+
+		t.Log("\twhen providing a value that cannot be parsed into a duration")
+		{
+			for _, v := range []any{"6 hours", "", 42, true, 1.5} {
+				err := ValidateDuration(path, v)
+
+				if err != nil && errors.As(err, &FailedParse{}) {
+					t.Log(correctTypeOfErrorMsg, checkMark, v)
+				} else {
+					t.Fatal(correctTypeOfErrorMsg, ballotX, v)
+				}
+			}
+		}
+	}
+
+}
+
 func TestValidateInt(t *testing.T) {
 
 	t.Log("given an int validation function")
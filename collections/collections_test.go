@@ -0,0 +1,353 @@
+package collections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hazelcast/hazelcast-go-client"
+	"hazeltest/hazelcastwrapper"
+	"sync"
+)
+
+type (
+	testConfigPropertyAssigner struct {
+		returnError bool
+		testConfig  map[string]any
+	}
+	// testHzCollectionStore hands out the same kind of in-memory collection for multimaps, lists, and sets. Entries
+	// are identified by their key (multimaps only) and value, and lists are the only kind of collection accepting
+	// an entry more than once.
+	testHzCollectionStore struct {
+		collections  map[string]*testHzCollection
+		behavior     *testCollectionStoreBehavior
+		observations *testCollectionStoreObservations
+		l            sync.Mutex
+	}
+	testHzCollection struct {
+		entries             map[string]int
+		allowDuplicates     bool
+		numAccepted         int
+		addInvocations      int
+		containsInvocations int
+		removeInvocations   int
+		destroyInvocations  int
+		behavior            *testCollectionStoreBehavior
+		l                   sync.Mutex
+	}
+	testCollectionStoreBehavior struct {
+		returnErrorUponGetCollection, returnErrorUponAdd, returnErrorUponContains, returnErrorUponRemove bool
+		// Entries whose (1-based) number of acceptance is a multiple of the given value are acknowledged as added,
+		// but not stored. Zero disables the behavior.
+		loseEveryNth int
+	}
+	testCollectionStoreObservations struct {
+		numInitInvocations int
+	}
+	testSleeper struct {
+		sleepKinds []string
+		l          sync.Mutex
+	}
+	testHzClientHandler struct {
+		getClientInvocations, initClientInvocations, shutdownInvocations int
+		hzClusterName                                                    string
+		hzClusterMembers                                                 []string
+	}
+)
+
+const (
+	checkMark          = "\u2713"
+	ballotX            = "\u2717"
+	runnerKeyPath      = "testCollectionRunner"
+	collectionPrefix   = "t_"
+	collectionBaseName = "test"
+)
+
+var (
+	hzCluster                = "awesome-hz-cluster"
+	hzMembers                = []string{"awesome-hz-cluster-svc.cluster.local"}
+	expectedStatesForFullRun = []runnerState{start, populateConfigComplete, checkEnabledComplete, raiseReadyComplete, testLoopStart, testLoopComplete}
+)
+
+func newTestHzCollectionStore(behavior *testCollectionStoreBehavior) *testHzCollectionStore {
+
+	return &testHzCollectionStore{
+		collections:  make(map[string]*testHzCollection),
+		behavior:     behavior,
+		observations: &testCollectionStoreObservations{},
+	}
+
+}
+
+func (s *testSleeper) sleep(sc *sleepConfig, _ evaluateTimeToSleep, kind, _, _ string) {
+
+	if sc.enabled {
+		s.l.Lock()
+		s.sleepKinds = append(s.sleepKinds, kind)
+		s.l.Unlock()
+	}
+
+}
+
+func (s *testSleeper) numSleeps(kind string) int {
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	n := 0
+	for _, k := range s.sleepKinds {
+		if k == kind {
+			n++
+		}
+	}
+
+	return n
+
+}
+
+func (d *testHzCollectionStore) getCollection(name string, allowDuplicates bool) (*testHzCollection, error) {
+
+	if d.behavior.returnErrorUponGetCollection {
+		return nil, errors.New("it is but a scratch")
+	}
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	if c, ok := d.collections[name]; ok {
+		return c, nil
+	}
+
+	c := &testHzCollection{entries: make(map[string]int), allowDuplicates: allowDuplicates, behavior: d.behavior}
+	d.collections[name] = c
+
+	return c, nil
+
+}
+
+func (d *testHzCollectionStore) GetMultiMap(_ context.Context, name string) (hazelcastwrapper.MultiMap, error) {
+
+	c, err := d.getCollection(name, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+
+}
+
+func (d *testHzCollectionStore) GetList(_ context.Context, name string) (hazelcastwrapper.List, error) {
+
+	c, err := d.getCollection(name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+
+}
+
+func (d *testHzCollectionStore) GetSet(_ context.Context, name string) (hazelcastwrapper.Set, error) {
+
+	c, err := d.getCollection(name, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+
+}
+
+func (d *testHzCollectionStore) sumInvocations(f func(c *testHzCollection) int) int {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	sum := 0
+	for _, c := range d.collections {
+		c.l.Lock()
+		sum += f(c)
+		c.l.Unlock()
+	}
+
+	return sum
+
+}
+
+func (d *testHzCollection) add(id string) (bool, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.addInvocations++
+
+	if d.behavior.returnErrorUponAdd {
+		return false, errors.New("that's no moon")
+	}
+
+	if d.entries[id] > 0 && !d.allowDuplicates {
+		return false, nil
+	}
+
+	d.numAccepted++
+	if d.behavior.loseEveryNth > 0 && d.numAccepted%d.behavior.loseEveryNth == 0 {
+		return true, nil
+	}
+
+	d.entries[id]++
+
+	return true, nil
+
+}
+
+func (d *testHzCollection) contains(id string) (bool, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.containsInvocations++
+
+	if d.behavior.returnErrorUponContains {
+		return false, errors.New("these aren't the droids you're looking for")
+	}
+
+	return d.entries[id] > 0, nil
+
+}
+
+func (d *testHzCollection) remove(id string) (bool, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.removeInvocations++
+
+	if d.behavior.returnErrorUponRemove {
+		return false, errors.New("i've got a bad feeling about this")
+	}
+
+	if d.entries[id] == 0 {
+		return false, nil
+	}
+
+	d.entries[id]--
+	if d.entries[id] == 0 {
+		delete(d.entries, id)
+	}
+
+	return true, nil
+
+}
+
+func (d *testHzCollection) Put(_ context.Context, key any, value any) (bool, error) {
+	return d.add(fmt.Sprintf("%v|%v", key, value))
+}
+
+func (d *testHzCollection) ContainsEntry(_ context.Context, key any, value any) (bool, error) {
+	return d.contains(fmt.Sprintf("%v|%v", key, value))
+}
+
+func (d *testHzCollection) RemoveEntry(_ context.Context, key any, value any) (bool, error) {
+	return d.remove(fmt.Sprintf("%v|%v", key, value))
+}
+
+func (d *testHzCollection) Add(_ context.Context, element any) (bool, error) {
+	return d.add(fmt.Sprintf("%v", element))
+}
+
+func (d *testHzCollection) Contains(_ context.Context, element any) (bool, error) {
+	return d.contains(fmt.Sprintf("%v", element))
+}
+
+func (d *testHzCollection) Remove(_ context.Context, element any) (bool, error) {
+	return d.remove(fmt.Sprintf("%v", element))
+}
+
+func (d *testHzCollection) Clear(_ context.Context) error {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.entries = make(map[string]int)
+
+	return nil
+
+}
+
+func (d *testHzCollection) Size(_ context.Context) (int, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	size := 0
+	for _, v := range d.entries {
+		size += v
+	}
+
+	return size, nil
+
+}
+
+func (d *testHzCollection) Destroy(_ context.Context) error {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.destroyInvocations++
+
+	return nil
+
+}
+
+func (a testConfigPropertyAssigner) Assign(keyPath string, eval func(string, any) error, assign func(any)) error {
+
+	if a.returnError {
+		return errors.New("lo and behold, here is a deliberately thrown error")
+	}
+
+	if value, ok := a.testConfig[keyPath]; ok {
+		if err := eval(keyPath, value); err != nil {
+			return err
+		}
+		assign(value)
+	}
+
+	return nil
+}
+
+func (d *testHzClientHandler) GetClusterName() string {
+	return d.hzClusterName
+}
+
+func (d *testHzClientHandler) GetClusterMembers() []string {
+	return d.hzClusterMembers
+}
+
+func (d *testHzClientHandler) GetClient() *hazelcast.Client {
+	d.getClientInvocations++
+	return nil
+}
+
+func (d *testHzClientHandler) InitHazelcastClient(_ context.Context, _ string, _ string, _ []string) {
+	d.initClientInvocations++
+}
+
+func (d *testHzClientHandler) Shutdown(_ context.Context) error {
+	d.shutdownInvocations++
+	return nil
+}
+
+func checkRunnerStateTransitions(expected []runnerState, actual []runnerState) (string, bool) {
+
+	if len(expected) != len(actual) {
+		return fmt.Sprintf("expected %d state transition(-s), got %d", len(expected), len(actual)), false
+	}
+
+	for i, expectedValue := range expected {
+		if actual[i] != expectedValue {
+			return fmt.Sprintf("expected '%s' in index '%d', got '%s'", expectedValue, i, actual[i]), false
+		}
+	}
+
+	return "", true
+
+}
@@ -0,0 +1,118 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+)
+
+type (
+	listRunner struct {
+		assigner        client.ConfigPropertyAssigner
+		stateList       []runnerState
+		name            string
+		source          string
+		hzClientHandler hazelcastwrapper.HzClientHandler
+		hzListStore     hazelcastwrapper.ListStore
+		l               looper
+		gatherer        *status.Gatherer
+	}
+	listCollection struct {
+		l hazelcastwrapper.List
+	}
+)
+
+func init() {
+	register(&listRunner{
+		assigner:        &client.DefaultConfigPropertyAssigner{},
+		stateList:       []runnerState{},
+		name:            "collectionsListRunner",
+		source:          "listRunner",
+		hzClientHandler: &hazelcastwrapper.DefaultHzClientHandler{},
+		l:               &testLoop{},
+	})
+}
+
+func (r *listRunner) getSourceName() string {
+	return r.source
+}
+
+func (r *listRunner) runCollectionTests(hzCluster string, hzMembers []string, gatherer *status.Gatherer, sf *storeFuncs) {
+
+	r.gatherer = gatherer
+	r.appendState(start)
+
+	c, err := populateConfig(r.assigner, "collectionTests.list", "list")
+	if err != nil {
+		lp.LogCollectionRunnerEvent(fmt.Sprintf("aborting launch of list runner: unable to populate config due to error: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.appendState(populateConfigComplete)
+
+	if !c.enabled {
+		lp.LogCollectionRunnerEvent("list runner not enabled -- won't run", r.name, log.InfoLevel)
+		return
+	}
+	r.appendState(checkEnabledComplete)
+
+	api.RaiseNotReady()
+
+	ctx := context.TODO()
+
+	r.hzClientHandler.InitHazelcastClient(ctx, r.name, hzCluster, hzMembers)
+	defer func() {
+		_ = r.hzClientHandler.Shutdown(ctx)
+	}()
+	r.hzListStore = sf.list(r.hzClientHandler)
+
+	api.RaiseReady()
+	r.appendState(raiseReadyComplete)
+
+	lp.LogCollectionRunnerEvent("initialized hazelcast client", r.name, log.InfoLevel)
+	lp.LogCollectionRunnerEvent("starting test loop for lists", r.name, log.InfoLevel)
+
+	tle := &testLoopExecution{id: uuid.New(), runnerName: r.name, source: r.source, getCollection: r.getList, runnerConfig: c, ctx: ctx}
+	r.l.init(tle, &defaultSleeper{}, r.gatherer)
+
+	r.appendState(testLoopStart)
+	r.l.run()
+	r.appendState(testLoopComplete)
+
+	lp.LogCollectionRunnerEvent("finished list test loop", r.name, log.InfoLevel)
+
+}
+
+func (r *listRunner) getList(ctx context.Context, name string) (collection, error) {
+
+	l, err := r.hzListStore.GetList(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &listCollection{l}, nil
+
+}
+
+func (r *listRunner) appendState(s runnerState) {
+
+	r.stateList = append(r.stateList, s)
+	r.gatherer.Updates <- status.Update{Key: string(statusKeyCurrentState), Value: string(s)}
+
+}
+
+func (c *listCollection) add(ctx context.Context, _ int, e element) (bool, error) {
+	return c.l.Add(ctx, e)
+}
+
+func (c *listCollection) contains(ctx context.Context, _ int, e element) (bool, error) {
+	return c.l.Contains(ctx, e)
+}
+
+func (c *listCollection) remove(ctx context.Context, _ int, e element) (bool, error) {
+	return c.l.Remove(ctx, e)
+}
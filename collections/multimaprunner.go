@@ -0,0 +1,144 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+)
+
+type (
+	multiMapRunner struct {
+		assigner        client.ConfigPropertyAssigner
+		stateList       []runnerState
+		name            string
+		source          string
+		hzClientHandler hazelcastwrapper.HzClientHandler
+		hzMultiMapStore hazelcastwrapper.MultiMapStore
+		l               looper
+		gatherer        *status.Gatherer
+	}
+	// multiMapCollection stores the test loop's elements as values of a multimap. Consecutive elements share a key
+	// until the configured number of values per key has been reached, so each key ends up holding several values,
+	// and the test loop's checks exercise the entry-level operations of multimaps.
+	multiMapCollection struct {
+		mm              hazelcastwrapper.MultiMap
+		numValuesPerKey int
+	}
+)
+
+var (
+	numValuesPerKey int
+)
+
+func init() {
+	register(&multiMapRunner{
+		assigner:        &client.DefaultConfigPropertyAssigner{},
+		stateList:       []runnerState{},
+		name:            "collectionsMultiMapRunner",
+		source:          "multiMapRunner",
+		hzClientHandler: &hazelcastwrapper.DefaultHzClientHandler{},
+		l:               &testLoop{},
+	})
+}
+
+func (r *multiMapRunner) getSourceName() string {
+	return r.source
+}
+
+func (r *multiMapRunner) runCollectionTests(hzCluster string, hzMembers []string, gatherer *status.Gatherer, sf *storeFuncs) {
+
+	r.gatherer = gatherer
+	r.appendState(start)
+
+	c, err := populateMultiMapConfig(r.assigner)
+	if err != nil {
+		lp.LogCollectionRunnerEvent(fmt.Sprintf("aborting launch of multimap runner: unable to populate config due to error: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.appendState(populateConfigComplete)
+
+	if !c.enabled {
+		lp.LogCollectionRunnerEvent("multimap runner not enabled -- won't run", r.name, log.InfoLevel)
+		return
+	}
+	r.appendState(checkEnabledComplete)
+
+	api.RaiseNotReady()
+
+	ctx := context.TODO()
+
+	r.hzClientHandler.InitHazelcastClient(ctx, r.name, hzCluster, hzMembers)
+	defer func() {
+		_ = r.hzClientHandler.Shutdown(ctx)
+	}()
+	r.hzMultiMapStore = sf.multiMap(r.hzClientHandler)
+
+	api.RaiseReady()
+	r.appendState(raiseReadyComplete)
+
+	lp.LogCollectionRunnerEvent("initialized hazelcast client", r.name, log.InfoLevel)
+	lp.LogCollectionRunnerEvent("starting test loop for multimaps", r.name, log.InfoLevel)
+
+	tle := &testLoopExecution{id: uuid.New(), runnerName: r.name, source: r.source, getCollection: r.getMultiMap, runnerConfig: c, ctx: ctx}
+	r.l.init(tle, &defaultSleeper{}, r.gatherer)
+
+	r.appendState(testLoopStart)
+	r.l.run()
+	r.appendState(testLoopComplete)
+
+	lp.LogCollectionRunnerEvent("finished multimap test loop", r.name, log.InfoLevel)
+
+}
+
+func (r *multiMapRunner) getMultiMap(ctx context.Context, name string) (collection, error) {
+
+	mm, err := r.hzMultiMapStore.GetMultiMap(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &multiMapCollection{mm: mm, numValuesPerKey: numValuesPerKey}, nil
+
+}
+
+func (r *multiMapRunner) appendState(s runnerState) {
+
+	r.stateList = append(r.stateList, s)
+	r.gatherer.Updates <- status.Update{Key: string(statusKeyCurrentState), Value: string(s)}
+
+}
+
+func (c *multiMapCollection) key(index int) string {
+	return fmt.Sprintf("k%d", index/c.numValuesPerKey)
+}
+
+func (c *multiMapCollection) add(ctx context.Context, index int, e element) (bool, error) {
+	return c.mm.Put(ctx, c.key(index), e)
+}
+
+func (c *multiMapCollection) contains(ctx context.Context, index int, e element) (bool, error) {
+	return c.mm.ContainsEntry(ctx, c.key(index), e)
+}
+
+func (c *multiMapCollection) remove(ctx context.Context, index int, e element) (bool, error) {
+	return c.mm.RemoveEntry(ctx, c.key(index), e)
+}
+
+func populateMultiMapConfig(assigner client.ConfigPropertyAssigner) (*runnerConfig, error) {
+
+	runnerKeyPath := "collectionTests.multiMap"
+
+	if err := assigner.Assign(runnerKeyPath+".numValuesPerKey", client.ValidateInt, func(a any) {
+		numValuesPerKey = a.(int)
+	}); err != nil {
+		return nil, err
+	}
+
+	return populateConfig(assigner, runnerKeyPath, "multiMap")
+
+}
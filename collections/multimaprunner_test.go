@@ -0,0 +1,193 @@
+package collections
+
+import (
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"testing"
+)
+
+func TestRunMultiMapCollectionTests(t *testing.T) {
+
+	t.Log("given a multimap runner to run collection test loops")
+	{
+		genericMsgStateTransitions := "\t\tstate transitions must be correct"
+		genericMsgLatestStateInGatherer := "\t\tlatest state in gatherer must be correct"
+		t.Log("\twhen runner configuration cannot be populated")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: true,
+				testConfig:  nil,
+			}
+			r := multiMapRunner{assigner: assigner, stateList: []runnerState{}, l: &testRunnerTestLoop{}}
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runCollectionTests(hzCluster, hzMembers, gatherer, testStoreFuncs)
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(gatherer, start) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, start)
+			}
+		}
+		t.Log("\twhen runner has been disabled")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: false,
+				testConfig: map[string]any{
+					"collectionTests.multiMap.enabled": false,
+				},
+			}
+			r := multiMapRunner{assigner: assigner, stateList: []runnerState{}, l: &testRunnerTestLoop{}}
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runCollectionTests(hzCluster, hzMembers, gatherer, testStoreFuncs)
+			gatherer.StopListen()
+
+			latestState := populateConfigComplete
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start, populateConfigComplete}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+		}
+		t.Log("\twhen test loop has executed")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: false,
+				testConfig: map[string]any{
+					"collectionTests.multiMap.enabled":         true,
+					"collectionTests.multiMap.numValuesPerKey": 4,
+				},
+			}
+			ch := &testHzClientHandler{}
+			l := &testRunnerTestLoop{}
+			r := multiMapRunner{assigner: assigner, stateList: []runnerState{}, l: l, hzClientHandler: ch}
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			cs := newTestHzCollectionStore(&testCollectionStoreBehavior{})
+			r.runCollectionTests(hzCluster, hzMembers, gatherer, &storeFuncs{multiMap: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.MultiMapStore {
+				cs.observations.numInitInvocations++
+				return cs
+			}})
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions(expectedStatesForFullRun, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			latestState := expectedStatesForFullRun[len(expectedStatesForFullRun)-1]
+			if latestStatePresentInGatherer(gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+
+			msg := "\t\thazelcast client handler must have initialized hazelcast client once"
+			if ch.initClientInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations)
+			}
+
+			msg = "\t\thazelcast client handler must have performed shutdown of hazelcast client once"
+			if ch.shutdownInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.shutdownInvocations)
+			}
+
+			msg = "\t\tmultimap store must have been initialized once"
+			if cs.observations.numInitInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cs.observations.numInitInvocations)
+			}
+
+			msg = "\t\ttest loop must retrieve multimaps from multimap store"
+			c, err := l.tle.getCollection(l.tle.ctx, "awesome-multimap")
+			if mmc, ok := c.(*multiMapCollection); ok && err == nil && mmc.numValuesPerKey == 4 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, c, err)
+			}
+		}
+	}
+
+}
+
+func TestPopulateMultiMapConfig(t *testing.T) {
+
+	t.Log("given a function for populating the multimap runner config")
+	{
+		t.Log("\twhen number of values per key is a positive number")
+		{
+			assigner := testConfigPropertyAssigner{testConfig: map[string]any{
+				"collectionTests.multiMap.numValuesPerKey": 7,
+			}}
+
+			rc, err := populateMultiMapConfig(assigner)
+
+			msg := "\t\tno error must be returned"
+			if err == nil && rc != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tnumber of values per key must have been assigned"
+			if numValuesPerKey == 7 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, numValuesPerKey)
+			}
+
+			msg = "\t\tcollection base name must be set"
+			if rc.collectionBaseName == "multiMap" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, rc.collectionBaseName)
+			}
+		}
+
+		t.Log("\twhen number of values per key is zero")
+		{
+			assigner := testConfigPropertyAssigner{testConfig: map[string]any{
+				"collectionTests.multiMap.numValuesPerKey": 0,
+			}}
+
+			rc, err := populateMultiMapConfig(assigner)
+
+			msg := "\t\terror must be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
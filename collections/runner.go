@@ -0,0 +1,252 @@
+package collections
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/logging"
+	"hazeltest/status"
+	"sync"
+)
+
+type (
+	CollectionTester struct {
+		HzCluster string
+		HzMembers []string
+	}
+	runner interface {
+		getSourceName() string
+		runCollectionTests(hzCluster string, hzMembers []string, gatherer *status.Gatherer, sf *storeFuncs)
+	}
+	// storeFuncs bundles the functions for initializing the stores of all data structures the collection runners
+	// work with, so the tester can hand the same set to each runner, and each runner picks the one it needs.
+	storeFuncs struct {
+		multiMap initMultiMapStoreFunc
+		list     initListStoreFunc
+		set      initSetStoreFunc
+	}
+	runnerConfig struct {
+		enabled                               bool
+		numCollections                        int
+		collectionBaseName                    string
+		appendCollectionIndexToCollectionName bool
+		appendClientIdToCollectionName        bool
+		useCollectionPrefix                   bool
+		collectionPrefix                      string
+		numRuns                               uint32
+		numElements                           int
+		payloadSizeBytes                      int
+		sleepBetweenRuns                      *sleepConfig
+	}
+	sleepConfig struct {
+		enabled          bool
+		durationMs       int
+		enableRandomness bool
+	}
+	runnerConfigBuilder struct {
+		assigner           client.ConfigPropertyAssigner
+		runnerKeyPath      string
+		collectionBaseName string
+	}
+	runnerState           string
+	statusKey             string
+	initMultiMapStoreFunc func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.MultiMapStore
+	initListStoreFunc     func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.ListStore
+	initSetStoreFunc      func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.SetStore
+)
+
+const (
+	start                  runnerState = "start"
+	populateConfigComplete runnerState = "populateConfigComplete"
+	checkEnabledComplete   runnerState = "checkEnabledComplete"
+	raiseReadyComplete     runnerState = "raiseReadyComplete"
+	testLoopStart          runnerState = "testLoopStart"
+	testLoopComplete       runnerState = "testLoopComplete"
+)
+
+const (
+	statusKeyCurrentState statusKey = "currentState"
+)
+
+var (
+	runners           []runner
+	lp                *logging.LogProvider
+	defaultStoreFuncs = &storeFuncs{
+		multiMap: func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.MultiMapStore {
+			return &hazelcastwrapper.DefaultMultiMapStore{Client: ch.GetClient()}
+		},
+		list: func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.ListStore {
+			return &hazelcastwrapper.DefaultListStore{Client: ch.GetClient()}
+		},
+		set: func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.SetStore {
+			return &hazelcastwrapper.DefaultSetStore{Client: ch.GetClient()}
+		},
+	}
+)
+
+func register(r runner) {
+	runners = append(runners, r)
+}
+
+func init() {
+	lp = logging.GetLogProviderInstance(client.ID())
+}
+
+func (b runnerConfigBuilder) populateConfig() (*runnerConfig, error) {
+
+	var assignmentOps []func() error
+
+	var enabled bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".enabled", client.ValidateBool, func(a any) {
+			enabled = a.(bool)
+		})
+	})
+
+	var numCollections int
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".numCollections", client.ValidateInt, func(a any) {
+			numCollections = a.(int)
+		})
+	})
+
+	var appendCollectionIndexToCollectionName bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".appendCollectionIndexToCollectionName", client.ValidateBool, func(a any) {
+			appendCollectionIndexToCollectionName = a.(bool)
+		})
+	})
+
+	var appendClientIdToCollectionName bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".appendClientIdToCollectionName", client.ValidateBool, func(a any) {
+			appendClientIdToCollectionName = a.(bool)
+		})
+	})
+
+	var useCollectionPrefix bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".collectionPrefix.enabled", client.ValidateBool, func(a any) {
+			useCollectionPrefix = a.(bool)
+		})
+	})
+
+	var collectionPrefix string
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".collectionPrefix.prefix", client.ValidateString, func(a any) {
+			collectionPrefix = a.(string)
+		})
+	})
+
+	var numRuns uint32
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".numRuns", client.ValidateInt, func(a any) {
+			numRuns = uint32(a.(int))
+		})
+	})
+
+	var numElements int
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".numElements", client.ValidateInt, func(a any) {
+			numElements = a.(int)
+		})
+	})
+
+	var payloadSizeBytes int
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".payloadSizeBytes", client.ValidateInt, func(a any) {
+			payloadSizeBytes = a.(int)
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	sleepBetweenRuns, err := b.populateSleepConfig(b.runnerKeyPath + ".sleeps.betweenRuns")
+	if err != nil {
+		return nil, err
+	}
+
+	return &runnerConfig{
+		enabled:                               enabled,
+		numCollections:                        numCollections,
+		collectionBaseName:                    b.collectionBaseName,
+		appendCollectionIndexToCollectionName: appendCollectionIndexToCollectionName,
+		appendClientIdToCollectionName:        appendClientIdToCollectionName,
+		useCollectionPrefix:                   useCollectionPrefix,
+		collectionPrefix:                      collectionPrefix,
+		numRuns:                               numRuns,
+		numElements:                           numElements,
+		payloadSizeBytes:                      payloadSizeBytes,
+		sleepBetweenRuns:                      sleepBetweenRuns,
+	}, nil
+
+}
+
+func (b runnerConfigBuilder) populateSleepConfig(configBasePath string) (*sleepConfig, error) {
+
+	var enabled bool
+	if err := b.assigner.Assign(configBasePath+".enabled", client.ValidateBool, func(a any) {
+		enabled = a.(bool)
+	}); err != nil {
+		return nil, err
+	}
+
+	var durationMs int
+	if err := b.assigner.Assign(configBasePath+".durationMs", client.ValidateInt, func(a any) {
+		durationMs = a.(int)
+	}); err != nil {
+		return nil, err
+	}
+
+	var enableRandomness bool
+	if err := b.assigner.Assign(configBasePath+".enableRandomness", client.ValidateBool, func(a any) {
+		enableRandomness = a.(bool)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &sleepConfig{enabled, durationMs, enableRandomness}, nil
+
+}
+
+func populateConfig(assigner client.ConfigPropertyAssigner, runnerKeyPath string, collectionBaseName string) (*runnerConfig, error) {
+
+	return runnerConfigBuilder{
+		assigner:           assigner,
+		runnerKeyPath:      runnerKeyPath,
+		collectionBaseName: collectionBaseName,
+	}.populateConfig()
+
+}
+
+func (t *CollectionTester) TestCollections() {
+
+	clientID := client.ID()
+	lp.LogInternalStateInfo(fmt.Sprintf("%s: collection tester starting %d runner/-s", clientID, len(runners)), log.InfoLevel)
+
+	var wg sync.WaitGroup
+	for i := 0; i < len(runners); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+			defer gatherer.StopListen()
+
+			runner := runners[i]
+
+			api.RegisterStatefulActor(api.CollectionRunners, runner.getSourceName(), gatherer.AssembleStatusCopy)
+			runner.runCollectionTests(t.HzCluster, t.HzMembers, gatherer, defaultStoreFuncs)
+		}(i)
+	}
+
+	wg.Wait()
+
+}
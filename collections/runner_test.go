@@ -0,0 +1,145 @@
+package collections
+
+import (
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"testing"
+)
+
+var (
+	testConfig = map[string]any{
+		runnerKeyPath + ".enabled":                               true,
+		runnerKeyPath + ".numCollections":                        5,
+		runnerKeyPath + ".appendCollectionIndexToCollectionName": true,
+		runnerKeyPath + ".appendClientIdToCollectionName":        false,
+		runnerKeyPath + ".collectionPrefix.enabled":              true,
+		runnerKeyPath + ".collectionPrefix.prefix":               collectionPrefix,
+		runnerKeyPath + ".numRuns":                               500,
+		runnerKeyPath + ".numElements":                           100,
+		runnerKeyPath + ".payloadSizeBytes":                      10,
+		runnerKeyPath + ".sleeps.betweenRuns.enabled":            true,
+		runnerKeyPath + ".sleeps.betweenRuns.durationMs":         2000,
+		runnerKeyPath + ".sleeps.betweenRuns.enableRandomness":   true,
+	}
+	testStoreFuncs = &storeFuncs{
+		multiMap: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.MultiMapStore {
+			return newTestHzCollectionStore(&testCollectionStoreBehavior{})
+		},
+		list: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.ListStore {
+			return newTestHzCollectionStore(&testCollectionStoreBehavior{})
+		},
+		set: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.SetStore {
+			return newTestHzCollectionStore(&testCollectionStoreBehavior{})
+		},
+	}
+)
+
+func waitForStatusGatheringDone(g *status.Gatherer) {
+
+	for {
+		if done := g.ListeningStopped(); done {
+			return
+		}
+	}
+
+}
+
+func latestStatePresentInGatherer(g *status.Gatherer, desiredState runnerState) bool {
+
+	if value, ok := g.AssembleStatusCopy()[string(statusKeyCurrentState)]; ok && value == string(desiredState) {
+		return true
+	}
+
+	return false
+
+}
+
+func TestPopulateConfig(t *testing.T) {
+
+	t.Log("given a function for populating collection runner configs")
+	{
+		b := runnerConfigBuilder{runnerKeyPath: runnerKeyPath, collectionBaseName: collectionBaseName}
+		t.Log("\twhen property assignment does not generate an error")
+		{
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfig}
+			rc, err := b.populateConfig()
+
+			msg := "\t\tno error should be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tconfig should contain expected values"
+			if configValuesAsExpected(rc, testConfig) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Error(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen property assigning a property yields an error")
+		{
+			b.assigner = testConfigPropertyAssigner{returnError: true, testConfig: map[string]any{}}
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror should be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Error(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen number of elements is not a positive number")
+		{
+			testConfigCopy := copyTestConfig()
+			testConfigCopy[runnerKeyPath+".numElements"] = 0
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror must be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func copyTestConfig() map[string]any {
+
+	testConfigCopy := make(map[string]any, len(testConfig))
+	for k, v := range testConfig {
+		testConfigCopy[k] = v
+	}
+
+	return testConfigCopy
+
+}
+
+func configValuesAsExpected(rc *runnerConfig, expected map[string]any) bool {
+
+	if rc == nil {
+		return false
+	}
+
+	return rc.enabled == expected[runnerKeyPath+".enabled"] &&
+		rc.numCollections == expected[runnerKeyPath+".numCollections"] &&
+		rc.collectionBaseName == collectionBaseName &&
+		rc.appendCollectionIndexToCollectionName == expected[runnerKeyPath+".appendCollectionIndexToCollectionName"] &&
+		rc.appendClientIdToCollectionName == expected[runnerKeyPath+".appendClientIdToCollectionName"] &&
+		rc.useCollectionPrefix == expected[runnerKeyPath+".collectionPrefix.enabled"] &&
+		rc.collectionPrefix == expected[runnerKeyPath+".collectionPrefix.prefix"] &&
+		rc.numRuns == uint32(expected[runnerKeyPath+".numRuns"].(int)) &&
+		rc.numElements == expected[runnerKeyPath+".numElements"] &&
+		rc.payloadSizeBytes == expected[runnerKeyPath+".payloadSizeBytes"] &&
+		rc.sleepBetweenRuns.enabled == expected[runnerKeyPath+".sleeps.betweenRuns.enabled"] &&
+		rc.sleepBetweenRuns.durationMs == expected[runnerKeyPath+".sleeps.betweenRuns.durationMs"] &&
+		rc.sleepBetweenRuns.enableRandomness == expected[runnerKeyPath+".sleeps.betweenRuns.enableRandomness"]
+
+}
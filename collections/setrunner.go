@@ -0,0 +1,118 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+)
+
+type (
+	setRunner struct {
+		assigner        client.ConfigPropertyAssigner
+		stateList       []runnerState
+		name            string
+		source          string
+		hzClientHandler hazelcastwrapper.HzClientHandler
+		hzSetStore      hazelcastwrapper.SetStore
+		l               looper
+		gatherer        *status.Gatherer
+	}
+	setCollection struct {
+		s hazelcastwrapper.Set
+	}
+)
+
+func init() {
+	register(&setRunner{
+		assigner:        &client.DefaultConfigPropertyAssigner{},
+		stateList:       []runnerState{},
+		name:            "collectionsSetRunner",
+		source:          "setRunner",
+		hzClientHandler: &hazelcastwrapper.DefaultHzClientHandler{},
+		l:               &testLoop{},
+	})
+}
+
+func (r *setRunner) getSourceName() string {
+	return r.source
+}
+
+func (r *setRunner) runCollectionTests(hzCluster string, hzMembers []string, gatherer *status.Gatherer, sf *storeFuncs) {
+
+	r.gatherer = gatherer
+	r.appendState(start)
+
+	c, err := populateConfig(r.assigner, "collectionTests.set", "set")
+	if err != nil {
+		lp.LogCollectionRunnerEvent(fmt.Sprintf("aborting launch of set runner: unable to populate config due to error: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.appendState(populateConfigComplete)
+
+	if !c.enabled {
+		lp.LogCollectionRunnerEvent("set runner not enabled -- won't run", r.name, log.InfoLevel)
+		return
+	}
+	r.appendState(checkEnabledComplete)
+
+	api.RaiseNotReady()
+
+	ctx := context.TODO()
+
+	r.hzClientHandler.InitHazelcastClient(ctx, r.name, hzCluster, hzMembers)
+	defer func() {
+		_ = r.hzClientHandler.Shutdown(ctx)
+	}()
+	r.hzSetStore = sf.set(r.hzClientHandler)
+
+	api.RaiseReady()
+	r.appendState(raiseReadyComplete)
+
+	lp.LogCollectionRunnerEvent("initialized hazelcast client", r.name, log.InfoLevel)
+	lp.LogCollectionRunnerEvent("starting test loop for sets", r.name, log.InfoLevel)
+
+	tle := &testLoopExecution{id: uuid.New(), runnerName: r.name, source: r.source, getCollection: r.getSet, runnerConfig: c, ctx: ctx}
+	r.l.init(tle, &defaultSleeper{}, r.gatherer)
+
+	r.appendState(testLoopStart)
+	r.l.run()
+	r.appendState(testLoopComplete)
+
+	lp.LogCollectionRunnerEvent("finished set test loop", r.name, log.InfoLevel)
+
+}
+
+func (r *setRunner) getSet(ctx context.Context, name string) (collection, error) {
+
+	s, err := r.hzSetStore.GetSet(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &setCollection{s}, nil
+
+}
+
+func (r *setRunner) appendState(s runnerState) {
+
+	r.stateList = append(r.stateList, s)
+	r.gatherer.Updates <- status.Update{Key: string(statusKeyCurrentState), Value: string(s)}
+
+}
+
+func (c *setCollection) add(ctx context.Context, _ int, e element) (bool, error) {
+	return c.s.Add(ctx, e)
+}
+
+func (c *setCollection) contains(ctx context.Context, _ int, e element) (bool, error) {
+	return c.s.Contains(ctx, e)
+}
+
+func (c *setCollection) remove(ctx context.Context, _ int, e element) (bool, error) {
+	return c.s.Remove(ctx, e)
+}
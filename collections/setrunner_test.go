@@ -0,0 +1,139 @@
+package collections
+
+import (
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"testing"
+)
+
+func TestRunSetCollectionTests(t *testing.T) {
+
+	t.Log("given a set runner to run collection test loops")
+	{
+		genericMsgStateTransitions := "\t\tstate transitions must be correct"
+		genericMsgLatestStateInGatherer := "\t\tlatest state in gatherer must be correct"
+		t.Log("\twhen runner configuration cannot be populated")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: true,
+				testConfig:  nil,
+			}
+			r := setRunner{assigner: assigner, stateList: []runnerState{}, l: &testRunnerTestLoop{}}
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runCollectionTests(hzCluster, hzMembers, gatherer, testStoreFuncs)
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(gatherer, start) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, start)
+			}
+		}
+		t.Log("\twhen runner has been disabled")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: false,
+				testConfig: map[string]any{
+					"collectionTests.set.enabled": false,
+				},
+			}
+			r := setRunner{assigner: assigner, stateList: []runnerState{}, l: &testRunnerTestLoop{}}
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runCollectionTests(hzCluster, hzMembers, gatherer, testStoreFuncs)
+			gatherer.StopListen()
+
+			latestState := populateConfigComplete
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start, populateConfigComplete}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+		}
+		t.Log("\twhen test loop has executed")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: false,
+				testConfig: map[string]any{
+					"collectionTests.set.enabled": true,
+				},
+			}
+			ch := &testHzClientHandler{}
+			l := &testRunnerTestLoop{}
+			r := setRunner{assigner: assigner, stateList: []runnerState{}, l: l, hzClientHandler: ch}
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			cs := newTestHzCollectionStore(&testCollectionStoreBehavior{})
+			r.runCollectionTests(hzCluster, hzMembers, gatherer, &storeFuncs{set: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.SetStore {
+				cs.observations.numInitInvocations++
+				return cs
+			}})
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions(expectedStatesForFullRun, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			latestState := expectedStatesForFullRun[len(expectedStatesForFullRun)-1]
+			if latestStatePresentInGatherer(gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+
+			msg := "\t\thazelcast client handler must have initialized hazelcast client once"
+			if ch.initClientInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations)
+			}
+
+			msg = "\t\thazelcast client handler must have performed shutdown of hazelcast client once"
+			if ch.shutdownInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.shutdownInvocations)
+			}
+
+			msg = "\t\tset store must have been initialized once"
+			if cs.observations.numInitInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cs.observations.numInitInvocations)
+			}
+
+			msg = "\t\ttest loop must retrieve sets from set store"
+			c, err := l.tle.getCollection(l.tle.ctx, "awesome-set")
+			if _, ok := c.(*setCollection); ok && err == nil && !cs.collections["awesome-set"].allowDuplicates {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, c, err)
+			}
+		}
+	}
+
+}
@@ -0,0 +1,298 @@
+package collections
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/client"
+	"hazeltest/loadsupport"
+	"hazeltest/status"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type (
+	evaluateTimeToSleep func(sc *sleepConfig) int
+	looper              interface {
+		init(tle *testLoopExecution, s sleeper, g *status.Gatherer)
+		run()
+	}
+	sleeper interface {
+		sleep(sc *sleepConfig, sf evaluateTimeToSleep, kind, collectionName, runnerName string)
+	}
+	counterTracker interface {
+		init(gatherer *status.Gatherer)
+		increaseCounter(sk statusKey)
+	}
+	// collection abstracts over the operations the test loop performs on its payload data structures, so the same
+	// loop can be run against multimaps, lists, and sets. Alongside each element, the element's index within the test
+	// loop's set of elements is provided for the benefit of data structures that need to derive a key from it.
+	collection interface {
+		add(ctx context.Context, index int, e element) (bool, error)
+		contains(ctx context.Context, index int, e element) (bool, error)
+		remove(ctx context.Context, index int, e element) (bool, error)
+	}
+	getCollectionFunc func(ctx context.Context, name string) (collection, error)
+	testLoop          struct {
+		tle      *testLoopExecution
+		s        sleeper
+		gatherer *status.Gatherer
+		ct       counterTracker
+	}
+	testLoopExecution struct {
+		id            uuid.UUID
+		runnerName    string
+		source        string
+		getCollection getCollectionFunc
+		runnerConfig  *runnerConfig
+		ctx           context.Context
+	}
+	// element is what gets stored in the payload data structures. Its ID is unique across all collection goroutines
+	// of all Hazeltest instances, so elements can be told apart even if a payload data structure is shared, and, in
+	// case of sets, none of them gets rejected as a duplicate of another one.
+	element struct {
+		ID      string
+		Payload string
+	}
+	defaultSleeper                    struct{}
+	collectionTestLoopCountersTracker struct {
+		counters map[statusKey]int
+		l        sync.Mutex
+		gatherer *status.Gatherer
+	}
+)
+
+const (
+	statusKeyNumCollections = "numCollections"
+	statusKeyNumRuns        = "numRuns"
+	statusKeyTotalNumRuns   = "totalNumRuns"
+	statusKeyNumElements    = "numElements"
+)
+
+const (
+	statusKeyNumAddedElements        statusKey = "numAddedElements"
+	statusKeyNumFailedAdds           statusKey = "numFailedAdds"
+	statusKeyNumRejectedAdds         statusKey = "numRejectedAdds"
+	statusKeyNumFailedContainsChecks statusKey = "numFailedContainsChecks"
+	statusKeyNumMissingElements      statusKey = "numMissingElements"
+	statusKeyNumRemovedElements      statusKey = "numRemovedElements"
+	statusKeyNumFailedRemoves        statusKey = "numFailedRemoves"
+)
+
+const (
+	collectionOperationLoggingUpdateStep = 10
+)
+
+var (
+	sleepTimeFunc evaluateTimeToSleep = func(sc *sleepConfig) int {
+		var sleepDuration int
+		if sc.enableRandomness {
+			sleepDuration = rand.Intn(sc.durationMs + 1)
+		} else {
+			sleepDuration = sc.durationMs
+		}
+		return sleepDuration
+	}
+	counters = []statusKey{statusKeyNumAddedElements, statusKeyNumFailedAdds, statusKeyNumRejectedAdds, statusKeyNumFailedContainsChecks, statusKeyNumMissingElements, statusKeyNumRemovedElements, statusKeyNumFailedRemoves}
+)
+
+func init() {
+	gob.Register(element{})
+}
+
+func (ct *collectionTestLoopCountersTracker) init(gatherer *status.Gatherer) {
+	ct.gatherer = gatherer
+
+	ct.counters = make(map[statusKey]int)
+
+	initialCounterValue := 0
+	for _, v := range counters {
+		ct.counters[v] = initialCounterValue
+		gatherer.Updates <- status.Update{Key: string(v), Value: initialCounterValue}
+	}
+
+}
+
+func (ct *collectionTestLoopCountersTracker) increaseCounter(sk statusKey) {
+
+	var newValue int
+	ct.l.Lock()
+	{
+		newValue = ct.counters[sk] + 1
+		ct.counters[sk] = newValue
+	}
+	ct.l.Unlock()
+
+	ct.gatherer.Updates <- status.Update{Key: string(sk), Value: newValue}
+
+}
+
+func (l *testLoop) init(tle *testLoopExecution, s sleeper, g *status.Gatherer) {
+	l.tle = tle
+	l.s = s
+	l.gatherer = g
+
+	ct := &collectionTestLoopCountersTracker{}
+	ct.init(g)
+	l.ct = ct
+}
+
+func (l *testLoop) run() {
+
+	l.insertLoopWithInitialStatus()
+
+	var numCollectionsWg sync.WaitGroup
+	for i := 0; i < l.tle.runnerConfig.numCollections; i++ {
+		numCollectionsWg.Add(1)
+		go func(i int) {
+			defer numCollectionsWg.Done()
+			l.runForCollection(i)
+		}(i)
+	}
+
+	numCollectionsWg.Wait()
+
+}
+
+func (l *testLoop) runForCollection(collectionNumber int) {
+
+	rc := l.tle.runnerConfig
+
+	collectionName := l.assembleCollectionName(collectionNumber)
+	lp.LogCollectionRunnerEvent(fmt.Sprintf("using collection name '%s' in collection goroutine %d", collectionName, collectionNumber), l.tle.runnerName, log.InfoLevel)
+	start := time.Now()
+	c, err := l.tle.getCollection(l.tle.ctx, collectionName)
+	if err != nil {
+		lp.LogCollectionRunnerEvent(fmt.Sprintf("unable to retrieve collection '%s' from hazelcast cluster -- aborting collection goroutine %d: %s", collectionName, collectionNumber, err), l.tle.runnerName, log.ErrorLevel)
+		return
+	}
+	elapsed := time.Since(start).Milliseconds()
+	lp.LogTimingEvent("getCollection()", collectionName, int(elapsed), log.InfoLevel)
+
+	elements := l.assembleElements(collectionNumber)
+
+	for i := uint32(0); i < rc.numRuns; i++ {
+		if i > 0 && i%collectionOperationLoggingUpdateStep == 0 {
+			lp.LogCollectionRunnerEvent(fmt.Sprintf("finished %d of %d runs for collection %s in collection goroutine %d", i, rc.numRuns, collectionName, collectionNumber), l.tle.runnerName, log.InfoLevel)
+		}
+		l.addElements(c, collectionName, elements)
+		l.verifyElements(c, collectionName, elements)
+		l.removeElements(c, collectionName, elements)
+		l.s.sleep(rc.sleepBetweenRuns, sleepTimeFunc, "betweenRuns", collectionName, l.tle.runnerName)
+	}
+
+	lp.LogCollectionRunnerEvent(fmt.Sprintf("collection test loop done on collection '%s' in collection goroutine %d", collectionName, collectionNumber), l.tle.runnerName, log.InfoLevel)
+
+}
+
+func (l *testLoop) addElements(c collection, collectionName string, elements []element) {
+
+	for i, e := range elements {
+		added, err := c.add(l.tle.ctx, i, e)
+		if err != nil {
+			l.ct.increaseCounter(statusKeyNumFailedAdds)
+			lp.LogCollectionRunnerEvent(fmt.Sprintf("unable to add element '%s' to collection '%s': %s", e.ID, collectionName, err), l.tle.runnerName, log.WarnLevel)
+		} else if !added {
+			// Elements get removed at the end of each run, so an element rejected as already present is left over
+			// from an earlier run whose removal did not go through
+			l.ct.increaseCounter(statusKeyNumRejectedAdds)
+			lp.LogCollectionRunnerEvent(fmt.Sprintf("collection '%s' rejected addition of element '%s'", collectionName, e.ID), l.tle.runnerName, log.WarnLevel)
+		} else {
+			l.ct.increaseCounter(statusKeyNumAddedElements)
+		}
+	}
+
+}
+
+func (l *testLoop) verifyElements(c collection, collectionName string, elements []element) {
+
+	for i, e := range elements {
+		found, err := c.contains(l.tle.ctx, i, e)
+		if err != nil {
+			l.ct.increaseCounter(statusKeyNumFailedContainsChecks)
+			lp.LogCollectionRunnerEvent(fmt.Sprintf("unable to check whether collection '%s' contains element '%s': %s", collectionName, e.ID, err), l.tle.runnerName, log.WarnLevel)
+		} else if !found {
+			l.ct.increaseCounter(statusKeyNumMissingElements)
+			lp.LogCollectionRunnerEvent(fmt.Sprintf("element '%s' previously added to collection '%s' is missing", e.ID, collectionName), l.tle.runnerName, log.WarnLevel)
+		}
+	}
+
+}
+
+func (l *testLoop) removeElements(c collection, collectionName string, elements []element) {
+
+	for i, e := range elements {
+		removed, err := c.remove(l.tle.ctx, i, e)
+		if err != nil {
+			l.ct.increaseCounter(statusKeyNumFailedRemoves)
+			lp.LogCollectionRunnerEvent(fmt.Sprintf("unable to remove element '%s' from collection '%s': %s", e.ID, collectionName, err), l.tle.runnerName, log.WarnLevel)
+		} else if removed {
+			l.ct.increaseCounter(statusKeyNumRemovedElements)
+		}
+		// Elements not removed because they could not be found were already reported by the preceding verification
+	}
+
+}
+
+func (l *testLoop) assembleElements(collectionNumber int) []element {
+
+	rc := l.tle.runnerConfig
+
+	elements := make([]element, rc.numElements)
+
+	randomPayload := loadsupport.GenerateRandomStringPayload(rc.payloadSizeBytes)
+
+	for i := 0; i < rc.numElements; i++ {
+		elements[i] = element{
+			ID:      fmt.Sprintf("%s-%s-%d-%d", client.ID(), l.tle.runnerName, collectionNumber, i),
+			Payload: randomPayload,
+		}
+	}
+
+	return elements
+
+}
+
+func (l *testLoop) insertLoopWithInitialStatus() {
+
+	rc := l.tle.runnerConfig
+
+	l.gatherer.Updates <- status.Update{Key: statusKeyNumCollections, Value: rc.numCollections}
+	l.gatherer.Updates <- status.Update{Key: statusKeyNumRuns, Value: rc.numRuns}
+	l.gatherer.Updates <- status.Update{Key: statusKeyTotalNumRuns, Value: uint32(rc.numCollections) * rc.numRuns}
+	l.gatherer.Updates <- status.Update{Key: statusKeyNumElements, Value: rc.numElements}
+
+}
+
+func (l *testLoop) assembleCollectionName(collectionIndex int) string {
+
+	rc := l.tle.runnerConfig
+
+	collectionName := rc.collectionBaseName
+
+	if rc.useCollectionPrefix && rc.collectionPrefix != "" {
+		collectionName = fmt.Sprintf("%s%s", rc.collectionPrefix, collectionName)
+	}
+	if rc.appendCollectionIndexToCollectionName {
+		collectionName = fmt.Sprintf("%s-%d", collectionName, collectionIndex)
+	}
+	if rc.appendClientIdToCollectionName {
+		collectionName = fmt.Sprintf("%s-%s", collectionName, client.ID())
+	}
+
+	return collectionName
+
+}
+
+func (s *defaultSleeper) sleep(sc *sleepConfig, sf evaluateTimeToSleep, kind, collectionName, runnerName string) {
+
+	if sc.enabled {
+		sleepDuration := sf(sc)
+		lp.LogCollectionRunnerEvent(fmt.Sprintf("sleeping for %d milliseconds for kind '%s' on collection '%s'", sleepDuration, kind, collectionName), runnerName, log.TraceLevel)
+		time.Sleep(time.Duration(sleepDuration) * time.Millisecond)
+	}
+
+}
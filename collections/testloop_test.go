@@ -0,0 +1,349 @@
+package collections
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"hazeltest/status"
+	"strings"
+	"testing"
+)
+
+const (
+	testNumCollections  = 3
+	testNumRuns         = 4
+	testNumElements     = 10
+	testNumValuesPerKey = 3
+)
+
+// collectionKinds maps each kind of collection to a function retrieving it from the given store the same way the
+// corresponding runner does, so the test loop can be tested against all of them.
+var collectionKinds = map[string]func(cs *testHzCollectionStore) getCollectionFunc{
+	"multiMap": func(cs *testHzCollectionStore) getCollectionFunc {
+		return func(ctx context.Context, name string) (collection, error) {
+			mm, err := cs.GetMultiMap(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			return &multiMapCollection{mm: mm, numValuesPerKey: testNumValuesPerKey}, nil
+		}
+	},
+	"list": func(cs *testHzCollectionStore) getCollectionFunc {
+		return func(ctx context.Context, name string) (collection, error) {
+			l, err := cs.GetList(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			return &listCollection{l}, nil
+		}
+	},
+	"set": func(cs *testHzCollectionStore) getCollectionFunc {
+		return func(ctx context.Context, name string) (collection, error) {
+			s, err := cs.GetSet(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			return &setCollection{s}, nil
+		}
+	},
+}
+
+func TestRunTestLoop(t *testing.T) {
+
+	t.Log("given a collection test loop")
+	{
+		t.Log("\twhen all operations are successful")
+		{
+			for kind, f := range collectionKinds {
+				cs := newTestHzCollectionStore(&testCollectionStoreBehavior{})
+				l, s, g := assembleTestLoop(f(cs), assembleTestLoopRunnerConfig())
+
+				l.run()
+				ct := finishTestLoop(l, g)
+
+				numExpected := testNumCollections * testNumRuns * testNumElements
+				msg := "\t\tall elements must have been added and removed again"
+				if ct.counters[statusKeyNumAddedElements] == numExpected && ct.counters[statusKeyNumRemovedElements] == numExpected {
+					t.Log(msg, checkMark, kind)
+				} else {
+					t.Fatal(msg, ballotX, kind, ct.counters)
+				}
+
+				msg = "\t\tno element must have been reported as failed, rejected, or missing"
+				if ct.counters[statusKeyNumFailedAdds] == 0 && ct.counters[statusKeyNumRejectedAdds] == 0 &&
+					ct.counters[statusKeyNumFailedContainsChecks] == 0 && ct.counters[statusKeyNumMissingElements] == 0 &&
+					ct.counters[statusKeyNumFailedRemoves] == 0 {
+					t.Log(msg, checkMark, kind)
+				} else {
+					t.Fatal(msg, ballotX, kind, ct.counters)
+				}
+
+				msg = "\t\teach element must have been checked for presence"
+				if cs.sumInvocations(func(c *testHzCollection) int { return c.containsInvocations }) == numExpected {
+					t.Log(msg, checkMark, kind)
+				} else {
+					t.Fatal(msg, ballotX, kind)
+				}
+
+				msg = "\t\tcollections must be empty after test loop has finished"
+				if len(cs.collections) == testNumCollections && cs.sumInvocations(func(c *testHzCollection) int { return len(c.entries) }) == 0 {
+					t.Log(msg, checkMark, kind)
+				} else {
+					t.Fatal(msg, ballotX, kind)
+				}
+
+				msg = "\t\tbetween-runs sleep must have been invoked once per run"
+				if s.numSleeps("betweenRuns") == testNumCollections*testNumRuns {
+					t.Log(msg, checkMark, kind)
+				} else {
+					t.Fatal(msg, ballotX, kind, s.numSleeps("betweenRuns"))
+				}
+			}
+		}
+
+		t.Log("\twhen collections lose elements")
+		{
+			for kind, f := range collectionKinds {
+				cs := newTestHzCollectionStore(&testCollectionStoreBehavior{loseEveryNth: 5})
+				l, _, g := assembleTestLoop(f(cs), assembleTestLoopRunnerConfig())
+
+				l.run()
+				ct := finishTestLoop(l, g)
+
+				numLost := testNumCollections * testNumRuns * testNumElements / 5
+				msg := "\t\teach element lost must have been reported as missing"
+				if ct.counters[statusKeyNumMissingElements] == numLost {
+					t.Log(msg, checkMark, kind)
+				} else {
+					t.Fatal(msg, ballotX, kind, ct.counters[statusKeyNumMissingElements])
+				}
+
+				msg = "\t\telements lost must not have been reported as removed"
+				if ct.counters[statusKeyNumRemovedElements] == testNumCollections*testNumRuns*testNumElements-numLost {
+					t.Log(msg, checkMark, kind)
+				} else {
+					t.Fatal(msg, ballotX, kind, ct.counters[statusKeyNumRemovedElements])
+				}
+			}
+		}
+
+		t.Log("\twhen elements are left over from previous run")
+		{
+			for kind, f := range collectionKinds {
+				cs := newTestHzCollectionStore(&testCollectionStoreBehavior{returnErrorUponRemove: true})
+				l, _, g := assembleTestLoop(f(cs), assembleTestLoopRunnerConfig())
+
+				l.run()
+				ct := finishTestLoop(l, g)
+
+				msg := "\t\tall removes must have been reported as failed"
+				if ct.counters[statusKeyNumFailedRemoves] == testNumCollections*testNumRuns*testNumElements {
+					t.Log(msg, checkMark, kind)
+				} else {
+					t.Fatal(msg, ballotX, kind, ct.counters[statusKeyNumFailedRemoves])
+				}
+
+				numRejected := 0
+				if kind != "list" {
+					numRejected = testNumCollections * (testNumRuns - 1) * testNumElements
+				}
+				msg = "\t\tadds of elements still present must have been reported as rejected, except for lists"
+				if ct.counters[statusKeyNumRejectedAdds] == numRejected {
+					t.Log(msg, checkMark, kind)
+				} else {
+					t.Fatal(msg, ballotX, kind, ct.counters[statusKeyNumRejectedAdds])
+				}
+			}
+		}
+
+		t.Log("\twhen add and contains operations fail")
+		{
+			cs := newTestHzCollectionStore(&testCollectionStoreBehavior{returnErrorUponAdd: true, returnErrorUponContains: true})
+			l, _, g := assembleTestLoop(collectionKinds["set"](cs), assembleTestLoopRunnerConfig())
+
+			l.run()
+			ct := finishTestLoop(l, g)
+
+			numExpected := testNumCollections * testNumRuns * testNumElements
+			msg := "\t\tall adds and contains checks must have been reported as failed"
+			if ct.counters[statusKeyNumFailedAdds] == numExpected && ct.counters[statusKeyNumFailedContainsChecks] == numExpected {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters)
+			}
+
+			msg = "\t\tfailed contains checks must not have been reported as missing elements"
+			if ct.counters[statusKeyNumMissingElements] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters[statusKeyNumMissingElements])
+			}
+		}
+
+		t.Log("\twhen retrieval of collections fails")
+		{
+			cs := newTestHzCollectionStore(&testCollectionStoreBehavior{returnErrorUponGetCollection: true})
+			l, s, g := assembleTestLoop(collectionKinds["list"](cs), assembleTestLoopRunnerConfig())
+
+			l.run()
+			ct := finishTestLoop(l, g)
+
+			msg := "\t\tno operations must have been performed"
+			if ct.counters[statusKeyNumAddedElements] == 0 && ct.counters[statusKeyNumFailedAdds] == 0 && s.numSleeps("betweenRuns") == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters)
+			}
+		}
+
+		t.Log("\twhen test loop has finished")
+		{
+			cs := newTestHzCollectionStore(&testCollectionStoreBehavior{})
+			l, _, g := assembleTestLoop(collectionKinds["list"](cs), assembleTestLoopRunnerConfig())
+
+			l.run()
+			finishTestLoop(l, g)
+
+			msg := "\t\tinitial status must have been reported"
+			sc := g.AssembleStatusCopy()
+			if sc[statusKeyNumCollections] == testNumCollections && sc[statusKeyNumRuns] == uint32(testNumRuns) &&
+				sc[statusKeyTotalNumRuns] == uint32(testNumCollections*testNumRuns) && sc[statusKeyNumElements] == testNumElements {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, sc)
+			}
+		}
+	}
+
+}
+
+func TestMultiMapCollectionKey(t *testing.T) {
+
+	t.Log("given a multimap collection configured to store a number of values per key")
+	{
+		c := &multiMapCollection{numValuesPerKey: testNumValuesPerKey}
+
+		t.Log("\twhen keys are derived for consecutive element indices")
+		{
+			msg := "\t\tconsecutive elements must share a key until number of values per key has been reached"
+			if c.key(0) == c.key(testNumValuesPerKey-1) && c.key(testNumValuesPerKey-1) != c.key(testNumValuesPerKey) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, c.key(0), c.key(testNumValuesPerKey-1), c.key(testNumValuesPerKey))
+			}
+		}
+	}
+
+}
+
+func TestAssembleElements(t *testing.T) {
+
+	t.Log("given a collection test loop")
+	{
+		t.Log("\twhen elements are assembled for two collection goroutines")
+		{
+			l, _, g := assembleTestLoop(nil, assembleTestLoopRunnerConfig())
+			finishTestLoop(l, g)
+
+			ids := make(map[string]struct{})
+			for _, e := range append(l.assembleElements(0), l.assembleElements(1)...) {
+				ids[e.ID] = struct{}{}
+			}
+
+			msg := "\t\telement IDs must be unique across collection goroutines"
+			if len(ids) == 2*testNumElements {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, len(ids))
+			}
+		}
+	}
+
+}
+
+func TestAssembleCollectionName(t *testing.T) {
+
+	t.Log("given a collection test loop and a collection index")
+	{
+		t.Log("\twhen prefix and collection index are configured to be part of the collection name")
+		{
+			l := &testLoop{tle: &testLoopExecution{runnerConfig: assembleTestLoopRunnerConfig()}}
+
+			collectionName := l.assembleCollectionName(7)
+
+			msg := "\t\tcollection name must contain prefix, base name, and index"
+			if collectionName == collectionPrefix+collectionBaseName+"-7" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, collectionName)
+			}
+		}
+
+		t.Log("\twhen client ID is configured to be part of the collection name")
+		{
+			rc := assembleTestLoopRunnerConfig()
+			rc.useCollectionPrefix = false
+			rc.appendCollectionIndexToCollectionName = false
+			rc.appendClientIdToCollectionName = true
+			l := &testLoop{tle: &testLoopExecution{runnerConfig: rc}}
+
+			collectionName := l.assembleCollectionName(7)
+
+			msg := "\t\tcollection name must contain base name and client ID"
+			if strings.HasPrefix(collectionName, collectionBaseName+"-") && !strings.HasSuffix(collectionName, "-7") {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, collectionName)
+			}
+		}
+	}
+
+}
+
+func assembleTestLoop(f getCollectionFunc, rc *runnerConfig) (*testLoop, *testSleeper, *status.Gatherer) {
+
+	tle := &testLoopExecution{
+		id:            uuid.New(),
+		runnerName:    "collectionsTestRunner",
+		source:        "testRunner",
+		getCollection: f,
+		runnerConfig:  rc,
+		ctx:           context.TODO(),
+	}
+
+	g := status.NewGatherer()
+	go g.Listen()
+
+	s := &testSleeper{}
+	l := &testLoop{}
+	l.init(tle, s, g)
+
+	return l, s, g
+
+}
+
+func finishTestLoop(l *testLoop, g *status.Gatherer) *collectionTestLoopCountersTracker {
+
+	g.StopListen()
+	waitForStatusGatheringDone(g)
+
+	return l.ct.(*collectionTestLoopCountersTracker)
+
+}
+
+func assembleTestLoopRunnerConfig() *runnerConfig {
+
+	return &runnerConfig{
+		enabled:                               true,
+		numCollections:                        testNumCollections,
+		collectionBaseName:                    collectionBaseName,
+		appendCollectionIndexToCollectionName: true,
+		appendClientIdToCollectionName:        false,
+		useCollectionPrefix:                   true,
+		collectionPrefix:                      collectionPrefix,
+		numRuns:                               testNumRuns,
+		numElements:                           testNumElements,
+		payloadSizeBytes:                      10,
+		sleepBetweenRuns:                      &sleepConfig{enabled: true},
+	}
+
+}
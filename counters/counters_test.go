@@ -0,0 +1,538 @@
+package counters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hazelcast/hazelcast-go-client"
+	"github.com/hazelcast/hazelcast-go-client/hzerrors"
+	"github.com/hazelcast/hazelcast-go-client/predicate"
+	"github.com/hazelcast/hazelcast-go-client/types"
+	"hazeltest/hazelcastwrapper"
+	"sync"
+	"time"
+)
+
+type (
+	testConfigPropertyAssigner struct {
+		returnError bool
+		testConfig  map[string]any
+	}
+	testHzPNCounterStore struct {
+		counters     map[string]*testHzPNCounter
+		behavior     *testPNCounterBehavior
+		observations *testStoreObservations
+		l            sync.Mutex
+	}
+	testHzPNCounter struct {
+		value                int64
+		numOperations        int
+		addAndGetInvocations int
+		getInvocations       int
+		resetInvocations     int
+		consistencyLost      bool
+		behavior             *testPNCounterBehavior
+		l                    sync.Mutex
+	}
+	testPNCounterBehavior struct {
+		returnErrorUponGetPNCounter, returnErrorUponGet bool
+		// Operations whose (1-based) number is a multiple of the given value return an error. Zero disables the
+		// behavior.
+		failEveryNth int
+		// Whether operations returning an error have been applied nonetheless, as can happen if a member dies after
+		// having applied the operation, but before having responded.
+		applyFailedOperations bool
+		// Operations whose (1-based) number is a multiple of the given value are acknowledged, but not applied. Zero
+		// disables the behavior.
+		loseEveryNth int
+		// The (1-based) number of the operation upon which the counter loses consistency, after which all operations
+		// return an error until the counter gets reset. Zero disables the behavior.
+		loseConsistencyUponNth int
+	}
+	testHzMapStore struct {
+		maps         map[string]*testHzMap
+		behavior     *testMapBehavior
+		observations *testStoreObservations
+		l            sync.Mutex
+	}
+	testHzMap struct {
+		data               map[any]any
+		locks              map[any]bool
+		setInvocations     int
+		tryLockInvocations int
+		unlockInvocations  int
+		behavior           *testMapBehavior
+		l                  sync.Mutex
+	}
+	testMapBehavior struct {
+		returnErrorUponGetMap, returnErrorUponSet, returnErrorUponTryLock bool
+		// Contributions without uncertainty are only published once all operations of a run have been applied, so
+		// failing them simulates Hazeltest instances dying before their contributions have been confirmed
+		returnErrorUponSetContribution, returnErrorUponSetCertainContribution bool
+	}
+	testHzFlakeIdGeneratorStore struct {
+		generators   map[string]*testHzFlakeIdGenerator
+		behavior     *testFlakeIdGeneratorBehavior
+		observations *testStoreObservations
+		l            sync.Mutex
+	}
+	testHzFlakeIdGenerator struct {
+		nextId           int64
+		numIds           int
+		newIdInvocations int
+		behavior         *testFlakeIdGeneratorBehavior
+		l                sync.Mutex
+	}
+	testFlakeIdGeneratorBehavior struct {
+		returnErrorUponGetGenerator bool
+		// IDs whose (1-based) number is a multiple of the given value cannot be generated. Zero disables the
+		// behavior.
+		failEveryNth int
+		// IDs whose (1-based) number is a multiple of the given value repeat the ID generated before. Zero disables
+		// the behavior.
+		repeatEveryNth int
+	}
+	testStoreObservations struct {
+		numInitInvocations int
+	}
+	testSleeper struct {
+		sleepKinds []string
+		l          sync.Mutex
+	}
+	testHzClientHandler struct {
+		getClientInvocations, initClientInvocations, shutdownInvocations int
+		hzClusterName                                                    string
+		hzClusterMembers                                                 []string
+	}
+)
+
+const (
+	checkMark     = "\u2713"
+	ballotX       = "\u2717"
+	runnerKeyPath = "testCounterRunner"
+	testPrefix    = "t_"
+	testBaseName  = "test"
+	testNoun      = "Counter"
+)
+
+var (
+	hzCluster                = "awesome-hz-cluster"
+	hzMembers                = []string{"awesome-hz-cluster-svc.cluster.local"}
+	expectedStatesForFullRun = []runnerState{start, populateConfigComplete, checkEnabledComplete, raiseReadyComplete, testLoopStart, testLoopComplete}
+)
+
+func newTestHzPNCounterStore(behavior *testPNCounterBehavior) *testHzPNCounterStore {
+
+	return &testHzPNCounterStore{
+		counters:     make(map[string]*testHzPNCounter),
+		behavior:     behavior,
+		observations: &testStoreObservations{},
+	}
+
+}
+
+func newTestHzMapStore(behavior *testMapBehavior) *testHzMapStore {
+
+	return &testHzMapStore{
+		maps:         make(map[string]*testHzMap),
+		behavior:     behavior,
+		observations: &testStoreObservations{},
+	}
+
+}
+
+func newTestHzFlakeIdGeneratorStore(behavior *testFlakeIdGeneratorBehavior) *testHzFlakeIdGeneratorStore {
+
+	return &testHzFlakeIdGeneratorStore{
+		generators:   make(map[string]*testHzFlakeIdGenerator),
+		behavior:     behavior,
+		observations: &testStoreObservations{},
+	}
+
+}
+
+func (s *testSleeper) sleep(sc *sleepConfig, _ evaluateTimeToSleep, kind, _, _ string) {
+
+	if sc.enabled {
+		s.l.Lock()
+		s.sleepKinds = append(s.sleepKinds, kind)
+		s.l.Unlock()
+	}
+
+}
+
+func (s *testSleeper) numSleeps(kind string) int {
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	n := 0
+	for _, k := range s.sleepKinds {
+		if k == kind {
+			n++
+		}
+	}
+
+	return n
+
+}
+
+func (d *testHzPNCounterStore) GetPNCounter(_ context.Context, name string) (hazelcastwrapper.PNCounter, error) {
+
+	if d.behavior.returnErrorUponGetPNCounter {
+		return nil, errors.New("the spice must flow")
+	}
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	c, ok := d.counters[name]
+	if !ok {
+		c = &testHzPNCounter{behavior: d.behavior}
+		d.counters[name] = c
+	}
+
+	return c, nil
+
+}
+
+func (d *testHzPNCounter) AddAndGet(_ context.Context, delta int64) (int64, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.addAndGetInvocations++
+	d.numOperations++
+
+	if d.behavior.loseConsistencyUponNth > 0 && d.numOperations == d.behavior.loseConsistencyUponNth {
+		d.consistencyLost = true
+	}
+	if d.consistencyLost {
+		return 0, hzerrors.ErrConsistencyLostException
+	}
+
+	if d.behavior.failEveryNth > 0 && d.numOperations%d.behavior.failEveryNth == 0 {
+		if d.behavior.applyFailedOperations {
+			d.value += delta
+		}
+		return 0, errors.New("fear is the mind-killer")
+	}
+
+	if d.behavior.loseEveryNth > 0 && d.numOperations%d.behavior.loseEveryNth == 0 {
+		return d.value + delta, nil
+	}
+
+	d.value += delta
+	return d.value, nil
+
+}
+
+func (d *testHzPNCounter) Get(_ context.Context) (int64, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.getInvocations++
+
+	if d.consistencyLost {
+		return 0, hzerrors.ErrConsistencyLostException
+	}
+
+	if d.behavior.returnErrorUponGet {
+		return 0, errors.New("he who controls the spice controls the universe")
+	}
+
+	return d.value, nil
+
+}
+
+func (d *testHzPNCounter) Reset() {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.resetInvocations++
+	d.consistencyLost = false
+
+}
+
+func (d *testHzPNCounter) Destroy(_ context.Context) error {
+	return nil
+}
+
+func (d *testHzMapStore) GetMap(_ context.Context, name string) (hazelcastwrapper.Map, error) {
+
+	if d.behavior.returnErrorUponGetMap {
+		return nil, errors.New("a beginning is a very delicate time")
+	}
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	m, ok := d.maps[name]
+	if !ok {
+		m = &testHzMap{data: make(map[any]any), locks: make(map[any]bool), behavior: d.behavior}
+		d.maps[name] = m
+	}
+
+	return m, nil
+
+}
+
+func (m *testHzMap) ContainsKey(_ context.Context, key any) (bool, error) {
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	_, ok := m.data[key]
+	return ok, nil
+
+}
+
+func (m *testHzMap) Set(_ context.Context, key any, value any) error {
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	m.setInvocations++
+
+	if m.behavior.returnErrorUponSet {
+		return errors.New("the sleeper must awaken")
+	}
+
+	if c, ok := value.(pnCounterContribution); ok && (m.behavior.returnErrorUponSetContribution ||
+		(m.behavior.returnErrorUponSetCertainContribution && c.Uncertainty == 0)) {
+		return errors.New("the spice must flow")
+	}
+
+	m.data[key] = value
+	return nil
+
+}
+
+func (m *testHzMap) SetWithTTLAndMaxIdle(ctx context.Context, key, value any, _ time.Duration, _ time.Duration) error {
+	return m.Set(ctx, key, value)
+}
+
+func (m *testHzMap) Get(_ context.Context, key any) (any, error) {
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	return m.data[key], nil
+
+}
+
+func (m *testHzMap) Remove(_ context.Context, key any) (any, error) {
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	v := m.data[key]
+	delete(m.data, key)
+	return v, nil
+
+}
+
+func (m *testHzMap) Destroy(_ context.Context) error {
+	return nil
+}
+
+func (m *testHzMap) Size(_ context.Context) (int, error) {
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	return len(m.data), nil
+
+}
+
+func (m *testHzMap) RemoveAll(_ context.Context, _ predicate.Predicate) error {
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	m.data = make(map[any]any)
+	return nil
+
+}
+
+func (m *testHzMap) GetEntrySetWithPredicate(_ context.Context, _ predicate.Predicate) ([]types.Entry, error) {
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	var entries []types.Entry
+	for k, v := range m.data {
+		entries = append(entries, types.Entry{Key: k, Value: v})
+	}
+	return entries, nil
+
+}
+
+func (m *testHzMap) GetKeySetWithPredicate(_ context.Context, _ predicate.Predicate) ([]any, error) {
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	var keys []any
+	for k := range m.data {
+		keys = append(keys, k)
+	}
+	return keys, nil
+
+}
+
+func (m *testHzMap) GetValuesWithPredicate(_ context.Context, _ predicate.Predicate) ([]any, error) {
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	var values []any
+	for _, v := range m.data {
+		values = append(values, v)
+	}
+	return values, nil
+
+}
+
+func (m *testHzMap) EvictAll(_ context.Context) error {
+	return nil
+}
+
+func (m *testHzMap) TryLock(_ context.Context, key any) (bool, error) {
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	m.tryLockInvocations++
+
+	if m.behavior.returnErrorUponTryLock {
+		return false, errors.New("i must not fear")
+	}
+
+	if m.locks[key] {
+		return false, nil
+	}
+	m.locks[key] = true
+	return true, nil
+
+}
+
+func (m *testHzMap) Unlock(_ context.Context, key any) error {
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	m.unlockInvocations++
+	delete(m.locks, key)
+	return nil
+
+}
+
+func (m *testHzMap) AddListenerWithPredicate(_ context.Context, _ hazelcast.MapListener, _ predicate.Predicate, _ bool) (types.UUID, error) {
+	return types.UUID{}, nil
+}
+
+func (m *testHzMap) RemoveListener(_ context.Context, _ types.UUID) error {
+	return nil
+}
+
+func (d *testHzFlakeIdGeneratorStore) GetFlakeIdGenerator(_ context.Context, name string) (hazelcastwrapper.FlakeIdGenerator, error) {
+
+	if d.behavior.returnErrorUponGetGenerator {
+		return nil, errors.New("the mystery of life isn't a problem to solve")
+	}
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	g, ok := d.generators[name]
+	if !ok {
+		g = &testHzFlakeIdGenerator{behavior: d.behavior}
+		d.generators[name] = g
+	}
+
+	return g, nil
+
+}
+
+func (g *testHzFlakeIdGenerator) NewID(_ context.Context) (int64, error) {
+
+	g.l.Lock()
+	defer g.l.Unlock()
+
+	g.newIdInvocations++
+	g.numIds++
+
+	if g.behavior.failEveryNth > 0 && g.numIds%g.behavior.failEveryNth == 0 {
+		return 0, errors.New("deep in the human unconscious is a pervasive need for a logical universe")
+	}
+
+	if g.behavior.repeatEveryNth > 0 && g.numIds%g.behavior.repeatEveryNth == 0 && g.nextId > 0 {
+		return g.nextId - 1, nil
+	}
+
+	id := g.nextId
+	g.nextId++
+	return id, nil
+
+}
+
+func (g *testHzFlakeIdGenerator) Destroy(_ context.Context) error {
+	return nil
+}
+
+func (a testConfigPropertyAssigner) Assign(keyPath string, eval func(string, any) error, assign func(any)) error {
+
+	if a.returnError {
+		return errors.New("lo and behold, here is a deliberately thrown error")
+	}
+
+	if value, ok := a.testConfig[keyPath]; ok {
+		if err := eval(keyPath, value); err != nil {
+			return err
+		}
+		assign(value)
+	}
+
+	return nil
+}
+
+func (d *testHzClientHandler) GetClusterName() string {
+	return d.hzClusterName
+}
+
+func (d *testHzClientHandler) GetClusterMembers() []string {
+	return d.hzClusterMembers
+}
+
+func (d *testHzClientHandler) GetClient() *hazelcast.Client {
+	d.getClientInvocations++
+	return nil
+}
+
+func (d *testHzClientHandler) InitHazelcastClient(_ context.Context, _ string, _ string, _ []string) {
+	d.initClientInvocations++
+}
+
+func (d *testHzClientHandler) Shutdown(_ context.Context) error {
+	d.shutdownInvocations++
+	return nil
+}
+
+func checkRunnerStateTransitions(expected []runnerState, actual []runnerState) (string, bool) {
+
+	if len(expected) != len(actual) {
+		return fmt.Sprintf("expected %d state transition(-s), got %d", len(expected), len(actual)), false
+	}
+
+	for i, expectedValue := range expected {
+		if actual[i] != expectedValue {
+			return fmt.Sprintf("expected '%s' in index '%d', got '%s'", expectedValue, i, actual[i]), false
+		}
+	}
+
+	return "", true
+
+}
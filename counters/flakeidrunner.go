@@ -0,0 +1,124 @@
+package counters
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+)
+
+type (
+	flakeIdRunner struct {
+		assigner        client.ConfigPropertyAssigner
+		stateList       []runnerState
+		name            string
+		source          string
+		hzClientHandler hazelcastwrapper.HzClientHandler
+		l               looper[flakeIdTestLoopExecution]
+		gatherer        *status.Gatherer
+	}
+	flakeIdConfig struct {
+		numIdsPerRun int
+	}
+)
+
+const (
+	flakeIdRunnerKeyPath = "counterTests.flakeIdGenerator"
+)
+
+func init() {
+	register(&flakeIdRunner{
+		assigner:        &client.DefaultConfigPropertyAssigner{},
+		stateList:       []runnerState{},
+		name:            "countersFlakeIdRunner",
+		source:          "flakeIdRunner",
+		hzClientHandler: &hazelcastwrapper.DefaultHzClientHandler{},
+		l:               &flakeIdTestLoop{},
+	})
+}
+
+func (r *flakeIdRunner) getSourceName() string {
+	return r.source
+}
+
+func (r *flakeIdRunner) runCounterTests(hzCluster string, hzMembers []string, gatherer *status.Gatherer, sf *storeFuncs) {
+
+	r.gatherer = gatherer
+	r.appendState(start)
+
+	b := runnerConfigBuilder{assigner: r.assigner, runnerKeyPath: flakeIdRunnerKeyPath, baseName: "flakeIdGenerator", noun: "Generator"}
+	rc, err := b.populateConfig()
+	if err != nil {
+		lp.LogCounterRunnerEvent(fmt.Sprintf("aborting launch of flake id runner: unable to populate config due to error: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	fc, err := populateFlakeIdConfig(r.assigner)
+	if err != nil {
+		lp.LogCounterRunnerEvent(fmt.Sprintf("aborting launch of flake id runner: unable to populate config due to error: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.appendState(populateConfigComplete)
+
+	if !rc.enabled {
+		lp.LogCounterRunnerEvent("flake id runner not enabled -- won't run", r.name, log.InfoLevel)
+		return
+	}
+	r.appendState(checkEnabledComplete)
+
+	api.RaiseNotReady()
+
+	ctx := context.TODO()
+
+	r.hzClientHandler.InitHazelcastClient(ctx, r.name, hzCluster, hzMembers)
+	defer func() {
+		_ = r.hzClientHandler.Shutdown(ctx)
+	}()
+
+	api.RaiseReady()
+	r.appendState(raiseReadyComplete)
+
+	lp.LogCounterRunnerEvent("initialized hazelcast client", r.name, log.InfoLevel)
+	lp.LogCounterRunnerEvent("starting test loop for flake id generators", r.name, log.InfoLevel)
+
+	tle := &flakeIdTestLoopExecution{
+		id:                    uuid.New(),
+		runnerName:            r.name,
+		source:                r.source,
+		flakeIdGeneratorStore: sf.flakeIdGenerator(r.hzClientHandler),
+		runnerConfig:          rc,
+		flakeIdConfig:         fc,
+		ctx:                   ctx,
+	}
+	r.l.init(tle, &defaultSleeper{}, r.gatherer)
+
+	r.appendState(testLoopStart)
+	r.l.run()
+	r.appendState(testLoopComplete)
+
+	lp.LogCounterRunnerEvent("finished flake id test loop", r.name, log.InfoLevel)
+
+}
+
+func (r *flakeIdRunner) appendState(s runnerState) {
+
+	r.stateList = append(r.stateList, s)
+	r.gatherer.Updates <- status.Update{Key: string(statusKeyCurrentState), Value: string(s)}
+
+}
+
+func populateFlakeIdConfig(a client.ConfigPropertyAssigner) (*flakeIdConfig, error) {
+
+	var numIdsPerRun int
+	if err := a.Assign(flakeIdRunnerKeyPath+".numIdsPerRun", client.ValidateInt, func(a any) {
+		numIdsPerRun = a.(int)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &flakeIdConfig{numIdsPerRun: numIdsPerRun}, nil
+
+}
@@ -0,0 +1,153 @@
+package counters
+
+import (
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"testing"
+)
+
+type testFlakeIdRunnerTestLoop struct {
+	tle *flakeIdTestLoopExecution
+}
+
+func (d *testFlakeIdRunnerTestLoop) init(tle *flakeIdTestLoopExecution, _ sleeper, _ *status.Gatherer) {
+	d.tle = tle
+}
+
+func (d *testFlakeIdRunnerTestLoop) run() {
+	// No-op
+}
+
+func TestRunFlakeIdTests(t *testing.T) {
+
+	t.Log("given a flake id runner to run counter test loops")
+	{
+		genericMsgStateTransitions := "\t\tstate transitions must be correct"
+		genericMsgLatestStateInGatherer := "\t\tlatest state in gatherer must be correct"
+		t.Log("\twhen runner configuration cannot be populated")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: true,
+				testConfig:  nil,
+			}
+			r := flakeIdRunner{assigner: assigner, stateList: []runnerState{}, l: &testFlakeIdRunnerTestLoop{}}
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runCounterTests(hzCluster, hzMembers, gatherer, testStoreFuncs)
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(gatherer, start) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, start)
+			}
+		}
+		t.Log("\twhen runner has been disabled")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: false,
+				testConfig: map[string]any{
+					"counterTests.flakeIdGenerator.enabled": false,
+				},
+			}
+			r := flakeIdRunner{assigner: assigner, stateList: []runnerState{}, l: &testFlakeIdRunnerTestLoop{}}
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runCounterTests(hzCluster, hzMembers, gatherer, testStoreFuncs)
+			gatherer.StopListen()
+
+			latestState := populateConfigComplete
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start, populateConfigComplete}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+		}
+		t.Log("\twhen test loop has executed")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: false,
+				testConfig: map[string]any{
+					"counterTests.flakeIdGenerator.enabled":      true,
+					"counterTests.flakeIdGenerator.numIdsPerRun": 50,
+				},
+			}
+			ch := &testHzClientHandler{}
+			l := &testFlakeIdRunnerTestLoop{}
+			r := flakeIdRunner{assigner: assigner, stateList: []runnerState{}, l: l, hzClientHandler: ch}
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			gs := newTestHzFlakeIdGeneratorStore(&testFlakeIdGeneratorBehavior{})
+			r.runCounterTests(hzCluster, hzMembers, gatherer, &storeFuncs{
+				flakeIdGenerator: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.FlakeIdGeneratorStore {
+					gs.observations.numInitInvocations++
+					return gs
+				},
+			})
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions(expectedStatesForFullRun, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			latestState := expectedStatesForFullRun[len(expectedStatesForFullRun)-1]
+			if latestStatePresentInGatherer(gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+
+			msg := "\t\thazelcast client handler must have initialized hazelcast client once"
+			if ch.initClientInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations)
+			}
+
+			msg = "\t\thazelcast client handler must have performed shutdown of hazelcast client once"
+			if ch.shutdownInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.shutdownInvocations)
+			}
+
+			msg = "\t\tflake id generator store must have been initialized once"
+			if gs.observations.numInitInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, gs.observations.numInitInvocations)
+			}
+
+			msg = "\t\ttest loop must have received flake id config"
+			if l.tle.flakeIdConfig.numIdsPerRun == 50 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, l.tle.flakeIdConfig)
+			}
+		}
+	}
+
+}
@@ -0,0 +1,153 @@
+package counters
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+)
+
+type (
+	pnCounterRunner struct {
+		assigner        client.ConfigPropertyAssigner
+		stateList       []runnerState
+		name            string
+		source          string
+		hzClientHandler hazelcastwrapper.HzClientHandler
+		l               looper[pnCounterTestLoopExecution]
+		gatherer        *status.Gatherer
+	}
+	pnCounterConfig struct {
+		numOperationsPerRun      int
+		maxDelta                 int
+		convergenceGracePeriodMs int
+	}
+)
+
+const (
+	pnCounterRunnerKeyPath = "counterTests.pnCounter"
+)
+
+func init() {
+	register(&pnCounterRunner{
+		assigner:        &client.DefaultConfigPropertyAssigner{},
+		stateList:       []runnerState{},
+		name:            "countersPnCounterRunner",
+		source:          "pnCounterRunner",
+		hzClientHandler: &hazelcastwrapper.DefaultHzClientHandler{},
+		l:               &pnCounterTestLoop{},
+	})
+}
+
+func (r *pnCounterRunner) getSourceName() string {
+	return r.source
+}
+
+func (r *pnCounterRunner) runCounterTests(hzCluster string, hzMembers []string, gatherer *status.Gatherer, sf *storeFuncs) {
+
+	r.gatherer = gatherer
+	r.appendState(start)
+
+	b := runnerConfigBuilder{assigner: r.assigner, runnerKeyPath: pnCounterRunnerKeyPath, baseName: "pnCounter", noun: "Counter"}
+	rc, err := b.populateConfig()
+	if err != nil {
+		lp.LogCounterRunnerEvent(fmt.Sprintf("aborting launch of pn counter runner: unable to populate config due to error: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	pc, err := populatePNCounterConfig(r.assigner)
+	if err != nil {
+		lp.LogCounterRunnerEvent(fmt.Sprintf("aborting launch of pn counter runner: unable to populate config due to error: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.appendState(populateConfigComplete)
+
+	if !rc.enabled {
+		lp.LogCounterRunnerEvent("pn counter runner not enabled -- won't run", r.name, log.InfoLevel)
+		return
+	}
+	r.appendState(checkEnabledComplete)
+
+	api.RaiseNotReady()
+
+	ctx := context.TODO()
+
+	r.hzClientHandler.InitHazelcastClient(ctx, r.name, hzCluster, hzMembers)
+	defer func() {
+		_ = r.hzClientHandler.Shutdown(ctx)
+	}()
+
+	api.RaiseReady()
+	r.appendState(raiseReadyComplete)
+
+	lp.LogCounterRunnerEvent("initialized hazelcast client", r.name, log.InfoLevel)
+	lp.LogCounterRunnerEvent("starting test loop for pn counters", r.name, log.InfoLevel)
+
+	tle := &pnCounterTestLoopExecution{
+		id:              uuid.New(),
+		runnerName:      r.name,
+		source:          r.source,
+		pnCounterStore:  sf.pnCounter(r.hzClientHandler),
+		mapStore:        sf.m(r.hzClientHandler),
+		runnerConfig:    rc,
+		pnCounterConfig: pc,
+		ctx:             ctx,
+	}
+	r.l.init(tle, &defaultSleeper{}, r.gatherer)
+
+	r.appendState(testLoopStart)
+	r.l.run()
+	r.appendState(testLoopComplete)
+
+	lp.LogCounterRunnerEvent("finished pn counter test loop", r.name, log.InfoLevel)
+
+}
+
+func (r *pnCounterRunner) appendState(s runnerState) {
+
+	r.stateList = append(r.stateList, s)
+	r.gatherer.Updates <- status.Update{Key: string(statusKeyCurrentState), Value: string(s)}
+
+}
+
+func populatePNCounterConfig(a client.ConfigPropertyAssigner) (*pnCounterConfig, error) {
+
+	var assignmentOps []func() error
+
+	var numOperationsPerRun int
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(pnCounterRunnerKeyPath+".numOperationsPerRun", client.ValidateInt, func(a any) {
+			numOperationsPerRun = a.(int)
+		})
+	})
+
+	var maxDelta int
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(pnCounterRunnerKeyPath+".maxDelta", client.ValidateInt, func(a any) {
+			maxDelta = a.(int)
+		})
+	})
+
+	var convergenceGracePeriodMs int
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(pnCounterRunnerKeyPath+".convergenceGracePeriodMs", client.ValidateInt, func(a any) {
+			convergenceGracePeriodMs = a.(int)
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &pnCounterConfig{
+		numOperationsPerRun:      numOperationsPerRun,
+		maxDelta:                 maxDelta,
+		convergenceGracePeriodMs: convergenceGracePeriodMs,
+	}, nil
+
+}
@@ -0,0 +1,183 @@
+package counters
+
+import (
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"testing"
+)
+
+type testPNCounterRunnerTestLoop struct {
+	tle *pnCounterTestLoopExecution
+}
+
+func (d *testPNCounterRunnerTestLoop) init(tle *pnCounterTestLoopExecution, _ sleeper, _ *status.Gatherer) {
+	d.tle = tle
+}
+
+func (d *testPNCounterRunnerTestLoop) run() {
+	// No-op
+}
+
+func TestRunPNCounterTests(t *testing.T) {
+
+	t.Log("given a pn counter runner to run counter test loops")
+	{
+		genericMsgStateTransitions := "\t\tstate transitions must be correct"
+		genericMsgLatestStateInGatherer := "\t\tlatest state in gatherer must be correct"
+		t.Log("\twhen runner configuration cannot be populated")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: true,
+				testConfig:  nil,
+			}
+			r := pnCounterRunner{assigner: assigner, stateList: []runnerState{}, l: &testPNCounterRunnerTestLoop{}}
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runCounterTests(hzCluster, hzMembers, gatherer, testStoreFuncs)
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(gatherer, start) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, start)
+			}
+		}
+		t.Log("\twhen runner has been disabled")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: false,
+				testConfig: map[string]any{
+					"counterTests.pnCounter.enabled": false,
+				},
+			}
+			r := pnCounterRunner{assigner: assigner, stateList: []runnerState{}, l: &testPNCounterRunnerTestLoop{}}
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runCounterTests(hzCluster, hzMembers, gatherer, testStoreFuncs)
+			gatherer.StopListen()
+
+			latestState := populateConfigComplete
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start, populateConfigComplete}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+		}
+		t.Log("\twhen test loop has executed")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: false,
+				testConfig: map[string]any{
+					"counterTests.pnCounter.enabled":                  true,
+					"counterTests.pnCounter.numOperationsPerRun":      50,
+					"counterTests.pnCounter.maxDelta":                 5,
+					"counterTests.pnCounter.convergenceGracePeriodMs": 1000,
+				},
+			}
+			ch := &testHzClientHandler{}
+			l := &testPNCounterRunnerTestLoop{}
+			r := pnCounterRunner{assigner: assigner, stateList: []runnerState{}, l: l, hzClientHandler: ch}
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			cs := newTestHzPNCounterStore(&testPNCounterBehavior{})
+			ms := newTestHzMapStore(&testMapBehavior{})
+			r.runCounterTests(hzCluster, hzMembers, gatherer, &storeFuncs{
+				pnCounter: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.PNCounterStore {
+					cs.observations.numInitInvocations++
+					return cs
+				},
+				m: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.MapStore {
+					ms.observations.numInitInvocations++
+					return ms
+				},
+			})
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions(expectedStatesForFullRun, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			latestState := expectedStatesForFullRun[len(expectedStatesForFullRun)-1]
+			if latestStatePresentInGatherer(gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+
+			msg := "\t\thazelcast client handler must have initialized hazelcast client once"
+			if ch.initClientInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations)
+			}
+
+			msg = "\t\thazelcast client handler must have performed shutdown of hazelcast client once"
+			if ch.shutdownInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.shutdownInvocations)
+			}
+
+			msg = "\t\tpn counter store and map store must have been initialized once each"
+			if cs.observations.numInitInvocations == 1 && ms.observations.numInitInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cs.observations.numInitInvocations, ms.observations.numInitInvocations)
+			}
+
+			msg = "\t\ttest loop must have received pn counter config"
+			pc := l.tle.pnCounterConfig
+			if pc.numOperationsPerRun == 50 && pc.maxDelta == 5 && pc.convergenceGracePeriodMs == 1000 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, pc)
+			}
+		}
+	}
+
+}
+
+func TestPopulatePNCounterConfig(t *testing.T) {
+
+	t.Log("given a function for populating the pn counter-specific part of the runner config")
+	{
+		t.Log("\twhen maximum delta is not a positive number")
+		{
+			a := testConfigPropertyAssigner{testConfig: map[string]any{
+				pnCounterRunnerKeyPath + ".maxDelta": 0,
+			}}
+			pc, err := populatePNCounterConfig(a)
+
+			msg := "\t\terror must be returned"
+			if err != nil && pc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
@@ -0,0 +1,247 @@
+package counters
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/logging"
+	"hazeltest/status"
+	"strings"
+	"sync"
+)
+
+type (
+	CounterTester struct {
+		HzCluster string
+		HzMembers []string
+	}
+	runner interface {
+		getSourceName() string
+		runCounterTests(hzCluster string, hzMembers []string, gatherer *status.Gatherer, sf *storeFuncs)
+	}
+	// storeFuncs bundles the functions for initializing the stores the counter runners work with, so the tester can
+	// hand the same set to each runner, and each runner picks the ones it needs.
+	storeFuncs struct {
+		pnCounter        initPNCounterStoreFunc
+		flakeIdGenerator initFlakeIdGeneratorStoreFunc
+		m                initMapStoreFunc
+	}
+	// runnerConfig holds the properties shared by all counter runners. The config keys of the properties concerning
+	// the data structures contain the noun the runner uses for its data structures -- 'counter' for the PNCounter
+	// runner, and 'generator' for the FlakeIdGenerator runner, e.g. 'numCounters' and 'numGenerators', respectively.
+	runnerConfig struct {
+		enabled              bool
+		numDataStructures    int
+		baseName             string
+		appendIndexToName    bool
+		appendClientIdToName bool
+		usePrefix            bool
+		prefix               string
+		numRuns              uint32
+		sleepBetweenRuns     *sleepConfig
+	}
+	sleepConfig struct {
+		enabled          bool
+		durationMs       int
+		enableRandomness bool
+	}
+	runnerConfigBuilder struct {
+		assigner      client.ConfigPropertyAssigner
+		runnerKeyPath string
+		baseName      string
+		noun          string
+	}
+	runnerState                   string
+	statusKey                     string
+	initPNCounterStoreFunc        func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.PNCounterStore
+	initFlakeIdGeneratorStoreFunc func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.FlakeIdGeneratorStore
+	initMapStoreFunc              func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.MapStore
+)
+
+const (
+	start                  runnerState = "start"
+	populateConfigComplete runnerState = "populateConfigComplete"
+	checkEnabledComplete   runnerState = "checkEnabledComplete"
+	raiseReadyComplete     runnerState = "raiseReadyComplete"
+	testLoopStart          runnerState = "testLoopStart"
+	testLoopComplete       runnerState = "testLoopComplete"
+)
+
+const (
+	statusKeyCurrentState statusKey = "currentState"
+)
+
+var (
+	runners           []runner
+	lp                *logging.LogProvider
+	defaultStoreFuncs = &storeFuncs{
+		pnCounter: func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.PNCounterStore {
+			return &hazelcastwrapper.DefaultPNCounterStore{Client: ch.GetClient()}
+		},
+		flakeIdGenerator: func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.FlakeIdGeneratorStore {
+			return &hazelcastwrapper.DefaultFlakeIdGeneratorStore{Client: ch.GetClient()}
+		},
+		m: func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.MapStore {
+			return &hazelcastwrapper.DefaultMapStore{Client: ch.GetClient()}
+		},
+	}
+)
+
+func register(r runner) {
+	runners = append(runners, r)
+}
+
+func init() {
+	lp = logging.GetLogProviderInstance(client.ID())
+}
+
+func (b runnerConfigBuilder) populateConfig() (*runnerConfig, error) {
+
+	var assignmentOps []func() error
+
+	var enabled bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".enabled", client.ValidateBool, func(a any) {
+			enabled = a.(bool)
+		})
+	})
+
+	var numDataStructures int
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(fmt.Sprintf("%s.num%ss", b.runnerKeyPath, b.noun), client.ValidateInt, func(a any) {
+			numDataStructures = a.(int)
+		})
+	})
+
+	var appendIndexToName bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(fmt.Sprintf("%s.append%sIndexTo%sName", b.runnerKeyPath, b.noun, b.noun), client.ValidateBool, func(a any) {
+			appendIndexToName = a.(bool)
+		})
+	})
+
+	var appendClientIdToName bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(fmt.Sprintf("%s.appendClientIdTo%sName", b.runnerKeyPath, b.noun), client.ValidateBool, func(a any) {
+			appendClientIdToName = a.(bool)
+		})
+	})
+
+	var usePrefix bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(fmt.Sprintf("%s.%sPrefix.enabled", b.runnerKeyPath, strings.ToLower(b.noun)), client.ValidateBool, func(a any) {
+			usePrefix = a.(bool)
+		})
+	})
+
+	var prefix string
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(fmt.Sprintf("%s.%sPrefix.prefix", b.runnerKeyPath, strings.ToLower(b.noun)), client.ValidateString, func(a any) {
+			prefix = a.(string)
+		})
+	})
+
+	var numRuns uint32
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".numRuns", client.ValidateInt, func(a any) {
+			numRuns = uint32(a.(int))
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	sleepBetweenRuns, err := b.populateSleepConfig(b.runnerKeyPath + ".sleeps.betweenRuns")
+	if err != nil {
+		return nil, err
+	}
+
+	return &runnerConfig{
+		enabled:              enabled,
+		numDataStructures:    numDataStructures,
+		baseName:             b.baseName,
+		appendIndexToName:    appendIndexToName,
+		appendClientIdToName: appendClientIdToName,
+		usePrefix:            usePrefix,
+		prefix:               prefix,
+		numRuns:              numRuns,
+		sleepBetweenRuns:     sleepBetweenRuns,
+	}, nil
+
+}
+
+func (b runnerConfigBuilder) populateSleepConfig(configBasePath string) (*sleepConfig, error) {
+
+	var enabled bool
+	if err := b.assigner.Assign(configBasePath+".enabled", client.ValidateBool, func(a any) {
+		enabled = a.(bool)
+	}); err != nil {
+		return nil, err
+	}
+
+	var durationMs int
+	if err := b.assigner.Assign(configBasePath+".durationMs", client.ValidateInt, func(a any) {
+		durationMs = a.(int)
+	}); err != nil {
+		return nil, err
+	}
+
+	var enableRandomness bool
+	if err := b.assigner.Assign(configBasePath+".enableRandomness", client.ValidateBool, func(a any) {
+		enableRandomness = a.(bool)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &sleepConfig{enabled, durationMs, enableRandomness}, nil
+
+}
+
+func (rc *runnerConfig) assembleName(index int) string {
+
+	name := rc.baseName
+
+	if rc.usePrefix && rc.prefix != "" {
+		name = fmt.Sprintf("%s%s", rc.prefix, name)
+	}
+	if rc.appendIndexToName {
+		name = fmt.Sprintf("%s-%d", name, index)
+	}
+	if rc.appendClientIdToName {
+		name = fmt.Sprintf("%s-%s", name, client.ID())
+	}
+
+	return name
+
+}
+
+func (t *CounterTester) TestCounters() {
+
+	clientID := client.ID()
+	lp.LogInternalStateInfo(fmt.Sprintf("%s: counter tester starting %d runner/-s", clientID, len(runners)), log.InfoLevel)
+
+	var wg sync.WaitGroup
+	for i := 0; i < len(runners); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+			defer gatherer.StopListen()
+
+			runner := runners[i]
+
+			api.RegisterStatefulActor(api.CounterRunners, runner.getSourceName(), gatherer.AssembleStatusCopy)
+			runner.runCounterTests(t.HzCluster, t.HzMembers, gatherer, defaultStoreFuncs)
+		}(i)
+	}
+
+	wg.Wait()
+
+}
@@ -0,0 +1,185 @@
+package counters
+
+import (
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"testing"
+)
+
+var (
+	testConfig = map[string]any{
+		runnerKeyPath + ".enabled":                             true,
+		runnerKeyPath + ".numCounters":                         5,
+		runnerKeyPath + ".appendCounterIndexToCounterName":     true,
+		runnerKeyPath + ".appendClientIdToCounterName":         false,
+		runnerKeyPath + ".counterPrefix.enabled":               true,
+		runnerKeyPath + ".counterPrefix.prefix":                testPrefix,
+		runnerKeyPath + ".numRuns":                             500,
+		runnerKeyPath + ".sleeps.betweenRuns.enabled":          true,
+		runnerKeyPath + ".sleeps.betweenRuns.durationMs":       2000,
+		runnerKeyPath + ".sleeps.betweenRuns.enableRandomness": true,
+	}
+	testStoreFuncs = &storeFuncs{
+		pnCounter: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.PNCounterStore {
+			return newTestHzPNCounterStore(&testPNCounterBehavior{})
+		},
+		flakeIdGenerator: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.FlakeIdGeneratorStore {
+			return newTestHzFlakeIdGeneratorStore(&testFlakeIdGeneratorBehavior{})
+		},
+		m: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.MapStore {
+			return newTestHzMapStore(&testMapBehavior{})
+		},
+	}
+)
+
+func waitForStatusGatheringDone(g *status.Gatherer) {
+
+	for {
+		if done := g.ListeningStopped(); done {
+			return
+		}
+	}
+
+}
+
+func latestStatePresentInGatherer(g *status.Gatherer, desiredState runnerState) bool {
+
+	if value, ok := g.AssembleStatusCopy()[string(statusKeyCurrentState)]; ok && value == string(desiredState) {
+		return true
+	}
+
+	return false
+
+}
+
+func TestPopulateConfig(t *testing.T) {
+
+	t.Log("given a function for populating counter runner configs")
+	{
+		b := runnerConfigBuilder{runnerKeyPath: runnerKeyPath, baseName: testBaseName, noun: testNoun}
+		t.Log("\twhen property assignment does not generate an error")
+		{
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfig}
+			rc, err := b.populateConfig()
+
+			msg := "\t\tno error should be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tconfig should contain expected values"
+			if configValuesAsExpected(rc, testConfig) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Error(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen property assigning a property yields an error")
+		{
+			b.assigner = testConfigPropertyAssigner{returnError: true, testConfig: map[string]any{}}
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror should be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Error(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen number of data structures is not a positive number")
+		{
+			testConfigCopy := copyTestConfig()
+			testConfigCopy[runnerKeyPath+".numCounters"] = 0
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror must be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestAssembleName(t *testing.T) {
+
+	t.Log("given a runner config and the index of a counter goroutine")
+	{
+		t.Log("\twhen prefix and index are to be used")
+		{
+			rc := &runnerConfig{baseName: testBaseName, usePrefix: true, prefix: testPrefix, appendIndexToName: true}
+
+			msg := "\t\tname must contain prefix, base name, and index"
+			if name := rc.assembleName(3); name == "t_test-3" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, name)
+			}
+		}
+
+		t.Log("\twhen client ID is to be appended")
+		{
+			rc := &runnerConfig{baseName: testBaseName, appendClientIdToName: true}
+
+			msg := "\t\tname must contain base name and client ID"
+			if name := rc.assembleName(3); name == testBaseName+"-"+client.ID().String() {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, name)
+			}
+		}
+
+		t.Log("\twhen neither prefix, nor index, nor client ID are to be used")
+		{
+			rc := &runnerConfig{baseName: testBaseName}
+
+			msg := "\t\tall goroutines must share the same name"
+			if rc.assembleName(0) == testBaseName && rc.assembleName(1) == testBaseName {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func copyTestConfig() map[string]any {
+
+	testConfigCopy := make(map[string]any, len(testConfig))
+	for k, v := range testConfig {
+		testConfigCopy[k] = v
+	}
+
+	return testConfigCopy
+
+}
+
+func configValuesAsExpected(rc *runnerConfig, expected map[string]any) bool {
+
+	if rc == nil {
+		return false
+	}
+
+	return rc.enabled == expected[runnerKeyPath+".enabled"] &&
+		rc.numDataStructures == expected[runnerKeyPath+".numCounters"] &&
+		rc.baseName == testBaseName &&
+		rc.appendIndexToName == expected[runnerKeyPath+".appendCounterIndexToCounterName"] &&
+		rc.appendClientIdToName == expected[runnerKeyPath+".appendClientIdToCounterName"] &&
+		rc.usePrefix == expected[runnerKeyPath+".counterPrefix.enabled"] &&
+		rc.prefix == expected[runnerKeyPath+".counterPrefix.prefix"] &&
+		rc.numRuns == uint32(expected[runnerKeyPath+".numRuns"].(int)) &&
+		rc.sleepBetweenRuns.enabled == expected[runnerKeyPath+".sleeps.betweenRuns.enabled"] &&
+		rc.sleepBetweenRuns.durationMs == expected[runnerKeyPath+".sleeps.betweenRuns.durationMs"] &&
+		rc.sleepBetweenRuns.enableRandomness == expected[runnerKeyPath+".sleeps.betweenRuns.enableRandomness"]
+
+}
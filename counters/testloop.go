@@ -0,0 +1,615 @@
+package counters
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/hazelcast/hazelcast-go-client/hzerrors"
+	"github.com/hazelcast/hazelcast-go-client/predicate"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type (
+	evaluateTimeToSleep func(sc *sleepConfig) int
+	looper[t any]       interface {
+		init(tle *t, s sleeper, g *status.Gatherer)
+		run()
+	}
+	sleeper interface {
+		sleep(sc *sleepConfig, sf evaluateTimeToSleep, kind, name, runnerName string)
+	}
+	counterTracker interface {
+		init(gatherer *status.Gatherer, keys []statusKey)
+		increaseCounter(sk statusKey)
+	}
+	pnCounterTestLoopExecution struct {
+		id              uuid.UUID
+		runnerName      string
+		source          string
+		pnCounterStore  hazelcastwrapper.PNCounterStore
+		mapStore        hazelcastwrapper.MapStore
+		runnerConfig    *runnerConfig
+		pnCounterConfig *pnCounterConfig
+		ctx             context.Context
+	}
+	// pnCounterTestLoop makes each of its goroutines apply random increments and decrements to its PNCounter and
+	// keep track of the net sum of the operations it applied successfully. Because the same PNCounter may be shared
+	// by many goroutines of many Hazeltest instances, each goroutine publishes its contribution to a map accompanying
+	// the PNCounter, along with the value the PNCounter had before any contribution was made. Once all goroutines
+	// have finished, the value of each PNCounter is expected to converge on the baseline plus the sum of all
+	// contributions. Each goroutine announces the deltas of a run as uncertainty before applying them, so the
+	// contributions account for the deltas applied at any time, even if a Hazeltest instance dies in the middle of
+	// a run or other instances check convergence while it is still running.
+	pnCounterTestLoop struct {
+		tle                  *pnCounterTestLoopExecution
+		s                    sleeper
+		gatherer             *status.Gatherer
+		ct                   counterTracker
+		establishedBaselines map[string]bool
+		l                    sync.Mutex
+	}
+	// pnCounterContribution is what a single PNCounter goroutine has contributed to the value of its PNCounter.
+	// Operations that returned an error might or might not have been applied on the cluster, so their deltas are
+	// not part of the net sum, but rather make up the uncertainty the convergence check will tolerate. The same
+	// goes for the deltas of a run announced, but not yet confirmed.
+	pnCounterContribution struct {
+		Net         int64
+		Uncertainty int64
+	}
+	flakeIdTestLoopExecution struct {
+		id                    uuid.UUID
+		runnerName            string
+		source                string
+		flakeIdGeneratorStore hazelcastwrapper.FlakeIdGeneratorStore
+		runnerConfig          *runnerConfig
+		flakeIdConfig         *flakeIdConfig
+		ctx                   context.Context
+	}
+	// flakeIdTestLoop makes each of its goroutines generate IDs from its FlakeIdGenerator. The IDs a single
+	// goroutine generates must be strictly increasing, and no ID must be handed out twice by the same FlakeIdGenerator,
+	// even if it is shared by several of the runner's goroutines. (IDs are only unique per FlakeIdGenerator, so
+	// different FlakeIdGenerators may well hand out the same ID.)
+	flakeIdTestLoop struct {
+		tle          *flakeIdTestLoopExecution
+		s            sleeper
+		gatherer     *status.Gatherer
+		ct           counterTracker
+		generatedIds map[string]*idWindow
+		l            sync.Mutex
+	}
+	// idWindow holds the most recent IDs generated from one FlakeIdGenerator, so memory consumption does not grow
+	// with the number of runs. An ID repeating one generated further back by the same goroutine is still caught by
+	// the monotonicity check.
+	idWindow struct {
+		ids    map[int64]struct{}
+		recent []int64
+		oldest int
+	}
+	defaultSleeper        struct{}
+	testLoopCountsTracker struct {
+		counts   map[statusKey]int
+		l        sync.Mutex
+		gatherer *status.Gatherer
+	}
+)
+
+const (
+	statusKeyNumDataStructures = "numDataStructures"
+	statusKeyNumRuns           = "numRuns"
+	statusKeyTotalNumRuns      = "totalNumRuns"
+)
+
+const (
+	statusKeyNumAppliedOperations              statusKey = "numAppliedOperations"
+	statusKeyNumFailedOperations               statusKey = "numFailedOperations"
+	statusKeyNumConsistencyLost                statusKey = "numConsistencyLost"
+	statusKeyNumFailedContributionPublications statusKey = "numFailedContributionPublications"
+	statusKeyNumConvergedCounters              statusKey = "numConvergedCounters"
+	statusKeyNumConvergenceViolations          statusKey = "numConvergenceViolations"
+	statusKeyNumFailedConvergenceChecks        statusKey = "numFailedConvergenceChecks"
+	statusKeyNumGeneratedIds                   statusKey = "numGeneratedIds"
+	statusKeyNumFailedIdGenerations            statusKey = "numFailedIdGenerations"
+	statusKeyNumMonotonicityViolations         statusKey = "numMonotonicityViolations"
+	statusKeyNumDuplicateIds                   statusKey = "numDuplicateIds"
+)
+
+const (
+	// Deliberately not using the prefix of the payload maps so the state cleaner for maps leaves contributions alone
+	contributionsMapPrefix     = "__ht.pnCounterContributions."
+	baselineKey                = "baseline"
+	maxBaselineLockAttempts    = 10
+	operationLoggingUpdateStep = 100
+)
+
+var (
+	sleepTimeFunc evaluateTimeToSleep = func(sc *sleepConfig) int {
+		var sleepDuration int
+		if sc.enableRandomness {
+			sleepDuration = rand.Intn(sc.durationMs + 1)
+		} else {
+			sleepDuration = sc.durationMs
+		}
+		return sleepDuration
+	}
+	pnCounterCounters = []statusKey{statusKeyNumAppliedOperations, statusKeyNumFailedOperations, statusKeyNumConsistencyLost, statusKeyNumFailedContributionPublications, statusKeyNumConvergedCounters, statusKeyNumConvergenceViolations, statusKeyNumFailedConvergenceChecks}
+	flakeIdCounters   = []statusKey{statusKeyNumGeneratedIds, statusKeyNumFailedIdGenerations, statusKeyNumMonotonicityViolations, statusKeyNumDuplicateIds}
+	// Variables rather than constants so tests don't have to wait for the real thing
+	convergencePollInterval = 500 * time.Millisecond
+	baselineLockRetryDelay  = 100 * time.Millisecond
+)
+
+func init() {
+	gob.Register(pnCounterContribution{})
+}
+
+func insertInitialStatus(g *status.Gatherer, rc *runnerConfig) {
+
+	g.Updates <- status.Update{Key: statusKeyNumDataStructures, Value: rc.numDataStructures}
+	g.Updates <- status.Update{Key: statusKeyNumRuns, Value: rc.numRuns}
+	g.Updates <- status.Update{Key: statusKeyTotalNumRuns, Value: uint32(rc.numDataStructures) * rc.numRuns}
+
+}
+
+func (l *pnCounterTestLoop) init(tle *pnCounterTestLoopExecution, s sleeper, g *status.Gatherer) {
+	l.tle = tle
+	l.s = s
+	l.gatherer = g
+	l.establishedBaselines = make(map[string]bool)
+
+	ct := &testLoopCountsTracker{}
+	l.ct = ct
+	ct.init(g, pnCounterCounters)
+}
+
+func (l *pnCounterTestLoop) run() {
+
+	rc := l.tle.runnerConfig
+	insertInitialStatus(l.gatherer, rc)
+
+	var wg sync.WaitGroup
+	for i := 0; i < rc.numDataStructures; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l.runForCounter(i)
+		}(i)
+	}
+	wg.Wait()
+
+	// Several goroutines might have worked on the same PNCounter, so check each PNCounter only once
+	counterNames := make(map[string]struct{})
+	for i := 0; i < rc.numDataStructures; i++ {
+		counterNames[rc.assembleName(i)] = struct{}{}
+	}
+
+	for name := range counterNames {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			l.checkConvergence(name)
+		}(name)
+	}
+	wg.Wait()
+
+}
+
+func (l *pnCounterTestLoop) runForCounter(index int) {
+
+	ctx := l.tle.ctx
+	counterName := l.tle.runnerConfig.assembleName(index)
+	contributionKey := fmt.Sprintf("%s-%s-%d", client.ID(), l.tle.runnerName, index)
+
+	c, err := l.tle.pnCounterStore.GetPNCounter(ctx, counterName)
+	if err != nil {
+		lp.LogCounterRunnerEvent(fmt.Sprintf("unable to retrieve pn counter '%s': %v", counterName, err), l.tle.runnerName, log.ErrorLevel)
+		return
+	}
+
+	m, err := l.tle.mapStore.GetMap(ctx, contributionsMapPrefix+counterName)
+	if err != nil {
+		lp.LogCounterRunnerEvent(fmt.Sprintf("unable to retrieve contributions map for pn counter '%s': %v", counterName, err), l.tle.runnerName, log.ErrorLevel)
+		return
+	}
+
+	if err := l.establishBaseline(counterName, c, m); err != nil {
+		lp.LogCounterRunnerEvent(fmt.Sprintf("unable to establish baseline for pn counter '%s': %v", counterName, err), l.tle.runnerName, log.ErrorLevel)
+		return
+	}
+
+	contribution := pnCounterContribution{}
+	pc := l.tle.pnCounterConfig
+	for i := uint32(0); i < l.tle.runnerConfig.numRuns; i++ {
+
+		if i > 0 && i%operationLoggingUpdateStep == 0 {
+			lp.LogCounterRunnerEvent(fmt.Sprintf("finished %d of %d runs for pn counter '%s'", i, l.tle.runnerConfig.numRuns, counterName), l.tle.runnerName, log.InfoLevel)
+		}
+
+		deltas := make([]int64, pc.numOperationsPerRun)
+		var pending int64
+		for j := range deltas {
+			deltas[j] = randomDelta(pc.maxDelta)
+			pending += abs(deltas[j])
+		}
+
+		// Should this goroutine's Hazeltest instance die before the run's contribution has been published, the
+		// announcement makes sure the deltas applied in the meantime are accounted for
+		announcement := pnCounterContribution{Net: contribution.Net, Uncertainty: contribution.Uncertainty + pending}
+		if err := m.Set(ctx, contributionKey, announcement); err != nil {
+			l.ct.increaseCounter(statusKeyNumFailedContributionPublications)
+			lp.LogCounterRunnerEvent(fmt.Sprintf("unable to announce deltas to pn counter '%s', skipping run: %v", counterName, err), l.tle.runnerName, log.WarnLevel)
+			l.s.sleep(l.tle.runnerConfig.sleepBetweenRuns, sleepTimeFunc, "betweenRuns", counterName, l.tle.runnerName)
+			continue
+		}
+
+		for _, delta := range deltas {
+			if _, err := c.AddAndGet(ctx, delta); err != nil {
+				// Operation might or might not have been applied on the cluster, so all that can be said
+				// is that the value of the PNCounter is off by the delta at most
+				contribution.Uncertainty += abs(delta)
+				if !l.resetIfConsistencyLost(c, counterName, err) {
+					l.ct.increaseCounter(statusKeyNumFailedOperations)
+					lp.LogCounterRunnerEvent(fmt.Sprintf("unable to apply delta %d to pn counter '%s': %v", delta, counterName, err), l.tle.runnerName, log.WarnLevel)
+				}
+				continue
+			}
+			contribution.Net += delta
+			l.ct.increaseCounter(statusKeyNumAppliedOperations)
+		}
+
+		// Contribution is cumulative, so a failed publication will be made up for by the next successful one, and
+		// until then, the announcement covers the deltas applied in this run
+		if err := m.Set(ctx, contributionKey, contribution); err != nil {
+			l.ct.increaseCounter(statusKeyNumFailedContributionPublications)
+			lp.LogCounterRunnerEvent(fmt.Sprintf("unable to publish contribution to pn counter '%s': %v", counterName, err), l.tle.runnerName, log.WarnLevel)
+		}
+
+		l.s.sleep(l.tle.runnerConfig.sleepBetweenRuns, sleepTimeFunc, "betweenRuns", counterName, l.tle.runnerName)
+
+	}
+
+	lp.LogCounterRunnerEvent(fmt.Sprintf("finished %d runs for pn counter '%s' with net contribution %d and uncertainty %d", l.tle.runnerConfig.numRuns, counterName, contribution.Net, contribution.Uncertainty), l.tle.runnerName, log.InfoLevel)
+
+}
+
+// resetIfConsistencyLost resets the observed state of the given PNCounter if the given error says the replica whose
+// state the PNCounter has observed last is gone -- for example because a member got killed before having replicated
+// its state --, and reports whether it has done so. The PNCounter does not reset its observed state by itself, so
+// without the reset, all subsequent operations on it would fail with the same error.
+func (l *pnCounterTestLoop) resetIfConsistencyLost(c hazelcastwrapper.PNCounter, counterName string, err error) bool {
+
+	if !errors.Is(err, hzerrors.ErrConsistencyLostException) {
+		return false
+	}
+
+	c.Reset()
+	l.ct.increaseCounter(statusKeyNumConsistencyLost)
+	lp.LogCounterRunnerEvent(fmt.Sprintf("lost consistency on pn counter '%s' -- reset observed state: %v", counterName, err), l.tle.runnerName, log.WarnLevel)
+	return true
+
+}
+
+// establishBaseline records the value the given PNCounter had before any goroutine of any Hazeltest instance
+// contributed to it, unless some goroutine has done so before. The baseline is what allows PNCounters to be
+// verified that still carry a value from previous Hazeltest runs.
+func (l *pnCounterTestLoop) establishBaseline(counterName string, c hazelcastwrapper.PNCounter, m hazelcastwrapper.Map) error {
+
+	l.l.Lock()
+	defer l.l.Unlock()
+
+	if l.establishedBaselines[counterName] {
+		return nil
+	}
+
+	ctx := l.tle.ctx
+	locked := false
+	for attempt := 0; attempt < maxBaselineLockAttempts; attempt++ {
+		var err error
+		locked, err = m.TryLock(ctx, baselineKey)
+		if err != nil {
+			return err
+		}
+		if locked {
+			break
+		}
+		time.Sleep(baselineLockRetryDelay)
+	}
+	if !locked {
+		return fmt.Errorf("unable to acquire lock on key '%s' in contributions map of pn counter '%s' after %d attempts", baselineKey, counterName, maxBaselineLockAttempts)
+	}
+	defer func() {
+		if err := m.Unlock(ctx, baselineKey); err != nil {
+			lp.LogCounterRunnerEvent(fmt.Sprintf("unable to release lock on key '%s' in contributions map of pn counter '%s': %v", baselineKey, counterName, err), l.tle.runnerName, log.WarnLevel)
+		}
+	}()
+
+	v, err := m.Get(ctx, baselineKey)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		value, err := c.Get(ctx)
+		if err != nil {
+			l.resetIfConsistencyLost(c, counterName, err)
+			return err
+		}
+		if err := m.Set(ctx, baselineKey, value); err != nil {
+			return err
+		}
+		lp.LogCounterRunnerEvent(fmt.Sprintf("established baseline %d for pn counter '%s'", value, counterName), l.tle.runnerName, log.InfoLevel)
+	}
+
+	l.establishedBaselines[counterName] = true
+	return nil
+
+}
+
+// checkConvergence polls the value of the given PNCounter until it has converged on the expected value or the
+// configured grace period has expired. Because PNCounter replicas are only eventually consistent, and because
+// goroutines of other Hazeltest instances might still be publishing their contributions, a mismatch is only
+// considered a violation once the grace period has expired.
+func (l *pnCounterTestLoop) checkConvergence(counterName string) {
+
+	ctx := l.tle.ctx
+	deadline := time.Now().Add(time.Duration(l.tle.pnCounterConfig.convergenceGracePeriodMs) * time.Millisecond)
+
+	for {
+		expected, uncertainty, actual, err := l.evaluateCounter(counterName)
+		if err != nil {
+			lp.LogCounterRunnerEvent(fmt.Sprintf("unable to evaluate pn counter '%s': %v", counterName, err), l.tle.runnerName, log.WarnLevel)
+		} else if abs(actual-expected) <= uncertainty {
+			l.ct.increaseCounter(statusKeyNumConvergedCounters)
+			lp.LogCounterRunnerEvent(fmt.Sprintf("pn counter '%s' converged on value %d (expected: %d, uncertainty: %d)", counterName, actual, expected, uncertainty), l.tle.runnerName, log.InfoLevel)
+			return
+		}
+
+		if !time.Now().Before(deadline) {
+			if err != nil {
+				l.ct.increaseCounter(statusKeyNumFailedConvergenceChecks)
+				lp.LogCounterRunnerEvent(fmt.Sprintf("unable to check convergence of pn counter '%s' within grace period: %v", counterName, err), l.tle.runnerName, log.ErrorLevel)
+			} else {
+				l.ct.increaseCounter(statusKeyNumConvergenceViolations)
+				lp.LogCounterRunnerEvent(fmt.Sprintf("pn counter '%s' did not converge within grace period: expected %d (uncertainty: %d), got %d", counterName, expected, uncertainty, actual), l.tle.runnerName, log.ErrorLevel)
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(convergencePollInterval):
+		}
+	}
+
+}
+
+func (l *pnCounterTestLoop) evaluateCounter(counterName string) (expected, uncertainty, actual int64, err error) {
+
+	ctx := l.tle.ctx
+
+	m, err := l.tle.mapStore.GetMap(ctx, contributionsMapPrefix+counterName)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	baseline, err := m.Get(ctx, baselineKey)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	b, ok := baseline.(int64)
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("expected baseline of type int64, got %T", baseline)
+	}
+
+	values, err := m.GetValuesWithPredicate(ctx, predicate.True())
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	expected = b
+	for _, v := range values {
+		// Map also holds the baseline
+		if c, ok := v.(pnCounterContribution); ok {
+			expected += c.Net
+			uncertainty += c.Uncertainty
+		}
+	}
+
+	c, err := l.tle.pnCounterStore.GetPNCounter(ctx, counterName)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	actual, err = c.Get(ctx)
+	if err != nil {
+		// Reset makes the next poll of the convergence check succeed
+		l.resetIfConsistencyLost(c, counterName, err)
+		return 0, 0, 0, err
+	}
+
+	return expected, uncertainty, actual, nil
+
+}
+
+func (l *flakeIdTestLoop) init(tle *flakeIdTestLoopExecution, s sleeper, g *status.Gatherer) {
+	l.tle = tle
+	l.s = s
+	l.gatherer = g
+	l.generatedIds = make(map[string]*idWindow)
+
+	ct := &testLoopCountsTracker{}
+	l.ct = ct
+	ct.init(g, flakeIdCounters)
+}
+
+func (l *flakeIdTestLoop) run() {
+
+	insertInitialStatus(l.gatherer, l.tle.runnerConfig)
+
+	var wg sync.WaitGroup
+	for i := 0; i < l.tle.runnerConfig.numDataStructures; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l.runForGenerator(i)
+		}(i)
+	}
+	wg.Wait()
+
+}
+
+func (l *flakeIdTestLoop) runForGenerator(index int) {
+
+	ctx := l.tle.ctx
+	generatorName := l.tle.runnerConfig.assembleName(index)
+
+	g, err := l.tle.flakeIdGeneratorStore.GetFlakeIdGenerator(ctx, generatorName)
+	if err != nil {
+		lp.LogCounterRunnerEvent(fmt.Sprintf("unable to retrieve flake id generator '%s': %v", generatorName, err), l.tle.runnerName, log.ErrorLevel)
+		return
+	}
+
+	var lastId int64
+	haveLastId := false
+	for i := uint32(0); i < l.tle.runnerConfig.numRuns; i++ {
+
+		if i > 0 && i%operationLoggingUpdateStep == 0 {
+			lp.LogCounterRunnerEvent(fmt.Sprintf("finished %d of %d runs for flake id generator '%s'", i, l.tle.runnerConfig.numRuns, generatorName), l.tle.runnerName, log.InfoLevel)
+		}
+
+		for j := 0; j < l.tle.flakeIdConfig.numIdsPerRun; j++ {
+			id, err := g.NewID(ctx)
+			if err != nil {
+				l.ct.increaseCounter(statusKeyNumFailedIdGenerations)
+				lp.LogCounterRunnerEvent(fmt.Sprintf("unable to generate id from flake id generator '%s': %v", generatorName, err), l.tle.runnerName, log.WarnLevel)
+				continue
+			}
+			l.ct.increaseCounter(statusKeyNumGeneratedIds)
+
+			if haveLastId && id <= lastId {
+				l.ct.increaseCounter(statusKeyNumMonotonicityViolations)
+				lp.LogCounterRunnerEvent(fmt.Sprintf("flake id generator '%s' violated monotonicity: generated id %d after id %d", generatorName, id, lastId), l.tle.runnerName, log.ErrorLevel)
+			}
+			lastId = id
+			haveLastId = true
+
+			if !l.recordId(generatorName, id) {
+				l.ct.increaseCounter(statusKeyNumDuplicateIds)
+				lp.LogCounterRunnerEvent(fmt.Sprintf("flake id generator '%s' generated id %d that was generated before", generatorName, id), l.tle.runnerName, log.ErrorLevel)
+			}
+		}
+
+		l.s.sleep(l.tle.runnerConfig.sleepBetweenRuns, sleepTimeFunc, "betweenRuns", generatorName, l.tle.runnerName)
+
+	}
+
+	lp.LogCounterRunnerEvent(fmt.Sprintf("finished %d runs for flake id generator '%s'", l.tle.runnerConfig.numRuns, generatorName), l.tle.runnerName, log.InfoLevel)
+
+}
+
+// recordId returns false if the given ID is among the IDs recently recorded for the given FlakeIdGenerator. The
+// window of recent IDs spans two runs' worth of IDs of all goroutines, which might all share the FlakeIdGenerator.
+func (l *flakeIdTestLoop) recordId(generatorName string, id int64) bool {
+
+	l.l.Lock()
+	defer l.l.Unlock()
+
+	w, ok := l.generatedIds[generatorName]
+	if !ok {
+		w = newIdWindow(2 * l.tle.flakeIdConfig.numIdsPerRun * l.tle.runnerConfig.numDataStructures)
+		l.generatedIds[generatorName] = w
+	}
+
+	return w.add(id)
+
+}
+
+func newIdWindow(size int) *idWindow {
+
+	return &idWindow{ids: make(map[int64]struct{}, size), recent: make([]int64, 0, size)}
+
+}
+
+// add returns false if the given ID is already in the window. Otherwise, it adds the ID, evicting the oldest one
+// if the window is full.
+func (w *idWindow) add(id int64) bool {
+
+	if _, ok := w.ids[id]; ok {
+		return false
+	}
+
+	if len(w.recent) < cap(w.recent) {
+		w.recent = append(w.recent, id)
+	} else {
+		delete(w.ids, w.recent[w.oldest])
+		w.recent[w.oldest] = id
+		w.oldest = (w.oldest + 1) % len(w.recent)
+	}
+	w.ids[id] = struct{}{}
+
+	return true
+
+}
+
+func randomDelta(maxDelta int) int64 {
+
+	// Delta of zero would not contribute anything, so draw from [1, maxDelta] and flip a coin for the sign
+	delta := int64(rand.Intn(maxDelta) + 1)
+	if rand.Intn(2) == 0 {
+		return -delta
+	}
+	return delta
+
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func (ct *testLoopCountsTracker) init(gatherer *status.Gatherer, keys []statusKey) {
+	ct.gatherer = gatherer
+
+	ct.counts = make(map[statusKey]int)
+
+	initialCounterValue := 0
+	for _, v := range keys {
+		ct.counts[v] = initialCounterValue
+		gatherer.Updates <- status.Update{Key: string(v), Value: initialCounterValue}
+	}
+
+}
+
+func (ct *testLoopCountsTracker) increaseCounter(sk statusKey) {
+
+	var newValue int
+	ct.l.Lock()
+	{
+		newValue = ct.counts[sk] + 1
+		ct.counts[sk] = newValue
+	}
+	ct.l.Unlock()
+
+	ct.gatherer.Updates <- status.Update{Key: string(sk), Value: newValue}
+
+}
+
+func (s *defaultSleeper) sleep(sc *sleepConfig, sf evaluateTimeToSleep, kind, name, runnerName string) {
+
+	if sc.enabled {
+		sleepDuration := sf(sc)
+		lp.LogCounterRunnerEvent(fmt.Sprintf("sleeping for %d milliseconds for kind '%s' on '%s'", sleepDuration, kind, name), runnerName, log.TraceLevel)
+		time.Sleep(time.Duration(sleepDuration) * time.Millisecond)
+	}
+
+}
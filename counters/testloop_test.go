@@ -0,0 +1,508 @@
+package counters
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"hazeltest/status"
+	"testing"
+	"time"
+)
+
+const (
+	testNumDataStructures = 3
+	testNumRuns           = 4
+	testNumOperations     = 10
+)
+
+func init() {
+	convergencePollInterval = time.Millisecond
+	baselineLockRetryDelay = time.Millisecond
+}
+
+func TestRunPNCounterTestLoop(t *testing.T) {
+
+	t.Log("given a pn counter test loop")
+	{
+		t.Log("\twhen all operations are successful")
+		{
+			cs := newTestHzPNCounterStore(&testPNCounterBehavior{})
+			ms := newTestHzMapStore(&testMapBehavior{})
+			l, s, g := assemblePNCounterTestLoop(cs, ms, assembleTestLoopRunnerConfig(), assemblePNCounterConfig(5))
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			msg := "\t\tall operations must have been applied"
+			if ct.counts[statusKeyNumAppliedOperations] == testNumDataStructures*testNumRuns*testNumOperations && ct.counts[statusKeyNumFailedOperations] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+
+			msg = "\t\tall counters must have converged"
+			if ct.counts[statusKeyNumConvergedCounters] == testNumDataStructures && ct.counts[statusKeyNumConvergenceViolations] == 0 && ct.counts[statusKeyNumFailedConvergenceChecks] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+
+			msg = "\t\teach contributions map must hold baseline and one contribution"
+			for name, m := range ms.maps {
+				if len(m.data) == 2 && m.data[baselineKey] == int64(0) {
+					t.Log(msg, checkMark, name)
+				} else {
+					t.Fatal(msg, ballotX, name, m.data)
+				}
+			}
+
+			msg = "\t\tbetween-runs sleep must have been invoked once per run"
+			if s.numSleeps("betweenRuns") == testNumDataStructures*testNumRuns {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.numSleeps("betweenRuns"))
+			}
+		}
+
+		t.Log("\twhen some operations fail, but have been applied nonetheless")
+		{
+			cs := newTestHzPNCounterStore(&testPNCounterBehavior{failEveryNth: 4, applyFailedOperations: true})
+			ms := newTestHzMapStore(&testMapBehavior{})
+			l, _, g := assemblePNCounterTestLoop(cs, ms, assembleTestLoopRunnerConfig(), assemblePNCounterConfig(5))
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			msg := "\t\tfailed operations must have been reported"
+			if ct.counts[statusKeyNumFailedOperations] == testNumDataStructures*testNumRuns*testNumOperations/4 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts[statusKeyNumFailedOperations])
+			}
+
+			msg = "\t\tall counters must have converged within the uncertainty of the failed operations"
+			if ct.counts[statusKeyNumConvergedCounters] == testNumDataStructures && ct.counts[statusKeyNumConvergenceViolations] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+		}
+
+		t.Log("\twhen counters lose consistency")
+		{
+			cs := newTestHzPNCounterStore(&testPNCounterBehavior{loseConsistencyUponNth: 3})
+			ms := newTestHzMapStore(&testMapBehavior{})
+			l, _, g := assemblePNCounterTestLoop(cs, ms, assembleTestLoopRunnerConfig(), assemblePNCounterConfig(5))
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			msg := "\t\tlost consistency must have been reported once per counter, but not as failed operation"
+			if ct.counts[statusKeyNumConsistencyLost] == testNumDataStructures && ct.counts[statusKeyNumFailedOperations] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+
+			msg = "\t\teach counter must have been reset once and must have kept applying operations afterwards"
+			for name, c := range cs.counters {
+				if c.resetInvocations == 1 && c.addAndGetInvocations == testNumRuns*testNumOperations {
+					t.Log(msg, checkMark, name)
+				} else {
+					t.Fatal(msg, ballotX, name, c.resetInvocations, c.addAndGetInvocations)
+				}
+			}
+
+			msg = "\t\tall operations but the one having lost consistency must have been applied"
+			if ct.counts[statusKeyNumAppliedOperations] == testNumDataStructures*(testNumRuns*testNumOperations-1) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts[statusKeyNumAppliedOperations])
+			}
+
+			msg = "\t\tall counters must have converged"
+			if ct.counts[statusKeyNumConvergedCounters] == testNumDataStructures && ct.counts[statusKeyNumFailedConvergenceChecks] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+		}
+
+		t.Log("\twhen counters lose acknowledged operations")
+		{
+			// Deltas of one and an odd number of lost operations per counter guarantee the lost deltas don't cancel out
+			cs := newTestHzPNCounterStore(&testPNCounterBehavior{loseEveryNth: 8})
+			ms := newTestHzMapStore(&testMapBehavior{})
+			l, _, g := assemblePNCounterTestLoop(cs, ms, assembleTestLoopRunnerConfig(), assemblePNCounterConfig(1))
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			msg := "\t\teach counter must have been reported as convergence violation"
+			if ct.counts[statusKeyNumConvergenceViolations] == testNumDataStructures && ct.counts[statusKeyNumConvergedCounters] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+		}
+
+		t.Log("\twhen hazeltest instances die before their contributions have been confirmed")
+		{
+			cs := newTestHzPNCounterStore(&testPNCounterBehavior{})
+			ms := newTestHzMapStore(&testMapBehavior{returnErrorUponSetCertainContribution: true})
+			l, _, g := assemblePNCounterTestLoop(cs, ms, assembleTestLoopRunnerConfig(), assemblePNCounterConfig(5))
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			msg := "\t\tfailed contribution publications must have been reported"
+			if ct.counts[statusKeyNumFailedContributionPublications] == testNumDataStructures*testNumRuns {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts[statusKeyNumFailedContributionPublications])
+			}
+
+			msg = "\t\tall counters must have converged within the uncertainty of the announced deltas"
+			if ct.counts[statusKeyNumConvergedCounters] == testNumDataStructures && ct.counts[statusKeyNumConvergenceViolations] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+		}
+
+		t.Log("\twhen deltas cannot be announced")
+		{
+			cs := newTestHzPNCounterStore(&testPNCounterBehavior{})
+			ms := newTestHzMapStore(&testMapBehavior{returnErrorUponSetContribution: true})
+			l, s, g := assemblePNCounterTestLoop(cs, ms, assembleTestLoopRunnerConfig(), assemblePNCounterConfig(5))
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			msg := "\t\truns must have been skipped without applying any operations"
+			if cs.sumInvocations(func(c *testHzPNCounter) int { return c.addAndGetInvocations }) == 0 &&
+				ct.counts[statusKeyNumFailedContributionPublications] == testNumDataStructures*testNumRuns && s.numSleeps("betweenRuns") == testNumDataStructures*testNumRuns {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+
+			msg = "\t\tall counters must have converged on their baseline"
+			if ct.counts[statusKeyNumConvergedCounters] == testNumDataStructures {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+		}
+
+		t.Log("\twhen all goroutines share a counter that already carries a value")
+		{
+			cs := newTestHzPNCounterStore(&testPNCounterBehavior{})
+			ms := newTestHzMapStore(&testMapBehavior{})
+			rc := assembleTestLoopRunnerConfig()
+			rc.appendIndexToName = false
+			counterName := rc.assembleName(0)
+			cs.counters[counterName] = &testHzPNCounter{value: 42, behavior: cs.behavior}
+
+			l, _, g := assemblePNCounterTestLoop(cs, ms, rc, assemblePNCounterConfig(5))
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			msg := "\t\tshared counter must have converged once"
+			if ct.counts[statusKeyNumConvergedCounters] == 1 && ct.counts[statusKeyNumConvergenceViolations] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+
+			msg = "\t\tbaseline must have been established once, and each goroutine must have published its contribution"
+			m := ms.maps[contributionsMapPrefix+counterName]
+			if len(m.data) == testNumDataStructures+1 && m.data[baselineKey] == int64(42) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, m.data)
+			}
+		}
+
+		t.Log("\twhen baseline cannot be established")
+		{
+			cs := newTestHzPNCounterStore(&testPNCounterBehavior{})
+			ms := newTestHzMapStore(&testMapBehavior{returnErrorUponTryLock: true})
+			l, _, g := assemblePNCounterTestLoop(cs, ms, assembleTestLoopRunnerConfig(), assemblePNCounterConfig(5))
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			msg := "\t\tno operations must have been applied"
+			if cs.sumInvocations(func(c *testHzPNCounter) int { return c.addAndGetInvocations }) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tconvergence checks must have been reported as failed"
+			if ct.counts[statusKeyNumFailedConvergenceChecks] == testNumDataStructures {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+		}
+
+		t.Log("\twhen pn counters cannot be retrieved")
+		{
+			cs := newTestHzPNCounterStore(&testPNCounterBehavior{returnErrorUponGetPNCounter: true})
+			ms := newTestHzMapStore(&testMapBehavior{})
+			l, s, g := assemblePNCounterTestLoop(cs, ms, assembleTestLoopRunnerConfig(), assemblePNCounterConfig(5))
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			msg := "\t\tgoroutines must return without running"
+			if ct.counts[statusKeyNumAppliedOperations] == 0 && s.numSleeps("betweenRuns") == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+		}
+	}
+
+}
+
+func TestRunFlakeIdTestLoop(t *testing.T) {
+
+	t.Log("given a flake id test loop")
+	{
+		t.Log("\twhen all ids are generated successfully")
+		{
+			gs := newTestHzFlakeIdGeneratorStore(&testFlakeIdGeneratorBehavior{})
+			l, s, g := assembleFlakeIdTestLoop(gs, assembleTestLoopRunnerConfig())
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			msg := "\t\tall ids must have been generated"
+			if ct.counts[statusKeyNumGeneratedIds] == testNumDataStructures*testNumRuns*testNumOperations && ct.counts[statusKeyNumFailedIdGenerations] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+
+			// Each generator of the test store starts counting from zero, so this also verifies ids are only checked
+			// for uniqueness per generator
+			msg = "\t\tno violations must have been reported"
+			if ct.counts[statusKeyNumDuplicateIds] == 0 && ct.counts[statusKeyNumMonotonicityViolations] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+
+			msg = "\t\tbetween-runs sleep must have been invoked once per run"
+			if s.numSleeps("betweenRuns") == testNumDataStructures*testNumRuns {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.numSleeps("betweenRuns"))
+			}
+		}
+
+		t.Log("\twhen all goroutines share a generator")
+		{
+			gs := newTestHzFlakeIdGeneratorStore(&testFlakeIdGeneratorBehavior{})
+			rc := assembleTestLoopRunnerConfig()
+			rc.appendIndexToName = false
+			l, _, g := assembleFlakeIdTestLoop(gs, rc)
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			msg := "\t\tno violations must have been reported"
+			if ct.counts[statusKeyNumGeneratedIds] == testNumDataStructures*testNumRuns*testNumOperations &&
+				ct.counts[statusKeyNumDuplicateIds] == 0 && ct.counts[statusKeyNumMonotonicityViolations] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+		}
+
+		t.Log("\twhen generator repeats ids")
+		{
+			gs := newTestHzFlakeIdGeneratorStore(&testFlakeIdGeneratorBehavior{repeatEveryNth: 5})
+			rc := assembleTestLoopRunnerConfig()
+			rc.numDataStructures = 1
+			l, _, g := assembleFlakeIdTestLoop(gs, rc)
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			numRepeated := testNumRuns * testNumOperations / 5
+			msg := "\t\teach repeated id must have been reported as duplicate and as monotonicity violation"
+			if ct.counts[statusKeyNumDuplicateIds] == numRepeated && ct.counts[statusKeyNumMonotonicityViolations] == numRepeated {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+		}
+
+		t.Log("\twhen some ids cannot be generated")
+		{
+			gs := newTestHzFlakeIdGeneratorStore(&testFlakeIdGeneratorBehavior{failEveryNth: 4})
+			rc := assembleTestLoopRunnerConfig()
+			rc.numDataStructures = 1
+			l, _, g := assembleFlakeIdTestLoop(gs, rc)
+
+			l.run()
+			ct := finishTestLoop(g, l.ct)
+
+			numFailed := testNumRuns * testNumOperations / 4
+			msg := "\t\tfailures must have been reported, and goroutine must have carried on"
+			if ct.counts[statusKeyNumFailedIdGenerations] == numFailed && ct.counts[statusKeyNumGeneratedIds] == testNumRuns*testNumOperations-numFailed {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+
+			msg = "\t\tno violations must have been reported"
+			if ct.counts[statusKeyNumDuplicateIds] == 0 && ct.counts[statusKeyNumMonotonicityViolations] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counts)
+			}
+		}
+	}
+
+}
+
+func TestIdWindow(t *testing.T) {
+
+	t.Log("given a window of recently generated ids")
+	{
+		w := newIdWindow(3)
+		for id := int64(1); id <= 3; id++ {
+			w.add(id)
+		}
+
+		t.Log("\twhen id within window is added again")
+		{
+			msg := "\t\tid must be rejected"
+			if !w.add(2) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen window is full and new id is added")
+		{
+			added := w.add(4)
+
+			msg := "\t\tnew id must be added and oldest id must have been evicted"
+			if added && len(w.ids) == 3 && w.add(1) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, w.ids)
+			}
+
+			msg = "\t\tremaining ids must still be rejected"
+			if !w.add(3) && !w.add(4) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, w.ids)
+			}
+		}
+	}
+
+}
+
+func (d *testHzPNCounterStore) sumInvocations(f func(c *testHzPNCounter) int) int {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	sum := 0
+	for _, c := range d.counters {
+		sum += f(c)
+	}
+
+	return sum
+
+}
+
+func assemblePNCounterTestLoop(cs *testHzPNCounterStore, ms *testHzMapStore, rc *runnerConfig, pc *pnCounterConfig) (*pnCounterTestLoop, *testSleeper, *status.Gatherer) {
+
+	tle := &pnCounterTestLoopExecution{
+		id:              uuid.New(),
+		runnerName:      "countersTestRunner",
+		source:          "testRunner",
+		pnCounterStore:  cs,
+		mapStore:        ms,
+		runnerConfig:    rc,
+		pnCounterConfig: pc,
+		ctx:             context.TODO(),
+	}
+
+	g := status.NewGatherer()
+	go g.Listen()
+
+	s := &testSleeper{}
+	l := &pnCounterTestLoop{}
+	l.init(tle, s, g)
+
+	return l, s, g
+
+}
+
+func assembleFlakeIdTestLoop(gs *testHzFlakeIdGeneratorStore, rc *runnerConfig) (*flakeIdTestLoop, *testSleeper, *status.Gatherer) {
+
+	tle := &flakeIdTestLoopExecution{
+		id:                    uuid.New(),
+		runnerName:            "countersTestRunner",
+		source:                "testRunner",
+		flakeIdGeneratorStore: gs,
+		runnerConfig:          rc,
+		flakeIdConfig:         &flakeIdConfig{numIdsPerRun: testNumOperations},
+		ctx:                   context.TODO(),
+	}
+
+	g := status.NewGatherer()
+	go g.Listen()
+
+	s := &testSleeper{}
+	l := &flakeIdTestLoop{}
+	l.init(tle, s, g)
+
+	return l, s, g
+
+}
+
+func finishTestLoop(g *status.Gatherer, ct counterTracker) *testLoopCountsTracker {
+
+	g.StopListen()
+	waitForStatusGatheringDone(g)
+
+	return ct.(*testLoopCountsTracker)
+
+}
+
+func assemblePNCounterConfig(maxDelta int) *pnCounterConfig {
+
+	return &pnCounterConfig{
+		numOperationsPerRun:      testNumOperations,
+		maxDelta:                 maxDelta,
+		convergenceGracePeriodMs: 20,
+	}
+
+}
+
+func assembleTestLoopRunnerConfig() *runnerConfig {
+
+	return &runnerConfig{
+		enabled:           true,
+		numDataStructures: testNumDataStructures,
+		baseName:          testBaseName,
+		appendIndexToName: true,
+		usePrefix:         true,
+		prefix:            testPrefix,
+		numRuns:           testNumRuns,
+		sleepBetweenRuns:  &sleepConfig{enabled: true},
+	}
+
+}
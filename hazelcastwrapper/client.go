@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/hazelcast/hazelcast-go-client"
+	"github.com/hazelcast/hazelcast-go-client/cluster"
 	log "github.com/sirupsen/logrus"
 	"hazeltest/client"
 	"hazeltest/logging"
@@ -61,6 +62,23 @@ func NewHzClientHelper() HzClientAssembler {
 
 func (h HzClientAssembler) Assemble(ctx context.Context, clientName string, hzCluster string, hzMembers []string) *hazelcast.Client {
 
+	return h.start(ctx, h.assembleConfig(clientName, hzCluster, hzMembers))
+
+}
+
+// AssembleWithMembershipListener works like Assemble, but registers the given membership listener prior to starting
+// the client, so the listener gets informed about all members of the cluster's initial member list, too.
+func (h HzClientAssembler) AssembleWithMembershipListener(ctx context.Context, clientName string, hzCluster string, hzMembers []string, handler cluster.MembershipStateChangeHandler) *hazelcast.Client {
+
+	hzConfig := h.assembleConfig(clientName, hzCluster, hzMembers)
+	hzConfig.AddMembershipListener(handler)
+
+	return h.start(ctx, hzConfig)
+
+}
+
+func (h HzClientAssembler) assembleConfig(clientName string, hzCluster string, hzMembers []string) *hazelcast.Config {
+
 	hzConfig := &hazelcast.Config{}
 	hzConfig.ClientName = fmt.Sprintf("%s-%s", h.clientID, clientName)
 	hzConfig.Cluster.Name = hzCluster
@@ -71,6 +89,12 @@ func (h HzClientAssembler) Assemble(ctx context.Context, clientName string, hzCl
 
 	hzConfig.Cluster.Network.SetAddresses(hzMembers...)
 
+	return hzConfig
+
+}
+
+func (h HzClientAssembler) start(ctx context.Context, hzConfig *hazelcast.Config) *hazelcast.Client {
+
 	hzClient, err := hazelcast.StartNewClientWithConfig(ctx, *hzConfig)
 
 	if err != nil {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"github.com/hazelcast/hazelcast-go-client"
 	"github.com/hazelcast/hazelcast-go-client/predicate"
+	"github.com/hazelcast/hazelcast-go-client/sql"
+	"github.com/hazelcast/hazelcast-go-client/types"
 	"time"
 )
 
@@ -20,15 +22,38 @@ type (
 		Destroy(ctx context.Context) error
 		Size(ctx context.Context) (int, error)
 		RemoveAll(ctx context.Context, predicate predicate.Predicate) error
+		GetEntrySetWithPredicate(ctx context.Context, predicate predicate.Predicate) ([]types.Entry, error)
+		GetKeySetWithPredicate(ctx context.Context, predicate predicate.Predicate) ([]any, error)
+		GetValuesWithPredicate(ctx context.Context, predicate predicate.Predicate) ([]any, error)
 		EvictAll(ctx context.Context) error
 		TryLock(ctx context.Context, key any) (bool, error)
 		Unlock(ctx context.Context, key any) error
+		AddListenerWithPredicate(ctx context.Context, listener hazelcast.MapListener, predicate predicate.Predicate, includeValue bool) (types.UUID, error)
+		RemoveListener(ctx context.Context, subscriptionID types.UUID) error
 	}
 	DefaultMapStore struct {
 		Client *hazelcast.Client
 	}
 )
 
+type (
+	ReplicatedMapStore interface {
+		GetReplicatedMap(ctx context.Context, name string) (ReplicatedMap, error)
+	}
+	ReplicatedMap interface {
+		ContainsKey(ctx context.Context, key any) (bool, error)
+		Put(ctx context.Context, key any, value any) (any, error)
+		Get(ctx context.Context, key any) (any, error)
+		Remove(ctx context.Context, key any) (any, error)
+		Size(ctx context.Context) (int, error)
+		Clear(ctx context.Context) error
+		Destroy(ctx context.Context) error
+	}
+	DefaultReplicatedMapStore struct {
+		Client *hazelcast.Client
+	}
+)
+
 type (
 	QueueStore interface {
 		GetQueue(ctx context.Context, name string) (Queue, error)
@@ -37,7 +62,11 @@ type (
 		Clear(ctx context.Context) error
 		Size(ctx context.Context) (int, error)
 		Put(ctx context.Context, element any) error
+		AddWithTimeout(ctx context.Context, element any, timeout time.Duration) (bool, error)
+		AddAll(ctx context.Context, elements ...any) (bool, error)
 		Poll(ctx context.Context) (any, error)
+		PollWithTimeout(ctx context.Context, timeout time.Duration) (any, error)
+		DrainWithMaxSize(ctx context.Context, maxSize int) ([]any, error)
 		RemainingCapacity(ctx context.Context) (int, error)
 		Destroy(ctx context.Context) error
 	}
@@ -46,6 +75,127 @@ type (
 	}
 )
 
+type (
+	TopicStore interface {
+		GetTopic(ctx context.Context, name string) (Topic, error)
+	}
+	Topic interface {
+		Publish(ctx context.Context, message any) error
+		PublishAll(ctx context.Context, messages ...any) error
+		AddMessageListener(ctx context.Context, handler hazelcast.TopicMessageHandler) (types.UUID, error)
+		RemoveListener(ctx context.Context, subscriptionID types.UUID) error
+		Destroy(ctx context.Context) error
+	}
+	DefaultTopicStore struct {
+		Client *hazelcast.Client
+	}
+)
+
+type (
+	RingbufferStore interface {
+		GetRingbuffer(ctx context.Context, name string) (Ringbuffer, error)
+	}
+	Ringbuffer interface {
+		Add(ctx context.Context, item any, overflowPolicy hazelcast.OverflowPolicy) (int64, error)
+		AddAll(ctx context.Context, overflowPolicy hazelcast.OverflowPolicy, items ...any) (int64, error)
+		ReadOne(ctx context.Context, sequence int64) (any, error)
+		HeadSequence(ctx context.Context) (int64, error)
+		TailSequence(ctx context.Context) (int64, error)
+		Destroy(ctx context.Context) error
+	}
+	DefaultRingbufferStore struct {
+		Client *hazelcast.Client
+	}
+)
+
+type (
+	MultiMapStore interface {
+		GetMultiMap(ctx context.Context, name string) (MultiMap, error)
+	}
+	MultiMap interface {
+		Put(ctx context.Context, key any, value any) (bool, error)
+		ContainsEntry(ctx context.Context, key any, value any) (bool, error)
+		RemoveEntry(ctx context.Context, key any, value any) (bool, error)
+		Clear(ctx context.Context) error
+		Size(ctx context.Context) (int, error)
+		Destroy(ctx context.Context) error
+	}
+	DefaultMultiMapStore struct {
+		Client *hazelcast.Client
+	}
+)
+
+type (
+	ListStore interface {
+		GetList(ctx context.Context, name string) (List, error)
+	}
+	List interface {
+		Add(ctx context.Context, element any) (bool, error)
+		Contains(ctx context.Context, element any) (bool, error)
+		Remove(ctx context.Context, element any) (bool, error)
+		Clear(ctx context.Context) error
+		Size(ctx context.Context) (int, error)
+		Destroy(ctx context.Context) error
+	}
+	DefaultListStore struct {
+		Client *hazelcast.Client
+	}
+)
+
+type (
+	SetStore interface {
+		GetSet(ctx context.Context, name string) (Set, error)
+	}
+	Set interface {
+		Add(ctx context.Context, element any) (bool, error)
+		Contains(ctx context.Context, element any) (bool, error)
+		Remove(ctx context.Context, element any) (bool, error)
+		Clear(ctx context.Context) error
+		Size(ctx context.Context) (int, error)
+		Destroy(ctx context.Context) error
+	}
+	DefaultSetStore struct {
+		Client *hazelcast.Client
+	}
+)
+
+type (
+	PNCounterStore interface {
+		GetPNCounter(ctx context.Context, name string) (PNCounter, error)
+	}
+	PNCounter interface {
+		AddAndGet(ctx context.Context, delta int64) (int64, error)
+		Get(ctx context.Context) (int64, error)
+		Reset()
+		Destroy(ctx context.Context) error
+	}
+	DefaultPNCounterStore struct {
+		Client *hazelcast.Client
+	}
+)
+
+type (
+	FlakeIdGeneratorStore interface {
+		GetFlakeIdGenerator(ctx context.Context, name string) (FlakeIdGenerator, error)
+	}
+	FlakeIdGenerator interface {
+		NewID(ctx context.Context) (int64, error)
+		Destroy(ctx context.Context) error
+	}
+	DefaultFlakeIdGeneratorStore struct {
+		Client *hazelcast.Client
+	}
+)
+
+type (
+	SqlStore interface {
+		Execute(ctx context.Context, query string, params ...any) (sql.Result, error)
+	}
+	DefaultSqlStore struct {
+		Client *hazelcast.Client
+	}
+)
+
 type (
 	ObjectInfo interface {
 		GetName() string
@@ -66,10 +216,46 @@ func (d *DefaultMapStore) GetMap(ctx context.Context, name string) (Map, error)
 	return d.Client.GetMap(ctx, name)
 }
 
+func (d *DefaultReplicatedMapStore) GetReplicatedMap(ctx context.Context, name string) (ReplicatedMap, error) {
+	return d.Client.GetReplicatedMap(ctx, name)
+}
+
 func (d *DefaultQueueStore) GetQueue(ctx context.Context, name string) (Queue, error) {
 	return d.Client.GetQueue(ctx, name)
 }
 
+func (d *DefaultTopicStore) GetTopic(ctx context.Context, name string) (Topic, error) {
+	return d.Client.GetTopic(ctx, name)
+}
+
+func (d *DefaultRingbufferStore) GetRingbuffer(ctx context.Context, name string) (Ringbuffer, error) {
+	return d.Client.GetRingbuffer(ctx, name)
+}
+
+func (d *DefaultMultiMapStore) GetMultiMap(ctx context.Context, name string) (MultiMap, error) {
+	return d.Client.GetMultiMap(ctx, name)
+}
+
+func (d *DefaultListStore) GetList(ctx context.Context, name string) (List, error) {
+	return d.Client.GetList(ctx, name)
+}
+
+func (d *DefaultSetStore) GetSet(ctx context.Context, name string) (Set, error) {
+	return d.Client.GetSet(ctx, name)
+}
+
+func (d *DefaultPNCounterStore) GetPNCounter(ctx context.Context, name string) (PNCounter, error) {
+	return d.Client.GetPNCounter(ctx, name)
+}
+
+func (d *DefaultFlakeIdGeneratorStore) GetFlakeIdGenerator(ctx context.Context, name string) (FlakeIdGenerator, error) {
+	return d.Client.GetFlakeIDGenerator(ctx, name)
+}
+
+func (d *DefaultSqlStore) Execute(ctx context.Context, query string, params ...any) (sql.Result, error) {
+	return d.Client.SQL().Execute(ctx, query, params...)
+}
+
 func (ois *DefaultObjectInfoStore) GetDistributedObjectsInfo(ctx context.Context) ([]ObjectInfo, error) {
 
 	infos, err := ois.Client.GetDistributedObjectsInfo(ctx)
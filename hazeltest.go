@@ -6,10 +6,13 @@ import (
 	"hazeltest/api"
 	"hazeltest/chaos"
 	"hazeltest/client"
+	"hazeltest/collections"
+	"hazeltest/counters"
 	"hazeltest/logging"
 	"hazeltest/maps"
 	"hazeltest/queues"
 	"hazeltest/state"
+	"hazeltest/topics"
 	"os"
 	"strings"
 	"sync"
@@ -43,7 +46,7 @@ func main() {
 	}
 
 	var wg sync.WaitGroup
-	wg.Add(4)
+	wg.Add(7)
 
 	go func() {
 		defer wg.Done()
@@ -64,7 +67,25 @@ func main() {
 
 	go func() {
 		defer wg.Done()
-		chaos.RunMonkeys()
+		topicTester := topics.TopicTester{HzCluster: hzCluster, HzMembers: hzMemberList}
+		topicTester.TestTopics()
+	}()
+
+	go func() {
+		defer wg.Done()
+		collectionTester := collections.CollectionTester{HzCluster: hzCluster, HzMembers: hzMemberList}
+		collectionTester.TestCollections()
+	}()
+
+	go func() {
+		defer wg.Done()
+		counterTester := counters.CounterTester{HzCluster: hzCluster, HzMembers: hzMemberList}
+		counterTester.TestCounters()
+	}()
+
+	go func() {
+		defer wg.Done()
+		chaos.RunMonkeys(hzCluster, hzMemberList)
 	}()
 
 	wg.Wait()
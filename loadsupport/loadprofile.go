@@ -0,0 +1,194 @@
+package loadsupport
+
+import (
+	"fmt"
+	"hazeltest/client"
+	"math"
+	"time"
+)
+
+type (
+	LoadProfileType string
+	// LoadProfileConfig describes how the target throughput of a runner evolves over time. The target throughput
+	// configured for the runner serves as the peak throughput of the ramp, step, and sinusoidal profiles, and as the
+	// regular throughput in between two spikes of the spike profile.
+	LoadProfileConfig struct {
+		Enabled    bool
+		Type       LoadProfileType
+		Ramp       *RampConfig
+		Step       *StepConfig
+		Spike      *SpikeConfig
+		Sinusoidal *SinusoidalConfig
+	}
+	RampConfig struct {
+		StartOpsPerSecond int
+		Duration          time.Duration
+	}
+	StepConfig struct {
+		StartOpsPerSecond     int
+		IncrementOpsPerSecond int
+		Interval              time.Duration
+	}
+	SpikeConfig struct {
+		OpsPerSecond int
+		Interval     time.Duration
+		Duration     time.Duration
+	}
+	SinusoidalConfig struct {
+		MinOpsPerSecond int
+		Period          time.Duration
+	}
+	LoadProfileConfigBuilder struct {
+		Assigner client.ConfigPropertyAssigner
+		KeyPath  string
+	}
+)
+
+const (
+	Ramp       LoadProfileType = "ramp"
+	Step       LoadProfileType = "step"
+	Spike      LoadProfileType = "spike"
+	Sinusoidal LoadProfileType = "sinusoidal"
+)
+
+func ValidateLoadProfileType(keyPath string, a any) error {
+	if err := client.ValidateString(keyPath, a); err != nil {
+		return err
+	}
+
+	switch a {
+	case string(Ramp), string(Step), string(Spike), string(Sinusoidal):
+		return nil
+	default:
+		return fmt.Errorf("load profile type expected to be one of '%s', '%s', '%s', or '%s', got %v", Ramp, Step, Spike, Sinusoidal, a)
+	}
+
+}
+
+func (b LoadProfileConfigBuilder) PopulateConfig() (*LoadProfileConfig, error) {
+
+	var assignmentOps []func() error
+
+	var enabled bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.Assigner.Assign(b.KeyPath+".enabled", client.ValidateBool, func(a any) {
+			enabled = a.(bool)
+		})
+	})
+
+	var profileType LoadProfileType
+	assignmentOps = append(assignmentOps, func() error {
+		return b.Assigner.Assign(b.KeyPath+".type", ValidateLoadProfileType, func(a any) {
+			profileType = LoadProfileType(a.(string))
+		})
+	})
+
+	ramp := &RampConfig{}
+	assignmentOps = append(assignmentOps, func() error {
+		return b.Assigner.Assign(b.KeyPath+".ramp.startOpsPerSecond", client.ValidateInt, func(a any) {
+			ramp.StartOpsPerSecond = a.(int)
+		})
+	})
+	assignmentOps = append(assignmentOps, func() error {
+		return b.Assigner.Assign(b.KeyPath+".ramp.duration", client.ValidateDuration, func(a any) {
+			ramp.Duration, _ = time.ParseDuration(a.(string))
+		})
+	})
+
+	step := &StepConfig{}
+	assignmentOps = append(assignmentOps, func() error {
+		return b.Assigner.Assign(b.KeyPath+".step.startOpsPerSecond", client.ValidateInt, func(a any) {
+			step.StartOpsPerSecond = a.(int)
+		})
+	})
+	assignmentOps = append(assignmentOps, func() error {
+		return b.Assigner.Assign(b.KeyPath+".step.incrementOpsPerSecond", client.ValidateInt, func(a any) {
+			step.IncrementOpsPerSecond = a.(int)
+		})
+	})
+	assignmentOps = append(assignmentOps, func() error {
+		return b.Assigner.Assign(b.KeyPath+".step.interval", client.ValidateDuration, func(a any) {
+			step.Interval, _ = time.ParseDuration(a.(string))
+		})
+	})
+
+	spike := &SpikeConfig{}
+	assignmentOps = append(assignmentOps, func() error {
+		return b.Assigner.Assign(b.KeyPath+".spike.opsPerSecond", client.ValidateInt, func(a any) {
+			spike.OpsPerSecond = a.(int)
+		})
+	})
+	assignmentOps = append(assignmentOps, func() error {
+		return b.Assigner.Assign(b.KeyPath+".spike.interval", client.ValidateDuration, func(a any) {
+			spike.Interval, _ = time.ParseDuration(a.(string))
+		})
+	})
+	assignmentOps = append(assignmentOps, func() error {
+		return b.Assigner.Assign(b.KeyPath+".spike.duration", client.ValidateDuration, func(a any) {
+			spike.Duration, _ = time.ParseDuration(a.(string))
+		})
+	})
+
+	sinusoidal := &SinusoidalConfig{}
+	assignmentOps = append(assignmentOps, func() error {
+		return b.Assigner.Assign(b.KeyPath+".sinusoidal.minOpsPerSecond", client.ValidateInt, func(a any) {
+			sinusoidal.MinOpsPerSecond = a.(int)
+		})
+	})
+	assignmentOps = append(assignmentOps, func() error {
+		return b.Assigner.Assign(b.KeyPath+".sinusoidal.period", client.ValidateDuration, func(a any) {
+			sinusoidal.Period, _ = time.ParseDuration(a.(string))
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	if enabled && profileType == Spike && spike.Duration >= spike.Interval {
+		return nil, fmt.Errorf("duration of spike in '%s' must be shorter than interval between spikes, got duration '%s' and interval '%s'", b.KeyPath, spike.Duration, spike.Interval)
+	}
+
+	return &LoadProfileConfig{
+		Enabled:    enabled,
+		Type:       profileType,
+		Ramp:       ramp,
+		Step:       step,
+		Spike:      spike,
+		Sinusoidal: sinusoidal,
+	}, nil
+
+}
+
+// targetOpsPerSecond calculates the target throughput the given amount of time after the test loop has started
+// for the given peak throughput.
+func (c *LoadProfileConfig) targetOpsPerSecond(elapsed time.Duration, peakOpsPerSecond int) int {
+
+	switch c.Type {
+	case Ramp:
+		if elapsed >= c.Ramp.Duration {
+			return peakOpsPerSecond
+		}
+		progress := float64(elapsed) / float64(c.Ramp.Duration)
+		return c.Ramp.StartOpsPerSecond + int(math.Round(progress*float64(peakOpsPerSecond-c.Ramp.StartOpsPerSecond)))
+	case Step:
+		numIncrements := int(elapsed / c.Step.Interval)
+		return min(peakOpsPerSecond, c.Step.StartOpsPerSecond+numIncrements*c.Step.IncrementOpsPerSecond)
+	case Spike:
+		// Spike occurs at the end of each interval, so the test loop starts at its regular throughput
+		if elapsed%c.Spike.Interval >= c.Spike.Interval-c.Spike.Duration {
+			return c.Spike.OpsPerSecond
+		}
+		return peakOpsPerSecond
+	case Sinusoidal:
+		// Starts at the minimum and reaches the peak after half a period
+		phase := 2 * math.Pi * float64(elapsed%c.Sinusoidal.Period) / float64(c.Sinusoidal.Period)
+		amplitude := float64(peakOpsPerSecond - c.Sinusoidal.MinOpsPerSecond)
+		return c.Sinusoidal.MinOpsPerSecond + int(math.Round(amplitude*(1-math.Cos(phase))/2))
+	default:
+		return peakOpsPerSecond
+	}
+
+}
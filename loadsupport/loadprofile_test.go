@@ -0,0 +1,200 @@
+package loadsupport
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type testConfigPropertyAssigner struct {
+	returnError bool
+	testConfig  map[string]any
+}
+
+const testLoadProfileKeyPath = "mapTests.load.loadProfile"
+
+var testLoadProfileConfig = map[string]any{
+	testLoadProfileKeyPath + ".enabled":                    true,
+	testLoadProfileKeyPath + ".type":                       "spike",
+	testLoadProfileKeyPath + ".ramp.startOpsPerSecond":     10,
+	testLoadProfileKeyPath + ".ramp.duration":              "10m",
+	testLoadProfileKeyPath + ".step.startOpsPerSecond":     100,
+	testLoadProfileKeyPath + ".step.incrementOpsPerSecond": 50,
+	testLoadProfileKeyPath + ".step.interval":              "5m",
+	testLoadProfileKeyPath + ".spike.opsPerSecond":         5000,
+	testLoadProfileKeyPath + ".spike.interval":             "10m",
+	testLoadProfileKeyPath + ".spike.duration":             "30s",
+	testLoadProfileKeyPath + ".sinusoidal.minOpsPerSecond": 50,
+	testLoadProfileKeyPath + ".sinusoidal.period":          "24h",
+}
+
+func (a testConfigPropertyAssigner) Assign(keyPath string, eval func(string, any) error, assign func(any)) error {
+
+	if a.returnError {
+		return errors.New("deliberately thrown error")
+	}
+
+	if value, ok := a.testConfig[keyPath]; ok {
+		if err := eval(keyPath, value); err != nil {
+			return err
+		}
+		assign(value)
+	}
+
+	return nil
+
+}
+
+func TestValidateLoadProfileType(t *testing.T) {
+
+	t.Log("given a function to validate the type of a load profile")
+	{
+		t.Log("\twhen valid type is provided")
+		{
+			for _, v := range []LoadProfileType{Ramp, Step, Spike, Sinusoidal} {
+				err := ValidateLoadProfileType(testLoadProfileKeyPath+".type", string(v))
+
+				msg := "\t\tno error must be returned"
+				if err == nil {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+		}
+
+		t.Log("\twhen invalid type is provided")
+		{
+			for _, v := range []any{"", "sawtooth", 1} {
+				err := ValidateLoadProfileType(testLoadProfileKeyPath+".type", v)
+
+				msg := "\t\terror must be returned"
+				if err != nil {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+		}
+	}
+
+}
+
+func TestLoadProfileConfigBuilderPopulateConfig(t *testing.T) {
+
+	t.Log("given a builder for load profile configs")
+	{
+		t.Log("\twhen property assignment does not yield an error")
+		{
+			b := LoadProfileConfigBuilder{Assigner: testConfigPropertyAssigner{false, testLoadProfileConfig}, KeyPath: testLoadProfileKeyPath}
+
+			c, err := b.PopulateConfig()
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tconfig must contain expected values"
+			if c.Enabled && c.Type == Spike &&
+				*c.Ramp == (RampConfig{10, 10 * time.Minute}) &&
+				*c.Step == (StepConfig{100, 50, 5 * time.Minute}) &&
+				*c.Spike == (SpikeConfig{5000, 10 * time.Minute, 30 * time.Second}) &&
+				*c.Sinusoidal == (SinusoidalConfig{50, 24 * time.Hour}) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, c)
+			}
+		}
+
+		t.Log("\twhen property assignment yields an error")
+		{
+			b := LoadProfileConfigBuilder{Assigner: testConfigPropertyAssigner{true, map[string]any{}}, KeyPath: testLoadProfileKeyPath}
+
+			c, err := b.PopulateConfig()
+
+			msg := "\t\terror must be returned"
+			if err != nil && c == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen spike duration is not shorter than interval between spikes")
+		{
+			testConfig := make(map[string]any, len(testLoadProfileConfig))
+			for k, v := range testLoadProfileConfig {
+				testConfig[k] = v
+			}
+			testConfig[testLoadProfileKeyPath+".spike.duration"] = "10m"
+			b := LoadProfileConfigBuilder{Assigner: testConfigPropertyAssigner{false, testConfig}, KeyPath: testLoadProfileKeyPath}
+
+			c, err := b.PopulateConfig()
+
+			msg := "\t\terror must be returned"
+			if err != nil && c == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestLoadProfileTargetOpsPerSecond(t *testing.T) {
+
+	t.Log("given a load profile and a peak throughput")
+	{
+		peak := 1000
+		c := &LoadProfileConfig{
+			Enabled:    true,
+			Ramp:       &RampConfig{StartOpsPerSecond: 100, Duration: 10 * time.Minute},
+			Step:       &StepConfig{StartOpsPerSecond: 100, IncrementOpsPerSecond: 400, Interval: 5 * time.Minute},
+			Spike:      &SpikeConfig{OpsPerSecond: 5000, Interval: 10 * time.Minute, Duration: 30 * time.Second},
+			Sinusoidal: &SinusoidalConfig{MinOpsPerSecond: 200, Period: 24 * time.Hour},
+		}
+
+		for _, tc := range []struct {
+			profileType LoadProfileType
+			elapsed     time.Duration
+			expected    int
+		}{
+			{Ramp, 0, 100},
+			{Ramp, 5 * time.Minute, 550},
+			{Ramp, 10 * time.Minute, 1000},
+			{Ramp, 2 * time.Hour, 1000},
+			{Step, 0, 100},
+			{Step, 4 * time.Minute, 100},
+			{Step, 5 * time.Minute, 500},
+			{Step, 11 * time.Minute, 900},
+			{Step, 15 * time.Minute, 1000},
+			{Spike, 0, 1000},
+			{Spike, 9 * time.Minute, 1000},
+			{Spike, 9*time.Minute + 45*time.Second, 5000},
+			{Spike, 10 * time.Minute, 1000},
+			{Spike, 19*time.Minute + 30*time.Second, 5000},
+			{Sinusoidal, 0, 200},
+			{Sinusoidal, 6 * time.Hour, 600},
+			{Sinusoidal, 12 * time.Hour, 1000},
+			{Sinusoidal, 24 * time.Hour, 200},
+		} {
+			t.Log(fmt.Sprintf("\twhen profile type is '%s' and %s have elapsed", tc.profileType, tc.elapsed))
+			{
+				c.Type = tc.profileType
+
+				msg := "\t\ttarget throughput must be determined according to profile"
+				if actual := c.targetOpsPerSecond(tc.elapsed, peak); actual == tc.expected {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, fmt.Sprintf("expected %d, got %d", tc.expected, actual))
+				}
+			}
+		}
+	}
+
+}
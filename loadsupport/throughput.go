@@ -0,0 +1,196 @@
+package loadsupport
+
+import (
+	"context"
+	"fmt"
+	"golang.org/x/time/rate"
+	"hazeltest/client"
+	"hazeltest/status"
+	"math"
+	"sync"
+	"time"
+)
+
+type (
+	ThroughputScope  string
+	ThroughputConfig struct {
+		Enabled            bool
+		TargetOpsPerSecond int
+		Scope              ThroughputScope
+		LoadProfile        *LoadProfileConfig
+	}
+	// ThroughputRegulator paces the operations of a test loop by means of a token bucket such that the test loop
+	// doesn't exceed the configured target throughput, and reports the target throughput as well as the throughput
+	// actually achieved to the status gatherer. The latter is published at most once per throughputPublishInterval
+	// and refers to the operations performed since the previous publication. If a load profile has been enabled, the
+	// target throughput is re-evaluated upon each publication.
+	ThroughputRegulator struct {
+		limiters            []*rate.Limiter
+		profile             *LoadProfileConfig
+		peakOpsPerSecond    int
+		targetOpsPerSecond  int
+		started             time.Time
+		numOps              uint64
+		numOpsAtLastPublish uint64
+		lastPublished       time.Time
+		l                   sync.Mutex
+		g                   *status.Gatherer
+	}
+)
+
+const (
+	// RunnerScope makes all goroutines of a test loop share one token bucket, so the target throughput applies to
+	// the runner as a whole.
+	RunnerScope ThroughputScope = "runner"
+	// GoroutineScope gives each map or queue goroutine of a test loop its own token bucket, so the target throughput
+	// applies to each goroutine individually.
+	GoroutineScope ThroughputScope = "goroutine"
+)
+
+const (
+	StatusKeyTargetOpsPerSecond   = "targetOpsPerSecond"
+	StatusKeyAchievedOpsPerSecond = "achievedOpsPerSecond"
+	throughputPublishInterval     = 1 * time.Second
+	// Allows a goroutine to make up for ~100 ms worth of operations it fell behind on, e.g. because the cluster
+	// responded slowly for a moment, without bursting so much the pacing would become uneven.
+	burstFraction = 10
+)
+
+func ValidateThroughputScope(keyPath string, a any) error {
+	if err := client.ValidateString(keyPath, a); err != nil {
+		return err
+	}
+
+	switch a {
+	case string(RunnerScope), string(GoroutineScope):
+		return nil
+	default:
+		return fmt.Errorf("throughput scope expected to be either '%s' or '%s', got %v", RunnerScope, GoroutineScope, a)
+	}
+
+}
+
+// NewThroughputRegulator assembles a regulator for a test loop running the given number of goroutines. In case
+// throughput regulation has not been enabled, nil is returned, and since a nil regulator neither paces operations
+// nor reports anything, test loops can work with the result regardless of the configuration.
+func NewThroughputRegulator(c *ThroughputConfig, numGoroutines int, g *status.Gatherer) *ThroughputRegulator {
+
+	if c == nil || !c.Enabled {
+		return nil
+	}
+
+	var profile *LoadProfileConfig
+	if c.LoadProfile != nil && c.LoadProfile.Enabled {
+		profile = c.LoadProfile
+	}
+
+	numLimiters := 1
+	if c.Scope == GoroutineScope {
+		numLimiters = max(1, numGoroutines)
+	}
+
+	limiters := make([]*rate.Limiter, numLimiters)
+	for i := 0; i < numLimiters; i++ {
+		limiters[i] = rate.NewLimiter(rate.Limit(c.TargetOpsPerSecond), max(1, c.TargetOpsPerSecond/burstFraction))
+	}
+
+	now := time.Now()
+	r := &ThroughputRegulator{
+		limiters:         limiters,
+		profile:          profile,
+		peakOpsPerSecond: c.TargetOpsPerSecond,
+		started:          now,
+		lastPublished:    now,
+		g:                g,
+	}
+	r.adjustTarget(0)
+
+	g.Updates <- status.Update{Key: StatusKeyAchievedOpsPerSecond, Value: float64(0)}
+
+	return r
+
+}
+
+// Await blocks until the goroutine having the given index is allowed to perform its next operation.
+func (r *ThroughputRegulator) Await(ctx context.Context, goroutineIndex int) {
+
+	if r == nil {
+		return
+	}
+
+	limiter := r.limiters[0]
+	if len(r.limiters) > 1 {
+		limiter = r.limiters[goroutineIndex%len(r.limiters)]
+	}
+
+	// Waiting only fails if the given context is done, in which case the test loop is about to stop anyway
+	_ = limiter.Wait(ctx)
+
+	var publishDue bool
+	r.l.Lock()
+	{
+		r.numOps++
+		publishDue = time.Since(r.lastPublished) >= throughputPublishInterval
+	}
+	r.l.Unlock()
+
+	if publishDue {
+		r.adjustTarget(time.Since(r.started))
+		r.Publish()
+	}
+
+}
+
+// adjustTarget applies the load profile, if any, for the given amount of time elapsed since the regulator was
+// created, and reports the resulting target throughput for all goroutines together in case it has changed.
+func (r *ThroughputRegulator) adjustTarget(elapsed time.Duration) {
+
+	perLimiter := r.peakOpsPerSecond
+	if r.profile != nil {
+		perLimiter = max(1, r.profile.targetOpsPerSecond(elapsed, r.peakOpsPerSecond))
+	}
+
+	var changed bool
+	var target int
+	r.l.Lock()
+	{
+		target = perLimiter * len(r.limiters)
+		changed = target != r.targetOpsPerSecond
+		if changed {
+			r.targetOpsPerSecond = target
+			for _, limiter := range r.limiters {
+				limiter.SetLimit(rate.Limit(perLimiter))
+				limiter.SetBurst(max(1, perLimiter/burstFraction))
+			}
+		}
+	}
+	r.l.Unlock()
+
+	if changed {
+		r.g.Updates <- status.Update{Key: StatusKeyTargetOpsPerSecond, Value: target}
+	}
+
+}
+
+// Publish reports the throughput achieved since the previous publication.
+func (r *ThroughputRegulator) Publish() {
+
+	if r == nil {
+		return
+	}
+
+	var achieved float64
+	r.l.Lock()
+	{
+		now := time.Now()
+		if elapsed := now.Sub(r.lastPublished).Seconds(); elapsed > 0 {
+			achieved = math.Round(float64(r.numOps-r.numOpsAtLastPublish)/elapsed*100) / 100
+		}
+		r.numOpsAtLastPublish = r.numOps
+		r.lastPublished = now
+	}
+	r.l.Unlock()
+
+	r.g.Updates <- status.Update{Key: StatusKeyAchievedOpsPerSecond, Value: achieved}
+
+}
@@ -0,0 +1,267 @@
+package loadsupport
+
+import (
+	"context"
+	"hazeltest/status"
+	"testing"
+	"time"
+)
+
+func TestValidateThroughputScope(t *testing.T) {
+
+	t.Log("given a function to validate the scope of a throughput configuration")
+	{
+		keyPath := "mapTests.load.throughput.scope"
+
+		t.Log("\twhen valid scope is provided")
+		{
+			for _, v := range []ThroughputScope{RunnerScope, GoroutineScope} {
+				err := ValidateThroughputScope(keyPath, string(v))
+
+				msg := "\t\tno error must be returned"
+				if err == nil {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+		}
+
+		t.Log("\twhen invalid scope is provided")
+		{
+			for _, v := range []any{"", "cluster", 42} {
+				err := ValidateThroughputScope(keyPath, v)
+
+				msg := "\t\terror must be returned"
+				if err != nil {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+		}
+	}
+
+}
+
+func TestNewThroughputRegulator(t *testing.T) {
+
+	t.Log("given a function to assemble a throughput regulator")
+	{
+		t.Log("\twhen throughput regulation has not been enabled")
+		{
+			for _, c := range []*ThroughputConfig{nil, {Enabled: false, TargetOpsPerSecond: 100, Scope: RunnerScope}} {
+				g := status.NewGatherer()
+				r := NewThroughputRegulator(c, 5, g)
+
+				msg := "\t\tno regulator must be returned"
+				if r == nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX)
+				}
+
+				msg = "\t\tno status must have been reported"
+				if len(g.Updates) == 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, len(g.Updates))
+				}
+			}
+		}
+
+		t.Log("\twhen scope is runner")
+		{
+			g := status.NewGatherer()
+			r := NewThroughputRegulator(&ThroughputConfig{Enabled: true, TargetOpsPerSecond: 100, Scope: RunnerScope}, 5, g)
+
+			msg := "\t\tall goroutines must share one token bucket"
+			if len(r.limiters) == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, len(r.limiters))
+			}
+
+			msg = "\t\ttarget throughput must have been reported as configured"
+			if u := <-g.Updates; u.Key == StatusKeyTargetOpsPerSecond && u.Value == 100 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, u)
+			}
+
+			msg = "\t\tachieved throughput must have been reported as zero"
+			if u := <-g.Updates; u.Key == StatusKeyAchievedOpsPerSecond && u.Value == float64(0) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, u)
+			}
+		}
+
+		t.Log("\twhen scope is goroutine")
+		{
+			g := status.NewGatherer()
+			r := NewThroughputRegulator(&ThroughputConfig{Enabled: true, TargetOpsPerSecond: 100, Scope: GoroutineScope}, 5, g)
+
+			msg := "\t\teach goroutine must have its own token bucket"
+			if len(r.limiters) == 5 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, len(r.limiters))
+			}
+
+			msg = "\t\ttarget throughput must have been reported for all goroutines together"
+			if u := <-g.Updates; u.Key == StatusKeyTargetOpsPerSecond && u.Value == 500 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, u)
+			}
+		}
+	}
+
+}
+
+func TestThroughputRegulatorAdjustTarget(t *testing.T) {
+
+	t.Log("given a throughput regulator's method to adjust the target throughput according to the load profile")
+	{
+		t.Log("\twhen load profile has been enabled")
+		{
+			g := status.NewGatherer()
+			profile := &LoadProfileConfig{Enabled: true, Type: Ramp, Ramp: &RampConfig{StartOpsPerSecond: 100, Duration: 10 * time.Minute}}
+			r := NewThroughputRegulator(&ThroughputConfig{Enabled: true, TargetOpsPerSecond: 1000, Scope: GoroutineScope, LoadProfile: profile}, 2, g)
+
+			msg := "\t\tinitial target throughput must be start of profile"
+			if u := <-g.Updates; u.Key == StatusKeyTargetOpsPerSecond && u.Value == 200 && float64(r.limiters[0].Limit()) == 100 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, u)
+			}
+			<-g.Updates
+
+			r.adjustTarget(5 * time.Minute)
+
+			msg = "\t\tadjusted target throughput must have been applied to all token buckets and reported"
+			if u := <-g.Updates; u.Key == StatusKeyTargetOpsPerSecond && u.Value == 1100 &&
+				float64(r.limiters[0].Limit()) == 550 && float64(r.limiters[1].Limit()) == 550 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, u)
+			}
+
+			r.adjustTarget(5 * time.Minute)
+
+			msg = "\t\tunchanged target throughput must not be reported again"
+			if len(g.Updates) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, len(g.Updates))
+			}
+		}
+
+		t.Log("\twhen load profile has not been enabled")
+		{
+			g := status.NewGatherer()
+			profile := &LoadProfileConfig{Enabled: false, Type: Ramp, Ramp: &RampConfig{StartOpsPerSecond: 100, Duration: 10 * time.Minute}}
+			r := NewThroughputRegulator(&ThroughputConfig{Enabled: true, TargetOpsPerSecond: 1000, Scope: RunnerScope, LoadProfile: profile}, 2, g)
+			<-g.Updates
+			<-g.Updates
+
+			r.adjustTarget(5 * time.Minute)
+
+			msg := "\t\ttarget throughput must remain constant"
+			if len(g.Updates) == 0 && float64(r.limiters[0].Limit()) == 1000 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestThroughputRegulatorAwait(t *testing.T) {
+
+	t.Log("given a throughput regulator's method to wait for permission to perform the next operation")
+	{
+		t.Log("\twhen regulator is nil")
+		{
+			var r *ThroughputRegulator
+
+			msg := "\t\tmethod must return immediately"
+			start := time.Now()
+			for i := 0; i < 1_000; i++ {
+				r.Await(context.TODO(), 0)
+			}
+			r.Publish()
+			if time.Since(start) < 50*time.Millisecond {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, time.Since(start))
+			}
+		}
+
+		t.Log("\twhen number of operations exceeds target throughput")
+		{
+			g := status.NewGatherer()
+			r := NewThroughputRegulator(&ThroughputConfig{Enabled: true, TargetOpsPerSecond: 50, Scope: RunnerScope}, 1, g)
+
+			// Burst of 5 operations, and 10 more operations at 50 per second
+			start := time.Now()
+			for i := 0; i < 15; i++ {
+				r.Await(context.TODO(), 0)
+			}
+			elapsed := time.Since(start)
+
+			msg := "\t\toperations must have been paced according to target throughput"
+			if elapsed >= 180*time.Millisecond {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, elapsed)
+			}
+
+			msg = "\t\tnumber of operations must have been recorded"
+			if r.numOps == 15 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, r.numOps)
+			}
+		}
+	}
+
+}
+
+func TestThroughputRegulatorPublish(t *testing.T) {
+
+	t.Log("given a throughput regulator's method to publish the achieved throughput")
+	{
+		t.Log("\twhen operations have been performed since last publication")
+		{
+			g := status.NewGatherer()
+			r := NewThroughputRegulator(&ThroughputConfig{Enabled: true, TargetOpsPerSecond: 100, Scope: RunnerScope}, 1, g)
+			<-g.Updates
+			<-g.Updates
+
+			r.numOps = 150
+			r.numOpsAtLastPublish = 50
+			r.lastPublished = time.Now().Add(-2 * time.Second)
+
+			r.Publish()
+
+			msg := "\t\tachieved throughput must refer to operations since last publication"
+			u := <-g.Updates
+			if achieved, ok := u.Value.(float64); ok && u.Key == StatusKeyAchievedOpsPerSecond && achieved > 49 && achieved <= 50 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, u)
+			}
+
+			msg = "\t\tnumber of operations at last publication must have been updated"
+			if r.numOpsAtLastPublish == 150 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, r.numOpsAtLastPublish)
+			}
+		}
+	}
+
+}
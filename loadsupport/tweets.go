@@ -0,0 +1,57 @@
+package loadsupport
+
+import (
+	"embed"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io/fs"
+)
+
+type (
+	TweetCollection struct {
+		Tweets []Tweet `json:"Tweets"`
+	}
+	Tweet struct {
+		Id        uint64 `json:"Id"`
+		CreatedAt string `json:"CreatedAt"`
+		Text      string `json:"Text"`
+	}
+)
+
+// The file containing 500 tweets on Marvel's "Avengers: Endgame" movie is embedded only once, here, so the
+// TweetRunners for queues and topics work on the very same data set
+//
+//go:embed tweets_simple.json
+var tweetsFile embed.FS
+
+func init() {
+	gob.Register(Tweet{})
+}
+
+func ParseTweets() (*TweetCollection, error) {
+
+	tweetsJson, err := tweetsFile.Open("tweets_simple.json")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(tweetsJson fs.File) {
+		err := tweetsJson.Close()
+		if err != nil {
+			lp.LogIoEvent(fmt.Sprintf("unable to close tweets json file: %v", err), log.WarnLevel)
+		}
+	}(tweetsJson)
+
+	var tc TweetCollection
+	err = json.NewDecoder(tweetsJson).Decode(&tc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &tc, nil
+
+}
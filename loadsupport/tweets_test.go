@@ -0,0 +1,36 @@
+package loadsupport
+
+import "testing"
+
+func TestParseTweets(t *testing.T) {
+
+	t.Log("given the embedded file containing the tweets the tweet runners work with")
+	{
+		t.Log("\twhen file is parsed")
+		{
+			tc, err := ParseTweets()
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tall tweets must have been parsed"
+			if len(tc.Tweets) == 500 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, len(tc.Tweets))
+			}
+
+			msg = "\t\ttweets must carry id and text"
+			if tc.Tweets[0].Id != 0 && tc.Tweets[0].Text != "" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, tc.Tweets[0])
+			}
+		}
+	}
+
+}
@@ -169,6 +169,42 @@ func (lp *LogProvider) LogQueueRunnerEvent(msg, runnerName string, level log.Lev
 
 }
 
+func (lp *LogProvider) LogTopicRunnerEvent(msg, runnerName string, level log.Level) {
+
+	fields := log.Fields{
+		"kind":       RunnerEvent,
+		"runnerName": runnerName,
+		"runnerKind": "topic",
+	}
+
+	lp.doLog(msg, fields, level)
+
+}
+
+func (lp *LogProvider) LogCollectionRunnerEvent(msg, runnerName string, level log.Level) {
+
+	fields := log.Fields{
+		"kind":       RunnerEvent,
+		"runnerName": runnerName,
+		"runnerKind": "collection",
+	}
+
+	lp.doLog(msg, fields, level)
+
+}
+
+func (lp *LogProvider) LogCounterRunnerEvent(msg, runnerName string, level log.Level) {
+
+	fields := log.Fields{
+		"kind":       RunnerEvent,
+		"runnerName": runnerName,
+		"runnerKind": "counter",
+	}
+
+	lp.doLog(msg, fields, level)
+
+}
+
 func (lp *LogProvider) LogHzEvent(msg string, level log.Level) {
 
 	fields := log.Fields{
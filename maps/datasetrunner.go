@@ -0,0 +1,391 @@
+package maps
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/state"
+	"hazeltest/status"
+	"io"
+	"os"
+)
+
+type (
+	datasetRunner struct {
+		assigner        client.ConfigPropertyAssigner
+		stateList       []runnerState
+		name            string
+		source          string
+		hzClientHandler hazelcastwrapper.HzClientHandler
+		hzMapStore      hazelcastwrapper.MapStore
+		l               looper[datasetElement]
+		gatherer        *status.Gatherer
+		raiseReady      func()
+		raiseNotReady   func()
+		providerFuncs   struct {
+			mapStore               newMapStoreFunc
+			datasetElementTestLoop newDatasetElementTestLoopFunc
+			datasetFile            openDatasetFileFunc
+		}
+	}
+	// datasetElement represents one record of a user-supplied dataset. Records are not decoded into a fixed type, so
+	// the dataset can be shaped like whatever entries the Hazelcast cluster under test holds in production.
+	datasetElement                map[string]any
+	newDatasetElementTestLoopFunc func(rc *runnerConfig) (looper[datasetElement], error)
+	openDatasetFileFunc           func(path string) (io.ReadCloser, error)
+	datasetFormat                 string
+	datasetConfig                 struct {
+		path    string
+		format  datasetFormat
+		idField string
+	}
+)
+
+const (
+	mapDatasetRunnerKeyPath     = "mapTests.dataset"
+	mapDatasetRunnerMapBaseName = "dataset"
+	mapDatasetRunnerName        = "mapsDatasetRunner"
+)
+
+const (
+	jsonFormat  datasetFormat = "json"
+	jsonlFormat datasetFormat = "jsonl"
+	csvFormat   datasetFormat = "csv"
+	// Lines of a JSONL file can be considerably longer than the scanner's default maximum token size of 64 KiB
+	maxJsonlLineSizeBytes = 16 * 1024 * 1024
+)
+
+func init() {
+	register(&datasetRunner{
+		assigner:        &client.DefaultConfigPropertyAssigner{},
+		stateList:       []runnerState{},
+		name:            mapDatasetRunnerName,
+		source:          "datasetRunner",
+		hzClientHandler: &hazelcastwrapper.DefaultHzClientHandler{},
+		raiseReady:      api.RaiseReady,
+		raiseNotReady:   api.RaiseNotReady,
+		providerFuncs: struct {
+			mapStore               newMapStoreFunc
+			datasetElementTestLoop newDatasetElementTestLoopFunc
+			datasetFile            openDatasetFileFunc
+		}{mapStore: newDefaultMapStore, datasetElementTestLoop: newDatasetElementTestLoop, datasetFile: openDatasetFile},
+	})
+	// Values of records decoded from JSON can be nested objects, arrays, and numbers, all of which get
+	// serialized as interface values
+	gob.Register(datasetElement{})
+	gob.Register(map[string]any{})
+	gob.Register([]any{})
+	gob.Register(json.Number(""))
+}
+
+func newDatasetElementTestLoop(rc *runnerConfig) (looper[datasetElement], error) {
+
+	switch rc.loopType {
+	case batch:
+		return &batchTestLoop[datasetElement]{}, nil
+	case boundary:
+		return &boundaryTestLoop[datasetElement]{}, nil
+	default:
+		return nil, fmt.Errorf("no such runner runnerLoopType: %s", rc.loopType)
+	}
+
+}
+
+func openDatasetFile(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+func (r *datasetRunner) getSourceName() string {
+	return "datasetRunner"
+}
+
+func (r *datasetRunner) runMapTests(ctx context.Context, hzCluster string, hzMembers []string, gatherer *status.Gatherer) {
+
+	r.gatherer = gatherer
+	r.appendState(start)
+
+	config, dc, err := populateDatasetConfig(r.assigner)
+	if err != nil {
+		lp.LogMapRunnerEvent(fmt.Sprintf("aborting launch of map dataset runner: unable to populate config: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.appendState(populateConfigComplete)
+
+	if !config.enabled {
+		lp.LogMapRunnerEvent("dataset runner not enabled -- won't run", r.name, log.InfoLevel)
+		return
+	}
+	r.appendState(checkEnabledComplete)
+
+	// Dataset is loaded and test loop initialized prior to raising not ready so a missing or malformed dataset file
+	// does not leave this Hazeltest instance not ready for good
+	elements, err := r.loadDataset(dc)
+	if err != nil {
+		lp.LogIoEvent(fmt.Sprintf("aborting launch of map dataset runner: unable to load dataset from file '%s': %s", dc.path, err), log.ErrorLevel)
+		return
+	}
+	lp.LogMapRunnerEvent(fmt.Sprintf("loaded %d elements from dataset file '%s'", len(elements), dc.path), r.name, log.InfoLevel)
+
+	l, err := r.providerFuncs.datasetElementTestLoop(config)
+	if err != nil {
+		lp.LogMapRunnerEvent(fmt.Sprintf("aborting launch of map dataset runner: unable to initialize test loop: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.l = l
+
+	r.appendState(assignTestLoopComplete)
+
+	r.raiseNotReady()
+
+	r.hzClientHandler.InitHazelcastClient(ctx, r.name, hzCluster, hzMembers)
+	defer func() {
+		_ = r.hzClientHandler.Shutdown(ctx)
+	}()
+	r.hzMapStore = r.providerFuncs.mapStore(r.hzClientHandler)
+
+	r.raiseReady()
+	r.appendState(raiseReadyComplete)
+
+	lp.LogMapRunnerEvent("initialized hazelcast client", r.name, log.InfoLevel)
+	lp.LogMapRunnerEvent("starting dataset test loop for maps", r.name, log.InfoLevel)
+
+	tle := &testLoopExecution[datasetElement]{
+		id:                   uuid.New(),
+		runnerName:           r.name,
+		source:               r.source,
+		hzClientHandler:      r.hzClientHandler,
+		hzMapStore:           r.hzMapStore,
+		stateCleanerBuilder:  &state.DefaultSingleMapCleanerBuilder{},
+		runnerConfig:         config,
+		elements:             elements,
+		ctx:                  ctx,
+		getElementID:         datasetElementIDFunc(dc.idField),
+		getOrAssemblePayload: returnDatasetElementPayload,
+	}
+
+	r.l.init(tle, &defaultSleeper{}, r.gatherer)
+
+	r.appendState(testLoopStart)
+	r.l.run()
+	r.appendState(testLoopComplete)
+
+	lp.LogMapRunnerEvent("finished dataset maps loop", r.name, log.InfoLevel)
+
+}
+
+func (r *datasetRunner) appendState(s runnerState) {
+
+	r.stateList = append(r.stateList, s)
+	r.gatherer.Updates <- status.Update{Key: string(statusKeyCurrentState), Value: string(s)}
+
+}
+
+func (r *datasetRunner) loadDataset(dc *datasetConfig) ([]datasetElement, error) {
+
+	f, err := r.providerFuncs.datasetFile(dc.path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			lp.LogMapRunnerEvent(fmt.Sprintf("unable to close dataset file '%s'", dc.path), r.name, log.WarnLevel)
+		}
+	}()
+
+	return parseDataset(f, dc.format, dc.idField)
+
+}
+
+func returnDatasetElementPayload(_ string, _ uint16, element any) (any, error) {
+	return element, nil
+}
+
+func datasetElementIDFunc(idField string) getElementIdFunc {
+
+	return func(element any) string {
+		return fmt.Sprintf("%v", element.(datasetElement)[idField])
+	}
+
+}
+
+// parseDataset decodes the records contained in the given dataset and verifies each record carries a unique value
+// in the given ID field, as the element ID forms part of the map key, and records sharing the same ID would
+// therefore overwrite each other in the target map.
+func parseDataset(r io.Reader, format datasetFormat, idField string) ([]datasetElement, error) {
+
+	var elements []datasetElement
+	var err error
+	switch format {
+	case jsonFormat:
+		elements, err = parseJsonDataset(r)
+	case jsonlFormat:
+		elements, err = parseJsonlDataset(r)
+	case csvFormat:
+		elements, err = parseCsvDataset(r)
+	default:
+		return nil, fmt.Errorf("no such dataset format: %s", format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) == 0 {
+		return nil, errors.New("dataset does not contain any records")
+	}
+
+	ids := make(map[string]struct{}, len(elements))
+	for i, e := range elements {
+		v, ok := e[idField]
+		if !ok || v == nil {
+			return nil, fmt.Errorf("record %d of dataset does not contain id field '%s'", i, idField)
+		}
+		id := fmt.Sprintf("%v", v)
+		if _, ok := ids[id]; ok {
+			return nil, fmt.Errorf("record %d of dataset has id '%s', which is not unique", i, id)
+		}
+		ids[id] = struct{}{}
+	}
+
+	return elements, nil
+
+}
+
+func parseJsonDataset(r io.Reader) ([]datasetElement, error) {
+
+	d := json.NewDecoder(r)
+	// Keeps numbers in their original representation, so, for example, an ID of 25 doesn't become 25.0 or 2.5e+01
+	d.UseNumber()
+
+	var elements []datasetElement
+	if err := d.Decode(&elements); err != nil {
+		return nil, fmt.Errorf("unable to decode json dataset, expected array of objects: %w", err)
+	}
+
+	return elements, nil
+
+}
+
+func parseJsonlDataset(r io.Reader) ([]datasetElement, error) {
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxJsonlLineSizeBytes)
+
+	var elements []datasetElement
+	for lineNumber := 1; s.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		d := json.NewDecoder(bytes.NewReader(line))
+		d.UseNumber()
+		var e datasetElement
+		if err := d.Decode(&e); err != nil {
+			return nil, fmt.Errorf("unable to decode line %d of jsonl dataset: %w", lineNumber, err)
+		}
+		elements = append(elements, e)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read jsonl dataset: %w", err)
+	}
+
+	return elements, nil
+
+}
+
+// parseCsvDataset treats the first row of the given dataset as header row and uses its columns as field names for
+// all subsequent rows. All values are kept as strings.
+func parseCsvDataset(r io.Reader) ([]datasetElement, error) {
+
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read csv dataset: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("csv dataset does not contain header row")
+	}
+
+	header := records[0]
+	elements := make([]datasetElement, 0, len(records)-1)
+	for _, record := range records[1:] {
+		e := make(datasetElement, len(header))
+		for i, field := range header {
+			e[field] = record[i]
+		}
+		elements = append(elements, e)
+	}
+
+	return elements, nil
+
+}
+
+func validateDatasetFormat(keyPath string, a any) error {
+
+	if err := client.ValidateString(keyPath, a); err != nil {
+		return err
+	}
+
+	switch datasetFormat(a.(string)) {
+	case jsonFormat, jsonlFormat, csvFormat:
+		return nil
+	default:
+		return fmt.Errorf("dataset format expected to be one of '%s', '%s', or '%s', got %v", jsonFormat, jsonlFormat, csvFormat, a)
+	}
+
+}
+
+func populateDatasetConfig(a client.ConfigPropertyAssigner) (*runnerConfig, *datasetConfig, error) {
+
+	var assignmentOps []func() error
+
+	dc := &datasetConfig{}
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapDatasetRunnerKeyPath+".file.path", client.ValidateString, func(a any) {
+			dc.path = a.(string)
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapDatasetRunnerKeyPath+".file.format", validateDatasetFormat, func(a any) {
+			dc.format = datasetFormat(a.(string))
+		})
+	})
+
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapDatasetRunnerKeyPath+".file.idField", client.ValidateString, func(a any) {
+			dc.idField = a.(string)
+		})
+	})
+
+	for _, fn := range assignmentOps {
+		if err := fn(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	configBuilder := runnerConfigBuilder{
+		assigner:      a,
+		runnerKeyPath: mapDatasetRunnerKeyPath,
+		mapBaseName:   mapDatasetRunnerMapBaseName,
+	}
+
+	cfg, err := configBuilder.populateConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, dc, nil
+
+}
@@ -0,0 +1,653 @@
+package maps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"io"
+	"strings"
+	"testing"
+)
+
+type (
+	testDatasetTestLoop struct {
+		assignedTestLoopExecution *testLoopExecution[datasetElement]
+		numRunInvocations         int
+	}
+)
+
+const (
+	testJsonDataset = `[
+		{"id": 1, "name": "Frodo", "race": "Hobbit", "companions": ["Sam", "Merry", "Pippin"]},
+		{"id": 2, "name": "Aragorn", "race": "Man", "heir": {"of": "Isildur"}}
+	]`
+	testJsonlDataset = `{"id": "frodo", "name": "Frodo"}
+
+{"id": "gandalf", "name": "Gandalf"}
+{"id": "legolas", "name": "Legolas"}
+`
+	testCsvDataset = `id,name,race
+1,Frodo,Hobbit
+2,Gimli,Dwarf
+`
+)
+
+func (d *testDatasetTestLoop) init(tle *testLoopExecution[datasetElement], _ sleeper, _ *status.Gatherer) {
+	d.assignedTestLoopExecution = tle
+}
+
+func (d *testDatasetTestLoop) run() {
+	d.numRunInvocations++
+}
+
+func TestNewDatasetElementTestLoop(t *testing.T) {
+
+	t.Log("given a function to initialize the test loop from the provided loop type")
+	{
+		t.Log("\twhen boundary test loop type is provided")
+		{
+			l, err := newDatasetElementTestLoop(&runnerConfig{loopType: boundary})
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tlooper must have expected type"
+			if _, ok := l.(*boundaryTestLoop[datasetElement]); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen batch test loop type is provided")
+		{
+			l, err := newDatasetElementTestLoop(&runnerConfig{loopType: batch})
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tlooper must have expected type"
+			if _, ok := l.(*batchTestLoop[datasetElement]); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen unknown test loop type is provided")
+		{
+			l, err := newDatasetElementTestLoop(&runnerConfig{loopType: "saruman"})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tlooper must be nil"
+			if l == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestParseDataset(t *testing.T) {
+
+	t.Log("given a function to parse a dataset in one of the supported formats")
+	{
+		t.Log("\twhen json dataset is provided")
+		{
+			elements, err := parseDataset(strings.NewReader(testJsonDataset), jsonFormat, "id")
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tall records must have been parsed"
+			if len(elements) == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, len(elements))
+			}
+
+			msg = "\t\tnumeric ids must have retained their original representation"
+			if id := datasetElementIDFunc("id")(elements[1]); id == "2" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, id)
+			}
+
+			msg = "\t\tnested values must have been retained"
+			if companions, ok := elements[0]["companions"].([]any); ok && len(companions) == 3 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, elements[0]["companions"])
+			}
+		}
+
+		t.Log("\twhen jsonl dataset containing empty line is provided")
+		{
+			elements, err := parseDataset(strings.NewReader(testJsonlDataset), jsonlFormat, "id")
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tall records must have been parsed, with empty line having been skipped"
+			if len(elements) == 3 && elements[2]["name"] == "Legolas" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, elements)
+			}
+		}
+
+		t.Log("\twhen csv dataset is provided")
+		{
+			elements, err := parseDataset(strings.NewReader(testCsvDataset), csvFormat, "id")
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\trecords must have been parsed using columns of header row as field names"
+			if len(elements) == 2 && elements[1]["id"] == "2" && elements[1]["name"] == "Gimli" && elements[1]["race"] == "Dwarf" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, elements)
+			}
+		}
+
+		for _, tc := range []struct {
+			description string
+			dataset     string
+			format      datasetFormat
+		}{
+			{"record lacks id field", `[{"id": 1}, {"name": "Boromir"}]`, jsonFormat},
+			{"record has null id", `{"id": null}`, jsonlFormat},
+			{"two records share the same id", "id,name\n1,Merry\n1,Pippin\n", csvFormat},
+			{"dataset does not contain any records", `[]`, jsonFormat},
+			{"csv dataset does not contain header row", "", csvFormat},
+			{"json dataset is not an array of objects", `{"id": 1}`, jsonFormat},
+			{"jsonl dataset contains malformed line", "{\"id\": 1}\n{\"id\": \n", jsonlFormat},
+			{"csv row has wrong number of fields", "id,name\n1,Sam,Gamgee\n", csvFormat},
+			{"format is unknown", `[{"id": 1}]`, "xml"},
+		} {
+			t.Log(fmt.Sprintf("\twhen %s", tc.description))
+			{
+				elements, err := parseDataset(strings.NewReader(tc.dataset), tc.format, "id")
+
+				msg := "\t\terror must be returned"
+				if err != nil {
+					t.Log(msg, checkMark, err)
+				} else {
+					t.Fatal(msg, ballotX)
+				}
+
+				msg = "\t\tno elements must be returned"
+				if elements == nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, elements)
+				}
+			}
+		}
+	}
+
+}
+
+func TestValidateDatasetFormat(t *testing.T) {
+
+	t.Log("given a function to validate the format of a dataset")
+	{
+		keyPath := mapDatasetRunnerKeyPath + ".file.format"
+
+		t.Log("\twhen valid format is provided")
+		{
+			for _, v := range []datasetFormat{jsonFormat, jsonlFormat, csvFormat} {
+				err := validateDatasetFormat(keyPath, string(v))
+
+				msg := "\t\tno error must be returned"
+				if err == nil {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+		}
+
+		t.Log("\twhen invalid format is provided")
+		{
+			for _, v := range []any{"", "xml", 42} {
+				err := validateDatasetFormat(keyPath, v)
+
+				msg := "\t\terror must be returned"
+				if err != nil {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+		}
+	}
+
+}
+
+func TestPopulateDatasetConfig(t *testing.T) {
+
+	t.Log("given set of configuration properties to populate the dataset config from")
+	{
+		t.Log("\twhen property contains invalid value")
+		{
+			a := &testConfigPropertyAssigner{
+				testConfig: map[string]any{
+					mapDatasetRunnerKeyPath + ".file.format": "xml",
+				},
+			}
+
+			cfg, dc, err := populateDatasetConfig(a)
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\treturned configs must be nil"
+			if cfg == nil && dc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+		t.Log("\twhen properties are correct")
+		{
+			a := &testConfigPropertyAssigner{
+				testConfig: map[string]any{
+					mapDatasetRunnerKeyPath + ".enabled":       true,
+					mapDatasetRunnerKeyPath + ".numMaps":       5,
+					mapDatasetRunnerKeyPath + ".file.path":     "/data/datasets/fellowship.jsonl",
+					mapDatasetRunnerKeyPath + ".file.format":   "jsonl",
+					mapDatasetRunnerKeyPath + ".file.idField":  "name",
+					mapDatasetRunnerKeyPath + ".testLoop.type": "batch",
+				},
+			}
+
+			cfg, dc, err := populateDatasetConfig(a)
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tdataset config must contain expected values"
+			if *dc == (datasetConfig{path: "/data/datasets/fellowship.jsonl", format: jsonlFormat, idField: "name"}) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, dc)
+			}
+
+			msg = "\t\trunner config must have been populated from dataset runner key path"
+			if cfg.enabled && cfg.numMaps == 5 && cfg.loopType == batch && cfg.mapBaseName == mapDatasetRunnerMapBaseName {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cfg)
+			}
+		}
+	}
+
+}
+
+func TestRunDatasetMapTests(t *testing.T) {
+
+	t.Log("given the dataset runner to run map tests")
+	{
+		genericMsgStateTransitions := "\t\tstate transitions must be correct"
+		genericMsgLatestStateInGatherer := "\t\tlatest state in gatherer must be correct"
+
+		t.Log("\twhen runner configuration cannot be populated")
+		{
+			ch := &testHzClientHandler{}
+			r := datasetRunner{
+				assigner:        testConfigPropertyAssigner{returnError: true},
+				stateList:       []runnerState{},
+				hzClientHandler: ch,
+			}
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runMapTests(context.TODO(), hzCluster, hzMembers, gatherer)
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(r.gatherer, start) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark, start)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, start)
+			}
+
+			msg := "\t\thazelcast client handler must not have initialized hazelcast client"
+			if ch.initClientInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations)
+			}
+		}
+
+		t.Log("\twhen runner has been disabled")
+		{
+			ch := &testHzClientHandler{}
+			r := datasetRunner{
+				assigner: testConfigPropertyAssigner{testConfig: map[string]any{
+					mapDatasetRunnerKeyPath + ".enabled": false,
+				}},
+				stateList:       []runnerState{},
+				hzClientHandler: ch,
+			}
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runMapTests(context.TODO(), hzCluster, hzMembers, gatherer)
+			gatherer.StopListen()
+
+			latestState := populateConfigComplete
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start, latestState}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(r.gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark, latestState)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+
+			msg := "\t\thazelcast client handler must not have initialized hazelcast client"
+			if ch.initClientInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations)
+			}
+		}
+
+		t.Log("\twhen dataset file cannot be opened")
+		{
+			ch := &testHzClientHandler{}
+			l := &testDatasetTestLoop{}
+			numNotReadyInvocations := 0
+			r := datasetRunner{
+				assigner: testConfigPropertyAssigner{testConfig: map[string]any{
+					mapDatasetRunnerKeyPath + ".enabled":       true,
+					mapDatasetRunnerKeyPath + ".file.format":   "json",
+					mapDatasetRunnerKeyPath + ".testLoop.type": "batch",
+				}},
+				stateList:       []runnerState{},
+				hzClientHandler: ch,
+				raiseNotReady: func() {
+					numNotReadyInvocations++
+				},
+				providerFuncs: struct {
+					mapStore               newMapStoreFunc
+					datasetElementTestLoop newDatasetElementTestLoopFunc
+					datasetFile            openDatasetFileFunc
+				}{datasetElementTestLoop: func(_ *runnerConfig) (looper[datasetElement], error) {
+					return l, nil
+				}, datasetFile: func(_ string) (io.ReadCloser, error) {
+					return nil, errors.New("one does not simply open a dataset file")
+				}},
+			}
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runMapTests(context.TODO(), hzCluster, hzMembers, gatherer)
+			gatherer.StopListen()
+
+			latestState := checkEnabledComplete
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start, populateConfigComplete, latestState}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(r.gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark, latestState)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+
+			msg := "\t\thazelcast client handler must not have initialized hazelcast client"
+			if ch.initClientInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations)
+			}
+
+			msg = "\t\ttest loop must not have been run"
+			if l.numRunInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, l.numRunInvocations)
+			}
+
+			msg = "\t\trunner must not have raised not ready"
+			if numNotReadyInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, numNotReadyInvocations)
+			}
+		}
+
+		t.Log("\twhen dataset file is malformed")
+		{
+			ch := &testHzClientHandler{}
+			numNotReadyInvocations := 0
+			r := datasetRunner{
+				assigner: testConfigPropertyAssigner{testConfig: map[string]any{
+					mapDatasetRunnerKeyPath + ".enabled":       true,
+					mapDatasetRunnerKeyPath + ".file.format":   "jsonl",
+					mapDatasetRunnerKeyPath + ".testLoop.type": "batch",
+				}},
+				stateList:       []runnerState{},
+				hzClientHandler: ch,
+				raiseNotReady: func() {
+					numNotReadyInvocations++
+				},
+				providerFuncs: struct {
+					mapStore               newMapStoreFunc
+					datasetElementTestLoop newDatasetElementTestLoopFunc
+					datasetFile            openDatasetFileFunc
+				}{datasetElementTestLoop: func(_ *runnerConfig) (looper[datasetElement], error) {
+					return &testDatasetTestLoop{}, nil
+				}, datasetFile: func(_ string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("{\"name\": \"Frodo\"}\n{\"name\": ")), nil
+				}},
+			}
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runMapTests(context.TODO(), hzCluster, hzMembers, gatherer)
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start, populateConfigComplete, checkEnabledComplete}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			msg := "\t\trunner must not have raised not ready"
+			if numNotReadyInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, numNotReadyInvocations)
+			}
+
+			msg = "\t\thazelcast client handler must not have initialized hazelcast client"
+			if ch.initClientInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations)
+			}
+		}
+
+		t.Log("\twhen test loop has executed")
+		{
+			ch := &testHzClientHandler{}
+			ms := &testHzMapStore{observations: &testHzMapStoreObservations{}}
+			l := &testDatasetTestLoop{}
+			openedPath := ""
+			numReadyInvocations, numNotReadyInvocations := 0, 0
+			r := datasetRunner{
+				assigner: testConfigPropertyAssigner{testConfig: map[string]any{
+					mapDatasetRunnerKeyPath + ".enabled":       true,
+					mapDatasetRunnerKeyPath + ".file.path":     "/data/datasets/dataset.csv",
+					mapDatasetRunnerKeyPath + ".file.format":   "csv",
+					mapDatasetRunnerKeyPath + ".file.idField":  "name",
+					mapDatasetRunnerKeyPath + ".testLoop.type": "batch",
+				}},
+				stateList:       []runnerState{},
+				hzClientHandler: ch,
+				raiseReady: func() {
+					numReadyInvocations++
+				},
+				raiseNotReady: func() {
+					numNotReadyInvocations++
+				},
+				providerFuncs: struct {
+					mapStore               newMapStoreFunc
+					datasetElementTestLoop newDatasetElementTestLoopFunc
+					datasetFile            openDatasetFileFunc
+				}{mapStore: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.MapStore {
+					ms.observations.numInitInvocations++
+					return ms
+				}, datasetElementTestLoop: func(_ *runnerConfig) (looper[datasetElement], error) {
+					return l, nil
+				}, datasetFile: func(path string) (io.ReadCloser, error) {
+					openedPath = path
+					return io.NopCloser(strings.NewReader(testCsvDataset)), nil
+				}},
+			}
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runMapTests(context.TODO(), hzCluster, hzMembers, gatherer)
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions(expectedStatesForFullRun, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+			latestState := expectedStatesForFullRun[len(expectedStatesForFullRun)-1]
+
+			if latestStatePresentInGatherer(r.gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark, latestState)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+
+			msg := "\t\tdataset must have been read from configured path"
+			if openedPath == "/data/datasets/dataset.csv" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, openedPath)
+			}
+
+			tle := l.assignedTestLoopExecution
+
+			msg = "\t\ttest loop execution must carry all records of dataset"
+			if len(tle.elements) == 2 && tle.elements[0]["name"] == "Frodo" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, tle.elements)
+			}
+
+			msg = "\t\telement ids must have been derived from configured id field"
+			if id := tle.getElementID(tle.elements[1]); id == "Gimli" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, id)
+			}
+
+			msg = "\t\tpayload must be dataset record itself"
+			if p, err := tle.getOrAssemblePayload("", 0, tle.elements[0]); err == nil && p.(datasetElement)["race"] == "Hobbit" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, p, err)
+			}
+
+			msg = "\t\ttest loop must have been run once"
+			if l.numRunInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, l.numRunInvocations)
+			}
+
+			msg = "\t\trunner must have raised not ready and readiness once each"
+			if numNotReadyInvocations == 1 && numReadyInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, numNotReadyInvocations, numReadyInvocations)
+			}
+
+			msg = "\t\thazelcast client handler must have initialized and shut down hazelcast client once"
+			if ch.initClientInvocations == 1 && ch.shutdownInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations, ch.shutdownInvocations)
+			}
+
+			msg = "\t\tmap store must have been initialized once"
+			if ms.observations.numInitInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ms.observations.numInitInvocations)
+			}
+		}
+	}
+
+}
@@ -0,0 +1,267 @@
+package maps
+
+import (
+	"fmt"
+	"github.com/hazelcast/hazelcast-go-client"
+	"github.com/hazelcast/hazelcast-go-client/predicate"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	entryEventVerifier interface {
+		expect(key string, k entryEventKind)
+		tolerate(key string, k entryEventKind)
+		record(key string, k entryEventKind)
+		reconcile(mapName, keyPrefix string) entryEventCounts
+		forget(keyPrefix string)
+	}
+	// mapTestLoopEntryEventVerifier keeps a tally per key of the entry events a test loop expects to be notified of
+	// given the operations it has performed, and of the entry events its listeners have actually been notified of.
+	// Events are delivered asynchronously and might even arrive before the operation causing them has returned, so
+	// expected and received events are not matched one by one, but only reconciled once a map goroutine has
+	// finished and the grace period for events still in flight has elapsed. Operations that returned an error might
+	// or might not have been applied, so they don't cause an event to be expected, but make the verifier tolerate
+	// one (that is, not report it as unexpected if it arrives nonetheless).
+	mapTestLoopEntryEventVerifier struct {
+		l        sync.Mutex
+		tallies  map[string]map[entryEventKind]*entryEventTally
+		perMap   map[string]entryEventCounts
+		gatherer *status.Gatherer
+	}
+	entryEventTally struct {
+		expected, tolerated, received int
+	}
+	// entryEventCounts is what gets reported per map in the runner's status.
+	entryEventCounts struct {
+		Received   uint64 `json:"received"`
+		Missed     uint64 `json:"missed"`
+		Unexpected uint64 `json:"unexpected"`
+	}
+	entryEventKind string
+)
+
+const (
+	// Both added and updated events, as whether a write adds or updates an entry depends on whether the entry was
+	// still present, which the test loops can't always tell (think of entries having expired in the meantime)
+	entryWritten entryEventKind = "written"
+	entryRemoved entryEventKind = "removed"
+	// Hazelcast expires and evicts entries on its own terms, so these events are counted as received, but never
+	// reported as missed or unexpected
+	entryExpiredOrEvicted entryEventKind = "expiredOrEvicted"
+)
+
+const (
+	statusKeyEntryEventsPerMap statusKey = "entryEventsPerMap"
+)
+
+var reconciledEventKinds = []entryEventKind{entryWritten, entryRemoved}
+
+func newMapTestLoopEntryEventVerifier(gatherer *status.Gatherer) *mapTestLoopEntryEventVerifier {
+
+	return &mapTestLoopEntryEventVerifier{
+		tallies:  make(map[string]map[entryEventKind]*entryEventTally),
+		perMap:   make(map[string]entryEventCounts),
+		gatherer: gatherer,
+	}
+
+}
+
+func (v *mapTestLoopEntryEventVerifier) tallyUnguarded(key string, k entryEventKind) *entryEventTally {
+
+	kinds, ok := v.tallies[key]
+	if !ok {
+		kinds = make(map[entryEventKind]*entryEventTally)
+		v.tallies[key] = kinds
+	}
+
+	t, ok := kinds[k]
+	if !ok {
+		t = &entryEventTally{}
+		kinds[k] = t
+	}
+
+	return t
+
+}
+
+func (v *mapTestLoopEntryEventVerifier) expect(key string, k entryEventKind) {
+
+	v.l.Lock()
+	{
+		v.tallyUnguarded(key, k).expected++
+	}
+	v.l.Unlock()
+
+}
+
+func (v *mapTestLoopEntryEventVerifier) tolerate(key string, k entryEventKind) {
+
+	v.l.Lock()
+	{
+		v.tallyUnguarded(key, k).tolerated++
+	}
+	v.l.Unlock()
+
+}
+
+func (v *mapTestLoopEntryEventVerifier) record(key string, k entryEventKind) {
+
+	v.l.Lock()
+	{
+		v.tallyUnguarded(key, k).received++
+	}
+	v.l.Unlock()
+
+}
+
+// reconcile compares expected and received events for all keys having the given prefix, adds the result to the
+// counts of the given map, and publishes the counts of all maps to the status gatherer. Keys reconciled are no
+// longer tracked.
+func (v *mapTestLoopEntryEventVerifier) reconcile(mapName, keyPrefix string) entryEventCounts {
+
+	var result entryEventCounts
+	var snapshot map[string]entryEventCounts
+
+	v.l.Lock()
+	{
+		for key, kinds := range v.tallies {
+			if !strings.HasPrefix(key, keyPrefix) {
+				continue
+			}
+			for _, t := range kinds {
+				result.Received += uint64(t.received)
+			}
+			for _, k := range reconciledEventKinds {
+				t, ok := kinds[k]
+				if !ok {
+					continue
+				}
+				if diff := t.received - t.expected; diff < 0 {
+					result.Missed += uint64(-diff)
+				} else if diff > t.tolerated {
+					result.Unexpected += uint64(diff - t.tolerated)
+				}
+			}
+			delete(v.tallies, key)
+		}
+
+		c := v.perMap[mapName]
+		c.Received += result.Received
+		c.Missed += result.Missed
+		c.Unexpected += result.Unexpected
+		v.perMap[mapName] = c
+
+		snapshot = make(map[string]entryEventCounts, len(v.perMap))
+		for k, c := range v.perMap {
+			snapshot[k] = c
+		}
+	}
+	v.l.Unlock()
+
+	v.gatherer.Updates <- status.Update{Key: string(statusKeyEntryEventsPerMap), Value: snapshot}
+
+	return result
+
+}
+
+// forget stops tracking all keys having the given prefix without reconciling them, which is required in case no
+// listener could be registered for these keys.
+func (v *mapTestLoopEntryEventVerifier) forget(keyPrefix string) {
+
+	v.l.Lock()
+	{
+		for key := range v.tallies {
+			if strings.HasPrefix(key, keyPrefix) {
+				delete(v.tallies, key)
+			}
+		}
+	}
+	v.l.Unlock()
+
+}
+
+// withEntryEventVerification wraps the given function for running the test loop on a single map such that an entry
+// listener gets registered on the map before the test loop starts, and the events it has been notified of get
+// reconciled against the operations performed by the test loop after the test loop has finished. The listener only
+// receives events for the keys of the map goroutine in question, so other goroutines and other Hazeltest instances
+// sharing the map don't interfere.
+func withEntryEventVerification[t any](
+	tle *testLoopExecution[t],
+	lv entryEventVerifier,
+	ct counterTracker,
+	runFunc func(hazelcastwrapper.Map, string, uint16),
+) func(hazelcastwrapper.Map, string, uint16) {
+
+	if !tle.runnerConfig.entryListener.enabled {
+		return runFunc
+	}
+
+	return func(m hazelcastwrapper.Map, mapName string, mapNumber uint16) {
+
+		keyPrefix := assembleMapKey(mapName, mapNumber, "")
+
+		recordAs := func(k entryEventKind) func(event *hazelcast.EntryNotified) {
+			return func(event *hazelcast.EntryNotified) {
+				if key, ok := event.Key.(string); ok {
+					lv.record(key, k)
+				}
+			}
+		}
+		listener := hazelcast.MapListener{
+			EntryAdded:   recordAs(entryWritten),
+			EntryUpdated: recordAs(entryWritten),
+			EntryRemoved: recordAs(entryRemoved),
+			EntryExpired: recordAs(entryExpiredOrEvicted),
+			EntryEvicted: recordAs(entryExpiredOrEvicted),
+		}
+
+		// Unlike the predicate used for removing all of the goroutine's keys, this one includes the separator
+		// following the map number, so the listener of goroutine 1 doesn't receive the events of goroutine 10
+		p := predicate.SQL(fmt.Sprintf("__key like %s%%", keyPrefix))
+		subscriptionID, err := m.AddListenerWithPredicate(tle.ctx, listener, p, false)
+		if err != nil {
+			ct.increaseCounter(statusKeyNumFailedListenerRegistrations)
+			lp.LogMapRunnerEvent(fmt.Sprintf("unable to register entry listener on map '%s' in goroutine %d -- running test loop without entry event verification: %v", mapName, mapNumber, err), tle.runnerName, log.WarnLevel)
+			runFunc(m, mapName, mapNumber)
+			lv.forget(keyPrefix)
+			return
+		}
+
+		runFunc(m, mapName, mapNumber)
+
+		if gracePeriod := tle.runnerConfig.entryListener.gracePeriod; gracePeriod > 0 {
+			lp.LogMapRunnerEvent(fmt.Sprintf("waiting %s for entry events still in flight for map '%s' in goroutine %d", gracePeriod, mapName, mapNumber), tle.runnerName, log.InfoLevel)
+			timer := time.NewTimer(gracePeriod)
+			select {
+			case <-tle.ctx.Done():
+				timer.Stop()
+			case <-timer.C:
+			}
+		}
+
+		if err := m.RemoveListener(tle.ctx, subscriptionID); err != nil {
+			lp.LogMapRunnerEvent(fmt.Sprintf("unable to remove entry listener from map '%s' in goroutine %d: %v", mapName, mapNumber, err), tle.runnerName, log.WarnLevel)
+		}
+
+		counts := lv.reconcile(mapName, keyPrefix)
+		for i := uint64(0); i < counts.Missed; i++ {
+			ct.increaseCounter(statusKeyNumMissedEntryEvents)
+		}
+		for i := uint64(0); i < counts.Unexpected; i++ {
+			ct.increaseCounter(statusKeyNumUnexpectedEntryEvents)
+		}
+
+		level := log.InfoLevel
+		if counts.Missed > 0 || counts.Unexpected > 0 {
+			level = log.WarnLevel
+		}
+		lp.LogMapRunnerEvent(fmt.Sprintf("reconciled entry events for map '%s' in goroutine %d: %d received, %d missed, %d unexpected", mapName, mapNumber, counts.Received, counts.Missed, counts.Unexpected), tle.runnerName, level)
+
+	}
+
+}
@@ -0,0 +1,364 @@
+package maps
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"testing"
+)
+
+func TestMapTestLoopEntryEventVerifierReconcile(t *testing.T) {
+
+	t.Log("given a verifier keeping track of expected and received entry events")
+	{
+		keyPrefix := assembleMapKey(defaultTestMapName, defaultTestMapNumber, "")
+		key := keyPrefix + "Aragorn"
+
+		t.Log("\twhen all expected events have been received")
+		{
+			v := newMapTestLoopEntryEventVerifier(status.NewGatherer())
+			v.expect(key, entryWritten)
+			v.record(key, entryWritten)
+			v.expect(key, entryRemoved)
+			v.record(key, entryRemoved)
+
+			counts := v.reconcile(defaultTestMapName, keyPrefix)
+
+			msg := "\t\tall events must have been counted as received, and none as missed or unexpected"
+			if counts == (entryEventCounts{Received: 2}) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, counts)
+			}
+
+			msg = "\t\treconciled keys must no longer be tracked"
+			if len(v.tallies) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, v.tallies)
+			}
+
+			msg = "\t\tcounts per map must have been published to gatherer"
+			update := <-v.gatherer.Updates
+			if update.Key == string(statusKeyEntryEventsPerMap) && update.Value.(map[string]entryEventCounts)[defaultTestMapName] == counts {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, update)
+			}
+		}
+
+		t.Log("\twhen fewer events than expected have been received")
+		{
+			v := newMapTestLoopEntryEventVerifier(status.NewGatherer())
+			v.expect(key, entryWritten)
+			v.expect(key, entryWritten)
+			v.record(key, entryWritten)
+			v.expect(key, entryRemoved)
+
+			counts := v.reconcile(defaultTestMapName, keyPrefix)
+
+			msg := "\t\tdifference must have been counted as missed"
+			if counts == (entryEventCounts{Received: 1, Missed: 2}) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, counts)
+			}
+		}
+
+		t.Log("\twhen more events than expected have been received")
+		{
+			v := newMapTestLoopEntryEventVerifier(status.NewGatherer())
+			v.expect(key, entryWritten)
+			v.record(key, entryWritten)
+			v.record(key, entryWritten)
+			v.record(key, entryRemoved)
+
+			counts := v.reconcile(defaultTestMapName, keyPrefix)
+
+			msg := "\t\tdifference must have been counted as unexpected"
+			if counts == (entryEventCounts{Received: 3, Unexpected: 2}) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, counts)
+			}
+		}
+
+		t.Log("\twhen events for failed operations have been received")
+		{
+			v := newMapTestLoopEntryEventVerifier(status.NewGatherer())
+			v.tolerate(key, entryWritten)
+			v.record(key, entryWritten)
+			v.tolerate(key, entryRemoved)
+
+			counts := v.reconcile(defaultTestMapName, keyPrefix)
+
+			msg := "\t\tevents must be counted neither as missed nor as unexpected"
+			if counts == (entryEventCounts{Received: 1}) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, counts)
+			}
+		}
+
+		t.Log("\twhen expiry or eviction events have been received")
+		{
+			v := newMapTestLoopEntryEventVerifier(status.NewGatherer())
+			v.record(key, entryExpiredOrEvicted)
+
+			counts := v.reconcile(defaultTestMapName, keyPrefix)
+
+			msg := "\t\tevents must be counted as received, but not as unexpected"
+			if counts == (entryEventCounts{Received: 1}) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, counts)
+			}
+		}
+
+		t.Log("\twhen events have been received for keys of other map goroutines")
+		{
+			v := newMapTestLoopEntryEventVerifier(status.NewGatherer())
+			otherKey := assembleMapKey(defaultTestMapName, defaultTestMapNumber+10, "Aragorn")
+			v.expect(otherKey, entryWritten)
+
+			counts := v.reconcile(defaultTestMapName, keyPrefix)
+
+			msg := "\t\tkeys of other map goroutines must not be reconciled"
+			if counts == (entryEventCounts{}) && len(v.tallies) == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, counts, v.tallies)
+			}
+		}
+
+		t.Log("\twhen same map is reconciled multiple times")
+		{
+			v := newMapTestLoopEntryEventVerifier(status.NewGatherer())
+			v.expect(key, entryWritten)
+			v.reconcile(defaultTestMapName, keyPrefix)
+			<-v.gatherer.Updates
+			v.expect(key, entryWritten)
+			v.reconcile(defaultTestMapName, keyPrefix)
+
+			msg := "\t\tcounts published for map must be cumulative"
+			update := <-v.gatherer.Updates
+			if c := update.Value.(map[string]entryEventCounts)[defaultTestMapName]; c.Missed == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, c)
+			}
+		}
+	}
+
+}
+
+func TestWithEntryEventVerification(t *testing.T) {
+
+	t.Log("given a function wrapping the test loop on a single map with entry event verification")
+	{
+		keys := []string{"Aragorn", "Gandalf", "Legolas"}
+		runFuncFor := func(lv entryEventVerifier, invocations *int) func(hazelcastwrapper.Map, string, uint16) {
+			return func(m hazelcastwrapper.Map, mapName string, mapNumber uint16) {
+				*invocations++
+				for _, v := range keys {
+					key := assembleMapKey(mapName, mapNumber, v)
+					_ = m.Set(context.TODO(), key, v)
+					lv.expect(key, entryWritten)
+				}
+			}
+		}
+
+		t.Log("\twhen entry event verification has not been enabled")
+		{
+			rc := assembleBaseRunnerConfig(&runnerProperties{numMaps: 1, numRuns: 1})
+			tle := assembleTestLoopExecution(uuid.New(), testSource, theFellowship, rc, &testHzClientHandler{}, nil)
+			ms := assembleTestMapStore(&testMapStoreBehavior{})
+
+			invocations := 0
+			lv := newMapTestLoopEntryEventVerifier(status.NewGatherer())
+			withEntryEventVerification(&tle, lv, &mapTestLoopCountersTracker{}, runFuncFor(lv, &invocations))(ms.m, defaultTestMapName, defaultTestMapNumber)
+
+			msg := "\t\ttest loop must have been run"
+			if invocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, invocations)
+			}
+
+			msg = "\t\tno listener must have been registered"
+			if ms.m.addListenerInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ms.m.addListenerInvocations)
+			}
+		}
+
+		for _, tc := range []struct {
+			description        string
+			behavior           func(m *testHzMap)
+			expectedCounters   map[statusKey]uint64
+			expectedListenerOp int
+		}{
+			{
+				description:        "all events are delivered",
+				behavior:           func(_ *testHzMap) {},
+				expectedCounters:   map[statusKey]uint64{},
+				expectedListenerOp: 1,
+			},
+			{
+				description:        "events get lost",
+				behavior:           func(m *testHzMap) { m.suppressEntryEvents = true },
+				expectedCounters:   map[statusKey]uint64{statusKeyNumMissedEntryEvents: uint64(len(keys))},
+				expectedListenerOp: 1,
+			},
+			{
+				description:        "events get delivered twice",
+				behavior:           func(m *testHzMap) { m.duplicateEntryEvents = true },
+				expectedCounters:   map[statusKey]uint64{statusKeyNumUnexpectedEntryEvents: uint64(len(keys))},
+				expectedListenerOp: 1,
+			},
+			{
+				description:        "listener cannot be registered",
+				behavior:           func(m *testHzMap) { m.returnErrorUponAddListener = true },
+				expectedCounters:   map[statusKey]uint64{statusKeyNumFailedListenerRegistrations: 1},
+				expectedListenerOp: 0,
+			},
+		} {
+			t.Log("\twhen entry event verification has been enabled and " + tc.description)
+			{
+				rc := assembleBaseRunnerConfig(&runnerProperties{numMaps: 1, numRuns: 1})
+				rc.entryListener = &entryListenerConfig{enabled: true}
+				tle := assembleTestLoopExecution(uuid.New(), testSource, theFellowship, rc, &testHzClientHandler{}, nil)
+				ms := assembleTestMapStore(&testMapStoreBehavior{})
+				tc.behavior(ms.m)
+
+				gatherer := status.NewGatherer()
+				go gatherer.Listen()
+
+				ct := &mapTestLoopCountersTracker{}
+				ct.init(gatherer, entryListenerCounters...)
+				lv := newMapTestLoopEntryEventVerifier(gatherer)
+
+				invocations := 0
+				withEntryEventVerification(&tle, lv, ct, runFuncFor(lv, &invocations))(ms.m, defaultTestMapName, defaultTestMapNumber)
+
+				gatherer.StopListen()
+				waitForStatusGatheringDone(gatherer)
+
+				msg := "\t\ttest loop must have been run"
+				if invocations == 1 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, invocations)
+				}
+
+				msg = "\t\tlistener must have been registered and removed as expected"
+				if ms.m.addListenerInvocations == 1 && ms.m.removeListenerInvocations == tc.expectedListenerOp && len(ms.m.listeners) == 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, ms.m.addListenerInvocations, ms.m.removeListenerInvocations)
+				}
+
+				msg = "\t\tcounters must have expected values"
+				ok := true
+				for _, k := range entryListenerCounters {
+					if ct.counters[k] != tc.expectedCounters[k] {
+						ok = false
+					}
+				}
+				if ok {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, ct.counters)
+				}
+
+				msg = "\t\tno keys must remain tracked by verifier"
+				if len(lv.tallies) == 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, lv.tallies)
+				}
+			}
+		}
+	}
+
+}
+
+func TestRunWithEntryEventVerification(t *testing.T) {
+
+	t.Log("given the map test loops with entry event verification enabled")
+	{
+		t.Log("\twhen batch test loop runs against map delivering all events")
+		{
+			func() {
+				defer resetGetOrAssemblePayloadTestSetup()
+
+				ms := assembleTestMapStore(&testMapStoreBehavior{})
+				rc := assembleRunnerConfigForBatchTestLoop(
+					&runnerProperties{numMaps: 2, numRuns: 3, sleepBetweenRuns: sleepConfigDisabled},
+					sleepConfigDisabled,
+					sleepConfigDisabled,
+				)
+				rc.entryListener = &entryListenerConfig{enabled: true}
+				tl := assembleBatchTestLoop(uuid.New(), testSource, &testHzClientHandler{}, ms, rc)
+
+				go tl.gatherer.Listen()
+				tl.run()
+				tl.gatherer.StopListen()
+
+				waitForStatusGatheringDone(tl.gatherer)
+
+				msg := "\t\tneither missed nor unexpected events must have been reported"
+				sc := tl.gatherer.AssembleStatusCopy()
+				if sc[string(statusKeyNumMissedEntryEvents)] == uint64(0) && sc[string(statusKeyNumUnexpectedEntryEvents)] == uint64(0) {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, sc)
+				}
+
+				msg = "\t\tentry event counts must have been reported for map"
+				if perMap, ok := sc[string(statusKeyEntryEventsPerMap)].(map[string]entryEventCounts); ok && perMap["ht_test"].Received > 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, sc[string(statusKeyEntryEventsPerMap)])
+				}
+			}()
+		}
+
+		t.Log("\twhen boundary test loop runs against map losing all events")
+		{
+			func() {
+				defer resetGetOrAssemblePayloadTestSetup()
+
+				ms := assembleTestMapStore(&testMapStoreBehavior{})
+				ms.m.suppressEntryEvents = true
+				rc := assembleRunnerConfigForBoundaryTestLoop(
+					&runnerProperties{numMaps: 1, numRuns: 2, sleepBetweenRuns: sleepConfigDisabled},
+					sleepConfigDisabled,
+					sleepConfigDisabled,
+					1.0, 0.0, 1.0,
+					len(theFellowship),
+					true,
+				)
+				rc.entryListener = &entryListenerConfig{enabled: true}
+				tl := assembleBoundaryTestLoop(uuid.New(), testSource, &testHzClientHandler{}, ms, rc)
+
+				go tl.gatherer.Listen()
+				tl.run()
+				tl.gatherer.StopListen()
+
+				waitForStatusGatheringDone(tl.gatherer)
+
+				msg := "\t\tmissed events must have been reported"
+				sc := tl.gatherer.AssembleStatusCopy()
+				if n, ok := sc[string(statusKeyNumMissedEntryEvents)].(uint64); ok && n > 0 && sc[string(statusKeyNumUnexpectedEntryEvents)] == uint64(0) {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, sc)
+				}
+			}()
+		}
+	}
+
+}
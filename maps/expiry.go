@@ -0,0 +1,159 @@
+package maps
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	expiryVerifier interface {
+		confirmWrite(key string)
+		confirmRemove(key string)
+		check(key string, present bool) expiryViolation
+		expectedExpiry(key string) (time.Time, bool)
+	}
+	// mapTestLoopExpiryVerifier keeps track of when each key has last been written and last been accessed by a test
+	// loop in order to tell when Hazelcast should expire the corresponding entry given the configured TTL and max
+	// idle duration. Writing an entry resets both its TTL and its max idle countdown, while reading it or checking
+	// for its presence only resets the latter. Entries missing before their expected expiry minus the tolerance
+	// have expired prematurely, and entries still present after their expected expiry plus the tolerance have
+	// expired late.
+	mapTestLoopExpiryVerifier struct {
+		l            sync.Mutex
+		ttl          time.Duration
+		maxIdle      time.Duration
+		tolerance    time.Duration
+		lastWritten  map[string]time.Time
+		lastAccessed map[string]time.Time
+	}
+	expiryViolation string
+)
+
+const (
+	noExpiryViolation   expiryViolation = ""
+	prematureExpiration expiryViolation = "prematureExpiration"
+	lateExpiration      expiryViolation = "lateExpiration"
+)
+
+// errEntryExpired signals that a read has found an entry written with expiry to be gone.
+var errEntryExpired = errors.New("entry has expired")
+
+func newMapTestLoopExpiryVerifier(ec *expiryConfig) *mapTestLoopExpiryVerifier {
+
+	return &mapTestLoopExpiryVerifier{
+		ttl:          ec.ttl,
+		maxIdle:      ec.maxIdle,
+		tolerance:    ec.tolerance,
+		lastWritten:  make(map[string]time.Time),
+		lastAccessed: make(map[string]time.Time),
+	}
+
+}
+
+func (v *mapTestLoopExpiryVerifier) confirmWrite(key string) {
+
+	now := time.Now()
+	v.l.Lock()
+	{
+		v.lastWritten[key] = now
+		v.lastAccessed[key] = now
+	}
+	v.l.Unlock()
+
+}
+
+func (v *mapTestLoopExpiryVerifier) confirmRemove(key string) {
+
+	v.l.Lock()
+	{
+		delete(v.lastWritten, key)
+		delete(v.lastAccessed, key)
+	}
+	v.l.Unlock()
+
+}
+
+func (v *mapTestLoopExpiryVerifier) expectedExpiry(key string) (time.Time, bool) {
+
+	v.l.Lock()
+	defer v.l.Unlock()
+
+	return v.expectedExpiryUnguarded(key)
+
+}
+
+func (v *mapTestLoopExpiryVerifier) expectedExpiryUnguarded(key string) (time.Time, bool) {
+
+	lastWritten, ok := v.lastWritten[key]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	// A value of zero means the entry never expires for the respective reason, and at least one of both is
+	// guaranteed to be non-zero by the runner config
+	var expiry time.Time
+	if v.ttl > 0 {
+		expiry = lastWritten.Add(v.ttl)
+	}
+	if v.maxIdle > 0 {
+		if idleExpiry := v.lastAccessed[key].Add(v.maxIdle); expiry.IsZero() || idleExpiry.Before(expiry) {
+			expiry = idleExpiry
+		}
+	}
+
+	return expiry, true
+
+}
+
+// check evaluates whether the given key being present or absent in the target map is in line with its expected
+// expiry, and updates the information kept on the key accordingly. Keys this test loop hasn't written are ignored,
+// and keys whose entries have been found to be gone or to have expired late are no longer tracked.
+func (v *mapTestLoopExpiryVerifier) check(key string, present bool) expiryViolation {
+
+	now := time.Now()
+
+	v.l.Lock()
+	defer v.l.Unlock()
+
+	expiry, ok := v.expectedExpiryUnguarded(key)
+	if !ok {
+		return noExpiryViolation
+	}
+
+	if !present {
+		delete(v.lastWritten, key)
+		delete(v.lastAccessed, key)
+		if now.Before(expiry.Add(-v.tolerance)) {
+			return prematureExpiration
+		}
+		return noExpiryViolation
+	}
+
+	if now.After(expiry.Add(v.tolerance)) {
+		// Stop tracking key, so the same entry doesn't get reported again upon subsequent checks
+		delete(v.lastWritten, key)
+		delete(v.lastAccessed, key)
+		return lateExpiration
+	}
+	v.lastAccessed[key] = now
+
+	return noExpiryViolation
+
+}
+
+func evaluateExpiry(ev expiryVerifier, ct counterTracker, key string, present bool) error {
+
+	switch ev.check(key, present) {
+	case prematureExpiration:
+		ct.increaseCounter(statusKeyNumPrematureExpirations)
+		return fmt.Errorf("entry for key '%s' expired before its expected expiry", key)
+	case lateExpiration:
+		ct.increaseCounter(statusKeyNumLateExpirations)
+		return fmt.Errorf("entry for key '%s' still present after its expected expiry", key)
+	default:
+		return nil
+	}
+
+}
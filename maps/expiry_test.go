@@ -0,0 +1,266 @@
+package maps
+
+import (
+	"fmt"
+	"hazeltest/status"
+	"testing"
+	"time"
+)
+
+func TestMapTestLoopExpiryVerifierExpectedExpiry(t *testing.T) {
+
+	t.Log("given a key whose expected expiry is to be determined")
+	{
+		key := "awesome-key"
+
+		t.Log("\twhen key has not been written")
+		{
+			v := newMapTestLoopExpiryVerifier(&expiryConfig{ttl: time.Minute})
+
+			msg := "\t\tno expected expiry must be returned"
+			if _, ok := v.expectedExpiry(key); !ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen only ttl has been configured")
+		{
+			v := newMapTestLoopExpiryVerifier(&expiryConfig{ttl: time.Minute})
+			v.confirmWrite(key)
+			v.lastAccessed[key] = v.lastAccessed[key].Add(30 * time.Second)
+
+			msg := "\t\texpected expiry must be ttl after last write regardless of last access"
+			if expiry, ok := v.expectedExpiry(key); ok && expiry.Equal(v.lastWritten[key].Add(time.Minute)) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, expiry)
+			}
+		}
+
+		t.Log("\twhen only max idle has been configured")
+		{
+			v := newMapTestLoopExpiryVerifier(&expiryConfig{maxIdle: time.Minute})
+			v.confirmWrite(key)
+			v.lastAccessed[key] = v.lastAccessed[key].Add(30 * time.Second)
+
+			msg := "\t\texpected expiry must be max idle after last access"
+			if expiry, ok := v.expectedExpiry(key); ok && expiry.Equal(v.lastAccessed[key].Add(time.Minute)) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, expiry)
+			}
+		}
+
+		t.Log("\twhen both ttl and max idle have been configured")
+		{
+			v := newMapTestLoopExpiryVerifier(&expiryConfig{ttl: time.Minute, maxIdle: 45 * time.Second})
+			v.confirmWrite(key)
+
+			msg := "\t\texpected expiry must be determined by max idle if entry hasn't been accessed since write"
+			if expiry, ok := v.expectedExpiry(key); ok && expiry.Equal(v.lastWritten[key].Add(45*time.Second)) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, expiry)
+			}
+
+			v.lastAccessed[key] = v.lastAccessed[key].Add(30 * time.Second)
+
+			msg = "\t\texpected expiry must be determined by ttl if entry has been accessed recently enough"
+			if expiry, ok := v.expectedExpiry(key); ok && expiry.Equal(v.lastWritten[key].Add(time.Minute)) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, expiry)
+			}
+		}
+	}
+
+}
+
+func TestMapTestLoopExpiryVerifierCheck(t *testing.T) {
+
+	t.Log("given the presence or absence of a key to be checked against the key's expected expiry")
+	{
+		key := "awesome-key"
+
+		t.Log("\twhen key has not been written")
+		{
+			v := newMapTestLoopExpiryVerifier(&expiryConfig{ttl: time.Minute, tolerance: time.Second})
+
+			msg := "\t\tno violation must be reported"
+			if violation := v.check(key, false); violation == noExpiryViolation {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen key is present before its expected expiry")
+		{
+			v := newMapTestLoopExpiryVerifier(&expiryConfig{maxIdle: time.Minute, tolerance: time.Second})
+			v.confirmWrite(key)
+			lastAccessedBeforeCheck := v.lastAccessed[key]
+
+			msg := "\t\tno violation must be reported"
+			if violation := v.check(key, true); violation == noExpiryViolation {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+
+			msg = "\t\tlast access must have been updated"
+			if v.lastAccessed[key].After(lastAccessedBeforeCheck) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen key is absent before its expected expiry")
+		{
+			v := newMapTestLoopExpiryVerifier(&expiryConfig{ttl: time.Minute, tolerance: time.Second})
+			v.confirmWrite(key)
+
+			msg := "\t\tpremature expiration must be reported"
+			if violation := v.check(key, false); violation == prematureExpiration {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+
+			msg = "\t\tkey must no longer be tracked"
+			if _, ok := v.expectedExpiry(key); !ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen key is absent shortly before its expected expiry, but within tolerance")
+		{
+			v := newMapTestLoopExpiryVerifier(&expiryConfig{ttl: time.Minute, tolerance: 5 * time.Second})
+			v.confirmWrite(key)
+			v.lastWritten[key] = v.lastWritten[key].Add(-58 * time.Second)
+
+			msg := "\t\tno violation must be reported"
+			if violation := v.check(key, false); violation == noExpiryViolation {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen key is absent after its expected expiry")
+		{
+			v := newMapTestLoopExpiryVerifier(&expiryConfig{ttl: time.Minute, tolerance: time.Second})
+			v.confirmWrite(key)
+			v.lastWritten[key] = v.lastWritten[key].Add(-2 * time.Minute)
+
+			msg := "\t\tno violation must be reported"
+			if violation := v.check(key, false); violation == noExpiryViolation {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen key is still present after its expected expiry plus tolerance")
+		{
+			v := newMapTestLoopExpiryVerifier(&expiryConfig{ttl: time.Minute, tolerance: time.Second})
+			v.confirmWrite(key)
+			v.lastWritten[key] = v.lastWritten[key].Add(-2 * time.Minute)
+
+			msg := "\t\tlate expiration must be reported"
+			if violation := v.check(key, true); violation == lateExpiration {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+
+			msg = "\t\tlate expiration must be reported only once"
+			if violation := v.check(key, true); violation == noExpiryViolation {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+	}
+
+}
+
+func TestEvaluateExpiry(t *testing.T) {
+
+	t.Log("given a function to evaluate the expiry of an entry and report violations")
+	{
+		for violation, expectedCounter := range map[expiryViolation]statusKey{
+			prematureExpiration: statusKeyNumPrematureExpirations,
+			lateExpiration:      statusKeyNumLateExpirations,
+		} {
+			t.Log(fmt.Sprintf("\twhen expiry verifier reports '%s'", violation))
+			{
+				ct := &mapTestLoopCountersTracker{
+					counters: make(map[statusKey]uint64),
+					gatherer: status.NewGatherer(),
+				}
+
+				err := evaluateExpiry(&testExpiryVerifier{violation: violation}, ct, "awesome-key", true)
+
+				msg := "\t\terror must be returned"
+				if err != nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX)
+				}
+
+				msg = "\t\tcorresponding counter must have been increased"
+				if ct.counters[expectedCounter] == 1 && len(ct.gatherer.Updates) == 1 {
+					t.Log(msg, checkMark, expectedCounter)
+				} else {
+					t.Fatal(msg, ballotX, ct.counters)
+				}
+			}
+		}
+
+		t.Log("\twhen expiry verifier does not report violation")
+		{
+			ct := &mapTestLoopCountersTracker{
+				counters: make(map[statusKey]uint64),
+				gatherer: status.NewGatherer(),
+			}
+
+			err := evaluateExpiry(&testExpiryVerifier{violation: noExpiryViolation}, ct, "awesome-key", true)
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tno counter must have been increased"
+			if len(ct.gatherer.Updates) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters)
+			}
+		}
+	}
+
+}
+
+type testExpiryVerifier struct {
+	violation expiryViolation
+}
+
+func (v *testExpiryVerifier) confirmWrite(_ string) {}
+
+func (v *testExpiryVerifier) confirmRemove(_ string) {}
+
+func (v *testExpiryVerifier) check(_ string, _ bool) expiryViolation {
+	return v.violation
+}
+
+func (v *testExpiryVerifier) expectedExpiry(_ string) (time.Time, bool) {
+	return time.Time{}, false
+}
@@ -0,0 +1,169 @@
+package maps
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"github.com/hazelcast/hazelcast-go-client/serialization"
+	"hash/crc32"
+	"sync"
+)
+
+type (
+	integrityVerifier interface {
+		wrap(payload any) (verifiablePayload, error)
+		confirmWrite(key string, p verifiablePayload)
+		confirmRemove(key string)
+		check(key string, value any) integrityViolation
+	}
+	// verifiablePayload is what gets written to the target map instead of the plain payload when integrity verification
+	// has been enabled. The checksum allows for detecting corrupted values upon read, and the version -- assigned in
+	// monotonically increasing order per test loop -- allows for detecting reads returning a value older than the one
+	// most recently written for the same key.
+	verifiablePayload struct {
+		Version  uint64
+		Checksum uint32
+		Payload  any
+	}
+	// jsonVerifiablePayload is the shape of a verifiable payload written as a JSON value. The payload is kept in its
+	// raw form, so its checksum can be calculated over exactly the bytes it was calculated over upon write.
+	jsonVerifiablePayload struct {
+		Version  uint64
+		Checksum uint32
+		Payload  json.RawMessage
+	}
+	mapTestLoopIntegrityVerifier struct {
+		l              sync.Mutex
+		currentVersion uint64
+		lastWritten    map[string]uint64
+	}
+	integrityViolation string
+)
+
+const (
+	noViolation    integrityViolation = ""
+	corruptedValue integrityViolation = "corruptedValue"
+	staleValue     integrityViolation = "staleValue"
+)
+
+func init() {
+	gob.Register(verifiablePayload{})
+}
+
+func newMapTestLoopIntegrityVerifier() *mapTestLoopIntegrityVerifier {
+
+	return &mapTestLoopIntegrityVerifier{
+		lastWritten: make(map[string]uint64),
+	}
+
+}
+
+func (v *mapTestLoopIntegrityVerifier) wrap(payload any) (verifiablePayload, error) {
+
+	checksum, err := calculateChecksum(payload)
+	if err != nil {
+		return verifiablePayload{}, err
+	}
+
+	var version uint64
+	v.l.Lock()
+	{
+		v.currentVersion++
+		version = v.currentVersion
+	}
+	v.l.Unlock()
+
+	return verifiablePayload{
+		Version:  version,
+		Checksum: checksum,
+		Payload:  payload,
+	}, nil
+
+}
+
+func (v *mapTestLoopIntegrityVerifier) confirmWrite(key string, p verifiablePayload) {
+
+	v.l.Lock()
+	{
+		if p.Version > v.lastWritten[key] {
+			v.lastWritten[key] = p.Version
+		}
+	}
+	v.l.Unlock()
+
+}
+
+func (v *mapTestLoopIntegrityVerifier) confirmRemove(key string) {
+
+	v.l.Lock()
+	{
+		delete(v.lastWritten, key)
+	}
+	v.l.Unlock()
+
+}
+
+func (v *mapTestLoopIntegrityVerifier) check(key string, value any) integrityViolation {
+
+	var version uint64
+	switch p := value.(type) {
+	case verifiablePayload:
+		if checksum, err := calculateChecksum(p.Payload); err != nil || checksum != p.Checksum {
+			return corruptedValue
+		}
+		version = p.Version
+	case serialization.JSON:
+		var jp jsonVerifiablePayload
+		if err := json.Unmarshal(p, &jp); err != nil || crc32.ChecksumIEEE(jp.Payload) != jp.Checksum {
+			return corruptedValue
+		}
+		version = jp.Version
+	default:
+		// Value either wasn't written by a test loop having integrity verification enabled, or it got mangled so
+		// badly it can no longer be deserialized into the expected type -- both cases amount to the value not
+		// being what this test loop wrote
+		return corruptedValue
+	}
+
+	var lastWrittenVersion uint64
+	v.l.Lock()
+	{
+		lastWrittenVersion = v.lastWritten[key]
+	}
+	v.l.Unlock()
+
+	// Keys this test loop hasn't written yet (e.g. because they were written by a previous incarnation of this
+	// Hazeltest instance and not cleaned) have a last written version of zero, so only their checksum is verified
+	if version < lastWrittenVersion {
+		return staleValue
+	}
+
+	return noViolation
+
+}
+
+func calculateChecksum(payload any) (uint32, error) {
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("unable to calculate checksum for payload: %w", err)
+	}
+
+	return crc32.ChecksumIEEE(b), nil
+
+}
+
+func evaluateReadIntegrity(iv integrityVerifier, ct counterTracker, key string, value any) error {
+
+	switch iv.check(key, value) {
+	case corruptedValue:
+		ct.increaseCounter(statusKeyNumCorruptedReads)
+		return fmt.Errorf("value read for key '%s' failed checksum verification", key)
+	case staleValue:
+		ct.increaseCounter(statusKeyNumStaleReads)
+		return fmt.Errorf("value read for key '%s' is older than most recently written value", key)
+	default:
+		return nil
+	}
+
+}
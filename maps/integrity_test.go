@@ -0,0 +1,286 @@
+package maps
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hazelcast/hazelcast-go-client/serialization"
+	"hazeltest/status"
+	"sync"
+	"testing"
+)
+
+func TestMapTestLoopIntegrityVerifierWrap(t *testing.T) {
+
+	t.Log("given a payload to be wrapped for integrity verification")
+	{
+		t.Log("\twhen payloads are wrapped concurrently")
+		{
+			v := newMapTestLoopIntegrityVerifier()
+
+			numPayloads := 100
+			versions := make(chan uint64, numPayloads)
+
+			var wg sync.WaitGroup
+			for i := 0; i < numPayloads; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					p, _ := v.wrap("frodo")
+					versions <- p.Version
+				}()
+			}
+			wg.Wait()
+			close(versions)
+
+			msg := "\t\teach payload must have been assigned a unique version"
+			seen := make(map[uint64]struct{})
+			for version := range versions {
+				seen[version] = struct{}{}
+			}
+			if len(seen) == numPayloads {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, fmt.Sprintf("expected %d unique versions, got %d", numPayloads, len(seen)))
+			}
+		}
+
+		t.Log("\twhen payload is pokemon")
+		{
+			v := newMapTestLoopIntegrityVerifier()
+			pokemon := pokemon{ID: 25, Name: "Pikachu", ElementType: []string{"Electric"}, Multipliers: []float32{2.34}}
+
+			p, err := v.wrap(pokemon)
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tchecksum must be identical for identical payloads"
+			expected, _ := calculateChecksum(pokemon)
+			if p.Checksum == expected {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, fmt.Sprintf("expected %d, got %d", expected, p.Checksum))
+			}
+		}
+
+		t.Log("\twhen payload cannot be serialized for checksum calculation")
+		{
+			v := newMapTestLoopIntegrityVerifier()
+
+			_, err := v.wrap(func() {})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestMapTestLoopIntegrityVerifierCheck(t *testing.T) {
+
+	t.Log("given a value read from a map to be checked for integrity violations")
+	{
+		key := "awesome-key"
+
+		t.Log("\twhen value is most recently written payload")
+		{
+			v := newMapTestLoopIntegrityVerifier()
+			p, _ := v.wrap("frodo")
+			v.confirmWrite(key, p)
+
+			msg := "\t\tno violation must be reported"
+			if violation := v.check(key, p); violation == noViolation {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen value is not a verifiable payload")
+		{
+			v := newMapTestLoopIntegrityVerifier()
+
+			msg := "\t\tcorrupted value must be reported"
+			if violation := v.check(key, "frodo"); violation == corruptedValue {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen payload does not match checksum")
+		{
+			v := newMapTestLoopIntegrityVerifier()
+			p, _ := v.wrap("frodo")
+			v.confirmWrite(key, p)
+			p.Payload = "sauron"
+
+			msg := "\t\tcorrupted value must be reported"
+			if violation := v.check(key, p); violation == corruptedValue {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen value is older than most recently written value")
+		{
+			v := newMapTestLoopIntegrityVerifier()
+			older, _ := v.wrap("frodo")
+			v.confirmWrite(key, older)
+			newer, _ := v.wrap("frodo")
+			v.confirmWrite(key, newer)
+
+			msg := "\t\tstale value must be reported"
+			if violation := v.check(key, older); violation == staleValue {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen value is most recently written payload read back as json value")
+		{
+			v := newMapTestLoopIntegrityVerifier()
+			p, _ := v.wrap(pokemon{ID: 25, Name: "Pikachu", ElementType: []string{"Electric"}})
+			v.confirmWrite(key, p)
+			b, _ := json.Marshal(p)
+
+			msg := "\t\tno violation must be reported"
+			if violation := v.check(key, serialization.JSON(b)); violation == noViolation {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen payload of json value does not match checksum")
+		{
+			v := newMapTestLoopIntegrityVerifier()
+			p, _ := v.wrap("frodo")
+			v.confirmWrite(key, p)
+			p.Payload = "sauron"
+			b, _ := json.Marshal(p)
+
+			msg := "\t\tcorrupted value must be reported"
+			if violation := v.check(key, serialization.JSON(b)); violation == corruptedValue {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen json value cannot be decoded")
+		{
+			v := newMapTestLoopIntegrityVerifier()
+
+			msg := "\t\tcorrupted value must be reported"
+			if violation := v.check(key, serialization.JSON("{\"Version\": 1, ")); violation == corruptedValue {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen key was removed after older value had been written")
+		{
+			v := newMapTestLoopIntegrityVerifier()
+			older, _ := v.wrap("frodo")
+			newer, _ := v.wrap("frodo")
+			v.confirmWrite(key, newer)
+			v.confirmRemove(key)
+
+			msg := "\t\tno violation must be reported"
+			if violation := v.check(key, older); violation == noViolation {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+	}
+
+}
+
+func TestEvaluateReadIntegrity(t *testing.T) {
+
+	t.Log("given a function to evaluate the integrity of a value read and report violations")
+	{
+		for violation, expectedCounter := range map[integrityViolation]statusKey{
+			corruptedValue: statusKeyNumCorruptedReads,
+			staleValue:     statusKeyNumStaleReads,
+		} {
+			t.Log(fmt.Sprintf("\twhen integrity verifier reports '%s'", violation))
+			{
+				ct := &mapTestLoopCountersTracker{
+					counters: make(map[statusKey]uint64),
+					gatherer: status.NewGatherer(),
+				}
+
+				err := evaluateReadIntegrity(&testIntegrityVerifier{violation: violation}, ct, "awesome-key", "frodo")
+
+				msg := "\t\terror must be returned"
+				if err != nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX)
+				}
+
+				msg = "\t\tcorresponding counter must have been increased"
+				if ct.counters[expectedCounter] == 1 && len(ct.gatherer.Updates) == 1 {
+					t.Log(msg, checkMark, expectedCounter)
+				} else {
+					t.Fatal(msg, ballotX, ct.counters)
+				}
+			}
+		}
+
+		t.Log("\twhen integrity verifier does not report violation")
+		{
+			ct := &mapTestLoopCountersTracker{
+				counters: make(map[statusKey]uint64),
+				gatherer: status.NewGatherer(),
+			}
+
+			err := evaluateReadIntegrity(&testIntegrityVerifier{violation: noViolation}, ct, "awesome-key", "frodo")
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tno counter must have been increased"
+			if len(ct.gatherer.Updates) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters)
+			}
+		}
+	}
+
+}
+
+type testIntegrityVerifier struct {
+	violation integrityViolation
+}
+
+func (v *testIntegrityVerifier) wrap(payload any) (verifiablePayload, error) {
+	return verifiablePayload{Payload: payload}, nil
+}
+
+func (v *testIntegrityVerifier) confirmWrite(_ string, _ verifiablePayload) {}
+
+func (v *testIntegrityVerifier) confirmRemove(_ string) {}
+
+func (v *testIntegrityVerifier) check(_ string, _ any) integrityViolation {
+	return v.violation
+}
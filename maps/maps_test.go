@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/hazelcast/hazelcast-go-client"
 	"github.com/hazelcast/hazelcast-go-client/predicate"
+	"github.com/hazelcast/hazelcast-go-client/types"
 	"hazeltest/hazelcastwrapper"
 	"hazeltest/status"
 	"strings"
@@ -48,7 +49,14 @@ type (
 		sizeInvocations                           int
 		removeAllInvocations                      int
 		evictAllInvocations                       int
+		setWithTTLAndMaxIdleInvocations           int
+		lastTTL                                   time.Duration
+		lastMaxIdle                               time.Duration
 		lastPredicateFilterForRemoveAllInvocation string
+		getEntrySetWithPredicateInvocations       int
+		getKeySetWithPredicateInvocations         int
+		getValuesWithPredicateInvocations         int
+		lastQueryPredicate                        string
 		// TODO Use regular map rather than sync.Map because access to testHzMap properties has to ge guarded by lock anyway
 		data                       *sync.Map
 		returnErrorUponGet         bool
@@ -57,7 +65,19 @@ type (
 		returnErrorUponRemove      bool
 		returnErrorUponRemoveAll   bool
 		returnErrorUponEvictAll    bool
+		returnErrorUponQuery       bool
 		bm                         *boundaryMonitoring
+		// Entry listeners only get notified of events for keys matching the prefix given in the listener's predicate
+		listeners                  map[types.UUID]testEntryListener
+		addListenerInvocations     int
+		removeListenerInvocations  int
+		returnErrorUponAddListener bool
+		suppressEntryEvents        bool
+		duplicateEntryEvents       bool
+	}
+	testEntryListener struct {
+		keyPrefix string
+		listener  hazelcast.MapListener
 	}
 )
 
@@ -83,8 +103,18 @@ func (d *testHzClientHandler) Shutdown(_ context.Context) error {
 	return nil
 }
 
-func (m *testHzMap) SetWithTTLAndMaxIdle(_ context.Context, _, _ any, _ time.Duration, _ time.Duration) error {
-	return nil
+func (m *testHzMap) SetWithTTLAndMaxIdle(ctx context.Context, key, value any, ttl time.Duration, maxIdle time.Duration) error {
+
+	testMapOperationLock.Lock()
+	{
+		m.setWithTTLAndMaxIdleInvocations++
+		m.lastTTL, m.lastMaxIdle = ttl, maxIdle
+	}
+	testMapOperationLock.Unlock()
+
+	// Test map doesn't expire entries by itself -- tests simulate expiry by removing entries from the map's data
+	return m.Set(ctx, key, value)
+
 }
 
 func (m *testHzMap) TryLock(_ context.Context, _ any) (bool, error) {
@@ -95,6 +125,66 @@ func (m *testHzMap) Unlock(_ context.Context, _ any) error {
 	return nil
 }
 
+func (m *testHzMap) AddListenerWithPredicate(_ context.Context, listener hazelcast.MapListener, p predicate.Predicate, _ bool) (types.UUID, error) {
+
+	testMapOperationLock.Lock()
+	defer testMapOperationLock.Unlock()
+
+	m.addListenerInvocations++
+
+	if m.returnErrorUponAddListener {
+		return types.UUID{}, errors.New("one does not simply register a listener")
+	}
+
+	if m.listeners == nil {
+		m.listeners = make(map[types.UUID]testEntryListener)
+	}
+
+	id := types.NewUUID()
+	m.listeners[id] = testEntryListener{keyPrefix: extractFilterFromPredicate(p), listener: listener}
+
+	return id, nil
+
+}
+
+func (m *testHzMap) RemoveListener(_ context.Context, subscriptionID types.UUID) error {
+
+	testMapOperationLock.Lock()
+	defer testMapOperationLock.Unlock()
+
+	m.removeListenerInvocations++
+	delete(m.listeners, subscriptionID)
+
+	return nil
+
+}
+
+// notifyListenersUnguarded invokes the handler chosen by the given function on all listeners registered for the given
+// key -- callers must hold the test map operation lock.
+func (m *testHzMap) notifyListenersUnguarded(key string, chooseHandler func(l hazelcast.MapListener) func(*hazelcast.EntryNotified)) {
+
+	if m.suppressEntryEvents {
+		return
+	}
+
+	numNotifications := 1
+	if m.duplicateEntryEvents {
+		numNotifications = 2
+	}
+
+	for _, l := range m.listeners {
+		if !strings.HasPrefix(key, l.keyPrefix) {
+			continue
+		}
+		if handler := chooseHandler(l.listener); handler != nil {
+			for i := 0; i < numNotifications; i++ {
+				handler(&hazelcast.EntryNotified{Key: key})
+			}
+		}
+	}
+
+}
+
 const (
 	checkMark            = "\u2713"
 	ballotX              = "\u2717"
@@ -186,8 +276,20 @@ func (m *testHzMap) Set(_ context.Context, key any, value any) error {
 		return fmt.Errorf("unable to parse given key into string for querying test data source: %v", key)
 	}
 
+	_, present := m.data.Load(keyString)
 	m.data.Store(keyString, value)
 
+	testMapOperationLock.Lock()
+	{
+		m.notifyListenersUnguarded(keyString, func(l hazelcast.MapListener) func(*hazelcast.EntryNotified) {
+			if present {
+				return l.EntryUpdated
+			}
+			return l.EntryAdded
+		})
+	}
+	testMapOperationLock.Unlock()
+
 	if m.bm != nil && !m.bm.upperBoundaryThresholdViolated && !m.bm.lowerBoundaryThresholdViolated {
 		currentMapSize := 0
 		m.data.Range(func(_, _ any) bool {
@@ -263,7 +365,14 @@ func (m *testHzMap) Remove(_ context.Context, key any) (any, error) {
 	}
 
 	if value, ok := m.data.Load(keyString); ok {
-		defer m.data.Delete(keyString)
+		m.data.Delete(keyString)
+		testMapOperationLock.Lock()
+		{
+			m.notifyListenersUnguarded(keyString, func(l hazelcast.MapListener) func(*hazelcast.EntryNotified) {
+				return l.EntryRemoved
+			})
+		}
+		testMapOperationLock.Unlock()
 		return value, nil
 	}
 
@@ -325,6 +434,9 @@ func (m *testHzMap) RemoveAll(_ context.Context, predicate predicate.Predicate)
 	applyFunctionToTestMapContents(m, func(key, value any) bool {
 		if strings.HasPrefix(key.(string), predicateFilter) {
 			m.data.Delete(key)
+			m.notifyListenersUnguarded(key.(string), func(l hazelcast.MapListener) func(*hazelcast.EntryNotified) {
+				return l.EntryRemoved
+			})
 		}
 		return true
 	})
@@ -333,6 +445,73 @@ func (m *testHzMap) RemoveAll(_ context.Context, predicate predicate.Predicate)
 
 }
 
+// The test map doesn't evaluate predicates -- queries always yield the map's entire contents
+func (m *testHzMap) GetEntrySetWithPredicate(_ context.Context, p predicate.Predicate) ([]types.Entry, error) {
+
+	testMapOperationLock.Lock()
+	defer testMapOperationLock.Unlock()
+
+	m.getEntrySetWithPredicateInvocations++
+	m.lastQueryPredicate = p.String()
+
+	if m.returnErrorUponQuery {
+		return nil, errors.New("the query has been lost in the mines of moria")
+	}
+
+	var result []types.Entry
+	applyFunctionToTestMapContents(m, func(key, value any) bool {
+		result = append(result, types.Entry{Key: key, Value: value})
+		return true
+	})
+
+	return result, nil
+
+}
+
+func (m *testHzMap) GetKeySetWithPredicate(_ context.Context, p predicate.Predicate) ([]any, error) {
+
+	testMapOperationLock.Lock()
+	defer testMapOperationLock.Unlock()
+
+	m.getKeySetWithPredicateInvocations++
+	m.lastQueryPredicate = p.String()
+
+	if m.returnErrorUponQuery {
+		return nil, errors.New("the query has been lost in the mines of moria")
+	}
+
+	var result []any
+	applyFunctionToTestMapContents(m, func(key, _ any) bool {
+		result = append(result, key)
+		return true
+	})
+
+	return result, nil
+
+}
+
+func (m *testHzMap) GetValuesWithPredicate(_ context.Context, p predicate.Predicate) ([]any, error) {
+
+	testMapOperationLock.Lock()
+	defer testMapOperationLock.Unlock()
+
+	m.getValuesWithPredicateInvocations++
+	m.lastQueryPredicate = p.String()
+
+	if m.returnErrorUponQuery {
+		return nil, errors.New("the query has been lost in the mines of moria")
+	}
+
+	var result []any
+	applyFunctionToTestMapContents(m, func(_, value any) bool {
+		result = append(result, value)
+		return true
+	})
+
+	return result, nil
+
+}
+
 func (m *testHzMap) EvictAll(_ context.Context) error {
 
 	testMapOperationLock.Lock()
@@ -0,0 +1,722 @@
+package maps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hazelcast/hazelcast-go-client/predicate"
+	"github.com/hazelcast/hazelcast-go-client/sql"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	queryRunner struct {
+		assigner        client.ConfigPropertyAssigner
+		stateList       []runnerState
+		name            string
+		source          string
+		hzClientHandler hazelcastwrapper.HzClientHandler
+		hzMapStore      hazelcastwrapper.MapStore
+		hzSqlStore      hazelcastwrapper.SqlStore
+		gatherer        *status.Gatherer
+		providerFuncs   struct {
+			mapStore newMapStoreFunc
+			sqlStore newSqlStoreFunc
+		}
+	}
+	newSqlStoreFunc func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.SqlStore
+	// queryTestLoop runs the configured queries against each map of the target runner. In contrast to the other
+	// map runners' test loops, it doesn't write to the maps it works on, so it relies on the target runner to
+	// populate them.
+	queryTestLoop struct {
+		runnerName string
+		cfg        *queryRunnerConfig
+		mapNames   []string
+		hzMapStore hazelcastwrapper.MapStore
+		hzSqlStore hazelcastwrapper.SqlStore
+		ctx        context.Context
+		gatherer   *status.Gatherer
+		ct         counterTracker
+		lt         latencyTracker
+		s          sleeper
+	}
+	queryRunnerConfig struct {
+		enabled          bool
+		numRuns          uint32
+		targetRunner     string
+		sleepBetweenRuns *sleepConfig
+		sqlMapping       *sqlMappingConfig
+		queries          []*queryDefinition
+	}
+	sqlMappingConfig struct {
+		enabled     bool
+		keyFormat   string
+		valueFormat string
+	}
+	queryDefinition struct {
+		name      string
+		queryType queryType
+		operation predicateQueryOperation
+		template  string
+		params    []any
+		// A maximum result size of less than zero means the result size is not bounded
+		minResultSize int
+		maxResultSize int
+	}
+	queryType               string
+	predicateQueryOperation string
+)
+
+const (
+	mapQueryRunnerKeyPath = "mapTests.query"
+	mapQueryRunnerName    = "mapsQueryRunner"
+)
+
+const (
+	predicateQuery queryType = "predicate"
+	sqlQuery       queryType = "sql"
+)
+
+const (
+	entrySet predicateQueryOperation = "entrySet"
+	keySet   predicateQueryOperation = "keySet"
+	values   predicateQueryOperation = "values"
+)
+
+const (
+	templateParamPlaceholder = "?"
+	mapNamePlaceholder       = "{mapName}"
+	clientIdPlaceholder      = "{clientId}"
+)
+
+const (
+	statusKeyNumFailedQueries         statusKey = "numFailedQueries"
+	statusKeyNumUnexpectedResultSizes statusKey = "numUnexpectedResultSizes"
+)
+
+var (
+	queryCounters = []statusKey{statusKeyNumFailedQueries, statusKeyNumUnexpectedResultSizes}
+	// Key paths and map base names of the runners whose maps the query runner can target
+	queryTargetRunners = map[string]struct {
+		keyPath     string
+		mapBaseName string
+	}{
+		"pokedex": {"mapTests.pokedex", "pokedex"},
+		"load":    {mapLoadRunnerKeyPath, mapLoadRunnerMapBaseName},
+		"dataset": {mapDatasetRunnerKeyPath, mapDatasetRunnerMapBaseName},
+	}
+	newDefaultSqlStore newSqlStoreFunc = func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.SqlStore {
+		return &hazelcastwrapper.DefaultSqlStore{Client: ch.GetClient()}
+	}
+)
+
+func init() {
+	register(&queryRunner{
+		assigner:        &client.DefaultConfigPropertyAssigner{},
+		stateList:       []runnerState{},
+		name:            mapQueryRunnerName,
+		source:          "queryRunner",
+		hzClientHandler: &hazelcastwrapper.DefaultHzClientHandler{},
+		providerFuncs: struct {
+			mapStore newMapStoreFunc
+			sqlStore newSqlStoreFunc
+		}{mapStore: newDefaultMapStore, sqlStore: newDefaultSqlStore},
+	})
+}
+
+func (r *queryRunner) getSourceName() string {
+	return "queryRunner"
+}
+
+func (r *queryRunner) runMapTests(ctx context.Context, hzCluster string, hzMembers []string, gatherer *status.Gatherer) {
+
+	r.gatherer = gatherer
+	r.appendState(start)
+
+	config, err := populateQueryConfig(r.assigner)
+	if err != nil {
+		lp.LogMapRunnerEvent(fmt.Sprintf("aborting launch of map query runner: unable to populate config: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.appendState(populateConfigComplete)
+
+	if !config.enabled {
+		lp.LogMapRunnerEvent("query runner not enabled -- won't run", r.name, log.InfoLevel)
+		return
+	}
+	r.appendState(checkEnabledComplete)
+
+	api.RaiseNotReady()
+
+	mapNames, err := assembleQueryTargetMapNames(r.assigner, config.targetRunner)
+	if err != nil {
+		lp.LogMapRunnerEvent(fmt.Sprintf("aborting launch of map query runner: unable to determine maps of target runner '%s': %s", config.targetRunner, err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.appendState(assignTestLoopComplete)
+
+	r.hzClientHandler.InitHazelcastClient(ctx, r.name, hzCluster, hzMembers)
+	defer func() {
+		_ = r.hzClientHandler.Shutdown(ctx)
+	}()
+	r.hzMapStore = r.providerFuncs.mapStore(r.hzClientHandler)
+	r.hzSqlStore = r.providerFuncs.sqlStore(r.hzClientHandler)
+
+	api.RaiseReady()
+	r.appendState(raiseReadyComplete)
+
+	lp.LogMapRunnerEvent("initialized hazelcast client", r.name, log.InfoLevel)
+	lp.LogMapRunnerEvent(fmt.Sprintf("starting query test loop on %d map/-s of target runner '%s'", len(mapNames), config.targetRunner), r.name, log.InfoLevel)
+
+	l := &queryTestLoop{
+		runnerName: r.name,
+		cfg:        config,
+		mapNames:   mapNames,
+		hzMapStore: r.hzMapStore,
+		hzSqlStore: r.hzSqlStore,
+		ctx:        ctx,
+	}
+	l.init(&defaultSleeper{}, r.gatherer)
+
+	r.appendState(testLoopStart)
+	l.run()
+	r.appendState(testLoopComplete)
+
+	lp.LogMapRunnerEvent("finished query maps loop", r.name, log.InfoLevel)
+
+}
+
+func (r *queryRunner) appendState(s runnerState) {
+
+	r.stateList = append(r.stateList, s)
+	r.gatherer.Updates <- status.Update{Key: string(statusKeyCurrentState), Value: string(s)}
+
+}
+
+// assembleQueryTargetMapNames determines the names of the maps the given target runner works on by populating the
+// target runner's config, so the query runner follows along with whatever map name settings have been configured
+// for the target runner.
+func assembleQueryTargetMapNames(a client.ConfigPropertyAssigner, targetRunner string) ([]string, error) {
+
+	target, ok := queryTargetRunners[targetRunner]
+	if !ok {
+		return nil, fmt.Errorf("no such target runner: %s", targetRunner)
+	}
+
+	rc, err := runnerConfigBuilder{
+		assigner:      a,
+		runnerKeyPath: target.keyPath,
+		mapBaseName:   target.mapBaseName,
+	}.populateConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	seen := make(map[string]struct{})
+	for i := uint16(0); i < rc.numMaps; i++ {
+		// All goroutines of the target runner share the same map if map index is not appended to map name
+		mapName := assembleMapName(rc, i)
+		if _, ok := seen[mapName]; ok {
+			continue
+		}
+		seen[mapName] = struct{}{}
+		result = append(result, mapName)
+	}
+
+	return result, nil
+
+}
+
+func (l *queryTestLoop) init(s sleeper, gatherer *status.Gatherer) {
+	l.s = s
+	l.gatherer = gatherer
+
+	ct := &mapTestLoopCountersTracker{baseCounters: queryCounters}
+	ct.init(gatherer)
+
+	l.ct = ct
+
+	trackedOperations := make([]mapOperation, len(l.cfg.queries))
+	for i, q := range l.cfg.queries {
+		trackedOperations[i] = mapOperation(q.name)
+	}
+	lt := &mapTestLoopLatencyTracker{trackedOperations: trackedOperations}
+	lt.init(gatherer)
+
+	l.lt = lt
+}
+
+func (l *queryTestLoop) run() {
+
+	insertInitialTestLoopStatus(l.gatherer.Updates, uint16(len(l.mapNames)), l.cfg.numRuns)
+
+	var wg sync.WaitGroup
+	for i, mapName := range l.mapNames {
+		wg.Add(1)
+		go func(i int, mapName string) {
+			defer wg.Done()
+			m, err := l.hzMapStore.GetMap(l.ctx, mapName)
+			if err != nil {
+				lp.LogHzEvent(fmt.Sprintf("unable to retrieve map '%s' from hazelcast: %s", mapName, err), log.ErrorLevel)
+				return
+			}
+			l.runForMap(m, mapName, i)
+		}(i, mapName)
+	}
+	wg.Wait()
+
+	l.lt.publish()
+
+}
+
+func (l *queryTestLoop) runForMap(m hazelcastwrapper.Map, mapName string, mapNumber int) {
+
+	if l.cfg.sqlMapping.enabled && l.containsSqlQueries() {
+		if err := l.createSqlMapping(mapName); err != nil {
+			lp.LogHzEvent(fmt.Sprintf("unable to create sql mapping for map '%s' -- sql queries on this map will likely fail: %s", mapName, err), log.WarnLevel)
+		}
+	}
+
+	for i := uint32(0); i < l.cfg.numRuns; i++ {
+		l.s.sleep(l.ctx, l.cfg.sleepBetweenRuns, sleepTimeFunc, l.runnerName)
+		if i > 0 && i%updateStep == 0 {
+			lp.LogMapRunnerEvent(fmt.Sprintf("finished %d of %d query runs for map %s in map goroutine %d", i, l.cfg.numRuns, mapName, mapNumber), l.runnerName, log.InfoLevel)
+		}
+		for _, q := range l.cfg.queries {
+			resultSize, err := l.executeQuery(m, mapName, q)
+			if err != nil {
+				l.ct.increaseCounter(statusKeyNumFailedQueries)
+				lp.LogHzEvent(fmt.Sprintf("failed to execute query '%s' on map '%s' in run %d: %s", q.name, mapName, i, err), log.WarnLevel)
+				continue
+			}
+			if !q.resultSizeAsExpected(resultSize) {
+				l.ct.increaseCounter(statusKeyNumUnexpectedResultSizes)
+				lp.LogMapRunnerEvent(fmt.Sprintf("query '%s' on map '%s' returned %d result/-s in run %d, which is outside expected range %s", q.name, mapName, resultSize, i, q.describeExpectedResultSize()), l.runnerName, log.WarnLevel)
+			}
+		}
+	}
+
+	lp.LogMapRunnerEvent(fmt.Sprintf("query test loop done on map '%s' in map goroutine %d", mapName, mapNumber), l.runnerName, log.InfoLevel)
+
+}
+
+func (l *queryTestLoop) containsSqlQueries() bool {
+
+	for _, q := range l.cfg.queries {
+		if q.queryType == sqlQuery {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+func (l *queryTestLoop) createSqlMapping(mapName string) error {
+
+	statement := fmt.Sprintf("CREATE MAPPING IF NOT EXISTS \"%s\" TYPE IMap OPTIONS ('keyFormat' = '%s', 'valueFormat' = '%s')", mapName, l.cfg.sqlMapping.keyFormat, l.cfg.sqlMapping.valueFormat)
+
+	result, err := l.hzSqlStore.Execute(l.ctx, statement)
+	if err != nil {
+		return err
+	}
+
+	return result.Close()
+
+}
+
+func (l *queryTestLoop) executeQuery(m hazelcastwrapper.Map, mapName string, q *queryDefinition) (int, error) {
+
+	params := make([]any, len(q.params))
+	for i, p := range q.params {
+		params[i] = expandQueryPlaceholders(p, mapName)
+	}
+
+	start := time.Now()
+	defer func() {
+		l.lt.recordLatency(mapOperation(q.name), time.Since(start))
+	}()
+
+	if q.queryType == sqlQuery {
+		return l.executeSqlQuery(expandQueryPlaceholders(q.template, mapName).(string), params)
+	}
+
+	return executePredicateQuery(l.ctx, m, q.operation, predicate.SQL(assemblePredicateExpression(q.template, params)))
+
+}
+
+func executePredicateQuery(ctx context.Context, m hazelcastwrapper.Map, operation predicateQueryOperation, p predicate.Predicate) (int, error) {
+
+	var size int
+	var err error
+	switch operation {
+	case entrySet:
+		entries, e := m.GetEntrySetWithPredicate(ctx, p)
+		size, err = len(entries), e
+	case keySet:
+		keys, e := m.GetKeySetWithPredicate(ctx, p)
+		size, err = len(keys), e
+	case values:
+		vals, e := m.GetValuesWithPredicate(ctx, p)
+		size, err = len(vals), e
+	default:
+		return 0, fmt.Errorf("no such predicate query operation: %s", operation)
+	}
+
+	return size, err
+
+}
+
+func (l *queryTestLoop) executeSqlQuery(statement string, params []any) (int, error) {
+
+	result, err := l.hzSqlStore.Execute(l.ctx, statement, params...)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = result.Close()
+	}()
+
+	if !result.IsRowSet() {
+		return int(result.UpdateCount()), nil
+	}
+
+	return countRows(result)
+
+}
+
+func countRows(result sql.Result) (int, error) {
+
+	it, err := result.Iterator()
+	if err != nil {
+		return 0, err
+	}
+
+	numRows := 0
+	for it.HasNext() {
+		if _, err := it.Next(); err != nil {
+			return numRows, err
+		}
+		numRows++
+	}
+
+	return numRows, nil
+
+}
+
+// expandQueryPlaceholders replaces the map name and client ID placeholders in the given template or parameter
+// value, so queries can refer to the map they run on and to the keys this Hazeltest instance has written.
+// Values other than strings are returned as-is.
+func expandQueryPlaceholders(a any, mapName string) any {
+
+	s, ok := a.(string)
+	if !ok {
+		return a
+	}
+
+	return strings.NewReplacer(mapNamePlaceholder, mapName, clientIdPlaceholder, client.ID().String()).Replace(s)
+
+}
+
+// findPlaceholders returns the positions of the parameter placeholders in the given template. Question marks within
+// string literals or quoted identifiers are part of those and therefore not placeholders.
+func findPlaceholders(template string) []int {
+
+	var positions []int
+	var quote rune
+	for i, c := range template {
+		switch {
+		case quote != 0:
+			// Escaped quotes inside literals (such as '') simply close and re-open the literal
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case strings.HasPrefix(template[i:], templateParamPlaceholder):
+			positions = append(positions, i)
+		}
+	}
+
+	return positions
+
+}
+
+// assemblePredicateExpression fills the parameter placeholders in the given predicate template with the given
+// parameters in order. Unlike Hazelcast SQL, predicates don't support parameters natively, so string parameters
+// are quoted here.
+func assemblePredicateExpression(template string, params []any) string {
+
+	var b strings.Builder
+	last := 0
+	for i, pos := range findPlaceholders(template) {
+		if i >= len(params) {
+			break
+		}
+		b.WriteString(template[last:pos])
+		if s, ok := params[i].(string); ok {
+			b.WriteString("'" + strings.ReplaceAll(s, "'", "''") + "'")
+		} else {
+			b.WriteString(fmt.Sprintf("%v", params[i]))
+		}
+		last = pos + len(templateParamPlaceholder)
+	}
+	b.WriteString(template[last:])
+
+	return b.String()
+
+}
+
+func (q *queryDefinition) resultSizeAsExpected(size int) bool {
+
+	return size >= q.minResultSize && (q.maxResultSize < 0 || size <= q.maxResultSize)
+
+}
+
+func (q *queryDefinition) describeExpectedResultSize() string {
+
+	if q.maxResultSize < 0 {
+		return fmt.Sprintf("[%d, unbounded)", q.minResultSize)
+	}
+
+	return fmt.Sprintf("[%d, %d]", q.minResultSize, q.maxResultSize)
+
+}
+
+func validateQueryTargetRunner(keyPath string, a any) error {
+
+	if err := client.ValidateString(keyPath, a); err != nil {
+		return err
+	}
+
+	if _, ok := queryTargetRunners[a.(string)]; !ok {
+		return fmt.Errorf("target runner expected to be one of 'pokedex', 'load', or 'dataset', got %v", a)
+	}
+
+	return nil
+
+}
+
+func validateQueryDefinitions(keyPath string, a any) error {
+
+	_, err := parseQueryDefinitions(keyPath, a)
+	return err
+
+}
+
+// parseQueryDefinitions turns the list of query definitions in the config into query definitions the query test
+// loop can run. Since the config property assigner only knows how to retrieve single values, each definition's
+// properties are checked here rather than by means of individual assignments.
+func parseQueryDefinitions(keyPath string, a any) ([]*queryDefinition, error) {
+
+	rawDefinitions, ok := a.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: unable to parse value into list of query definitions", keyPath)
+	}
+
+	if len(rawDefinitions) == 0 {
+		return nil, fmt.Errorf("%s: expected at least one query definition", keyPath)
+	}
+
+	var result []*queryDefinition
+	names := make(map[string]struct{})
+	for i, rawDefinition := range rawDefinitions {
+		definitionKeyPath := fmt.Sprintf("%s[%d]", keyPath, i)
+		q, err := parseQueryDefinition(definitionKeyPath, rawDefinition)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := names[q.name]; ok {
+			return nil, fmt.Errorf("%s: expected query names to be unique, but '%s' has been used before", definitionKeyPath, q.name)
+		}
+		names[q.name] = struct{}{}
+		result = append(result, q)
+	}
+
+	return result, nil
+
+}
+
+func parseQueryDefinition(keyPath string, a any) (*queryDefinition, error) {
+
+	m, ok := a.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: unable to parse value into query definition", keyPath)
+	}
+
+	q := &queryDefinition{maxResultSize: -1}
+
+	if err := client.ValidateString(keyPath+".name", m["name"]); err != nil {
+		return nil, err
+	}
+	q.name = m["name"].(string)
+
+	if err := client.ValidateString(keyPath+".type", m["type"]); err != nil {
+		return nil, err
+	}
+	q.queryType = queryType(m["type"].(string))
+
+	switch q.queryType {
+	case predicateQuery:
+		if err := client.ValidateString(keyPath+".operation", m["operation"]); err != nil {
+			return nil, err
+		}
+		q.operation = predicateQueryOperation(m["operation"].(string))
+		switch q.operation {
+		case entrySet, keySet, values:
+		default:
+			return nil, fmt.Errorf("%s.operation: predicate query operation expected to be one of '%s', '%s', or '%s', got %v", keyPath, entrySet, keySet, values, q.operation)
+		}
+	case sqlQuery:
+	default:
+		return nil, fmt.Errorf("%s.type: query type expected to be one of '%s' or '%s', got %v", keyPath, predicateQuery, sqlQuery, q.queryType)
+	}
+
+	if err := client.ValidateString(keyPath+".template", m["template"]); err != nil {
+		return nil, err
+	}
+	q.template = m["template"].(string)
+
+	if rawParams, ok := m["params"]; ok && rawParams != nil {
+		params, ok := rawParams.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: unable to parse value into list of query parameters", keyPath+".params")
+		}
+		q.params = params
+	}
+
+	if numPlaceholders := len(findPlaceholders(q.template)); numPlaceholders != len(q.params) {
+		return nil, fmt.Errorf("%s: expected number of parameters to match number of placeholders in template (%d), got %d", keyPath+".params", numPlaceholders, len(q.params))
+	}
+
+	if rawMin, ok := m["minResultSize"]; ok {
+		minResultSize, ok := rawMin.(int)
+		if !ok || minResultSize < 0 {
+			return nil, fmt.Errorf("%s: expected minimum result size to be non-negative number", keyPath+".minResultSize")
+		}
+		q.minResultSize = minResultSize
+	}
+
+	if rawMax, ok := m["maxResultSize"]; ok {
+		maxResultSize, ok := rawMax.(int)
+		if !ok || maxResultSize < q.minResultSize {
+			return nil, fmt.Errorf("%s: expected maximum result size to be number not less than minimum result size", keyPath+".maxResultSize")
+		}
+		q.maxResultSize = maxResultSize
+	}
+
+	return q, nil
+
+}
+
+func populateQueryConfig(a client.ConfigPropertyAssigner) (*queryRunnerConfig, error) {
+
+	var assignmentOps []func() error
+
+	var enabled bool
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapQueryRunnerKeyPath+".enabled", client.ValidateBool, func(a any) {
+			enabled = a.(bool)
+		})
+	})
+
+	var targetRunner string
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapQueryRunnerKeyPath+".targetRunner", validateQueryTargetRunner, func(a any) {
+			targetRunner = a.(string)
+		})
+	})
+
+	var numRuns uint32
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapQueryRunnerKeyPath+".numRuns", client.ValidateInt, func(a any) {
+			numRuns = uint32(a.(int))
+		})
+	})
+
+	var sleepBetweenRunsEnabled bool
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapQueryRunnerKeyPath+".sleeps.betweenRuns.enabled", client.ValidateBool, func(a any) {
+			sleepBetweenRunsEnabled = a.(bool)
+		})
+	})
+
+	var sleepBetweenRunsDurationMs int
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapQueryRunnerKeyPath+".sleeps.betweenRuns.durationMs", client.ValidateInt, func(a any) {
+			sleepBetweenRunsDurationMs = a.(int)
+		})
+	})
+
+	var sleepBetweenRunsEnableRandomness bool
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapQueryRunnerKeyPath+".sleeps.betweenRuns.enableRandomness", client.ValidateBool, func(a any) {
+			sleepBetweenRunsEnableRandomness = a.(bool)
+		})
+	})
+
+	var createSqlMapping bool
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapQueryRunnerKeyPath+".sql.createMapping.enabled", client.ValidateBool, func(a any) {
+			createSqlMapping = a.(bool)
+		})
+	})
+
+	var sqlMappingKeyFormat string
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapQueryRunnerKeyPath+".sql.createMapping.keyFormat", client.ValidateString, func(a any) {
+			sqlMappingKeyFormat = a.(string)
+		})
+	})
+
+	var sqlMappingValueFormat string
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapQueryRunnerKeyPath+".sql.createMapping.valueFormat", client.ValidateString, func(a any) {
+			sqlMappingValueFormat = a.(string)
+		})
+	})
+
+	var queries []*queryDefinition
+	assignmentOps = append(assignmentOps, func() error {
+		return a.Assign(mapQueryRunnerKeyPath+".queries", validateQueryDefinitions, func(a any) {
+			// Definitions have been validated at this point, so parsing them again cannot fail
+			queries, _ = parseQueryDefinitions(mapQueryRunnerKeyPath+".queries", a)
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	if enabled && len(queries) == 0 {
+		return nil, errors.New("query runner has been enabled, but no queries have been configured")
+	}
+
+	return &queryRunnerConfig{
+		enabled:      enabled,
+		numRuns:      numRuns,
+		targetRunner: targetRunner,
+		sleepBetweenRuns: &sleepConfig{
+			sleepBetweenRunsEnabled,
+			sleepBetweenRunsDurationMs,
+			sleepBetweenRunsEnableRandomness,
+		},
+		sqlMapping: &sqlMappingConfig{
+			enabled:     createSqlMapping,
+			keyFormat:   sqlMappingKeyFormat,
+			valueFormat: sqlMappingValueFormat,
+		},
+		queries: queries,
+	}, nil
+
+}
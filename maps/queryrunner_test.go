@@ -0,0 +1,689 @@
+package maps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hazelcast/hazelcast-go-client/sql"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type (
+	testSqlStore struct {
+		l                      sync.Mutex
+		statements             []string
+		lastParams             []any
+		numRows                int
+		returnErrorUponExecute bool
+	}
+	testSqlResult struct {
+		numRows int
+	}
+	testSqlRowsIterator struct {
+		remaining int
+	}
+)
+
+const testQueryTemplateKeyPath = mapQueryRunnerKeyPath + ".queries"
+
+var (
+	testQueryRunnerConfig = map[string]any{
+		mapQueryRunnerKeyPath + ".enabled":                             true,
+		mapQueryRunnerKeyPath + ".targetRunner":                        "pokedex",
+		mapQueryRunnerKeyPath + ".numRuns":                             3,
+		mapQueryRunnerKeyPath + ".sleeps.betweenRuns.enabled":          false,
+		mapQueryRunnerKeyPath + ".sql.createMapping.enabled":           true,
+		mapQueryRunnerKeyPath + ".sql.createMapping.keyFormat":         "varchar",
+		mapQueryRunnerKeyPath + ".sql.createMapping.valueFormat":       "varchar",
+		mapQueryRunnerKeyPath + ".sleeps.betweenRuns.durationMs":       1000,
+		mapQueryRunnerKeyPath + ".sleeps.betweenRuns.enableRandomness": false,
+		testQueryTemplateKeyPath: []any{
+			map[string]any{
+				"name":      "heavyPokemon",
+				"type":      "predicate",
+				"operation": "values",
+				"template":  "weight > ? and name != ?",
+				"params":    []any{50, "Snorlax"},
+			},
+			map[string]any{
+				"name":          "sqlKeys",
+				"type":          "sql",
+				"template":      "SELECT __key FROM \"{mapName}\" WHERE __key LIKE ?",
+				"params":        []any{"{clientId}-%"},
+				"minResultSize": 1,
+				"maxResultSize": 10,
+			},
+		},
+		"mapTests.pokedex.numMaps":                 3,
+		"mapTests.pokedex.appendMapIndexToMapName": true,
+		"mapTests.pokedex.mapPrefix.enabled":       true,
+		"mapTests.pokedex.mapPrefix.prefix":        "ht_",
+	}
+)
+
+func (s *testSqlStore) Execute(_ context.Context, query string, params ...any) (sql.Result, error) {
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	s.statements = append(s.statements, query)
+	s.lastParams = params
+
+	if s.returnErrorUponExecute {
+		return nil, errors.New("you shall not pass")
+	}
+
+	return &testSqlResult{numRows: s.numRows}, nil
+
+}
+
+func (r *testSqlResult) RowMetadata() (sql.RowMetadata, error) {
+	return nil, nil
+}
+
+func (r *testSqlResult) IsRowSet() bool {
+	return true
+}
+
+func (r *testSqlResult) UpdateCount() int64 {
+	return -1
+}
+
+func (r *testSqlResult) Iterator() (sql.RowsIterator, error) {
+	return &testSqlRowsIterator{remaining: r.numRows}, nil
+}
+
+func (r *testSqlResult) Close() error {
+	return nil
+}
+
+func (it *testSqlRowsIterator) HasNext() bool {
+	return it.remaining > 0
+}
+
+func (it *testSqlRowsIterator) Next() (sql.Row, error) {
+	it.remaining--
+	return nil, nil
+}
+
+func copyQueryRunnerTestConfig() map[string]any {
+
+	result := make(map[string]any, len(testQueryRunnerConfig))
+	for k, v := range testQueryRunnerConfig {
+		result[k] = v
+	}
+
+	return result
+
+}
+
+func assembleQueryTestLoop(cfg *queryRunnerConfig, mapNames []string, ms hazelcastwrapper.MapStore, ss hazelcastwrapper.SqlStore) *queryTestLoop {
+
+	l := &queryTestLoop{
+		runnerName: mapQueryRunnerName,
+		cfg:        cfg,
+		mapNames:   mapNames,
+		hzMapStore: ms,
+		hzSqlStore: ss,
+		ctx:        context.TODO(),
+	}
+	l.init(&testSleeper{}, status.NewGatherer())
+
+	return l
+
+}
+
+func TestParseQueryDefinitions(t *testing.T) {
+
+	t.Log("given a function to parse the query definitions contained in the config")
+	{
+		t.Log("\twhen query definitions are valid")
+		{
+			queries, err := parseQueryDefinitions(testQueryTemplateKeyPath, testQueryRunnerConfig[testQueryTemplateKeyPath])
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tpredicate query must have been parsed with unbounded result size"
+			q := queries[0]
+			if q.name == "heavyPokemon" && q.queryType == predicateQuery && q.operation == values &&
+				q.template == "weight > ? and name != ?" && len(q.params) == 2 &&
+				q.minResultSize == 0 && q.maxResultSize == -1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, *q)
+			}
+
+			msg = "\t\tsql query must have been parsed with configured result size boundaries"
+			q = queries[1]
+			if q.name == "sqlKeys" && q.queryType == sqlQuery && len(q.params) == 1 &&
+				q.minResultSize == 1 && q.maxResultSize == 10 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, *q)
+			}
+		}
+
+		validDefinition := func() map[string]any {
+			return map[string]any{"name": "fellowship", "type": "predicate", "operation": "keySet", "template": "__key like ?", "params": []any{"%"}}
+		}
+		withValues := func(kv ...any) map[string]any {
+			d := validDefinition()
+			for i := 0; i < len(kv); i += 2 {
+				d[kv[i].(string)] = kv[i+1]
+			}
+			return d
+		}
+		withoutValue := func(key string) map[string]any {
+			d := validDefinition()
+			delete(d, key)
+			return d
+		}
+
+		for _, tc := range []struct {
+			description string
+			definitions any
+		}{
+			{"definitions are not a list", validDefinition()},
+			{"list of definitions is empty", []any{}},
+			{"definition is not an object", []any{"gandalf"}},
+			{"definition lacks name", []any{withoutValue("name")}},
+			{"query type is unknown", []any{withValues("type", "palantir")}},
+			{"predicate query lacks operation", []any{withoutValue("operation")}},
+			{"predicate query operation is unknown", []any{withValues("operation", "size")}},
+			{"definition lacks template", []any{withoutValue("template")}},
+			{"params are not a list", []any{withValues("params", "%")}},
+			{"number of params does not match number of placeholders", []any{withValues("params", []any{"%", "%"})}},
+			{"question mark in string literal is counted as placeholder", []any{withValues("template", "__key like ? and name = 'Who?'", "params", []any{"%", "Who"})}},
+			{"minimum result size is negative", []any{withValues("minResultSize", -1)}},
+			{"maximum result size is less than minimum result size", []any{withValues("minResultSize", 5, "maxResultSize", 4)}},
+			{"names are not unique", []any{validDefinition(), validDefinition()}},
+		} {
+			t.Log(fmt.Sprintf("\twhen %s", tc.description))
+			{
+				queries, err := parseQueryDefinitions(testQueryTemplateKeyPath, tc.definitions)
+
+				msg := "\t\terror must be returned"
+				if err != nil {
+					t.Log(msg, checkMark, err)
+				} else {
+					t.Fatal(msg, ballotX)
+				}
+
+				msg = "\t\tno query definitions must be returned"
+				if queries == nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, queries)
+				}
+			}
+		}
+	}
+
+}
+
+func TestAssemblePredicateExpression(t *testing.T) {
+
+	t.Log("given a function to fill the placeholders of a predicate template with parameters")
+	{
+		for _, tc := range []struct {
+			template string
+			params   []any
+			expected string
+		}{
+			{"weight > ?", []any{50}, "weight > 50"},
+			{"weight > ? and name = ?", []any{12.5, "Bulbasaur"}, "weight > 12.5 and name = 'Bulbasaur'"},
+			{"name = ?", []any{"Farfetch'd"}, "name = 'Farfetch''d'"},
+			{"__key like '%'", nil, "__key like '%'"},
+			{"name = 'Who?' and weight > ?", []any{50}, "name = 'Who?' and weight > 50"},
+			{"name = 'Farfetch''d?' or name = ?", []any{"Mr. Mime"}, "name = 'Farfetch''d?' or name = 'Mr. Mime'"},
+		} {
+			t.Log(fmt.Sprintf("\twhen template is '%s'", tc.template))
+			{
+				msg := "\t\tplaceholders must have been replaced by parameters, with strings having been quoted"
+				if actual := assemblePredicateExpression(tc.template, tc.params); actual == tc.expected {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, actual)
+				}
+			}
+		}
+	}
+
+}
+
+func TestExpandQueryPlaceholders(t *testing.T) {
+
+	t.Log("given a function to expand the map name and client id placeholders")
+	{
+		t.Log("\twhen string contains both placeholders")
+		{
+			actual := expandQueryPlaceholders("{clientId}-{mapName}-%", "ht_pokedex-0")
+
+			msg := "\t\tboth placeholders must have been expanded"
+			if expected := fmt.Sprintf("%s-ht_pokedex-0-%%", client.ID()); actual == expected {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, actual)
+			}
+		}
+
+		t.Log("\twhen value is not a string")
+		{
+			msg := "\t\tvalue must be returned as-is"
+			if actual := expandQueryPlaceholders(42, "ht_pokedex-0"); actual == 42 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, actual)
+			}
+		}
+	}
+
+}
+
+func TestQueryDefinitionResultSizeAsExpected(t *testing.T) {
+
+	t.Log("given a query definition's method to check the size of a query result")
+	{
+		for _, tc := range []struct {
+			minResultSize, maxResultSize, size int
+			expected                           bool
+		}{
+			{0, -1, 0, true},
+			{0, -1, 100_000, true},
+			{1, -1, 0, false},
+			{1, 10, 10, true},
+			{1, 10, 11, false},
+			{0, 0, 0, true},
+		} {
+			t.Log(fmt.Sprintf("\twhen expected result size is %d to %d and actual size is %d", tc.minResultSize, tc.maxResultSize, tc.size))
+			{
+				q := &queryDefinition{minResultSize: tc.minResultSize, maxResultSize: tc.maxResultSize}
+
+				msg := "\t\tresult size check must yield expected outcome"
+				if q.resultSizeAsExpected(tc.size) == tc.expected {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX)
+				}
+			}
+		}
+	}
+
+}
+
+func TestAssembleQueryTargetMapNames(t *testing.T) {
+
+	t.Log("given a function to determine the names of the maps to query from the target runner's config")
+	{
+		t.Log("\twhen target runner appends map index to map name")
+		{
+			names, err := assembleQueryTargetMapNames(testConfigPropertyAssigner{testConfig: testQueryRunnerConfig}, "pokedex")
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tone map name must have been returned for each of the target runner's map goroutines"
+			if len(names) == 3 && names[0] == "ht_pokedex-0" && names[2] == "ht_pokedex-2" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, names)
+			}
+		}
+
+		t.Log("\twhen target runner does not append map index to map name")
+		{
+			tc := copyQueryRunnerTestConfig()
+			tc["mapTests.pokedex.appendMapIndexToMapName"] = false
+
+			names, err := assembleQueryTargetMapNames(testConfigPropertyAssigner{testConfig: tc}, "pokedex")
+
+			msg := "\t\tshared map name must have been returned only once"
+			if err == nil && len(names) == 1 && names[0] == "ht_pokedex" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, names, err)
+			}
+		}
+
+		t.Log("\twhen target runner is unknown")
+		{
+			names, err := assembleQueryTargetMapNames(testConfigPropertyAssigner{testConfig: testQueryRunnerConfig}, "palantir")
+
+			msg := "\t\terror must be returned"
+			if err != nil && names == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, names)
+			}
+		}
+	}
+
+}
+
+func TestPopulateQueryConfig(t *testing.T) {
+
+	t.Log("given set of configuration properties to populate the query runner config from")
+	{
+		t.Log("\twhen properties are correct")
+		{
+			cfg, err := populateQueryConfig(testConfigPropertyAssigner{testConfig: testQueryRunnerConfig})
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tconfig must contain expected values"
+			if cfg.enabled && cfg.targetRunner == "pokedex" && cfg.numRuns == 3 &&
+				*cfg.sleepBetweenRuns == (sleepConfig{false, 1000, false}) &&
+				*cfg.sqlMapping == (sqlMappingConfig{true, "varchar", "varchar"}) &&
+				len(cfg.queries) == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, cfg)
+			}
+		}
+
+		t.Log("\twhen target runner is invalid")
+		{
+			tc := copyQueryRunnerTestConfig()
+			tc[mapQueryRunnerKeyPath+".targetRunner"] = "palantir"
+
+			cfg, err := populateQueryConfig(testConfigPropertyAssigner{testConfig: tc})
+
+			msg := "\t\terror must be returned"
+			if err != nil && cfg == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen runner has been enabled, but no queries have been configured")
+		{
+			tc := copyQueryRunnerTestConfig()
+			delete(tc, testQueryTemplateKeyPath)
+
+			cfg, err := populateQueryConfig(testConfigPropertyAssigner{testConfig: tc})
+
+			msg := "\t\terror must be returned"
+			if err != nil && cfg == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestQueryTestLoopRun(t *testing.T) {
+
+	t.Log("given the query test loop's run function")
+	{
+		t.Log("\twhen predicate queries succeed")
+		{
+			cfg, _ := populateQueryConfig(testConfigPropertyAssigner{testConfig: testQueryRunnerConfig})
+			cfg.queries = cfg.queries[:1]
+			ms := assembleTestMapStore(&testMapStoreBehavior{})
+			populateTestHzMapStore(defaultTestMapName, defaultTestMapNumber, &ms)
+			ss := &testSqlStore{}
+
+			l := assembleQueryTestLoop(cfg, []string{"ht_pokedex-0"}, ms, ss)
+			go l.gatherer.Listen()
+			l.run()
+			l.gatherer.StopListen()
+			waitForStatusGatheringDone(l.gatherer)
+
+			msg := "\t\tpredicate query must have been run once per run using configured operation"
+			if ms.m.getValuesWithPredicateInvocations == 3 && ms.m.getEntrySetWithPredicateInvocations == 0 && ms.m.getKeySetWithPredicateInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ms.m.getValuesWithPredicateInvocations)
+			}
+
+			msg = "\t\tpredicate must have been assembled from template and params"
+			if strings.Contains(ms.m.lastQueryPredicate, "weight > 50 and name != 'Snorlax'") {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ms.m.lastQueryPredicate)
+			}
+
+			msg = "\t\tno sql statement must have been executed, not even for creating a mapping"
+			if len(ss.statements) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ss.statements)
+			}
+
+			statusCopy := l.gatherer.AssembleStatusCopy()
+
+			msg = "\t\tcounters must have been reported as zero"
+			for _, v := range queryCounters {
+				if ok, detail := expectedCounterValuePresent(statusCopy, v, 0); ok {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, detail)
+				}
+			}
+
+			msg = "\t\tlatencies of query must have been reported"
+			if snapshots, ok := statusCopy[string(statusKeyLatencies)].(map[string]status.HistogramSnapshot); ok && snapshots["heavyPokemon"].Count == 3 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, statusCopy[string(statusKeyLatencies)])
+			}
+		}
+
+		t.Log("\twhen predicate queries fail")
+		{
+			cfg, _ := populateQueryConfig(testConfigPropertyAssigner{testConfig: testQueryRunnerConfig})
+			cfg.queries = cfg.queries[:1]
+			ms := assembleTestMapStore(&testMapStoreBehavior{})
+			ms.m.returnErrorUponQuery = true
+
+			l := assembleQueryTestLoop(cfg, []string{"ht_pokedex-0"}, ms, &testSqlStore{})
+			go l.gatherer.Listen()
+			l.run()
+			l.gatherer.StopListen()
+			waitForStatusGatheringDone(l.gatherer)
+
+			msg := "\t\tfailed queries must have been counted"
+			if ok, detail := expectedCounterValuePresent(l.gatherer.AssembleStatusCopy(), statusKeyNumFailedQueries, 3); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
+
+		t.Log("\twhen sql query returns number of rows outside expected range")
+		{
+			cfg, _ := populateQueryConfig(testConfigPropertyAssigner{testConfig: testQueryRunnerConfig})
+			cfg.queries = cfg.queries[1:]
+			ms := assembleTestMapStore(&testMapStoreBehavior{})
+			ss := &testSqlStore{numRows: 11}
+
+			l := assembleQueryTestLoop(cfg, []string{"ht_pokedex-0", "ht_pokedex-1"}, ms, ss)
+			go l.gatherer.Listen()
+			l.run()
+			l.gatherer.StopListen()
+			waitForStatusGatheringDone(l.gatherer)
+
+			msg := "\t\tmapping must have been created once for each map before running sql queries on it"
+			numMappingStatements := 0
+			for _, s := range ss.statements {
+				if strings.HasPrefix(s, "CREATE MAPPING IF NOT EXISTS") {
+					numMappingStatements++
+				}
+			}
+			if numMappingStatements == 2 && len(ss.statements) == 8 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ss.statements)
+			}
+
+			msg = "\t\tplaceholders in statement and params must have been expanded"
+			if strings.Contains(ss.statements[len(ss.statements)-1], "FROM \"ht_pokedex-") && strings.HasPrefix(ss.lastParams[0].(string), client.ID().String()) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ss.statements[len(ss.statements)-1], ss.lastParams)
+			}
+
+			msg = "\t\tunexpected result sizes must have been counted"
+			if ok, detail := expectedCounterValuePresent(l.gatherer.AssembleStatusCopy(), statusKeyNumUnexpectedResultSizes, 6); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
+
+		t.Log("\twhen sql queries fail")
+		{
+			cfg, _ := populateQueryConfig(testConfigPropertyAssigner{testConfig: testQueryRunnerConfig})
+			cfg.queries = cfg.queries[1:]
+
+			l := assembleQueryTestLoop(cfg, []string{"ht_pokedex-0"}, assembleTestMapStore(&testMapStoreBehavior{}), &testSqlStore{returnErrorUponExecute: true})
+			go l.gatherer.Listen()
+			l.run()
+			l.gatherer.StopListen()
+			waitForStatusGatheringDone(l.gatherer)
+
+			msg := "\t\tfailed queries must have been counted, but failed mapping creation must not"
+			if ok, detail := expectedCounterValuePresent(l.gatherer.AssembleStatusCopy(), statusKeyNumFailedQueries, 3); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
+	}
+
+}
+
+func TestRunQueryMapTests(t *testing.T) {
+
+	t.Log("given the query runner to run map tests")
+	{
+		genericMsgStateTransitions := "\t\tstate transitions must be correct"
+
+		t.Log("\twhen runner configuration cannot be populated")
+		{
+			ch := &testHzClientHandler{}
+			r := queryRunner{assigner: testConfigPropertyAssigner{returnError: true}, stateList: []runnerState{}, hzClientHandler: ch}
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+			r.runMapTests(context.TODO(), hzCluster, hzMembers, gatherer)
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			msg := "\t\thazelcast client handler must not have initialized hazelcast client"
+			if ch.initClientInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations)
+			}
+		}
+
+		t.Log("\twhen runner has been disabled")
+		{
+			tc := copyQueryRunnerTestConfig()
+			tc[mapQueryRunnerKeyPath+".enabled"] = false
+			ch := &testHzClientHandler{}
+			r := queryRunner{assigner: testConfigPropertyAssigner{testConfig: tc}, stateList: []runnerState{}, hzClientHandler: ch}
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+			r.runMapTests(context.TODO(), hzCluster, hzMembers, gatherer)
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start, populateConfigComplete}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			msg := "\t\thazelcast client handler must not have initialized hazelcast client"
+			if ch.initClientInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations)
+			}
+		}
+
+		t.Log("\twhen test loop has executed")
+		{
+			ch := &testHzClientHandler{}
+			ms := assembleTestMapStore(&testMapStoreBehavior{})
+			ss := &testSqlStore{numRows: 5}
+			r := queryRunner{
+				assigner:        testConfigPropertyAssigner{testConfig: testQueryRunnerConfig},
+				stateList:       []runnerState{},
+				hzClientHandler: ch,
+				providerFuncs: struct {
+					mapStore newMapStoreFunc
+					sqlStore newSqlStoreFunc
+				}{mapStore: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.MapStore {
+					return ms
+				}, sqlStore: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.SqlStore {
+					return ss
+				}},
+			}
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+			r.runMapTests(context.TODO(), hzCluster, hzMembers, gatherer)
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions(expectedStatesForFullRun, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			msg := "\t\tqueries must have been run on each map of target runner"
+			if ms.m.getValuesWithPredicateInvocations == 9 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ms.m.getValuesWithPredicateInvocations)
+			}
+
+			msg = "\t\thazelcast client handler must have initialized and shut down hazelcast client once"
+			if ch.initClientInvocations == 1 && ch.shutdownInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations, ch.shutdownInvocations)
+			}
+
+			msg = "\t\tnumber of maps must have been reported"
+			if v, ok := gatherer.AssembleStatusCopy()[string(statusKeyNumMaps)]; ok && v == uint16(3) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, v)
+			}
+		}
+	}
+
+}
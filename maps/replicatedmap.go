@@ -0,0 +1,105 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"github.com/hazelcast/hazelcast-go-client"
+	"github.com/hazelcast/hazelcast-go-client/predicate"
+	"github.com/hazelcast/hazelcast-go-client/types"
+	"hazeltest/hazelcastwrapper"
+	"time"
+)
+
+type (
+	// replicatedMapStore makes replicated maps available to the map test loops by presenting them as maps. Replicated
+	// maps offer only a subset of the operations of maps, so the operations they lack return an error -- runners
+	// using this store must make sure the test loop features relying on those operations have been disabled.
+	replicatedMapStore struct {
+		rms hazelcastwrapper.ReplicatedMapStore
+	}
+	replicatedMap struct {
+		rm hazelcastwrapper.ReplicatedMap
+	}
+)
+
+func (s *replicatedMapStore) GetMap(ctx context.Context, name string) (hazelcastwrapper.Map, error) {
+
+	rm, err := s.rms.GetReplicatedMap(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &replicatedMap{rm: rm}, nil
+
+}
+
+func unsupportedReplicatedMapOperation(operation string) error {
+	return fmt.Errorf("operation '%s' not supported by replicated maps", operation)
+}
+
+func (m *replicatedMap) ContainsKey(ctx context.Context, key any) (bool, error) {
+	return m.rm.ContainsKey(ctx, key)
+}
+
+func (m *replicatedMap) Set(ctx context.Context, key any, value any) error {
+	_, err := m.rm.Put(ctx, key, value)
+	return err
+}
+
+func (m *replicatedMap) SetWithTTLAndMaxIdle(_ context.Context, _, _ any, _ time.Duration, _ time.Duration) error {
+	return unsupportedReplicatedMapOperation("SetWithTTLAndMaxIdle")
+}
+
+func (m *replicatedMap) Get(ctx context.Context, key any) (any, error) {
+	return m.rm.Get(ctx, key)
+}
+
+func (m *replicatedMap) Remove(ctx context.Context, key any) (any, error) {
+	return m.rm.Remove(ctx, key)
+}
+
+func (m *replicatedMap) Destroy(ctx context.Context) error {
+	return m.rm.Destroy(ctx)
+}
+
+func (m *replicatedMap) Size(ctx context.Context) (int, error) {
+	return m.rm.Size(ctx)
+}
+
+func (m *replicatedMap) RemoveAll(_ context.Context, _ predicate.Predicate) error {
+	return unsupportedReplicatedMapOperation("RemoveAll")
+}
+
+func (m *replicatedMap) GetEntrySetWithPredicate(_ context.Context, _ predicate.Predicate) ([]types.Entry, error) {
+	return nil, unsupportedReplicatedMapOperation("GetEntrySetWithPredicate")
+}
+
+func (m *replicatedMap) GetKeySetWithPredicate(_ context.Context, _ predicate.Predicate) ([]any, error) {
+	return nil, unsupportedReplicatedMapOperation("GetKeySetWithPredicate")
+}
+
+func (m *replicatedMap) GetValuesWithPredicate(_ context.Context, _ predicate.Predicate) ([]any, error) {
+	return nil, unsupportedReplicatedMapOperation("GetValuesWithPredicate")
+}
+
+// EvictAll clears the replicated map -- replicated maps are not backed by a map store, so evicting all entries is
+// equivalent to removing them.
+func (m *replicatedMap) EvictAll(ctx context.Context) error {
+	return m.rm.Clear(ctx)
+}
+
+func (m *replicatedMap) TryLock(_ context.Context, _ any) (bool, error) {
+	return false, unsupportedReplicatedMapOperation("TryLock")
+}
+
+func (m *replicatedMap) Unlock(_ context.Context, _ any) error {
+	return unsupportedReplicatedMapOperation("Unlock")
+}
+
+func (m *replicatedMap) AddListenerWithPredicate(_ context.Context, _ hazelcast.MapListener, _ predicate.Predicate, _ bool) (types.UUID, error) {
+	return types.UUID{}, unsupportedReplicatedMapOperation("AddListenerWithPredicate")
+}
+
+func (m *replicatedMap) RemoveListener(_ context.Context, _ types.UUID) error {
+	return unsupportedReplicatedMapOperation("RemoveListener")
+}
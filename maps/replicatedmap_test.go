@@ -0,0 +1,216 @@
+package maps
+
+import (
+	"context"
+	"errors"
+	"hazeltest/hazelcastwrapper"
+	"testing"
+)
+
+type (
+	testHzReplicatedMapStore struct {
+		rm                              *testHzReplicatedMap
+		returnErrorUponGetReplicatedMap bool
+	}
+	testHzReplicatedMap struct {
+		data                                                                      map[any]any
+		putInvocations, getInvocations, removeInvocations, containsKeyInvocations int
+		sizeInvocations, clearInvocations, destroyInvocations                     int
+	}
+)
+
+func (s *testHzReplicatedMapStore) GetReplicatedMap(_ context.Context, _ string) (hazelcastwrapper.ReplicatedMap, error) {
+	if s.returnErrorUponGetReplicatedMap {
+		return nil, errors.New("replication is not an option today")
+	}
+	return s.rm, nil
+}
+
+func (m *testHzReplicatedMap) ContainsKey(_ context.Context, key any) (bool, error) {
+	m.containsKeyInvocations++
+	_, ok := m.data[key]
+	return ok, nil
+}
+
+func (m *testHzReplicatedMap) Put(_ context.Context, key any, value any) (any, error) {
+	m.putInvocations++
+	old := m.data[key]
+	m.data[key] = value
+	return old, nil
+}
+
+func (m *testHzReplicatedMap) Get(_ context.Context, key any) (any, error) {
+	m.getInvocations++
+	return m.data[key], nil
+}
+
+func (m *testHzReplicatedMap) Remove(_ context.Context, key any) (any, error) {
+	m.removeInvocations++
+	old := m.data[key]
+	delete(m.data, key)
+	return old, nil
+}
+
+func (m *testHzReplicatedMap) Size(_ context.Context) (int, error) {
+	m.sizeInvocations++
+	return len(m.data), nil
+}
+
+func (m *testHzReplicatedMap) Clear(_ context.Context) error {
+	m.clearInvocations++
+	m.data = make(map[any]any)
+	return nil
+}
+
+func (m *testHzReplicatedMap) Destroy(_ context.Context) error {
+	m.destroyInvocations++
+	return nil
+}
+
+func TestReplicatedMapStoreGetMap(t *testing.T) {
+
+	t.Log("given a replicated map store presenting replicated maps as maps")
+	{
+		t.Log("\twhen retrieving the replicated map fails")
+		{
+			s := &replicatedMapStore{rms: &testHzReplicatedMapStore{returnErrorUponGetReplicatedMap: true}}
+
+			m, err := s.GetMap(context.TODO(), "awesome-map")
+
+			msg := "\t\terror must be returned"
+			if err != nil && m == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen retrieving the replicated map succeeds")
+		{
+			rm := &testHzReplicatedMap{data: make(map[any]any)}
+			s := &replicatedMapStore{rms: &testHzReplicatedMapStore{rm: rm}}
+
+			m, err := s.GetMap(context.TODO(), "awesome-map")
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\treturned map must wrap replicated map"
+			if wrapper, ok := m.(*replicatedMap); ok && wrapper.rm == rm {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestReplicatedMapOperations(t *testing.T) {
+
+	t.Log("given a replicated map presented as map")
+	{
+		ctx := context.TODO()
+		t.Log("\twhen operations supported by replicated maps are invoked")
+		{
+			rm := &testHzReplicatedMap{data: make(map[any]any)}
+			m := &replicatedMap{rm: rm}
+
+			err := m.Set(ctx, "pikachu", "electric")
+
+			msg := "\t\tset must put value into replicated map"
+			if err == nil && rm.putInvocations == 1 && rm.data["pikachu"] == "electric" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			v, err := m.Get(ctx, "pikachu")
+
+			msg = "\t\tget must return value from replicated map"
+			if err == nil && v == "electric" && rm.getInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, v, err)
+			}
+
+			contained, err := m.ContainsKey(ctx, "pikachu")
+			size, sizeErr := m.Size(ctx)
+
+			msg = "\t\tkey check and size must be delegated to replicated map"
+			if err == nil && sizeErr == nil && contained && size == 1 && rm.containsKeyInvocations == 1 && rm.sizeInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, contained, size)
+			}
+
+			v, err = m.Remove(ctx, "pikachu")
+
+			msg = "\t\tremove must remove value from replicated map"
+			if err == nil && v == "electric" && len(rm.data) == 0 && rm.removeInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, v, err)
+			}
+
+			rm.data["charmander"] = "fire"
+			err = m.EvictAll(ctx)
+
+			msg = "\t\tevicting all entries must clear replicated map"
+			if err == nil && len(rm.data) == 0 && rm.clearInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			err = m.Destroy(ctx)
+
+			msg = "\t\tdestroy must be delegated to replicated map"
+			if err == nil && rm.destroyInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen operations not supported by replicated maps are invoked")
+		{
+			rm := &testHzReplicatedMap{data: make(map[any]any)}
+			m := &replicatedMap{rm: rm}
+
+			_, getEntrySetErr := m.GetEntrySetWithPredicate(ctx, nil)
+			_, getKeySetErr := m.GetKeySetWithPredicate(ctx, nil)
+			_, getValuesErr := m.GetValuesWithPredicate(ctx, nil)
+			_, tryLockErr := m.TryLock(ctx, "pikachu")
+			errs := []error{
+				m.SetWithTTLAndMaxIdle(ctx, "pikachu", "electric", 0, 0),
+				m.RemoveAll(ctx, nil),
+				getEntrySetErr,
+				getKeySetErr,
+				getValuesErr,
+				tryLockErr,
+				m.Unlock(ctx, "pikachu"),
+			}
+
+			msg := "\t\terror must be returned for each operation"
+			for i, err := range errs {
+				if err == nil {
+					t.Fatal(msg, ballotX, i)
+				}
+			}
+			t.Log(msg, checkMark)
+
+			msg = "\t\treplicated map must not have been modified"
+			if len(rm.data) == 0 && rm.putInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
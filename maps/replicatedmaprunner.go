@@ -0,0 +1,184 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/state"
+	"hazeltest/status"
+)
+
+type (
+	replicatedMapRunner struct {
+		assigner        client.ConfigPropertyAssigner
+		stateList       []runnerState
+		name            string
+		source          string
+		hzMapStore      hazelcastwrapper.MapStore
+		hzClientHandler hazelcastwrapper.HzClientHandler
+		l               looper[pokemon]
+		gatherer        *status.Gatherer
+		providerFuncs   struct {
+			mapStore        newMapStoreFunc
+			pokemonTestLoop newPokemonTestLoopFunc
+		}
+	}
+)
+
+const (
+	mapReplicatedMapRunnerKeyPath     = "mapTests.replicatedMap"
+	mapReplicatedMapRunnerMapBaseName = "replicatedPokedex"
+)
+
+var (
+	newReplicatedMapStore newMapStoreFunc = func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.MapStore {
+		return &replicatedMapStore{rms: &hazelcastwrapper.DefaultReplicatedMapStore{Client: ch.GetClient()}}
+	}
+)
+
+func init() {
+	register(&replicatedMapRunner{
+		assigner:        &client.DefaultConfigPropertyAssigner{},
+		stateList:       []runnerState{},
+		name:            "mapsReplicatedMapRunner",
+		source:          "replicatedMapRunner",
+		hzClientHandler: &hazelcastwrapper.DefaultHzClientHandler{},
+		providerFuncs: struct {
+			mapStore        newMapStoreFunc
+			pokemonTestLoop newPokemonTestLoopFunc
+		}{mapStore: newReplicatedMapStore, pokemonTestLoop: initPokedexTestLoop},
+	})
+}
+
+func (r *replicatedMapRunner) getSourceName() string {
+	return r.source
+}
+
+func (r *replicatedMapRunner) runMapTests(ctx context.Context, hzCluster string, hzMembers []string, gatherer *status.Gatherer) {
+
+	r.gatherer = gatherer
+	r.appendState(start)
+
+	config, err := populateReplicatedMapConfig(r.assigner)
+	if err != nil {
+		lp.LogMapRunnerEvent(fmt.Sprintf("aborting launch of replicated map runner: unable to populate config due to error: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.appendState(populateConfigComplete)
+
+	if !config.enabled {
+		lp.LogMapRunnerEvent("replicated map runner not enabled -- won't run", r.name, log.InfoLevel)
+		return
+	}
+	r.appendState(checkEnabledComplete)
+
+	api.RaiseNotReady()
+
+	p, err := parsePokedexFile(r.name)
+
+	if err != nil {
+		lp.LogIoEvent(fmt.Sprintf("unable to parse pokedex json file: %s", err), log.FatalLevel)
+	}
+
+	l, err := r.providerFuncs.pokemonTestLoop(config)
+	if err != nil {
+		lp.LogMapRunnerEvent(fmt.Sprintf("aborting launch of replicated map runner: unable to initialize test loop: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.l = l
+
+	r.appendState(assignTestLoopComplete)
+
+	r.hzClientHandler.InitHazelcastClient(ctx, r.name, hzCluster, hzMembers)
+	defer func() {
+		_ = r.hzClientHandler.Shutdown(ctx)
+	}()
+	r.hzMapStore = r.providerFuncs.mapStore(r.hzClientHandler)
+
+	api.RaiseReady()
+	r.appendState(raiseReadyComplete)
+
+	lp.LogMapRunnerEvent("initialized hazelcast client", r.name, log.InfoLevel)
+	lp.LogMapRunnerEvent("starting pokedex test loop for replicated maps", r.name, log.InfoLevel)
+
+	le := &testLoopExecution[pokemon]{
+		id:                   uuid.New(),
+		runnerName:           r.name,
+		source:               r.source,
+		hzClientHandler:      r.hzClientHandler,
+		hzMapStore:           r.hzMapStore,
+		stateCleanerBuilder:  &state.DefaultSingleMapCleanerBuilder{},
+		runnerConfig:         config,
+		elements:             p.Pokemon,
+		ctx:                  ctx,
+		getElementID:         getPokemonID,
+		getOrAssemblePayload: returnPokemonPayload,
+	}
+
+	r.l.init(le, &defaultSleeper{}, r.gatherer)
+
+	r.appendState(testLoopStart)
+	r.l.run()
+	r.appendState(testLoopComplete)
+
+	lp.LogMapRunnerEvent("finished pokedex replicated maps loop", r.name, log.InfoLevel)
+
+}
+
+func (r *replicatedMapRunner) appendState(s runnerState) {
+
+	r.stateList = append(r.stateList, s)
+	r.gatherer.Updates <- status.Update{Key: string(statusKeyCurrentState), Value: string(s)}
+
+}
+
+// validateReplicatedMapConfig makes sure the given config does not enable any test loop feature relying on map
+// operations replicated maps do not offer.
+func validateReplicatedMapConfig(rc *runnerConfig, runnerKeyPath string) error {
+
+	if rc.expiry.enabled {
+		return fmt.Errorf("expiry enabled for '%s', but replicated maps do not support writing entries with ttl or max idle", runnerKeyPath)
+	}
+
+	if rc.preRunClean.enabled {
+		return fmt.Errorf("pre-run clean enabled for '%s', but pre-run cleaning is not supported for replicated maps", runnerKeyPath)
+	}
+
+	if rc.entryListener.enabled {
+		return fmt.Errorf("entry listener verification enabled for '%s', but entry listeners are not supported for replicated maps", runnerKeyPath)
+	}
+
+	if rc.boundary != nil && rc.boundary.resetAfterChain {
+		return fmt.Errorf("reset after operation chain enabled for '%s', but replicated maps do not support removing entries by predicate", runnerKeyPath)
+	}
+
+	return nil
+
+}
+
+func populateReplicatedMapConfig(a client.ConfigPropertyAssigner) (*runnerConfig, error) {
+
+	configBuilder := runnerConfigBuilder{
+		assigner:      a,
+		runnerKeyPath: mapReplicatedMapRunnerKeyPath,
+		mapBaseName:   mapReplicatedMapRunnerMapBaseName,
+	}
+
+	cfg, err := configBuilder.populateConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.enabled {
+		if err := validateReplicatedMapConfig(cfg, mapReplicatedMapRunnerKeyPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+
+}
@@ -0,0 +1,254 @@
+package maps
+
+import (
+	"context"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"strings"
+	"testing"
+)
+
+func TestRunReplicatedMapTests(t *testing.T) {
+
+	t.Log("given the replicated map runner to run map tests")
+	{
+		genericMsgStateTransitions := "\t\tstate transitions must be correct"
+		genericMsgLatestStateInGatherer := "\t\tlatest state in gatherer must be correct"
+		t.Log("\twhen runner configuration cannot be populated")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: true,
+				testConfig:  nil,
+			}
+			ch := &testHzClientHandler{}
+			r := replicatedMapRunner{
+				assigner:        assigner,
+				stateList:       []runnerState{},
+				hzClientHandler: ch,
+			}
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runMapTests(context.TODO(), hzCluster, hzMembers, gatherer)
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(r.gatherer, start) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark, start)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, start)
+			}
+
+			msg := "\t\thazelcast client handler must not have initialized hazelcast client"
+			if ch.initClientInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations)
+			}
+		}
+		t.Log("\twhen runner has been disabled")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: false,
+				testConfig: map[string]any{
+					"mapTests.replicatedMap.enabled": false,
+				},
+			}
+			ch := &testHzClientHandler{}
+			r := replicatedMapRunner{
+				assigner:        assigner,
+				stateList:       []runnerState{},
+				hzClientHandler: ch,
+			}
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runMapTests(context.TODO(), hzCluster, hzMembers, gatherer)
+			gatherer.StopListen()
+
+			latestState := populateConfigComplete
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start, latestState}, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			if latestStatePresentInGatherer(r.gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark, latestState)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+
+			msg := "\t\thazelcast client handler must not have initialized hazelcast client"
+			if ch.initClientInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations)
+			}
+		}
+		t.Log("\twhen test loop has executed")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: false,
+				testConfig: map[string]any{
+					"mapTests.replicatedMap.enabled":       true,
+					"mapTests.replicatedMap.testLoop.type": "batch",
+				},
+			}
+			ch := &testHzClientHandler{}
+			ms := &testHzMapStore{observations: &testHzMapStoreObservations{}}
+			r := replicatedMapRunner{
+				assigner:        assigner,
+				stateList:       []runnerState{},
+				hzClientHandler: ch,
+				providerFuncs: struct {
+					mapStore        newMapStoreFunc
+					pokemonTestLoop newPokemonTestLoopFunc
+				}{mapStore: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.MapStore {
+					ms.observations.numInitInvocations++
+					return ms
+				}, pokemonTestLoop: func(rc *runnerConfig) (looper[pokemon], error) {
+					return &testPokedexTestLoop{}, nil
+				}},
+			}
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+
+			r.runMapTests(context.TODO(), hzCluster, hzMembers, gatherer)
+			gatherer.StopListen()
+
+			if msg, ok := checkRunnerStateTransitions(expectedStatesForFullRun, r.stateList); ok {
+				t.Log(genericMsgStateTransitions, checkMark)
+			} else {
+				t.Fatal(genericMsgStateTransitions, ballotX, msg)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+			latestState := r.stateList[len(r.stateList)-1]
+
+			if latestStatePresentInGatherer(r.gatherer, latestState) {
+				t.Log(genericMsgLatestStateInGatherer, checkMark, latestState)
+			} else {
+				t.Fatal(genericMsgLatestStateInGatherer, ballotX, latestState)
+			}
+
+			msg := "\t\thazelcast client handler must have initialized and shut down hazelcast client once"
+			if ch.initClientInvocations == 1 && ch.shutdownInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ch.initClientInvocations, ch.shutdownInvocations)
+			}
+
+			msg = "\t\tmap store must have been initialized once"
+			if ms.observations.numInitInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ms.observations.numInitInvocations)
+			}
+		}
+	}
+
+}
+
+func TestPopulateReplicatedMapConfig(t *testing.T) {
+
+	t.Log("given a function to populate the replicated map runner's config")
+	{
+		t.Log("\twhen no map feature unsupported by replicated maps has been enabled")
+		{
+			assigner := testConfigPropertyAssigner{
+				returnError: false,
+				testConfig: map[string]any{
+					"mapTests.replicatedMap.enabled":                                                                     true,
+					"mapTests.replicatedMap.testLoop.type":                                                               "boundary",
+					"mapTests.replicatedMap.testLoop.boundary.operationChain.length":                                     100,
+					"mapTests.replicatedMap.integrityVerification.enabled":                                               true,
+					"mapTests.replicatedMap.testLoop.boundary.operationChain.boundaryDefinition.upper.mapFillPercentage": 0.9,
+					"mapTests.replicatedMap.testLoop.boundary.operationChain.boundaryDefinition.lower.mapFillPercentage": 0.2,
+				},
+			}
+
+			rc, err := populateReplicatedMapConfig(assigner)
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tconfig must use replicated map base name and verify integrity"
+			if rc.mapBaseName == mapReplicatedMapRunnerMapBaseName && rc.verifyIntegrity {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, rc.mapBaseName, rc.verifyIntegrity)
+			}
+		}
+
+		unsupportedFeatures := map[string]map[string]any{
+			"expiry": {
+				"mapTests.replicatedMap.expiry.enabled":      true,
+				"mapTests.replicatedMap.expiry.ttl.enabled":  true,
+				"mapTests.replicatedMap.expiry.ttl.duration": "60s",
+			},
+			"entry listener verification": {
+				"mapTests.replicatedMap.entryListenerVerification.enabled": true,
+			},
+			"pre-run clean": {
+				"mapTests.replicatedMap.performPreRunClean.enabled":       true,
+				"mapTests.replicatedMap.performPreRunClean.errorBehavior": "ignore",
+			},
+			"reset after chain": {
+				"mapTests.replicatedMap.testLoop.type":                                                               "boundary",
+				"mapTests.replicatedMap.testLoop.boundary.operationChain.resetAfterChain":                            true,
+				"mapTests.replicatedMap.testLoop.boundary.operationChain.boundaryDefinition.upper.mapFillPercentage": 0.9,
+				"mapTests.replicatedMap.testLoop.boundary.operationChain.boundaryDefinition.lower.mapFillPercentage": 0.2,
+			},
+		}
+		for feature, featureConfig := range unsupportedFeatures {
+			t.Logf("\twhen %s has been enabled", feature)
+			{
+				for _, enabled := range []bool{true, false} {
+					testConfig := map[string]any{
+						"mapTests.replicatedMap.enabled":       enabled,
+						"mapTests.replicatedMap.testLoop.type": "batch",
+					}
+					for k, v := range featureConfig {
+						testConfig[k] = v
+					}
+
+					rc, err := populateReplicatedMapConfig(testConfigPropertyAssigner{returnError: false, testConfig: testConfig})
+
+					if enabled {
+						msg := "\t\terror referring to runner's key path must be returned if runner is enabled"
+						if err != nil && rc == nil && strings.Contains(err.Error(), mapReplicatedMapRunnerKeyPath) {
+							t.Log(msg, checkMark)
+						} else {
+							t.Fatal(msg, ballotX, err)
+						}
+					} else {
+						msg := "\t\tno error must be returned if runner is disabled"
+						if err == nil && rc != nil {
+							t.Log(msg, checkMark)
+						} else {
+							t.Fatal(msg, ballotX, err)
+						}
+					}
+				}
+			}
+		}
+	}
+
+}
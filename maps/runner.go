@@ -7,10 +7,12 @@ import (
 	"hazeltest/api"
 	"hazeltest/client"
 	"hazeltest/hazelcastwrapper"
+	"hazeltest/loadsupport"
 	"hazeltest/logging"
 	"hazeltest/state"
 	"hazeltest/status"
 	"sync"
+	"time"
 )
 
 type (
@@ -23,11 +25,17 @@ type (
 		enabled                 bool
 		numMaps                 uint16
 		numRuns                 uint32
+		runDuration             time.Duration
 		mapBaseName             string
 		useMapPrefix            bool
 		mapPrefix               string
 		appendMapIndexToMapName bool
 		appendClientIdToMapName bool
+		verifyIntegrity         bool
+		storeJsonValues         bool
+		throughput              *loadsupport.ThroughputConfig
+		expiry                  *expiryConfig
+		entryListener           *entryListenerConfig
 		loopType                runnerLoopType
 		preRunClean             *preRunCleanConfig
 		sleepBetweenRuns        *sleepConfig
@@ -40,6 +48,24 @@ type (
 		applyCleanAgainThreshold bool
 		cleanAgainThresholdMs    uint64
 	}
+	// expiryConfig holds the TTL and max idle duration to write map entries with -- a duration of zero means
+	// entries never expire for the respective reason, which mirrors Hazelcast's semantics. The sample size caps the
+	// number of keys checked for late expiry after each run.
+	expiryConfig struct {
+		enabled    bool
+		ttl        time.Duration
+		maxIdle    time.Duration
+		verify     bool
+		tolerance  time.Duration
+		sampleSize int
+	}
+	// entryListenerConfig holds whether the test loop registers entry listeners on the maps it writes to, and for
+	// how long to wait for events still in flight after the test loop on a map has finished before reconciling
+	// received and expected events.
+	entryListenerConfig struct {
+		enabled     bool
+		gracePeriod time.Duration
+	}
 	sleepConfig struct {
 		enabled          bool
 		durationMs       int
@@ -402,6 +428,125 @@ func (b runnerConfigBuilder) populateConfig() (*runnerConfig, error) {
 		})
 	})
 
+	var useRunDuration bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".runDuration.enabled", client.ValidateBool, func(a any) {
+			useRunDuration = a.(bool)
+		})
+	})
+
+	var runDuration time.Duration
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".runDuration.duration", client.ValidateDuration, func(a any) {
+			runDuration, _ = time.ParseDuration(a.(string))
+		})
+	})
+
+	var verifyIntegrity bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".integrityVerification.enabled", client.ValidateBool, func(a any) {
+			verifyIntegrity = a.(bool)
+		})
+	})
+
+	var storeJsonValues bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".jsonValues.enabled", client.ValidateBool, func(a any) {
+			storeJsonValues = a.(bool)
+		})
+	})
+
+	var regulateThroughput bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".throughput.enabled", client.ValidateBool, func(a any) {
+			regulateThroughput = a.(bool)
+		})
+	})
+
+	var targetOpsPerSecond int
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".throughput.targetOpsPerSecond", client.ValidateInt, func(a any) {
+			targetOpsPerSecond = a.(int)
+		})
+	})
+
+	var throughputScope loadsupport.ThroughputScope
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".throughput.scope", loadsupport.ValidateThroughputScope, func(a any) {
+			throughputScope = loadsupport.ThroughputScope(a.(string))
+		})
+	})
+
+	var useExpiry bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".expiry.enabled", client.ValidateBool, func(a any) {
+			useExpiry = a.(bool)
+		})
+	})
+
+	var useTTL bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".expiry.ttl.enabled", client.ValidateBool, func(a any) {
+			useTTL = a.(bool)
+		})
+	})
+
+	var ttl time.Duration
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".expiry.ttl.duration", client.ValidateDuration, func(a any) {
+			ttl, _ = time.ParseDuration(a.(string))
+		})
+	})
+
+	var useMaxIdle bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".expiry.maxIdle.enabled", client.ValidateBool, func(a any) {
+			useMaxIdle = a.(bool)
+		})
+	})
+
+	var maxIdle time.Duration
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".expiry.maxIdle.duration", client.ValidateDuration, func(a any) {
+			maxIdle, _ = time.ParseDuration(a.(string))
+		})
+	})
+
+	var verifyExpiry bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".expiry.verification.enabled", client.ValidateBool, func(a any) {
+			verifyExpiry = a.(bool)
+		})
+	})
+
+	var expiryTolerance time.Duration
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".expiry.verification.tolerance", client.ValidateDuration, func(a any) {
+			expiryTolerance, _ = time.ParseDuration(a.(string))
+		})
+	})
+
+	var expiryVerificationSampleSize int
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".expiry.verification.sampleSize", client.ValidateInt, func(a any) {
+			expiryVerificationSampleSize = a.(int)
+		})
+	})
+
+	var verifyEntryEvents bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".entryListenerVerification.enabled", client.ValidateBool, func(a any) {
+			verifyEntryEvents = a.(bool)
+		})
+	})
+
+	var entryEventsGracePeriod time.Duration
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".entryListenerVerification.gracePeriod", client.ValidateDuration, func(a any) {
+			entryEventsGracePeriod, _ = time.ParseDuration(a.(string))
+		})
+	})
+
 	var numRuns uint32
 	assignmentOps = append(assignmentOps, func() error {
 		return b.assigner.Assign(b.runnerKeyPath+".numRuns", client.ValidateInt, func(a any) {
@@ -486,6 +631,28 @@ func (b runnerConfigBuilder) populateConfig() (*runnerConfig, error) {
 		}
 	}
 
+	if !useRunDuration {
+		runDuration = 0
+	}
+
+	if !useTTL {
+		ttl = 0
+	}
+	if !useMaxIdle {
+		maxIdle = 0
+	}
+	if useExpiry && ttl == 0 && maxIdle == 0 {
+		return nil, fmt.Errorf("expiry enabled for '%s', but neither ttl nor max idle has been enabled", b.runnerKeyPath)
+	}
+
+	loadProfile, err := loadsupport.LoadProfileConfigBuilder{Assigner: b.assigner, KeyPath: b.runnerKeyPath + ".loadProfile"}.PopulateConfig()
+	if err != nil {
+		return nil, err
+	}
+	if loadProfile.Enabled && !regulateThroughput {
+		return nil, fmt.Errorf("load profile enabled for '%s', but load profiles require throughput regulation to be enabled, too", b.runnerKeyPath)
+	}
+
 	var batchConfig *batchTestLoopConfig
 	if loopType == batch {
 		if bc, err := populateBatchTestLoopConfig(b); err != nil {
@@ -512,11 +679,32 @@ func (b runnerConfigBuilder) populateConfig() (*runnerConfig, error) {
 		enabled:                 enabled,
 		numMaps:                 numMaps,
 		numRuns:                 numRuns,
+		runDuration:             runDuration,
 		mapBaseName:             b.mapBaseName,
 		useMapPrefix:            useMapPrefix,
 		mapPrefix:               mapPrefix,
 		appendMapIndexToMapName: appendMapIndexToMapName,
 		appendClientIdToMapName: appendClientIdToMapName,
+		verifyIntegrity:         verifyIntegrity,
+		storeJsonValues:         storeJsonValues,
+		throughput: &loadsupport.ThroughputConfig{
+			Enabled:            regulateThroughput,
+			TargetOpsPerSecond: targetOpsPerSecond,
+			Scope:              throughputScope,
+			LoadProfile:        loadProfile,
+		},
+		expiry: &expiryConfig{
+			enabled:    useExpiry,
+			ttl:        ttl,
+			maxIdle:    maxIdle,
+			verify:     useExpiry && verifyExpiry,
+			tolerance:  expiryTolerance,
+			sampleSize: expiryVerificationSampleSize,
+		},
+		entryListener: &entryListenerConfig{
+			enabled:     verifyEntryEvents,
+			gracePeriod: entryEventsGracePeriod,
+		},
 		sleepBetweenRuns: &sleepConfig{
 			sleepBetweenRunsEnabled,
 			sleepBetweenRunsDurationMs,
@@ -5,6 +5,7 @@ import (
 	"hazeltest/hazelcastwrapper"
 	"strings"
 	"testing"
+	"time"
 )
 
 var (
@@ -14,6 +15,27 @@ var (
 		testMapRunnerKeyPath + ".appendMapIndexToMapName":                                  true,
 		testMapRunnerKeyPath + ".appendClientIdToMapName":                                  false,
 		testMapRunnerKeyPath + ".numRuns":                                                  1_000,
+		testMapRunnerKeyPath + ".runDuration.enabled":                                      true,
+		testMapRunnerKeyPath + ".runDuration.duration":                                     "6h",
+		testMapRunnerKeyPath + ".integrityVerification.enabled":                            true,
+		testMapRunnerKeyPath + ".jsonValues.enabled":                                       true,
+		testMapRunnerKeyPath + ".expiry.enabled":                                           true,
+		testMapRunnerKeyPath + ".expiry.ttl.enabled":                                       true,
+		testMapRunnerKeyPath + ".expiry.ttl.duration":                                      "60s",
+		testMapRunnerKeyPath + ".expiry.maxIdle.enabled":                                   true,
+		testMapRunnerKeyPath + ".expiry.maxIdle.duration":                                  "30s",
+		testMapRunnerKeyPath + ".expiry.verification.enabled":                              true,
+		testMapRunnerKeyPath + ".expiry.verification.tolerance":                            "5s",
+		testMapRunnerKeyPath + ".expiry.verification.sampleSize":                           50,
+		testMapRunnerKeyPath + ".entryListenerVerification.enabled":                        true,
+		testMapRunnerKeyPath + ".entryListenerVerification.gracePeriod":                    "10s",
+		testMapRunnerKeyPath + ".throughput.enabled":                                       true,
+		testMapRunnerKeyPath + ".throughput.targetOpsPerSecond":                            500,
+		testMapRunnerKeyPath + ".throughput.scope":                                         "goroutine",
+		testMapRunnerKeyPath + ".loadProfile.enabled":                                      true,
+		testMapRunnerKeyPath + ".loadProfile.type":                                         "ramp",
+		testMapRunnerKeyPath + ".loadProfile.ramp.startOpsPerSecond":                       50,
+		testMapRunnerKeyPath + ".loadProfile.ramp.duration":                                "10m",
 		testMapRunnerKeyPath + ".numEntriesPerMap":                                         2_000_000,
 		testMapRunnerKeyPath + ".payload.fixedSize.enabled":                                false,
 		testMapRunnerKeyPath + ".payload.fixedSize.sizeBytes":                              10_000,
@@ -190,6 +212,101 @@ func TestPopulateConfig(t *testing.T) {
 			}
 		}
 
+		t.Log("\twhen run duration is disabled")
+		{
+			testConfig := assembleTestConfigForTestLoopType(batch)
+			testConfig[testMapRunnerKeyPath+".runDuration.enabled"] = false
+			b.assigner = testConfigPropertyAssigner{false, testConfig}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\trun duration must be zero"
+			if rc.runDuration == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, rc.runDuration)
+			}
+		}
+
+		t.Log("\twhen run duration cannot be parsed")
+		{
+			testConfig := assembleTestConfigForTestLoopType(batch)
+			testConfig[testMapRunnerKeyPath+".runDuration.duration"] = "6 hours"
+			b.assigner = testConfigPropertyAssigner{false, testConfig}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror must be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen expiry is enabled, but neither ttl nor max idle is")
+		{
+			testConfig := assembleTestConfigForTestLoopType(batch)
+			testConfig[testMapRunnerKeyPath+".expiry.ttl.enabled"] = false
+			testConfig[testMapRunnerKeyPath+".expiry.maxIdle.enabled"] = false
+			b.assigner = testConfigPropertyAssigner{false, testConfig}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror must be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen expiry is disabled")
+		{
+			testConfig := assembleTestConfigForTestLoopType(batch)
+			testConfig[testMapRunnerKeyPath+".expiry.enabled"] = false
+			b.assigner = testConfigPropertyAssigner{false, testConfig}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\texpiry verification must be disabled, too"
+			if !rc.expiry.verify {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen load profile is enabled, but throughput regulation is not")
+		{
+			testConfig := assembleTestConfigForTestLoopType(batch)
+			testConfig[testMapRunnerKeyPath+".throughput.enabled"] = false
+			b.assigner = testConfigPropertyAssigner{false, testConfig}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror must be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
 		msgTemplate := "\twhen value for upper map fill boundary is %s value for lower map fill boundary"
 		for _, s := range []string{"less than", "equal to"} {
 			t.Log(fmt.Sprintf(msgTemplate, s))
@@ -272,6 +389,96 @@ func configValuesAsExpected(rc *runnerConfig, expected map[string]any) (bool, st
 		return false, keyPath
 	}
 
+	keyPath = testMapRunnerKeyPath + ".runDuration.duration"
+	if expectedRunDuration, _ := time.ParseDuration(expected[keyPath].(string)); rc.runDuration != expectedRunDuration {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".integrityVerification.enabled"
+	if rc.verifyIntegrity != expected[keyPath] {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".jsonValues.enabled"
+	if rc.storeJsonValues != expected[keyPath] {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".expiry.enabled"
+	if rc.expiry.enabled != expected[keyPath] {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".expiry.ttl.duration"
+	if expectedTTL, _ := time.ParseDuration(expected[keyPath].(string)); rc.expiry.ttl != expectedTTL {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".expiry.maxIdle.duration"
+	if expectedMaxIdle, _ := time.ParseDuration(expected[keyPath].(string)); rc.expiry.maxIdle != expectedMaxIdle {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".expiry.verification.enabled"
+	if rc.expiry.verify != expected[keyPath] {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".expiry.verification.tolerance"
+	if expectedTolerance, _ := time.ParseDuration(expected[keyPath].(string)); rc.expiry.tolerance != expectedTolerance {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".expiry.verification.sampleSize"
+	if rc.expiry.sampleSize != expected[keyPath] {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".entryListenerVerification.enabled"
+	if rc.entryListener.enabled != expected[keyPath] {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".entryListenerVerification.gracePeriod"
+	if expectedGracePeriod, _ := time.ParseDuration(expected[keyPath].(string)); rc.entryListener.gracePeriod != expectedGracePeriod {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".throughput.enabled"
+	if rc.throughput.Enabled != expected[keyPath] {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".throughput.targetOpsPerSecond"
+	if rc.throughput.TargetOpsPerSecond != expected[keyPath] {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".throughput.scope"
+	if string(rc.throughput.Scope) != expected[keyPath] {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".loadProfile.enabled"
+	if rc.throughput.LoadProfile.Enabled != expected[keyPath] {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".loadProfile.type"
+	if string(rc.throughput.LoadProfile.Type) != expected[keyPath] {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".loadProfile.ramp.startOpsPerSecond"
+	if rc.throughput.LoadProfile.Ramp.StartOpsPerSecond != expected[keyPath] {
+		return false, keyPath
+	}
+
+	keyPath = testMapRunnerKeyPath + ".loadProfile.ramp.duration"
+	if expectedRampDuration, _ := time.ParseDuration(expected[keyPath].(string)); rc.throughput.LoadProfile.Ramp.Duration != expectedRampDuration {
+		return false, keyPath
+	}
+
 	keyPath = testMapRunnerKeyPath + ".sleeps.betweenRuns.enabled"
 	if rc.sleepBetweenRuns.enabled != expected[keyPath] {
 		return false, keyPath
@@ -2,11 +2,14 @@ package maps
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/hazelcast/hazelcast-go-client/predicate"
+	"github.com/hazelcast/hazelcast-go-client/serialization"
 	"hazeltest/client"
 	"hazeltest/hazelcastwrapper"
+	"hazeltest/loadsupport"
 	"hazeltest/state"
 	"hazeltest/status"
 	"math"
@@ -27,11 +30,16 @@ type (
 		run()
 	}
 	counterTracker interface {
-		init(gatherer *status.Gatherer)
+		init(gatherer *status.Gatherer, optionalCounters ...statusKey)
 		increaseCounter(sk statusKey)
 	}
+	latencyTracker interface {
+		init(gatherer *status.Gatherer)
+		recordLatency(o mapOperation, d time.Duration)
+		publish()
+	}
 	sleeper interface {
-		sleep(sc *sleepConfig, sf evaluateTimeToSleep, runnerName string)
+		sleep(ctx context.Context, sc *sleepConfig, sf evaluateTimeToSleep, runnerName string)
 	}
 	defaultSleeper struct{}
 )
@@ -41,6 +49,11 @@ type (
 		tle      *testLoopExecution[t]
 		gatherer *status.Gatherer
 		ct       counterTracker
+		lt       latencyTracker
+		iv       integrityVerifier
+		ev       expiryVerifier
+		lv       entryEventVerifier
+		tr       *loadsupport.ThroughputRegulator
 		s        sleeper
 	}
 	modeCache struct {
@@ -57,6 +70,11 @@ type (
 		gatherer *status.Gatherer
 		s        sleeper
 		ct       counterTracker
+		lt       latencyTracker
+		iv       integrityVerifier
+		ev       expiryVerifier
+		lv       entryEventVerifier
+		tr       *loadsupport.ThroughputRegulator
 	}
 	testLoopExecution[t any] struct {
 		id                   uuid.UUID
@@ -68,19 +86,35 @@ type (
 		runnerConfig         *runnerConfig
 		elements             []t
 		ctx                  context.Context
+		runCtx               context.Context
 		getElementID         getElementIdFunc
 		getOrAssemblePayload getOrAssemblePayloadFunc
 	}
 	mapTestLoopCountersTracker struct {
-		counters map[statusKey]uint64
-		l        sync.Mutex
-		gatherer *status.Gatherer
+		// Counters to initialize in addition to the optional ones -- defaults to the counters of the map test loops
+		baseCounters []statusKey
+		counters     map[statusKey]uint64
+		l            sync.Mutex
+		gatherer     *status.Gatherer
+	}
+	// mapTestLoopLatencyTracker keeps one histogram per Hazelcast map operation for all map goroutines of a test
+	// loop. Recording a latency is cheap, so, in contrast to the counters tracker, the histograms are not
+	// published to the status gatherer upon each recording, but at most once per latencyPublishInterval
+	// (and once more when the test loop has finished).
+	mapTestLoopLatencyTracker struct {
+		// Operations to keep histograms for -- defaults to the map operations performed by the test loops
+		trackedOperations []mapOperation
+		histograms        map[mapOperation]*status.Histogram
+		lastPublished     time.Time
+		l                 sync.Mutex
+		gatherer          *status.Gatherer
 	}
 )
 
 type (
-	actionMode string
-	mapAction  string
+	actionMode   string
+	mapAction    string
+	mapOperation string
 )
 
 const (
@@ -103,12 +137,42 @@ const (
 	noop mapAction = "noop"
 )
 
+const (
+	opSet                  mapOperation = "set"
+	opGet                  mapOperation = "get"
+	opRemove               mapOperation = "remove"
+	opContainsKey          mapOperation = "containsKey"
+	statusKeyLatencies     statusKey    = "latencies"
+	latencyPublishInterval              = 1 * time.Second
+)
+
+// Only reported if runner has been configured with run duration
+const (
+	statusKeyRemainingRunDurationSeconds statusKey = "remainingRunDurationSeconds"
+	remainingRunDurationUpdateInterval             = 5 * time.Second
+)
+
 const (
 	statusKeyNumFailedInserts   statusKey = "numFailedInserts"
 	statusKeyNumFailedReads     statusKey = "numFailedReads"
 	statusKeyNumNilReads        statusKey = "numNilReads"
 	statusKeyNumFailedRemoves   statusKey = "numFailedRemoves"
 	statusKeyNumFailedKeyChecks statusKey = "numFailedKeyChecks"
+	statusKeyNumCorruptedReads  statusKey = "numCorruptedReads"
+	statusKeyNumStaleReads      statusKey = "numStaleReads"
+)
+
+// Only reported if runner has been configured to verify expiry
+const (
+	statusKeyNumPrematureExpirations statusKey = "numPrematureExpirations"
+	statusKeyNumLateExpirations      statusKey = "numLateExpirations"
+)
+
+// Only reported if runner has been configured to verify entry events
+const (
+	statusKeyNumMissedEntryEvents           statusKey = "numMissedEntryEvents"
+	statusKeyNumUnexpectedEntryEvents       statusKey = "numUnexpectedEntryEvents"
+	statusKeyNumFailedListenerRegistrations statusKey = "numFailedListenerRegistrations"
 )
 
 var (
@@ -121,16 +185,27 @@ var (
 		}
 		return sleepDuration
 	}
-	counters = []statusKey{statusKeyNumFailedInserts, statusKeyNumFailedReads, statusKeyNumNilReads, statusKeyNumFailedRemoves, statusKeyNumFailedKeyChecks}
+	counters   = []statusKey{statusKeyNumFailedInserts, statusKeyNumFailedReads, statusKeyNumNilReads, statusKeyNumFailedRemoves, statusKeyNumFailedKeyChecks}
+	operations = []mapOperation{opSet, opGet, opRemove, opContainsKey}
+	// Counters only relevant when the corresponding feature has been enabled -- initialized (and hence reported)
+	// only in that case
+	integrityCounters     = []statusKey{statusKeyNumCorruptedReads, statusKeyNumStaleReads}
+	expiryCounters        = []statusKey{statusKeyNumPrematureExpirations, statusKeyNumLateExpirations}
+	entryListenerCounters = []statusKey{statusKeyNumMissedEntryEvents, statusKeyNumUnexpectedEntryEvents, statusKeyNumFailedListenerRegistrations}
 )
 
-func (ct *mapTestLoopCountersTracker) init(gatherer *status.Gatherer) {
+func (ct *mapTestLoopCountersTracker) init(gatherer *status.Gatherer, optionalCounters ...statusKey) {
 	ct.gatherer = gatherer
 
 	ct.counters = make(map[statusKey]uint64)
 
+	baseCounters := ct.baseCounters
+	if baseCounters == nil {
+		baseCounters = counters
+	}
+
 	initialCounterValue := uint64(0)
-	for _, v := range counters {
+	for _, v := range append(baseCounters, optionalCounters...) {
 		ct.counters[v] = initialCounterValue
 		gatherer.Updates <- status.Update{Key: string(v), Value: initialCounterValue}
 	}
@@ -150,15 +225,94 @@ func (ct *mapTestLoopCountersTracker) increaseCounter(sk statusKey) {
 
 }
 
+func assembleOptionalCounters(rc *runnerConfig) []statusKey {
+
+	var result []statusKey
+	if rc.verifyIntegrity {
+		result = append(result, integrityCounters...)
+	}
+	if rc.expiry.verify {
+		result = append(result, expiryCounters...)
+	}
+	if rc.entryListener.enabled {
+		result = append(result, entryListenerCounters...)
+	}
+
+	return result
+
+}
+
+func (lt *mapTestLoopLatencyTracker) init(gatherer *status.Gatherer) {
+	lt.gatherer = gatherer
+
+	if lt.trackedOperations == nil {
+		lt.trackedOperations = operations
+	}
+
+	lt.histograms = make(map[mapOperation]*status.Histogram)
+	for _, v := range lt.trackedOperations {
+		lt.histograms[v] = status.NewHistogram()
+	}
+
+	lt.publish()
+}
+
+func (lt *mapTestLoopLatencyTracker) recordLatency(o mapOperation, d time.Duration) {
+
+	lt.histograms[o].Record(d)
+
+	var publishDue bool
+	lt.l.Lock()
+	{
+		publishDue = time.Since(lt.lastPublished) >= latencyPublishInterval
+	}
+	lt.l.Unlock()
+
+	if publishDue {
+		lt.publish()
+	}
+
+}
+
+func (lt *mapTestLoopLatencyTracker) publish() {
+
+	snapshots := make(map[string]status.HistogramSnapshot, len(lt.histograms))
+	for k, v := range lt.histograms {
+		snapshots[string(k)] = v.Snapshot()
+	}
+
+	lt.l.Lock()
+	{
+		lt.lastPublished = time.Now()
+	}
+	lt.l.Unlock()
+
+	lt.gatherer.Updates <- status.Update{Key: string(statusKeyLatencies), Value: snapshots}
+
+}
+
 func (l *boundaryTestLoop[t]) init(tle *testLoopExecution[t], s sleeper, gatherer *status.Gatherer) {
 	l.tle = tle
 	l.s = s
 	l.gatherer = gatherer
 
 	ct := &mapTestLoopCountersTracker{}
-	ct.init(gatherer)
+	ct.init(gatherer, assembleOptionalCounters(tle.runnerConfig)...)
 
 	l.ct = ct
+
+	lt := &mapTestLoopLatencyTracker{}
+	lt.init(gatherer)
+
+	l.lt = lt
+
+	l.iv = newMapTestLoopIntegrityVerifier()
+
+	l.ev = newMapTestLoopExpiryVerifier(tle.runnerConfig.expiry)
+
+	l.lv = newMapTestLoopEntryEventVerifier(gatherer)
+
+	l.tr = loadsupport.NewThroughputRegulator(tle.runnerConfig.throughput, int(tle.runnerConfig.numMaps), gatherer)
 }
 
 func (l *boundaryTestLoop[t]) run() {
@@ -167,9 +321,12 @@ func (l *boundaryTestLoop[t]) run() {
 		l.tle,
 		l.gatherer,
 		assembleMapName,
-		l.runForMap,
+		withEntryEventVerification(l.tle, l.lv, l.ct, l.runForMap),
 	)
 
+	l.lt.publish()
+	l.tr.Publish()
+
 }
 
 func (l *boundaryTestLoop[t]) chooseNextMapElement(action mapAction, elementsInserted, elementsAvailableForInsertion map[string]t) (t, error) {
@@ -219,13 +376,14 @@ func (l *boundaryTestLoop[t]) runForMap(m hazelcastwrapper.Map, mapName string,
 	ac := &actionCache{}
 	elementsInserted := make(map[string]t)
 	elementsAvailableForInsertion := l.populateElementsAvailableForInsertion(mapName, mapNumber)
+	var pendingExpiryVerifications sync.WaitGroup
 
-	for i := uint32(0); i < l.tle.runnerConfig.numRuns; i++ {
+	for i := uint32(0); runsRemaining(l.tle, i); i++ {
 
-		l.s.sleep(sleepBetweenRunsConfig, sleepTimeFunc, l.tle.runnerName)
+		l.s.sleep(sleepContext(l.tle), sleepBetweenRunsConfig, sleepTimeFunc, l.tle.runnerName)
 
 		if i > 0 && i%updateStep == 0 {
-			lp.LogMapRunnerEvent(fmt.Sprintf("finished %d of %d runs for map %s in map goroutine %d", i, l.tle.runnerConfig.numRuns, mapName, mapNumber), l.tle.runnerName, log.InfoLevel)
+			lp.LogMapRunnerEvent(fmt.Sprintf("finished %d of %s runs for map %s in map goroutine %d", i, describeNumRuns(l.tle), mapName, mapNumber), l.tle.runnerName, log.InfoLevel)
 		}
 
 		if err := l.runOperationChain(i, m, mc, ac, mapName, mapNumber, elementsInserted, elementsAvailableForInsertion); err != nil {
@@ -234,6 +392,14 @@ func (l *boundaryTestLoop[t]) runForMap(m hazelcastwrapper.Map, mapName string,
 			lp.LogMapRunnerEvent(fmt.Sprintf("successfully finished operation chain for map '%s' in goroutine %d in map run %d", mapName, mapNumber, i), l.tle.runnerName, log.InfoLevel)
 		}
 
+		if l.tle.runnerConfig.expiry.verify {
+			keys := make([]string, 0, len(elementsInserted))
+			for k := range elementsInserted {
+				keys = append(keys, k)
+			}
+			verifyExpiryInBackground(l.tle, l.ev, l.ct, l.lt, l.tr, m, mapName, mapNumber, keys, &pendingExpiryVerifications)
+		}
+
 		if l.tle.runnerConfig.boundary.resetAfterChain {
 			lp.LogMapRunnerEvent(fmt.Sprintf("performing reset after operation chain on map '%s' in goroutine %d in map run %d", mapName, mapNumber, i), l.tle.runnerName, log.InfoLevel)
 			l.resetAfterOperationChain(m, mapName, mapNumber, &elementsInserted, &elementsAvailableForInsertion, mc, ac)
@@ -241,6 +407,8 @@ func (l *boundaryTestLoop[t]) runForMap(m hazelcastwrapper.Map, mapName string,
 
 	}
 
+	pendingExpiryVerifications.Wait()
+
 	lp.LogMapRunnerEvent(fmt.Sprintf("map test loop done on map '%s' in map goroutine %d", mapName, mapNumber), l.tle.runnerName, log.InfoLevel)
 
 }
@@ -256,8 +424,29 @@ func (l *boundaryTestLoop[t]) resetAfterOperationChain(m hazelcastwrapper.Map, m
 	lp.LogMapRunnerEvent(fmt.Sprintf("removing all keys from map '%s' in goroutine %d having match for predicate '%s'", mapName, mapNumber, p), l.tle.runnerName, log.TraceLevel)
 	err := m.RemoveAll(l.tle.ctx, p)
 	if err != nil {
+		if l.lv != nil && l.tle.runnerConfig.entryListener.enabled {
+			// Some of the keys might have been removed nonetheless
+			for k := range *elementsInserted {
+				l.lv.tolerate(k, entryRemoved)
+			}
+		}
 		lp.LogHzEvent(fmt.Sprintf("won't update local cache because removing all keys from map '%s' in goroutine %d having match for predicate '%s' failed due to error: '%s'", mapName, mapNumber, p, err.Error()), log.WarnLevel)
 	} else {
+		if l.ev != nil {
+			for k := range *elementsInserted {
+				l.ev.confirmRemove(k)
+			}
+		}
+		if l.lv != nil && l.tle.runnerConfig.entryListener.enabled {
+			for k := range *elementsInserted {
+				// With expiry enabled, some of the keys might already be gone, so no removal event can be relied upon
+				if l.tle.runnerConfig.expiry.enabled {
+					l.lv.tolerate(k, entryRemoved)
+				} else {
+					l.lv.expect(k, entryRemoved)
+				}
+			}
+		}
 		*elementsInserted = make(map[string]t)
 		*elementsAvailableForInsertion = l.populateElementsAvailableForInsertion(mapName, mapNumber)
 	}
@@ -298,7 +487,7 @@ func (l *boundaryTestLoop[t]) runOperationChain(
 	chainLength := l.tle.runnerConfig.boundary.chainLength
 	lp.LogMapRunnerEvent(fmt.Sprintf("starting operation chain of length %d for map '%s' on goroutine %d", chainLength, mapName, mapNumber), l.tle.runnerName, log.InfoLevel)
 
-	l.s.sleep(l.tle.runnerConfig.boundary.sleepBetweenOperationChains, sleepTimeFunc, l.tle.runnerName)
+	l.s.sleep(sleepContext(l.tle), l.tle.runnerConfig.boundary.sleepBetweenOperationChains, sleepTimeFunc, l.tle.runnerName)
 
 	upperBoundary, lowerBoundary := evaluateMapFillBoundaries(l.tle.runnerConfig.boundary)
 	actionProbability := l.tle.runnerConfig.boundary.actionTowardsBoundaryProbability
@@ -307,6 +496,11 @@ func (l *boundaryTestLoop[t]) runOperationChain(
 
 	for j := 0; j < chainLength; j++ {
 
+		if runDeadlineReached(l.tle) {
+			lp.LogMapRunnerEvent(fmt.Sprintf("run duration elapsed -- aborting operation chain for map '%s' on goroutine %d in chain position %d", mapName, mapNumber, j), l.tle.runnerName, log.InfoLevel)
+			return nil
+		}
+
 		if (actions.last == insert || actions.last == remove) && j > 0 && uint32(j)%updateStep == 0 {
 			lp.LogMapRunnerEvent(fmt.Sprintf("chain position %d of %d for map '%s' on goroutine %d", j, chainLength, mapName, mapNumber), l.tle.runnerName, log.InfoLevel)
 		}
@@ -314,7 +508,7 @@ func (l *boundaryTestLoop[t]) runOperationChain(
 		nextMode, forceActionTowardsMode := l.checkForModeChange(upperBoundary, lowerBoundary, uint32(len(elementsInserted)), modes.current)
 		if nextMode != modes.current && modes.current != "" {
 			lp.LogMapRunnerEvent(fmt.Sprintf("detected mode change from '%s' to '%s' for map '%s' in chain position %d with %d map items currently under management", modes.current, nextMode, mapName, j, len(elementsInserted)), l.tle.runnerName, log.InfoLevel)
-			l.s.sleep(l.tle.runnerConfig.boundary.sleepUponModeChange, sleepTimeFunc, l.tle.runnerName)
+			l.s.sleep(sleepContext(l.tle), l.tle.runnerConfig.boundary.sleepUponModeChange, sleepTimeFunc, l.tle.runnerName)
 		}
 		modes.current, modes.forceActionTowardsMode = nextMode, forceActionTowardsMode
 
@@ -349,7 +543,13 @@ func (l *boundaryTestLoop[t]) runOperationChain(
 		actions.last = actions.next
 		actions.next = ""
 
-		if err != nil {
+		if errors.Is(err, errEntryExpired) {
+			// Entry is gone for good, so stop treating it as inserted -- otherwise, subsequent reads and removes
+			// would keep choosing it
+			lp.LogMapRunnerEvent(fmt.Sprintf("entry read from map '%s' has expired -- dropping it from local cache: %v", mapName, err), l.tle.runnerName, log.InfoLevel)
+			key := assembleMapKey(mapName, mapNumber, l.tle.getElementID(nextMapElement))
+			l.updateKeysCache(nextMapElement, remove, elementsInserted, elementsAvailableForInsertion, key, l.tle.runnerName)
+		} else if err != nil {
 			lp.LogMapRunnerEvent(fmt.Sprintf("encountered error upon execution of '%s' action on map '%s' in run '%d' (still moving to next loop iteration): %v", actions.last, mapName, currentRun, err), l.tle.runnerName, log.WarnLevel)
 		} else {
 			lp.LogMapRunnerEvent(fmt.Sprintf("action '%s' successfully executed on map '%s', moving to next action in upcoming loop iteration", actions.last, mapName), l.tle.runnerName, log.TraceLevel)
@@ -357,7 +557,7 @@ func (l *boundaryTestLoop[t]) runOperationChain(
 			l.updateKeysCache(nextMapElement, actions.last, elementsInserted, elementsAvailableForInsertion, key, l.tle.runnerName)
 		}
 
-		l.s.sleep(l.tle.runnerConfig.boundary.sleepAfterChainAction, sleepTimeFunc, l.tle.runnerName)
+		l.s.sleep(sleepContext(l.tle), l.tle.runnerConfig.boundary.sleepAfterChainAction, sleepTimeFunc, l.tle.runnerName)
 
 	}
 
@@ -407,32 +607,92 @@ func (l *boundaryTestLoop[t]) executeMapAction(m hazelcastwrapper.Map, mapName s
 			lp.LogMapRunnerEvent(fmt.Sprintf("unable to execute insert operation for map '%s' due to error upon generating payload: %v", mapName, err), l.tle.runnerName, log.ErrorLevel)
 			return err
 		}
-		if err := m.Set(l.tle.ctx, key, payload); err != nil {
+		var vp verifiablePayload
+		if l.tle.runnerConfig.verifyIntegrity {
+			if vp, err = l.iv.wrap(payload); err != nil {
+				lp.LogMapRunnerEvent(fmt.Sprintf("unable to execute insert operation for map '%s' due to error upon preparing payload for integrity verification: %v", mapName, err), l.tle.runnerName, log.ErrorLevel)
+				return err
+			}
+			payload = vp
+		}
+		l.tr.Await(l.tle.ctx, int(mapNumber))
+		start := time.Now()
+		err = setEntry(l.tle, m, key, payload)
+		l.lt.recordLatency(opSet, time.Since(start))
+		if err != nil {
 			l.ct.increaseCounter(statusKeyNumFailedInserts)
+			if l.tle.runnerConfig.entryListener.enabled {
+				l.lv.tolerate(key, entryWritten)
+			}
 			lp.LogHzEvent(fmt.Sprintf("failed to insert key '%s' into map '%s'", key, mapName), log.WarnLevel)
 			return err
 		} else {
+			if l.tle.runnerConfig.verifyIntegrity {
+				l.iv.confirmWrite(key, vp)
+			}
+			if l.tle.runnerConfig.expiry.verify {
+				l.ev.confirmWrite(key)
+			}
+			if l.tle.runnerConfig.entryListener.enabled {
+				l.lv.expect(key, entryWritten)
+			}
 			lp.LogHzEvent(fmt.Sprintf("successfully inserted key '%s' into map '%s'", key, mapName), log.TraceLevel)
 			return nil
 		}
 	case remove:
-		if _, err := m.Remove(l.tle.ctx, key); err != nil {
+		l.tr.Await(l.tle.ctx, int(mapNumber))
+		start := time.Now()
+		v, err := m.Remove(l.tle.ctx, key)
+		l.lt.recordLatency(opRemove, time.Since(start))
+		if err != nil {
 			l.ct.increaseCounter(statusKeyNumFailedRemoves)
+			if l.tle.runnerConfig.entryListener.enabled {
+				l.lv.tolerate(key, entryRemoved)
+			}
 			lp.LogHzEvent(fmt.Sprintf("failed to remove key '%s' from map '%s'", key, mapName), log.WarnLevel)
 			return err
 		} else {
+			if l.tle.runnerConfig.verifyIntegrity {
+				l.iv.confirmRemove(key)
+			}
+			if l.tle.runnerConfig.expiry.verify {
+				l.ev.confirmRemove(key)
+			}
+			// Removing a key no longer present (e.g. because it has expired in the meantime) doesn't cause an event
+			if l.tle.runnerConfig.entryListener.enabled && v != nil {
+				l.lv.expect(key, entryRemoved)
+			}
 			lp.LogHzEvent(fmt.Sprintf("successfully removed key '%s' from map '%s'", key, mapName), log.TraceLevel)
 			return nil
 		}
 	case read:
-		if v, err := m.Get(l.tle.ctx, key); err != nil {
+		l.tr.Await(l.tle.ctx, int(mapNumber))
+		start := time.Now()
+		v, err := m.Get(l.tle.ctx, key)
+		l.lt.recordLatency(opGet, time.Since(start))
+		if err != nil {
 			l.ct.increaseCounter(statusKeyNumFailedReads)
 			lp.LogHzEvent(fmt.Sprintf("read for key '%s' failed for map '%s'", key, mapName), log.WarnLevel)
 			return err
+		}
+		if l.tle.runnerConfig.expiry.verify {
+			if err := evaluateExpiry(l.ev, l.ct, key, v != nil); err != nil {
+				lp.LogHzEvent(fmt.Sprintf("expiry verification failed for map '%s': %v", mapName, err), log.WarnLevel)
+				return err
+			}
+		}
+		if v == nil && l.tle.runnerConfig.expiry.enabled {
+			return fmt.Errorf("%w: read for key '%s' successful for map '%s'", errEntryExpired, key, mapName)
 		} else if v == nil {
 			l.ct.increaseCounter(statusKeyNumNilReads)
 			return fmt.Errorf("read for key '%s' successful for map '%s', but associated value was nil", key, mapName)
 		} else {
+			if l.tle.runnerConfig.verifyIntegrity {
+				if err := evaluateReadIntegrity(l.iv, l.ct, key, v); err != nil {
+					lp.LogHzEvent(fmt.Sprintf("integrity verification failed for map '%s': %v", mapName, err), log.WarnLevel)
+					return err
+				}
+			}
 			lp.LogHzEvent(fmt.Sprintf("successfully read key '%s' in map '%s'", key, mapName), log.TraceLevel)
 			return nil
 		}
@@ -520,9 +780,22 @@ func (l *batchTestLoop[t]) init(tle *testLoopExecution[t], s sleeper, gatherer *
 	l.gatherer = gatherer
 
 	ct := &mapTestLoopCountersTracker{}
-	ct.init(gatherer)
+	ct.init(gatherer, assembleOptionalCounters(tle.runnerConfig)...)
 
 	l.ct = ct
+
+	lt := &mapTestLoopLatencyTracker{}
+	lt.init(gatherer)
+
+	l.lt = lt
+
+	l.iv = newMapTestLoopIntegrityVerifier()
+
+	l.ev = newMapTestLoopExpiryVerifier(tle.runnerConfig.expiry)
+
+	l.lv = newMapTestLoopEntryEventVerifier(gatherer)
+
+	l.tr = loadsupport.NewThroughputRegulator(tle.runnerConfig.throughput, int(tle.runnerConfig.numMaps), gatherer)
 }
 
 func runWrapper[t any](tle *testLoopExecution[t],
@@ -533,6 +806,26 @@ func runWrapper[t any](tle *testLoopExecution[t],
 	rc := tle.runnerConfig
 	insertInitialTestLoopStatus(gatherer.Updates, rc.numMaps, rc.numRuns)
 
+	if rc.runDuration > 0 {
+		lp.LogMapRunnerEvent(fmt.Sprintf("test loop will run for %s rather than for configured number of runs", rc.runDuration), tle.runnerName, log.InfoLevel)
+		// Run context only governs when the test loop stops -- operations on Hazelcast still use the test loop
+		// execution's main context, so operations in progress upon expiry of the deadline don't fail
+		runCtx, cancel := context.WithTimeout(tle.ctx, rc.runDuration)
+		tle.runCtx = runCtx
+		var reporterWg sync.WaitGroup
+		reporterWg.Add(1)
+		go func() {
+			defer reporterWg.Done()
+			reportRemainingRunDuration(runCtx, gatherer, remainingRunDurationUpdateInterval)
+		}()
+		// Reporter must have returned before this function does, otherwise it might attempt to send updates
+		// to a gatherer that has already stopped listening
+		defer func() {
+			cancel()
+			reporterWg.Wait()
+		}()
+	}
+
 	var stateCleaner state.SingleCleaner
 	var hzService string
 	if tle.runnerConfig.preRunClean.enabled {
@@ -597,15 +890,92 @@ func runWrapper[t any](tle *testLoopExecution[t],
 
 }
 
+func reportRemainingRunDuration(runCtx context.Context, gatherer *status.Gatherer, updateInterval time.Duration) {
+
+	deadline, _ := runCtx.Deadline()
+
+	ticker := time.NewTicker(updateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-runCtx.Done():
+			gatherer.Updates <- status.Update{Key: string(statusKeyRemainingRunDurationSeconds), Value: uint64(0)}
+			return
+		default:
+			gatherer.Updates <- status.Update{Key: string(statusKeyRemainingRunDurationSeconds), Value: remainingSeconds(deadline)}
+		}
+		select {
+		case <-runCtx.Done():
+		case <-ticker.C:
+		}
+	}
+
+}
+
+func remainingSeconds(deadline time.Time) uint64 {
+
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return 0
+	}
+
+	return uint64(math.Ceil(remaining.Seconds()))
+
+}
+
+// runsRemaining tells whether the test loop should start another run. If the runner has been configured with a run
+// duration, this is the case as long as the run duration hasn't elapsed, and the number of runs is ignored.
+func runsRemaining[t any](tle *testLoopExecution[t], currentRun uint32) bool {
+
+	if tle.runCtx != nil {
+		return tle.runCtx.Err() == nil
+	}
+
+	return currentRun < tle.runnerConfig.numRuns
+
+}
+
+func describeNumRuns[t any](tle *testLoopExecution[t]) string {
+
+	if tle.runCtx != nil {
+		return fmt.Sprintf("%s worth of", tle.runnerConfig.runDuration)
+	}
+
+	return fmt.Sprintf("%d", tle.runnerConfig.numRuns)
+
+}
+
+// sleepContext provides the context sleeps are cut short by, which is the run's context if the runner has been
+// configured with a run duration.
+func sleepContext[t any](tle *testLoopExecution[t]) context.Context {
+
+	if tle.runCtx != nil {
+		return tle.runCtx
+	}
+
+	return context.Background()
+
+}
+
+func runDeadlineReached[t any](tle *testLoopExecution[t]) bool {
+
+	return tle.runCtx != nil && tle.runCtx.Err() != nil
+
+}
+
 func (l *batchTestLoop[t]) run() {
 
 	runWrapper(
 		l.tle,
 		l.gatherer,
 		assembleMapName,
-		l.runForMap,
+		withEntryEventVerification(l.tle, l.lv, l.ct, l.runForMap),
 	)
 
+	l.lt.publish()
+	l.tr.Publish()
+
 }
 
 func insertInitialTestLoopStatus(c chan status.Update, numMaps uint16, numRuns uint32) {
@@ -620,11 +990,12 @@ func (l *batchTestLoop[t]) runForMap(m hazelcastwrapper.Map, mapName string, map
 
 	sleepBetweenActionBatchesConfig := l.tle.runnerConfig.batch.sleepBetweenActionBatches
 	sleepBetweenRunsConfig := l.tle.runnerConfig.sleepBetweenRuns
+	var pendingExpiryVerifications sync.WaitGroup
 
-	for i := uint32(0); i < l.tle.runnerConfig.numRuns; i++ {
-		l.s.sleep(sleepBetweenRunsConfig, sleepTimeFunc, l.tle.runnerName)
+	for i := uint32(0); runsRemaining(l.tle, i); i++ {
+		l.s.sleep(sleepContext(l.tle), sleepBetweenRunsConfig, sleepTimeFunc, l.tle.runnerName)
 		if i > 0 && i%updateStep == 0 {
-			lp.LogMapRunnerEvent(fmt.Sprintf("finished %d of %d runs for map %s in map goroutine %d", i, l.tle.runnerConfig.numRuns, mapName, mapNumber), l.tle.runnerName, log.InfoLevel)
+			lp.LogMapRunnerEvent(fmt.Sprintf("finished %d of %s runs for map %s in map goroutine %d", i, describeNumRuns(l.tle), mapName, mapNumber), l.tle.runnerName, log.InfoLevel)
 		}
 		lp.LogMapRunnerEvent(fmt.Sprintf("in run %d on map %s in map goroutine %d", i, mapName, mapNumber), l.tle.runnerName, log.TraceLevel)
 		err := l.ingestAll(m, mapName, mapNumber)
@@ -632,20 +1003,29 @@ func (l *batchTestLoop[t]) runForMap(m hazelcastwrapper.Map, mapName string, map
 			lp.LogHzEvent(fmt.Sprintf("failed to ingest data into map '%s' in run %d: %s", mapName, i, err), log.WarnLevel)
 			continue
 		}
-		l.s.sleep(sleepBetweenActionBatchesConfig, sleepTimeFunc, l.tle.runnerName)
+		l.s.sleep(sleepContext(l.tle), sleepBetweenActionBatchesConfig, sleepTimeFunc, l.tle.runnerName)
 		err = l.readAll(m, mapName, mapNumber)
 		if err != nil {
 			lp.LogHzEvent(fmt.Sprintf("failed to read data from map '%s' in run %d: %s", mapName, i, err), log.WarnLevel)
 			continue
 		}
-		l.s.sleep(sleepBetweenActionBatchesConfig, sleepTimeFunc, l.tle.runnerName)
+		l.s.sleep(sleepContext(l.tle), sleepBetweenActionBatchesConfig, sleepTimeFunc, l.tle.runnerName)
 		err = l.removeSome(m, mapName, mapNumber)
 		if err != nil {
 			lp.LogHzEvent(fmt.Sprintf("failed to delete data from map '%s' in run %d: %s", mapName, i, err), log.WarnLevel)
 			continue
 		}
+		if l.tle.runnerConfig.expiry.verify {
+			keys := make([]string, len(l.tle.elements))
+			for j, v := range l.tle.elements {
+				keys[j] = assembleMapKey(mapName, mapNumber, l.tle.getElementID(v))
+			}
+			verifyExpiryInBackground(l.tle, l.ev, l.ct, l.lt, l.tr, m, mapName, mapNumber, keys, &pendingExpiryVerifications)
+		}
 	}
 
+	pendingExpiryVerifications.Wait()
+
 	lp.LogMapRunnerEvent(fmt.Sprintf("map test loop done on map '%s' in map goroutine %d", mapName, mapNumber), l.tle.runnerName, log.InfoLevel)
 
 }
@@ -655,11 +1035,19 @@ func (l *batchTestLoop[t]) ingestAll(m hazelcastwrapper.Map, mapName string, map
 	numNewlyIngested := 0
 	for _, v := range l.tle.elements {
 		key := assembleMapKey(mapName, mapNumber, l.tle.getElementID(v))
+		l.tr.Await(l.tle.ctx, int(mapNumber))
+		start := time.Now()
 		containsKey, err := m.ContainsKey(l.tle.ctx, key)
+		l.lt.recordLatency(opContainsKey, time.Since(start))
 		if err != nil {
 			l.ct.increaseCounter(statusKeyNumFailedKeyChecks)
 			return err
 		}
+		if l.tle.runnerConfig.expiry.verify {
+			if err := evaluateExpiry(l.ev, l.ct, key, containsKey); err != nil {
+				lp.LogHzEvent(fmt.Sprintf("expiry verification failed for map '%s': %v", mapName, err), log.WarnLevel)
+			}
+		}
 		if containsKey {
 			continue
 		}
@@ -667,11 +1055,34 @@ func (l *batchTestLoop[t]) ingestAll(m hazelcastwrapper.Map, mapName string, map
 		if err != nil {
 			return err
 		}
-		if err = m.Set(l.tle.ctx, key, value); err != nil {
+		var vp verifiablePayload
+		if l.tle.runnerConfig.verifyIntegrity {
+			if vp, err = l.iv.wrap(value); err != nil {
+				return err
+			}
+			value = vp
+		}
+		l.tr.Await(l.tle.ctx, int(mapNumber))
+		start = time.Now()
+		err = setEntry(l.tle, m, key, value)
+		l.lt.recordLatency(opSet, time.Since(start))
+		if err != nil {
 			l.ct.increaseCounter(statusKeyNumFailedInserts)
+			if l.tle.runnerConfig.entryListener.enabled {
+				l.lv.tolerate(key, entryWritten)
+			}
 			return err
 		}
-		l.s.sleep(l.tle.runnerConfig.batch.sleepAfterBatchAction, sleepTimeFunc, l.tle.runnerName)
+		if l.tle.runnerConfig.verifyIntegrity {
+			l.iv.confirmWrite(key, vp)
+		}
+		if l.tle.runnerConfig.expiry.verify {
+			l.ev.confirmWrite(key)
+		}
+		if l.tle.runnerConfig.entryListener.enabled {
+			l.lv.expect(key, entryWritten)
+		}
+		l.s.sleep(sleepContext(l.tle), l.tle.runnerConfig.batch.sleepAfterBatchAction, sleepTimeFunc, l.tle.runnerName)
 		numNewlyIngested++
 	}
 
@@ -685,16 +1096,33 @@ func (l *batchTestLoop[t]) readAll(m hazelcastwrapper.Map, mapName string, mapNu
 
 	for _, v := range l.tle.elements {
 		key := assembleMapKey(mapName, mapNumber, l.tle.getElementID(v))
+		l.tr.Await(l.tle.ctx, int(mapNumber))
+		start := time.Now()
 		valueFromHZ, err := m.Get(l.tle.ctx, key)
+		l.lt.recordLatency(opGet, time.Since(start))
 		if err != nil {
 			l.ct.increaseCounter(statusKeyNumFailedReads)
 			return err
 		}
+		if l.tle.runnerConfig.expiry.verify {
+			if err := evaluateExpiry(l.ev, l.ct, key, valueFromHZ != nil); err != nil {
+				return err
+			}
+		}
+		if valueFromHZ == nil && l.tle.runnerConfig.expiry.enabled {
+			// Entry has expired in the meantime, so there is nothing to read
+			continue
+		}
 		if valueFromHZ == nil {
 			l.ct.increaseCounter(statusKeyNumNilReads)
 			return fmt.Errorf("value retrieved from hazelcast for key '%s' was nil", key)
 		}
-		l.s.sleep(l.tle.runnerConfig.batch.sleepAfterBatchAction, sleepTimeFunc, l.tle.runnerName)
+		if l.tle.runnerConfig.verifyIntegrity {
+			if err := evaluateReadIntegrity(l.iv, l.ct, key, valueFromHZ); err != nil {
+				return err
+			}
+		}
+		l.s.sleep(sleepContext(l.tle), l.tle.runnerConfig.batch.sleepAfterBatchAction, sleepTimeFunc, l.tle.runnerName)
 	}
 
 	lp.LogMapRunnerEvent(fmt.Sprintf("retrieved %d items from hazelcast map '%s'", len(l.tle.elements), mapName), l.tle.runnerName, log.TraceLevel)
@@ -712,20 +1140,44 @@ func (l *batchTestLoop[t]) removeSome(m hazelcastwrapper.Map, mapName string, ma
 
 	for i := 0; i < numElementsToDelete; i++ {
 		key := assembleMapKey(mapName, mapNumber, l.tle.getElementID(elements[i]))
+		l.tr.Await(l.tle.ctx, int(mapNumber))
+		start := time.Now()
 		containsKey, err := m.ContainsKey(l.tle.ctx, key)
+		l.lt.recordLatency(opContainsKey, time.Since(start))
 		if err != nil {
 			return err
 		}
+		if l.tle.runnerConfig.expiry.verify {
+			if err := evaluateExpiry(l.ev, l.ct, key, containsKey); err != nil {
+				lp.LogHzEvent(fmt.Sprintf("expiry verification failed for map '%s': %v", mapName, err), log.WarnLevel)
+			}
+		}
 		if !containsKey {
 			continue
 		}
-		_, err = m.Remove(l.tle.ctx, key)
+		l.tr.Await(l.tle.ctx, int(mapNumber))
+		start = time.Now()
+		v, err := m.Remove(l.tle.ctx, key)
+		l.lt.recordLatency(opRemove, time.Since(start))
 		if err != nil {
 			l.ct.increaseCounter(statusKeyNumFailedRemoves)
+			if l.tle.runnerConfig.entryListener.enabled {
+				l.lv.tolerate(key, entryRemoved)
+			}
 			return err
 		}
+		if l.tle.runnerConfig.verifyIntegrity {
+			l.iv.confirmRemove(key)
+		}
+		if l.tle.runnerConfig.expiry.verify {
+			l.ev.confirmRemove(key)
+		}
+		// Key might have expired between the check above and the removal, in which case there is no event to expect
+		if l.tle.runnerConfig.entryListener.enabled && v != nil {
+			l.lv.expect(key, entryRemoved)
+		}
 		removed++
-		l.s.sleep(l.tle.runnerConfig.batch.sleepAfterBatchAction, sleepTimeFunc, l.tle.runnerName)
+		l.s.sleep(sleepContext(l.tle), l.tle.runnerConfig.batch.sleepAfterBatchAction, sleepTimeFunc, l.tle.runnerName)
 	}
 
 	lp.LogMapRunnerEvent(fmt.Sprintf("removed %d elements from hazelcast map '%s'", removed, mapName), l.tle.runnerName, log.TraceLevel)
@@ -734,6 +1186,120 @@ func (l *batchTestLoop[t]) removeSome(m hazelcastwrapper.Map, mapName string, ma
 
 }
 
+// setEntry writes the given value to the given map using the configured TTL and max idle duration in case expiry has
+// been enabled for the runner. With JSON values enabled, the value is written as a JSON document rather than in Go's
+// own serialization format, so the Hazelcast cluster can evaluate queries on its attributes.
+func setEntry[t any](tle *testLoopExecution[t], m hazelcastwrapper.Map, key string, value any) error {
+
+	if tle.runnerConfig.storeJsonValues {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("unable to encode value for key '%s' as json: %w", key, err)
+		}
+		value = serialization.JSON(b)
+	}
+
+	if ec := tle.runnerConfig.expiry; ec.enabled {
+		return m.SetWithTTLAndMaxIdle(tle.ctx, key, value, ec.ttl, ec.maxIdle)
+	}
+
+	return m.Set(tle.ctx, key, value)
+
+}
+
+// sampleExpiryKeys picks up to the configured sample size of those among the given keys whose expiry the test loop
+// currently keeps track of, and returns them along with their expected expiry.
+func sampleExpiryKeys(ev expiryVerifier, keys []string, sampleSize int) map[string]time.Time {
+
+	sample := make(map[string]time.Time)
+	for _, i := range rand.Perm(len(keys)) {
+		if len(sample) >= sampleSize {
+			break
+		}
+		if expiry, ok := ev.expectedExpiry(keys[i]); ok {
+			sample[keys[i]] = expiry
+		}
+	}
+
+	return sample
+
+}
+
+// verifyExpiryInBackground checks a sample of the given keys for late expiry on a separate goroutine, so the test
+// loop can carry on with its next runs in the meantime rather than waiting until the entries in question should have
+// expired. Keys whose expected expiry has changed by the time they are checked -- because the test loop has
+// written, read, or removed them in the meantime -- are skipped, as they have been verified upon access anyway.
+func verifyExpiryInBackground[t any](
+	tle *testLoopExecution[t],
+	ev expiryVerifier,
+	ct counterTracker,
+	lt latencyTracker,
+	tr *loadsupport.ThroughputRegulator,
+	m hazelcastwrapper.Map,
+	mapName string,
+	mapNumber uint16,
+	keys []string,
+	pending *sync.WaitGroup,
+) {
+
+	sample := sampleExpiryKeys(ev, keys, tle.runnerConfig.expiry.sampleSize)
+	if len(sample) == 0 {
+		lp.LogMapRunnerEvent(fmt.Sprintf("no entries to verify expiry for in map '%s' in goroutine %d", mapName, mapNumber), tle.runnerName, log.TraceLevel)
+		return
+	}
+
+	var latestExpiry time.Time
+	for _, expiry := range sample {
+		if expiry.After(latestExpiry) {
+			latestExpiry = expiry
+		}
+	}
+
+	ctx := sleepContext(tle)
+
+	pending.Add(1)
+	go func() {
+		defer pending.Done()
+
+		if wait := time.Until(latestExpiry.Add(tle.runnerConfig.expiry.tolerance)); wait > 0 {
+			lp.LogMapRunnerEvent(fmt.Sprintf("verifying expiry of %d entries in map '%s' in goroutine %d in %s", len(sample), mapName, mapNumber, wait), tle.runnerName, log.InfoLevel)
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				lp.LogMapRunnerEvent(fmt.Sprintf("test loop stopped before entries in map '%s' in goroutine %d were expected to expire -- skipping expiry verification", mapName, mapNumber), tle.runnerName, log.InfoLevel)
+				return
+			case <-timer.C:
+			}
+		}
+
+		numGone := 0
+		for k, expiry := range sample {
+			if current, ok := ev.expectedExpiry(k); !ok || !current.Equal(expiry) {
+				continue
+			}
+			tr.Await(tle.ctx, int(mapNumber))
+			start := time.Now()
+			present, err := m.ContainsKey(tle.ctx, k)
+			lt.recordLatency(opContainsKey, time.Since(start))
+			if err != nil {
+				ct.increaseCounter(statusKeyNumFailedKeyChecks)
+				lp.LogHzEvent(fmt.Sprintf("unable to check presence of key '%s' in map '%s' for expiry verification: %v", k, mapName, err), log.WarnLevel)
+				continue
+			}
+			if err := evaluateExpiry(ev, ct, k, present); err != nil {
+				lp.LogHzEvent(fmt.Sprintf("expiry verification failed for map '%s': %v", mapName, err), log.WarnLevel)
+			}
+			if !present {
+				numGone++
+			}
+		}
+
+		lp.LogMapRunnerEvent(fmt.Sprintf("verified expiry of %d sampled entries in map '%s' in goroutine %d, %d of which were gone", len(sample), mapName, mapNumber, numGone), tle.runnerName, log.InfoLevel)
+	}()
+
+}
+
 func assembleMapName(rc *runnerConfig, mapIndex uint16) string {
 
 	mapName := rc.mapBaseName
@@ -751,12 +1317,20 @@ func assembleMapName(rc *runnerConfig, mapIndex uint16) string {
 
 }
 
-func (s *defaultSleeper) sleep(sc *sleepConfig, sf evaluateTimeToSleep, runnerName string) {
+// sleep returns early once the given context is done, so a run duration elapsing in the middle of a sleep does not
+// make the test loop overshoot its run duration by the remainder of the sleep.
+func (s *defaultSleeper) sleep(ctx context.Context, sc *sleepConfig, sf evaluateTimeToSleep, runnerName string) {
 
 	if sc.enabled {
 		sleepDuration := sf(sc)
 		lp.LogMapRunnerEvent(fmt.Sprintf("sleeping for %d milliseconds", sleepDuration), runnerName, log.TraceLevel)
-		time.Sleep(time.Duration(sleepDuration) * time.Millisecond)
+		timer := time.NewTimer(time.Duration(sleepDuration) * time.Millisecond)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			lp.LogMapRunnerEvent("sleep cut short because run duration has elapsed", runnerName, log.TraceLevel)
+		case <-timer.C:
+		}
 	}
 
 }
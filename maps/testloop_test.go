@@ -2,17 +2,21 @@ package maps
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/hazelcast/hazelcast-go-client/serialization"
 	"hazeltest/client"
 	"hazeltest/hazelcastwrapper"
+	"hazeltest/loadsupport"
 	"hazeltest/state"
 	"hazeltest/status"
 	"math"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 type (
@@ -230,6 +234,37 @@ func TestMapTestLoopCountersTrackerInit(t *testing.T) {
 					t.Fatal(msg, ballotX, detail)
 				}
 			}
+
+			msg = "\t\toptional counters must not have been inserted into status record"
+			for _, v := range integrityCounters {
+				if _, ok := statusCopy[string(v)]; !ok {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+		}
+
+		t.Log("\twhen init method is invoked with optional counters")
+		{
+			ct := &mapTestLoopCountersTracker{}
+			g := status.NewGatherer()
+
+			go g.Listen()
+			ct.init(g, integrityCounters...)
+			g.StopListen()
+
+			waitForStatusGatheringDone(g)
+			msg := "\t\tgatherer must have received both regular and optional status keys with initial values"
+			statusCopy := g.AssembleStatusCopy()
+
+			for _, v := range append(counters, integrityCounters...) {
+				if ok, detail := expectedCounterValuePresent(statusCopy, v, 0); ok {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, detail)
+				}
+			}
 		}
 	}
 
@@ -303,6 +338,129 @@ func TestMapTestLoopCountersTrackerIncreaseCounter(t *testing.T) {
 
 }
 
+func TestMapTestLoopLatencyTrackerInit(t *testing.T) {
+
+	t.Log("given the latency tracker's init function")
+	{
+		t.Log("\twhen init method is invoked")
+		{
+			lt := &mapTestLoopLatencyTracker{}
+			g := status.NewGatherer()
+
+			go g.Listen()
+			lt.init(g)
+			g.StopListen()
+
+			msg := "\t\tgatherer must have been assigned"
+			if lt.gatherer == g {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\thistogram must have been created for each map operation"
+			for _, v := range operations {
+				if h, ok := lt.histograms[v]; ok && h != nil {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+
+			waitForStatusGatheringDone(g)
+
+			msg = "\t\tgatherer must have received empty latency snapshot for each map operation"
+			snapshots, ok := g.AssembleStatusCopy()[string(statusKeyLatencies)].(map[string]status.HistogramSnapshot)
+			if !ok {
+				t.Fatal(msg, ballotX, "no latency snapshots present in status")
+			}
+			for _, v := range operations {
+				if s, ok := snapshots[string(v)]; ok && s.Count == 0 {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+		}
+	}
+
+}
+
+func TestMapTestLoopLatencyTrackerRecordLatency(t *testing.T) {
+
+	t.Log("given a method for recording the latency of a map operation")
+	{
+		t.Log("\twhen multiple goroutines record latencies and publish interval has not elapsed yet")
+		{
+			g := status.NewGatherer()
+			lt := &mapTestLoopLatencyTracker{}
+			go g.Listen()
+			lt.init(g)
+
+			wg := sync.WaitGroup{}
+			numInvokingGoroutines := 100
+			for i := 0; i < numInvokingGoroutines; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					lt.recordLatency(opSet, 2*time.Millisecond)
+				}()
+			}
+			wg.Wait()
+
+			msg := "\t\thistogram for operation must contain one recording per invocation"
+			if s := lt.histograms[opSet].Snapshot(); s.Count == uint64(numInvokingGoroutines) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.Count)
+			}
+
+			msg = "\t\thistograms for other operations must remain empty"
+			for _, v := range []mapOperation{opGet, opRemove, opContainsKey} {
+				if s := lt.histograms[v].Snapshot(); s.Count == 0 {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+
+			lt.publish()
+			g.StopListen()
+			waitForStatusGatheringDone(g)
+
+			msg = "\t\tstatus must reflect recorded latencies after publish"
+			snapshots := g.AssembleStatusCopy()[string(statusKeyLatencies)].(map[string]status.HistogramSnapshot)
+			if s := snapshots[string(opSet)]; s.Count == uint64(numInvokingGoroutines) && s.MaxMicros == 2000 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s)
+			}
+		}
+		t.Log("\twhen publish interval has elapsed")
+		{
+			g := status.NewGatherer()
+			lt := &mapTestLoopLatencyTracker{}
+			go g.Listen()
+			lt.init(g)
+
+			lt.lastPublished = time.Now().Add(-latencyPublishInterval)
+			lt.recordLatency(opGet, time.Millisecond)
+
+			g.StopListen()
+			waitForStatusGatheringDone(g)
+
+			msg := "\t\tlatencies must have been published to status gatherer"
+			snapshots := g.AssembleStatusCopy()[string(statusKeyLatencies)].(map[string]status.HistogramSnapshot)
+			if s := snapshots[string(opGet)]; s.Count == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s)
+			}
+		}
+	}
+
+}
+
 func TestChooseNextMapElement(t *testing.T) {
 
 	t.Log("given a set of possible map actions and a cache mirroring the current state of the corresponding map in hazelcast")
@@ -861,6 +1019,13 @@ func TestExecuteMapAction(t *testing.T) {
 						t.Fatal(msg, ballotX, fmt.Sprintf("expected 1 invocation, got %d", ms.m.setInvocations))
 					}
 
+					msg = "\t\t\tlatency of set operation must have been recorded"
+					if c := tl.lt.(*mapTestLoopLatencyTracker).histograms[opSet].Snapshot().Count; c == 1 {
+						t.Log(msg, checkMark)
+					} else {
+						t.Fatal(msg, ballotX, fmt.Sprintf("expected 1 recording, got %d", c))
+					}
+
 					msg = "\t\t\tmap must contain one element"
 					count := 0
 					ms.m.data.Range(func(_, _ any) bool {
@@ -1408,6 +1573,98 @@ func TestExecuteMapAction(t *testing.T) {
 				t.Fatal(msg, ballotX, fmt.Sprintf("expected 0 invocations, got %d", ms.m.getInvocations))
 			}
 		}
+		t.Log("\twhen integrity verification is enabled")
+		{
+			t.Log("\t\twhen element is inserted and read back")
+			{
+				rc := assembleRunnerConfigForBoundaryTestLoop(
+					rpOneMapOneRunNoEvictionScDisabled,
+					sleepConfigDisabled,
+					sleepConfigDisabled,
+					1.0,
+					0.0,
+					0.5,
+					42,
+					true,
+				)
+				rc.verifyIntegrity = true
+				ms := assembleTestMapStore(&testMapStoreBehavior{})
+				tl := assembleBoundaryTestLoop(uuid.New(), testSource, &testHzClientHandler{}, ms, rc)
+
+				go tl.gatherer.Listen()
+				insertErr := tl.executeMapAction(ms.m, defaultTestMapName, defaultTestMapNumber, theFellowship[0], insert)
+				readErr := tl.executeMapAction(ms.m, defaultTestMapName, defaultTestMapNumber, theFellowship[0], read)
+				tl.gatherer.StopListen()
+
+				msg := "\t\t\tno error must be returned"
+				if insertErr == nil && readErr == nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, insertErr, readErr)
+				}
+
+				msg = "\t\t\tvalue stored in map must be verifiable payload wrapping element"
+				v, _ := ms.m.data.Load(assembleMapKey(defaultTestMapName, defaultTestMapNumber, theFellowship[0]))
+				if p, ok := v.(verifiablePayload); ok && p.Payload == theFellowship[0] && p.Version == 1 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+
+				waitForStatusGatheringDone(tl.gatherer)
+
+				msg = "\t\t\tstatus gatherer must indicate zero corrupted and zero stale reads"
+				statusCopy := tl.gatherer.AssembleStatusCopy()
+				for _, v := range []statusKey{statusKeyNumCorruptedReads, statusKeyNumStaleReads} {
+					if ok, detail := expectedCounterValuePresent(statusCopy, v, 0); ok {
+						t.Log(msg, checkMark, v)
+					} else {
+						t.Fatal(msg, ballotX, detail)
+					}
+				}
+			}
+			t.Log("\t\twhen value read does not match its checksum")
+			{
+				rc := assembleRunnerConfigForBoundaryTestLoop(
+					rpOneMapOneRunNoEvictionScDisabled,
+					sleepConfigDisabled,
+					sleepConfigDisabled,
+					1.0,
+					0.0,
+					0.5,
+					42,
+					true,
+				)
+				rc.verifyIntegrity = true
+				ms := assembleTestMapStore(&testMapStoreBehavior{})
+				tl := assembleBoundaryTestLoop(uuid.New(), testSource, &testHzClientHandler{}, ms, rc)
+
+				key := assembleMapKey(defaultTestMapName, defaultTestMapNumber, theFellowship[0])
+				p, _ := tl.iv.wrap(theFellowship[0])
+				p.Payload = theFellowship[1]
+				ms.m.data.Store(key, p)
+
+				go tl.gatherer.Listen()
+				err := tl.executeMapAction(ms.m, defaultTestMapName, defaultTestMapNumber, theFellowship[0], read)
+				tl.gatherer.StopListen()
+
+				msg := "\t\t\terror must be returned"
+				if err != nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX)
+				}
+
+				waitForStatusGatheringDone(tl.gatherer)
+
+				msg = "\t\t\tstatus gatherer must indicate one corrupted read"
+				if ok, detail := expectedCounterValuePresent(tl.gatherer.AssembleStatusCopy(), statusKeyNumCorruptedReads, 1); ok {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, detail)
+				}
+			}
+		}
 	}
 
 }
@@ -2298,6 +2555,85 @@ func TestRunOperationChain(t *testing.T) {
 			}
 		}
 
+		t.Log("\twhen run duration has already elapsed")
+		{
+			ms := assembleTestMapStore(&testMapStoreBehavior{})
+			rc := assembleRunnerConfigForBoundaryTestLoop(
+				rpOneMapOneRunNoEvictionScDisabled,
+				sleepConfigDisabled,
+				sleepConfigDisabled,
+				1.0,
+				0.0,
+				1.0,
+				len(theFellowship),
+				true,
+			)
+			tl := assembleBoundaryTestLoop(uuid.New(), testSource, &testHzClientHandler{}, ms, rc)
+			runCtx, cancel := context.WithCancel(context.TODO())
+			cancel()
+			tl.tle.runCtx = runCtx
+
+			err := tl.runOperationChain(0, ms.m, &modeCache{}, &actionCache{}, "awesome-map", 0, map[string]string{}, populateElementsAvailableForInsertion("awesome-map", 0, theFellowship))
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\toperation chain must have been aborted before first action"
+			if ms.m.setInvocations == 0 && ms.m.getInvocations == 0 && ms.m.removeInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen expiry has been enabled and read finds entry to have expired")
+		{
+			ms := assembleTestMapStore(&testMapStoreBehavior{})
+			rc := assembleRunnerConfigForBoundaryTestLoop(
+				rpOneMapOneRunNoEvictionScDisabled,
+				sleepConfigDisabled,
+				sleepConfigDisabled,
+				1.0,
+				0.0,
+				1.0,
+				1,
+				true,
+			)
+			rc.expiry = &expiryConfig{enabled: true, ttl: time.Minute}
+			tl := assembleBoundaryTestLoop(uuid.New(), testSource, &testHzClientHandler{}, ms, rc)
+
+			// Keys in cache are absent from map, so map reports them as expired upon read
+			keysCache := populateElementsAvailableForInsertion(defaultTestMapName, defaultTestMapNumber, theFellowship)
+			availableForInsertion := map[string]string{}
+
+			err := tl.runOperationChain(0, ms.m, &modeCache{}, &actionCache{last: insert}, defaultTestMapName, defaultTestMapNumber, keysCache, availableForInsertion)
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tread must have been executed"
+			if ms.m.getInvocations == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ms.m.getInvocations)
+			}
+
+			msg = "\t\texpired entry must have been dropped from keys cache"
+			if len(keysCache) == len(theFellowship)-1 && len(availableForInsertion) == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, len(keysCache), len(availableForInsertion))
+			}
+		}
+
 		t.Log("\twhen chain length is greater than zero")
 		{
 			t.Log("\t\twhen upper boundary is 100 %, lower boundary is 0 %, and probability for action towards boundary is 100 %")
@@ -2691,23 +3027,258 @@ func TestRunOperationChain(t *testing.T) {
 
 }
 
-func (s *testSleeper) sleep(_ *sleepConfig, _ evaluateTimeToSleep, _ string) {
-
-	s.sleepInvoked = true
-
-}
-
-func TestRunWithBatchTestLoop(t *testing.T) {
+func TestSetEntry(t *testing.T) {
 
-	t.Log("given the maps batch test loop")
+	t.Log("given a value to be written to a map")
 	{
-		t.Log("\twhen only one map goroutine is used and the test loop runs only once")
+		key := assembleMapKey(defaultTestMapName, defaultTestMapNumber, "pikachu")
+		value := pokemon{ID: 25, Name: "Pikachu", ElementType: []string{"Electric"}}
+
+		t.Log("\twhen json values have been enabled")
 		{
-			func() {
-				defer resetGetOrAssemblePayloadTestSetup()
+			ms := assembleTestMapStore(&testMapStoreBehavior{})
+			tle := &testLoopExecution[pokemon]{
+				ctx:          context.TODO(),
+				runnerConfig: &runnerConfig{storeJsonValues: true, expiry: &expiryConfig{}},
+			}
 
-				id := uuid.New()
-				ms := assembleTestMapStore(&testMapStoreBehavior{})
+			err := setEntry(tle, ms.m, key, value)
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tvalue must have been written as json value"
+			stored, _ := ms.m.data.Load(key)
+			if j, ok := stored.(serialization.JSON); ok {
+				var decoded pokemon
+				if err := json.Unmarshal(j, &decoded); err == nil && decoded.Name == value.Name && decoded.ElementType[0] == value.ElementType[0] {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, string(j))
+				}
+			} else {
+				t.Fatal(msg, ballotX, stored)
+			}
+		}
+
+		t.Log("\twhen json values have not been enabled")
+		{
+			ms := assembleTestMapStore(&testMapStoreBehavior{})
+			tle := &testLoopExecution[pokemon]{
+				ctx:          context.TODO(),
+				runnerConfig: &runnerConfig{expiry: &expiryConfig{}},
+			}
+
+			_ = setEntry(tle, ms.m, key, value)
+
+			msg := "\t\tvalue must have been written as-is"
+			if stored, _ := ms.m.data.Load(key); stored != nil {
+				if _, ok := stored.(pokemon); ok {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, stored)
+				}
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestSampleExpiryKeys(t *testing.T) {
+
+	t.Log("given keys written by the test loop with expiry")
+	{
+		ev := newMapTestLoopExpiryVerifier(&expiryConfig{ttl: time.Minute})
+		keys := make([]string, 0, len(theFellowship))
+		for _, v := range theFellowship {
+			key := assembleMapKey(defaultTestMapName, defaultTestMapNumber, v)
+			keys = append(keys, key)
+			ev.confirmWrite(key)
+		}
+		untrackedKey := assembleMapKey(defaultTestMapName, defaultTestMapNumber, "gollum")
+
+		t.Log("\twhen sample size is smaller than number of keys")
+		{
+			sample := sampleExpiryKeys(ev, keys, 3)
+
+			msg := "\t\tsample must contain number of keys equal to sample size"
+			if len(sample) == 3 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, len(sample))
+			}
+
+			msg = "\t\tsampled keys must carry their expected expiry"
+			for k, expiry := range sample {
+				if expected, ok := ev.expectedExpiry(k); !ok || !expected.Equal(expiry) {
+					t.Fatal(msg, ballotX, k)
+				}
+			}
+			t.Log(msg, checkMark)
+		}
+
+		t.Log("\twhen sample size exceeds number of keys and some keys aren't tracked")
+		{
+			sample := sampleExpiryKeys(ev, append(keys, untrackedKey), 2*len(keys))
+
+			msg := "\t\tsample must contain all tracked keys"
+			if len(sample) == len(keys) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, len(sample))
+			}
+
+			msg = "\t\tsample must not contain untracked key"
+			if _, ok := sample[untrackedKey]; !ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestDefaultSleeperSleep(t *testing.T) {
+
+	t.Log("given a sleeper and a context")
+	{
+		t.Log("\twhen context is done while sleeping")
+		{
+			s := &defaultSleeper{}
+			ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			s.sleep(ctx, &sleepConfig{enabled: true, durationMs: 10000}, sleepTimeFunc, "awesomeRunner")
+			elapsed := time.Since(start)
+
+			msg := "\t\tsleep must be cut short"
+			if elapsed < 5*time.Second {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, elapsed)
+			}
+		}
+		t.Log("\twhen context is not done")
+		{
+			s := &defaultSleeper{}
+
+			start := time.Now()
+			s.sleep(context.TODO(), &sleepConfig{enabled: true, durationMs: 20}, sleepTimeFunc, "awesomeRunner")
+			elapsed := time.Since(start)
+
+			msg := "\t\tsleeper must sleep for configured duration"
+			if elapsed >= 20*time.Millisecond {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, elapsed)
+			}
+		}
+	}
+
+}
+
+func TestReportRemainingRunDuration(t *testing.T) {
+
+	t.Log("given a function to report the remaining run duration of a test loop")
+	{
+		t.Log("\twhen run context has a deadline")
+		{
+			g := status.NewGatherer()
+			runCtx, cancel := context.WithTimeout(context.TODO(), 2*time.Second)
+
+			done := make(chan struct{})
+			go func() {
+				reportRemainingRunDuration(runCtx, g, time.Hour)
+				close(done)
+			}()
+
+			msg := "\t\tremaining run duration must be reported immediately"
+			if u := <-g.Updates; u.Key == string(statusKeyRemainingRunDurationSeconds) && u.Value == uint64(2) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, u)
+			}
+
+			cancel()
+			<-done
+
+			msg = "\t\tzero seconds must be reported once run context is done"
+			if u := <-g.Updates; u.Key == string(statusKeyRemainingRunDurationSeconds) && u.Value == uint64(0) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, u)
+			}
+		}
+	}
+
+}
+
+func TestRunsRemaining(t *testing.T) {
+
+	t.Log("given a function to determine whether a test loop should start another run")
+	{
+		t.Log("\twhen no run duration has been configured")
+		{
+			tle := &testLoopExecution[string]{runnerConfig: &runnerConfig{numRuns: 2}}
+
+			msg := "\t\tresult must be determined by number of runs"
+			if runsRemaining(tle, 1) && !runsRemaining(tle, 2) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen run duration has been configured")
+		{
+			runCtx, cancel := context.WithCancel(context.TODO())
+			tle := &testLoopExecution[string]{runnerConfig: &runnerConfig{numRuns: 2}, runCtx: runCtx}
+
+			msg := "\t\tnumber of runs must be ignored as long as run duration hasn't elapsed"
+			if runsRemaining(tle, 5) && !runDeadlineReached(tle) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			cancel()
+
+			msg = "\t\tno runs must remain once run duration has elapsed"
+			if !runsRemaining(tle, 0) && runDeadlineReached(tle) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func (s *testSleeper) sleep(_ context.Context, _ *sleepConfig, _ evaluateTimeToSleep, _ string) {
+
+	s.sleepInvoked = true
+
+}
+
+func TestRunWithBatchTestLoop(t *testing.T) {
+
+	t.Log("given the maps batch test loop")
+	{
+		t.Log("\twhen only one map goroutine is used and the test loop runs only once")
+		{
+			func() {
+				defer resetGetOrAssemblePayloadTestSetup()
+
+				id := uuid.New()
+				ms := assembleTestMapStore(&testMapStoreBehavior{})
 				numMaps, numRuns := uint16(1), uint32(1)
 				rc := assembleRunnerConfigForBatchTestLoop(
 					&runnerProperties{
@@ -3044,6 +3615,176 @@ func TestRunWithBatchTestLoop(t *testing.T) {
 				}
 			}()
 		}
+
+		t.Log("\twhen run duration has been configured")
+		{
+			func() {
+				defer resetGetOrAssemblePayloadTestSetup()
+
+				numRuns := uint32(1)
+				rc := assembleRunnerConfigForBatchTestLoop(
+					&runnerProperties{
+						numMaps:             1,
+						numRuns:             numRuns,
+						cleanMapsPriorToRun: false,
+						sleepBetweenRuns:    sleepConfigDisabled,
+					},
+					sleepConfigDisabled,
+					sleepConfigDisabled,
+				)
+				rc.runDuration = 50 * time.Millisecond
+				ms := assembleTestMapStore(&testMapStoreBehavior{})
+				tl := assembleBatchTestLoop(uuid.New(), testSource, &testHzClientHandler{}, ms, rc)
+				tl.tle.ctx = context.TODO()
+
+				go tl.gatherer.Listen()
+				start := time.Now()
+				tl.run()
+				elapsed := time.Since(start)
+				tl.gatherer.StopListen()
+
+				waitForStatusGatheringDone(tl.gatherer)
+
+				msg := "\t\ttest loop must have run until run duration elapsed"
+				if elapsed >= rc.runDuration {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, elapsed)
+				}
+
+				msg = "\t\tconfigured number of runs must have been ignored"
+				if ms.m.getInvocations > int(numRuns)*len(theFellowship) {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, ms.m.getInvocations)
+				}
+
+				msg = "\t\tstatus must report zero seconds of remaining run duration"
+				if v, ok := tl.gatherer.AssembleStatusCopy()[string(statusKeyRemainingRunDurationSeconds)]; ok && v == uint64(0) {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}()
+		}
+
+		t.Log("\twhen throughput regulation has been enabled")
+		{
+			func() {
+				defer resetGetOrAssemblePayloadTestSetup()
+
+				rc := assembleRunnerConfigForBatchTestLoop(
+					&runnerProperties{
+						numMaps:             2,
+						numRuns:             1,
+						cleanMapsPriorToRun: false,
+						sleepBetweenRuns:    sleepConfigDisabled,
+					},
+					sleepConfigDisabled,
+					sleepConfigDisabled,
+				)
+				rc.throughput = &loadsupport.ThroughputConfig{Enabled: true, TargetOpsPerSecond: 10_000, Scope: loadsupport.GoroutineScope}
+				ms := assembleTestMapStore(&testMapStoreBehavior{})
+				tl := assembleBatchTestLoop(uuid.New(), testSource, &testHzClientHandler{}, ms, rc)
+				tl.tle.ctx = context.TODO()
+
+				go tl.gatherer.Listen()
+				tl.run()
+				tl.gatherer.StopListen()
+
+				waitForStatusGatheringDone(tl.gatherer)
+
+				statusCopy := tl.gatherer.AssembleStatusCopy()
+
+				msg := "\t\tstatus must contain target throughput for all map goroutines together"
+				if v, ok := statusCopy[loadsupport.StatusKeyTargetOpsPerSecond]; ok && v == 20_000 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+
+				msg = "\t\tstatus must contain achieved throughput"
+				if v, ok := statusCopy[loadsupport.StatusKeyAchievedOpsPerSecond]; ok && v.(float64) > 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+
+				msg = "\t\tall map operations must have been executed nonetheless"
+				if ms.m.getInvocations == 2*len(theFellowship) {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, ms.m.getInvocations)
+				}
+			}()
+		}
+
+		t.Log("\twhen expiry verification has been enabled")
+		{
+			func() {
+				defer resetGetOrAssemblePayloadTestSetup()
+
+				rc := assembleRunnerConfigForBatchTestLoop(
+					&runnerProperties{
+						numMaps:             1,
+						numRuns:             1,
+						cleanMapsPriorToRun: false,
+						sleepBetweenRuns:    sleepConfigDisabled,
+					},
+					sleepConfigDisabled,
+					sleepConfigDisabled,
+				)
+				rc.expiry = &expiryConfig{enabled: true, ttl: 20 * time.Millisecond, verify: true, tolerance: 10 * time.Millisecond, sampleSize: len(theFellowship)}
+				ms := assembleTestMapStore(&testMapStoreBehavior{})
+				tl := assembleBatchTestLoop(uuid.New(), testSource, &testHzClientHandler{}, ms, rc)
+				tl.tle.ctx = context.TODO()
+
+				go tl.gatherer.Listen()
+				start := time.Now()
+				tl.run()
+				elapsed := time.Since(start)
+				tl.gatherer.StopListen()
+
+				waitForStatusGatheringDone(tl.gatherer)
+
+				msg := "\t\tall entries must have been written with configured ttl and max idle duration"
+				if ms.m.setWithTTLAndMaxIdleInvocations == len(theFellowship) && ms.m.lastTTL == rc.expiry.ttl && ms.m.lastMaxIdle == 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, ms.m.setWithTTLAndMaxIdleInvocations)
+				}
+
+				// Test map doesn't expire entries, so all entries not removed by the test loop must have expired late
+				numEntriesNotRemoved := 0
+				ms.m.data.Range(func(_, _ any) bool {
+					numEntriesNotRemoved++
+					return true
+				})
+
+				// Number of entries removed is random, and if all entries have been removed, there is nothing to wait for
+				msg = "\t\ttest loop must have waited for outstanding expiry verifications before finishing"
+				if numEntriesNotRemoved == 0 || elapsed >= rc.expiry.ttl+rc.expiry.tolerance {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, elapsed)
+				}
+				statusCopy := tl.gatherer.AssembleStatusCopy()
+
+				msg = "\t\tentries still present after expected expiry must have been reported as late expirations"
+				if v, ok := statusCopy[string(statusKeyNumLateExpirations)]; ok && v == uint64(numEntriesNotRemoved) {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, fmt.Sprintf("expected %d, got %v", numEntriesNotRemoved, v))
+				}
+
+				msg = "\t\tno premature expirations must have been reported"
+				if v, ok := statusCopy[string(statusKeyNumPrematureExpirations)]; ok && v == uint64(0) {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}()
+		}
 	}
 
 }
@@ -3540,6 +4281,170 @@ func TestReadAll(t *testing.T) {
 				t.Fatal(msg, ballotX)
 			}
 		}
+
+		t.Log("\twhen integrity verification is enabled and map returns value older than most recently written one")
+		{
+			ms := assembleTestMapStore(&testMapStoreBehavior{})
+			rc := assembleRunnerConfigForBatchTestLoop(
+				&runnerProperties{
+					numMaps:             1,
+					numRuns:             9,
+					cleanMapsPriorToRun: false,
+					sleepBetweenRuns:    sleepConfigDisabled,
+				},
+				sleepConfigDisabled,
+				sleepConfigDisabled,
+			)
+			rc.verifyIntegrity = true
+			tl := assembleBatchTestLoop(uuid.New(), testSource, &testHzClientHandler{}, ms, rc)
+
+			go tl.gatherer.Listen()
+			ingestErr := tl.ingestAll(ms.m, defaultTestMapName, defaultTestMapNumber)
+
+			key := assembleMapKey(defaultTestMapName, defaultTestMapNumber, theFellowship[0])
+			outdated, _ := ms.m.data.Load(key)
+			p, _ := tl.iv.wrap(theFellowship[0])
+			tl.iv.confirmWrite(key, p)
+			ms.m.data.Store(key, outdated)
+
+			readErr := tl.readAll(ms.m, defaultTestMapName, defaultTestMapNumber)
+			tl.gatherer.StopListen()
+
+			msg := "\t\tingest must not yield error"
+			if ingestErr == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ingestErr)
+			}
+
+			msg = "\t\tread must yield error"
+			if readErr != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			waitForStatusGatheringDone(tl.gatherer)
+
+			statusCopy := tl.gatherer.AssembleStatusCopy()
+
+			msg = "\t\tstatus gatherer must indicate one stale read"
+			if ok, detail := expectedCounterValuePresent(statusCopy, statusKeyNumStaleReads, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+
+			msg = "\t\tstatus gatherer must indicate zero corrupted reads"
+			if ok, detail := expectedCounterValuePresent(statusCopy, statusKeyNumCorruptedReads, 0); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
+
+		t.Log("\twhen expiry verification has been enabled and entry has expired before its expected expiry")
+		{
+			ms := assembleTestMapStore(&testMapStoreBehavior{})
+			rc := assembleRunnerConfigForBatchTestLoop(
+				&runnerProperties{
+					numMaps:             1,
+					numRuns:             1,
+					cleanMapsPriorToRun: false,
+					sleepBetweenRuns:    sleepConfigDisabled,
+				},
+				sleepConfigDisabled,
+				sleepConfigDisabled,
+			)
+			rc.expiry = &expiryConfig{enabled: true, ttl: time.Minute, verify: true, tolerance: time.Second}
+			tl := assembleBatchTestLoop(uuid.New(), testSource, &testHzClientHandler{}, ms, rc)
+			go tl.gatherer.Listen()
+
+			populateTestHzMapStore(defaultTestMapName, defaultTestMapNumber, &ms)
+			for _, v := range theFellowship {
+				tl.ev.confirmWrite(assembleMapKey(defaultTestMapName, defaultTestMapNumber, v))
+			}
+			ms.m.data.Delete(assembleMapKey(defaultTestMapName, defaultTestMapNumber, theFellowship[0]))
+
+			err := tl.readAll(ms.m, defaultTestMapName, defaultTestMapNumber)
+			tl.gatherer.StopListen()
+			waitForStatusGatheringDone(tl.gatherer)
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			statusCopy := tl.gatherer.AssembleStatusCopy()
+
+			msg = "\t\tstatus gatherer must indicate one premature expiration"
+			if ok, detail := expectedCounterValuePresent(statusCopy, statusKeyNumPrematureExpirations, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+
+			msg = "\t\tstatus gatherer must indicate zero nil reads"
+			if ok, detail := expectedCounterValuePresent(statusCopy, statusKeyNumNilReads, 0); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
+
+		t.Log("\twhen expiry verification has been enabled and entries have expired as expected")
+		{
+			ms := assembleTestMapStore(&testMapStoreBehavior{})
+			rc := assembleRunnerConfigForBatchTestLoop(
+				&runnerProperties{
+					numMaps:             1,
+					numRuns:             1,
+					cleanMapsPriorToRun: false,
+					sleepBetweenRuns:    sleepConfigDisabled,
+				},
+				sleepConfigDisabled,
+				sleepConfigDisabled,
+			)
+			rc.expiry = &expiryConfig{enabled: true, ttl: time.Minute, verify: true, tolerance: time.Second}
+			tl := assembleBatchTestLoop(uuid.New(), testSource, &testHzClientHandler{}, ms, rc)
+			go tl.gatherer.Listen()
+
+			ev := tl.ev.(*mapTestLoopExpiryVerifier)
+			for _, v := range theFellowship {
+				key := assembleMapKey(defaultTestMapName, defaultTestMapNumber, v)
+				ev.confirmWrite(key)
+				ev.lastWritten[key] = ev.lastWritten[key].Add(-2 * time.Minute)
+			}
+
+			err := tl.readAll(ms.m, defaultTestMapName, defaultTestMapNumber)
+			tl.gatherer.StopListen()
+			waitForStatusGatheringDone(tl.gatherer)
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			statusCopy := tl.gatherer.AssembleStatusCopy()
+
+			msg = "\t\tstatus gatherer must indicate zero premature expirations"
+			if ok, detail := expectedCounterValuePresent(statusCopy, statusKeyNumPrematureExpirations, 0); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+
+			msg = "\t\tstatus gatherer must indicate zero nil reads"
+			if ok, detail := expectedCounterValuePresent(statusCopy, statusKeyNumNilReads, 0); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
 	}
 }
 
@@ -3940,6 +4845,8 @@ func assembleBaseRunnerConfig(rp *runnerProperties) *runnerConfig {
 			enabled:       rp.cleanMapsPriorToRun,
 			errorBehavior: rp.mapCleanErrorBehavior,
 		},
+		expiry:           &expiryConfig{},
+		entryListener:    &entryListenerConfig{},
 		sleepBetweenRuns: rp.sleepBetweenRuns,
 		loopType:         boundary,
 		batch:            nil,
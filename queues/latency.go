@@ -0,0 +1,101 @@
+package queues
+
+import (
+	"encoding/gob"
+	"hazeltest/status"
+	"sync"
+	"time"
+)
+
+type (
+	latencyTracker interface {
+		init(gatherer *status.Gatherer)
+		recordLatency(queueName string, d time.Duration)
+		publish()
+	}
+	// timestampedElement is what gets put into the target queue instead of the plain element when time-in-queue
+	// latency tracking has been enabled. It wraps the element as it would have been put otherwise, so it forms the
+	// outermost layer in case ordering verification has been enabled, too.
+	timestampedElement struct {
+		EnqueuedAt time.Time
+		Payload    any
+	}
+	// queueTestLoopLatencyTracker keeps one histogram per queue for the time elements spent in the queue, i.e. the
+	// time between an element having been put and having been polled. Just like for the latencies of map operations
+	// tracked by the map test loops, the histograms are published to the status gatherer at most once per
+	// latencyPublishInterval (and once more when the test loop has finished).
+	queueTestLoopLatencyTracker struct {
+		histograms    map[string]*status.Histogram
+		lastPublished time.Time
+		l             sync.Mutex
+		gatherer      *status.Gatherer
+	}
+)
+
+const (
+	statusKeyTimeInQueueLatencies statusKey = "timeInQueueLatencies"
+	latencyPublishInterval                  = 1 * time.Second
+)
+
+func init() {
+	gob.Register(timestampedElement{})
+}
+
+func (lt *queueTestLoopLatencyTracker) init(gatherer *status.Gatherer) {
+	lt.gatherer = gatherer
+
+	lt.histograms = make(map[string]*status.Histogram)
+
+	lt.publish()
+}
+
+func (lt *queueTestLoopLatencyTracker) recordLatency(queueName string, d time.Duration) {
+
+	var publishDue bool
+	lt.l.Lock()
+	{
+		// Queue names are only known once the queue goroutines have started, so histograms are created lazily
+		h, ok := lt.histograms[queueName]
+		if !ok {
+			h = status.NewHistogram()
+			lt.histograms[queueName] = h
+		}
+		h.Record(d)
+		publishDue = time.Since(lt.lastPublished) >= latencyPublishInterval
+	}
+	lt.l.Unlock()
+
+	if publishDue {
+		lt.publish()
+	}
+
+}
+
+func (lt *queueTestLoopLatencyTracker) publish() {
+
+	var snapshots map[string]status.HistogramSnapshot
+	lt.l.Lock()
+	{
+		snapshots = make(map[string]status.HistogramSnapshot, len(lt.histograms))
+		for k, v := range lt.histograms {
+			snapshots[k] = v.Snapshot()
+		}
+		lt.lastPublished = time.Now()
+	}
+	lt.l.Unlock()
+
+	lt.gatherer.Updates <- status.Update{Key: string(statusKeyTimeInQueueLatencies), Value: snapshots}
+
+}
+
+// unwrapTimestamp returns the element wrapped by the given value along with the time the element was put into its
+// queue if the value is a timestampedElement, and the value as-is otherwise.
+func unwrapTimestamp(value any) (any, time.Time, bool) {
+
+	if te, ok := value.(timestampedElement); ok {
+		return te.Payload, te.EnqueuedAt, true
+	}
+
+	return value, time.Time{}, false
+
+}
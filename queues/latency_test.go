@@ -0,0 +1,145 @@
+package queues
+
+import (
+	"hazeltest/status"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueueTestLoopLatencyTrackerInit(t *testing.T) {
+
+	t.Log("given the latency tracker's init function")
+	{
+		t.Log("\twhen init method is invoked")
+		{
+			lt := &queueTestLoopLatencyTracker{}
+			g := status.NewGatherer()
+
+			go g.Listen()
+			lt.init(g)
+			g.StopListen()
+
+			msg := "\t\tgatherer must have been assigned"
+			if lt.gatherer == g {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			waitForStatusGatheringDone(g)
+
+			msg = "\t\tgatherer must have received empty set of latency snapshots"
+			if snapshots, ok := g.AssembleStatusCopy()[string(statusKeyTimeInQueueLatencies)].(map[string]status.HistogramSnapshot); ok && len(snapshots) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, snapshots)
+			}
+		}
+	}
+
+}
+
+func TestQueueTestLoopLatencyTrackerRecordLatency(t *testing.T) {
+
+	t.Log("given a method for recording the time an element spent in a queue")
+	{
+		t.Log("\twhen multiple goroutines record latencies for multiple queues and publish interval has not elapsed yet")
+		{
+			g := status.NewGatherer()
+			lt := &queueTestLoopLatencyTracker{}
+			go g.Listen()
+			lt.init(g)
+
+			queueNames := []string{"awesomeQueue", "anotherAwesomeQueue"}
+			wg := sync.WaitGroup{}
+			numInvocationsPerQueue := 50
+			for _, queueName := range queueNames {
+				for i := 0; i < numInvocationsPerQueue; i++ {
+					wg.Add(1)
+					go func(queueName string) {
+						defer wg.Done()
+						lt.recordLatency(queueName, 3*time.Millisecond)
+					}(queueName)
+				}
+			}
+			wg.Wait()
+
+			msg := "\t\thistogram for each queue must contain one recording per invocation for that queue"
+			for _, queueName := range queueNames {
+				if s := lt.histograms[queueName].Snapshot(); s.Count == uint64(numInvocationsPerQueue) {
+					t.Log(msg, checkMark, queueName)
+				} else {
+					t.Fatal(msg, ballotX, queueName, s.Count)
+				}
+			}
+
+			lt.publish()
+			g.StopListen()
+			waitForStatusGatheringDone(g)
+
+			msg = "\t\tstatus must reflect recorded latencies after publish"
+			snapshots := g.AssembleStatusCopy()[string(statusKeyTimeInQueueLatencies)].(map[string]status.HistogramSnapshot)
+			for _, queueName := range queueNames {
+				if s := snapshots[queueName]; s.Count == uint64(numInvocationsPerQueue) && s.MaxMicros == 3000 {
+					t.Log(msg, checkMark, queueName)
+				} else {
+					t.Fatal(msg, ballotX, queueName, s)
+				}
+			}
+		}
+		t.Log("\twhen publish interval has elapsed")
+		{
+			g := status.NewGatherer()
+			lt := &queueTestLoopLatencyTracker{}
+			go g.Listen()
+			lt.init(g)
+
+			lt.lastPublished = time.Now().Add(-latencyPublishInterval)
+			lt.recordLatency("awesomeQueue", time.Millisecond)
+
+			g.StopListen()
+			waitForStatusGatheringDone(g)
+
+			msg := "\t\tlatencies must have been published to status gatherer"
+			snapshots := g.AssembleStatusCopy()[string(statusKeyTimeInQueueLatencies)].(map[string]status.HistogramSnapshot)
+			if s := snapshots["awesomeQueue"]; s.Count == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s)
+			}
+		}
+	}
+
+}
+
+func TestUnwrapTimestamp(t *testing.T) {
+
+	t.Log("given a value polled from a queue")
+	{
+		t.Log("\twhen value is a timestamped element")
+		{
+			enqueuedAt := time.Now().Add(-time.Minute)
+			payload, ts, ok := unwrapTimestamp(timestampedElement{EnqueuedAt: enqueuedAt, Payload: "Leia Organa"})
+
+			msg := "\t\twrapped payload and enqueue time must be returned"
+			if ok && payload == "Leia Organa" && ts.Equal(enqueuedAt) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, payload, ts, ok)
+			}
+		}
+		t.Log("\twhen value is not a timestamped element")
+		{
+			payload, _, ok := unwrapTimestamp("Leia Organa")
+
+			msg := "\t\tvalue must be returned as-is"
+			if !ok && payload == "Leia Organa" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, payload, ok)
+			}
+		}
+	}
+
+}
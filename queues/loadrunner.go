@@ -10,21 +10,27 @@ import (
 	"hazeltest/client"
 	"hazeltest/hazelcastwrapper"
 	"hazeltest/loadsupport"
+	"hazeltest/state"
 	"hazeltest/status"
 )
 
 type (
 	loadRunner struct {
-		assigner        client.ConfigPropertyAssigner
-		stateList       []state
-		name            string
-		source          string
-		hzClientHandler hazelcastwrapper.HzClientHandler
-		hzQueueStore    hazelcastwrapper.QueueStore
-		l               looper[loadElement]
-		gatherer        *status.Gatherer
+		assigner            client.ConfigPropertyAssigner
+		stateList           []runnerState
+		name                string
+		source              string
+		hzClientHandler     hazelcastwrapper.HzClientHandler
+		hzQueueStore        hazelcastwrapper.QueueStore
+		stateCleanerBuilder state.SingleQueueCleanerBuilder
+		l                   looper[loadElement]
+		gatherer            *status.Gatherer
+		providerFuncs       struct {
+			loadTestLoop newLoadTestLoopFunc
+		}
 	}
-	loadElement struct {
+	newLoadTestLoopFunc func(rc *runnerConfig) (looper[loadElement], error)
+	loadElement         struct {
 		Payload string
 	}
 )
@@ -36,16 +42,32 @@ var (
 
 func init() {
 	register(&loadRunner{
-		assigner:        &client.DefaultConfigPropertyAssigner{},
-		stateList:       []state{},
-		name:            "queuesLoadRunner",
-		source:          "loadRunner",
-		hzClientHandler: &hazelcastwrapper.DefaultHzClientHandler{},
-		l:               &testLoop[loadElement]{},
+		assigner:            &client.DefaultConfigPropertyAssigner{},
+		stateList:           []runnerState{},
+		name:                "queuesLoadRunner",
+		source:              "loadRunner",
+		hzClientHandler:     &hazelcastwrapper.DefaultHzClientHandler{},
+		stateCleanerBuilder: &state.DefaultSingleQueueCleanerBuilder{},
+		providerFuncs: struct {
+			loadTestLoop newLoadTestLoopFunc
+		}{loadTestLoop: initLoadTestLoop},
 	})
 	gob.Register(loadElement{})
 }
 
+func initLoadTestLoop(rc *runnerConfig) (looper[loadElement], error) {
+
+	switch rc.loopType {
+	case putPoll:
+		return &testLoop[loadElement]{}, nil
+	case boundary:
+		return &boundaryTestLoop[loadElement]{}, nil
+	default:
+		return nil, fmt.Errorf("no such runner runnerLoopType: %s", rc.loopType)
+	}
+
+}
+
 func (r *loadRunner) getSourceName() string {
 	return r.source
 }
@@ -71,6 +93,15 @@ func (r *loadRunner) runQueueTests(hzCluster string, hzMembers []string, gathere
 
 	api.RaiseNotReady()
 
+	l, err := r.providerFuncs.loadTestLoop(c)
+	if err != nil {
+		lp.LogQueueRunnerEvent(fmt.Sprintf("aborting launch of queue load runner: unable to initialize test loop: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.l = l
+
+	r.appendState(assignTestLoopComplete)
+
 	ctx := context.TODO()
 
 	r.hzClientHandler.InitHazelcastClient(ctx, "queuesLoadRunner", hzCluster, hzMembers)
@@ -85,7 +116,7 @@ func (r *loadRunner) runQueueTests(hzCluster string, hzMembers []string, gathere
 	lp.LogQueueRunnerEvent("initialized hazelcast client", r.name, log.InfoLevel)
 	lp.LogQueueRunnerEvent("starting load test loop for queues", r.name, log.InfoLevel)
 
-	lc := &testLoopExecution[loadElement]{id: uuid.New(), runnerName: r.name, source: r.source, hzQueueStore: r.hzQueueStore, runnerConfig: c, elements: populateLoadElements(), ctx: ctx}
+	lc := &testLoopExecution[loadElement]{id: uuid.New(), runnerName: r.name, source: r.source, hzQueueStore: r.hzQueueStore, hzMapStore: &hazelcastwrapper.DefaultMapStore{Client: r.hzClientHandler.GetClient()}, stateCleanerBuilder: r.stateCleanerBuilder, runnerConfig: c, elements: populateLoadElements(), validatePayload: validateLoadElement, ctx: ctx}
 
 	r.l.init(lc, &defaultSleeper{}, r.gatherer)
 
@@ -97,7 +128,7 @@ func (r *loadRunner) runQueueTests(hzCluster string, hzMembers []string, gathere
 
 }
 
-func (r *loadRunner) appendState(s state) {
+func (r *loadRunner) appendState(s runnerState) {
 
 	r.stateList = append(r.stateList, s)
 	r.gatherer.Updates <- status.Update{Key: string(statusKeyCurrentState), Value: string(s)}
@@ -118,6 +149,18 @@ func populateLoadElements() []loadElement {
 
 }
 
+// validateLoadElement checks whether a load element retrieved from a queue carries a payload of the configured size.
+// Payloads are random strings generated by each producer, so their size is all a consumer can verify.
+func validateLoadElement(e loadElement) error {
+
+	if len(e.Payload) != payloadSizeBytes {
+		return fmt.Errorf("expected payload of %d bytes, got %d", payloadSizeBytes, len(e.Payload))
+	}
+
+	return nil
+
+}
+
 func populateLoadConfig(assigner client.ConfigPropertyAssigner) (*runnerConfig, error) {
 
 	runnerKeyPath := "queueTests.load"
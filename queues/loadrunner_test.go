@@ -16,6 +16,99 @@ func (d testLoadRunnerTestLoop) run() {
 	// No-op
 }
 
+func TestValidateLoadElement(t *testing.T) {
+
+	t.Log("given a function to validate load elements retrieved from a queue")
+	{
+		payloadSizeBytes = 8
+
+		t.Log("\twhen payload has configured size")
+		{
+			msg := "\t\tno error must be returned"
+			if err := validateLoadElement(loadElement{Payload: "abcdefgh"}); err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen payload size differs from configured size")
+		{
+			msg := "\t\terror must be returned"
+			if err := validateLoadElement(loadElement{Payload: "abc"}); err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func TestInitializeLoadElementTestLoop(t *testing.T) {
+
+	t.Log("given a function to initialize the test loop from the provided loop type")
+	{
+		t.Log("\twhen put/poll test loop type is provided")
+		{
+			l, err := initLoadTestLoop(&runnerConfig{loopType: putPoll})
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tlooper must have expected type"
+			if _, ok := l.(*testLoop[loadElement]); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen boundary test loop type is provided")
+		{
+			l, err := initLoadTestLoop(&runnerConfig{loopType: boundary})
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tlooper must have expected type"
+			if _, ok := l.(*boundaryTestLoop[loadElement]); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen unknown test loop type is provided")
+		{
+			l, err := initLoadTestLoop(&runnerConfig{loopType: "saruman"})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tlooper must be nil"
+			if l == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
 func TestRunLoadQueueTests(t *testing.T) {
 
 	t.Log("given a load runner to run queue test loops")
@@ -28,7 +121,11 @@ func TestRunLoadQueueTests(t *testing.T) {
 				returnError: true,
 				testConfig:  nil,
 			}
-			r := loadRunner{assigner: assigner, stateList: []state{}, hzQueueStore: testHzQueueStore{}, l: testLoadRunnerTestLoop{}}
+			r := loadRunner{assigner: assigner, stateList: []runnerState{}, hzQueueStore: testHzQueueStore{}, providerFuncs: struct {
+				loadTestLoop newLoadTestLoopFunc
+			}{loadTestLoop: func(rc *runnerConfig) (looper[loadElement], error) {
+				return testLoadRunnerTestLoop{}, nil
+			}}}
 
 			gatherer := status.NewGatherer()
 			go gatherer.Listen()
@@ -36,7 +133,7 @@ func TestRunLoadQueueTests(t *testing.T) {
 			r.runQueueTests(hzCluster, hzMembers, gatherer, initTestQueueStore)
 			gatherer.StopListen()
 
-			if msg, ok := checkRunnerStateTransitions([]state{start}, r.stateList); ok {
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start}, r.stateList); ok {
 				t.Log(genericMsgStateTransitions, checkMark)
 			} else {
 				t.Fatal(genericMsgStateTransitions, ballotX, msg)
@@ -66,7 +163,11 @@ func TestRunLoadQueueTests(t *testing.T) {
 					"queueTests.load.enabled": false,
 				},
 			}
-			r := loadRunner{assigner: assigner, stateList: []state{}, hzQueueStore: testHzQueueStore{}, l: testLoadRunnerTestLoop{}}
+			r := loadRunner{assigner: assigner, stateList: []runnerState{}, hzQueueStore: testHzQueueStore{}, providerFuncs: struct {
+				loadTestLoop newLoadTestLoopFunc
+			}{loadTestLoop: func(rc *runnerConfig) (looper[loadElement], error) {
+				return testLoadRunnerTestLoop{}, nil
+			}}}
 
 			gatherer := status.NewGatherer()
 			go gatherer.Listen()
@@ -75,7 +176,7 @@ func TestRunLoadQueueTests(t *testing.T) {
 			gatherer.StopListen()
 
 			latestState := populateConfigComplete
-			if msg, ok := checkRunnerStateTransitions([]state{start, latestState}, r.stateList); ok {
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start, latestState}, r.stateList); ok {
 				t.Log(genericMsgStateTransitions, checkMark)
 			} else {
 				t.Fatal(genericMsgStateTransitions, ballotX, msg)
@@ -98,7 +199,11 @@ func TestRunLoadQueueTests(t *testing.T) {
 				},
 			}
 			ch := &testHzClientHandler{}
-			r := loadRunner{assigner: assigner, stateList: []state{}, hzQueueStore: testHzQueueStore{}, l: testLoadRunnerTestLoop{}, hzClientHandler: ch}
+			r := loadRunner{assigner: assigner, stateList: []runnerState{}, hzQueueStore: testHzQueueStore{}, providerFuncs: struct {
+				loadTestLoop newLoadTestLoopFunc
+			}{loadTestLoop: func(rc *runnerConfig) (looper[loadElement], error) {
+				return testLoadRunnerTestLoop{}, nil
+			}}, hzClientHandler: ch}
 			gatherer := status.NewGatherer()
 			go gatherer.Listen()
 
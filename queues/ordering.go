@@ -0,0 +1,197 @@
+package queues
+
+import (
+	"encoding/gob"
+	"fmt"
+	"sync"
+)
+
+type (
+	orderingVerifier interface {
+		wrap(producerID string, payload any) sequencedElement
+		wrapBatch(producerID string, payloads []any) []sequencedElement
+		confirmPut(e sequencedElement)
+		check(value any) (orderingViolation, uint64)
+	}
+	// sequencedElement is what gets put into the target queue instead of the plain element when ordering verification
+	// has been enabled. Sequence numbers are assigned per producer -- i.e. per put goroutine -- without gaps, so the
+	// poll side can tell from the sequence numbers of the elements it retrieves whether elements were lost, retrieved
+	// more than once, or retrieved in a different order than the one they were put in.
+	sequencedElement struct {
+		ProducerID     string
+		SequenceNumber uint64
+		Payload        any
+	}
+	queueTestLoopOrderingVerifier struct {
+		l         sync.Mutex
+		lastPut   map[string]uint64
+		producers map[string]*producerSequence
+	}
+	// producerSequence tracks the sequence numbers the poll side has retrieved for a single producer so far. Elements
+	// up to next-1 have either been retrieved or are recorded as missing.
+	producerSequence struct {
+		next    uint64
+		missing []sequenceRange
+	}
+	sequenceRange struct {
+		from, to uint64
+	}
+	orderingViolation string
+)
+
+const (
+	noOrderingViolation orderingViolation = ""
+	sequenceGap         orderingViolation = "sequenceGap"
+	outOfOrder          orderingViolation = "outOfOrder"
+	duplicate           orderingViolation = "duplicate"
+)
+
+func init() {
+	gob.Register(sequencedElement{})
+}
+
+func newQueueTestLoopOrderingVerifier() *queueTestLoopOrderingVerifier {
+
+	return &queueTestLoopOrderingVerifier{
+		lastPut:   make(map[string]uint64),
+		producers: make(map[string]*producerSequence),
+	}
+
+}
+
+// wrap assigns the given payload the sequence number following the one of the given producer's most recent
+// successful put. Sequence numbers are only advanced by confirmPut, so a failed put doesn't leave a gap the poll
+// side would report as lost elements -- on the flip side, a put reported as failed that actually made it into the
+// queue will surface as a duplicate once the next put reuses its sequence number.
+func (v *queueTestLoopOrderingVerifier) wrap(producerID string, payload any) sequencedElement {
+
+	var sequenceNumber uint64
+	v.l.Lock()
+	{
+		sequenceNumber = v.lastPut[producerID] + 1
+	}
+	v.l.Unlock()
+
+	return sequencedElement{
+		ProducerID:     producerID,
+		SequenceNumber: sequenceNumber,
+		Payload:        payload,
+	}
+
+}
+
+// wrapBatch assigns the given payloads consecutive sequence numbers following the one of the given producer's most
+// recent successful put. Confirming the put of the batch's last element confirms the whole batch.
+func (v *queueTestLoopOrderingVerifier) wrapBatch(producerID string, payloads []any) []sequencedElement {
+
+	var lastPut uint64
+	v.l.Lock()
+	{
+		lastPut = v.lastPut[producerID]
+	}
+	v.l.Unlock()
+
+	result := make([]sequencedElement, len(payloads))
+	for i, p := range payloads {
+		result[i] = sequencedElement{
+			ProducerID:     producerID,
+			SequenceNumber: lastPut + uint64(i) + 1,
+			Payload:        p,
+		}
+	}
+
+	return result
+
+}
+
+func (v *queueTestLoopOrderingVerifier) confirmPut(e sequencedElement) {
+
+	v.l.Lock()
+	{
+		if e.SequenceNumber > v.lastPut[e.ProducerID] {
+			v.lastPut[e.ProducerID] = e.SequenceNumber
+		}
+	}
+	v.l.Unlock()
+
+}
+
+// check compares the sequence number of the given value to the one expected next for the value's producer. In case
+// of a gap, the number of skipped sequence numbers is returned along with the violation, and the skipped sequence
+// numbers are remembered, so an element retrieved later on for one of them is reported as out of order rather than
+// as duplicate (and is no longer considered lost).
+func (v *queueTestLoopOrderingVerifier) check(value any) (orderingViolation, uint64) {
+
+	e, ok := value.(sequencedElement)
+	if !ok {
+		// Element wasn't put by a test loop having ordering verification enabled, so there is nothing to verify
+		return noOrderingViolation, 0
+	}
+
+	v.l.Lock()
+	defer v.l.Unlock()
+
+	ps, ok := v.producers[e.ProducerID]
+	if !ok {
+		ps = &producerSequence{next: 1}
+		v.producers[e.ProducerID] = ps
+	}
+
+	switch {
+	case e.SequenceNumber == ps.next:
+		ps.next++
+		return noOrderingViolation, 0
+	case e.SequenceNumber > ps.next:
+		numSkipped := e.SequenceNumber - ps.next
+		ps.missing = append(ps.missing, sequenceRange{from: ps.next, to: e.SequenceNumber - 1})
+		ps.next = e.SequenceNumber + 1
+		return sequenceGap, numSkipped
+	case ps.removeMissing(e.SequenceNumber):
+		return outOfOrder, 0
+	default:
+		return duplicate, 0
+	}
+
+}
+
+func (ps *producerSequence) removeMissing(sequenceNumber uint64) bool {
+
+	for i, r := range ps.missing {
+		if sequenceNumber < r.from || sequenceNumber > r.to {
+			continue
+		}
+		var remaining []sequenceRange
+		if sequenceNumber > r.from {
+			remaining = append(remaining, sequenceRange{from: r.from, to: sequenceNumber - 1})
+		}
+		if sequenceNumber < r.to {
+			remaining = append(remaining, sequenceRange{from: sequenceNumber + 1, to: r.to})
+		}
+		ps.missing = append(ps.missing[:i], append(remaining, ps.missing[i+1:]...)...)
+		return true
+	}
+
+	return false
+
+}
+
+func evaluatePollOrdering(ov orderingVerifier, ct counterTracker, value any) error {
+
+	violation, numSkipped := ov.check(value)
+	switch violation {
+	case sequenceGap:
+		ct.increaseCounterBy(statusKeyNumLostItems, int(numSkipped))
+		return fmt.Errorf("polled element skips %d sequence number/-s of its producer", numSkipped)
+	case outOfOrder:
+		// Element has been counted as lost when it was skipped, but it turned up after all
+		ct.increaseCounterBy(statusKeyNumLostItems, -1)
+		ct.increaseCounter(statusKeyNumOutOfOrderPolls)
+		return fmt.Errorf("polled element was put before elements already polled for the same producer")
+	case duplicate:
+		ct.increaseCounter(statusKeyNumDuplicateItems)
+		return fmt.Errorf("polled element has already been polled before")
+	default:
+		return nil
+	}
+
+}
@@ -0,0 +1,318 @@
+package queues
+
+import (
+	"fmt"
+	"hazeltest/status"
+	"sync"
+	"testing"
+)
+
+func TestQueueTestLoopOrderingVerifierWrap(t *testing.T) {
+
+	t.Log("given a payload to be wrapped into a sequenced element")
+	{
+		producerID := "awesome-producer"
+
+		t.Log("\twhen producer hasn't put any element yet")
+		{
+			v := newQueueTestLoopOrderingVerifier()
+			e := v.wrap(producerID, "Luke Skywalker")
+
+			msg := "\t\tsequence number must be one"
+			if e.SequenceNumber == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, e.SequenceNumber)
+			}
+
+			msg = "\t\tproducer id and payload must have been assigned"
+			if e.ProducerID == producerID && e.Payload == "Luke Skywalker" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, e)
+			}
+		}
+
+		t.Log("\twhen put of previously wrapped element has not been confirmed")
+		{
+			v := newQueueTestLoopOrderingVerifier()
+			v.wrap(producerID, "Luke Skywalker")
+			e := v.wrap(producerID, "Han Solo")
+
+			msg := "\t\tsequence number must not have been advanced"
+			if e.SequenceNumber == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, e.SequenceNumber)
+			}
+		}
+
+		t.Log("\twhen put of previously wrapped element has been confirmed")
+		{
+			v := newQueueTestLoopOrderingVerifier()
+			v.confirmPut(v.wrap(producerID, "Luke Skywalker"))
+			e := v.wrap(producerID, "Han Solo")
+
+			msg := "\t\tsequence number must have been advanced"
+			if e.SequenceNumber == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, e.SequenceNumber)
+			}
+
+			msg = "\t\tsequence numbers of other producers must be unaffected"
+			if e := v.wrap("another-producer", "Chewbacca"); e.SequenceNumber == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, e.SequenceNumber)
+			}
+		}
+	}
+
+}
+
+func TestQueueTestLoopOrderingVerifierWrapBatch(t *testing.T) {
+
+	t.Log("given a batch of payloads to be wrapped into sequenced elements")
+	{
+		producerID := "awesome-producer"
+
+		t.Log("\twhen producer has already put elements before")
+		{
+			v := newQueueTestLoopOrderingVerifier()
+			v.confirmPut(v.wrap(producerID, "Luke Skywalker"))
+			batch := v.wrapBatch(producerID, []any{"Han Solo", "Chewbacca", "Leia Organa"})
+
+			msg := "\t\tpayloads must have been assigned consecutive sequence numbers following the last confirmed one"
+			for i, e := range batch {
+				if e.SequenceNumber == uint64(i+2) && e.ProducerID == producerID {
+					t.Log(msg, checkMark, i)
+				} else {
+					t.Fatal(msg, ballotX, i, e)
+				}
+			}
+
+			msg = "\t\tconfirming put of last element must confirm whole batch"
+			v.confirmPut(batch[len(batch)-1])
+			if e := v.wrap(producerID, "Obi-Wan Kenobi"); e.SequenceNumber == 5 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, e.SequenceNumber)
+			}
+		}
+
+		t.Log("\twhen put of batch has not been confirmed")
+		{
+			v := newQueueTestLoopOrderingVerifier()
+			v.wrapBatch(producerID, []any{"Han Solo", "Chewbacca"})
+
+			msg := "\t\tsequence number must not have been advanced"
+			if e := v.wrap(producerID, "Obi-Wan Kenobi"); e.SequenceNumber == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, e.SequenceNumber)
+			}
+		}
+	}
+
+}
+
+func TestQueueTestLoopOrderingVerifierCheck(t *testing.T) {
+
+	t.Log("given a polled value to be checked against the sequence numbers polled before for its producer")
+	{
+		producerID := "awesome-producer"
+
+		t.Log("\twhen value is not a sequenced element")
+		{
+			v := newQueueTestLoopOrderingVerifier()
+
+			msg := "\t\tno violation must be reported"
+			if violation, _ := v.check("Darth Vader"); violation == noOrderingViolation {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen sequence numbers are polled in order")
+		{
+			v := newQueueTestLoopOrderingVerifier()
+
+			msg := "\t\tno violation must be reported"
+			for i := uint64(1); i <= 5; i++ {
+				if violation, _ := v.check(sequencedElement{ProducerID: producerID, SequenceNumber: i}); violation == noOrderingViolation {
+					t.Log(msg, checkMark, i)
+				} else {
+					t.Fatal(msg, ballotX, i, violation)
+				}
+			}
+		}
+
+		t.Log("\twhen polled sequence number skips sequence numbers")
+		{
+			v := newQueueTestLoopOrderingVerifier()
+			v.check(sequencedElement{ProducerID: producerID, SequenceNumber: 1})
+
+			violation, numSkipped := v.check(sequencedElement{ProducerID: producerID, SequenceNumber: 5})
+
+			msg := "\t\tgap must be reported along with number of skipped sequence numbers"
+			if violation == sequenceGap && numSkipped == 3 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation, numSkipped)
+			}
+
+			msg = "\t\tsequence number following the one polled must be expected next"
+			if violation, _ := v.check(sequencedElement{ProducerID: producerID, SequenceNumber: 6}); violation == noOrderingViolation {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+
+			msg = "\t\tskipped sequence numbers polled later on must be reported as out of order"
+			for _, i := range []uint64{3, 2, 4} {
+				if violation, _ := v.check(sequencedElement{ProducerID: producerID, SequenceNumber: i}); violation == outOfOrder {
+					t.Log(msg, checkMark, i)
+				} else {
+					t.Fatal(msg, ballotX, i, violation)
+				}
+			}
+
+			msg = "\t\tno skipped sequence numbers must remain"
+			if len(v.producers[producerID].missing) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, v.producers[producerID].missing)
+			}
+
+			msg = "\t\tskipped sequence number polled a second time must be reported as duplicate"
+			if violation, _ := v.check(sequencedElement{ProducerID: producerID, SequenceNumber: 3}); violation == duplicate {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen sequence number is polled twice")
+		{
+			v := newQueueTestLoopOrderingVerifier()
+			v.check(sequencedElement{ProducerID: producerID, SequenceNumber: 1})
+			v.check(sequencedElement{ProducerID: producerID, SequenceNumber: 2})
+
+			msg := "\t\tduplicate must be reported"
+			if violation, _ := v.check(sequencedElement{ProducerID: producerID, SequenceNumber: 1}); violation == duplicate {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, violation)
+			}
+		}
+
+		t.Log("\twhen elements of multiple producers are polled interleaved")
+		{
+			v := newQueueTestLoopOrderingVerifier()
+
+			msg := "\t\tno violation must be reported"
+			for i := uint64(1); i <= 3; i++ {
+				for _, p := range []string{producerID, "another-producer"} {
+					if violation, _ := v.check(sequencedElement{ProducerID: p, SequenceNumber: i}); violation == noOrderingViolation {
+						t.Log(msg, checkMark, p, i)
+					} else {
+						t.Fatal(msg, ballotX, p, i, violation)
+					}
+				}
+			}
+		}
+	}
+
+}
+
+func TestEvaluatePollOrdering(t *testing.T) {
+
+	t.Log("given a function to evaluate the ordering of a polled element and report violations")
+	{
+		for _, tc := range []struct {
+			violation        orderingViolation
+			numSkipped       uint64
+			expectedCounters map[statusKey]int
+		}{
+			{sequenceGap, 42, map[statusKey]int{statusKeyNumLostItems: 42}},
+			{outOfOrder, 0, map[statusKey]int{statusKeyNumOutOfOrderPolls: 1, statusKeyNumLostItems: -1}},
+			{duplicate, 0, map[statusKey]int{statusKeyNumDuplicateItems: 1}},
+		} {
+			t.Log(fmt.Sprintf("\twhen ordering verifier reports '%s'", tc.violation))
+			{
+				ct := &queueTestLoopCountersTracker{
+					counters: make(map[statusKey]int),
+					l:        sync.Mutex{},
+					gatherer: status.NewGatherer(),
+				}
+
+				err := evaluatePollOrdering(&testOrderingVerifier{violation: tc.violation, numSkipped: tc.numSkipped}, ct, "Obi-Wan Kenobi")
+
+				msg := "\t\terror must be returned"
+				if err != nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX)
+				}
+
+				msg = "\t\tcorresponding counters must have been updated"
+				if len(ct.gatherer.Updates) != len(tc.expectedCounters) {
+					t.Fatal(msg, ballotX, ct.counters)
+				}
+				for k, v := range tc.expectedCounters {
+					if ct.counters[k] != v {
+						t.Fatal(msg, ballotX, ct.counters)
+					}
+				}
+				t.Log(msg, checkMark)
+			}
+		}
+
+		t.Log("\twhen ordering verifier does not report violation")
+		{
+			ct := &queueTestLoopCountersTracker{
+				counters: make(map[statusKey]int),
+				l:        sync.Mutex{},
+				gatherer: status.NewGatherer(),
+			}
+
+			err := evaluatePollOrdering(&testOrderingVerifier{violation: noOrderingViolation}, ct, "Obi-Wan Kenobi")
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tno counter must have been increased"
+			if len(ct.gatherer.Updates) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters)
+			}
+		}
+	}
+
+}
+
+type testOrderingVerifier struct {
+	violation  orderingViolation
+	numSkipped uint64
+}
+
+func (v *testOrderingVerifier) wrap(producerID string, payload any) sequencedElement {
+	return sequencedElement{ProducerID: producerID, SequenceNumber: 1, Payload: payload}
+}
+
+func (v *testOrderingVerifier) wrapBatch(_ string, _ []any) []sequencedElement {
+	return nil
+}
+
+func (v *testOrderingVerifier) confirmPut(_ sequencedElement) {}
+
+func (v *testOrderingVerifier) check(_ any) (orderingViolation, uint64) {
+	return v.violation, v.numSkipped
+}
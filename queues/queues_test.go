@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"github.com/hazelcast/hazelcast-go-client"
 	"hazeltest/hazelcastwrapper"
+	"hazeltest/state"
 	"sync"
+	"time"
 )
 
 type (
@@ -24,17 +26,35 @@ type (
 		queueCapacity                int
 		data                         *list.List
 		putInvocations               int
+		addWithTimeoutInvocations    int
+		addAllInvocations            int
 		pollInvocations              int
+		pollWithTimeoutInvocations   int
+		drainInvocations             int
 		destroyInvocations           int
 		remainingCapacityInvocations int
+		sizeInvocations              int
 		behavior                     *testQueueStoreBehavior
 	}
 	testQueueStoreBehavior struct {
 		returnErrorUponGetQueue, returnErrorUponRemainingCapacity, returnErrorUponPut, returnErrorUponPoll bool
+		rejectAdds                                                                                         bool
 	}
 	testQueueStoreObservations struct {
 		numInitInvocations int
 	}
+	testSingleQueueCleanerBuilder struct {
+		queueCleanerToReturn state.SingleCleaner
+	}
+	testSingleQueueCleaner struct {
+		numElementsCleanedReturnValue int
+		returnErrorUponClean          bool
+		cleanInvocations              int
+	}
+	testSleeper struct {
+		sleepKinds []string
+		l          sync.Mutex
+	}
 	testHzClientHandler struct {
 		getClientInvocations, initClientInvocations, shutdownInvocations int
 		hzClusterName                                                    string
@@ -53,16 +73,67 @@ const (
 var (
 	hzCluster                = "awesome-hz-cluster"
 	hzMembers                = []string{"awesome-hz-cluster-svc.cluster.local"}
-	expectedStatesForFullRun = []state{start, populateConfigComplete, checkEnabledComplete, raiseReadyComplete, testLoopStart, testLoopComplete}
+	expectedStatesForFullRun = []runnerState{start, populateConfigComplete, checkEnabledComplete, assignTestLoopComplete, raiseReadyComplete, testLoopStart, testLoopComplete}
 	testQueueOperationLock   sync.Mutex
 )
 
+func (b *testSingleQueueCleanerBuilder) Build(_ context.Context, _ hazelcastwrapper.QueueStore, _ hazelcastwrapper.MapStore, _ state.CleanedTracker, _ state.LastCleanedInfoHandler) (state.SingleCleaner, string) {
+
+	return b.queueCleanerToReturn, state.HzQueueService
+
+}
+
+func (c *testSingleQueueCleaner) Clean(_ string) (int, error) {
+
+	c.cleanInvocations++
+
+	if c.returnErrorUponClean {
+		return 0, errors.New("a queue cleaner is never late, nor is it early; it fails precisely when it means to")
+	}
+
+	return c.numElementsCleanedReturnValue, nil
+
+}
+
+func (s *testSleeper) sleep(_ context.Context, sc *sleepConfig, _ evaluateTimeToSleep, kind, _, _ string, _ operation) {
+
+	if sc.enabled {
+		s.l.Lock()
+		s.sleepKinds = append(s.sleepKinds, kind)
+		s.l.Unlock()
+	}
+
+}
+
+func (s *testSleeper) numSleeps(kind string) int {
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	n := 0
+	for _, k := range s.sleepKinds {
+		if k == kind {
+			n++
+		}
+	}
+
+	return n
+
+}
+
 func (d *testHzQueue) Clear(_ context.Context) error {
 	return nil
 }
 
 func (d *testHzQueue) Size(_ context.Context) (int, error) {
-	return 0, nil
+
+	testQueueOperationLock.Lock()
+	defer testQueueOperationLock.Unlock()
+
+	d.sizeInvocations++
+
+	return d.data.Len(), nil
+
 }
 
 func (d testHzQueueStore) Shutdown(_ context.Context) error {
@@ -133,9 +204,101 @@ func (d *testHzQueue) Poll(_ context.Context) (any, error) {
 	} else {
 		element = d.data.Front()
 		d.data.Remove(element)
-		return element, nil
+		return element.Value, nil
+	}
+
+}
+
+func (d *testHzQueue) AddWithTimeout(_ context.Context, element any, _ time.Duration) (bool, error) {
+
+	testQueueOperationLock.Lock()
+	defer testQueueOperationLock.Unlock()
+
+	d.addWithTimeoutInvocations++
+
+	if d.behavior.returnErrorUponPut {
+		return false, errors.New("that's no moon")
+	}
+
+	if d.behavior.rejectAdds {
+		return false, nil
+	}
+
+	d.data.PushBack(element)
+
+	return true, nil
+
+}
+
+func (d *testHzQueue) AddAll(_ context.Context, elements ...any) (bool, error) {
+
+	testQueueOperationLock.Lock()
+	defer testQueueOperationLock.Unlock()
+
+	d.addAllInvocations++
+
+	if d.behavior.returnErrorUponPut {
+		return false, errors.New("it's a trap")
+	}
+
+	if d.behavior.rejectAdds {
+		return false, nil
+	}
+
+	for _, e := range elements {
+		d.data.PushBack(e)
+	}
+
+	return true, nil
+
+}
+
+// PollWithTimeout waits for the given timeout if the queue is empty, but, unlike the real thing, doesn't return an
+// element put in the meantime.
+func (d *testHzQueue) PollWithTimeout(_ context.Context, timeout time.Duration) (any, error) {
+
+	testQueueOperationLock.Lock()
+
+	d.pollWithTimeoutInvocations++
+
+	if d.behavior.returnErrorUponPoll {
+		testQueueOperationLock.Unlock()
+		return nil, errors.New("these aren't the droids you're looking for")
+	}
+
+	if d.data.Len() == 0 {
+		testQueueOperationLock.Unlock()
+		time.Sleep(timeout)
+		return nil, nil
+	}
+
+	element := d.data.Front()
+	d.data.Remove(element)
+	testQueueOperationLock.Unlock()
+	return element.Value, nil
+
+}
+
+func (d *testHzQueue) DrainWithMaxSize(_ context.Context, maxSize int) ([]any, error) {
+
+	testQueueOperationLock.Lock()
+	defer testQueueOperationLock.Unlock()
+
+	d.drainInvocations++
+
+	if d.behavior.returnErrorUponPoll {
+		return nil, errors.New("never tell me the odds")
+	}
+
+	var result []any
+	for d.data.Len() > 0 && len(result) < maxSize {
+		element := d.data.Front()
+		d.data.Remove(element)
+		result = append(result, element.Value)
 	}
 
+	return result, nil
+
 }
 
 func (d *testHzQueue) RemainingCapacity(_ context.Context) (int, error) {
@@ -196,7 +359,7 @@ func (d *testHzClientHandler) Shutdown(_ context.Context) error {
 	return nil
 }
 
-func checkRunnerStateTransitions(expected []state, actual []state) (string, bool) {
+func checkRunnerStateTransitions(expected []runnerState, actual []runnerState) (string, bool) {
 
 	if len(expected) != len(actual) {
 		return fmt.Sprintf("expected %d state transition(-s), got %d", len(expected), len(actual)), false
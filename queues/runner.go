@@ -6,9 +6,12 @@ import (
 	"hazeltest/api"
 	"hazeltest/client"
 	"hazeltest/hazelcastwrapper"
+	"hazeltest/loadsupport"
 	"hazeltest/logging"
+	"hazeltest/state"
 	"hazeltest/status"
 	"sync"
+	"time"
 )
 
 type (
@@ -22,17 +25,33 @@ type (
 	}
 	runnerConfig struct {
 		enabled                     bool
+		role                        runnerRole
 		numQueues                   int
 		queueBaseName               string
 		appendQueueIndexToQueueName bool
 		appendClientIdToQueueName   bool
 		useQueuePrefix              bool
 		queuePrefix                 string
+		runDuration                 time.Duration
+		throughput                  *loadsupport.ThroughputConfig
+		verifyOrdering              bool
+		trackTimeInQueue            bool
+		preRunClean                 *preRunCleanConfig
+		loopType                    runnerLoopType
+		boundary                    *boundaryTestLoopConfig
 		putConfig                   *operationConfig
 		pollConfig                  *operationConfig
 	}
+	preRunCleanConfig struct {
+		enabled                  bool
+		errorBehavior            state.ErrorDuringCleanBehavior
+		applyCleanAgainThreshold bool
+		cleanAgainThresholdMs    uint64
+	}
 	operationConfig struct {
 		enabled                   bool
+		mode                      operationMode
+		timeout                   time.Duration
 		numRuns                   uint32
 		batchSize                 int
 		initialDelay              *sleepConfig
@@ -49,24 +68,90 @@ type (
 		runnerKeyPath string
 		queueBaseName string
 	}
-	state              string
+	operationMode      string
+	runnerRole         string
+	runnerLoopType     string
+	runnerState        string
 	statusKey          string
 	initQueueStoreFunc func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.QueueStore
 )
 
+type (
+	// boundaryTestLoopConfig configures the boundary test loop, which, rather than putting and polling all elements
+	// in dedicated goroutines, alternates between put and poll operations in a single goroutine per queue so as to
+	// move the number of elements held by the queue back and forth between an upper and a lower boundary. In contrast
+	// to the map boundary test loop, boundaries are expressed relative to the queue's capacity rather than to the
+	// number of source elements, so the queue can be driven close to its configured max size.
+	boundaryTestLoopConfig struct {
+		numRuns                          uint32
+		sleepBetweenOperationChains      *sleepConfig
+		sleepAfterChainAction            *sleepConfig
+		sleepUponModeChange              *sleepConfig
+		chainLength                      int
+		upper                            *boundaryDefinition
+		lower                            *boundaryDefinition
+		actionTowardsBoundaryProbability float32
+	}
+	boundaryDefinition struct {
+		queueFillPercentage float32
+		enableRandomness    bool
+	}
+)
+
 const (
-	start                  state = "start"
-	populateConfigComplete state = "populateConfigComplete"
-	checkEnabledComplete   state = "checkEnabledComplete"
-	raiseReadyComplete     state = "raiseReadyComplete"
-	testLoopStart          state = "testLoopStart"
-	testLoopComplete       state = "testLoopComplete"
+	putPoll  runnerLoopType = "putPoll"
+	boundary runnerLoopType = "boundary"
+)
+
+const (
+	start                  runnerState = "start"
+	populateConfigComplete runnerState = "populateConfigComplete"
+	checkEnabledComplete   runnerState = "checkEnabledComplete"
+	assignTestLoopComplete runnerState = "assignTestLoopComplete"
+	raiseReadyComplete     runnerState = "raiseReadyComplete"
+	testLoopStart          runnerState = "testLoopStart"
+	testLoopComplete       runnerState = "testLoopComplete"
 )
 
 const (
 	statusKeyCurrentState statusKey = "currentState"
 )
 
+const (
+	putMode              operationMode = "put"
+	offerWithTimeoutMode operationMode = "offerWithTimeout"
+	addAllMode           operationMode = "addAll"
+	pollMode             operationMode = "poll"
+	takeMode             operationMode = "take"
+	pollWithTimeoutMode  operationMode = "pollWithTimeout"
+	drainToMode          operationMode = "drainTo"
+)
+
+// Roles a queue runner can take on. In the producer and consumer roles, a runner only performs put and poll
+// operations, respectively, so Hazeltest instances deployed with different roles can share queues.
+const (
+	bothRoles runnerRole = "both"
+	producer  runnerRole = "producer"
+	consumer  runnerRole = "consumer"
+)
+
+var (
+	// Bulk operations and blocking takes would undermine the boundary test loop's control over the number of
+	// elements held by the queue, so it only supports operation modes working on single elements without blocking
+	// indefinitely
+	boundaryOperationModes = map[operationMode]struct{}{
+		putMode:              {},
+		offerWithTimeoutMode: {},
+		pollMode:             {},
+		pollWithTimeoutMode:  {},
+	}
+	runnerRoles    = []runnerRole{bothRoles, producer, consumer}
+	operationModes = map[string][]operationMode{
+		string(put):  {putMode, offerWithTimeoutMode, addAllMode},
+		string(poll): {pollMode, takeMode, pollWithTimeoutMode, drainToMode},
+	}
+)
+
 var (
 	runners               []runner
 	lp                    *logging.LogProvider
@@ -94,6 +179,13 @@ func (b runnerConfigBuilder) populateConfig() (*runnerConfig, error) {
 		})
 	})
 
+	var role runnerRole
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".role", validateRunnerRole, func(a any) {
+			role = runnerRole(a.(string))
+		})
+	})
+
 	var numQueues int
 	assignmentOps = append(assignmentOps, func() error {
 		return b.assigner.Assign(b.runnerKeyPath+".numQueues", client.ValidateInt, func(a any) {
@@ -129,12 +221,112 @@ func (b runnerConfigBuilder) populateConfig() (*runnerConfig, error) {
 		})
 	})
 
+	var useRunDuration bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".runDuration.enabled", client.ValidateBool, func(a any) {
+			useRunDuration = a.(bool)
+		})
+	})
+
+	var runDuration time.Duration
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".runDuration.duration", client.ValidateDuration, func(a any) {
+			runDuration, _ = time.ParseDuration(a.(string))
+		})
+	})
+
+	var regulateThroughput bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".throughput.enabled", client.ValidateBool, func(a any) {
+			regulateThroughput = a.(bool)
+		})
+	})
+
+	var targetOpsPerSecond int
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".throughput.targetOpsPerSecond", client.ValidateInt, func(a any) {
+			targetOpsPerSecond = a.(int)
+		})
+	})
+
+	var throughputScope loadsupport.ThroughputScope
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".throughput.scope", loadsupport.ValidateThroughputScope, func(a any) {
+			throughputScope = loadsupport.ThroughputScope(a.(string))
+		})
+	})
+
+	var verifyOrdering bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".orderingVerification.enabled", client.ValidateBool, func(a any) {
+			verifyOrdering = a.(bool)
+		})
+	})
+
+	var trackTimeInQueue bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".timeInQueueLatency.enabled", client.ValidateBool, func(a any) {
+			trackTimeInQueue = a.(bool)
+		})
+	})
+
+	var performPreRunClean bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".performPreRunClean.enabled", client.ValidateBool, func(a any) {
+			performPreRunClean = a.(bool)
+		})
+	})
+
+	var errorDuringPreRunCleanBehavior state.ErrorDuringCleanBehavior
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".performPreRunClean.errorBehavior", state.ValidateErrorDuringCleanBehavior, func(a any) {
+			errorDuringPreRunCleanBehavior = state.ErrorDuringCleanBehavior(a.(string))
+		})
+	})
+
+	var applyCleanAgainThreshold bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".performPreRunClean.cleanAgainThreshold.enabled", client.ValidateBool, func(a any) {
+			applyCleanAgainThreshold = a.(bool)
+		})
+	})
+
+	var cleanAgainThresholdMs uint64
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".performPreRunClean.cleanAgainThreshold.thresholdMs", client.ValidateInt, func(a any) {
+			cleanAgainThresholdMs = uint64(a.(int))
+		})
+	})
+
+	var loopType runnerLoopType
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".testLoop.type", validateTestLoopType, func(a any) {
+			loopType = runnerLoopType(a.(string))
+		})
+	})
+
 	for _, f := range assignmentOps {
 		if err := f(); err != nil {
 			return nil, err
 		}
 	}
 
+	if !useRunDuration {
+		runDuration = 0
+	}
+
+	loadProfile, err := loadsupport.LoadProfileConfigBuilder{Assigner: b.assigner, KeyPath: b.runnerKeyPath + ".loadProfile"}.PopulateConfig()
+	if err != nil {
+		return nil, err
+	}
+	if loadProfile.Enabled && !regulateThroughput {
+		return nil, fmt.Errorf("load profile enabled for '%s', but load profiles require throughput regulation to be enabled, too", b.runnerKeyPath)
+	}
+
+	if role != bothRoles && appendClientIdToQueueName {
+		return nil, fmt.Errorf("role '%s' configured for '%s', but queues can't be shared with other clients if client ID is appended to queue names", role, b.runnerKeyPath)
+	}
+
 	putConfig, err := b.populateOperationConfig("put")
 	if err != nil {
 		return nil, err
@@ -144,16 +336,66 @@ func (b runnerConfigBuilder) populateConfig() (*runnerConfig, error) {
 		return nil, err
 	}
 
+	// Role takes precedence over operation configs having been enabled
+	switch role {
+	case producer:
+		pollConfig.enabled = false
+	case consumer:
+		putConfig.enabled = false
+	}
+
+	// Without a put goroutine of its own, a consumer waiting for elements in take mode only stops once the run
+	// duration has elapsed
+	if role == consumer && pollConfig.enabled && pollConfig.mode == takeMode && runDuration == 0 {
+		return nil, fmt.Errorf("poll mode '%s' configured for '%s' in role '%s', but this combination requires run duration to be enabled", takeMode, b.runnerKeyPath, consumer)
+	}
+
+	var boundaryConfig *boundaryTestLoopConfig
+	if loopType == boundary {
+		if role != bothRoles {
+			return nil, fmt.Errorf("boundary test loop configured for '%s', but boundary test loop requires role '%s', got '%s'", b.runnerKeyPath, bothRoles, role)
+		}
+		if _, ok := boundaryOperationModes[putConfig.mode]; !ok {
+			return nil, fmt.Errorf("boundary test loop configured for '%s', but put mode '%s' is not supported by boundary test loop", b.runnerKeyPath, putConfig.mode)
+		}
+		if _, ok := boundaryOperationModes[pollConfig.mode]; !ok {
+			return nil, fmt.Errorf("boundary test loop configured for '%s', but poll mode '%s' is not supported by boundary test loop", b.runnerKeyPath, pollConfig.mode)
+		}
+		if bc, err := populateBoundaryTestLoopConfig(b); err != nil {
+			return nil, err
+		} else {
+			boundaryConfig = bc
+		}
+	}
+
 	return &runnerConfig{
 		enabled:                     enabled,
+		role:                        role,
 		numQueues:                   numQueues,
 		queueBaseName:               b.queueBaseName,
 		appendQueueIndexToQueueName: appendQueueIndexToQueueName,
 		appendClientIdToQueueName:   appendClientIdToQueueName,
 		useQueuePrefix:              useQueuePrefix,
 		queuePrefix:                 queuePrefix,
-		putConfig:                   putConfig,
-		pollConfig:                  pollConfig,
+		runDuration:                 runDuration,
+		verifyOrdering:              verifyOrdering,
+		trackTimeInQueue:            trackTimeInQueue,
+		loopType:                    loopType,
+		boundary:                    boundaryConfig,
+		preRunClean: &preRunCleanConfig{
+			enabled:                  performPreRunClean,
+			errorBehavior:            errorDuringPreRunCleanBehavior,
+			applyCleanAgainThreshold: applyCleanAgainThreshold,
+			cleanAgainThresholdMs:    cleanAgainThresholdMs,
+		},
+		putConfig:  putConfig,
+		pollConfig: pollConfig,
+		throughput: &loadsupport.ThroughputConfig{
+			Enabled:            regulateThroughput,
+			TargetOpsPerSecond: targetOpsPerSecond,
+			Scope:              throughputScope,
+			LoadProfile:        loadProfile,
+		},
 	}, nil
 
 }
@@ -171,6 +413,20 @@ func (b runnerConfigBuilder) populateOperationConfig(operation string) (*operati
 		})
 	})
 
+	var mode operationMode
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".mode", validateOperationMode(operationModes[operation]), func(a any) {
+			mode = operationMode(a.(string))
+		})
+	})
+
+	var timeout time.Duration
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".timeout", client.ValidateDuration, func(a any) {
+			timeout, _ = time.ParseDuration(a.(string))
+		})
+	})
+
 	var numRuns uint32
 	assignmentOps = append(assignmentOps, func() error {
 		return b.assigner.Assign(c+".numRuns", client.ValidateInt, func(a any) {
@@ -208,6 +464,8 @@ func (b runnerConfigBuilder) populateOperationConfig(operation string) (*operati
 
 	return &operationConfig{
 		enabled:                   enabled,
+		mode:                      mode,
+		timeout:                   timeout,
 		numRuns:                   numRuns,
 		batchSize:                 batchSizePoll,
 		initialDelay:              initialDelay,
@@ -217,6 +475,162 @@ func (b runnerConfigBuilder) populateOperationConfig(operation string) (*operati
 
 }
 
+func validateOperationMode(modes []operationMode) func(string, any) error {
+
+	return func(keyPath string, a any) error {
+		if err := client.ValidateString(keyPath, a); err != nil {
+			return err
+		}
+		for _, m := range modes {
+			if operationMode(a.(string)) == m {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: expected one of %v, got '%v'", keyPath, modes, a)
+	}
+
+}
+
+func validateTestLoopType(keyPath string, a any) error {
+
+	if err := client.ValidateString(keyPath, a); err != nil {
+		return err
+	}
+	switch runnerLoopType(a.(string)) {
+	case putPoll, boundary:
+		return nil
+	default:
+		return fmt.Errorf("%s: test loop type expected to be one of '%s' or '%s', got '%v'", keyPath, putPoll, boundary, a)
+	}
+
+}
+
+func populateBoundaryTestLoopConfig(b runnerConfigBuilder) (*boundaryTestLoopConfig, error) {
+
+	c := b.runnerKeyPath + ".testLoop.boundary"
+
+	var assignmentOps []func() error
+
+	var numRuns uint32
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".numRuns", client.ValidateInt, func(a any) {
+			numRuns = uint32(a.(int))
+		})
+	})
+
+	var chainLength int
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".operationChain.length", client.ValidateInt, func(a any) {
+			chainLength = a.(int)
+		})
+	})
+
+	var upperBoundaryQueueFillPercentage float32
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".operationChain.boundaryDefinition.upper.queueFillPercentage", client.ValidatePercentage, func(a any) {
+			upperBoundaryQueueFillPercentage = parsePercentage(a)
+		})
+	})
+
+	var upperBoundaryEnableRandomness bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".operationChain.boundaryDefinition.upper.enableRandomness", client.ValidateBool, func(a any) {
+			upperBoundaryEnableRandomness = a.(bool)
+		})
+	})
+
+	var lowerBoundaryQueueFillPercentage float32
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".operationChain.boundaryDefinition.lower.queueFillPercentage", client.ValidatePercentage, func(a any) {
+			lowerBoundaryQueueFillPercentage = parsePercentage(a)
+		})
+	})
+
+	var lowerBoundaryEnableRandomness bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".operationChain.boundaryDefinition.lower.enableRandomness", client.ValidateBool, func(a any) {
+			lowerBoundaryEnableRandomness = a.(bool)
+		})
+	})
+
+	var actionTowardsBoundaryProbability float32
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".operationChain.boundaryDefinition.actionTowardsBoundaryProbability", client.ValidatePercentage, func(a any) {
+			actionTowardsBoundaryProbability = parsePercentage(a)
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	if upperBoundaryQueueFillPercentage <= lowerBoundaryQueueFillPercentage {
+		return nil, fmt.Errorf("upper queue fill percentage must be greater than lower queue fill percentage, got %f (upper) and %f (lower)", upperBoundaryQueueFillPercentage, lowerBoundaryQueueFillPercentage)
+	}
+
+	sleepBetweenOperationChains, err := b.populateSleepConfig(c + ".sleeps.betweenOperationChains")
+	if err != nil {
+		return nil, err
+	}
+
+	sleepAfterChainAction, err := b.populateSleepConfig(c + ".sleeps.afterChainAction")
+	if err != nil {
+		return nil, err
+	}
+
+	sleepUponModeChange, err := b.populateSleepConfig(c + ".sleeps.uponModeChange")
+	if err != nil {
+		return nil, err
+	}
+
+	return &boundaryTestLoopConfig{
+		numRuns:                     numRuns,
+		sleepBetweenOperationChains: sleepBetweenOperationChains,
+		sleepAfterChainAction:       sleepAfterChainAction,
+		sleepUponModeChange:         sleepUponModeChange,
+		chainLength:                 chainLength,
+		upper: &boundaryDefinition{
+			queueFillPercentage: upperBoundaryQueueFillPercentage,
+			enableRandomness:    upperBoundaryEnableRandomness,
+		},
+		lower: &boundaryDefinition{
+			queueFillPercentage: lowerBoundaryQueueFillPercentage,
+			enableRandomness:    lowerBoundaryEnableRandomness,
+		},
+		actionTowardsBoundaryProbability: actionTowardsBoundaryProbability,
+	}, nil
+
+}
+
+func parsePercentage(a any) float32 {
+
+	if v, ok := a.(float64); ok {
+		return float32(v)
+	} else if v, ok := a.(float32); ok {
+		return v
+	}
+
+	return float32(a.(int))
+
+}
+
+func validateRunnerRole(keyPath string, a any) error {
+
+	if err := client.ValidateString(keyPath, a); err != nil {
+		return err
+	}
+	for _, r := range runnerRoles {
+		if runnerRole(a.(string)) == r {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: expected one of %v, got '%v'", keyPath, runnerRoles, a)
+
+}
+
 func (b runnerConfigBuilder) populateSleepConfig(configBasePath string) (*sleepConfig, error) {
 
 	var enabled bool
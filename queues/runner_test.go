@@ -7,40 +7,79 @@ import (
 	"hazeltest/status"
 	"strings"
 	"testing"
+	"time"
 )
 
 var (
 	testConfig = map[string]any{
-		runnerKeyPath + ".enabled":                                                 true,
-		runnerKeyPath + ".numQueues":                                               5,
-		runnerKeyPath + ".appendQueueIndexToQueueName":                             true,
-		runnerKeyPath + ".appendClientIdToQueueName":                               false,
-		runnerKeyPath + ".queuePrefix.enabled":                                     true,
-		runnerKeyPath + ".queuePrefix.prefix":                                      queuePrefix,
-		runnerKeyPath + ".putConfig.enabled":                                       true,
-		runnerKeyPath + ".putConfig.numRuns":                                       500,
-		runnerKeyPath + ".putConfig.batchSize":                                     50,
-		runnerKeyPath + ".putConfig.sleeps.initialDelay.enabled":                   true,
-		runnerKeyPath + ".putConfig.sleeps.initialDelay.durationMs":                2000,
-		runnerKeyPath + ".putConfig.sleeps.initialDelay.enableRandomness":          true,
-		runnerKeyPath + ".putConfig.sleeps.betweenActionBatches.enabled":           true,
-		runnerKeyPath + ".putConfig.sleeps.betweenActionBatches.durationMs":        1000,
-		runnerKeyPath + ".putConfig.sleeps.betweenActionBatches.enableRandomness":  true,
-		runnerKeyPath + ".putConfig.sleeps.betweenRuns.enabled":                    true,
-		runnerKeyPath + ".putConfig.sleeps.betweenRuns.durationMs":                 2000,
-		runnerKeyPath + ".putConfig.sleeps.betweenRuns.enableRandomness":           true,
-		runnerKeyPath + ".pollConfig.enabled":                                      true,
-		runnerKeyPath + ".pollConfig.numRuns":                                      500,
-		runnerKeyPath + ".pollConfig.batchSize":                                    50,
-		runnerKeyPath + ".pollConfig.sleeps.initialDelay.enabled":                  true,
-		runnerKeyPath + ".pollConfig.sleeps.initialDelay.durationMs":               12500,
-		runnerKeyPath + ".pollConfig.sleeps.initialDelay.enableRandomness":         true,
-		runnerKeyPath + ".pollConfig.sleeps.betweenActionBatches.enabled":          true,
-		runnerKeyPath + ".pollConfig.sleeps.betweenActionBatches.durationMs":       1000,
-		runnerKeyPath + ".pollConfig.sleeps.betweenActionBatches.enableRandomness": true,
-		runnerKeyPath + ".pollConfig.sleeps.betweenRuns.enabled":                   true,
-		runnerKeyPath + ".pollConfig.sleeps.betweenRuns.durationMs":                2000,
-		runnerKeyPath + ".pollConfig.sleeps.betweenRuns.enableRandomness":          true,
+		runnerKeyPath + ".enabled":                                                                              true,
+		runnerKeyPath + ".role":                                                                                 "both",
+		runnerKeyPath + ".numQueues":                                                                            5,
+		runnerKeyPath + ".appendQueueIndexToQueueName":                                                          true,
+		runnerKeyPath + ".appendClientIdToQueueName":                                                            false,
+		runnerKeyPath + ".queuePrefix.enabled":                                                                  true,
+		runnerKeyPath + ".queuePrefix.prefix":                                                                   queuePrefix,
+		runnerKeyPath + ".runDuration.enabled":                                                                  true,
+		runnerKeyPath + ".runDuration.duration":                                                                 "6h",
+		runnerKeyPath + ".throughput.enabled":                                                                   true,
+		runnerKeyPath + ".throughput.targetOpsPerSecond":                                                        200,
+		runnerKeyPath + ".throughput.scope":                                                                     "runner",
+		runnerKeyPath + ".loadProfile.enabled":                                                                  true,
+		runnerKeyPath + ".loadProfile.type":                                                                     "step",
+		runnerKeyPath + ".loadProfile.step.startOpsPerSecond":                                                   20,
+		runnerKeyPath + ".loadProfile.step.incrementOpsPerSecond":                                               20,
+		runnerKeyPath + ".loadProfile.step.interval":                                                            "5m",
+		runnerKeyPath + ".orderingVerification.enabled":                                                         true,
+		runnerKeyPath + ".timeInQueueLatency.enabled":                                                           true,
+		runnerKeyPath + ".performPreRunClean.enabled":                                                           true,
+		runnerKeyPath + ".performPreRunClean.errorBehavior":                                                     "fail",
+		runnerKeyPath + ".performPreRunClean.cleanAgainThreshold.enabled":                                       true,
+		runnerKeyPath + ".performPreRunClean.cleanAgainThreshold.thresholdMs":                                   30000,
+		runnerKeyPath + ".testLoop.type":                                                                        "putPoll",
+		runnerKeyPath + ".testLoop.boundary.numRuns":                                                            100,
+		runnerKeyPath + ".testLoop.boundary.sleeps.betweenOperationChains.enabled":                              true,
+		runnerKeyPath + ".testLoop.boundary.sleeps.betweenOperationChains.durationMs":                           5000,
+		runnerKeyPath + ".testLoop.boundary.sleeps.betweenOperationChains.enableRandomness":                     true,
+		runnerKeyPath + ".testLoop.boundary.sleeps.afterChainAction.enabled":                                    true,
+		runnerKeyPath + ".testLoop.boundary.sleeps.afterChainAction.durationMs":                                 50,
+		runnerKeyPath + ".testLoop.boundary.sleeps.afterChainAction.enableRandomness":                           true,
+		runnerKeyPath + ".testLoop.boundary.sleeps.uponModeChange.enabled":                                      true,
+		runnerKeyPath + ".testLoop.boundary.sleeps.uponModeChange.durationMs":                                   6000,
+		runnerKeyPath + ".testLoop.boundary.sleeps.uponModeChange.enableRandomness":                             false,
+		runnerKeyPath + ".testLoop.boundary.operationChain.length":                                              10_000,
+		runnerKeyPath + ".testLoop.boundary.operationChain.boundaryDefinition.upper.queueFillPercentage":        0.9,
+		runnerKeyPath + ".testLoop.boundary.operationChain.boundaryDefinition.upper.enableRandomness":           true,
+		runnerKeyPath + ".testLoop.boundary.operationChain.boundaryDefinition.lower.queueFillPercentage":        0.1,
+		runnerKeyPath + ".testLoop.boundary.operationChain.boundaryDefinition.lower.enableRandomness":           false,
+		runnerKeyPath + ".testLoop.boundary.operationChain.boundaryDefinition.actionTowardsBoundaryProbability": 0.75,
+		runnerKeyPath + ".putConfig.enabled":                                                                    true,
+		runnerKeyPath + ".putConfig.mode":                                                                       "offerWithTimeout",
+		runnerKeyPath + ".putConfig.timeout":                                                                    "2s",
+		runnerKeyPath + ".putConfig.numRuns":                                                                    500,
+		runnerKeyPath + ".putConfig.batchSize":                                                                  50,
+		runnerKeyPath + ".putConfig.sleeps.initialDelay.enabled":                                                true,
+		runnerKeyPath + ".putConfig.sleeps.initialDelay.durationMs":                                             2000,
+		runnerKeyPath + ".putConfig.sleeps.initialDelay.enableRandomness":                                       true,
+		runnerKeyPath + ".putConfig.sleeps.betweenActionBatches.enabled":                                        true,
+		runnerKeyPath + ".putConfig.sleeps.betweenActionBatches.durationMs":                                     1000,
+		runnerKeyPath + ".putConfig.sleeps.betweenActionBatches.enableRandomness":                               true,
+		runnerKeyPath + ".putConfig.sleeps.betweenRuns.enabled":                                                 true,
+		runnerKeyPath + ".putConfig.sleeps.betweenRuns.durationMs":                                              2000,
+		runnerKeyPath + ".putConfig.sleeps.betweenRuns.enableRandomness":                                        true,
+		runnerKeyPath + ".pollConfig.enabled":                                                                   true,
+		runnerKeyPath + ".pollConfig.mode":                                                                      "take",
+		runnerKeyPath + ".pollConfig.timeout":                                                                   "5s",
+		runnerKeyPath + ".pollConfig.numRuns":                                                                   500,
+		runnerKeyPath + ".pollConfig.batchSize":                                                                 50,
+		runnerKeyPath + ".pollConfig.sleeps.initialDelay.enabled":                                               true,
+		runnerKeyPath + ".pollConfig.sleeps.initialDelay.durationMs":                                            12500,
+		runnerKeyPath + ".pollConfig.sleeps.initialDelay.enableRandomness":                                      true,
+		runnerKeyPath + ".pollConfig.sleeps.betweenActionBatches.enabled":                                       true,
+		runnerKeyPath + ".pollConfig.sleeps.betweenActionBatches.durationMs":                                    1000,
+		runnerKeyPath + ".pollConfig.sleeps.betweenActionBatches.enableRandomness":                              true,
+		runnerKeyPath + ".pollConfig.sleeps.betweenRuns.enabled":                                                true,
+		runnerKeyPath + ".pollConfig.sleeps.betweenRuns.durationMs":                                             2000,
+		runnerKeyPath + ".pollConfig.sleeps.betweenRuns.enableRandomness":                                       true,
 	}
 	initTestQueueStore initQueueStoreFunc = func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.QueueStore {
 		return &testHzQueueStore{observations: &testQueueStoreObservations{}}
@@ -57,7 +96,7 @@ func waitForStatusGatheringDone(g *status.Gatherer) {
 
 }
 
-func latestStatePresentInGatherer(g *status.Gatherer, desiredState state) bool {
+func latestStatePresentInGatherer(g *status.Gatherer, desiredState runnerState) bool {
 
 	if value, ok := g.AssembleStatusCopy()[string(statusKeyCurrentState)]; ok && value == string(desiredState) {
 		return true
@@ -115,6 +154,266 @@ func TestPopulateConfig(t *testing.T) {
 			}
 		}
 
+		t.Log("\twhen run duration is disabled")
+		{
+			testConfigCopy := copyTestConfig()
+			testConfigCopy[runnerKeyPath+".runDuration.enabled"] = false
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\tno error should be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\trun duration must be zero"
+			if rc.runDuration == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, rc.runDuration)
+			}
+		}
+
+		t.Log("\twhen load profile is enabled, but throughput regulation is not")
+		{
+			testConfigCopy := copyTestConfig()
+			testConfigCopy[runnerKeyPath+".throughput.enabled"] = false
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror must be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen operation mode is not supported by operation")
+		{
+			for _, key := range []string{runnerKeyPath + ".putConfig.mode", runnerKeyPath + ".pollConfig.mode"} {
+				testConfigCopy := copyTestConfig()
+				// Poll mode for put config, put mode for poll config
+				if strings.Contains(key, "putConfig") {
+					testConfigCopy[key] = string(drainToMode)
+				} else {
+					testConfigCopy[key] = string(addAllMode)
+				}
+				b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+				rc, err := b.populateConfig()
+
+				msg := "\t\terror containing path of erroneous key must be returned"
+				if err != nil && rc == nil && strings.Contains(err.Error(), key) {
+					t.Log(msg, checkMark, key)
+				} else {
+					t.Fatal(msg, ballotX, key, err)
+				}
+			}
+		}
+
+		t.Log("\twhen pre-run clean error behavior is not supported")
+		{
+			testConfigCopy := copyTestConfig()
+			testConfigCopy[runnerKeyPath+".performPreRunClean.errorBehavior"] = "panic"
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror must be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen role is not supported")
+		{
+			testConfigCopy := copyTestConfig()
+			testConfigCopy[runnerKeyPath+".role"] = "spectator"
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror containing path of erroneous key must be returned"
+			if err != nil && rc == nil && strings.Contains(err.Error(), runnerKeyPath+".role") {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen producer or consumer role is configured")
+		{
+			for _, role := range []runnerRole{producer, consumer} {
+				testConfigCopy := copyTestConfig()
+				testConfigCopy[runnerKeyPath+".role"] = string(role)
+				b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+				rc, err := b.populateConfig()
+
+				msg := "\t\tno error must be returned"
+				if err == nil {
+					t.Log(msg, checkMark, role)
+				} else {
+					t.Fatal(msg, ballotX, role, err)
+				}
+
+				msg = "\t\tonly operation corresponding to role must be enabled"
+				if rc.putConfig.enabled == (role == producer) && rc.pollConfig.enabled == (role == consumer) {
+					t.Log(msg, checkMark, role)
+				} else {
+					t.Fatal(msg, ballotX, role, rc.putConfig.enabled, rc.pollConfig.enabled)
+				}
+			}
+		}
+
+		t.Log("\twhen producer or consumer role is configured along with appending client id to queue names")
+		{
+			for _, role := range []runnerRole{producer, consumer} {
+				testConfigCopy := copyTestConfig()
+				testConfigCopy[runnerKeyPath+".role"] = string(role)
+				testConfigCopy[runnerKeyPath+".appendClientIdToQueueName"] = true
+				b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+				rc, err := b.populateConfig()
+
+				msg := "\t\terror must be returned"
+				if err != nil && rc == nil {
+					t.Log(msg, checkMark, role)
+				} else {
+					t.Fatal(msg, ballotX, role)
+				}
+			}
+		}
+
+		t.Log("\twhen consumer role is configured along with take mode and run duration has been disabled")
+		{
+			testConfigCopy := copyTestConfig()
+			testConfigCopy[runnerKeyPath+".role"] = string(consumer)
+			testConfigCopy[runnerKeyPath+".pollConfig.mode"] = string(takeMode)
+			testConfigCopy[runnerKeyPath+".runDuration.enabled"] = false
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror must be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen test loop type is not supported")
+		{
+			testConfigCopy := copyTestConfig()
+			testConfigCopy[runnerKeyPath+".testLoop.type"] = "batch"
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror containing path of erroneous key must be returned"
+			if err != nil && rc == nil && strings.Contains(err.Error(), runnerKeyPath+".testLoop.type") {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen put/poll test loop is configured")
+		{
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfig}
+
+			rc, _ := b.populateConfig()
+
+			msg := "\t\tboundary test loop config must not be populated"
+			if rc.loopType == putPoll && rc.boundary == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, rc.loopType, rc.boundary)
+			}
+		}
+
+		t.Log("\twhen boundary test loop is configured")
+		{
+			testConfigCopy := assembleBoundaryTestConfig()
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tboundary test loop config must contain expected values"
+			if rc.loopType == boundary && boundaryConfigValuesAsExpected(rc.boundary, testConfigCopy) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, rc.boundary)
+			}
+		}
+
+		t.Log("\twhen boundary test loop is configured with upper boundary not greater than lower boundary")
+		{
+			testConfigCopy := assembleBoundaryTestConfig()
+			testConfigCopy[runnerKeyPath+".testLoop.boundary.operationChain.boundaryDefinition.upper.queueFillPercentage"] = 0.1
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror must be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen boundary test loop is configured along with producer or consumer role")
+		{
+			for _, role := range []runnerRole{producer, consumer} {
+				testConfigCopy := assembleBoundaryTestConfig()
+				testConfigCopy[runnerKeyPath+".role"] = string(role)
+				b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+				rc, err := b.populateConfig()
+
+				msg := "\t\terror must be returned"
+				if err != nil && rc == nil {
+					t.Log(msg, checkMark, role)
+				} else {
+					t.Fatal(msg, ballotX, role)
+				}
+			}
+		}
+
+		t.Log("\twhen boundary test loop is configured along with operation mode not supported by boundary test loop")
+		{
+			for key, mode := range map[string]operationMode{runnerKeyPath + ".putConfig.mode": addAllMode, runnerKeyPath + ".pollConfig.mode": takeMode} {
+				testConfigCopy := assembleBoundaryTestConfig()
+				testConfigCopy[key] = string(mode)
+				b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+				rc, err := b.populateConfig()
+
+				msg := "\t\terror must be returned"
+				if err != nil && rc == nil {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode)
+				}
+			}
+		}
+
 		t.Log("\twhen property parsing a property yields an error")
 		{
 			testConfigCopy := copyTestConfig()
@@ -157,17 +456,76 @@ func copyTestConfig() map[string]any {
 
 }
 
+func assembleBoundaryTestConfig() map[string]any {
+
+	testConfigCopy := copyTestConfig()
+	testConfigCopy[runnerKeyPath+".testLoop.type"] = string(boundary)
+	testConfigCopy[runnerKeyPath+".pollConfig.mode"] = string(pollWithTimeoutMode)
+
+	return testConfigCopy
+
+}
+
+func boundaryConfigValuesAsExpected(bc *boundaryTestLoopConfig, expected map[string]any) bool {
+
+	c := runnerKeyPath + ".testLoop.boundary"
+
+	return bc.numRuns == uint32(expected[c+".numRuns"].(int)) &&
+		bc.sleepBetweenOperationChains.enabled == expected[c+".sleeps.betweenOperationChains.enabled"] &&
+		bc.sleepBetweenOperationChains.durationMs == expected[c+".sleeps.betweenOperationChains.durationMs"] &&
+		bc.sleepBetweenOperationChains.enableRandomness == expected[c+".sleeps.betweenOperationChains.enableRandomness"] &&
+		bc.sleepAfterChainAction.enabled == expected[c+".sleeps.afterChainAction.enabled"] &&
+		bc.sleepAfterChainAction.durationMs == expected[c+".sleeps.afterChainAction.durationMs"] &&
+		bc.sleepAfterChainAction.enableRandomness == expected[c+".sleeps.afterChainAction.enableRandomness"] &&
+		bc.sleepUponModeChange.enabled == expected[c+".sleeps.uponModeChange.enabled"] &&
+		bc.sleepUponModeChange.durationMs == expected[c+".sleeps.uponModeChange.durationMs"] &&
+		bc.sleepUponModeChange.enableRandomness == expected[c+".sleeps.uponModeChange.enableRandomness"] &&
+		bc.chainLength == expected[c+".operationChain.length"] &&
+		bc.upper.queueFillPercentage == float32(expected[c+".operationChain.boundaryDefinition.upper.queueFillPercentage"].(float64)) &&
+		bc.upper.enableRandomness == expected[c+".operationChain.boundaryDefinition.upper.enableRandomness"] &&
+		bc.lower.queueFillPercentage == float32(expected[c+".operationChain.boundaryDefinition.lower.queueFillPercentage"].(float64)) &&
+		bc.lower.enableRandomness == expected[c+".operationChain.boundaryDefinition.lower.enableRandomness"] &&
+		bc.actionTowardsBoundaryProbability == float32(expected[c+".operationChain.boundaryDefinition.actionTowardsBoundaryProbability"].(float64))
+
+}
+
+func expectedRunDuration(expected map[string]any, runnerKeyPath string) time.Duration {
+
+	d, _ := time.ParseDuration(expected[runnerKeyPath+".runDuration.duration"].(string))
+	return d
+
+}
+
 func configValuesAsExpected(rc *runnerConfig, expected map[string]any) bool {
 
 	var runnerKeyPath = "testQueueRunner"
 
 	return rc.enabled == expected[runnerKeyPath+".enabled"] &&
+		string(rc.role) == expected[runnerKeyPath+".role"] &&
 		rc.numQueues == expected[runnerKeyPath+".numQueues"] &&
 		rc.appendQueueIndexToQueueName == expected[runnerKeyPath+".appendQueueIndexToQueueName"] &&
 		rc.appendClientIdToQueueName == expected[runnerKeyPath+".appendClientIdToQueueName"] &&
 		rc.useQueuePrefix == expected[runnerKeyPath+".queuePrefix.enabled"] &&
 		rc.queuePrefix == expected[runnerKeyPath+".queuePrefix.prefix"] &&
+		rc.runDuration == expectedRunDuration(expected, runnerKeyPath) &&
+		rc.throughput.Enabled == expected[runnerKeyPath+".throughput.enabled"] &&
+		rc.throughput.TargetOpsPerSecond == expected[runnerKeyPath+".throughput.targetOpsPerSecond"] &&
+		string(rc.throughput.Scope) == expected[runnerKeyPath+".throughput.scope"] &&
+		rc.throughput.LoadProfile.Enabled == expected[runnerKeyPath+".loadProfile.enabled"] &&
+		string(rc.throughput.LoadProfile.Type) == expected[runnerKeyPath+".loadProfile.type"] &&
+		rc.throughput.LoadProfile.Step.StartOpsPerSecond == expected[runnerKeyPath+".loadProfile.step.startOpsPerSecond"] &&
+		rc.throughput.LoadProfile.Step.IncrementOpsPerSecond == expected[runnerKeyPath+".loadProfile.step.incrementOpsPerSecond"] &&
+		rc.throughput.LoadProfile.Step.Interval == 5*time.Minute &&
+		rc.verifyOrdering == expected[runnerKeyPath+".orderingVerification.enabled"] &&
+		rc.trackTimeInQueue == expected[runnerKeyPath+".timeInQueueLatency.enabled"] &&
+		string(rc.loopType) == expected[runnerKeyPath+".testLoop.type"] &&
+		rc.preRunClean.enabled == expected[runnerKeyPath+".performPreRunClean.enabled"] &&
+		string(rc.preRunClean.errorBehavior) == expected[runnerKeyPath+".performPreRunClean.errorBehavior"] &&
+		rc.preRunClean.applyCleanAgainThreshold == expected[runnerKeyPath+".performPreRunClean.cleanAgainThreshold.enabled"] &&
+		rc.preRunClean.cleanAgainThresholdMs == uint64(expected[runnerKeyPath+".performPreRunClean.cleanAgainThreshold.thresholdMs"].(int)) &&
 		rc.putConfig.enabled == expected[runnerKeyPath+".putConfig.enabled"] &&
+		string(rc.putConfig.mode) == expected[runnerKeyPath+".putConfig.mode"] &&
+		rc.putConfig.timeout == 2*time.Second &&
 		rc.putConfig.numRuns == uint32(expected[runnerKeyPath+".putConfig.numRuns"].(int)) &&
 		rc.putConfig.batchSize == expected[runnerKeyPath+".putConfig.batchSize"] &&
 		rc.putConfig.initialDelay.enabled == expected[runnerKeyPath+".putConfig.sleeps.initialDelay.enabled"] &&
@@ -180,6 +538,8 @@ func configValuesAsExpected(rc *runnerConfig, expected map[string]any) bool {
 		rc.putConfig.sleepBetweenRuns.durationMs == expected[runnerKeyPath+".putConfig.sleeps.betweenRuns.durationMs"] &&
 		rc.putConfig.sleepBetweenRuns.enableRandomness == expected[runnerKeyPath+".putConfig.sleeps.betweenRuns.enableRandomness"] &&
 		rc.pollConfig.enabled == expected[runnerKeyPath+".pollConfig.enabled"] &&
+		string(rc.pollConfig.mode) == expected[runnerKeyPath+".pollConfig.mode"] &&
+		rc.pollConfig.timeout == 5*time.Second &&
 		rc.pollConfig.numRuns == uint32(expected[runnerKeyPath+".pollConfig.numRuns"].(int)) &&
 		rc.pollConfig.batchSize == expected[runnerKeyPath+".pollConfig.batchSize"] &&
 		rc.pollConfig.initialDelay.enabled == expected[runnerKeyPath+".pollConfig.sleeps.initialDelay.enabled"] &&
@@ -7,7 +7,10 @@ import (
 	log "github.com/sirupsen/logrus"
 	"hazeltest/client"
 	"hazeltest/hazelcastwrapper"
+	"hazeltest/loadsupport"
+	"hazeltest/state"
 	"hazeltest/status"
+	"math"
 	"math/rand"
 	"sync"
 	"time"
@@ -20,28 +23,48 @@ type (
 		run()
 	}
 	sleeper interface {
-		sleep(sc *sleepConfig, sf evaluateTimeToSleep, kind, queueName, runnerName string, o operation)
+		sleep(ctx context.Context, sc *sleepConfig, sf evaluateTimeToSleep, kind, queueName, runnerName string, o operation)
 	}
 	counterTracker interface {
-		init(gatherer *status.Gatherer)
+		init(gatherer *status.Gatherer, optionalCounters ...statusKey)
 		increaseCounter(sk statusKey)
+		increaseCounterBy(sk statusKey, delta int)
 	}
 	testLoop[t any] struct {
 		tle      *testLoopExecution[t]
 		s        sleeper
 		gatherer *status.Gatherer
 		ct       counterTracker
+		tr       *loadsupport.ThroughputRegulator
+		ov       orderingVerifier
+		lt       latencyTracker
+	}
+	// boundaryTestLoop drives each queue back and forth between an upper and a lower fill level relative to the
+	// queue's capacity, so queue behavior close to the configured max size can be tested. It shares the setup of
+	// queues, the operations on single elements, and the status reporting with the put/poll test loop.
+	boundaryTestLoop[t any] struct {
+		testLoop[t]
+	}
+	modeCache struct {
+		current                actionMode
+		forceActionTowardsMode bool
 	}
 	testLoopExecution[t any] struct {
-		id           uuid.UUID
-		runnerName   string
-		source       string
-		hzQueueStore hazelcastwrapper.QueueStore
-		runnerConfig *runnerConfig
-		elements     []t
-		ctx          context.Context
+		id                  uuid.UUID
+		runnerName          string
+		source              string
+		hzQueueStore        hazelcastwrapper.QueueStore
+		hzMapStore          hazelcastwrapper.MapStore
+		stateCleanerBuilder state.SingleQueueCleanerBuilder
+		runnerConfig        *runnerConfig
+		elements            []t
+		// Optional -- checks the content of elements a consumer retrieves beyond their type
+		validatePayload func(payload t) error
+		ctx             context.Context
+		runCtx          context.Context
 	}
 	operation                    string
+	actionMode                   string
 	defaultSleeper               struct{}
 	queueTestLoopCountersTracker struct {
 		counters map[statusKey]int
@@ -51,7 +74,10 @@ type (
 )
 
 const (
+	statusKeyRole             = "role"
+	statusKeyTestLoopType     = "testLoopType"
 	statusKeyOperationEnabled = "enabled"
+	statusKeyOperationMode    = "mode"
 	statusKeyNumQueues        = "numQueues"
 	statusKeyNumRuns          = "numRuns"
 	statusKeyBatchSize        = "batchSize"
@@ -61,6 +87,14 @@ const (
 const (
 	put  = operation("put")
 	poll = operation("poll")
+	// Not an operation on the queue itself, but used to describe sleeps in between operation chains of the
+	// boundary test loop
+	operationChain = operation("operationChain")
+)
+
+const (
+	fill  actionMode = "fill"
+	drain actionMode = "drain"
 )
 
 const (
@@ -71,6 +105,30 @@ const (
 	statusKeyNumQueueFullEvents      statusKey = "numQueueFullEvents"
 )
 
+// Only reported if runner has been configured with ordering verification
+const (
+	statusKeyNumOutOfOrderPolls statusKey = "numOutOfOrderPolls"
+	statusKeyNumLostItems       statusKey = "numLostItems"
+	statusKeyNumDuplicateItems  statusKey = "numDuplicateItems"
+)
+
+// Only reported if runner has been configured to clean queues prior to running
+const (
+	statusKeyNumPreRunCleanedItems statusKey = "numPreRunCleanedItems"
+	statusKeyNumFailedPreRunCleans statusKey = "numFailedPreRunCleans"
+)
+
+// Only reported if runner has been configured with consumer role
+const (
+	statusKeyNumUnexpectedElements statusKey = "numUnexpectedElements"
+)
+
+// Only reported if runner has been configured with run duration
+const (
+	statusKeyRemainingRunDurationSeconds statusKey = "remainingRunDurationSeconds"
+	remainingRunDurationUpdateInterval             = 5 * time.Second
+)
+
 var (
 	sleepTimeFunc evaluateTimeToSleep = func(sc *sleepConfig) int {
 		var sleepDuration int
@@ -81,16 +139,18 @@ var (
 		}
 		return sleepDuration
 	}
-	counters = []statusKey{statusKeyNumFailedPuts, statusKeyNumFailedPolls, statusKeyNumNilPolls, statusKeyNumFailedCapacityChecks, statusKeyNumQueueFullEvents}
+	counters            = []statusKey{statusKeyNumFailedPuts, statusKeyNumFailedPolls, statusKeyNumNilPolls, statusKeyNumFailedCapacityChecks, statusKeyNumQueueFullEvents}
+	orderingCounters    = []statusKey{statusKeyNumOutOfOrderPolls, statusKeyNumLostItems, statusKeyNumDuplicateItems}
+	preRunCleanCounters = []statusKey{statusKeyNumPreRunCleanedItems, statusKeyNumFailedPreRunCleans}
 )
 
-func (ct *queueTestLoopCountersTracker) init(gatherer *status.Gatherer) {
+func (ct *queueTestLoopCountersTracker) init(gatherer *status.Gatherer, optionalCounters ...statusKey) {
 	ct.gatherer = gatherer
 
 	ct.counters = make(map[statusKey]int)
 
 	initialCounterValue := 0
-	for _, v := range counters {
+	for _, v := range append(counters, optionalCounters...) {
 		ct.counters[v] = initialCounterValue
 		gatherer.Updates <- status.Update{Key: string(v), Value: initialCounterValue}
 	}
@@ -99,10 +159,16 @@ func (ct *queueTestLoopCountersTracker) init(gatherer *status.Gatherer) {
 
 func (ct *queueTestLoopCountersTracker) increaseCounter(sk statusKey) {
 
+	ct.increaseCounterBy(sk, 1)
+
+}
+
+func (ct *queueTestLoopCountersTracker) increaseCounterBy(sk statusKey, delta int) {
+
 	var newValue int
 	ct.l.Lock()
 	{
-		newValue = ct.counters[sk] + 1
+		newValue = ct.counters[sk] + delta
 		ct.counters[sk] = newValue
 	}
 	ct.l.Unlock()
@@ -116,16 +182,84 @@ func (l *testLoop[t]) init(tle *testLoopExecution[t], s sleeper, g *status.Gathe
 	l.s = s
 	l.gatherer = g
 
+	var optionalCounters []statusKey
+	if tle.runnerConfig.verifyOrdering {
+		optionalCounters = append(optionalCounters, orderingCounters...)
+	}
+	if tle.runnerConfig.preRunClean.enabled {
+		optionalCounters = append(optionalCounters, preRunCleanCounters...)
+	}
+	if tle.runnerConfig.role == consumer {
+		optionalCounters = append(optionalCounters, statusKeyNumUnexpectedElements)
+	}
+
 	ct := &queueTestLoopCountersTracker{}
-	ct.init(g)
+	ct.init(g, optionalCounters...)
 
 	l.ct = ct
+	l.ov = newQueueTestLoopOrderingVerifier()
+
+	if tle.runnerConfig.trackTimeInQueue {
+		lt := &queueTestLoopLatencyTracker{}
+		lt.init(g)
+		l.lt = lt
+	}
+
+	l.tr = loadsupport.NewThroughputRegulator(tle.runnerConfig.throughput, tle.runnerConfig.numQueues, g)
 }
 
 func (l *testLoop[t]) run() {
 
+	l.runWrapper(l.runForQueue)
+
+}
+
+// runWrapper performs the setup shared by all queue test loops -- reporting the initial status, honoring the run
+// duration, cleaning queues prior to the run if configured, and retrieving and eventually destroying the queues --
+// and invokes the given function on each queue in a dedicated goroutine.
+func (l *testLoop[t]) runWrapper(runFunc func(q hazelcastwrapper.Queue, queueName string, queueNumber int)) {
+
 	l.insertLoopWithInitialStatus()
 
+	if rc := l.tle.runnerConfig; rc.runDuration > 0 {
+		lp.LogQueueRunnerEvent(fmt.Sprintf("put and poll test loops will run for %s rather than for configured number of runs", rc.runDuration), l.tle.runnerName, log.InfoLevel)
+		// Run context only governs when the test loop stops -- operations on Hazelcast still use the test loop
+		// execution's main context, so operations in progress upon expiry of the deadline don't fail
+		runCtx, cancel := context.WithTimeout(l.tle.ctx, rc.runDuration)
+		l.tle.runCtx = runCtx
+		var reporterWg sync.WaitGroup
+		reporterWg.Add(1)
+		go func() {
+			defer reporterWg.Done()
+			reportRemainingRunDuration(runCtx, l.gatherer, remainingRunDurationUpdateInterval)
+		}()
+		// Reporter must have returned before this function does, otherwise it might attempt to send updates
+		// to a gatherer that has already stopped listening
+		defer func() {
+			cancel()
+			reporterWg.Wait()
+		}()
+	}
+
+	var stateCleaner state.SingleCleaner
+	var hzService string
+	if l.tle.runnerConfig.preRunClean.enabled {
+		stateCleaner, hzService = l.tle.stateCleanerBuilder.Build(
+			l.tle.ctx,
+			l.tle.hzQueueStore,
+			l.tle.hzMapStore,
+			&state.CleanedDataStructureTracker{G: l.gatherer},
+			&state.DefaultLastCleanedInfoHandler{
+				Ctx: l.tle.ctx,
+				Ms:  l.tle.hzMapStore,
+				Cfg: &state.LastCleanedInfoHandlerConfig{
+					UseCleanAgainThreshold: l.tle.runnerConfig.preRunClean.applyCleanAgainThreshold,
+					CleanAgainThresholdMs:  l.tle.runnerConfig.preRunClean.cleanAgainThresholdMs,
+				},
+			},
+		)
+	}
+
 	var numQueuesWg sync.WaitGroup
 	tle := l.tle
 	for i := 0; i < tle.runnerConfig.numQueues; i++ {
@@ -141,40 +275,103 @@ func (l *testLoop[t]) run() {
 				lp.LogHzEvent("unable to retrieve queue from hazelcast cluster", log.FatalLevel)
 			}
 			defer func() {
-				_ = q.Destroy(l.tle.ctx)
+				// In the producer and consumer roles, the queue is shared with other Hazeltest instances, so
+				// destroying it would discard elements those instances have yet to poll
+				if tle.runnerConfig.role == bothRoles {
+					_ = q.Destroy(l.tle.ctx)
+				}
 			}()
 			elapsed := time.Since(start).Milliseconds()
 			lp.LogTimingEvent("getQueue()", queueName, int(elapsed), log.InfoLevel)
 
-			// TODO Check whether queue should be cleaned prior to starting put and pull operations
-			// --> https://github.com/AntsInMyEy3sJohnson/hazeltest/issues/69
-
-			var putWg sync.WaitGroup
-			if tle.runnerConfig.putConfig.enabled {
-				putWg.Add(1)
-				go func() {
-					defer putWg.Done()
-					l.runElementLoop(l.tle.elements, q, put, queueName, i)
-				}()
-
-			}
-
-			var pollWg sync.WaitGroup
-			if tle.runnerConfig.pollConfig.enabled {
-				pollWg.Add(1)
-				go func() {
-					defer pollWg.Done()
-					l.runElementLoop(l.tle.elements, q, poll, queueName, i)
-				}()
+			if tle.runnerConfig.preRunClean.enabled {
+				if stateCleaner == nil || hzService == "" {
+					lp.LogQueueRunnerEvent("pre-run queue cleaning enabled, but encountered uninitialized state cleaner -- won't start test run for this queue", l.tle.runnerName, log.ErrorLevel)
+					return
+				}
+				if !l.cleanQueue(stateCleaner, queueName) {
+					return
+				}
 			}
 
-			putWg.Wait()
-			pollWg.Wait()
+			runFunc(q, queueName, i)
 		}(i)
 	}
 
 	numQueuesWg.Wait()
 
+	l.tr.Publish()
+	if tle.runnerConfig.trackTimeInQueue {
+		l.lt.publish()
+	}
+
+}
+
+func (l *testLoop[t]) runForQueue(q hazelcastwrapper.Queue, queueName string, queueNumber int) {
+
+	tle := l.tle
+
+	// Lets the poll goroutine know when no more elements are going to be put into the queue
+	putsFinishedParent := tle.ctx
+	if tle.runCtx != nil {
+		putsFinishedParent = tle.runCtx
+	}
+	putsFinished, finishPuts := context.WithCancel(putsFinishedParent)
+	defer finishPuts()
+
+	var putWg sync.WaitGroup
+	if tle.runnerConfig.putConfig.enabled {
+		putWg.Add(1)
+		go func() {
+			defer putWg.Done()
+			defer finishPuts()
+			l.runElementLoop(tle.elements, q, put, queueName, queueNumber, putsFinished)
+		}()
+	} else if tle.runnerConfig.role != consumer {
+		// In the consumer role, elements are put by other Hazeltest instances, so there is no telling
+		// when the last one has been put
+		finishPuts()
+	}
+
+	var pollWg sync.WaitGroup
+	if tle.runnerConfig.pollConfig.enabled {
+		pollWg.Add(1)
+		go func() {
+			defer pollWg.Done()
+			l.runElementLoop(tle.elements, q, poll, queueName, queueNumber, putsFinished)
+		}()
+	}
+
+	putWg.Wait()
+	pollWg.Wait()
+
+}
+
+// cleanQueue cleans the given queue using the given state cleaner and reports whether the test loop should commence
+// on the queue, which depends on the outcome of the clean as well as on the configured error behavior.
+func (l *testLoop[t]) cleanQueue(stateCleaner state.SingleCleaner, queueName string) bool {
+
+	numCleanedItems, err := stateCleaner.Clean(queueName)
+	if err != nil {
+		l.ct.increaseCounter(statusKeyNumFailedPreRunCleans)
+		configuredErrorBehavior := l.tle.runnerConfig.preRunClean.errorBehavior
+		if state.Ignore == configuredErrorBehavior {
+			lp.LogQueueRunnerEvent(fmt.Sprintf("encountered error upon attempt to clean single queue '%s' in scope of pre-run cleaning, but error behavior is '%s', so test loop will commence: %v", queueName, configuredErrorBehavior, err), l.tle.runnerName, log.WarnLevel)
+			return true
+		}
+		lp.LogQueueRunnerEvent(fmt.Sprintf("encountered error upon attempt to clean single queue '%s' in scope of pre-run cleaning and error behavior is '%s' -- won't start test run for this queue: %v", queueName, configuredErrorBehavior, err), l.tle.runnerName, log.ErrorLevel)
+		return false
+	}
+
+	if numCleanedItems > 0 {
+		l.ct.increaseCounterBy(statusKeyNumPreRunCleanedItems, numCleanedItems)
+		lp.LogQueueRunnerEvent(fmt.Sprintf("successfully cleaned %d items from queue '%s'", numCleanedItems, queueName), l.tle.runnerName, log.InfoLevel)
+	} else {
+		lp.LogQueueRunnerEvent(fmt.Sprintf("payload queue '%s' either didn't contain elements to be cleaned, or wasn't susceptible to cleaning yet", queueName), l.tle.runnerName, log.InfoLevel)
+	}
+
+	return true
+
 }
 
 func (l *testLoop[t]) insertLoopWithInitialStatus() {
@@ -182,6 +379,8 @@ func (l *testLoop[t]) insertLoopWithInitialStatus() {
 	tle := l.tle
 
 	numQueues := tle.runnerConfig.numQueues
+	l.gatherer.Updates <- status.Update{Key: statusKeyRole, Value: string(tle.runnerConfig.role)}
+	l.gatherer.Updates <- status.Update{Key: statusKeyTestLoopType, Value: string(tle.runnerConfig.loopType)}
 	l.gatherer.Updates <- status.Update{Key: statusKeyNumQueues, Value: numQueues}
 	l.gatherer.Updates <- status.Update{Key: string(put), Value: assembleInitialOperationStatus(numQueues, tle.runnerConfig.putConfig)}
 	l.gatherer.Updates <- status.Update{Key: string(poll), Value: assembleInitialOperationStatus(numQueues, tle.runnerConfig.pollConfig)}
@@ -192,6 +391,7 @@ func assembleInitialOperationStatus(numQueues int, o *operationConfig) map[strin
 
 	return map[string]any{
 		statusKeyOperationEnabled: o.enabled,
+		statusKeyOperationMode:    string(o.mode),
 		statusKeyNumRuns:          o.numRuns,
 		statusKeyBatchSize:        o.batchSize,
 		statusKeyTotalNumRuns:     uint32(numQueues) * o.numRuns,
@@ -199,27 +399,29 @@ func assembleInitialOperationStatus(numQueues int, o *operationConfig) map[strin
 
 }
 
-func (l *testLoop[t]) runElementLoop(elements []t, q hazelcastwrapper.Queue, o operation, queueName string, queueNumber int) {
+func (l *testLoop[t]) runElementLoop(elements []t, q hazelcastwrapper.Queue, o operation, queueName string, queueNumber int, putsFinished context.Context) {
 
 	var config *operationConfig
-	var queueFunction func(queue hazelcastwrapper.Queue, queueName string)
+	var queueFunction func(queue hazelcastwrapper.Queue, queueName string, queueNumber int)
 	if o == put {
 		config = l.tle.runnerConfig.putConfig
 		queueFunction = l.putElements
 	} else {
 		config = l.tle.runnerConfig.pollConfig
-		queueFunction = l.pollElements
+		queueFunction = func(queue hazelcastwrapper.Queue, queueName string, queueNumber int) {
+			l.pollElements(queue, queueName, queueNumber, putsFinished)
+		}
 	}
 
-	l.s.sleep(config.initialDelay, sleepTimeFunc, "initialDelay", queueName, l.tle.runnerName, o)
+	l.s.sleep(l.sleepContext(), config.initialDelay, sleepTimeFunc, "initialDelay", queueName, l.tle.runnerName, o)
 
 	numRuns := config.numRuns
-	for i := uint32(0); i < numRuns; i++ {
+	for i := uint32(0); l.runsRemaining(i, numRuns); i++ {
 		if i > 0 && i%queueOperationLoggingUpdateStep == 0 {
-			lp.LogQueueRunnerEvent(fmt.Sprintf("finished %d of %d %s runs for queue %s in queue goroutine %d", i, numRuns, o, queueName, queueNumber), l.tle.runnerName, log.InfoLevel)
+			lp.LogQueueRunnerEvent(fmt.Sprintf("finished %d of %s %s runs for queue %s in queue goroutine %d", i, l.describeNumRuns(numRuns), o, queueName, queueNumber), l.tle.runnerName, log.InfoLevel)
 		}
-		queueFunction(q, queueName)
-		l.s.sleep(config.sleepBetweenRuns, sleepTimeFunc, "betweenRuns", queueName, l.tle.runnerName, o)
+		queueFunction(q, queueName, queueNumber)
+		l.s.sleep(l.sleepContext(), config.sleepBetweenRuns, sleepTimeFunc, "betweenRuns", queueName, l.tle.runnerName, o)
 		lp.LogQueueRunnerEvent(fmt.Sprintf("finished %sing one set of %d tweets in queue %s after run %d of %d on queue goroutine %d", o, len(elements), queueName, i, numRuns, queueNumber), l.tle.runnerName, log.TraceLevel)
 	}
 
@@ -227,55 +429,548 @@ func (l *testLoop[t]) runElementLoop(elements []t, q hazelcastwrapper.Queue, o o
 
 }
 
-func (l *testLoop[t]) putElements(q hazelcastwrapper.Queue, queueName string) {
+func reportRemainingRunDuration(runCtx context.Context, g *status.Gatherer, updateInterval time.Duration) {
+
+	deadline, _ := runCtx.Deadline()
+
+	ticker := time.NewTicker(updateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-runCtx.Done():
+			g.Updates <- status.Update{Key: string(statusKeyRemainingRunDurationSeconds), Value: uint64(0)}
+			return
+		default:
+			g.Updates <- status.Update{Key: string(statusKeyRemainingRunDurationSeconds), Value: remainingSeconds(deadline)}
+		}
+		select {
+		case <-runCtx.Done():
+		case <-ticker.C:
+		}
+	}
+
+}
+
+func remainingSeconds(deadline time.Time) uint64 {
+
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return 0
+	}
+
+	return uint64(math.Ceil(remaining.Seconds()))
+
+}
+
+// runsRemaining tells whether the put or poll loop should start another run. If the runner has been configured with a
+// run duration, this is the case as long as the run duration hasn't elapsed, and the number of runs is ignored.
+func (l *testLoop[t]) runsRemaining(currentRun, numRuns uint32) bool {
+
+	if l.tle.runCtx != nil {
+		return l.tle.runCtx.Err() == nil
+	}
+
+	return currentRun < numRuns
+
+}
+
+// sleepContext provides the context sleeps are cut short by, which is the run's context if the runner has been
+// configured with a run duration.
+func (l *testLoop[t]) sleepContext() context.Context {
+
+	if l.tle.runCtx != nil {
+		return l.tle.runCtx
+	}
+
+	return context.Background()
+
+}
+
+func (l *testLoop[t]) describeNumRuns(numRuns uint32) string {
+
+	if l.tle.runCtx != nil {
+		return fmt.Sprintf("%s worth of", l.tle.runnerConfig.runDuration)
+	}
+
+	return fmt.Sprintf("%d", numRuns)
+
+}
+
+func (l *testLoop[t]) putElements(q hazelcastwrapper.Queue, queueName string, queueNumber int) {
 
-	elements := l.tle.elements
 	putConfig := l.tle.runnerConfig.putConfig
+	if putConfig.mode == addAllMode {
+		l.addAllElements(q, queueName, queueNumber)
+		return
+	}
+
+	elements := l.tle.elements
+	producerID := l.assembleProducerID(queueNumber)
 
 	for i := 0; i < len(elements); i++ {
-		e := elements[i]
-		if remaining, err := q.RemainingCapacity(l.tle.ctx); err != nil {
-			l.ct.increaseCounter(statusKeyNumFailedCapacityChecks)
-			lp.LogQueueRunnerEvent(fmt.Sprintf("unable to check remaining capacity for queue with name '%s'", queueName), l.tle.runnerName, log.WarnLevel)
-		} else if remaining == 0 {
-			l.ct.increaseCounter(statusKeyNumQueueFullEvents)
-			lp.LogQueueRunnerEvent(fmt.Sprintf("no capacity left in queue '%s' -- won't execute put", queueName), l.tle.runnerName, log.WarnLevel)
-		} else {
-			err := q.Put(l.tle.ctx, e)
+		l.putSingleElement(q, queueName, queueNumber, producerID, elements[i])
+		if i > 0 && i%putConfig.batchSize == 0 {
+			l.s.sleep(l.sleepContext(), putConfig.sleepBetweenActionBatches, sleepTimeFunc, "betweenActionBatches", queueName, l.tle.runnerName, "put")
+		}
+	}
+
+}
+
+// putSingleElement puts the given element into the given queue and reports whether the element was added.
+func (l *testLoop[t]) putSingleElement(q hazelcastwrapper.Queue, queueName string, queueNumber int, producerID string, element any) bool {
+
+	putConfig := l.tle.runnerConfig.putConfig
+	verifyOrdering := l.tle.runnerConfig.verifyOrdering
+
+	e := element
+	var se sequencedElement
+	if verifyOrdering {
+		se = l.ov.wrap(producerID, e)
+		e = se
+	}
+
+	// Offers wait for capacity to become available by themselves, so checking for remaining capacity beforehand
+	// would only defeat their purpose
+	if putConfig.mode != offerWithTimeoutMode && !l.capacityAvailable(q, queueName, 1) {
+		return false
+	}
+
+	l.tr.Await(l.tle.ctx, queueNumber)
+	added, err := l.putElement(q, l.stampIfEnabled(e))
+	if err != nil {
+		l.ct.increaseCounter(statusKeyNumFailedPuts)
+		lp.LogQueueRunnerEvent(fmt.Sprintf("unable to put tweet item into queue '%s': %s", queueName, err), l.tle.runnerName, log.WarnLevel)
+		return false
+	}
+	if !added {
+		l.ct.increaseCounter(statusKeyNumQueueFullEvents)
+		lp.LogQueueRunnerEvent(fmt.Sprintf("no capacity left in queue '%s' within offer timeout of %s", queueName, putConfig.timeout), l.tle.runnerName, log.WarnLevel)
+		return false
+	}
+
+	if verifyOrdering {
+		l.ov.confirmPut(se)
+	}
+	lp.LogQueueRunnerEvent(fmt.Sprintf("successfully wrote value to queue '%s'", queueName), l.tle.runnerName, log.TraceLevel)
+	return true
+
+}
+
+func (l *testLoop[t]) putElement(q hazelcastwrapper.Queue, element any) (bool, error) {
+
+	putConfig := l.tle.runnerConfig.putConfig
+	if putConfig.mode == offerWithTimeoutMode {
+		return q.AddWithTimeout(l.tle.ctx, element, putConfig.timeout)
+	}
+
+	return true, q.Put(l.tle.ctx, element)
+
+}
+
+// addAllElements puts the test loop's elements into the given queue in bulk, using one operation per batch of
+// elements, so the batch size determines the number of elements added at once rather than only the number of
+// elements after which to sleep.
+func (l *testLoop[t]) addAllElements(q hazelcastwrapper.Queue, queueName string, queueNumber int) {
+
+	elements := l.tle.elements
+	putConfig := l.tle.runnerConfig.putConfig
+	verifyOrdering := l.tle.runnerConfig.verifyOrdering
+	producerID := l.assembleProducerID(queueNumber)
+
+	for start := 0; start < len(elements); start += putConfig.batchSize {
+		end := min(start+putConfig.batchSize, len(elements))
+		batch := make([]any, 0, end-start)
+		for _, e := range elements[start:end] {
+			batch = append(batch, e)
+		}
+		var sequenced []sequencedElement
+		if verifyOrdering {
+			sequenced = l.ov.wrapBatch(producerID, batch)
+			for i, se := range sequenced {
+				batch[i] = se
+			}
+		}
+		if l.capacityAvailable(q, queueName, len(batch)) {
+			l.tr.Await(l.tle.ctx, queueNumber)
+			for i, e := range batch {
+				batch[i] = l.stampIfEnabled(e)
+			}
+			added, err := q.AddAll(l.tle.ctx, batch...)
 			if err != nil {
-				l.ct.increaseCounter(statusKeyNumFailedPuts)
-				lp.LogQueueRunnerEvent(fmt.Sprintf("unable to put tweet item into queue '%s': %s", queueName, err), l.tle.runnerName, log.WarnLevel)
+				l.ct.increaseCounterBy(statusKeyNumFailedPuts, len(batch))
+				lp.LogQueueRunnerEvent(fmt.Sprintf("unable to add batch of %d tweet items to queue '%s': %s", len(batch), queueName, err), l.tle.runnerName, log.WarnLevel)
+			} else if !added {
+				l.ct.increaseCounter(statusKeyNumQueueFullEvents)
+				lp.LogQueueRunnerEvent(fmt.Sprintf("queue '%s' rejected batch of %d elements", queueName, len(batch)), l.tle.runnerName, log.WarnLevel)
 			} else {
-				lp.LogQueueRunnerEvent(fmt.Sprintf("successfully wrote value to queue '%s'", queueName), l.tle.runnerName, log.TraceLevel)
+				if verifyOrdering {
+					l.ov.confirmPut(sequenced[len(sequenced)-1])
+				}
+				lp.LogQueueRunnerEvent(fmt.Sprintf("successfully added batch of %d elements to queue '%s'", len(batch), queueName), l.tle.runnerName, log.TraceLevel)
 			}
 		}
-		if i > 0 && i%putConfig.batchSize == 0 {
-			l.s.sleep(putConfig.sleepBetweenActionBatches, sleepTimeFunc, "betweenActionBatches", queueName, l.tle.runnerName, "put")
-		}
+		l.s.sleep(l.sleepContext(), putConfig.sleepBetweenActionBatches, sleepTimeFunc, "betweenActionBatches", queueName, l.tle.runnerName, "put")
+	}
+
+}
+
+// capacityAvailable checks whether the given queue can take the given number of elements, increasing the
+// corresponding counter if it cannot or if the check fails.
+func (l *testLoop[t]) capacityAvailable(q hazelcastwrapper.Queue, queueName string, numElements int) bool {
+
+	if remaining, err := q.RemainingCapacity(l.tle.ctx); err != nil {
+		l.ct.increaseCounter(statusKeyNumFailedCapacityChecks)
+		lp.LogQueueRunnerEvent(fmt.Sprintf("unable to check remaining capacity for queue with name '%s'", queueName), l.tle.runnerName, log.WarnLevel)
+		return false
+	} else if remaining < numElements {
+		l.ct.increaseCounter(statusKeyNumQueueFullEvents)
+		lp.LogQueueRunnerEvent(fmt.Sprintf("not enough capacity left in queue '%s' for %d element/-s -- won't execute put", queueName, numElements), l.tle.runnerName, log.WarnLevel)
+		return false
+	}
+
+	return true
+
+}
+
+func (l *testLoop[t]) stampIfEnabled(element any) any {
+
+	if l.tle.runnerConfig.trackTimeInQueue {
+		// Taken as late as possible, so the time spent waiting for throughput regulation doesn't count towards the
+		// time the element spends in the queue
+		return timestampedElement{EnqueuedAt: time.Now(), Payload: element}
 	}
 
+	return element
+
 }
 
-func (l *testLoop[t]) pollElements(q hazelcastwrapper.Queue, queueName string) {
+// pollElements retrieves as many elements from the given queue as the test loop has elements. The given context is
+// expected to be cancelled once the put goroutine working on the same queue has finished, which matters only for
+// the take mode (see takeElement).
+func (l *testLoop[t]) pollElements(q hazelcastwrapper.Queue, queueName string, queueNumber int, putsFinished context.Context) {
 
 	pollConfig := l.tle.runnerConfig.pollConfig
+	if pollConfig.mode == drainToMode {
+		l.drainElements(q, queueName, queueNumber)
+		return
+	}
 
 	for i := 0; i < len(l.tle.elements); i++ {
-		valueFromQueue, err := q.Poll(l.tle.ctx)
+		l.pollSingleElement(q, queueName, queueNumber, putsFinished)
+		if i > 0 && i%pollConfig.batchSize == 0 {
+			l.s.sleep(l.sleepContext(), pollConfig.sleepBetweenActionBatches, sleepTimeFunc, "betweenActionBatches", queueName, l.tle.runnerName, "poll")
+		}
+	}
+
+}
+
+// pollSingleElement retrieves one element from the given queue and reports whether an element was retrieved.
+func (l *testLoop[t]) pollSingleElement(q hazelcastwrapper.Queue, queueName string, queueNumber int, putsFinished context.Context) bool {
+
+	l.tr.Await(l.tle.ctx, queueNumber)
+	valueFromQueue, err := l.pollElement(q, putsFinished)
+	if err != nil {
+		l.ct.increaseCounter(statusKeyNumFailedPolls)
+		lp.LogQueueRunnerEvent(fmt.Sprintf("unable to poll tweet from queue '%s': %s", queueName, err), l.tle.runnerName, log.WarnLevel)
+		return false
+	}
+	if valueFromQueue == nil {
+		l.ct.increaseCounter(statusKeyNumNilPolls)
+		lp.LogQueueRunnerEvent(fmt.Sprintf("nothing to poll from queue '%s'", queueName), l.tle.runnerName, log.TraceLevel)
+		return false
+	}
+
+	lp.LogQueueRunnerEvent(fmt.Sprintf("successfully retrieved value from queue '%s'", queueName), l.tle.runnerName, log.TraceLevel)
+	l.evaluatePolledValue(queueName, valueFromQueue)
+	return true
+
+}
+
+func (l *testLoop[t]) pollElement(q hazelcastwrapper.Queue, putsFinished context.Context) (any, error) {
+
+	pollConfig := l.tle.runnerConfig.pollConfig
+	switch pollConfig.mode {
+	case takeMode:
+		return l.takeElement(q, putsFinished)
+	case pollWithTimeoutMode:
+		return q.PollWithTimeout(l.tle.ctx, pollConfig.timeout)
+	default:
+		return q.Poll(l.tle.ctx)
+	}
+
+}
+
+// takeElement waits until an element becomes available in the given queue for as long as the put goroutine working
+// on the same queue might still put elements, and returns nil once the put goroutine has finished and the queue has
+// been drained. Rather than by means of a blocking take, which would have to be cancelled on the client side once the
+// put goroutine has finished -- losing the element in case the cluster had already handed it out to the cancelled
+// take --, waiting is implemented as a loop of polls with timeout, none of which ever gets cancelled. In the consumer
+// role, there is no put goroutine, so waiting continues until the run duration has elapsed.
+func (l *testLoop[t]) takeElement(q hazelcastwrapper.Queue, putsFinished context.Context) (any, error) {
+
+	for {
+		// Evaluated prior to polling, so the queue is polled at least once more after the put goroutine has finished
+		finished := putsFinished.Err() != nil
+		v, err := q.PollWithTimeout(l.tle.ctx, l.tle.runnerConfig.pollConfig.timeout)
+		if err != nil || v != nil || finished {
+			return v, err
+		}
+	}
+
+}
+
+// drainElements retrieves elements from the given queue in bulk, using one operation per batch of elements -- the
+// counterpart to addAllElements.
+func (l *testLoop[t]) drainElements(q hazelcastwrapper.Queue, queueName string, queueNumber int) {
+
+	pollConfig := l.tle.runnerConfig.pollConfig
+
+	for start := 0; start < len(l.tle.elements); start += pollConfig.batchSize {
+		l.tr.Await(l.tle.ctx, queueNumber)
+		values, err := q.DrainWithMaxSize(l.tle.ctx, min(pollConfig.batchSize, len(l.tle.elements)-start))
 		if err != nil {
 			l.ct.increaseCounter(statusKeyNumFailedPolls)
-			lp.LogQueueRunnerEvent(fmt.Sprintf("unable to poll tweet from queue '%s': %s", queueName, err), l.tle.runnerName, log.WarnLevel)
-		} else if valueFromQueue == nil {
+			lp.LogQueueRunnerEvent(fmt.Sprintf("unable to drain tweets from queue '%s': %s", queueName, err), l.tle.runnerName, log.WarnLevel)
+		} else if len(values) == 0 {
 			l.ct.increaseCounter(statusKeyNumNilPolls)
-			lp.LogQueueRunnerEvent(fmt.Sprintf("nothing to poll from queue '%s'", queueName), l.tle.runnerName, log.TraceLevel)
+			lp.LogQueueRunnerEvent(fmt.Sprintf("nothing to drain from queue '%s'", queueName), l.tle.runnerName, log.TraceLevel)
 		} else {
-			lp.LogQueueRunnerEvent(fmt.Sprintf("successfully retrieved value from queue '%s'", queueName), l.tle.runnerName, log.TraceLevel)
+			lp.LogQueueRunnerEvent(fmt.Sprintf("successfully drained %d values from queue '%s'", len(values), queueName), l.tle.runnerName, log.TraceLevel)
+			for _, v := range values {
+				l.evaluatePolledValue(queueName, v)
+			}
 		}
-		if i > 0 && i%pollConfig.batchSize == 0 {
-			l.s.sleep(pollConfig.sleepBetweenActionBatches, sleepTimeFunc, "betweenActionBatches", queueName, l.tle.runnerName, "poll")
+		l.s.sleep(l.sleepContext(), pollConfig.sleepBetweenActionBatches, sleepTimeFunc, "betweenActionBatches", queueName, l.tle.runnerName, "poll")
+	}
+
+}
+
+func (l *testLoop[t]) evaluatePolledValue(queueName string, valueFromQueue any) {
+
+	// Elements might have been timestamped by a test loop sharing the queue even if this one doesn't
+	// track the time elements spend in the queue, so they're always unwrapped
+	value, enqueuedAt, timestamped := unwrapTimestamp(valueFromQueue)
+	if timestamped && l.tle.runnerConfig.trackTimeInQueue {
+		l.lt.recordLatency(queueName, time.Since(enqueuedAt))
+	}
+	if l.tle.runnerConfig.verifyOrdering {
+		if err := evaluatePollOrdering(l.ov, l.ct, value); err != nil {
+			lp.LogQueueRunnerEvent(fmt.Sprintf("ordering verification failed for queue '%s': %v", queueName, err), l.tle.runnerName, log.WarnLevel)
+		}
+	}
+	if l.tle.runnerConfig.role == consumer {
+		l.evaluatePayload(queueName, value)
+	}
+
+}
+
+// evaluatePayload checks whether the given value, which a consumer has retrieved from a queue, carries an element of
+// the kind its test loop works with and, if the runner provides a way of doing so, whether the element's content is
+// what producers running the same runner put. Other clients might have put elements of any kind into a shared queue,
+// e.g. when producer and consumer instances have been deployed with different runners for the same queue names, and
+// elements might have been corrupted on their way through the cluster.
+func (l *testLoop[t]) evaluatePayload(queueName string, value any) {
+
+	payload := value
+	if se, ok := value.(sequencedElement); ok {
+		payload = se.Payload
+	}
+
+	p, ok := payload.(t)
+	if !ok {
+		l.ct.increaseCounter(statusKeyNumUnexpectedElements)
+		lp.LogQueueRunnerEvent(fmt.Sprintf("retrieved element of unexpected type %T from queue '%s'", payload, queueName), l.tle.runnerName, log.WarnLevel)
+		return
+	}
+
+	if l.tle.validatePayload == nil {
+		return
+	}
+
+	if err := l.tle.validatePayload(p); err != nil {
+		l.ct.increaseCounter(statusKeyNumUnexpectedElements)
+		lp.LogQueueRunnerEvent(fmt.Sprintf("retrieved element with unexpected content from queue '%s': %v", queueName, err), l.tle.runnerName, log.WarnLevel)
+	}
+
+}
+
+func (l *boundaryTestLoop[t]) run() {
+
+	l.runWrapper(l.runForQueue)
+
+}
+
+func (l *boundaryTestLoop[t]) runForQueue(q hazelcastwrapper.Queue, queueName string, queueNumber int) {
+
+	bc := l.tle.runnerConfig.boundary
+	mc := &modeCache{}
+	producerID := l.assembleProducerID(queueNumber)
+	nextElementIndex := 0
+
+	for i := uint32(0); l.runsRemaining(i, bc.numRuns); i++ {
+		if i > 0 && i%queueOperationLoggingUpdateStep == 0 {
+			lp.LogQueueRunnerEvent(fmt.Sprintf("finished %d of %s operation chains for queue %s in queue goroutine %d", i, l.describeNumRuns(bc.numRuns), queueName, queueNumber), l.tle.runnerName, log.InfoLevel)
 		}
+		l.s.sleep(l.sleepContext(), bc.sleepBetweenOperationChains, sleepTimeFunc, "betweenOperationChains", queueName, l.tle.runnerName, operationChain)
+		l.runOperationChain(q, queueName, queueNumber, producerID, mc, &nextElementIndex)
 	}
 
+	lp.LogQueueRunnerEvent(fmt.Sprintf("boundary test loop done on queue '%s' in queue goroutine %d", queueName, queueNumber), l.tle.runnerName, log.InfoLevel)
+
+}
+
+// runOperationChain performs the configured number of put and poll actions on the given queue. The queue's fill
+// level is queried only once at the beginning of the chain and then tracked locally, so the chain can decide upon
+// the next action without having to query the cluster each time.
+func (l *boundaryTestLoop[t]) runOperationChain(q hazelcastwrapper.Queue, queueName string, queueNumber int, producerID string, mc *modeCache, nextElementIndex *int) {
+
+	bc := l.tle.runnerConfig.boundary
+
+	size, capacity, err := l.queryFillLevel(q)
+	if err != nil {
+		l.ct.increaseCounter(statusKeyNumFailedCapacityChecks)
+		lp.LogQueueRunnerEvent(fmt.Sprintf("unable to determine fill level of queue '%s' -- skipping operation chain: %v", queueName, err), l.tle.runnerName, log.WarnLevel)
+		return
+	}
+
+	upperBoundary, lowerBoundary := evaluateQueueFillBoundaries(bc)
+	lp.LogQueueRunnerEvent(fmt.Sprintf("starting operation chain of length %d for queue '%s' holding %d of %d elements, using upper boundary %f and lower boundary %f", bc.chainLength, queueName, size, capacity, upperBoundary, lowerBoundary), l.tle.runnerName, log.InfoLevel)
+
+	for j := 0; j < bc.chainLength; j++ {
+
+		if l.tle.runCtx != nil && l.tle.runCtx.Err() != nil {
+			lp.LogQueueRunnerEvent(fmt.Sprintf("run duration elapsed -- aborting operation chain for queue '%s' in chain position %d", queueName, j), l.tle.runnerName, log.InfoLevel)
+			return
+		}
+
+		nextMode, forceActionTowardsMode := l.checkForModeChange(upperBoundary, lowerBoundary, size, capacity, mc.current)
+		if nextMode != mc.current && mc.current != "" {
+			lp.LogQueueRunnerEvent(fmt.Sprintf("detected mode change from '%s' to '%s' for queue '%s' in chain position %d with %d elements in queue", mc.current, nextMode, queueName, j, size), l.tle.runnerName, log.InfoLevel)
+			l.s.sleep(l.sleepContext(), bc.sleepUponModeChange, sleepTimeFunc, "uponModeChange", queueName, l.tle.runnerName, operationChain)
+		}
+		mc.current, mc.forceActionTowardsMode = nextMode, forceActionTowardsMode
+
+		action := determineNextQueueAction(mc, bc.actionTowardsBoundaryProbability, size, capacity)
+		lp.LogQueueRunnerEvent(fmt.Sprintf("for queue '%s', current mode is '%s', and next action was determined to be '%s'", queueName, mc.current, action), l.tle.runnerName, log.TraceLevel)
+
+		if action == put {
+			element := l.tle.elements[*nextElementIndex]
+			*nextElementIndex = (*nextElementIndex + 1) % len(l.tle.elements)
+			if l.putSingleElement(q, queueName, queueNumber, producerID, element) {
+				size++
+			}
+		} else if l.pollSingleElement(q, queueName, queueNumber, l.tle.ctx) {
+			size--
+		}
+
+		l.s.sleep(l.sleepContext(), bc.sleepAfterChainAction, sleepTimeFunc, "afterChainAction", queueName, l.tle.runnerName, action)
+
+	}
+
+}
+
+// queryFillLevel returns the number of elements currently held by the given queue along with its capacity. For
+// queues without a configured max size, the capacity is practically unlimited, so the upper boundary is never
+// reached.
+func (l *boundaryTestLoop[t]) queryFillLevel(q hazelcastwrapper.Queue) (int, int, error) {
+
+	size, err := q.Size(l.tle.ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	remaining, err := q.RemainingCapacity(l.tle.ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return size, size + remaining, nil
+
+}
+
+func evaluateQueueFillBoundaries(bc *boundaryTestLoopConfig) (float32, float32) {
+
+	upper := float32(0)
+	if bc.upper.enableRandomness {
+		upper = bc.upper.queueFillPercentage + rand.Float32()*(1-bc.upper.queueFillPercentage)
+	} else {
+		upper = bc.upper.queueFillPercentage
+	}
+
+	lower := float32(0)
+	if bc.lower.enableRandomness {
+		lower = rand.Float32() * bc.lower.queueFillPercentage
+	} else {
+		lower = bc.lower.queueFillPercentage
+	}
+
+	return upper, lower
+
+}
+
+func (l *boundaryTestLoop[t]) checkForModeChange(upperBoundary, lowerBoundary float32, size, capacity int, currentMode actionMode) (actionMode, bool) {
+
+	currentNumElements := float64(size)
+	maxNumElements := float64(capacity)
+
+	if currentNumElements <= math.Round(maxNumElements*float64(lowerBoundary)) {
+		lp.LogQueueRunnerEvent(fmt.Sprintf("enforcing 'fill' mode -- current number of elements: %d; capacity: %d", size, capacity), l.tle.runnerName, log.TraceLevel)
+		return fill, true
+	}
+
+	if currentNumElements >= math.Round(maxNumElements*float64(upperBoundary)) {
+		lp.LogQueueRunnerEvent(fmt.Sprintf("enforcing 'drain' mode -- current number of elements: %d; capacity: %d", size, capacity), l.tle.runnerName, log.TraceLevel)
+		return drain, true
+	}
+
+	if currentMode == "" {
+		return fill, false
+	}
+
+	return currentMode, false
+
+}
+
+// determineNextQueueAction chooses between put and poll depending on the current mode. In contrast to the map
+// boundary test loop, there is no read action in between, so every action changes the queue's fill level.
+func determineNextQueueAction(mc *modeCache, actionProbability float32, size, capacity int) operation {
+
+	// Polling from an empty queue or putting into a full one would not change the fill level
+	if size <= 0 {
+		return put
+	}
+	if size >= capacity {
+		return poll
+	}
+
+	var hit bool
+	if mc.forceActionTowardsMode {
+		hit = true
+	} else {
+		hit = rand.Float32() < actionProbability
+	}
+
+	if mc.current == drain {
+		if hit {
+			return poll
+		}
+		return put
+	}
+
+	if hit {
+		return put
+	}
+	return poll
+
+}
+
+// assembleProducerID identifies the put goroutine of the given queue goroutine across all Hazeltest instances, so
+// sequence numbers assigned by different put goroutines sharing a queue can be told apart on the poll side.
+func (l *testLoop[t]) assembleProducerID(queueNumber int) string {
+
+	return fmt.Sprintf("%s-%s-%d", client.ID(), l.tle.runnerName, queueNumber)
+
 }
 
 func (l *testLoop[t]) assembleQueueName(queueIndex int) string {
@@ -298,13 +993,21 @@ func (l *testLoop[t]) assembleQueueName(queueIndex int) string {
 
 }
 
-func (s *defaultSleeper) sleep(sc *sleepConfig, sf evaluateTimeToSleep, kind, queueName, runnerName string, o operation) {
+// sleep returns early once the given context is done, so a run duration elapsing in the middle of a sleep does not
+// make the test loop overshoot its run duration by the remainder of the sleep.
+func (s *defaultSleeper) sleep(ctx context.Context, sc *sleepConfig, sf evaluateTimeToSleep, kind, queueName, runnerName string, o operation) {
 
 	if sc.enabled {
 		sleepDuration := sf(sc)
 		lp.LogQueueRunnerEvent(fmt.Sprintf("sleeping for %d milliseconds for kind '%s' on queue '%s' for operation '%s'",
 			sleepDuration, kind, queueName, o), runnerName, log.TraceLevel)
-		time.Sleep(time.Duration(sleepDuration) * time.Millisecond)
+		timer := time.NewTimer(time.Duration(sleepDuration) * time.Millisecond)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			lp.LogQueueRunnerEvent(fmt.Sprintf("sleep for kind '%s' on queue '%s' cut short because run duration has elapsed", kind, queueName), runnerName, log.TraceLevel)
+		case <-timer.C:
+		}
 	}
 
 }
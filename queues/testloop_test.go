@@ -2,12 +2,18 @@ package queues
 
 import (
 	"container/list"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"hazeltest/hazelcastwrapper"
+	"hazeltest/loadsupport"
+	"hazeltest/state"
 	"hazeltest/status"
+	"math"
 	"sync"
 	"testing"
+	"time"
 )
 
 var (
@@ -72,6 +78,45 @@ func TestQueueTestLoopCountersTrackerInit(t *testing.T) {
 				}
 			}
 		}
+		t.Log("\twhen init method is invoked with optional counters")
+		{
+			ct := &queueTestLoopCountersTracker{}
+			g := status.NewGatherer()
+
+			go g.Listen()
+			ct.init(g, orderingCounters...)
+			g.StopListen()
+
+			waitForStatusGatheringDone(g)
+			msg := "\t\tgatherer must have received both base and optional counters with initial values"
+			statusCopy := g.AssembleStatusCopy()
+
+			for _, v := range append(counters, orderingCounters...) {
+				if ok, detail := expectedStatusPresent(statusCopy, v, 0); ok {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, detail)
+				}
+			}
+		}
+		t.Log("\twhen init method is invoked without optional counters")
+		{
+			ct := &queueTestLoopCountersTracker{}
+			g := status.NewGatherer()
+
+			go g.Listen()
+			ct.init(g)
+			g.StopListen()
+
+			msg := "\t\toptional counters must not have been initialized"
+			for _, v := range orderingCounters {
+				if _, ok := ct.counters[v]; !ok {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, v)
+				}
+			}
+		}
 	}
 
 }
@@ -139,6 +184,31 @@ func TestQueueTestLoopCountersTrackerIncreaseCounter(t *testing.T) {
 				t.Fatal(msg, ballotX)
 			}
 		}
+		t.Log("\twhen counter is increased by more than one")
+		{
+			ct := &queueTestLoopCountersTracker{
+				counters: make(map[statusKey]int),
+				l:        sync.Mutex{},
+				gatherer: status.NewGatherer(),
+			}
+			ct.counters[statusKeyNumLostItems] = 1
+			ct.increaseCounterBy(statusKeyNumLostItems, 41)
+
+			msg := "\t\tcounter increase must be reflected in counter tracker's state"
+			if ct.counters[statusKeyNumLostItems] == 42 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters[statusKeyNumLostItems])
+			}
+
+			msg = "\t\tsingle update carrying new value must have been sent to status gatherer"
+			update := <-ct.gatherer.Updates
+			if update.Key == string(statusKeyNumLostItems) && update.Value == 42 && len(ct.gatherer.Updates) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, update)
+			}
+		}
 	}
 
 }
@@ -158,7 +228,7 @@ func TestPutElements(t *testing.T) {
 			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
 
 			go gatherer.Listen()
-			tl.putElements(qs.q, "awesomeQueue")
+			tl.putElements(qs.q, "awesomeQueue", 0)
 			gatherer.StopListen()
 
 			msg := "\t\tnumber of checks for remaining queue capacity must be equal to number of elements in source data"
@@ -195,7 +265,7 @@ func TestPutElements(t *testing.T) {
 			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
 
 			go gatherer.Listen()
-			tl.putElements(qs.q, "awesomeQueue")
+			tl.putElements(qs.q, "awesomeQueue", 0)
 			gatherer.StopListen()
 
 			msg := "\t\tstatus gatherer must indicate zero failed remaining capacity checks"
@@ -233,7 +303,7 @@ func TestPutElements(t *testing.T) {
 			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
 
 			go gatherer.Listen()
-			tl.putElements(qs.q, "anotherAwesomeQueue")
+			tl.putElements(qs.q, "anotherAwesomeQueue", 0)
 			gatherer.StopListen()
 
 			msg := "\t\tnumber of executed put attempts must be equal to number of elements in test loop source data"
@@ -263,7 +333,7 @@ func TestPutElements(t *testing.T) {
 			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, status.NewGatherer())
 
 			go tl.gatherer.Listen()
-			tl.putElements(qs.q, "yetAnotherAwesomeQueue")
+			tl.putElements(qs.q, "yetAnotherAwesomeQueue", 0)
 			tl.gatherer.StopListen()
 
 			msg := "\t\tnumber of put invocations must be equal to number of elements in test loop source data"
@@ -282,6 +352,235 @@ func TestPutElements(t *testing.T) {
 				t.Fatal(msg, ballotX, detail)
 			}
 		}
+
+		t.Log("\twhen ordering verification has been enabled and puts succeed")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 42)
+			rc := assembleRunnerConfig(true, 1, false, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.verifyOrdering = true
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, status.NewGatherer())
+
+			go tl.gatherer.Listen()
+			tl.putElements(qs.q, "awesomeSequencedQueue", 0)
+			tl.putElements(qs.q, "awesomeSequencedQueue", 0)
+			tl.gatherer.StopListen()
+
+			msg := "\t\telements must have been put as sequenced elements with consecutive sequence numbers across runs"
+			expectedSequenceNumber := uint64(1)
+			for e := qs.q.data.Front(); e != nil; e = e.Next() {
+				se, ok := e.Value.(sequencedElement)
+				if !ok || se.SequenceNumber != expectedSequenceNumber || se.ProducerID != tl.assembleProducerID(0) {
+					t.Fatal(msg, ballotX, e.Value)
+				}
+				expectedSequenceNumber++
+			}
+			if expectedSequenceNumber-1 == uint64(2*len(aNewHope)) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, expectedSequenceNumber-1)
+			}
+		}
+
+		t.Log("\twhen offer with timeout has been configured and offers succeed")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 0)
+			rc := assembleRunnerConfig(true, 1, false, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.putConfig.mode = offerWithTimeoutMode
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, status.NewGatherer())
+
+			go tl.gatherer.Listen()
+			tl.putElements(qs.q, "awesomeOfferQueue", 0)
+			tl.gatherer.StopListen()
+
+			msg := "\t\tno checks for remaining capacity must have been made"
+			if qs.q.remainingCapacityInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.remainingCapacityInvocations)
+			}
+
+			msg = "\t\tone offer per element must have been made instead of puts"
+			if qs.q.addWithTimeoutInvocations == len(aNewHope) && qs.q.putInvocations == 0 && qs.q.data.Len() == len(aNewHope) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.addWithTimeoutInvocations, qs.q.putInvocations)
+			}
+		}
+
+		t.Log("\twhen offer with timeout has been configured and queue remains full until offers time out")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{rejectAdds: true}, 9)
+			rc := assembleRunnerConfig(true, 1, false, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.putConfig.mode = offerWithTimeoutMode
+			rc.verifyOrdering = true
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+			go gatherer.Listen()
+			tl.putElements(qs.q, "anotherAwesomeOfferQueue", 0)
+			gatherer.StopListen()
+
+			waitForStatusGatheringDone(gatherer)
+			statusCopy := gatherer.AssembleStatusCopy()
+
+			msg := "\t\teach rejected offer must be reported as queue full event"
+			if ok, detail := expectedStatusPresent(statusCopy, statusKeyNumQueueFullEvents, len(aNewHope)); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+
+			msg = "\t\trejected offers must not count as failed puts"
+			if ok, detail := expectedStatusPresent(statusCopy, statusKeyNumFailedPuts, 0); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+
+			msg = "\t\tsequence number must not have been advanced"
+			if e := tl.ov.wrap(tl.assembleProducerID(0), "Jabba the Hutt"); e.SequenceNumber == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, e.SequenceNumber)
+			}
+		}
+
+		t.Log("\twhen add all has been configured and queue has enough remaining capacity")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 42)
+			rc := assembleRunnerConfig(true, 1, false, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.putConfig.mode = addAllMode
+			rc.putConfig.batchSize = 4
+			rc.verifyOrdering = true
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, status.NewGatherer())
+
+			go tl.gatherer.Listen()
+			tl.putElements(qs.q, "awesomeAddAllQueue", 0)
+			tl.putElements(qs.q, "awesomeAddAllQueue", 0)
+			tl.gatherer.StopListen()
+
+			msg := "\t\tone add all operation per batch must have been made"
+			if qs.q.addAllInvocations == 2*3 && qs.q.putInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.addAllInvocations)
+			}
+
+			msg = "\t\tall elements must have been added with consecutive sequence numbers across batches and runs"
+			expectedSequenceNumber := uint64(1)
+			for e := qs.q.data.Front(); e != nil; e = e.Next() {
+				se, ok := e.Value.(sequencedElement)
+				if !ok || se.SequenceNumber != expectedSequenceNumber {
+					t.Fatal(msg, ballotX, e.Value)
+				}
+				expectedSequenceNumber++
+			}
+			if expectedSequenceNumber-1 == uint64(2*len(aNewHope)) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, expectedSequenceNumber-1)
+			}
+		}
+
+		t.Log("\twhen add all has been configured and queue does not have enough remaining capacity for batch")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 3)
+			rc := assembleRunnerConfig(true, 1, false, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.putConfig.mode = addAllMode
+			rc.putConfig.batchSize = 4
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+			go gatherer.Listen()
+			tl.putElements(qs.q, "anotherAwesomeAddAllQueue", 0)
+			gatherer.StopListen()
+
+			msg := "\t\tonly last batch must have been added"
+			if qs.q.addAllInvocations == 1 && qs.q.data.Len() == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.addAllInvocations, qs.q.data.Len())
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			msg = "\t\tbatches exceeding remaining capacity must have been reported as queue full events"
+			if ok, detail := expectedStatusPresent(gatherer.AssembleStatusCopy(), statusKeyNumQueueFullEvents, 2); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
+
+		t.Log("\twhen add all has been configured and add all operations fail")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{returnErrorUponPut: true}, 42)
+			rc := assembleRunnerConfig(true, 1, false, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.putConfig.mode = addAllMode
+			rc.putConfig.batchSize = 4
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+			go gatherer.Listen()
+			tl.putElements(qs.q, "yetAnotherAwesomeAddAllQueue", 0)
+			gatherer.StopListen()
+
+			waitForStatusGatheringDone(gatherer)
+
+			msg := "\t\teach element of failed batches must have been reported as failed put"
+			if ok, detail := expectedStatusPresent(gatherer.AssembleStatusCopy(), statusKeyNumFailedPuts, len(aNewHope)); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
+
+		t.Log("\twhen time-in-queue latency tracking and ordering verification have been enabled and puts succeed")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 42)
+			rc := assembleRunnerConfig(true, 1, false, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.verifyOrdering = true
+			rc.trackTimeInQueue = true
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, status.NewGatherer())
+
+			beforePut := time.Now()
+			go tl.gatherer.Listen()
+			tl.putElements(qs.q, "awesomeTimestampedQueue", 0)
+			tl.gatherer.StopListen()
+
+			msg := "\t\telements must have been put as timestamped elements wrapping sequenced elements"
+			for e := qs.q.data.Front(); e != nil; e = e.Next() {
+				te, ok := e.Value.(timestampedElement)
+				if !ok || te.EnqueuedAt.Before(beforePut) {
+					t.Fatal(msg, ballotX, e.Value)
+				}
+				if _, ok := te.Payload.(sequencedElement); !ok {
+					t.Fatal(msg, ballotX, te.Payload)
+				}
+			}
+			t.Log(msg, checkMark)
+		}
+
+		t.Log("\twhen ordering verification has been enabled and puts fail")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{
+				returnErrorUponPut: true,
+			}, 42)
+			rc := assembleRunnerConfig(true, 1, false, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.verifyOrdering = true
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, status.NewGatherer())
+
+			go tl.gatherer.Listen()
+			tl.putElements(qs.q, "anotherAwesomeSequencedQueue", 0)
+			tl.gatherer.StopListen()
+
+			msg := "\t\tsequence number must not have been advanced"
+			if e := tl.ov.wrap(tl.assembleProducerID(0), "Jabba the Hutt"); e.SequenceNumber == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, e.SequenceNumber)
+			}
+		}
 	}
 
 }
@@ -301,7 +600,7 @@ func TestPollElements(t *testing.T) {
 			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
 
 			go gatherer.Listen()
-			tl.pollElements(qs.q, "yeehawQueue")
+			tl.pollElements(qs.q, "yeehawQueue", 0, context.Background())
 			gatherer.StopListen()
 
 			msg := "\t\tnumber of poll attempts must be equal to number of elements in test loop source data"
@@ -333,7 +632,7 @@ func TestPollElements(t *testing.T) {
 			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
 
 			go gatherer.Listen()
-			tl.pollElements(qs.q, "anotherYeehawQueue")
+			tl.pollElements(qs.q, "anotherYeehawQueue", 0, context.Background())
 			gatherer.StopListen()
 
 			msg := "\t\tstatus gatherer must indicate zero failed polls"
@@ -367,7 +666,7 @@ func TestPollElements(t *testing.T) {
 			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
 
 			go gatherer.Listen()
-			tl.pollElements(qs.q, "yetAnotherYeehawQueue")
+			tl.pollElements(qs.q, "yetAnotherYeehawQueue", 0, context.Background())
 			gatherer.StopListen()
 
 			waitForStatusGatheringDone(tl.gatherer)
@@ -387,63 +686,403 @@ func TestPollElements(t *testing.T) {
 				t.Fatal(msg, ballotX, detail)
 			}
 		}
-	}
 
-}
+		t.Log("\twhen ordering verification has been enabled and polled elements are out of order, lost, and duplicated")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+			// Sequence numbers 3 and 4 go missing, 4 turns up late (so only 3 counts as lost), and 1 gets delivered twice
+			for _, i := range []uint64{1, 2, 5, 6, 4, 7, 1, 8, 9} {
+				qs.q.data.PushBack(sequencedElement{ProducerID: "awesome-producer", SequenceNumber: i, Payload: "R2-D2"})
+			}
+			rc := assembleRunnerConfig(false, 0, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.verifyOrdering = true
 
-func TestRun(t *testing.T) {
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
 
-	t.Log("given the queue test loop")
-	{
-		t.Log("\twhen only put config is provided")
+			go gatherer.Listen()
+			tl.pollElements(qs.q, "sequencedYeehawQueue", 0, context.Background())
+			gatherer.StopListen()
+
+			waitForStatusGatheringDone(gatherer)
+			statusCopy := gatherer.AssembleStatusCopy()
+
+			for k, v := range map[statusKey]int{
+				statusKeyNumLostItems:       1,
+				statusKeyNumOutOfOrderPolls: 1,
+				statusKeyNumDuplicateItems:  1,
+			} {
+				msg := fmt.Sprintf("\t\tstatus gatherer must indicate %d for '%s'", v, k)
+				if ok, detail := expectedStatusPresent(statusCopy, k, v); ok {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, detail)
+				}
+			}
+		}
+
+		t.Log("\twhen runner has been configured with consumer role and polled elements have been put by other clients")
 		{
 			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
-			rc := assembleRunnerConfig(true, 1, false, 0, sleepConfigDisabled, sleepConfigDisabled)
+			qs.q.data.PushBack(timestampedElement{EnqueuedAt: time.Now(), Payload: sequencedElement{ProducerID: "other-client", SequenceNumber: 1, Payload: aNewHope[0]}})
+			qs.q.data.PushBack(sequencedElement{ProducerID: "other-client", SequenceNumber: 2, Payload: aNewHope[1]})
+			qs.q.data.PushBack(aNewHope[2])
+			qs.q.data.PushBack(sequencedElement{ProducerID: "other-client", SequenceNumber: 3, Payload: 42})
+			qs.q.data.PushBack(tweet{Text: "Jar Jar Binks"})
+			rc := assembleRunnerConfig(false, 0, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.role = consumer
+			rc.verifyOrdering = true
 
 			gatherer := status.NewGatherer()
 			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
 
 			go gatherer.Listen()
-			tl.run()
+			tl.pollElements(qs.q, "sharedYeehawQueue", 0, context.Background())
 			gatherer.StopListen()
 
-			msg := "\t\texpected number of puts must have been executed"
-			if qs.q.putInvocations == len(aNewHope) {
+			waitForStatusGatheringDone(gatherer)
+			statusCopy := gatherer.AssembleStatusCopy()
+
+			msg := "\t\telements whose payload is not of test loop's element type must have been reported as unexpected"
+			if ok, detail := expectedStatusPresent(statusCopy, statusKeyNumUnexpectedElements, 2); ok {
 				t.Log(msg, checkMark)
 			} else {
-				t.Fatal(msg, ballotX)
+				t.Fatal(msg, ballotX, detail)
 			}
 
-			msg = "\t\tpoll must not have been executed because test loop did not have enabled poll config"
-			if qs.q.pollInvocations == 0 {
+			msg = "\t\tsequence numbers of other clients must have been verified"
+			if ok, detail := expectedStatusPresent(statusCopy, statusKeyNumLostItems, 0); ok {
 				t.Log(msg, checkMark)
 			} else {
-				t.Fatal(msg, ballotX)
+				t.Fatal(msg, ballotX, detail)
 			}
+		}
 
-			msg = "\t\tdata must be present in queue"
-			if qs.q.data.Len() == len(aNewHope) {
-				t.Log(msg, checkMark)
-			} else {
-				t.Fatal(msg, ballotX)
+		t.Log("\twhen runner has been configured with consumer role and polled elements fail payload validation")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+			qs.q.data.PushBack(aNewHope[0])
+			qs.q.data.PushBack("It's a trap!")
+			qs.q.data.PushBack(sequencedElement{ProducerID: "other-client", SequenceNumber: 1, Payload: "I am your father"})
+			rc := assembleRunnerConfig(false, 0, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.role = consumer
+
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+			tl.tle.validatePayload = func(payload string) error {
+				for _, v := range aNewHope {
+					if v == payload {
+						return nil
+					}
+				}
+				return errors.New("not part of a new hope")
 			}
 
-			msg = "\t\ttest loop status must be correct"
+			go gatherer.Listen()
+			tl.pollElements(qs.q, "validatedYeehawQueue", 0, context.Background())
+			gatherer.StopListen()
+
 			waitForStatusGatheringDone(gatherer)
-			statusCopy := tl.gatherer.AssembleStatusCopy()
-			if ok, key, detail := statusContainsExpectedValues(statusCopy, rc.numQueues, rc.putConfig, rc.pollConfig); ok {
+
+			msg := "\t\telements of test loop's element type having unexpected content must have been reported as unexpected"
+			if ok, detail := expectedStatusPresent(gatherer.AssembleStatusCopy(), statusKeyNumUnexpectedElements, 2); ok {
 				t.Log(msg, checkMark)
 			} else {
-				t.Fatal(msg, ballotX, key, detail)
+				t.Fatal(msg, ballotX, detail)
 			}
-
 		}
-	}
-
-	t.Log("\twhen both put and poll config are provided, and put runs twice as many times as poll")
-	{
-		qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 18)
-		rc := assembleRunnerConfig(true, 2, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+
+		t.Log("\twhen poll with timeout has been configured")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+			for _, v := range aNewHope[:5] {
+				qs.q.data.PushBack(v)
+			}
+			rc := assembleRunnerConfig(false, 0, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.pollConfig.mode = pollWithTimeoutMode
+
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+			go gatherer.Listen()
+			tl.pollElements(qs.q, "timeoutYeehawQueue", 0, context.Background())
+			gatherer.StopListen()
+
+			msg := "\t\tone poll with timeout per element must have been made instead of polls"
+			if qs.q.pollWithTimeoutInvocations == len(aNewHope) && qs.q.pollInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.pollWithTimeoutInvocations, qs.q.pollInvocations)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			msg = "\t\tpolls with timeout not retrieving anything must have been reported as nil polls"
+			if ok, detail := expectedStatusPresent(gatherer.AssembleStatusCopy(), statusKeyNumNilPolls, len(aNewHope)-5); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
+
+		t.Log("\twhen take has been configured and put goroutine is still active")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+			rc := assembleRunnerConfig(false, 0, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.pollConfig.mode = takeMode
+			rc.pollConfig.timeout = time.Millisecond
+
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+			putsFinished, finishPuts := context.WithCancel(context.Background())
+			go gatherer.Listen()
+			go func() {
+				// Simulates put goroutine putting elements with some delay, then finishing without having put
+				// all elements
+				for _, v := range aNewHope[:5] {
+					time.Sleep(time.Millisecond)
+					testQueueOperationLock.Lock()
+					qs.q.data.PushBack(v)
+					testQueueOperationLock.Unlock()
+				}
+				time.Sleep(10 * time.Millisecond)
+				finishPuts()
+			}()
+			tl.pollElements(qs.q, "takeYeehawQueue", 0, putsFinished)
+			gatherer.StopListen()
+
+			msg := "\t\tall elements put must have been retrieved"
+			if qs.q.data.Len() == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.data.Len())
+			}
+
+			msg = "\t\twaiting must have been done by means of polls with timeout"
+			if qs.q.pollInvocations == 0 && qs.q.pollWithTimeoutInvocations > len(aNewHope) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.pollInvocations, qs.q.pollWithTimeoutInvocations)
+			}
+
+			waitForStatusGatheringDone(gatherer)
+			statusCopy := gatherer.AssembleStatusCopy()
+
+			msg = "\t\tput goroutine having finished while waiting must not count as failed poll"
+			if ok, detail := expectedStatusPresent(statusCopy, statusKeyNumFailedPolls, 0); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+
+			msg = "\t\twaits on drained queue must have been reported as nil polls"
+			if ok, detail := expectedStatusPresent(statusCopy, statusKeyNumNilPolls, len(aNewHope)-5); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
+
+		t.Log("\twhen take has been configured and takes fail")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{returnErrorUponPoll: true}, 9)
+			rc := assembleRunnerConfig(false, 0, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.pollConfig.mode = takeMode
+			rc.pollConfig.timeout = time.Millisecond
+
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+			go gatherer.Listen()
+			tl.pollElements(qs.q, "anotherTakeYeehawQueue", 0, context.Background())
+			gatherer.StopListen()
+
+			waitForStatusGatheringDone(gatherer)
+
+			msg := "\t\tfailed waits must have been reported as failed polls"
+			if ok, detail := expectedStatusPresent(gatherer.AssembleStatusCopy(), statusKeyNumFailedPolls, len(aNewHope)); ok && qs.q.pollInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail, qs.q.pollInvocations)
+			}
+		}
+
+		t.Log("\twhen drain to has been configured")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+			for _, v := range aNewHope[:6] {
+				qs.q.data.PushBack(v)
+			}
+			rc := assembleRunnerConfig(false, 0, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.pollConfig.mode = drainToMode
+			rc.pollConfig.batchSize = 4
+
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+			go gatherer.Listen()
+			tl.pollElements(qs.q, "drainYeehawQueue", 0, context.Background())
+			gatherer.StopListen()
+
+			msg := "\t\tone drain operation per batch must have been made instead of polls"
+			if qs.q.drainInvocations == 3 && qs.q.pollInvocations == 0 && qs.q.data.Len() == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.drainInvocations, qs.q.pollInvocations, qs.q.data.Len())
+			}
+
+			waitForStatusGatheringDone(gatherer)
+
+			msg = "\t\tdrain operation not retrieving anything must have been reported as nil poll"
+			if ok, detail := expectedStatusPresent(gatherer.AssembleStatusCopy(), statusKeyNumNilPolls, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
+
+		t.Log("\twhen drain to has been configured along with ordering verification")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+			for _, i := range []uint64{1, 2, 4, 5} {
+				qs.q.data.PushBack(sequencedElement{ProducerID: "awesome-producer", SequenceNumber: i, Payload: "Han Solo"})
+			}
+			rc := assembleRunnerConfig(false, 0, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.pollConfig.mode = drainToMode
+			rc.pollConfig.batchSize = 4
+			rc.verifyOrdering = true
+
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+			go gatherer.Listen()
+			tl.pollElements(qs.q, "anotherDrainYeehawQueue", 0, context.Background())
+			gatherer.StopListen()
+
+			waitForStatusGatheringDone(gatherer)
+
+			msg := "\t\teach drained element must have been verified"
+			if ok, detail := expectedStatusPresent(gatherer.AssembleStatusCopy(), statusKeyNumLostItems, 1); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
+
+		t.Log("\twhen time-in-queue latency tracking has been enabled and polled elements carry timestamps")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+			for range aNewHope {
+				qs.q.data.PushBack(timestampedElement{EnqueuedAt: time.Now().Add(-2 * time.Second), Payload: "C-3PO"})
+			}
+			rc := assembleRunnerConfig(false, 0, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.trackTimeInQueue = true
+
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+			go gatherer.Listen()
+			tl.pollElements(qs.q, "timestampedYeehawQueue", 0, context.Background())
+			tl.lt.publish()
+			gatherer.StopListen()
+
+			waitForStatusGatheringDone(gatherer)
+
+			msg := "\t\tstatus gatherer must have received one time-in-queue latency per polled element for the queue"
+			snapshots := gatherer.AssembleStatusCopy()[string(statusKeyTimeInQueueLatencies)].(map[string]status.HistogramSnapshot)
+			if s := snapshots["timestampedYeehawQueue"]; s.Count == uint64(len(aNewHope)) && s.MinMicros >= 2_000_000 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s)
+			}
+		}
+
+		t.Log("\twhen ordering verification has been enabled and elements are polled in the order they were put")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 42)
+			rc := assembleRunnerConfig(true, 1, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+			rc.verifyOrdering = true
+			// Ordering verification must see through the timestamp wrapping the sequenced element
+			rc.trackTimeInQueue = true
+
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+			go gatherer.Listen()
+			tl.putElements(qs.q, "anotherSequencedYeehawQueue", 0)
+			tl.pollElements(qs.q, "anotherSequencedYeehawQueue", 0, context.Background())
+			gatherer.StopListen()
+
+			waitForStatusGatheringDone(gatherer)
+			statusCopy := gatherer.AssembleStatusCopy()
+
+			msg := "\t\tstatus gatherer must indicate zero for all ordering counters"
+			for _, v := range orderingCounters {
+				if ok, detail := expectedStatusPresent(statusCopy, v, 0); ok {
+					t.Log(msg, checkMark, v)
+				} else {
+					t.Fatal(msg, ballotX, detail)
+				}
+			}
+		}
+	}
+
+}
+
+func TestRun(t *testing.T) {
+
+	t.Log("given the queue test loop")
+	{
+		t.Log("\twhen only put config is provided")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+			rc := assembleRunnerConfig(true, 1, false, 0, sleepConfigDisabled, sleepConfigDisabled)
+
+			gatherer := status.NewGatherer()
+			tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+			go gatherer.Listen()
+			tl.run()
+			gatherer.StopListen()
+
+			msg := "\t\texpected number of puts must have been executed"
+			if qs.q.putInvocations == len(aNewHope) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tpoll must not have been executed because test loop did not have enabled poll config"
+			if qs.q.pollInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tdata must be present in queue"
+			if qs.q.data.Len() == len(aNewHope) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\ttest loop status must be correct"
+			waitForStatusGatheringDone(gatherer)
+			statusCopy := tl.gatherer.AssembleStatusCopy()
+			if ok, key, detail := statusContainsExpectedValues(statusCopy, rc.numQueues, rc.putConfig, rc.pollConfig); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, key, detail)
+			}
+
+		}
+	}
+
+	t.Log("\twhen both put and poll config are provided, and put runs twice as many times as poll")
+	{
+		qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 18)
+		rc := assembleRunnerConfig(true, 2, true, 1, sleepConfigDisabled, sleepConfigDisabled)
 
 		gatherer := status.NewGatherer()
 		tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
@@ -654,29 +1293,630 @@ func TestRun(t *testing.T) {
 			t.Fatal(msg, ballotX)
 		}
 	}
+	t.Log("\twhen run duration has been configured")
+	{
+		numRuns := 1
+		// Brief sleeps between runs make sure neither the put nor the poll goroutine can starve the other one
+		// when only a single processor is available
+		sleepBetweenRuns := &sleepConfig{enabled: true, durationMs: 1}
+		sleepTimeFunc = func(sc *sleepConfig) int {
+			return sc.durationMs
+		}
+		rc := assembleRunnerConfig(true, numRuns, true, numRuns, sleepBetweenRuns, sleepBetweenRuns)
+		rc.runDuration = 50 * time.Millisecond
+		qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+		gatherer := status.NewGatherer()
+		tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+		tl.tle.ctx = context.TODO()
 
-}
+		go gatherer.Listen()
+		start := time.Now()
+		tl.run()
+		elapsed := time.Since(start)
+		gatherer.StopListen()
 
-func expectedStatusPresent(statusCopy map[string]any, expectedKey statusKey, expectedValue int) (bool, string) {
+		waitForStatusGatheringDone(gatherer)
 
-	recordedValue := statusCopy[string(expectedKey)].(int)
+		msg := "\t\ttest loop must have run until run duration elapsed"
+		if elapsed >= rc.runDuration {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, elapsed)
+		}
 
-	if recordedValue == expectedValue {
-		return true, ""
-	} else {
-		return false, fmt.Sprintf("expected %d, got %d\n", expectedValue, recordedValue)
+		msg = "\t\tconfigured number of runs must have been ignored for both put and poll"
+		if qs.q.putInvocations > numRuns*len(aNewHope) && qs.q.pollInvocations > numRuns*len(aNewHope) {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, qs.q.putInvocations, qs.q.pollInvocations)
+		}
+
+		msg = "\t\tstatus must report zero seconds of remaining run duration"
+		if v, ok := gatherer.AssembleStatusCopy()[string(statusKeyRemainingRunDurationSeconds)]; ok && v == uint64(0) {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, v)
+		}
 	}
+	t.Log("\twhen throughput regulation has been enabled")
+	{
+		rc := assembleRunnerConfig(true, 1, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+		rc.throughput = &loadsupport.ThroughputConfig{Enabled: true, TargetOpsPerSecond: 10_000, Scope: loadsupport.RunnerScope}
+		qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+		gatherer := status.NewGatherer()
+		tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+		tl.tle.ctx = context.TODO()
 
-}
+		go gatherer.Listen()
+		tl.run()
+		gatherer.StopListen()
 
-func statusContainsExpectedValues(status map[string]any, expectedNumQueues int, expectedPutStatus, expectedPollStatus *operationConfig) (bool, string, string) {
+		waitForStatusGatheringDone(gatherer)
 
-	if numQueuesFromStatus, ok := status[statusKeyNumQueues]; ok && numQueuesFromStatus != expectedNumQueues {
-		return false, statusKeyNumQueues, fmt.Sprintf("want: %d; got: %d", expectedNumQueues, numQueuesFromStatus)
-	}
+		statusCopy := gatherer.AssembleStatusCopy()
 
-	if ok, key, detail := operationConfigStatusContainsExpectedValues(status[string(put)].(map[string]any), expectedNumQueues, expectedPutStatus); !ok {
-		return false, fmt.Sprintf("%s.%s", string(put), key), detail
+		msg := "\t\tstatus must contain target throughput"
+		if v, ok := statusCopy[loadsupport.StatusKeyTargetOpsPerSecond]; ok && v == 10_000 {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, v)
+		}
+
+		msg = "\t\tstatus must contain achieved throughput"
+		if v, ok := statusCopy[loadsupport.StatusKeyAchievedOpsPerSecond]; ok && v.(float64) > 0 {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, v)
+		}
+	}
+
+	t.Log("\twhen pre-run clean is disabled")
+	{
+		qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+		rc := assembleRunnerConfig(true, 1, false, 0, sleepConfigDisabled, sleepConfigDisabled)
+		gatherer := status.NewGatherer()
+		tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+		cleaner := &testSingleQueueCleaner{}
+		tl.tle.stateCleanerBuilder = &testSingleQueueCleanerBuilder{queueCleanerToReturn: cleaner}
+
+		go gatherer.Listen()
+		tl.run()
+		gatherer.StopListen()
+
+		msg := "\t\tqueue cleaner must not have been invoked"
+		if cleaner.cleanInvocations == 0 {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, cleaner.cleanInvocations)
+		}
+
+		msg = "\t\tputs must have been executed"
+		if qs.q.putInvocations == len(aNewHope) {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, qs.q.putInvocations)
+		}
+
+		waitForStatusGatheringDone(gatherer)
+
+		msg = "\t\tstatus must not contain pre-run clean counters"
+		statusCopy := gatherer.AssembleStatusCopy()
+		for _, v := range preRunCleanCounters {
+			if _, ok := statusCopy[string(v)]; !ok {
+				t.Log(msg, checkMark, v)
+			} else {
+				t.Fatal(msg, ballotX, v)
+			}
+		}
+	}
+
+	t.Log("\twhen pre-run clean is enabled and clean is successful")
+	{
+		qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 18)
+		rc := assembleRunnerConfig(true, 1, false, 0, sleepConfigDisabled, sleepConfigDisabled)
+		rc.numQueues = 2
+		rc.preRunClean = &preRunCleanConfig{enabled: true, errorBehavior: state.Fail}
+		gatherer := status.NewGatherer()
+		tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+		cleaner := &testSingleQueueCleaner{numElementsCleanedReturnValue: 3}
+		tl.tle.stateCleanerBuilder = &testSingleQueueCleanerBuilder{queueCleanerToReturn: cleaner}
+
+		go gatherer.Listen()
+		tl.run()
+		gatherer.StopListen()
+
+		msg := "\t\tqueue cleaner must have been invoked once per queue"
+		if cleaner.cleanInvocations == 2 {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, cleaner.cleanInvocations)
+		}
+
+		msg = "\t\tputs must have been executed on all queues"
+		if qs.q.putInvocations == 2*len(aNewHope) {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, qs.q.putInvocations)
+		}
+
+		waitForStatusGatheringDone(gatherer)
+		statusCopy := gatherer.AssembleStatusCopy()
+
+		msg = "\t\tstatus must contain number of cleaned items across all queues"
+		if ok, detail := expectedStatusPresent(statusCopy, statusKeyNumPreRunCleanedItems, 6); ok {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, detail)
+		}
+
+		msg = "\t\tstatus must indicate zero failed cleans"
+		if ok, detail := expectedStatusPresent(statusCopy, statusKeyNumFailedPreRunCleans, 0); ok {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, detail)
+		}
+	}
+
+	t.Log("\twhen pre-run clean is enabled, clean fails, and error behavior is to ignore the error")
+	{
+		qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+		rc := assembleRunnerConfig(true, 1, false, 0, sleepConfigDisabled, sleepConfigDisabled)
+		rc.preRunClean = &preRunCleanConfig{enabled: true, errorBehavior: state.Ignore}
+		gatherer := status.NewGatherer()
+		tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+		cleaner := &testSingleQueueCleaner{returnErrorUponClean: true}
+		tl.tle.stateCleanerBuilder = &testSingleQueueCleanerBuilder{queueCleanerToReturn: cleaner}
+
+		go gatherer.Listen()
+		tl.run()
+		gatherer.StopListen()
+
+		msg := "\t\tputs must have been executed despite error during pre-run clean"
+		if cleaner.cleanInvocations == 1 && qs.q.putInvocations == len(aNewHope) {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, cleaner.cleanInvocations, qs.q.putInvocations)
+		}
+
+		waitForStatusGatheringDone(gatherer)
+
+		msg = "\t\tstatus must contain failed clean"
+		if ok, detail := expectedStatusPresent(gatherer.AssembleStatusCopy(), statusKeyNumFailedPreRunCleans, 1); ok {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, detail)
+		}
+	}
+
+	t.Log("\twhen pre-run clean is enabled, clean fails, and error behavior is to fail")
+	{
+		qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+		rc := assembleRunnerConfig(true, 1, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+		rc.preRunClean = &preRunCleanConfig{enabled: true, errorBehavior: state.Fail}
+		gatherer := status.NewGatherer()
+		tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+		cleaner := &testSingleQueueCleaner{returnErrorUponClean: true}
+		tl.tle.stateCleanerBuilder = &testSingleQueueCleanerBuilder{queueCleanerToReturn: cleaner}
+
+		go gatherer.Listen()
+		tl.run()
+		gatherer.StopListen()
+
+		msg := "\t\tneither puts nor polls must have been executed"
+		if cleaner.cleanInvocations == 1 && qs.q.putInvocations == 0 && qs.q.pollInvocations == 0 {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, cleaner.cleanInvocations, qs.q.putInvocations, qs.q.pollInvocations)
+		}
+
+		waitForStatusGatheringDone(gatherer)
+
+		msg = "\t\tstatus must contain failed clean"
+		if ok, detail := expectedStatusPresent(gatherer.AssembleStatusCopy(), statusKeyNumFailedPreRunCleans, 1); ok {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, detail)
+		}
+	}
+
+	t.Log("\twhen pre-run clean is enabled, but state cleaner could not be assembled")
+	{
+		qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+		rc := assembleRunnerConfig(true, 1, false, 0, sleepConfigDisabled, sleepConfigDisabled)
+		rc.preRunClean = &preRunCleanConfig{enabled: true, errorBehavior: state.Ignore}
+		gatherer := status.NewGatherer()
+		tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+		tl.tle.stateCleanerBuilder = &testSingleQueueCleanerBuilder{}
+
+		go gatherer.Listen()
+		tl.run()
+		gatherer.StopListen()
+
+		msg := "\t\tputs must not have been executed"
+		if qs.q.putInvocations == 0 {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, qs.q.putInvocations)
+		}
+	}
+
+	t.Log("\twhen both put and poll are performed by the same runner")
+	{
+		qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+		rc := assembleRunnerConfig(true, 1, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+		gatherer := status.NewGatherer()
+		tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+		go gatherer.Listen()
+		tl.run()
+		gatherer.StopListen()
+
+		msg := "\t\tqueue must have been destroyed"
+		if qs.q.destroyInvocations == 1 {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, qs.q.destroyInvocations)
+		}
+
+		waitForStatusGatheringDone(gatherer)
+
+		msg = "\t\tstatus must contain role"
+		if v, ok := gatherer.AssembleStatusCopy()[statusKeyRole]; ok && v == string(bothRoles) {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, v)
+		}
+	}
+
+	t.Log("\twhen runner has been configured with producer role")
+	{
+		qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+		rc := assembleRunnerConfig(true, 1, false, 0, sleepConfigDisabled, sleepConfigDisabled)
+		rc.role = producer
+		gatherer := status.NewGatherer()
+		tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+
+		go gatherer.Listen()
+		tl.run()
+		gatherer.StopListen()
+
+		msg := "\t\tqueue must not have been destroyed so consumers can still poll its elements"
+		if qs.q.destroyInvocations == 0 && qs.q.data.Len() == len(aNewHope) {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, qs.q.destroyInvocations, qs.q.data.Len())
+		}
+
+		waitForStatusGatheringDone(gatherer)
+
+		msg = "\t\tstatus must contain role"
+		if v, ok := gatherer.AssembleStatusCopy()[statusKeyRole]; ok && v == string(producer) {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, v)
+		}
+	}
+
+	t.Log("\twhen runner has been configured with consumer role and take mode")
+	{
+		qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 9)
+		rc := assembleRunnerConfig(false, 0, true, 1, sleepConfigDisabled, sleepConfigDisabled)
+		rc.role = consumer
+		rc.pollConfig.mode = takeMode
+		rc.pollConfig.timeout = time.Millisecond
+		ctx, cancel := context.WithCancel(context.Background())
+		gatherer := status.NewGatherer()
+		tl := assembleTestLoop(uuid.New(), testSource, qs, &rc, gatherer)
+		tl.tle.ctx = ctx
+
+		go gatherer.Listen()
+		go func() {
+			// Simulates another Hazeltest instance putting a couple of elements, then test loop getting cancelled
+			for _, v := range aNewHope[:3] {
+				time.Sleep(time.Millisecond)
+				testQueueOperationLock.Lock()
+				qs.q.data.PushBack(v)
+				testQueueOperationLock.Unlock()
+			}
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+		tl.run()
+		gatherer.StopListen()
+
+		msg := "\t\tpoll goroutine must have kept waiting for elements even though put is disabled"
+		if qs.q.pollWithTimeoutInvocations > 3 && qs.q.data.Len() == 0 {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, qs.q.pollWithTimeoutInvocations, qs.q.data.Len())
+		}
+
+		msg = "\t\tqueue must not have been destroyed so other consumers can still poll its elements"
+		if qs.q.destroyInvocations == 0 {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, qs.q.destroyInvocations)
+		}
+
+		waitForStatusGatheringDone(gatherer)
+
+		msg = "\t\tstatus must contain counter for unexpected elements"
+		if ok, detail := expectedStatusPresent(gatherer.AssembleStatusCopy(), statusKeyNumUnexpectedElements, 0); ok {
+			t.Log(msg, checkMark)
+		} else {
+			t.Fatal(msg, ballotX, detail)
+		}
+	}
+
+}
+
+func TestDefaultSleeperSleep(t *testing.T) {
+
+	t.Log("given a sleeper and a context")
+	{
+		t.Log("\twhen context is done while sleeping")
+		{
+			s := &defaultSleeper{}
+			ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			s.sleep(ctx, &sleepConfig{enabled: true, durationMs: 10000}, sleepTimeFunc, "betweenRuns", "awesomeQueue", "awesomeRunner", "put")
+			elapsed := time.Since(start)
+
+			msg := "\t\tsleep must be cut short"
+			if elapsed < 5*time.Second {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, elapsed)
+			}
+		}
+		t.Log("\twhen context is not done")
+		{
+			s := &defaultSleeper{}
+
+			start := time.Now()
+			s.sleep(context.TODO(), &sleepConfig{enabled: true, durationMs: 20}, sleepTimeFunc, "betweenRuns", "awesomeQueue", "awesomeRunner", "put")
+			elapsed := time.Since(start)
+
+			msg := "\t\tsleeper must sleep for configured duration"
+			if elapsed >= 20*time.Millisecond {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, elapsed)
+			}
+		}
+	}
+
+}
+
+func TestBoundaryTestLoopRun(t *testing.T) {
+
+	t.Log("given the queue boundary test loop")
+	{
+		t.Log("\twhen action towards boundary probability is 100 percent")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, 10)
+			rc := assembleBoundaryRunnerConfig(0.8, 0.2, 1.0, 20, 1)
+			s := &testSleeper{}
+			gatherer := status.NewGatherer()
+			tl := assembleBoundaryTestLoop(qs, &rc, s, gatherer)
+
+			go gatherer.Listen()
+			tl.run()
+			gatherer.StopListen()
+
+			// Fill from 0 to 8 elements, drain down to 2, then fill up to 8 again
+			msg := "\t\tnumber of puts must correspond to fill actions between boundaries"
+			if qs.q.putInvocations == 14 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.putInvocations)
+			}
+
+			msg = "\t\tnumber of polls must correspond to drain actions between boundaries"
+			if qs.q.pollInvocations == 6 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.pollInvocations)
+			}
+
+			msg = "\t\tqueue must hold number of elements corresponding to upper boundary"
+			if qs.q.data.Len() == 8 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.data.Len())
+			}
+
+			msg = "\t\ttest loop must have slept upon each mode change"
+			if n := s.numSleeps("uponModeChange"); n == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, n)
+			}
+
+			msg = "\t\ttest loop type must have been reported"
+			waitForStatusGatheringDone(gatherer)
+			if v, ok := gatherer.AssembleStatusCopy()[statusKeyTestLoopType]; ok && v == string(boundary) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, v)
+			}
+		}
+
+		t.Log("\twhen queue is unbounded")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{}, math.MaxInt32)
+			rc := assembleBoundaryRunnerConfig(0.8, 0.2, 1.0, 20, 1)
+			tl := assembleBoundaryTestLoop(qs, &rc, &testSleeper{}, status.NewGatherer())
+
+			go tl.gatherer.Listen()
+			tl.run()
+			tl.gatherer.StopListen()
+
+			msg := "\t\tall actions must be puts because upper boundary is never reached"
+			if qs.q.putInvocations == 20 && qs.q.pollInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.putInvocations, qs.q.pollInvocations)
+			}
+		}
+
+		t.Log("\twhen fill level of queue cannot be determined")
+		{
+			qs := assembleTestQueueStore(&testQueueStoreBehavior{returnErrorUponRemainingCapacity: true}, 10)
+			rc := assembleBoundaryRunnerConfig(0.8, 0.2, 1.0, 20, 3)
+			gatherer := status.NewGatherer()
+			tl := assembleBoundaryTestLoop(qs, &rc, &testSleeper{}, gatherer)
+
+			go gatherer.Listen()
+			tl.run()
+			gatherer.StopListen()
+
+			msg := "\t\tno operation chain must have been run"
+			if qs.q.putInvocations == 0 && qs.q.pollInvocations == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, qs.q.putInvocations, qs.q.pollInvocations)
+			}
+
+			msg = "\t\tfailed capacity check must have been reported for each operation chain"
+			waitForStatusGatheringDone(gatherer)
+			if ok, detail := expectedStatusPresent(gatherer.AssembleStatusCopy(), statusKeyNumFailedCapacityChecks, 3); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, detail)
+			}
+		}
+	}
+
+}
+
+func TestCheckForModeChange(t *testing.T) {
+
+	t.Log("given the queue boundary test loop's function for checking whether the mode should change")
+	{
+		tl := &boundaryTestLoop[string]{testLoop[string]{tle: &testLoopExecution[string]{}}}
+
+		t.Log("\twhen number of elements has reached lower boundary")
+		{
+			mode, force := tl.checkForModeChange(0.8, 0.2, 2, 10, drain)
+
+			msg := "\t\tfill mode must be enforced"
+			if mode == fill && force {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, mode, force)
+			}
+		}
+
+		t.Log("\twhen number of elements has reached upper boundary")
+		{
+			mode, force := tl.checkForModeChange(0.8, 0.2, 8, 10, fill)
+
+			msg := "\t\tdrain mode must be enforced"
+			if mode == drain && force {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, mode, force)
+			}
+		}
+
+		t.Log("\twhen number of elements lies between boundaries")
+		{
+			mode, force := tl.checkForModeChange(0.8, 0.2, 5, 10, drain)
+
+			msg := "\t\tcurrent mode must be kept without being enforced"
+			if mode == drain && !force {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, mode, force)
+			}
+
+			mode, force = tl.checkForModeChange(0.8, 0.2, 5, 10, "")
+
+			msg = "\t\tfill mode must be chosen if there is no current mode yet"
+			if mode == fill && !force {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, mode, force)
+			}
+		}
+	}
+
+}
+
+func TestDetermineNextQueueAction(t *testing.T) {
+
+	t.Log("given a function for determining the boundary test loop's next queue action")
+	{
+		t.Log("\twhen queue is empty")
+		{
+			msg := "\t\tput must be chosen even in drain mode"
+			if a := determineNextQueueAction(&modeCache{current: drain}, 1.0, 0, 10); a == put {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, a)
+			}
+		}
+
+		t.Log("\twhen queue is full")
+		{
+			msg := "\t\tpoll must be chosen even in fill mode"
+			if a := determineNextQueueAction(&modeCache{current: fill}, 1.0, 10, 10); a == poll {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, a)
+			}
+		}
+
+		t.Log("\twhen action towards mode is enforced")
+		{
+			for mode, expected := range map[actionMode]operation{fill: put, drain: poll} {
+				msg := "\t\taction towards mode must be chosen despite zero probability"
+				if a := determineNextQueueAction(&modeCache{current: mode, forceActionTowardsMode: true}, 0.0, 5, 10); a == expected {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, a)
+				}
+			}
+		}
+
+		t.Log("\twhen action towards boundary probability is zero")
+		{
+			for mode, expected := range map[actionMode]operation{fill: poll, drain: put} {
+				msg := "\t\taction away from boundary must be chosen"
+				if a := determineNextQueueAction(&modeCache{current: mode}, 0.0, 5, 10); a == expected {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, a)
+				}
+			}
+		}
+	}
+
+}
+
+func expectedStatusPresent(statusCopy map[string]any, expectedKey statusKey, expectedValue int) (bool, string) {
+
+	recordedValue := statusCopy[string(expectedKey)].(int)
+
+	if recordedValue == expectedValue {
+		return true, ""
+	} else {
+		return false, fmt.Sprintf("expected %d, got %d\n", expectedValue, recordedValue)
+	}
+
+}
+
+func statusContainsExpectedValues(status map[string]any, expectedNumQueues int, expectedPutStatus, expectedPollStatus *operationConfig) (bool, string, string) {
+
+	if numQueuesFromStatus, ok := status[statusKeyNumQueues]; ok && numQueuesFromStatus != expectedNumQueues {
+		return false, statusKeyNumQueues, fmt.Sprintf("want: %d; got: %d", expectedNumQueues, numQueuesFromStatus)
+	}
+
+	if ok, key, detail := operationConfigStatusContainsExpectedValues(status[string(put)].(map[string]any), expectedNumQueues, expectedPutStatus); !ok {
+		return false, fmt.Sprintf("%s.%s", string(put), key), detail
 	}
 
 	if ok, key, detail := operationConfigStatusContainsExpectedValues(status[string(poll)].(map[string]any), expectedNumQueues, expectedPollStatus); !ok {
@@ -720,6 +1960,35 @@ func assembleTestLoop(id uuid.UUID, source string, qs hazelcastwrapper.QueueStor
 
 }
 
+func assembleBoundaryTestLoop(qs hazelcastwrapper.QueueStore, rc *runnerConfig, s sleeper, g *status.Gatherer) *boundaryTestLoop[string] {
+
+	tlc := assembleTestLoopConfig(uuid.New(), testSource, qs, rc)
+	tl := &boundaryTestLoop[string]{}
+	tl.init(&tlc, s, g)
+
+	return tl
+
+}
+
+func assembleBoundaryRunnerConfig(upper, lower, actionProbability float32, chainLength int, numRuns uint32) runnerConfig {
+
+	rc := assembleRunnerConfig(true, 0, true, 0, sleepConfigDisabled, sleepConfigDisabled)
+	rc.loopType = boundary
+	rc.boundary = &boundaryTestLoopConfig{
+		numRuns:                          numRuns,
+		sleepBetweenOperationChains:      sleepConfigDisabled,
+		sleepAfterChainAction:            sleepConfigDisabled,
+		sleepUponModeChange:              &sleepConfig{enabled: true},
+		chainLength:                      chainLength,
+		upper:                            &boundaryDefinition{queueFillPercentage: upper},
+		lower:                            &boundaryDefinition{queueFillPercentage: lower},
+		actionTowardsBoundaryProbability: actionProbability,
+	}
+
+	return rc
+
+}
+
 func assembleTestLoopConfig(id uuid.UUID, source string, qs hazelcastwrapper.QueueStore, rc *runnerConfig) testLoopExecution[string] {
 
 	return testLoopExecution[string]{
@@ -728,7 +1997,7 @@ func assembleTestLoopConfig(id uuid.UUID, source string, qs hazelcastwrapper.Que
 		hzQueueStore: qs,
 		runnerConfig: rc,
 		elements:     aNewHope,
-		ctx:          nil,
+		ctx:          context.TODO(),
 	}
 
 }
@@ -753,12 +2022,14 @@ func assembleRunnerConfig(enablePut bool, numRunsPut int, enablePoll bool, numRu
 	}
 	return runnerConfig{
 		enabled:                     true,
+		role:                        bothRoles,
 		numQueues:                   1,
 		queueBaseName:               "test",
 		appendQueueIndexToQueueName: false,
 		appendClientIdToQueueName:   false,
 		useQueuePrefix:              true,
 		queuePrefix:                 "ht_",
+		preRunClean:                 &preRunCleanConfig{},
 		putConfig:                   &putConfig,
 		pollConfig:                  &pollConfig,
 	}
@@ -2,55 +2,64 @@ package queues
 
 import (
 	"context"
-	"embed"
-	"encoding/gob"
-	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	"hazeltest/api"
 	"hazeltest/client"
 	"hazeltest/hazelcastwrapper"
+	"hazeltest/loadsupport"
+	"hazeltest/state"
 	"hazeltest/status"
-	"io/fs"
 )
 
 type (
 	tweetRunner struct {
-		assigner        client.ConfigPropertyAssigner
-		stateList       []state
-		name            string
-		source          string
-		hzClientHandler hazelcastwrapper.HzClientHandler
-		hzQueueStore    hazelcastwrapper.QueueStore
-		l               looper[tweet]
-		gatherer        *status.Gatherer
-	}
-	tweetCollection struct {
-		Tweets []tweet `json:"Tweets"`
-	}
-	tweet struct {
-		Id        uint64 `json:"Id"`
-		CreatedAt string `json:"CreatedAt"`
-		Text      string `json:"Text"`
+		assigner            client.ConfigPropertyAssigner
+		stateList           []runnerState
+		name                string
+		source              string
+		hzClientHandler     hazelcastwrapper.HzClientHandler
+		hzQueueStore        hazelcastwrapper.QueueStore
+		stateCleanerBuilder state.SingleQueueCleanerBuilder
+		l                   looper[tweet]
+		gatherer            *status.Gatherer
+		providerFuncs       struct {
+			tweetTestLoop newTweetTestLoopFunc
+		}
 	}
+	newTweetTestLoopFunc func(rc *runnerConfig) (looper[tweet], error)
+	// tweet is shared with the TweetRunner for topics, both working on the same embedded data set
+	tweet = loadsupport.Tweet
 )
 
 const queueOperationLoggingUpdateStep = 10
 
-//go:embed tweets_simple.json
-var tweetsFile embed.FS
-
 func init() {
 	register(&tweetRunner{
-		assigner:        &client.DefaultConfigPropertyAssigner{},
-		stateList:       []state{},
-		name:            "queuesTweetRunner",
-		source:          "tweetRunner",
-		hzClientHandler: &hazelcastwrapper.DefaultHzClientHandler{},
-		l:               &testLoop[tweet]{},
+		assigner:            &client.DefaultConfigPropertyAssigner{},
+		stateList:           []runnerState{},
+		name:                "queuesTweetRunner",
+		source:              "tweetRunner",
+		hzClientHandler:     &hazelcastwrapper.DefaultHzClientHandler{},
+		stateCleanerBuilder: &state.DefaultSingleQueueCleanerBuilder{},
+		providerFuncs: struct {
+			tweetTestLoop newTweetTestLoopFunc
+		}{tweetTestLoop: initTweetTestLoop},
 	})
-	gob.Register(tweet{})
+}
+
+func initTweetTestLoop(rc *runnerConfig) (looper[tweet], error) {
+
+	switch rc.loopType {
+	case putPoll:
+		return &testLoop[tweet]{}, nil
+	case boundary:
+		return &boundaryTestLoop[tweet]{}, nil
+	default:
+		return nil, fmt.Errorf("no such runner runnerLoopType: %s", rc.loopType)
+	}
+
 }
 
 func (r *tweetRunner) getSourceName() string {
@@ -77,11 +86,20 @@ func (r *tweetRunner) runQueueTests(hzCluster string, hzMembers []string, gather
 
 	api.RaiseNotReady()
 
-	tc, err := parseTweets()
+	tc, err := loadsupport.ParseTweets()
 	if err != nil {
 		lp.LogIoEvent(fmt.Sprintf("unable to parse tweets json file: %v", err), log.FatalLevel)
 	}
 
+	l, err := r.providerFuncs.tweetTestLoop(config)
+	if err != nil {
+		lp.LogQueueRunnerEvent(fmt.Sprintf("aborting launch of queue tweet runner: unable to initialize test loop: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.l = l
+
+	r.appendState(assignTestLoopComplete)
+
 	ctx := context.TODO()
 
 	r.hzClientHandler.InitHazelcastClient(ctx, r.name, hzCluster, hzMembers)
@@ -96,7 +114,7 @@ func (r *tweetRunner) runQueueTests(hzCluster string, hzMembers []string, gather
 	lp.LogQueueRunnerEvent("initialized hazelcast client", r.name, log.InfoLevel)
 	lp.LogQueueRunnerEvent("started tweets queue loop", r.name, log.InfoLevel)
 
-	lc := &testLoopExecution[tweet]{id: uuid.New(), runnerName: r.name, source: r.source, hzQueueStore: r.hzQueueStore, runnerConfig: config, elements: tc.Tweets, ctx: ctx}
+	lc := &testLoopExecution[tweet]{id: uuid.New(), runnerName: r.name, source: r.source, hzQueueStore: r.hzQueueStore, hzMapStore: &hazelcastwrapper.DefaultMapStore{Client: r.hzClientHandler.GetClient()}, stateCleanerBuilder: r.stateCleanerBuilder, runnerConfig: config, elements: tc.Tweets, validatePayload: tweetValidator(tc.Tweets), ctx: ctx}
 	r.l.init(lc, &defaultSleeper{}, r.gatherer)
 
 	r.appendState(testLoopStart)
@@ -107,36 +125,31 @@ func (r *tweetRunner) runQueueTests(hzCluster string, hzMembers []string, gather
 
 }
 
-func (r *tweetRunner) appendState(s state) {
+func (r *tweetRunner) appendState(s runnerState) {
 	r.stateList = append(r.stateList, s)
 
 	r.gatherer.Updates <- status.Update{Key: string(statusKeyCurrentState), Value: string(s)}
 }
 
-func parseTweets() (*tweetCollection, error) {
+// tweetValidator returns a function checking whether a tweet retrieved from a queue is identical to the tweet having
+// the same ID in the given source data. All TweetRunners work on the same embedded data set, so the tweets a consumer
+// retrieves should be identical to the ones in its own data set regardless of which producer put them.
+func tweetValidator(tweets []tweet) func(tweet) error {
 
-	// TODO Refactor logic related to file parsing into common file? Parsing json files is required in PokedexRunner, too... redundancy vs. coupling
-
-	tweetsJson, err := tweetsFile.Open("tweets_simple.json")
-
-	if err != nil {
-		return nil, err
+	byID := make(map[uint64]tweet, len(tweets))
+	for _, t := range tweets {
+		byID[t.Id] = t
 	}
 
-	defer func(tweetsCsv fs.File) {
-		err := tweetsCsv.Close()
-		if err != nil {
-			lp.LogIoEvent(fmt.Sprintf("unable to close tweets json file: %v", err), log.WarnLevel)
+	return func(t tweet) error {
+		expected, ok := byID[t.Id]
+		if !ok {
+			return fmt.Errorf("no tweet having id %d in source data", t.Id)
 		}
-	}(tweetsJson)
-
-	var tc tweetCollection
-	err = json.NewDecoder(tweetsJson).Decode(&tc)
-
-	if err != nil {
-		return nil, err
+		if t != expected {
+			return fmt.Errorf("tweet having id %d differs from source data", t.Id)
+		}
+		return nil
 	}
 
-	return &tc, nil
-
 }
@@ -16,6 +16,111 @@ func (d testTweetRunnerTestLoop) run() {
 	// No-op
 }
 
+func TestTweetValidator(t *testing.T) {
+
+	t.Log("given a function to validate tweets retrieved from a queue against the source data")
+	{
+		source := []tweet{{Id: 1, CreatedAt: "yesterday", Text: "Hello there"}, {Id: 2, CreatedAt: "today", Text: "General Kenobi"}}
+		validate := tweetValidator(source)
+
+		for _, tc := range []struct {
+			description string
+			tweet       tweet
+			expectError bool
+		}{
+			{"tweet is identical to source tweet having same id", source[1], false},
+			{"tweet has id not contained in source data", tweet{Id: 3, Text: "Hello there"}, true},
+			{"tweet differs from source tweet having same id", tweet{Id: 1, CreatedAt: "yesterday", Text: "Hello where"}, true},
+		} {
+			t.Log("\twhen " + tc.description)
+			{
+				err := validate(tc.tweet)
+
+				if tc.expectError {
+					msg := "\t\terror must be returned"
+					if err != nil {
+						t.Log(msg, checkMark)
+					} else {
+						t.Fatal(msg, ballotX)
+					}
+				} else {
+					msg := "\t\tno error must be returned"
+					if err == nil {
+						t.Log(msg, checkMark)
+					} else {
+						t.Fatal(msg, ballotX, err)
+					}
+				}
+			}
+		}
+	}
+
+}
+
+func TestInitializeTweetTestLoop(t *testing.T) {
+
+	t.Log("given a function to initialize the test loop from the provided loop type")
+	{
+		t.Log("\twhen put/poll test loop type is provided")
+		{
+			l, err := initTweetTestLoop(&runnerConfig{loopType: putPoll})
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tlooper must have expected type"
+			if _, ok := l.(*testLoop[tweet]); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen boundary test loop type is provided")
+		{
+			l, err := initTweetTestLoop(&runnerConfig{loopType: boundary})
+
+			msg := "\t\tno error must be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tlooper must have expected type"
+			if _, ok := l.(*boundaryTestLoop[tweet]); ok {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen unknown test loop type is provided")
+		{
+			l, err := initTweetTestLoop(&runnerConfig{loopType: "saruman"})
+
+			msg := "\t\terror must be returned"
+			if err != nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\tlooper must be nil"
+			if l == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
 func TestRunTweetQueueTests(t *testing.T) {
 
 	t.Log("given a tweet runner to run queue test loops")
@@ -28,14 +133,18 @@ func TestRunTweetQueueTests(t *testing.T) {
 				returnError: true,
 				testConfig:  nil,
 			}
-			r := tweetRunner{assigner: assigner, stateList: []state{}, hzQueueStore: testHzQueueStore{}, l: testTweetRunnerTestLoop{}}
+			r := tweetRunner{assigner: assigner, stateList: []runnerState{}, hzQueueStore: testHzQueueStore{}, providerFuncs: struct {
+				tweetTestLoop newTweetTestLoopFunc
+			}{tweetTestLoop: func(rc *runnerConfig) (looper[tweet], error) {
+				return testTweetRunnerTestLoop{}, nil
+			}}}
 			gatherer := status.NewGatherer()
 			go gatherer.Listen()
 
 			r.runQueueTests(hzCluster, hzMembers, gatherer, initTestQueueStore)
 			gatherer.StopListen()
 
-			if msg, ok := checkRunnerStateTransitions([]state{start}, r.stateList); ok {
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start}, r.stateList); ok {
 				t.Log(genericMsgStateTransitions, checkMark)
 			} else {
 				t.Fatal(genericMsgStateTransitions, ballotX, msg)
@@ -64,7 +173,11 @@ func TestRunTweetQueueTests(t *testing.T) {
 					"queueTests.tweets.enabled": false,
 				},
 			}
-			r := tweetRunner{assigner: assigner, stateList: []state{}, hzQueueStore: testHzQueueStore{}, l: testTweetRunnerTestLoop{}}
+			r := tweetRunner{assigner: assigner, stateList: []runnerState{}, hzQueueStore: testHzQueueStore{}, providerFuncs: struct {
+				tweetTestLoop newTweetTestLoopFunc
+			}{tweetTestLoop: func(rc *runnerConfig) (looper[tweet], error) {
+				return testTweetRunnerTestLoop{}, nil
+			}}}
 			gatherer := status.NewGatherer()
 			go gatherer.Listen()
 
@@ -72,7 +185,7 @@ func TestRunTweetQueueTests(t *testing.T) {
 			gatherer.StopListen()
 
 			latestState := populateConfigComplete
-			if msg, ok := checkRunnerStateTransitions([]state{start, populateConfigComplete}, r.stateList); ok {
+			if msg, ok := checkRunnerStateTransitions([]runnerState{start, populateConfigComplete}, r.stateList); ok {
 				t.Log(genericMsgStateTransitions, checkMark)
 			} else {
 				t.Fatal(genericMsgStateTransitions, ballotX, msg)
@@ -96,7 +209,11 @@ func TestRunTweetQueueTests(t *testing.T) {
 				},
 			}
 			ch := &testHzClientHandler{}
-			r := tweetRunner{assigner: assigner, stateList: []state{}, hzQueueStore: testHzQueueStore{}, l: testTweetRunnerTestLoop{}, hzClientHandler: ch}
+			r := tweetRunner{assigner: assigner, stateList: []runnerState{}, hzQueueStore: testHzQueueStore{}, providerFuncs: struct {
+				tweetTestLoop newTweetTestLoopFunc
+			}{tweetTestLoop: func(rc *runnerConfig) (looper[tweet], error) {
+				return testTweetRunnerTestLoop{}, nil
+			}}, hzClientHandler: ch}
 
 			gatherer := status.NewGatherer()
 			go gatherer.Listen()
@@ -54,6 +54,57 @@ type (
 		cih       LastCleanedInfoHandler
 		t         CleanedTracker
 	}
+	DefaultBatchMultiMapCleanerBuilder struct {
+		cfb cleanerConfigBuilder
+	}
+	DefaultBatchMultiMapCleaner struct {
+		ctx       context.Context
+		name      string
+		hzCluster string
+		hzMembers []string
+		keyPath   string
+		cfg       *cleanerConfig
+		mms       hazelcastwrapper.MultiMapStore
+		ms        hazelcastwrapper.MapStore
+		ois       hazelcastwrapper.ObjectInfoStore
+		ch        hazelcastwrapper.HzClientHandler
+		cih       LastCleanedInfoHandler
+		t         CleanedTracker
+	}
+	DefaultBatchListCleanerBuilder struct {
+		cfb cleanerConfigBuilder
+	}
+	DefaultBatchListCleaner struct {
+		ctx       context.Context
+		name      string
+		hzCluster string
+		hzMembers []string
+		keyPath   string
+		cfg       *cleanerConfig
+		ls        hazelcastwrapper.ListStore
+		ms        hazelcastwrapper.MapStore
+		ois       hazelcastwrapper.ObjectInfoStore
+		ch        hazelcastwrapper.HzClientHandler
+		cih       LastCleanedInfoHandler
+		t         CleanedTracker
+	}
+	DefaultBatchSetCleanerBuilder struct {
+		cfb cleanerConfigBuilder
+	}
+	DefaultBatchSetCleaner struct {
+		ctx       context.Context
+		name      string
+		hzCluster string
+		hzMembers []string
+		keyPath   string
+		cfg       *cleanerConfig
+		ss        hazelcastwrapper.SetStore
+		ms        hazelcastwrapper.MapStore
+		ois       hazelcastwrapper.ObjectInfoStore
+		ch        hazelcastwrapper.HzClientHandler
+		cih       LastCleanedInfoHandler
+		t         CleanedTracker
+	}
 )
 
 type (
@@ -84,7 +135,7 @@ type (
 	// the target Hazelcast cluster and capabilities for accessing it, the same thoughts as on the
 	// SingleMapCleanerBuilder interface apply.
 	SingleQueueCleanerBuilder interface {
-		Build(ctx context.Context, qs hazelcastwrapper.QueueStore, ms hazelcastwrapper.MapStore, t CleanedTracker, cih LastCleanedInfoHandler) SingleCleaner
+		Build(ctx context.Context, qs hazelcastwrapper.QueueStore, ms hazelcastwrapper.MapStore, t CleanedTracker, cih LastCleanedInfoHandler) (SingleCleaner, string)
 	}
 	DefaultSingleMapCleanerBuilder struct{}
 	DefaultSingleMapCleaner        struct {
@@ -101,6 +152,30 @@ type (
 		cih LastCleanedInfoHandler
 		t   CleanedTracker
 	}
+	DefaultSingleMultiMapCleanerBuilder struct{}
+	DefaultSingleMultiMapCleaner        struct {
+		ctx context.Context
+		ms  hazelcastwrapper.MapStore
+		mms hazelcastwrapper.MultiMapStore
+		cih LastCleanedInfoHandler
+		t   CleanedTracker
+	}
+	DefaultSingleListCleanerBuilder struct{}
+	DefaultSingleListCleaner        struct {
+		ctx context.Context
+		ms  hazelcastwrapper.MapStore
+		ls  hazelcastwrapper.ListStore
+		cih LastCleanedInfoHandler
+		t   CleanedTracker
+	}
+	DefaultSingleSetCleanerBuilder struct{}
+	DefaultSingleSetCleaner        struct {
+		ctx context.Context
+		ms  hazelcastwrapper.MapStore
+		ss  hazelcastwrapper.SetStore
+		cih LastCleanedInfoHandler
+		t   CleanedTracker
+	}
 )
 
 type (
@@ -170,16 +245,25 @@ const (
 )
 
 const (
-	HzMapService   = "hz:impl:mapService"
-	HzQueueService = "hz:impl:queueService"
+	HzMapService      = "hz:impl:mapService"
+	HzQueueService    = "hz:impl:queueService"
+	HzMultiMapService = "hz:impl:multiMapService"
+	HzListService     = "hz:impl:listService"
+	HzSetService      = "hz:impl:setService"
 )
 
 const (
 	mapCleanerBasePath            = "stateCleaners.maps"
 	queueCleanerBasePath          = "stateCleaners.queues"
+	multiMapCleanerBasePath       = "stateCleaners.multiMaps"
+	listCleanerBasePath           = "stateCleaners.lists"
+	setCleanerBasePath            = "stateCleaners.sets"
 	hzInternalDataStructurePrefix = "__"
 	mapCleanersSyncMapName        = hzInternalDataStructurePrefix + "ht.mapCleaners"
 	queueCleanersSyncMapName      = hzInternalDataStructurePrefix + "ht.queueCleaners"
+	multiMapCleanersSyncMapName   = hzInternalDataStructurePrefix + "ht.multiMapCleaners"
+	listCleanersSyncMapName       = hzInternalDataStructurePrefix + "ht.listCleaners"
+	setCleanersSyncMapName        = hzInternalDataStructurePrefix + "ht.setCleaners"
 )
 
 var (
@@ -191,6 +275,9 @@ var (
 func init() {
 	register(newMapCleanerBuilder())
 	register(newQueueCleanerBuilder())
+	register(newMultiMapCleanerBuilder())
+	register(newListCleanerBuilder())
+	register(newSetCleanerBuilder())
 	lp = logging.GetLogProviderInstance(client.ID())
 }
 
@@ -216,6 +303,39 @@ func newQueueCleanerBuilder() *DefaultBatchQueueCleanerBuilder {
 
 }
 
+func newMultiMapCleanerBuilder() *DefaultBatchMultiMapCleanerBuilder {
+
+	return &DefaultBatchMultiMapCleanerBuilder{
+		cfb: cleanerConfigBuilder{
+			keyPath: multiMapCleanerBasePath,
+			a:       client.DefaultConfigPropertyAssigner{},
+		},
+	}
+
+}
+
+func newListCleanerBuilder() *DefaultBatchListCleanerBuilder {
+
+	return &DefaultBatchListCleanerBuilder{
+		cfb: cleanerConfigBuilder{
+			keyPath: listCleanerBasePath,
+			a:       client.DefaultConfigPropertyAssigner{},
+		},
+	}
+
+}
+
+func newSetCleanerBuilder() *DefaultBatchSetCleanerBuilder {
+
+	return &DefaultBatchSetCleanerBuilder{
+		cfb: cleanerConfigBuilder{
+			keyPath: setCleanerBasePath,
+			a:       client.DefaultConfigPropertyAssigner{},
+		},
+	}
+
+}
+
 func register(cb BatchCleanerBuilder) {
 	builders = append(builders, cb)
 }
@@ -437,6 +557,132 @@ func (b *DefaultBatchQueueCleanerBuilder) Build(ch hazelcastwrapper.HzClientHand
 
 }
 
+func (b *DefaultBatchMultiMapCleanerBuilder) Build(ch hazelcastwrapper.HzClientHandler, ctx context.Context, g *status.Gatherer, hzCluster string, hzMembers []string) (BatchCleaner, string, error) {
+
+	config, err := b.cfb.populateConfig()
+
+	if err != nil {
+		lp.LogStateCleanerEvent(fmt.Sprintf("unable to populate state cleaner config for key path '%s' due to error: %v", b.cfb.keyPath, err), HzMultiMapService, log.ErrorLevel)
+		return nil, HzMultiMapService, err
+	}
+
+	clientName := "multiMapCleaner"
+	ch.InitHazelcastClient(ctx, clientName, hzCluster, hzMembers)
+
+	ms := &hazelcastwrapper.DefaultMapStore{Client: ch.GetClient()}
+	cih := &DefaultLastCleanedInfoHandler{
+		Ms:  ms,
+		Ctx: ctx,
+		Cfg: &LastCleanedInfoHandlerConfig{
+			UseCleanAgainThreshold: config.useCleanAgainThreshold,
+			CleanAgainThresholdMs:  config.cleanAgainThresholdMs,
+		},
+	}
+
+	t := &CleanedDataStructureTracker{g}
+	api.RegisterStatefulActor(api.StateCleaners, clientName, t.G.AssembleStatusCopy)
+
+	return &DefaultBatchMultiMapCleaner{
+		ctx:       ctx,
+		name:      clientName,
+		hzCluster: hzCluster,
+		hzMembers: hzMembers,
+		keyPath:   b.cfb.keyPath,
+		cfg:       config,
+		mms:       &hazelcastwrapper.DefaultMultiMapStore{Client: ch.GetClient()},
+		ms:        ms,
+		ois:       &hazelcastwrapper.DefaultObjectInfoStore{Client: ch.GetClient()},
+		ch:        ch,
+		cih:       cih,
+		t:         t,
+	}, HzMultiMapService, nil
+
+}
+
+func (b *DefaultBatchListCleanerBuilder) Build(ch hazelcastwrapper.HzClientHandler, ctx context.Context, g *status.Gatherer, hzCluster string, hzMembers []string) (BatchCleaner, string, error) {
+
+	config, err := b.cfb.populateConfig()
+
+	if err != nil {
+		lp.LogStateCleanerEvent(fmt.Sprintf("unable to populate state cleaner config for key path '%s' due to error: %v", b.cfb.keyPath, err), HzListService, log.ErrorLevel)
+		return nil, HzListService, err
+	}
+
+	clientName := "listCleaner"
+	ch.InitHazelcastClient(ctx, clientName, hzCluster, hzMembers)
+
+	ms := &hazelcastwrapper.DefaultMapStore{Client: ch.GetClient()}
+	cih := &DefaultLastCleanedInfoHandler{
+		Ms:  ms,
+		Ctx: ctx,
+		Cfg: &LastCleanedInfoHandlerConfig{
+			UseCleanAgainThreshold: config.useCleanAgainThreshold,
+			CleanAgainThresholdMs:  config.cleanAgainThresholdMs,
+		},
+	}
+
+	t := &CleanedDataStructureTracker{g}
+	api.RegisterStatefulActor(api.StateCleaners, clientName, t.G.AssembleStatusCopy)
+
+	return &DefaultBatchListCleaner{
+		ctx:       ctx,
+		name:      clientName,
+		hzCluster: hzCluster,
+		hzMembers: hzMembers,
+		keyPath:   b.cfb.keyPath,
+		cfg:       config,
+		ls:        &hazelcastwrapper.DefaultListStore{Client: ch.GetClient()},
+		ms:        ms,
+		ois:       &hazelcastwrapper.DefaultObjectInfoStore{Client: ch.GetClient()},
+		ch:        ch,
+		cih:       cih,
+		t:         t,
+	}, HzListService, nil
+
+}
+
+func (b *DefaultBatchSetCleanerBuilder) Build(ch hazelcastwrapper.HzClientHandler, ctx context.Context, g *status.Gatherer, hzCluster string, hzMembers []string) (BatchCleaner, string, error) {
+
+	config, err := b.cfb.populateConfig()
+
+	if err != nil {
+		lp.LogStateCleanerEvent(fmt.Sprintf("unable to populate state cleaner config for key path '%s' due to error: %v", b.cfb.keyPath, err), HzSetService, log.ErrorLevel)
+		return nil, HzSetService, err
+	}
+
+	clientName := "setCleaner"
+	ch.InitHazelcastClient(ctx, clientName, hzCluster, hzMembers)
+
+	ms := &hazelcastwrapper.DefaultMapStore{Client: ch.GetClient()}
+	cih := &DefaultLastCleanedInfoHandler{
+		Ms:  ms,
+		Ctx: ctx,
+		Cfg: &LastCleanedInfoHandlerConfig{
+			UseCleanAgainThreshold: config.useCleanAgainThreshold,
+			CleanAgainThresholdMs:  config.cleanAgainThresholdMs,
+		},
+	}
+
+	t := &CleanedDataStructureTracker{g}
+	api.RegisterStatefulActor(api.StateCleaners, clientName, t.G.AssembleStatusCopy)
+
+	return &DefaultBatchSetCleaner{
+		ctx:       ctx,
+		name:      clientName,
+		hzCluster: hzCluster,
+		hzMembers: hzMembers,
+		keyPath:   b.cfb.keyPath,
+		cfg:       config,
+		ss:        &hazelcastwrapper.DefaultSetStore{Client: ch.GetClient()},
+		ms:        ms,
+		ois:       &hazelcastwrapper.DefaultObjectInfoStore{Client: ch.GetClient()},
+		ch:        ch,
+		cih:       cih,
+		t:         t,
+	}, HzSetService, nil
+
+}
+
 func releaseLock(ctx context.Context, lockInfo mapLockInfo, hzService string) error {
 
 	if lockInfo == emptyMapLockInfo {
@@ -644,6 +890,42 @@ func (b *DefaultSingleQueueCleanerBuilder) Build(ctx context.Context, qs hazelca
 
 }
 
+func (b *DefaultSingleMultiMapCleanerBuilder) Build(ctx context.Context, mms hazelcastwrapper.MultiMapStore, ms hazelcastwrapper.MapStore, t CleanedTracker, cih LastCleanedInfoHandler) (SingleCleaner, string) {
+
+	return &DefaultSingleMultiMapCleaner{
+		ctx: ctx,
+		mms: mms,
+		ms:  ms,
+		cih: cih,
+		t:   t,
+	}, HzMultiMapService
+
+}
+
+func (b *DefaultSingleListCleanerBuilder) Build(ctx context.Context, ls hazelcastwrapper.ListStore, ms hazelcastwrapper.MapStore, t CleanedTracker, cih LastCleanedInfoHandler) (SingleCleaner, string) {
+
+	return &DefaultSingleListCleaner{
+		ctx: ctx,
+		ls:  ls,
+		ms:  ms,
+		cih: cih,
+		t:   t,
+	}, HzListService
+
+}
+
+func (b *DefaultSingleSetCleanerBuilder) Build(ctx context.Context, ss hazelcastwrapper.SetStore, ms hazelcastwrapper.MapStore, t CleanedTracker, cih LastCleanedInfoHandler) (SingleCleaner, string) {
+
+	return &DefaultSingleSetCleaner{
+		ctx: ctx,
+		ss:  ss,
+		ms:  ms,
+		cih: cih,
+		t:   t,
+	}, HzSetService
+
+}
+
 func (c *DefaultSingleQueueCleaner) retrieveAndClean(payloadQueueName string) (int, error) {
 
 	queueToClean, err := c.qs.GetQueue(c.ctx, payloadQueueName)
@@ -682,6 +964,120 @@ func (c *DefaultSingleQueueCleaner) retrieveAndClean(payloadQueueName string) (i
 
 }
 
+func (c *DefaultSingleMultiMapCleaner) retrieveAndClean(payloadMultiMapName string) (int, error) {
+
+	multiMapToClean, err := c.mms.GetMultiMap(c.ctx, payloadMultiMapName)
+
+	if err != nil {
+		lp.LogStateCleanerEvent(fmt.Sprintf("cannot clean '%s' due to error upon retrieval of proxy object from Hazelcast cluster: %v", payloadMultiMapName, err), HzMultiMapService, log.ErrorLevel)
+		return 0, err
+	}
+
+	if multiMapToClean == nil {
+		msg := fmt.Sprintf("cannot clean '%s' because multimap retrieved from target Hazelcast cluster was nil", payloadMultiMapName)
+		lp.LogStateCleanerEvent(msg, HzMultiMapService, log.ErrorLevel)
+		return 0, errors.New(msg)
+	}
+
+	size, err := multiMapToClean.Size(c.ctx)
+	if err != nil {
+		msg := fmt.Sprintf("unable to clean '%s' because size check failed with error: %v", payloadMultiMapName, err)
+		lp.LogStateCleanerEvent(msg, HzMultiMapService, log.ErrorLevel)
+		return 0, err
+	}
+
+	if size == 0 {
+		lp.LogStateCleanerEvent(fmt.Sprintf("payload multimap '%s' does not currently hold any items -- skipping", payloadMultiMapName), HzMultiMapService, log.DebugLevel)
+		return 0, nil
+	}
+
+	lp.LogStateCleanerEvent(fmt.Sprintf("payload multimap '%s' currently holds %d elements -- proceeding to clean", payloadMultiMapName, size), HzMultiMapService, log.DebugLevel)
+
+	if err := multiMapToClean.Clear(c.ctx); err != nil {
+		lp.LogStateCleanerEvent(fmt.Sprintf("encountered error upon cleaning '%s': %v", payloadMultiMapName, err), HzMultiMapService, log.ErrorLevel)
+		return 0, err
+	}
+
+	return size, nil
+
+}
+
+func (c *DefaultSingleListCleaner) retrieveAndClean(payloadListName string) (int, error) {
+
+	listToClean, err := c.ls.GetList(c.ctx, payloadListName)
+
+	if err != nil {
+		lp.LogStateCleanerEvent(fmt.Sprintf("cannot clean '%s' due to error upon retrieval of proxy object from Hazelcast cluster: %v", payloadListName, err), HzListService, log.ErrorLevel)
+		return 0, err
+	}
+
+	if listToClean == nil {
+		msg := fmt.Sprintf("cannot clean '%s' because list retrieved from target Hazelcast cluster was nil", payloadListName)
+		lp.LogStateCleanerEvent(msg, HzListService, log.ErrorLevel)
+		return 0, errors.New(msg)
+	}
+
+	size, err := listToClean.Size(c.ctx)
+	if err != nil {
+		msg := fmt.Sprintf("unable to clean '%s' because size check failed with error: %v", payloadListName, err)
+		lp.LogStateCleanerEvent(msg, HzListService, log.ErrorLevel)
+		return 0, err
+	}
+
+	if size == 0 {
+		lp.LogStateCleanerEvent(fmt.Sprintf("payload list '%s' does not currently hold any items -- skipping", payloadListName), HzListService, log.DebugLevel)
+		return 0, nil
+	}
+
+	lp.LogStateCleanerEvent(fmt.Sprintf("payload list '%s' currently holds %d elements -- proceeding to clean", payloadListName, size), HzListService, log.DebugLevel)
+
+	if err := listToClean.Clear(c.ctx); err != nil {
+		lp.LogStateCleanerEvent(fmt.Sprintf("encountered error upon cleaning '%s': %v", payloadListName, err), HzListService, log.ErrorLevel)
+		return 0, err
+	}
+
+	return size, nil
+
+}
+
+func (c *DefaultSingleSetCleaner) retrieveAndClean(payloadSetName string) (int, error) {
+
+	setToClean, err := c.ss.GetSet(c.ctx, payloadSetName)
+
+	if err != nil {
+		lp.LogStateCleanerEvent(fmt.Sprintf("cannot clean '%s' due to error upon retrieval of proxy object from Hazelcast cluster: %v", payloadSetName, err), HzSetService, log.ErrorLevel)
+		return 0, err
+	}
+
+	if setToClean == nil {
+		msg := fmt.Sprintf("cannot clean '%s' because set retrieved from target Hazelcast cluster was nil", payloadSetName)
+		lp.LogStateCleanerEvent(msg, HzSetService, log.ErrorLevel)
+		return 0, errors.New(msg)
+	}
+
+	size, err := setToClean.Size(c.ctx)
+	if err != nil {
+		msg := fmt.Sprintf("unable to clean '%s' because size check failed with error: %v", payloadSetName, err)
+		lp.LogStateCleanerEvent(msg, HzSetService, log.ErrorLevel)
+		return 0, err
+	}
+
+	if size == 0 {
+		lp.LogStateCleanerEvent(fmt.Sprintf("payload set '%s' does not currently hold any items -- skipping", payloadSetName), HzSetService, log.DebugLevel)
+		return 0, nil
+	}
+
+	lp.LogStateCleanerEvent(fmt.Sprintf("payload set '%s' currently holds %d elements -- proceeding to clean", payloadSetName, size), HzSetService, log.DebugLevel)
+
+	if err := setToClean.Clear(c.ctx); err != nil {
+		lp.LogStateCleanerEvent(fmt.Sprintf("encountered error upon cleaning '%s': %v", payloadSetName, err), HzSetService, log.ErrorLevel)
+		return 0, err
+	}
+
+	return size, nil
+
+}
+
 func (c *DefaultSingleQueueCleaner) Clean(name string) (int, error) {
 
 	return runGenericSingleClean(
@@ -696,6 +1092,48 @@ func (c *DefaultSingleQueueCleaner) Clean(name string) (int, error) {
 
 }
 
+func (c *DefaultSingleMultiMapCleaner) Clean(name string) (int, error) {
+
+	return runGenericSingleClean(
+		c.ctx,
+		c.cih,
+		c.t,
+		multiMapCleanersSyncMapName,
+		name,
+		HzMultiMapService,
+		c.retrieveAndClean,
+	)
+
+}
+
+func (c *DefaultSingleListCleaner) Clean(name string) (int, error) {
+
+	return runGenericSingleClean(
+		c.ctx,
+		c.cih,
+		c.t,
+		listCleanersSyncMapName,
+		name,
+		HzListService,
+		c.retrieveAndClean,
+	)
+
+}
+
+func (c *DefaultSingleSetCleaner) Clean(name string) (int, error) {
+
+	return runGenericSingleClean(
+		c.ctx,
+		c.cih,
+		c.t,
+		setCleanersSyncMapName,
+		name,
+		HzSetService,
+		c.retrieveAndClean,
+	)
+
+}
+
 func (c *DefaultBatchQueueCleaner) Clean() (int, error) {
 
 	defer func() {
@@ -721,6 +1159,81 @@ func (c *DefaultBatchQueueCleaner) Clean() (int, error) {
 
 }
 
+func (c *DefaultBatchMultiMapCleaner) Clean() (int, error) {
+
+	defer func() {
+		_ = c.ch.Shutdown(c.ctx)
+	}()
+
+	if !c.cfg.enabled {
+		lp.LogStateCleanerEvent(fmt.Sprintf("multimap cleaner '%s' not enabled; won't run", c.name), HzMultiMapService, log.InfoLevel)
+		return 0, nil
+	}
+
+	b := DefaultSingleMultiMapCleanerBuilder{}
+	sc, _ := b.Build(c.ctx, c.mms, c.ms, c.t, c.cih)
+	numCleaned, err := runGenericBatchClean(
+		c.ctx,
+		c.ois,
+		HzMultiMapService,
+		c.cfg,
+		sc,
+	)
+
+	return numCleaned, err
+
+}
+
+func (c *DefaultBatchListCleaner) Clean() (int, error) {
+
+	defer func() {
+		_ = c.ch.Shutdown(c.ctx)
+	}()
+
+	if !c.cfg.enabled {
+		lp.LogStateCleanerEvent(fmt.Sprintf("list cleaner '%s' not enabled; won't run", c.name), HzListService, log.InfoLevel)
+		return 0, nil
+	}
+
+	b := DefaultSingleListCleanerBuilder{}
+	sc, _ := b.Build(c.ctx, c.ls, c.ms, c.t, c.cih)
+	numCleaned, err := runGenericBatchClean(
+		c.ctx,
+		c.ois,
+		HzListService,
+		c.cfg,
+		sc,
+	)
+
+	return numCleaned, err
+
+}
+
+func (c *DefaultBatchSetCleaner) Clean() (int, error) {
+
+	defer func() {
+		_ = c.ch.Shutdown(c.ctx)
+	}()
+
+	if !c.cfg.enabled {
+		lp.LogStateCleanerEvent(fmt.Sprintf("set cleaner '%s' not enabled; won't run", c.name), HzSetService, log.InfoLevel)
+		return 0, nil
+	}
+
+	b := DefaultSingleSetCleanerBuilder{}
+	sc, _ := b.Build(c.ctx, c.ss, c.ms, c.t, c.cih)
+	numCleaned, err := runGenericBatchClean(
+		c.ctx,
+		c.ois,
+		HzSetService,
+		c.cfg,
+		sc,
+	)
+
+	return numCleaned, err
+
+}
+
 func RunCleaners(hzCluster string, hzMembers []string) error {
 
 	for _, b := range builders {
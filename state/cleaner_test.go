@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/hazelcast/hazelcast-go-client"
 	"github.com/hazelcast/hazelcast-go-client/predicate"
+	"github.com/hazelcast/hazelcast-go-client/types"
 	"hazeltest/hazelcastwrapper"
 	"hazeltest/status"
 	"strings"
@@ -96,6 +97,29 @@ type (
 	testCleanedTracker struct {
 		numAddInvocations int
 	}
+	// testHzCollectionStore stands in for the multimap, list, and set stores alike, as the cleaners for these data
+	// structures only ever ask for their size and clear them.
+	testHzCollectionStore struct {
+		collections                  map[string]*testHzCollection
+		getCollectionInvocations     int
+		returnErrorUponGetCollection bool
+	}
+	testHzCollection struct {
+		numItems             int
+		clearInvocations     int
+		sizeInvocations      int
+		returnErrorUponClear bool
+		returnErrorUponSize  bool
+	}
+	// collectionCleanerTestSetup describes how to assemble the single cleaner for one of the collection-like data
+	// structures, so the same test cases can be run against all of them.
+	collectionCleanerTestSetup struct {
+		kind        string
+		hzService   string
+		syncMapName string
+		build       func(ctx context.Context, cs *testHzCollectionStore, ms hazelcastwrapper.MapStore, t CleanedTracker, cih LastCleanedInfoHandler) (SingleCleaner, string)
+		retrieve    func(c SingleCleaner, name string) (int, error)
+	}
 )
 
 func (ch *testHzClientHandler) GetClusterName() string {
@@ -130,6 +154,26 @@ func (q *testHzQueue) Poll(_ context.Context) (any, error) {
 	return nil, nil
 }
 
+func (q *testHzQueue) AddWithTimeout(_ context.Context, _ any, _ time.Duration) (bool, error) {
+	return false, nil
+}
+
+func (q *testHzQueue) AddAll(_ context.Context, _ ...any) (bool, error) {
+	return false, nil
+}
+
+func (q *testHzQueue) PollWithTimeout(_ context.Context, _ time.Duration) (any, error) {
+	return nil, nil
+}
+
+func (q *testHzQueue) Take(_ context.Context) (any, error) {
+	return nil, nil
+}
+
+func (q *testHzQueue) DrainWithMaxSize(_ context.Context, _ int) ([]any, error) {
+	return nil, nil
+}
+
 func (q *testHzQueue) RemainingCapacity(_ context.Context) (int, error) {
 	return 0, nil
 }
@@ -154,6 +198,18 @@ func (m *testHzMap) RemoveAll(_ context.Context, _ predicate.Predicate) error {
 	return nil
 }
 
+func (m *testHzMap) GetEntrySetWithPredicate(_ context.Context, _ predicate.Predicate) ([]types.Entry, error) {
+	return nil, nil
+}
+
+func (m *testHzMap) GetKeySetWithPredicate(_ context.Context, _ predicate.Predicate) ([]any, error) {
+	return nil, nil
+}
+
+func (m *testHzMap) GetValuesWithPredicate(_ context.Context, _ predicate.Predicate) ([]any, error) {
+	return nil, nil
+}
+
 const (
 	checkMark        = "\u2713"
 	ballotX          = "\u2717"
@@ -186,6 +242,9 @@ var (
 	getQueueError                 = errors.New("something somewhere went terribly wrong when attempting to get a queue from the target hazelcast cluster")
 	queueClearError               = errors.New("something somewhere went terribly wrong upon attempt to perform clear operation on queue")
 	queueSizeError                = errors.New("something somewhere went terribly wrong upon attempt to query the queue's size")
+	getCollectionError            = errors.New("something somewhere went terribly wrong when attempting to get a collection from the target hazelcast cluster")
+	collectionClearError          = errors.New("something somewhere went terribly wrong upon attempt to perform clear operation on collection")
+	collectionSizeError           = errors.New("something somewhere went terribly wrong upon attempt to query the collection's size")
 	lastCleanedInfoCheckError     = errors.New("something somewhere went terribly wrong upon attempt to check last cleaned info")
 	lastCleanedInfoUpdateError    = errors.New("something somewhere went terribly wrong upon attempt to update last cleaned info")
 	tryLockError                  = errors.New("something somewhere went terribly wrong upon attempt to acquire a lock")
@@ -241,6 +300,14 @@ func (m *testHzMap) Unlock(_ context.Context, _ any) error {
 
 }
 
+func (m *testHzMap) AddListenerWithPredicate(_ context.Context, _ hazelcast.MapListener, _ predicate.Predicate, _ bool) (types.UUID, error) {
+	return types.UUID{}, nil
+}
+
+func (m *testHzMap) RemoveListener(_ context.Context, _ types.UUID) error {
+	return nil
+}
+
 func (m *testHzMap) Get(_ context.Context, payloadDataStructureName any) (any, error) {
 
 	m.getInvocations++
@@ -4559,6 +4626,9 @@ func populateTestMapStore(numPayloadMapObjects int, objectNamePrefixes []string,
 
 	testMaps[mapCleanersSyncMapName] = &testHzMap{data: make(map[string]any)}
 	testMaps[queueCleanersSyncMapName] = &testHzMap{data: make(map[string]any)}
+	testMaps[multiMapCleanersSyncMapName] = &testHzMap{data: make(map[string]any)}
+	testMaps[listCleanersSyncMapName] = &testHzMap{data: make(map[string]any)}
+	testMaps[setCleanersSyncMapName] = &testHzMap{data: make(map[string]any)}
 
 	return &testHzMapStore{maps: testMaps}
 
@@ -4623,3 +4693,463 @@ func assembleBatchMapCleaner(c *cleanerConfig, ms *testHzMapStore, ois *testHzOb
 	}
 
 }
+
+func (cs *testHzCollectionStore) get(name string) (*testHzCollection, error) {
+
+	cs.getCollectionInvocations++
+
+	if cs.returnErrorUponGetCollection {
+		return nil, getCollectionError
+	}
+
+	if v, exists := cs.collections[name]; exists {
+		return v, nil
+	}
+
+	return nil, nil
+
+}
+
+func (cs *testHzCollectionStore) GetMultiMap(_ context.Context, name string) (hazelcastwrapper.MultiMap, error) {
+
+	c, err := cs.get(name)
+	if c == nil {
+		// Return untyped nil so the cleaner's nil check works as it would on a proxy object that was not found
+		return nil, err
+	}
+
+	return c, err
+
+}
+
+func (cs *testHzCollectionStore) GetList(_ context.Context, name string) (hazelcastwrapper.List, error) {
+
+	c, err := cs.get(name)
+	if c == nil {
+		return nil, err
+	}
+
+	return c, err
+
+}
+
+func (cs *testHzCollectionStore) GetSet(_ context.Context, name string) (hazelcastwrapper.Set, error) {
+
+	c, err := cs.get(name)
+	if c == nil {
+		return nil, err
+	}
+
+	return c, err
+
+}
+
+func (c *testHzCollection) Put(_ context.Context, _ any, _ any) (bool, error) {
+	panic("implement me if required")
+}
+
+func (c *testHzCollection) ContainsEntry(_ context.Context, _ any, _ any) (bool, error) {
+	panic("implement me if required")
+}
+
+func (c *testHzCollection) RemoveEntry(_ context.Context, _ any, _ any) (bool, error) {
+	panic("implement me if required")
+}
+
+func (c *testHzCollection) Add(_ context.Context, _ any) (bool, error) {
+	panic("implement me if required")
+}
+
+func (c *testHzCollection) Contains(_ context.Context, _ any) (bool, error) {
+	panic("implement me if required")
+}
+
+func (c *testHzCollection) Remove(_ context.Context, _ any) (bool, error) {
+	panic("implement me if required")
+}
+
+func (c *testHzCollection) Destroy(_ context.Context) error {
+	panic("implement me if required")
+}
+
+func (c *testHzCollection) Clear(_ context.Context) error {
+
+	c.clearInvocations++
+
+	if c.returnErrorUponClear {
+		return collectionClearError
+	}
+
+	c.numItems = 0
+	return nil
+
+}
+
+func (c *testHzCollection) Size(_ context.Context) (int, error) {
+
+	c.sizeInvocations++
+
+	if c.returnErrorUponSize {
+		return 0, collectionSizeError
+	}
+
+	return c.numItems, nil
+
+}
+
+func populateTestCollectionStore(names []string, numItemsInCollections int) *testHzCollectionStore {
+
+	collections := make(map[string]*testHzCollection)
+	for _, v := range names {
+		collections[v] = &testHzCollection{numItems: numItemsInCollections}
+	}
+
+	return &testHzCollectionStore{collections: collections}
+
+}
+
+func collectionCleanerTestSetups() []collectionCleanerTestSetup {
+
+	return []collectionCleanerTestSetup{
+		{
+			kind:        "multimap",
+			hzService:   HzMultiMapService,
+			syncMapName: multiMapCleanersSyncMapName,
+			build: func(ctx context.Context, cs *testHzCollectionStore, ms hazelcastwrapper.MapStore, t CleanedTracker, cih LastCleanedInfoHandler) (SingleCleaner, string) {
+				return (&DefaultSingleMultiMapCleanerBuilder{}).Build(ctx, cs, ms, t, cih)
+			},
+			retrieve: func(c SingleCleaner, name string) (int, error) {
+				return c.(*DefaultSingleMultiMapCleaner).retrieveAndClean(name)
+			},
+		},
+		{
+			kind:        "list",
+			hzService:   HzListService,
+			syncMapName: listCleanersSyncMapName,
+			build: func(ctx context.Context, cs *testHzCollectionStore, ms hazelcastwrapper.MapStore, t CleanedTracker, cih LastCleanedInfoHandler) (SingleCleaner, string) {
+				return (&DefaultSingleListCleanerBuilder{}).Build(ctx, cs, ms, t, cih)
+			},
+			retrieve: func(c SingleCleaner, name string) (int, error) {
+				return c.(*DefaultSingleListCleaner).retrieveAndClean(name)
+			},
+		},
+		{
+			kind:        "set",
+			hzService:   HzSetService,
+			syncMapName: setCleanersSyncMapName,
+			build: func(ctx context.Context, cs *testHzCollectionStore, ms hazelcastwrapper.MapStore, t CleanedTracker, cih LastCleanedInfoHandler) (SingleCleaner, string) {
+				return (&DefaultSingleSetCleanerBuilder{}).Build(ctx, cs, ms, t, cih)
+			},
+			retrieve: func(c SingleCleaner, name string) (int, error) {
+				return c.(*DefaultSingleSetCleaner).retrieveAndClean(name)
+			},
+		},
+	}
+
+}
+
+func TestDefaultSingleCollectionCleaners_retrieveAndClean(t *testing.T) {
+
+	for _, setup := range collectionCleanerTestSetups() {
+		t.Logf("given a %s in a target hazelcast cluster to be retrieved and cleaned", setup.kind)
+		{
+			payloadName := "ht_load-0"
+
+			t.Log("	when retrieval yields error")
+			{
+				cs := populateTestCollectionStore([]string{payloadName}, 0)
+				cs.returnErrorUponGetCollection = true
+				c, _ := setup.build(context.TODO(), cs, nil, nil, nil)
+
+				numCleanedItems, err := setup.retrieve(c, payloadName)
+
+				msg := "		error must be returned"
+				if errors.Is(err, getCollectionError) {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, err)
+				}
+
+				msg = "		reported number of cleaned items must be zero"
+				if numCleanedItems == 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, numCleanedItems)
+				}
+			}
+			t.Log("	when retrieval is successful, but data structure is nil")
+			{
+				cs := populateTestCollectionStore([]string{payloadName}, 0)
+				c, _ := setup.build(context.TODO(), cs, nil, nil, nil)
+
+				numCleanedItems, err := setup.retrieve(c, "blubbo")
+
+				msg := "		error must be returned"
+				if err != nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX)
+				}
+
+				msg = "		reported number of cleaned items must be zero"
+				if numCleanedItems == 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, numCleanedItems)
+				}
+			}
+			t.Log("	when size check fails")
+			{
+				cs := populateTestCollectionStore([]string{payloadName}, 3)
+				cs.collections[payloadName].returnErrorUponSize = true
+				c, _ := setup.build(context.TODO(), cs, nil, nil, nil)
+
+				numCleanedItems, err := setup.retrieve(c, payloadName)
+
+				msg := "		error must be returned"
+				if errors.Is(err, collectionSizeError) {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, err)
+				}
+
+				msg = "		reported number of cleaned items must be zero"
+				if numCleanedItems == 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, numCleanedItems)
+				}
+
+				msg = "		clear must not have been invoked"
+				if cs.collections[payloadName].clearInvocations == 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, cs.collections[payloadName].clearInvocations)
+				}
+			}
+			t.Log("	when data structure does not hold any items")
+			{
+				cs := populateTestCollectionStore([]string{payloadName}, 0)
+				c, _ := setup.build(context.TODO(), cs, nil, nil, nil)
+
+				numCleanedItems, err := setup.retrieve(c, payloadName)
+
+				msg := "		no error must be returned"
+				if err == nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, err)
+				}
+
+				msg = "		reported number of cleaned items must be zero"
+				if numCleanedItems == 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, numCleanedItems)
+				}
+
+				msg = "		clear must not have been invoked"
+				if cs.collections[payloadName].clearInvocations == 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, cs.collections[payloadName].clearInvocations)
+				}
+			}
+			t.Log("	when clear fails")
+			{
+				cs := populateTestCollectionStore([]string{payloadName}, 3)
+				cs.collections[payloadName].returnErrorUponClear = true
+				c, _ := setup.build(context.TODO(), cs, nil, nil, nil)
+
+				numCleanedItems, err := setup.retrieve(c, payloadName)
+
+				msg := "		error must be returned"
+				if errors.Is(err, collectionClearError) {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, err)
+				}
+
+				msg = "		reported number of cleaned items must be zero"
+				if numCleanedItems == 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, numCleanedItems)
+				}
+			}
+			t.Log("	when data structure holds items and clear is successful")
+			{
+				numItems := 7
+				cs := populateTestCollectionStore([]string{payloadName}, numItems)
+				c, hzService := setup.build(context.TODO(), cs, nil, nil, nil)
+
+				numCleanedItems, err := setup.retrieve(c, payloadName)
+
+				msg := "		no error must be returned"
+				if err == nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, err)
+				}
+
+				msg = "		reported number of cleaned items must be equal to number of items previously held"
+				if numCleanedItems == numItems {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, numCleanedItems)
+				}
+
+				msg = "		data structure must have been cleared"
+				if cs.collections[payloadName].clearInvocations == 1 && cs.collections[payloadName].numItems == 0 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX)
+				}
+
+				msg = "		builder must report hazelcast service type corresponding to data structure"
+				if hzService == setup.hzService {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, hzService)
+				}
+			}
+		}
+	}
+
+}
+
+func TestDefaultSingleCollectionCleaners_Clean(t *testing.T) {
+
+	for _, setup := range collectionCleanerTestSetups() {
+		t.Logf("given a specific %s to clean in a target hazelcast cluster", setup.kind)
+		{
+			t.Log("	when all operations performed in scope of cleaning are successful")
+			{
+				ms := populateTestMapStore(0, []string{}, 0)
+				ms.maps[setup.syncMapName].tryLockReturnValue = true
+
+				payloadName := "ht_tweets-0"
+				numItems := 9
+				tr := &testCleanedTracker{}
+				cih := &DefaultLastCleanedInfoHandler{
+					Ms: ms,
+					Cfg: &LastCleanedInfoHandlerConfig{
+						UseCleanAgainThreshold: true,
+						CleanAgainThresholdMs:  30_000,
+					},
+				}
+				c, _ := setup.build(context.TODO(), populateTestCollectionStore([]string{payloadName}, numItems), ms, tr, cih)
+
+				numItemsCleaned, err := c.Clean(payloadName)
+
+				msg := "		no error must be returned"
+				if err == nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, err)
+				}
+
+				msg = "		reported number of cleaned items must be equal to number of items previously held"
+				if numItemsCleaned == numItems {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, numItemsCleaned)
+				}
+
+				msg = "		cleaned data structure must have been reported to tracker"
+				if tr.numAddInvocations == 1 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, tr.numAddInvocations)
+				}
+
+				msg = "		last cleaned info must have been recorded in sync map of data structure's cleaners"
+				if ms.maps[setup.syncMapName].setWithTTLAndMaxIdleInvocations == 1 {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, ms.maps[setup.syncMapName].setWithTTLAndMaxIdleInvocations)
+				}
+			}
+		}
+	}
+
+}
+
+func TestDefaultBatchCollectionCleanerBuilders_Build(t *testing.T) {
+
+	type batchBuilderTestSetup struct {
+		kind      string
+		keyPath   string
+		hzService string
+		b         BatchCleanerBuilder
+		cfb       *cleanerConfigBuilder
+	}
+
+	mmb, lb, sb := newMultiMapCleanerBuilder(), newListCleanerBuilder(), newSetCleanerBuilder()
+	setups := []batchBuilderTestSetup{
+		{"multimap", multiMapCleanerBasePath, HzMultiMapService, mmb, &mmb.cfb},
+		{"list", listCleanerBasePath, HzListService, lb, &lb.cfb},
+		{"set", setCleanerBasePath, HzSetService, sb, &sb.cfb},
+	}
+
+	for _, v := range setups {
+		t.Logf("given the properties necessary to assemble a batch %s cleaner", v.kind)
+		{
+			t.Log("	when populate config is successful")
+			{
+				v.cfb.a = &testConfigPropertyAssigner{testConfig: assembleTestConfig(v.keyPath)}
+
+				c, hzService, err := v.b.Build(&testHzClientHandler{}, context.TODO(), status.NewGatherer(), hzCluster, hzMembers)
+
+				msg := "		no error must be returned"
+				if err == nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, err)
+				}
+
+				msg = "		cleaner must be built"
+				if c != nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX)
+				}
+
+				msg = "		build method must report hazelcast service type corresponding to cleaner"
+				if hzService == v.hzService {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, hzService)
+				}
+			}
+			t.Log("	when populate config is unsuccessful")
+			{
+				v.cfb.a = &testConfigPropertyAssigner{returnErrorUponAssignConfigValue: true}
+
+				c, hzService, err := v.b.Build(&testHzClientHandler{}, context.TODO(), status.NewGatherer(), hzCluster, hzMembers)
+
+				msg := "		cleaner must be nil"
+				if c == nil {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX)
+				}
+
+				msg = "		builder must report type of hazelcast service for which builder was to be assembled"
+				if hzService == v.hzService {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, hzService)
+				}
+
+				msg = "		right kind of error must be returned"
+				if errors.Is(err, assignConfigPropertyError) {
+					t.Log(msg, checkMark)
+				} else {
+					t.Fatal(msg, ballotX, err)
+				}
+			}
+		}
+	}
+
+}
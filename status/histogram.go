@@ -0,0 +1,153 @@
+package status
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+type (
+	// Histogram records durations in microsecond resolution into log-linear buckets, similar to an HDR
+	// histogram: values below subBucketCount are tracked exactly, and every power of two above that
+	// is split into halfSubBucketCount equally-sized buckets, which keeps the relative error of any
+	// reported percentile below 1/halfSubBucketCount (~1.6 %) while requiring only a fixed amount of memory.
+	Histogram struct {
+		l      sync.Mutex
+		counts []uint64
+		total  uint64
+		sum    uint64
+		min    uint64
+		max    uint64
+	}
+	HistogramSnapshot struct {
+		Count      uint64 `json:"count"`
+		MinMicros  uint64 `json:"minMicros"`
+		MeanMicros uint64 `json:"meanMicros"`
+		SumMicros  uint64 `json:"sumMicros"`
+		P50Micros  uint64 `json:"p50Micros"`
+		P90Micros  uint64 `json:"p90Micros"`
+		P99Micros  uint64 `json:"p99Micros"`
+		P999Micros uint64 `json:"p999Micros"`
+		MaxMicros  uint64 `json:"maxMicros"`
+	}
+)
+
+const (
+	subBucketBits      = 7
+	subBucketCount     = 1 << subBucketBits
+	halfSubBucketCount = subBucketCount / 2
+	// Values beyond roughly 19 hours are clamped -- no operation of interest should ever take that long,
+	// and the cap keeps the number of buckets per histogram reasonably small.
+	highestTrackableValueBits = 36
+	highestTrackableValue     = 1<<highestTrackableValueBits - 1
+	numBuckets                = subBucketCount + (highestTrackableValueBits-subBucketBits)*halfSubBucketCount
+)
+
+func NewHistogram() *Histogram {
+
+	return &Histogram{
+		counts: make([]uint64, numBuckets),
+		min:    math.MaxUint64,
+	}
+
+}
+
+func (h *Histogram) Record(d time.Duration) {
+
+	v := uint64(0)
+	if d > 0 {
+		v = uint64(d.Microseconds())
+	}
+	if v > highestTrackableValue {
+		v = highestTrackableValue
+	}
+
+	h.l.Lock()
+	{
+		h.counts[bucketIndex(v)]++
+		h.total++
+		h.sum += v
+		if v < h.min {
+			h.min = v
+		}
+		if v > h.max {
+			h.max = v
+		}
+	}
+	h.l.Unlock()
+
+}
+
+func (h *Histogram) Snapshot() HistogramSnapshot {
+
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	if h.total == 0 {
+		return HistogramSnapshot{}
+	}
+
+	percentiles := []float64{50, 90, 99, 99.9}
+	values := make([]uint64, len(percentiles))
+
+	var cumulative uint64
+	p := 0
+	for i := 0; i < len(h.counts) && p < len(percentiles); i++ {
+		cumulative += h.counts[i]
+		for p < len(percentiles) && cumulative >= countAtPercentile(percentiles[p], h.total) {
+			values[p] = min(highestEquivalentValue(i), h.max)
+			p++
+		}
+	}
+
+	return HistogramSnapshot{
+		Count:      h.total,
+		MinMicros:  h.min,
+		MeanMicros: h.sum / h.total,
+		SumMicros:  h.sum,
+		P50Micros:  values[0],
+		P90Micros:  values[1],
+		P99Micros:  values[2],
+		P999Micros: values[3],
+		MaxMicros:  h.max,
+	}
+
+}
+
+func countAtPercentile(percentile float64, total uint64) uint64 {
+
+	c := uint64(math.Ceil(percentile / 100 * float64(total)))
+	if c == 0 {
+		return 1
+	}
+	return c
+
+}
+
+func bucketIndex(v uint64) int {
+
+	if v < subBucketCount {
+		return int(v)
+	}
+
+	shift := bits.Len64(v) - subBucketBits
+	subBucket := v >> shift
+
+	return subBucketCount + (shift-1)*halfSubBucketCount + int(subBucket-halfSubBucketCount)
+
+}
+
+func highestEquivalentValue(index int) uint64 {
+
+	if index < subBucketCount {
+		return uint64(index)
+	}
+
+	j := index - subBucketCount
+	shift := j/halfSubBucketCount + 1
+	subBucket := uint64(j%halfSubBucketCount + halfSubBucketCount)
+
+	return (subBucket+1)<<shift - 1
+
+}
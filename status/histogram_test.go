@@ -0,0 +1,172 @@
+package status
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHistogram_Record(t *testing.T) {
+
+	t.Log("given a histogram for recording durations")
+	{
+		t.Log("\twhen durations are recorded concurrently")
+		{
+			h := NewHistogram()
+
+			numGoroutines := 100
+			numRecordsPerGoroutine := 100
+
+			var wg sync.WaitGroup
+			for i := 0; i < numGoroutines; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < numRecordsPerGoroutine; j++ {
+						h.Record(time.Millisecond)
+					}
+				}()
+			}
+			wg.Wait()
+
+			msg := "\t\ttotal count must be equal to number of recorded durations"
+			expected := uint64(numGoroutines * numRecordsPerGoroutine)
+			if h.total == expected {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, fmt.Sprintf("expected %d, got %d", expected, h.total))
+			}
+		}
+
+		t.Log("\twhen negative duration is recorded")
+		{
+			h := NewHistogram()
+			h.Record(-5 * time.Millisecond)
+
+			msg := "\t\tduration must be recorded as zero"
+			if h.counts[0] == 1 && h.max == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen duration beyond highest trackable value is recorded")
+		{
+			h := NewHistogram()
+			h.Record(time.Duration(math.MaxInt64))
+
+			msg := "\t\tduration must be clamped to highest trackable value"
+			if h.max == highestTrackableValue && h.counts[numBuckets-1] == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, h.max)
+			}
+		}
+	}
+
+}
+
+func TestHistogram_Snapshot(t *testing.T) {
+
+	t.Log("given a histogram to take a snapshot of")
+	{
+		t.Log("\twhen histogram is empty")
+		{
+			s := NewHistogram().Snapshot()
+
+			msg := "\t\tsnapshot must be empty"
+			if s == (HistogramSnapshot{}) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s)
+			}
+		}
+
+		t.Log("\twhen histogram contains only small values")
+		{
+			h := NewHistogram()
+			for i := 1; i <= 100; i++ {
+				h.Record(time.Duration(i) * time.Microsecond)
+			}
+
+			s := h.Snapshot()
+
+			msg := "\t\tpercentiles must be exact"
+			if s.P50Micros == 50 && s.P90Micros == 90 && s.P99Micros == 99 && s.P999Micros == 100 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s)
+			}
+
+			msg = "\t\tcount, min, mean, sum, and max must be correct"
+			if s.Count == 100 && s.MinMicros == 1 && s.MeanMicros == 50 && s.SumMicros == 5050 && s.MaxMicros == 100 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s)
+			}
+		}
+
+		t.Log("\twhen histogram contains values across several orders of magnitude")
+		{
+			h := NewHistogram()
+			for i := 1; i <= 10_000; i++ {
+				h.Record(time.Duration(i*100) * time.Microsecond)
+			}
+
+			s := h.Snapshot()
+
+			msg := "\t\tpercentiles must be within relative error of expected values"
+			expected := map[string][]uint64{
+				"p50":  {s.P50Micros, 500_000},
+				"p90":  {s.P90Micros, 900_000},
+				"p99":  {s.P99Micros, 990_000},
+				"p999": {s.P999Micros, 999_000},
+			}
+			for k, v := range expected {
+				if relativeError(v[0], v[1]) <= 1.0/halfSubBucketCount {
+					t.Log(msg, checkMark, k)
+				} else {
+					t.Fatal(msg, ballotX, fmt.Sprintf("%s: expected approximately %d, got %d", k, v[1], v[0]))
+				}
+			}
+
+			msg = "\t\tmax must be exact"
+			if s.MaxMicros == 1_000_000 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.MaxMicros)
+			}
+		}
+	}
+
+}
+
+func TestBucketIndex(t *testing.T) {
+
+	t.Log("given a function to map values to bucket indices")
+	{
+		t.Log("\twhen values are mapped to bucket indices in ascending order")
+		{
+			msg := "\t\teach value must fall into a bucket whose highest equivalent value is greater than or equal to the value itself"
+			lastIndex := 0
+			for _, v := range []uint64{0, 1, 127, 128, 129, 255, 256, 1_000, 65_535, 1 << 20, highestTrackableValue} {
+				i := bucketIndex(v)
+				if i < lastIndex || i >= numBuckets || highestEquivalentValue(i) < v {
+					t.Fatal(msg, ballotX, v)
+				}
+				lastIndex = i
+			}
+			t.Log(msg, checkMark)
+		}
+	}
+
+}
+
+func relativeError(actual, expected uint64) float64 {
+
+	return math.Abs(float64(actual)-float64(expected)) / float64(expected)
+
+}
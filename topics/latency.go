@@ -0,0 +1,77 @@
+package topics
+
+import (
+	"hazeltest/status"
+	"sync"
+	"time"
+)
+
+type (
+	latencyTracker interface {
+		init(gatherer *status.Gatherer)
+		recordLatency(topicName string, d time.Duration)
+		publish()
+	}
+	// topicTestLoopLatencyTracker keeps one histogram per topic for the delivery latency of messages, i.e. the time
+	// between a message having been published and a subscriber having received it. Just like the time-in-queue
+	// latencies of the queue test loops, the histograms are published to the status gatherer at most once per
+	// latencyPublishInterval (and once more when the test loop has finished).
+	topicTestLoopLatencyTracker struct {
+		histograms    map[string]*status.Histogram
+		lastPublished time.Time
+		l             sync.Mutex
+		gatherer      *status.Gatherer
+	}
+)
+
+const (
+	statusKeyDeliveryLatencies statusKey = "deliveryLatencies"
+	latencyPublishInterval               = 1 * time.Second
+)
+
+func (lt *topicTestLoopLatencyTracker) init(gatherer *status.Gatherer) {
+	lt.gatherer = gatherer
+
+	lt.histograms = make(map[string]*status.Histogram)
+
+	lt.publish()
+}
+
+func (lt *topicTestLoopLatencyTracker) recordLatency(topicName string, d time.Duration) {
+
+	var publishDue bool
+	lt.l.Lock()
+	{
+		// Topic names are only known once the topic goroutines have started, so histograms are created lazily
+		h, ok := lt.histograms[topicName]
+		if !ok {
+			h = status.NewHistogram()
+			lt.histograms[topicName] = h
+		}
+		h.Record(d)
+		publishDue = time.Since(lt.lastPublished) >= latencyPublishInterval
+	}
+	lt.l.Unlock()
+
+	if publishDue {
+		lt.publish()
+	}
+
+}
+
+func (lt *topicTestLoopLatencyTracker) publish() {
+
+	var snapshots map[string]status.HistogramSnapshot
+	lt.l.Lock()
+	{
+		snapshots = make(map[string]status.HistogramSnapshot, len(lt.histograms))
+		for k, v := range lt.histograms {
+			snapshots[k] = v.Snapshot()
+		}
+		lt.lastPublished = time.Now()
+	}
+	lt.l.Unlock()
+
+	lt.gatherer.Updates <- status.Update{Key: string(statusKeyDeliveryLatencies), Value: snapshots}
+
+}
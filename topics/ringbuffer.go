@@ -0,0 +1,158 @@
+package topics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hazelcast/hazelcast-go-client"
+	"github.com/hazelcast/hazelcast-go-client/hzerrors"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/hazelcastwrapper"
+	"sync"
+	"time"
+)
+
+// ringbufferPublishTarget publishes messages by adding them to a ringbuffer. Once the ringbuffer is full, each message added overwrites the oldest one, so readers falling behind lose messages rather
+// than publishers getting blocked -- this is what readers report as overrun.
+type ringbufferPublishTarget struct {
+	rb hazelcastwrapper.Ringbuffer
+}
+
+func (r ringbufferPublishTarget) Publish(ctx context.Context, message any) error {
+
+	_, err := r.rb.Add(ctx, message, hazelcast.OverflowPolicyOverwrite)
+	return err
+
+}
+
+func (r ringbufferPublishTarget) PublishAll(ctx context.Context, messages ...any) error {
+
+	_, err := r.rb.AddAll(ctx, hazelcast.OverflowPolicyOverwrite, messages...)
+	return err
+
+}
+
+// runForRingbuffer runs the topic goroutine's test loop on a plain ringbuffer named like the topic, which is as
+// close to a reliable topic as the Hazelcast Go client version in use gets -- it does not offer reliable topics, and
+// the ringbuffer is not the one a reliable topic of the same name would be backed by, so this neither exercises nor
+// interoperates with reliable topics. Rather than having the cluster push messages to listeners, each subscriber
+// reads the ringbuffer's items by sequence, keeping track of the next sequence to read. The ringbuffer only retains
+// its most recent items, so a subscriber whose next sequence has fallen behind the ringbuffer's head sequence has
+// lost the items overwritten in the meantime.
+func (l *testLoop[t]) runForRingbuffer(topicName string, topicNumber int, p *publisher) {
+
+	rc := l.tle.runnerConfig
+
+	start := time.Now()
+	rb, err := l.tle.hzRingbufferStore.GetRingbuffer(l.tle.ctx, topicName)
+	if err != nil {
+		lp.LogHzEvent("unable to retrieve ringbuffer from hazelcast cluster", log.FatalLevel)
+	}
+	if rc.publishConfig.enabled && rc.subscribeConfig.enabled {
+		// Same as for plain topics -- other Hazeltest instances might still be reading the ringbuffer otherwise
+		defer func() {
+			_ = rb.Destroy(l.tle.ctx)
+		}()
+	}
+	elapsed := time.Since(start).Milliseconds()
+	lp.LogTimingEvent("getRingbuffer()", topicName, int(elapsed), log.InfoLevel)
+
+	var subscribers []*subscriber
+	if rc.subscribeConfig.enabled {
+		stop := make(chan struct{})
+		var readers sync.WaitGroup
+		subscribers = l.startReaders(rb, topicName, p, stop, &readers)
+		defer func() {
+			close(stop)
+			readers.Wait()
+		}()
+	}
+
+	l.publishAndVerify(ringbufferPublishTarget{rb: rb}, topicName, topicNumber, p, subscribers)
+
+}
+
+// startReaders starts one reader per configured subscriber on the given ringbuffer. Each reader begins reading after
+// the ringbuffer's current tail sequence, so, like message listeners added to a plain topic, readers only receive the
+// messages published after they have been started.
+func (l *testLoop[t]) startReaders(rb hazelcastwrapper.Ringbuffer, topicName string, p *publisher, stop <-chan struct{}, readers *sync.WaitGroup) []*subscriber {
+
+	var subscribers []*subscriber
+	for i := 0; i < l.tle.runnerConfig.subscribeConfig.numSubscribers; i++ {
+		tail, err := rb.TailSequence(l.tle.ctx)
+		if err != nil {
+			l.ct.increaseCounter(statusKeyNumFailedSubscriptions)
+			lp.LogTopicRunnerEvent(fmt.Sprintf("unable to determine tail sequence of ringbuffer '%s': %s", topicName, err), l.tle.runnerName, log.WarnLevel)
+			continue
+		}
+		s := newSubscriber(p)
+		subscribers = append(subscribers, s)
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			l.read(rb, topicName, s, tail+1, stop)
+		}()
+	}
+
+	return subscribers
+
+}
+
+// read makes the given subscriber read the given ringbuffer's items, beginning with the given sequence, until the
+// given channel gets closed. In each round, the subscriber first compares its next sequence to the ringbuffer's head
+// sequence -- if it has fallen behind, the items in between have been overwritten before it could read them, so it
+// reports them as overrun and continues at the head. It then reads all items up to the ringbuffer's tail sequence.
+func (l *testLoop[t]) read(rb hazelcastwrapper.Ringbuffer, topicName string, s *subscriber, next int64, stop <-chan struct{}) {
+
+	for {
+		head, tail, err := querySequences(l.tle.ctx, rb)
+		if err != nil {
+			l.ct.increaseCounter(statusKeyNumFailedReads)
+			lp.LogTopicRunnerEvent(fmt.Sprintf("unable to query sequences of ringbuffer '%s': %s", topicName, err), l.tle.runnerName, log.WarnLevel)
+		} else {
+			if next < head {
+				l.ct.increaseCounterBy(statusKeyNumOverrunMessages, int(head-next))
+				lp.LogTopicRunnerEvent(fmt.Sprintf("subscriber on ringbuffer '%s' fell behind: %d message/-s got overwritten before they could be read", topicName, head-next), l.tle.runnerName, log.WarnLevel)
+				s.resync()
+				next = head
+			}
+			for ; next <= tail; next++ {
+				item, err := rb.ReadOne(l.tle.ctx, next)
+				if err != nil {
+					// An item overwritten in the meantime gets reported as overrun in the next round
+					if !errors.Is(err, hzerrors.ErrStaleSequence) {
+						l.ct.increaseCounter(statusKeyNumFailedReads)
+						lp.LogTopicRunnerEvent(fmt.Sprintf("unable to read item with sequence %d from ringbuffer '%s': %s", next, topicName, err), l.tle.runnerName, log.WarnLevel)
+					}
+					break
+				}
+				l.receive(s, topicName, item)
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-l.tle.ctx.Done():
+			return
+		case <-time.After(deliveryCheckInterval):
+		}
+	}
+
+}
+
+func querySequences(ctx context.Context, rb hazelcastwrapper.Ringbuffer) (int64, int64, error) {
+
+	head, err := rb.HeadSequence(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tail, err := rb.TailSequence(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return head, tail, nil
+
+}
@@ -0,0 +1,382 @@
+package topics
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/logging"
+	"hazeltest/status"
+	"sync"
+	"time"
+)
+
+type (
+	TopicTester struct {
+		HzCluster string
+		HzMembers []string
+	}
+	runner interface {
+		getSourceName() string
+		runTopicTests(hzCluster string, hzMembers []string, gatherer *status.Gatherer, sf *storeFuncs)
+	}
+	// storeFuncs bundles the functions for initializing the stores the topic runners work with -- topics are
+	// retrieved from the topic store, and ringbuffers from the ringbuffer store.
+	storeFuncs struct {
+		topic      initTopicStoreFunc
+		ringbuffer initRingbufferStoreFunc
+	}
+	runnerConfig struct {
+		enabled                     bool
+		numTopics                   int
+		topicBaseName               string
+		appendTopicIndexToTopicName bool
+		appendClientIdToTopicName   bool
+		useTopicPrefix              bool
+		topicPrefix                 string
+		useRingbuffer               bool
+		publishConfig               *publishConfig
+		subscribeConfig             *subscribeConfig
+	}
+	publishConfig struct {
+		enabled                   bool
+		mode                      publishMode
+		numRuns                   uint32
+		batchSize                 int
+		initialDelay              *sleepConfig
+		sleepBetweenActionBatches *sleepConfig
+		sleepBetweenRuns          *sleepConfig
+	}
+	subscribeConfig struct {
+		enabled        bool
+		numSubscribers int
+		gracePeriod    time.Duration
+	}
+	sleepConfig struct {
+		enabled          bool
+		durationMs       int
+		enableRandomness bool
+	}
+	runnerConfigBuilder struct {
+		assigner      client.ConfigPropertyAssigner
+		runnerKeyPath string
+		topicBaseName string
+	}
+	publishMode             string
+	runnerState             string
+	statusKey               string
+	initTopicStoreFunc      func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.TopicStore
+	initRingbufferStoreFunc func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.RingbufferStore
+)
+
+const (
+	start                  runnerState = "start"
+	populateConfigComplete runnerState = "populateConfigComplete"
+	checkEnabledComplete   runnerState = "checkEnabledComplete"
+	raiseReadyComplete     runnerState = "raiseReadyComplete"
+	testLoopStart          runnerState = "testLoopStart"
+	testLoopComplete       runnerState = "testLoopComplete"
+)
+
+const (
+	statusKeyCurrentState statusKey = "currentState"
+)
+
+const (
+	publishSingleMode publishMode = "publish"
+	publishAllMode    publishMode = "publishAll"
+)
+
+var (
+	publishModes      = []publishMode{publishSingleMode, publishAllMode}
+	runners           []runner
+	lp                *logging.LogProvider
+	defaultStoreFuncs = &storeFuncs{
+		topic: func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.TopicStore {
+			return &hazelcastwrapper.DefaultTopicStore{Client: ch.GetClient()}
+		},
+		ringbuffer: func(ch hazelcastwrapper.HzClientHandler) hazelcastwrapper.RingbufferStore {
+			return &hazelcastwrapper.DefaultRingbufferStore{Client: ch.GetClient()}
+		},
+	}
+)
+
+func register(r runner) {
+	runners = append(runners, r)
+}
+
+func init() {
+	lp = logging.GetLogProviderInstance(client.ID())
+}
+
+func (b runnerConfigBuilder) populateConfig() (*runnerConfig, error) {
+
+	var assignmentOps []func() error
+
+	var enabled bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".enabled", client.ValidateBool, func(a any) {
+			enabled = a.(bool)
+		})
+	})
+
+	var numTopics int
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".numTopics", client.ValidateInt, func(a any) {
+			numTopics = a.(int)
+		})
+	})
+
+	var appendTopicIndexToTopicName bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".appendTopicIndexToTopicName", client.ValidateBool, func(a any) {
+			appendTopicIndexToTopicName = a.(bool)
+		})
+	})
+
+	var appendClientIdToTopicName bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".appendClientIdToTopicName", client.ValidateBool, func(a any) {
+			appendClientIdToTopicName = a.(bool)
+		})
+	})
+
+	var useTopicPrefix bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".topicPrefix.enabled", client.ValidateBool, func(a any) {
+			useTopicPrefix = a.(bool)
+		})
+	})
+
+	var topicPrefix string
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".topicPrefix.prefix", client.ValidateString, func(a any) {
+			topicPrefix = a.(string)
+		})
+	})
+
+	var useRingbuffer bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(b.runnerKeyPath+".ringbuffer.enabled", client.ValidateBool, func(a any) {
+			useRingbuffer = a.(bool)
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	pc, err := b.populatePublishConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := b.populateSubscribeConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &runnerConfig{
+		enabled:                     enabled,
+		numTopics:                   numTopics,
+		topicBaseName:               b.topicBaseName,
+		appendTopicIndexToTopicName: appendTopicIndexToTopicName,
+		appendClientIdToTopicName:   appendClientIdToTopicName,
+		useTopicPrefix:              useTopicPrefix,
+		topicPrefix:                 topicPrefix,
+		useRingbuffer:               useRingbuffer,
+		publishConfig:               pc,
+		subscribeConfig:             sc,
+	}, nil
+
+}
+
+func (b runnerConfigBuilder) populatePublishConfig() (*publishConfig, error) {
+
+	c := b.runnerKeyPath + ".publishConfig"
+
+	var assignmentOps []func() error
+
+	var enabled bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".enabled", client.ValidateBool, func(a any) {
+			enabled = a.(bool)
+		})
+	})
+
+	var mode publishMode
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".mode", validatePublishMode, func(a any) {
+			mode = publishMode(a.(string))
+		})
+	})
+
+	var numRuns uint32
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".numRuns", client.ValidateInt, func(a any) {
+			numRuns = uint32(a.(int))
+		})
+	})
+
+	var batchSize int
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".batchSize", client.ValidateInt, func(a any) {
+			batchSize = a.(int)
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	initialDelay, err := b.populateSleepConfig(c + ".sleeps.initialDelay")
+	if err != nil {
+		return nil, err
+	}
+
+	sleepBetweenActionBatches, err := b.populateSleepConfig(c + ".sleeps.betweenActionBatches")
+	if err != nil {
+		return nil, err
+	}
+
+	sleepBetweenRuns, err := b.populateSleepConfig(c + ".sleeps.betweenRuns")
+	if err != nil {
+		return nil, err
+	}
+
+	return &publishConfig{
+		enabled:                   enabled,
+		mode:                      mode,
+		numRuns:                   numRuns,
+		batchSize:                 batchSize,
+		initialDelay:              initialDelay,
+		sleepBetweenActionBatches: sleepBetweenActionBatches,
+		sleepBetweenRuns:          sleepBetweenRuns,
+	}, nil
+
+}
+
+func (b runnerConfigBuilder) populateSubscribeConfig() (*subscribeConfig, error) {
+
+	c := b.runnerKeyPath + ".subscribeConfig"
+
+	var assignmentOps []func() error
+
+	var enabled bool
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".enabled", client.ValidateBool, func(a any) {
+			enabled = a.(bool)
+		})
+	})
+
+	var numSubscribers int
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".numSubscribers", client.ValidateInt, func(a any) {
+			numSubscribers = a.(int)
+		})
+	})
+
+	var gracePeriod time.Duration
+	assignmentOps = append(assignmentOps, func() error {
+		return b.assigner.Assign(c+".gracePeriod", client.ValidateDuration, func(a any) {
+			gracePeriod, _ = time.ParseDuration(a.(string))
+		})
+	})
+
+	for _, f := range assignmentOps {
+		if err := f(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &subscribeConfig{
+		enabled:        enabled,
+		numSubscribers: numSubscribers,
+		gracePeriod:    gracePeriod,
+	}, nil
+
+}
+
+func validatePublishMode(keyPath string, a any) error {
+
+	if err := client.ValidateString(keyPath, a); err != nil {
+		return err
+	}
+
+	for _, m := range publishModes {
+		if publishMode(a.(string)) == m {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: expected one of %v, got '%v'", keyPath, publishModes, a)
+
+}
+
+func (b runnerConfigBuilder) populateSleepConfig(configBasePath string) (*sleepConfig, error) {
+
+	var enabled bool
+	if err := b.assigner.Assign(configBasePath+".enabled", client.ValidateBool, func(a any) {
+		enabled = a.(bool)
+	}); err != nil {
+		return nil, err
+	}
+
+	var durationMs int
+	if err := b.assigner.Assign(configBasePath+".durationMs", client.ValidateInt, func(a any) {
+		durationMs = a.(int)
+	}); err != nil {
+		return nil, err
+	}
+
+	var enableRandomness bool
+	if err := b.assigner.Assign(configBasePath+".enableRandomness", client.ValidateBool, func(a any) {
+		enableRandomness = a.(bool)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &sleepConfig{enabled, durationMs, enableRandomness}, nil
+
+}
+
+func populateConfig(assigner client.ConfigPropertyAssigner, runnerKeyPath string, topicBaseName string) (*runnerConfig, error) {
+
+	return runnerConfigBuilder{
+		assigner:      assigner,
+		runnerKeyPath: runnerKeyPath,
+		topicBaseName: topicBaseName,
+	}.populateConfig()
+
+}
+
+func (t *TopicTester) TestTopics() {
+
+	clientID := client.ID()
+	lp.LogInternalStateInfo(fmt.Sprintf("%s: topic tester starting %d runner/-s", clientID, len(runners)), log.InfoLevel)
+
+	var wg sync.WaitGroup
+	for i := 0; i < len(runners); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			gatherer := status.NewGatherer()
+			go gatherer.Listen()
+			defer gatherer.StopListen()
+
+			runner := runners[i]
+
+			api.RegisterStatefulActor(api.TopicRunners, runner.getSourceName(), gatherer.AssembleStatusCopy)
+			runner.runTopicTests(t.HzCluster, t.HzMembers, gatherer, defaultStoreFuncs)
+		}(i)
+	}
+
+	wg.Wait()
+
+}
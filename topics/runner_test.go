@@ -0,0 +1,188 @@
+package topics
+
+import (
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	testConfig = map[string]any{
+		runnerKeyPath + ".enabled":                                                    true,
+		runnerKeyPath + ".numTopics":                                                  5,
+		runnerKeyPath + ".appendTopicIndexToTopicName":                                true,
+		runnerKeyPath + ".appendClientIdToTopicName":                                  false,
+		runnerKeyPath + ".topicPrefix.enabled":                                        true,
+		runnerKeyPath + ".topicPrefix.prefix":                                         topicPrefix,
+		runnerKeyPath + ".ringbuffer.enabled":                                         true,
+		runnerKeyPath + ".publishConfig.enabled":                                      true,
+		runnerKeyPath + ".publishConfig.mode":                                         "publishAll",
+		runnerKeyPath + ".publishConfig.numRuns":                                      500,
+		runnerKeyPath + ".publishConfig.batchSize":                                    50,
+		runnerKeyPath + ".publishConfig.sleeps.initialDelay.enabled":                  true,
+		runnerKeyPath + ".publishConfig.sleeps.initialDelay.durationMs":               2000,
+		runnerKeyPath + ".publishConfig.sleeps.initialDelay.enableRandomness":         true,
+		runnerKeyPath + ".publishConfig.sleeps.betweenActionBatches.enabled":          true,
+		runnerKeyPath + ".publishConfig.sleeps.betweenActionBatches.durationMs":       1000,
+		runnerKeyPath + ".publishConfig.sleeps.betweenActionBatches.enableRandomness": true,
+		runnerKeyPath + ".publishConfig.sleeps.betweenRuns.enabled":                   true,
+		runnerKeyPath + ".publishConfig.sleeps.betweenRuns.durationMs":                2000,
+		runnerKeyPath + ".publishConfig.sleeps.betweenRuns.enableRandomness":          true,
+		runnerKeyPath + ".subscribeConfig.enabled":                                    true,
+		runnerKeyPath + ".subscribeConfig.numSubscribers":                             3,
+		runnerKeyPath + ".subscribeConfig.gracePeriod":                                "45s",
+	}
+	testStoreFuncs = &storeFuncs{
+		topic: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.TopicStore {
+			return newTestHzTopicStore(&testTopicStoreBehavior{})
+		},
+		ringbuffer: func(_ hazelcastwrapper.HzClientHandler) hazelcastwrapper.RingbufferStore {
+			return newTestHzRingbufferStore(&testRingbufferBehavior{})
+		},
+	}
+)
+
+func waitForStatusGatheringDone(g *status.Gatherer) {
+
+	for {
+		if done := g.ListeningStopped(); done {
+			return
+		}
+	}
+
+}
+
+func latestStatePresentInGatherer(g *status.Gatherer, desiredState runnerState) bool {
+
+	if value, ok := g.AssembleStatusCopy()[string(statusKeyCurrentState)]; ok && value == string(desiredState) {
+		return true
+	}
+
+	return false
+
+}
+
+func TestPopulateConfig(t *testing.T) {
+
+	t.Log("given a function for populating topic runner configs")
+	{
+		b := runnerConfigBuilder{runnerKeyPath: runnerKeyPath, topicBaseName: topicBaseName}
+		t.Log("\twhen property assignment does not generate an error")
+		{
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfig}
+			rc, err := b.populateConfig()
+
+			msg := "\t\tno error should be returned"
+			if err == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+
+			msg = "\t\tconfig should contain expected values"
+			if configValuesAsExpected(rc, testConfig) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Error(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen property assigning a property yields an error")
+		{
+			b.assigner = testConfigPropertyAssigner{returnError: true, testConfig: map[string]any{}}
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror should be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Error(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen publish mode is not supported")
+		{
+			testConfigCopy := copyTestConfig()
+			key := runnerKeyPath + ".publishConfig.mode"
+			testConfigCopy[key] = "broadcast"
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror containing path of erroneous key must be returned"
+			if err != nil && rc == nil && strings.Contains(err.Error(), key) {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, err)
+			}
+		}
+
+		t.Log("\twhen grace period cannot be parsed as duration")
+		{
+			testConfigCopy := copyTestConfig()
+			testConfigCopy[runnerKeyPath+".subscribeConfig.gracePeriod"] = "a while"
+			b.assigner = testConfigPropertyAssigner{returnError: false, testConfig: testConfigCopy}
+
+			rc, err := b.populateConfig()
+
+			msg := "\t\terror must be returned"
+			if err != nil && rc == nil {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+	}
+
+}
+
+func copyTestConfig() map[string]any {
+
+	testConfigCopy := make(map[string]any, len(testConfig))
+	for k, v := range testConfig {
+		testConfigCopy[k] = v
+	}
+
+	return testConfigCopy
+
+}
+
+func configValuesAsExpected(rc *runnerConfig, expected map[string]any) bool {
+
+	if rc == nil {
+		return false
+	}
+
+	pc := rc.publishConfig
+	sc := rc.subscribeConfig
+
+	gracePeriod, _ := time.ParseDuration(expected[runnerKeyPath+".subscribeConfig.gracePeriod"].(string))
+
+	return rc.enabled == expected[runnerKeyPath+".enabled"] &&
+		rc.numTopics == expected[runnerKeyPath+".numTopics"] &&
+		rc.topicBaseName == topicBaseName &&
+		rc.appendTopicIndexToTopicName == expected[runnerKeyPath+".appendTopicIndexToTopicName"] &&
+		rc.appendClientIdToTopicName == expected[runnerKeyPath+".appendClientIdToTopicName"] &&
+		rc.useTopicPrefix == expected[runnerKeyPath+".topicPrefix.enabled"] &&
+		rc.topicPrefix == expected[runnerKeyPath+".topicPrefix.prefix"] &&
+		rc.useRingbuffer == expected[runnerKeyPath+".ringbuffer.enabled"] &&
+		pc.enabled == expected[runnerKeyPath+".publishConfig.enabled"] &&
+		string(pc.mode) == expected[runnerKeyPath+".publishConfig.mode"] &&
+		pc.numRuns == uint32(expected[runnerKeyPath+".publishConfig.numRuns"].(int)) &&
+		pc.batchSize == expected[runnerKeyPath+".publishConfig.batchSize"] &&
+		pc.initialDelay.enabled == expected[runnerKeyPath+".publishConfig.sleeps.initialDelay.enabled"] &&
+		pc.initialDelay.durationMs == expected[runnerKeyPath+".publishConfig.sleeps.initialDelay.durationMs"] &&
+		pc.initialDelay.enableRandomness == expected[runnerKeyPath+".publishConfig.sleeps.initialDelay.enableRandomness"] &&
+		pc.sleepBetweenActionBatches.enabled == expected[runnerKeyPath+".publishConfig.sleeps.betweenActionBatches.enabled"] &&
+		pc.sleepBetweenActionBatches.durationMs == expected[runnerKeyPath+".publishConfig.sleeps.betweenActionBatches.durationMs"] &&
+		pc.sleepBetweenActionBatches.enableRandomness == expected[runnerKeyPath+".publishConfig.sleeps.betweenActionBatches.enableRandomness"] &&
+		pc.sleepBetweenRuns.enabled == expected[runnerKeyPath+".publishConfig.sleeps.betweenRuns.enabled"] &&
+		pc.sleepBetweenRuns.durationMs == expected[runnerKeyPath+".publishConfig.sleeps.betweenRuns.durationMs"] &&
+		pc.sleepBetweenRuns.enableRandomness == expected[runnerKeyPath+".publishConfig.sleeps.betweenRuns.enableRandomness"] &&
+		sc.enabled == expected[runnerKeyPath+".subscribeConfig.enabled"] &&
+		sc.numSubscribers == expected[runnerKeyPath+".subscribeConfig.numSubscribers"] &&
+		sc.gracePeriod == gracePeriod
+
+}
@@ -0,0 +1,663 @@
+package topics
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/hazelcast/hazelcast-go-client"
+	"github.com/hazelcast/hazelcast-go-client/types"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/status"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type (
+	evaluateTimeToSleep func(sc *sleepConfig) int
+	looper[t any]       interface {
+		init(tle *testLoopExecution[t], s sleeper, g *status.Gatherer)
+		run()
+	}
+	sleeper interface {
+		sleep(sc *sleepConfig, sf evaluateTimeToSleep, kind, topicName, runnerName string)
+	}
+	counterTracker interface {
+		init(gatherer *status.Gatherer)
+		increaseCounter(sk statusKey)
+		increaseCounterBy(sk statusKey, delta int)
+	}
+	testLoop[t any] struct {
+		tle      *testLoopExecution[t]
+		s        sleeper
+		gatherer *status.Gatherer
+		ct       counterTracker
+		lt       latencyTracker
+	}
+	testLoopExecution[t any] struct {
+		id                uuid.UUID
+		runnerName        string
+		source            string
+		hzTopicStore      hazelcastwrapper.TopicStore
+		hzRingbufferStore hazelcastwrapper.RingbufferStore
+		runnerConfig      *runnerConfig
+		elements          []t
+		ctx               context.Context
+	}
+	// topicMessage is what gets published to the target topic instead of the plain element. It identifies the topic
+	// goroutine that published it and carries a sequence number increasing with each publish attempt of that
+	// goroutine, so subscribers can tell missed and duplicate messages apart, as well as the time it was published,
+	// so subscribers can measure the delivery latency. It also carries the number of messages directly preceding it
+	// whose publish was reported as failed, so subscribers in other Hazeltest instances, too, can tell the sequence
+	// numbers skipped because of failed publishes apart from messages missed.
+	topicMessage struct {
+		PublisherID     string
+		Seq             uint64
+		NumFailedBefore uint64
+		PublishedAt     time.Time
+		Payload         any
+	}
+	// publishTarget is the data structure a topic goroutine publishes its messages to -- either a plain topic or a
+	// ringbuffer.
+	publishTarget interface {
+		Publish(ctx context.Context, message any) error
+		PublishAll(ctx context.Context, messages ...any) error
+	}
+	// publisher keeps track of the messages published by one topic goroutine. A publish reported as failed might
+	// have gone through nonetheless, for example, if the connection broke after the member had received the message,
+	// so the publisher assigns a sequence number of its own to each message, including the ones whose publish failed,
+	// and remembers the latter. This way, such a message being delivered does not make a later message with the same
+	// sequence number look like a duplicate, and it can be reported as tolerated instead.
+	publisher struct {
+		id               string
+		seq              uint64
+		lastPublishedSeq uint64
+		numFailedInARow  uint64
+		failedSeqs       map[uint64]struct{}
+		l                sync.Mutex
+	}
+	// subscriber keeps track of the messages received by one message listener or ringbuffer reader. Both plain topics
+	// and ringbuffers deliver the messages of one publisher in the order they were published, so, per publisher, a
+	// sequence number skipping others means messages were missed, and a sequence number seen before means a message
+	// was delivered twice.
+	subscriber struct {
+		own              *publisher
+		subscriptionID   types.UUID
+		lastSeqs         map[string]uint64
+		numSkippedFailed uint64
+		resyncs          map[string]struct{}
+		l                sync.Mutex
+	}
+	defaultSleeper               struct{}
+	topicTestLoopCountersTracker struct {
+		counters map[statusKey]int
+		l        sync.Mutex
+		gatherer *status.Gatherer
+	}
+)
+
+const (
+	statusKeyOperationEnabled = "enabled"
+	statusKeyPublishMode      = "mode"
+	statusKeyNumTopics        = "numTopics"
+	statusKeyNumRuns          = "numRuns"
+	statusKeyBatchSize        = "batchSize"
+	statusKeyTotalNumRuns     = "totalNumRuns"
+	statusKeyNumSubscribers   = "numSubscribers"
+	statusKeyPublish          = "publish"
+	statusKeySubscribe        = "subscribe"
+)
+
+const (
+	statusKeyNumPublishedMessages   statusKey = "numPublishedMessages"
+	statusKeyNumFailedPublishes     statusKey = "numFailedPublishes"
+	statusKeyNumReceivedMessages    statusKey = "numReceivedMessages"
+	statusKeyNumMissedMessages      statusKey = "numMissedMessages"
+	statusKeyNumDuplicateMessages   statusKey = "numDuplicateMessages"
+	statusKeyNumUnexpectedMessages  statusKey = "numUnexpectedMessages"
+	statusKeyNumFailedSubscriptions statusKey = "numFailedSubscriptions"
+	statusKeyNumToleratedMessages   statusKey = "numToleratedMessages"
+	statusKeyNumOverrunMessages     statusKey = "numOverrunMessages"
+	statusKeyNumFailedReads         statusKey = "numFailedReads"
+)
+
+const (
+	topicOperationLoggingUpdateStep = 10
+	deliveryCheckInterval           = 100 * time.Millisecond
+)
+
+var (
+	sleepTimeFunc evaluateTimeToSleep = func(sc *sleepConfig) int {
+		var sleepDuration int
+		if sc.enableRandomness {
+			sleepDuration = rand.Intn(sc.durationMs + 1)
+		} else {
+			sleepDuration = sc.durationMs
+		}
+		return sleepDuration
+	}
+	counters = []statusKey{statusKeyNumPublishedMessages, statusKeyNumFailedPublishes, statusKeyNumReceivedMessages, statusKeyNumMissedMessages, statusKeyNumDuplicateMessages, statusKeyNumUnexpectedMessages, statusKeyNumFailedSubscriptions, statusKeyNumToleratedMessages, statusKeyNumOverrunMessages, statusKeyNumFailedReads}
+)
+
+func init() {
+	gob.Register(topicMessage{})
+}
+
+func (ct *topicTestLoopCountersTracker) init(gatherer *status.Gatherer) {
+	ct.gatherer = gatherer
+
+	ct.counters = make(map[statusKey]int)
+
+	initialCounterValue := 0
+	for _, v := range counters {
+		ct.counters[v] = initialCounterValue
+		gatherer.Updates <- status.Update{Key: string(v), Value: initialCounterValue}
+	}
+
+}
+
+func (ct *topicTestLoopCountersTracker) increaseCounter(sk statusKey) {
+
+	ct.increaseCounterBy(sk, 1)
+
+}
+
+func (ct *topicTestLoopCountersTracker) increaseCounterBy(sk statusKey, delta int) {
+
+	var newValue int
+	ct.l.Lock()
+	{
+		newValue = ct.counters[sk] + delta
+		ct.counters[sk] = newValue
+	}
+	ct.l.Unlock()
+
+	ct.gatherer.Updates <- status.Update{Key: string(sk), Value: newValue}
+
+}
+
+func (l *testLoop[t]) init(tle *testLoopExecution[t], s sleeper, g *status.Gatherer) {
+	l.tle = tle
+	l.s = s
+	l.gatherer = g
+
+	ct := &topicTestLoopCountersTracker{}
+	ct.init(g)
+	l.ct = ct
+
+	lt := &topicTestLoopLatencyTracker{}
+	lt.init(g)
+	l.lt = lt
+}
+
+func (l *testLoop[t]) run() {
+
+	l.insertLoopWithInitialStatus()
+
+	var numTopicsWg sync.WaitGroup
+	for i := 0; i < l.tle.runnerConfig.numTopics; i++ {
+		numTopicsWg.Add(1)
+		go func(i int) {
+			defer numTopicsWg.Done()
+			l.runForTopic(i)
+		}(i)
+	}
+
+	numTopicsWg.Wait()
+
+	l.lt.publish()
+
+}
+
+func (l *testLoop[t]) runForTopic(topicNumber int) {
+
+	topicName := l.assembleTopicName(topicNumber)
+	lp.LogTopicRunnerEvent(fmt.Sprintf("using topic name '%s' in topic goroutine %d", topicName, topicNumber), l.tle.runnerName, log.InfoLevel)
+
+	p := newPublisher(l.assemblePublisherID(topicNumber))
+	if l.tle.runnerConfig.useRingbuffer {
+		l.runForRingbuffer(topicName, topicNumber, p)
+	} else {
+		l.runForPlainTopic(topicName, topicNumber, p)
+	}
+
+	lp.LogTopicRunnerEvent(fmt.Sprintf("topic test loop done on topic '%s' in topic goroutine %d", topicName, topicNumber), l.tle.runnerName, log.InfoLevel)
+
+}
+
+func (l *testLoop[t]) runForPlainTopic(topicName string, topicNumber int, p *publisher) {
+
+	rc := l.tle.runnerConfig
+
+	start := time.Now()
+	topic, err := l.tle.hzTopicStore.GetTopic(l.tle.ctx, topicName)
+	if err != nil {
+		lp.LogHzEvent("unable to retrieve topic from hazelcast cluster", log.FatalLevel)
+	}
+	if rc.publishConfig.enabled && rc.subscribeConfig.enabled {
+		// If only one of both is enabled, the topic is presumably shared with other Hazeltest instances
+		// taking care of the other, so destroying it would remove their listeners
+		defer func() {
+			_ = topic.Destroy(l.tle.ctx)
+		}()
+	}
+	elapsed := time.Since(start).Milliseconds()
+	lp.LogTimingEvent("getTopic()", topicName, int(elapsed), log.InfoLevel)
+
+	var subscribers []*subscriber
+	if rc.subscribeConfig.enabled {
+		// Subscribers must be in place before the first message gets published, otherwise the topic goroutine's
+		// own messages published in the meantime would be reported as missed
+		subscribers = l.subscribe(topic, topicName, p)
+		defer l.unsubscribe(topic, topicName, subscribers)
+	}
+
+	l.publishAndVerify(topic, topicName, topicNumber, p, subscribers)
+
+}
+
+// publishAndVerify publishes the test loop's elements to the given target, provided publishing is enabled, and then
+// waits for the given subscribers to have received them, reporting the messages of the given publisher each
+// subscriber has not received by the end of the grace period as missed.
+func (l *testLoop[t]) publishAndVerify(target publishTarget, topicName string, topicNumber int, p *publisher, subscribers []*subscriber) {
+
+	if l.tle.runnerConfig.publishConfig.enabled {
+		l.publishMessages(target, topicName, topicNumber, p)
+	}
+
+	if len(subscribers) > 0 {
+		l.awaitDelivery(subscribers, p)
+		for _, s := range subscribers {
+			lastSeq := s.lastSeq(p.id)
+			if numMissed := p.numMissedAfter(lastSeq); numMissed > 0 {
+				l.ct.increaseCounterBy(statusKeyNumMissedMessages, int(numMissed))
+				lp.LogTopicRunnerEvent(fmt.Sprintf("subscriber on topic '%s' did not receive last %d message/-s within grace period", topicName, numMissed), l.tle.runnerName, log.WarnLevel)
+			}
+			// Messages arrive in order, so of the messages up to the last one received whose publish failed, the
+			// subscriber received all but the ones it skipped
+			if numTolerated := p.numFailedBetween(1, lastSeq) - s.numSkippedFailedMessages(); numTolerated > 0 {
+				l.ct.increaseCounterBy(statusKeyNumToleratedMessages, int(numTolerated))
+				lp.LogTopicRunnerEvent(fmt.Sprintf("subscriber on topic '%s' received %d message/-s even though their publish was reported as failed", topicName, numTolerated), l.tle.runnerName, log.InfoLevel)
+			}
+		}
+	}
+
+}
+
+func (l *testLoop[t]) subscribe(topic hazelcastwrapper.Topic, topicName string, p *publisher) []*subscriber {
+
+	var subscribers []*subscriber
+	for i := 0; i < l.tle.runnerConfig.subscribeConfig.numSubscribers; i++ {
+		s := newSubscriber(p)
+		subscriptionID, err := topic.AddMessageListener(l.tle.ctx, func(event *hazelcast.MessagePublished) {
+			l.onMessage(s, topicName, event)
+		})
+		if err != nil {
+			l.ct.increaseCounter(statusKeyNumFailedSubscriptions)
+			lp.LogTopicRunnerEvent(fmt.Sprintf("unable to add message listener to topic '%s': %s", topicName, err), l.tle.runnerName, log.WarnLevel)
+			continue
+		}
+		s.subscriptionID = subscriptionID
+		subscribers = append(subscribers, s)
+	}
+
+	return subscribers
+
+}
+
+func (l *testLoop[t]) unsubscribe(topic hazelcastwrapper.Topic, topicName string, subscribers []*subscriber) {
+
+	for _, s := range subscribers {
+		if err := topic.RemoveListener(l.tle.ctx, s.subscriptionID); err != nil {
+			lp.LogTopicRunnerEvent(fmt.Sprintf("unable to remove message listener from topic '%s': %s", topicName, err), l.tle.runnerName, log.WarnLevel)
+		}
+	}
+
+}
+
+func (l *testLoop[t]) onMessage(s *subscriber, topicName string, event *hazelcast.MessagePublished) {
+
+	l.receive(s, topicName, event.Value)
+
+}
+
+// receive evaluates the given value received by the given subscriber, regardless of whether it was delivered to
+// a message listener on a plain topic or read from a ringbuffer.
+func (l *testLoop[t]) receive(s *subscriber, topicName string, value any) {
+
+	l.ct.increaseCounter(statusKeyNumReceivedMessages)
+
+	m, ok := value.(topicMessage)
+	if !ok {
+		l.ct.increaseCounter(statusKeyNumUnexpectedMessages)
+		lp.LogTopicRunnerEvent(fmt.Sprintf("received message of unexpected type %T on topic '%s'", value, topicName), l.tle.runnerName, log.WarnLevel)
+		return
+	}
+
+	l.lt.recordLatency(topicName, time.Since(m.PublishedAt))
+
+	numMissed, duplicate := s.record(m)
+	if duplicate {
+		l.ct.increaseCounter(statusKeyNumDuplicateMessages)
+		lp.LogTopicRunnerEvent(fmt.Sprintf("received message with sequence number %d of publisher '%s' on topic '%s' more than once", m.Seq, m.PublisherID, topicName), l.tle.runnerName, log.WarnLevel)
+	} else if numMissed > 0 {
+		l.ct.increaseCounterBy(statusKeyNumMissedMessages, int(numMissed))
+		lp.LogTopicRunnerEvent(fmt.Sprintf("missed %d message/-s of publisher '%s' on topic '%s' before sequence number %d", numMissed, m.PublisherID, topicName, m.Seq), l.tle.runnerName, log.WarnLevel)
+	}
+
+}
+
+// awaitDelivery waits for the given subscribers to have received the messages published by the given publisher, but
+// for no longer than the configured grace period. If publishing is disabled, this is how long the subscribers listen
+// for messages published by others.
+func (l *testLoop[t]) awaitDelivery(subscribers []*subscriber, p *publisher) {
+
+	deadline := time.Now().Add(l.tle.runnerConfig.subscribeConfig.gracePeriod)
+
+	for {
+		if l.tle.runnerConfig.publishConfig.enabled && allDelivered(subscribers, p) {
+			return
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return
+		}
+		select {
+		case <-l.tle.ctx.Done():
+			return
+		case <-time.After(min(deliveryCheckInterval, remaining)):
+		}
+	}
+
+}
+
+func allDelivered(subscribers []*subscriber, p *publisher) bool {
+
+	for _, s := range subscribers {
+		if s.lastSeq(p.id) < p.lastPublishedSeq {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+func newPublisher(id string) *publisher {
+
+	return &publisher{id: id, failedSeqs: make(map[uint64]struct{})}
+
+}
+
+// next assembles the message carrying the given payload and the publisher's next sequence number.
+func (p *publisher) next(payload any, publishedAt time.Time) topicMessage {
+
+	p.seq++
+	m := topicMessage{PublisherID: p.id, Seq: p.seq, NumFailedBefore: p.numFailedInARow, PublishedAt: publishedAt, Payload: payload}
+	p.numFailedInARow = 0
+
+	return m
+
+}
+
+func (p *publisher) published(m topicMessage) {
+
+	p.lastPublishedSeq = m.Seq
+
+}
+
+// failed remembers the given message's publish as having failed. In the 'publishAll' mode, all messages of a batch
+// fail together, and only the batch's first message carries the number of failed messages preceding it, so adding
+// that number to the running count yields the number of failed messages preceding the next message.
+func (p *publisher) failed(m topicMessage) {
+
+	p.l.Lock()
+	p.failedSeqs[m.Seq] = struct{}{}
+	p.l.Unlock()
+
+	p.numFailedInARow += m.NumFailedBefore + 1
+
+}
+
+// numFailedBetween returns the number of messages within the given range of sequence numbers, both inclusive,
+// whose publish was reported as failed.
+func (p *publisher) numFailedBetween(from, to uint64) uint64 {
+
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	var numFailed uint64
+	for seq := range p.failedSeqs {
+		if seq >= from && seq <= to {
+			numFailed++
+		}
+	}
+
+	return numFailed
+
+}
+
+// numMissedAfter returns the number of messages successfully published after the given sequence number, that is,
+// the number of messages a subscriber whose last received sequence number is the given one has missed.
+func (p *publisher) numMissedAfter(lastReceivedSeq uint64) uint64 {
+
+	if lastReceivedSeq >= p.lastPublishedSeq {
+		return 0
+	}
+
+	return p.lastPublishedSeq - lastReceivedSeq - p.numFailedBetween(lastReceivedSeq+1, p.lastPublishedSeq)
+
+}
+
+func newSubscriber(own *publisher) *subscriber {
+
+	return &subscriber{own: own, lastSeqs: make(map[string]uint64), resyncs: make(map[string]struct{})}
+
+}
+
+// record evaluates the given message against the messages previously received from the same publisher and returns
+// the number of messages missed in between as well as whether the message is a duplicate. Skipped sequence numbers
+// whose publish was reported as failed are not counted as missed -- those messages were presumably never delivered.
+func (s *subscriber) record(m topicMessage) (uint64, bool) {
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	last, seen := s.lastSeqs[m.PublisherID]
+	if _, resync := s.resyncs[m.PublisherID]; resync || (!seen && m.PublisherID != s.own.id) {
+		// Other publishers might have been publishing before this subscriber was in place, so their
+		// earlier messages were never supposed to be received
+		delete(s.resyncs, m.PublisherID)
+		if m.PublisherID == s.own.id && m.Seq > last+1 {
+			s.numSkippedFailed += s.own.numFailedBetween(last+1, m.Seq-1)
+		}
+		s.lastSeqs[m.PublisherID] = max(last, m.Seq)
+		return 0, false
+	}
+
+	if m.Seq <= last {
+		return 0, true
+	}
+
+	s.lastSeqs[m.PublisherID] = m.Seq
+	numSkipped := m.Seq - last - 1
+	// The messages whose publish failed directly precede the given one, so those skipped are the most recent ones
+	numSkippedFailed := min(numSkipped, m.NumFailedBefore)
+	if m.PublisherID == s.own.id {
+		s.numSkippedFailed += numSkippedFailed
+	}
+
+	return numSkipped - numSkippedFailed, false
+
+}
+
+// resync makes the subscriber take the next message of each publisher as the new baseline, as it does for the first
+// message of other publishers, because the messages in between have been reported as overrun already.
+func (s *subscriber) resync() {
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	for publisherID := range s.lastSeqs {
+		s.resyncs[publisherID] = struct{}{}
+	}
+	s.resyncs[s.own.id] = struct{}{}
+
+}
+
+func (s *subscriber) numSkippedFailedMessages() uint64 {
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	return s.numSkippedFailed
+
+}
+
+func (s *subscriber) lastSeq(publisherID string) uint64 {
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	return s.lastSeqs[publisherID]
+
+}
+
+// publishMessages publishes the test loop's elements to the given target for the configured number of runs.
+func (l *testLoop[t]) publishMessages(target publishTarget, topicName string, topicNumber int, p *publisher) {
+
+	pc := l.tle.runnerConfig.publishConfig
+
+	l.s.sleep(pc.initialDelay, sleepTimeFunc, "initialDelay", topicName, l.tle.runnerName)
+
+	for i := uint32(0); i < pc.numRuns; i++ {
+		if i > 0 && i%topicOperationLoggingUpdateStep == 0 {
+			lp.LogTopicRunnerEvent(fmt.Sprintf("finished %d of %d publish runs for topic %s in topic goroutine %d", i, pc.numRuns, topicName, topicNumber), l.tle.runnerName, log.InfoLevel)
+		}
+		if pc.mode == publishAllMode {
+			l.publishAllElements(target, topicName, p)
+		} else {
+			l.publishElements(target, topicName, p)
+		}
+		l.s.sleep(pc.sleepBetweenRuns, sleepTimeFunc, "betweenRuns", topicName, l.tle.runnerName)
+	}
+
+	lp.LogTopicRunnerEvent(fmt.Sprintf("publishing done on topic '%s' in topic goroutine %d", topicName, topicNumber), l.tle.runnerName, log.InfoLevel)
+
+}
+
+func (l *testLoop[t]) publishElements(target publishTarget, topicName string, p *publisher) {
+
+	pc := l.tle.runnerConfig.publishConfig
+
+	for i, e := range l.tle.elements {
+		m := p.next(e, time.Now())
+		if err := target.Publish(l.tle.ctx, m); err != nil {
+			p.failed(m)
+			l.ct.increaseCounter(statusKeyNumFailedPublishes)
+			lp.LogTopicRunnerEvent(fmt.Sprintf("unable to publish message to topic '%s': %s", topicName, err), l.tle.runnerName, log.WarnLevel)
+		} else {
+			p.published(m)
+			l.ct.increaseCounter(statusKeyNumPublishedMessages)
+			lp.LogTopicRunnerEvent(fmt.Sprintf("successfully published message to topic '%s'", topicName), l.tle.runnerName, log.TraceLevel)
+		}
+		if i > 0 && i%pc.batchSize == 0 {
+			l.s.sleep(pc.sleepBetweenActionBatches, sleepTimeFunc, "betweenActionBatches", topicName, l.tle.runnerName)
+		}
+	}
+
+}
+
+// publishAllElements publishes the test loop's elements in bulk, using one operation per batch of elements, so the
+// batch size determines the number of messages published at once rather than only the number of messages after which
+// to sleep.
+func (l *testLoop[t]) publishAllElements(target publishTarget, topicName string, p *publisher) {
+
+	pc := l.tle.runnerConfig.publishConfig
+	elements := l.tle.elements
+
+	for start := 0; start < len(elements); start += pc.batchSize {
+		end := min(start+pc.batchSize, len(elements))
+		messages := make([]topicMessage, 0, end-start)
+		batch := make([]any, 0, end-start)
+		publishedAt := time.Now()
+		for _, e := range elements[start:end] {
+			m := p.next(e, publishedAt)
+			messages = append(messages, m)
+			batch = append(batch, m)
+		}
+		if err := target.PublishAll(l.tle.ctx, batch...); err != nil {
+			for _, m := range messages {
+				p.failed(m)
+			}
+			l.ct.increaseCounterBy(statusKeyNumFailedPublishes, len(batch))
+			lp.LogTopicRunnerEvent(fmt.Sprintf("unable to publish batch of %d messages to topic '%s': %s", len(batch), topicName, err), l.tle.runnerName, log.WarnLevel)
+		} else {
+			p.published(messages[len(messages)-1])
+			l.ct.increaseCounterBy(statusKeyNumPublishedMessages, len(batch))
+			lp.LogTopicRunnerEvent(fmt.Sprintf("successfully published batch of %d messages to topic '%s'", len(batch), topicName), l.tle.runnerName, log.TraceLevel)
+		}
+		l.s.sleep(pc.sleepBetweenActionBatches, sleepTimeFunc, "betweenActionBatches", topicName, l.tle.runnerName)
+	}
+
+}
+
+func (l *testLoop[t]) insertLoopWithInitialStatus() {
+
+	rc := l.tle.runnerConfig
+
+	l.gatherer.Updates <- status.Update{Key: statusKeyNumTopics, Value: rc.numTopics}
+	l.gatherer.Updates <- status.Update{Key: statusKeyPublish, Value: map[string]any{
+		statusKeyOperationEnabled: rc.publishConfig.enabled,
+		statusKeyPublishMode:      string(rc.publishConfig.mode),
+		statusKeyNumRuns:          rc.publishConfig.numRuns,
+		statusKeyBatchSize:        rc.publishConfig.batchSize,
+		statusKeyTotalNumRuns:     uint32(rc.numTopics) * rc.publishConfig.numRuns,
+	}}
+	l.gatherer.Updates <- status.Update{Key: statusKeySubscribe, Value: map[string]any{
+		statusKeyOperationEnabled: rc.subscribeConfig.enabled,
+		statusKeyNumSubscribers:   rc.subscribeConfig.numSubscribers,
+	}}
+
+}
+
+// assemblePublisherID identifies the publishing side of the given topic goroutine across all Hazeltest instances, so
+// sequence numbers assigned by different topic goroutines publishing to the same topic can be told apart.
+func (l *testLoop[t]) assemblePublisherID(topicNumber int) string {
+
+	return fmt.Sprintf("%s-%s-%d", client.ID(), l.tle.runnerName, topicNumber)
+
+}
+
+func (l *testLoop[t]) assembleTopicName(topicIndex int) string {
+
+	rc := l.tle.runnerConfig
+
+	topicName := rc.topicBaseName
+
+	if rc.useTopicPrefix && rc.topicPrefix != "" {
+		topicName = fmt.Sprintf("%s%s", rc.topicPrefix, topicName)
+	}
+	if rc.appendTopicIndexToTopicName {
+		topicName = fmt.Sprintf("%s-%d", topicName, topicIndex)
+	}
+	if rc.appendClientIdToTopicName {
+		topicName = fmt.Sprintf("%s-%s", topicName, client.ID())
+	}
+
+	return topicName
+
+}
+
+func (s *defaultSleeper) sleep(sc *sleepConfig, sf evaluateTimeToSleep, kind, topicName, runnerName string) {
+
+	if sc.enabled {
+		sleepDuration := sf(sc)
+		lp.LogTopicRunnerEvent(fmt.Sprintf("sleeping for %d milliseconds for kind '%s' on topic '%s'", sleepDuration, kind, topicName), runnerName, log.TraceLevel)
+		time.Sleep(time.Duration(sleepDuration) * time.Millisecond)
+	}
+
+}
@@ -0,0 +1,798 @@
+package topics
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/hazelcast/hazelcast-go-client"
+	"hazeltest/status"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testNumTopics      = 3
+	testNumRuns        = 4
+	testNumElements    = 10
+	testBatchSize      = 3
+	testNumSubscribers = 2
+)
+
+func TestRunTestLoop(t *testing.T) {
+
+	t.Log("given a topic test loop")
+	{
+		t.Log("\twhen both publishing and subscribing are enabled and all messages get delivered")
+		{
+			for _, mode := range publishModes {
+				ts := newTestHzTopicStore(&testTopicStoreBehavior{})
+				rc := assembleTestLoopRunnerConfig(mode, true, true)
+				l, s, g := assembleTestLoop(ts, rc)
+
+				l.run()
+				ct := finishTestLoop(l, g)
+
+				numExpectedPublished := testNumTopics * testNumRuns * testNumElements
+				msg := "\t\tall messages must have been published"
+				if ct.counters[statusKeyNumPublishedMessages] == numExpectedPublished {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, ct.counters[statusKeyNumPublishedMessages])
+				}
+
+				msg = "\t\teach subscriber must have received each message of its own topic"
+				if ct.counters[statusKeyNumReceivedMessages] == numExpectedPublished*testNumSubscribers {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, ct.counters[statusKeyNumReceivedMessages])
+				}
+
+				msg = "\t\tno message must have been reported as failed, missed, duplicate, or unexpected"
+				if ct.counters[statusKeyNumFailedPublishes] == 0 && ct.counters[statusKeyNumMissedMessages] == 0 &&
+					ct.counters[statusKeyNumDuplicateMessages] == 0 && ct.counters[statusKeyNumUnexpectedMessages] == 0 {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, ct.counters)
+				}
+
+				msg = "\t\tpublish operation corresponding to configured mode must have been used"
+				numPublishInvocations := ts.sumInvocations(func(t *testHzTopic) int { return t.publishInvocations })
+				numPublishAllInvocations := ts.sumInvocations(func(t *testHzTopic) int { return t.publishAllInvocations })
+				numBatches := (testNumElements + testBatchSize - 1) / testBatchSize
+				if (mode == publishSingleMode && numPublishInvocations == numExpectedPublished && numPublishAllInvocations == 0) ||
+					(mode == publishAllMode && numPublishAllInvocations == testNumTopics*testNumRuns*numBatches && numPublishInvocations == 0) {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, numPublishInvocations, numPublishAllInvocations)
+				}
+
+				msg = "\t\tall listeners must have been removed"
+				if ts.sumInvocations(func(t *testHzTopic) int { return t.removeListenerInvocations }) == testNumTopics*testNumSubscribers {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode)
+				}
+
+				msg = "\t\tall topics must have been destroyed"
+				if len(ts.topics) == testNumTopics && ts.sumInvocations(func(t *testHzTopic) int { return t.destroyInvocations }) == testNumTopics {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode)
+				}
+
+				msg = "\t\tbetween-runs sleep must have been invoked once per run"
+				if s.numSleeps("betweenRuns") == testNumTopics*testNumRuns {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, s.numSleeps("betweenRuns"))
+				}
+
+				msg = "\t\tdelivery latencies must have been reported for each topic"
+				latencies, ok := g.AssembleStatusCopy()[string(statusKeyDeliveryLatencies)].(map[string]status.HistogramSnapshot)
+				if ok && len(latencies) == testNumTopics && latencies[l.assembleTopicName(0)].Count == uint64(testNumRuns*testNumElements*testNumSubscribers) {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, latencies)
+				}
+			}
+		}
+
+		t.Log("\twhen publish operations fail")
+		{
+			for _, mode := range publishModes {
+				ts := newTestHzTopicStore(&testTopicStoreBehavior{returnErrorUponPublish: true})
+				rc := assembleTestLoopRunnerConfig(mode, true, true)
+				l, _, g := assembleTestLoop(ts, rc)
+
+				l.run()
+				ct := finishTestLoop(l, g)
+
+				msg := "\t\tall messages must have been reported as failed publishes"
+				if ct.counters[statusKeyNumFailedPublishes] == testNumTopics*testNumRuns*testNumElements && ct.counters[statusKeyNumPublishedMessages] == 0 {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, ct.counters)
+				}
+
+				msg = "\t\tmessages never published must not be reported as missed"
+				if ct.counters[statusKeyNumMissedMessages] == 0 {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, ct.counters[statusKeyNumMissedMessages])
+				}
+			}
+		}
+
+		t.Log("\twhen messages do not get delivered")
+		{
+			ts := newTestHzTopicStore(&testTopicStoreBehavior{dropEveryNth: 5})
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, true, true)
+			rc.subscribeConfig.gracePeriod = 10 * time.Millisecond
+			l, _, g := assembleTestLoop(ts, rc)
+
+			l.run()
+			ct := finishTestLoop(l, g)
+
+			numDroppedPerTopic := testNumRuns * testNumElements / 5
+			msg := "\t\teach subscriber must have reported each message not delivered as missed"
+			if ct.counters[statusKeyNumMissedMessages] == testNumTopics*numDroppedPerTopic*testNumSubscribers {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters[statusKeyNumMissedMessages])
+			}
+
+			msg = "\t\tmessages delivered must have been reported as received"
+			if ct.counters[statusKeyNumReceivedMessages] == testNumTopics*(testNumRuns*testNumElements-numDroppedPerTopic)*testNumSubscribers {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters[statusKeyNumReceivedMessages])
+			}
+		}
+
+		t.Log("\twhen messages get delivered more than once")
+		{
+			ts := newTestHzTopicStore(&testTopicStoreBehavior{duplicateEveryNth: 4})
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, true, true)
+			l, _, g := assembleTestLoop(ts, rc)
+
+			l.run()
+			ct := finishTestLoop(l, g)
+
+			numDuplicatesPerTopic := testNumRuns * testNumElements / 4
+			msg := "\t\teach subscriber must have reported each message delivered twice as duplicate"
+			if ct.counters[statusKeyNumDuplicateMessages] == testNumTopics*numDuplicatesPerTopic*testNumSubscribers {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters[statusKeyNumDuplicateMessages])
+			}
+
+			msg = "\t\tno message must have been reported as missed"
+			if ct.counters[statusKeyNumMissedMessages] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters[statusKeyNumMissedMessages])
+			}
+		}
+
+		t.Log("\twhen adding message listeners fails")
+		{
+			ts := newTestHzTopicStore(&testTopicStoreBehavior{returnErrorUponAddListener: true})
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, true, true)
+			l, _, g := assembleTestLoop(ts, rc)
+
+			l.run()
+			ct := finishTestLoop(l, g)
+
+			msg := "\t\tfailed subscriptions must have been reported"
+			if ct.counters[statusKeyNumFailedSubscriptions] == testNumTopics*testNumSubscribers {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters[statusKeyNumFailedSubscriptions])
+			}
+
+			msg = "\t\tpublishing must have happened nonetheless, with no message reported as received or missed"
+			if ct.counters[statusKeyNumPublishedMessages] == testNumTopics*testNumRuns*testNumElements &&
+				ct.counters[statusKeyNumReceivedMessages] == 0 && ct.counters[statusKeyNumMissedMessages] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters)
+			}
+		}
+
+		t.Log("\twhen subscribing is disabled")
+		{
+			ts := newTestHzTopicStore(&testTopicStoreBehavior{})
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, true, false)
+			l, _, g := assembleTestLoop(ts, rc)
+
+			l.run()
+			ct := finishTestLoop(l, g)
+
+			msg := "\t\tno message listeners must have been added"
+			if ts.sumInvocations(func(t *testHzTopic) int { return t.addListenerInvocations }) == 0 && ct.counters[statusKeyNumReceivedMessages] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+
+			msg = "\t\ttopics must not have been destroyed"
+			if ts.sumInvocations(func(t *testHzTopic) int { return t.destroyInvocations }) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen publishing is disabled")
+		{
+			ts := newTestHzTopicStore(&testTopicStoreBehavior{})
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, false, true)
+			rc.subscribeConfig.gracePeriod = 10 * time.Millisecond
+			l, _, g := assembleTestLoop(ts, rc)
+
+			start := time.Now()
+			l.run()
+			elapsed := time.Since(start)
+			ct := finishTestLoop(l, g)
+
+			msg := "\t\tsubscribers must have listened for the duration of the grace period"
+			if elapsed >= rc.subscribeConfig.gracePeriod {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, elapsed)
+			}
+
+			msg = "\t\tno message must have been published or reported as missed"
+			if ts.sumInvocations(func(t *testHzTopic) int { return t.publishInvocations }) == 0 &&
+				ct.counters[statusKeyNumPublishedMessages] == 0 && ct.counters[statusKeyNumMissedMessages] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters)
+			}
+
+			msg = "\t\tlisteners must have been removed, but topics must not have been destroyed"
+			if ts.sumInvocations(func(t *testHzTopic) int { return t.removeListenerInvocations }) == testNumTopics*testNumSubscribers &&
+				ts.sumInvocations(func(t *testHzTopic) int { return t.destroyInvocations }) == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX)
+			}
+		}
+
+		t.Log("\twhen topic goroutines share a topic")
+		{
+			ts := newTestHzTopicStore(&testTopicStoreBehavior{})
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, true, true)
+			rc.appendTopicIndexToTopicName = false
+			l, _, g := assembleTestLoop(ts, rc)
+
+			l.run()
+			ct := finishTestLoop(l, g)
+
+			msg := "\t\tmessages of other topic goroutines must not be reported as missed"
+			if len(ts.topics) == 1 && ct.counters[statusKeyNumMissedMessages] == 0 && ct.counters[statusKeyNumDuplicateMessages] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters)
+			}
+		}
+
+		t.Log("\twhen some publish operations fail without having delivered their messages")
+		{
+			ts := newTestHzTopicStore(&testTopicStoreBehavior{failEveryNth: 4})
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, true, true)
+			l, _, g := assembleTestLoop(ts, rc)
+
+			l.run()
+			ct := finishTestLoop(l, g)
+
+			numFailedPerTopic := testNumRuns * testNumElements / 4
+			msg := "\t\tfailed publishes must have been reported"
+			if ct.counters[statusKeyNumFailedPublishes] == testNumTopics*numFailedPerTopic {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters[statusKeyNumFailedPublishes])
+			}
+
+			msg = "\t\tsequence numbers of failed publishes must not be reported as missed"
+			if ct.counters[statusKeyNumMissedMessages] == 0 && ct.counters[statusKeyNumDuplicateMessages] == 0 && ct.counters[statusKeyNumToleratedMessages] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters)
+			}
+		}
+
+		t.Log("\twhen some publish operations fail after having delivered their messages")
+		{
+			for _, mode := range publishModes {
+				ts := newTestHzTopicStore(&testTopicStoreBehavior{failAfterDeliveryEveryNth: 2})
+				rc := assembleTestLoopRunnerConfig(mode, true, true)
+				l, _, g := assembleTestLoop(ts, rc)
+
+				l.run()
+				ct := finishTestLoop(l, g)
+
+				msg := "\t\tmessages delivered despite failed publish must have been reported as tolerated rather than duplicate"
+				if ct.counters[statusKeyNumFailedPublishes] > 0 && ct.counters[statusKeyNumToleratedMessages] == ct.counters[statusKeyNumFailedPublishes]*testNumSubscribers &&
+					ct.counters[statusKeyNumDuplicateMessages] == 0 && ct.counters[statusKeyNumMissedMessages] == 0 {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, ct.counters)
+				}
+			}
+		}
+
+		t.Log("\twhen ringbuffers are used and all messages get delivered")
+		{
+			for _, mode := range publishModes {
+				rs := newTestHzRingbufferStore(&testRingbufferBehavior{})
+				rc := assembleTestLoopRunnerConfig(mode, true, true)
+				rc.useRingbuffer = true
+				ts := newTestHzTopicStore(&testTopicStoreBehavior{})
+				l, _, g := assembleTestLoop(ts, rc)
+				l.tle.hzRingbufferStore = rs
+
+				l.run()
+				ct := finishTestLoop(l, g)
+
+				numExpectedPublished := testNumTopics * testNumRuns * testNumElements
+				msg := "\t\teach subscriber must have read each message of its own ringbuffer"
+				if ct.counters[statusKeyNumPublishedMessages] == numExpectedPublished && ct.counters[statusKeyNumReceivedMessages] == numExpectedPublished*testNumSubscribers {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, ct.counters)
+				}
+
+				msg = "\t\tno message must have been reported as missed, overrun, duplicate, or unexpected"
+				if ct.counters[statusKeyNumMissedMessages] == 0 && ct.counters[statusKeyNumOverrunMessages] == 0 &&
+					ct.counters[statusKeyNumDuplicateMessages] == 0 && ct.counters[statusKeyNumUnexpectedMessages] == 0 {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, ct.counters)
+				}
+
+				msg = "\t\tringbuffer operation corresponding to configured mode must have been used instead of topics"
+				numAddInvocations := rs.sumInvocations(func(rb *testHzRingbuffer) int { return rb.addInvocations })
+				numAddAllInvocations := rs.sumInvocations(func(rb *testHzRingbuffer) int { return rb.addAllInvocations })
+				if len(ts.topics) == 0 && ((mode == publishSingleMode && numAddInvocations == numExpectedPublished && numAddAllInvocations == 0) ||
+					(mode == publishAllMode && numAddInvocations == 0 && numAddAllInvocations > 0)) {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode, numAddInvocations, numAddAllInvocations)
+				}
+
+				msg = "\t\tall ringbuffers must have been destroyed"
+				if len(rs.ringbuffers) == testNumTopics && rs.sumInvocations(func(rb *testHzRingbuffer) int { return rb.destroyInvocations }) == testNumTopics {
+					t.Log(msg, checkMark, mode)
+				} else {
+					t.Fatal(msg, ballotX, mode)
+				}
+			}
+		}
+
+		t.Log("\twhen ringbuffers are used and adding items to ringbuffer fails")
+		{
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, true, true)
+			rc.useRingbuffer = true
+			rc.subscribeConfig.gracePeriod = 10 * time.Millisecond
+			l, _, g := assembleTestLoop(newTestHzTopicStore(&testTopicStoreBehavior{}), rc)
+			l.tle.hzRingbufferStore = newTestHzRingbufferStore(&testRingbufferBehavior{returnErrorUponAdd: true})
+
+			l.run()
+			ct := finishTestLoop(l, g)
+
+			msg := "\t\tall messages must have been reported as failed publishes, but not as missed"
+			if ct.counters[statusKeyNumFailedPublishes] == testNumTopics*testNumRuns*testNumElements && ct.counters[statusKeyNumMissedMessages] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters)
+			}
+		}
+
+		t.Log("\twhen ringbuffers are used and tail sequence cannot be determined")
+		{
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, true, true)
+			rc.useRingbuffer = true
+			l, _, g := assembleTestLoop(newTestHzTopicStore(&testTopicStoreBehavior{}), rc)
+			l.tle.hzRingbufferStore = newTestHzRingbufferStore(&testRingbufferBehavior{returnErrorUponTailSequence: true})
+
+			l.run()
+			ct := finishTestLoop(l, g)
+
+			msg := "\t\tfailed subscriptions must have been reported"
+			if ct.counters[statusKeyNumFailedSubscriptions] == testNumTopics*testNumSubscribers && ct.counters[statusKeyNumReceivedMessages] == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters)
+			}
+		}
+	}
+
+}
+
+func TestRead(t *testing.T) {
+
+	t.Log("given a subscriber reading a ringbuffer")
+	{
+		t.Log("\twhen items have been overwritten before subscriber could read them")
+		{
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, true, true)
+			l, _, g := assembleTestLoop(newTestHzTopicStore(&testTopicStoreBehavior{}), rc)
+			rb := &testHzRingbuffer{behavior: &testRingbufferBehavior{capacity: 5}}
+			p := newPublisher("awesome-publisher")
+			for i := 0; i < 8; i++ {
+				m := p.next("awesome-element", time.Now())
+				_, _ = rb.Add(context.TODO(), m, hazelcast.OverflowPolicyOverwrite)
+				p.published(m)
+			}
+			s := newSubscriber(p)
+			stop := make(chan struct{})
+			close(stop)
+
+			l.read(rb, "awesome-topic", s, 0, stop)
+			ct := finishTestLoop(l, g)
+
+			msg := "\t\toverwritten items must have been reported as overrun"
+			if ct.counters[statusKeyNumOverrunMessages] == 3 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters[statusKeyNumOverrunMessages])
+			}
+
+			msg = "\t\tremaining items must have been read without reporting overwritten ones as missed, too"
+			if ct.counters[statusKeyNumReceivedMessages] == 5 && ct.counters[statusKeyNumMissedMessages] == 0 && s.lastSeq(p.id) == 8 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters, s.lastSeq(p.id))
+			}
+		}
+	}
+
+}
+
+func TestOnMessage(t *testing.T) {
+
+	t.Log("given a message received by a subscriber")
+	{
+		t.Log("\twhen message was not published by a topic runner")
+		{
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, true, true)
+			l, _, g := assembleTestLoop(newTestHzTopicStore(&testTopicStoreBehavior{}), rc)
+			s := newSubscriber(newPublisher("awesome-publisher"))
+
+			l.onMessage(s, "awesome-topic", &hazelcast.MessagePublished{Value: "some string"})
+			ct := finishTestLoop(l, g)
+
+			msg := "\t\tmessage must be reported as received and unexpected"
+			if ct.counters[statusKeyNumReceivedMessages] == 1 && ct.counters[statusKeyNumUnexpectedMessages] == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, ct.counters)
+			}
+		}
+
+	}
+
+}
+
+func TestSubscriberRecord(t *testing.T) {
+
+	t.Log("given a subscriber keeping track of received messages")
+	{
+		ownPublisherID := "own-publisher"
+		t.Log("\twhen messages of own publisher arrive in sequence")
+		{
+			s := newSubscriber(newPublisher(ownPublisherID))
+
+			msg := "\t\tno message must be reported as missed or duplicate"
+			for i := uint64(1); i <= 5; i++ {
+				if missed, duplicate := s.record(topicMessage{PublisherID: ownPublisherID, Seq: i}); missed != 0 || duplicate {
+					t.Fatal(msg, ballotX, i)
+				}
+			}
+			t.Log(msg, checkMark)
+
+			msg = "\t\tlast sequence number must be tracked"
+			if s.lastSeq(ownPublisherID) == 5 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.lastSeq(ownPublisherID))
+			}
+		}
+
+		t.Log("\twhen first message of own publisher does not carry first sequence number")
+		{
+			s := newSubscriber(newPublisher(ownPublisherID))
+
+			missed, duplicate := s.record(topicMessage{PublisherID: ownPublisherID, Seq: 3})
+
+			msg := "\t\tmessages before must be reported as missed"
+			if missed == 2 && !duplicate {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, missed, duplicate)
+			}
+		}
+
+		t.Log("\twhen first message of other publisher does not carry first sequence number")
+		{
+			s := newSubscriber(newPublisher(ownPublisherID))
+
+			missed, duplicate := s.record(topicMessage{PublisherID: "other-publisher", Seq: 42})
+
+			msg := "\t\tmessage must serve as baseline rather than messages before being reported as missed"
+			if missed == 0 && !duplicate && s.lastSeq("other-publisher") == 42 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, missed, duplicate)
+			}
+
+			missed, duplicate = s.record(topicMessage{PublisherID: "other-publisher", Seq: 45})
+
+			msg = "\t\tsubsequent gaps must be reported as missed"
+			if missed == 2 && !duplicate {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, missed, duplicate)
+			}
+		}
+
+		t.Log("\twhen message with sequence number seen before arrives")
+		{
+			s := newSubscriber(newPublisher(ownPublisherID))
+			s.record(topicMessage{PublisherID: ownPublisherID, Seq: 1})
+			s.record(topicMessage{PublisherID: ownPublisherID, Seq: 2})
+
+			missed, duplicate := s.record(topicMessage{PublisherID: ownPublisherID, Seq: 2})
+
+			msg := "\t\tmessage must be reported as duplicate"
+			if missed == 0 && duplicate && s.lastSeq(ownPublisherID) == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, missed, duplicate)
+			}
+		}
+
+		t.Log("\twhen gap contains messages whose publish was reported as failed")
+		{
+			s := newSubscriber(newPublisher(ownPublisherID))
+			s.record(topicMessage{PublisherID: ownPublisherID, Seq: 1})
+
+			missed, duplicate := s.record(topicMessage{PublisherID: ownPublisherID, Seq: 5, NumFailedBefore: 2})
+
+			msg := "\t\tonly skipped messages whose publish was not reported as failed must be reported as missed"
+			if missed == 1 && !duplicate {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, missed, duplicate)
+			}
+
+			msg = "\t\tskipped messages whose publish was reported as failed must be tracked"
+			if s.numSkippedFailedMessages() == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.numSkippedFailedMessages())
+			}
+		}
+
+		t.Log("\twhen subscriber has been resynced")
+		{
+			p := newPublisher(ownPublisherID)
+			for i := 1; i <= 12; i++ {
+				m := p.next("awesome-element", time.Now())
+				if i == 5 {
+					p.failed(m)
+				} else {
+					p.published(m)
+				}
+			}
+			s := newSubscriber(p)
+			s.record(topicMessage{PublisherID: ownPublisherID, Seq: 1})
+			s.record(topicMessage{PublisherID: "other-publisher", Seq: 1})
+
+			s.resync()
+
+			msg := "\t\tnext message of each publisher must serve as baseline"
+			for _, publisherID := range []string{ownPublisherID, "other-publisher"} {
+				if missed, duplicate := s.record(topicMessage{PublisherID: publisherID, Seq: 10}); missed != 0 || duplicate || s.lastSeq(publisherID) != 10 {
+					t.Fatal(msg, ballotX, publisherID, missed, duplicate)
+				}
+			}
+			t.Log(msg, checkMark)
+
+			msg = "\t\tmessages of own publisher skipped whose publish was reported as failed must be tracked"
+			if s.numSkippedFailedMessages() == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, s.numSkippedFailedMessages())
+			}
+
+			msg = "\t\tsubsequent gaps must be reported as missed again"
+			if missed, _ := s.record(topicMessage{PublisherID: ownPublisherID, Seq: 12}); missed == 1 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, missed)
+			}
+		}
+	}
+
+}
+
+func TestPublisherNext(t *testing.T) {
+
+	t.Log("given a publisher")
+	{
+		t.Log("\twhen publishes fail")
+		{
+			p := newPublisher("awesome-publisher")
+			p.failed(p.next("awesome-element", time.Now()))
+			for _, m := range []topicMessage{p.next("awesome-element", time.Now()), p.next("awesome-element", time.Now())} {
+				p.failed(m)
+			}
+			m := p.next("awesome-element", time.Now())
+
+			msg := "\t\tnext message must carry sequence number of its own and number of failed messages directly preceding it"
+			if m.Seq == 4 && m.NumFailedBefore == 3 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, m)
+			}
+
+			p.published(m)
+			m = p.next("awesome-element", time.Now())
+
+			msg = "\t\tmessage following successful publish must not carry failed messages"
+			if m.Seq == 5 && m.NumFailedBefore == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, m)
+			}
+		}
+	}
+
+}
+
+func TestPublisherNumMissedAfter(t *testing.T) {
+
+	t.Log("given a publisher some of whose publishes were reported as failed")
+	{
+		p := newPublisher("awesome-publisher")
+		for i := 1; i <= 6; i++ {
+			m := p.next("awesome-element", time.Now())
+			if i%3 == 0 {
+				p.failed(m)
+			} else {
+				p.published(m)
+			}
+		}
+
+		t.Log("\twhen subscriber has not received the last messages")
+		{
+			msg := "\t\tonly messages successfully published after last received one must be counted as missed"
+			if n := p.numMissedAfter(2); n == 2 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, n)
+			}
+		}
+
+		t.Log("\twhen subscriber has received the last successfully published message")
+		{
+			msg := "\t\tno message must be counted as missed"
+			if n := p.numMissedAfter(5); n == 0 {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, n)
+			}
+		}
+	}
+
+}
+
+func TestAssembleTopicName(t *testing.T) {
+
+	t.Log("given a topic test loop and a topic index")
+	{
+		t.Log("\twhen prefix and topic index are configured to be part of the topic name")
+		{
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, true, true)
+			l := &testLoop[string]{tle: &testLoopExecution[string]{runnerConfig: rc}}
+
+			topicName := l.assembleTopicName(7)
+
+			msg := "\t\ttopic name must contain prefix, base name, and index"
+			if topicName == topicPrefix+topicBaseName+"-7" {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, topicName)
+			}
+		}
+
+		t.Log("\twhen client ID is configured to be part of the topic name")
+		{
+			rc := assembleTestLoopRunnerConfig(publishSingleMode, true, true)
+			rc.useTopicPrefix = false
+			rc.appendTopicIndexToTopicName = false
+			rc.appendClientIdToTopicName = true
+			l := &testLoop[string]{tle: &testLoopExecution[string]{runnerConfig: rc}}
+
+			topicName := l.assembleTopicName(7)
+
+			msg := "\t\ttopic name must contain base name and client ID"
+			if strings.HasPrefix(topicName, topicBaseName+"-") && !strings.HasSuffix(topicName, "-7") {
+				t.Log(msg, checkMark)
+			} else {
+				t.Fatal(msg, ballotX, topicName)
+			}
+		}
+	}
+
+}
+
+func assembleTestLoop(ts *testHzTopicStore, rc *runnerConfig) (*testLoop[string], *testSleeper, *status.Gatherer) {
+
+	elements := make([]string, testNumElements)
+	for i := 0; i < testNumElements; i++ {
+		elements[i] = "awesome-element"
+	}
+
+	tle := &testLoopExecution[string]{
+		id:                uuid.New(),
+		runnerName:        "topicsTestRunner",
+		source:            "testRunner",
+		hzTopicStore:      ts,
+		hzRingbufferStore: newTestHzRingbufferStore(&testRingbufferBehavior{}),
+		runnerConfig:      rc,
+		elements:          elements,
+		ctx:               context.TODO(),
+	}
+
+	g := status.NewGatherer()
+	go g.Listen()
+
+	s := &testSleeper{}
+	l := &testLoop[string]{}
+	l.init(tle, s, g)
+
+	return l, s, g
+
+}
+
+func finishTestLoop(l *testLoop[string], g *status.Gatherer) *topicTestLoopCountersTracker {
+
+	g.StopListen()
+	waitForStatusGatheringDone(g)
+
+	return l.ct.(*topicTestLoopCountersTracker)
+
+}
+
+func assembleTestLoopRunnerConfig(mode publishMode, publishEnabled, subscribeEnabled bool) *runnerConfig {
+
+	enabledSleep := &sleepConfig{enabled: true}
+
+	return &runnerConfig{
+		enabled:                     true,
+		numTopics:                   testNumTopics,
+		topicBaseName:               topicBaseName,
+		appendTopicIndexToTopicName: true,
+		appendClientIdToTopicName:   false,
+		useTopicPrefix:              true,
+		topicPrefix:                 topicPrefix,
+		publishConfig: &publishConfig{
+			enabled:                   publishEnabled,
+			mode:                      mode,
+			numRuns:                   testNumRuns,
+			batchSize:                 testBatchSize,
+			initialDelay:              &sleepConfig{},
+			sleepBetweenActionBatches: enabledSleep,
+			sleepBetweenRuns:          enabledSleep,
+		},
+		subscribeConfig: &subscribeConfig{
+			enabled:        subscribeEnabled,
+			numSubscribers: testNumSubscribers,
+			gracePeriod:    time.Second,
+		},
+	}
+
+}
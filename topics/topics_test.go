@@ -0,0 +1,473 @@
+package topics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hazelcast/hazelcast-go-client"
+	"github.com/hazelcast/hazelcast-go-client/hzerrors"
+	"github.com/hazelcast/hazelcast-go-client/types"
+	"hazeltest/hazelcastwrapper"
+	"sync"
+)
+
+type (
+	testConfigPropertyAssigner struct {
+		returnError bool
+		testConfig  map[string]any
+	}
+	testHzTopicStore struct {
+		topics       map[string]*testHzTopic
+		behavior     *testTopicStoreBehavior
+		observations *testTopicStoreObservations
+		l            sync.Mutex
+	}
+	testHzTopic struct {
+		listeners                 map[types.UUID]hazelcast.TopicMessageHandler
+		numPublished              int
+		numPublishAttempts        int
+		publishInvocations        int
+		publishAllInvocations     int
+		addListenerInvocations    int
+		removeListenerInvocations int
+		destroyInvocations        int
+		behavior                  *testTopicStoreBehavior
+		l                         sync.Mutex
+	}
+	testTopicStoreBehavior struct {
+		returnErrorUponGetTopic, returnErrorUponPublish, returnErrorUponAddListener bool
+		// Messages whose (1-based) number is a multiple of the given value are not delivered to listeners (drop)
+		// or delivered twice (duplicate), respectively. Zero disables the behavior.
+		dropEveryNth, duplicateEveryNth int
+		// Publish operations whose (1-based) number is a multiple of the given value return an error, either without
+		// having delivered their messages (fail) or after having delivered them (failAfterDelivery). Zero disables
+		// the behavior.
+		failEveryNth, failAfterDeliveryEveryNth int
+	}
+	testHzRingbufferStore struct {
+		ringbuffers  map[string]*testHzRingbuffer
+		behavior     *testRingbufferBehavior
+		observations *testTopicStoreObservations
+		l            sync.Mutex
+	}
+	// testHzRingbuffer retains its items in a slice whose first element carries the head sequence. Once the
+	// configured capacity is exceeded, adding an item drops the oldest one.
+	testHzRingbuffer struct {
+		items              []any
+		head               int64
+		addInvocations     int
+		addAllInvocations  int
+		destroyInvocations int
+		behavior           *testRingbufferBehavior
+		l                  sync.Mutex
+	}
+	testRingbufferBehavior struct {
+		returnErrorUponAdd, returnErrorUponTailSequence bool
+		// Zero means unbounded
+		capacity int
+	}
+	testTopicStoreObservations struct {
+		numInitInvocations int
+	}
+	testSleeper struct {
+		sleepKinds []string
+		l          sync.Mutex
+	}
+	testHzClientHandler struct {
+		getClientInvocations, initClientInvocations, shutdownInvocations int
+		hzClusterName                                                    string
+		hzClusterMembers                                                 []string
+	}
+)
+
+const (
+	checkMark     = "\u2713"
+	ballotX       = "\u2717"
+	runnerKeyPath = "testTopicRunner"
+	topicPrefix   = "t_"
+	topicBaseName = "test"
+)
+
+var (
+	hzCluster                = "awesome-hz-cluster"
+	hzMembers                = []string{"awesome-hz-cluster-svc.cluster.local"}
+	expectedStatesForFullRun = []runnerState{start, populateConfigComplete, checkEnabledComplete, raiseReadyComplete, testLoopStart, testLoopComplete}
+)
+
+func newTestHzTopicStore(behavior *testTopicStoreBehavior) *testHzTopicStore {
+
+	return &testHzTopicStore{
+		topics:       make(map[string]*testHzTopic),
+		behavior:     behavior,
+		observations: &testTopicStoreObservations{},
+	}
+
+}
+
+func newTestHzRingbufferStore(behavior *testRingbufferBehavior) *testHzRingbufferStore {
+
+	return &testHzRingbufferStore{
+		ringbuffers:  make(map[string]*testHzRingbuffer),
+		behavior:     behavior,
+		observations: &testTopicStoreObservations{},
+	}
+
+}
+
+func (s *testSleeper) sleep(sc *sleepConfig, _ evaluateTimeToSleep, kind, _, _ string) {
+
+	if sc.enabled {
+		s.l.Lock()
+		s.sleepKinds = append(s.sleepKinds, kind)
+		s.l.Unlock()
+	}
+
+}
+
+func (s *testSleeper) numSleeps(kind string) int {
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	n := 0
+	for _, k := range s.sleepKinds {
+		if k == kind {
+			n++
+		}
+	}
+
+	return n
+
+}
+
+func (d *testHzTopicStore) GetTopic(_ context.Context, name string) (hazelcastwrapper.Topic, error) {
+
+	if d.behavior.returnErrorUponGetTopic {
+		return nil, errors.New("it is but a scratch")
+	}
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	if t, ok := d.topics[name]; ok {
+		return t, nil
+	}
+
+	t := &testHzTopic{listeners: make(map[types.UUID]hazelcast.TopicMessageHandler), behavior: d.behavior}
+	d.topics[name] = t
+
+	return t, nil
+
+}
+
+func (d *testHzTopicStore) sumInvocations(f func(t *testHzTopic) int) int {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	sum := 0
+	for _, t := range d.topics {
+		t.l.Lock()
+		sum += f(t)
+		t.l.Unlock()
+	}
+
+	return sum
+
+}
+
+func (d *testHzTopic) Publish(_ context.Context, message any) error {
+
+	d.l.Lock()
+	d.publishInvocations++
+	d.l.Unlock()
+
+	return d.attemptPublish(message)
+
+}
+
+func (d *testHzTopic) PublishAll(_ context.Context, messages ...any) error {
+
+	d.l.Lock()
+	d.publishAllInvocations++
+	d.l.Unlock()
+
+	return d.attemptPublish(messages...)
+
+}
+
+func (d *testHzTopic) attemptPublish(messages ...any) error {
+
+	d.l.Lock()
+	d.numPublishAttempts++
+	n := d.numPublishAttempts
+	d.l.Unlock()
+
+	if d.behavior.returnErrorUponPublish || (d.behavior.failEveryNth > 0 && n%d.behavior.failEveryNth == 0) {
+		return errors.New("a foul wind has carried this message away")
+	}
+
+	for _, m := range messages {
+		d.deliver(m)
+	}
+
+	if d.behavior.failAfterDeliveryEveryNth > 0 && n%d.behavior.failAfterDeliveryEveryNth == 0 {
+		return errors.New("the raven has flown, but never returned")
+	}
+
+	return nil
+
+}
+
+// deliver hands the given message to all listeners synchronously, so once a publish operation has returned, all
+// listeners have received the message it published (unless configured to drop it).
+func (d *testHzTopic) deliver(message any) {
+
+	var handlers []hazelcast.TopicMessageHandler
+	d.l.Lock()
+	{
+		d.numPublished++
+		if d.behavior.dropEveryNth > 0 && d.numPublished%d.behavior.dropEveryNth == 0 {
+			d.l.Unlock()
+			return
+		}
+		numDeliveries := 1
+		if d.behavior.duplicateEveryNth > 0 && d.numPublished%d.behavior.duplicateEveryNth == 0 {
+			numDeliveries = 2
+		}
+		for _, h := range d.listeners {
+			for i := 0; i < numDeliveries; i++ {
+				handlers = append(handlers, h)
+			}
+		}
+	}
+	d.l.Unlock()
+
+	for _, h := range handlers {
+		h(&hazelcast.MessagePublished{Value: message})
+	}
+
+}
+
+func (d *testHzTopic) AddMessageListener(_ context.Context, handler hazelcast.TopicMessageHandler) (types.UUID, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.addListenerInvocations++
+
+	if d.behavior.returnErrorUponAddListener {
+		return types.UUID{}, errors.New("nobody is listening")
+	}
+
+	id := types.NewUUID()
+	d.listeners[id] = handler
+
+	return id, nil
+
+}
+
+func (d *testHzTopic) RemoveListener(_ context.Context, subscriptionID types.UUID) error {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.removeListenerInvocations++
+	delete(d.listeners, subscriptionID)
+
+	return nil
+
+}
+
+func (d *testHzTopic) Destroy(_ context.Context) error {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.destroyInvocations++
+
+	return nil
+
+}
+
+func (d *testHzRingbufferStore) GetRingbuffer(_ context.Context, name string) (hazelcastwrapper.Ringbuffer, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	if rb, ok := d.ringbuffers[name]; ok {
+		return rb, nil
+	}
+
+	rb := &testHzRingbuffer{behavior: d.behavior}
+	d.ringbuffers[name] = rb
+
+	return rb, nil
+
+}
+
+func (d *testHzRingbufferStore) sumInvocations(f func(rb *testHzRingbuffer) int) int {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	sum := 0
+	for _, rb := range d.ringbuffers {
+		rb.l.Lock()
+		sum += f(rb)
+		rb.l.Unlock()
+	}
+
+	return sum
+
+}
+
+func (d *testHzRingbuffer) Add(_ context.Context, item any, _ hazelcast.OverflowPolicy) (int64, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.addInvocations++
+
+	if d.behavior.returnErrorUponAdd {
+		return -1, errors.New("the ring is full of holes")
+	}
+
+	return d.append(item), nil
+
+}
+
+func (d *testHzRingbuffer) AddAll(_ context.Context, _ hazelcast.OverflowPolicy, items ...any) (int64, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.addAllInvocations++
+
+	if d.behavior.returnErrorUponAdd {
+		return -1, errors.New("the ring is full of holes")
+	}
+
+	var seq int64
+	for _, item := range items {
+		seq = d.append(item)
+	}
+
+	return seq, nil
+
+}
+
+func (d *testHzRingbuffer) append(item any) int64 {
+
+	d.items = append(d.items, item)
+	if d.behavior.capacity > 0 && len(d.items) > d.behavior.capacity {
+		d.items = d.items[1:]
+		d.head++
+	}
+
+	return d.head + int64(len(d.items)) - 1
+
+}
+
+func (d *testHzRingbuffer) ReadOne(_ context.Context, sequence int64) (any, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	if sequence < d.head {
+		return nil, hzerrors.ErrStaleSequence
+	}
+	if sequence >= d.head+int64(len(d.items)) {
+		return nil, fmt.Errorf("sequence %d is beyond tail", sequence)
+	}
+
+	return d.items[sequence-d.head], nil
+
+}
+
+func (d *testHzRingbuffer) HeadSequence(_ context.Context) (int64, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	return d.head, nil
+
+}
+
+func (d *testHzRingbuffer) TailSequence(_ context.Context) (int64, error) {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	if d.behavior.returnErrorUponTailSequence {
+		return -1, errors.New("the tail has been lost")
+	}
+
+	return d.head + int64(len(d.items)) - 1, nil
+
+}
+
+func (d *testHzRingbuffer) Destroy(_ context.Context) error {
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.destroyInvocations++
+
+	return nil
+
+}
+
+func (a testConfigPropertyAssigner) Assign(keyPath string, eval func(string, any) error, assign func(any)) error {
+
+	if a.returnError {
+		return errors.New("lo and behold, here is a deliberately thrown error")
+	}
+
+	if value, ok := a.testConfig[keyPath]; ok {
+		if err := eval(keyPath, value); err != nil {
+			return err
+		}
+		assign(value)
+	}
+
+	return nil
+}
+
+func (d *testHzClientHandler) GetClusterName() string {
+	return d.hzClusterName
+}
+
+func (d *testHzClientHandler) GetClusterMembers() []string {
+	return d.hzClusterMembers
+}
+
+func (d *testHzClientHandler) GetClient() *hazelcast.Client {
+	d.getClientInvocations++
+	return nil
+}
+
+func (d *testHzClientHandler) InitHazelcastClient(_ context.Context, _ string, _ string, _ []string) {
+	d.initClientInvocations++
+}
+
+func (d *testHzClientHandler) Shutdown(_ context.Context) error {
+	d.shutdownInvocations++
+	return nil
+}
+
+func checkRunnerStateTransitions(expected []runnerState, actual []runnerState) (string, bool) {
+
+	if len(expected) != len(actual) {
+		return fmt.Sprintf("expected %d state transition(-s), got %d", len(expected), len(actual)), false
+	}
+
+	for i, expectedValue := range expected {
+		if actual[i] != expectedValue {
+			return fmt.Sprintf("expected '%s' in index '%d', got '%s'", expectedValue, i, actual[i]), false
+		}
+	}
+
+	return "", true
+
+}
@@ -0,0 +1,101 @@
+package topics
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"hazeltest/api"
+	"hazeltest/client"
+	"hazeltest/hazelcastwrapper"
+	"hazeltest/loadsupport"
+	"hazeltest/status"
+)
+
+type (
+	tweetRunner struct {
+		assigner          client.ConfigPropertyAssigner
+		stateList         []runnerState
+		name              string
+		source            string
+		hzClientHandler   hazelcastwrapper.HzClientHandler
+		hzTopicStore      hazelcastwrapper.TopicStore
+		hzRingbufferStore hazelcastwrapper.RingbufferStore
+		l                 looper[tweet]
+		gatherer          *status.Gatherer
+	}
+	// tweet is shared with the TweetRunner for queues, both working on the same embedded data set
+	tweet = loadsupport.Tweet
+)
+
+func init() {
+	register(&tweetRunner{
+		assigner:        &client.DefaultConfigPropertyAssigner{},
+		stateList:       []runnerState{},
+		name:            "topicsTweetRunner",
+		source:          "tweetRunner",
+		hzClientHandler: &hazelcastwrapper.DefaultHzClientHandler{},
+		l:               &testLoop[tweet]{},
+	})
+}
+
+func (r *tweetRunner) getSourceName() string {
+	return r.source
+}
+
+func (r *tweetRunner) runTopicTests(hzCluster string, hzMembers []string, gatherer *status.Gatherer, sf *storeFuncs) {
+
+	r.gatherer = gatherer
+	r.appendState(start)
+
+	config, err := populateConfig(r.assigner, "topicTests.tweets", "tweets")
+	if err != nil {
+		lp.LogTopicRunnerEvent(fmt.Sprintf("aborting launch of topic tweet runner: unable to populate config due to error: %s", err.Error()), r.name, log.ErrorLevel)
+		return
+	}
+	r.appendState(populateConfigComplete)
+
+	if !config.enabled {
+		lp.LogTopicRunnerEvent("tweet runner not enabled -- won't run", r.name, log.InfoLevel)
+		return
+	}
+	r.appendState(checkEnabledComplete)
+
+	api.RaiseNotReady()
+
+	tc, err := loadsupport.ParseTweets()
+	if err != nil {
+		lp.LogIoEvent(fmt.Sprintf("unable to parse tweets json file: %v", err), log.FatalLevel)
+	}
+
+	ctx := context.TODO()
+
+	r.hzClientHandler.InitHazelcastClient(ctx, r.name, hzCluster, hzMembers)
+	defer func() {
+		_ = r.hzClientHandler.Shutdown(ctx)
+	}()
+	r.hzTopicStore = sf.topic(r.hzClientHandler)
+	r.hzRingbufferStore = sf.ringbuffer(r.hzClientHandler)
+
+	api.RaiseReady()
+	r.appendState(raiseReadyComplete)
+
+	lp.LogTopicRunnerEvent("initialized hazelcast client", r.name, log.InfoLevel)
+	lp.LogTopicRunnerEvent("started tweets topic loop", r.name, log.InfoLevel)
+
+	lc := &testLoopExecution[tweet]{id: uuid.New(), runnerName: r.name, source: r.source, hzTopicStore: r.hzTopicStore, hzRingbufferStore: r.hzRingbufferStore, runnerConfig: config, elements: tc.Tweets, ctx: ctx}
+	r.l.init(lc, &defaultSleeper{}, r.gatherer)
+
+	r.appendState(testLoopStart)
+	r.l.run()
+	r.appendState(testLoopComplete)
+
+	lp.LogTopicRunnerEvent("finished tweet test loop", r.name, log.InfoLevel)
+
+}
+
+func (r *tweetRunner) appendState(s runnerState) {
+	r.stateList = append(r.stateList, s)
+
+	r.gatherer.Updates <- status.Update{Key: string(statusKeyCurrentState), Value: string(s)}
+}